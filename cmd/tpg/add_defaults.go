@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var flagAddNoDefaults bool
+
+// applyAddDefaults fills the add flags the user left out from the project's
+// add_defaults config and says on stderr what it filled in, then routes the
+// item under an epic by its labels (see routeByLabel). It fails when the
+// project requires a parent and none was given or routed. It reports whether
+// the priority came from the defaults, so a custom type's own default doesn't
+// replace it, and the label the item was routed by, if any.
+func applyAddDefaults(cmd *cobra.Command, database *db.DB, project string) (bool, string, error) {
+	if flagAddNoDefaults {
+		return false, "", nil
+	}
+	config, err := db.LoadConfig()
+	if err != nil {
+		return false, "", nil
+	}
+	defaults, ok := config.AddDefaultsFor(project)
+	if !ok {
+		return false, routeByLabel(database, config), nil
+	}
+
+	var applied []string
+	priorityDefaulted := false
+	if len(defaults.Labels) > 0 && len(flagAddLabels) == 0 {
+		flagAddLabels = append([]string(nil), defaults.Labels...)
+		applied = append(applied, "labels "+strings.Join(defaults.Labels, ", "))
+	}
+	if defaults.Priority != 0 && !cmd.Flags().Changed("priority") {
+		flagPriority = defaults.Priority
+		priorityDefaulted = true
+		applied = append(applied, fmt.Sprintf("priority %d", defaults.Priority))
+	}
+	if defaults.Type != "" && flagType == "" {
+		if err := validateTypeFlag(defaults.Type); err != nil {
+			return false, "", fmt.Errorf("invalid add_defaults type for project %s: %w", project, err)
+		}
+		flagType = defaults.Type
+		applied = append(applied, "type "+defaults.Type)
+	}
+	if len(applied) > 0 {
+		fmt.Fprintf(os.Stderr, "Note: using %s defaults: %s (--no-defaults to skip)\n", project, strings.Join(applied, "; "))
+	}
+
+	routedBy := routeByLabel(database, config)
+	if defaults.RequireParent && flagParent == "" {
+		return false, "", fmt.Errorf("project %s requires a parent epic: use --parent <epic-id> (or --no-defaults)", project)
+	}
+	return priorityDefaulted, routedBy, nil
+}
+
+// routeByLabel sets --parent from the label_routes config when it wasn't
+// given: the first label of the new item with a route names the epic. Routes
+// to epics that are missing or closed are skipped with a note on stderr. It
+// returns the label the item was routed by, or "" when it wasn't.
+func routeByLabel(database *db.DB, config *db.Config) string {
+	if flagParent != "" || len(config.LabelRoutes) == 0 {
+		return ""
+	}
+	for _, label := range flagAddLabels {
+		target, ok := config.LabelRoutes[label]
+		if !ok {
+			continue
+		}
+		epicID, err := resolveItemArg(database, target)
+		if err == nil {
+			var epic *model.Item
+			if epic, err = database.GetItem(epicID); err == nil {
+				switch {
+				case !epic.Type.CanHaveChildren():
+					err = fmt.Errorf("%s is not an epic", epicID)
+				case epic.Status == model.StatusDone || epic.Status == model.StatusCanceled:
+					err = fmt.Errorf("%s is %s", epicID, epic.Status)
+				}
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Note: not routing by label %s to %s: %v (see label_routes in .tpg/config.json)\n", label, target, err)
+			continue
+		}
+		flagParent = epicID
+		fmt.Fprintf(os.Stderr, "Note: routed under %s by label %s (--parent or --no-defaults to skip)\n", epicID, label)
+		return label
+	}
+	return ""
+}
+
+// logLabelRoute notes on a new item that routeByLabel chose its parent.
+func logLabelRoute(database *db.DB, id, label string) error {
+	if label == "" {
+		return nil
+	}
+	return database.AddLog(id, fmt.Sprintf("Routed under %s by label %s (label_routes)", flagParent, label))
+}
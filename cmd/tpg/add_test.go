@@ -70,6 +70,7 @@ func resetAddCmdFlags() {
 	flagWorktreeBase = ""
 	flagWorktreeAllow = false
 	flagProject = ""
+	flagAddNoDefaults = false
 }
 
 func captureStdoutAndStderr(f func()) (string, string) {
@@ -268,8 +269,8 @@ func TestAddCmd_ParentFlag_NonEpicParent(t *testing.T) {
 	if err == nil {
 		t.Error("expected error when setting non-epic as parent")
 	}
-	if err != nil && !strings.Contains(err.Error(), "not an epic") {
-		t.Errorf("expected 'not an epic' error, got: %v", err)
+	if err != nil && !strings.Contains(err.Error(), "(type task) cannot have children") {
+		t.Errorf("expected 'cannot have children' error, got: %v", err)
 	}
 }
 
@@ -771,3 +772,157 @@ func TestListCmd_TypeValidation_AcceptsTask(t *testing.T) {
 		t.Fatalf("expected success for --type task, got: %v", runErr)
 	}
 }
+
+func TestAddCmd_ProjectDefaults(t *testing.T) {
+	database := setupAddCommandTest(t)
+	resetAddCmdFlags()
+	t.Cleanup(resetAddCmdFlags)
+
+	config := &db.Config{AddDefaults: map[string]db.AddDefaults{
+		"*": {Labels: []string{"backend"}, Priority: 1},
+	}}
+	if err := db.SaveConfig(config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	var runErr error
+	stdout, stderr := captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Defaulted task"})
+	})
+	if runErr != nil {
+		t.Fatalf("add failed: %v", runErr)
+	}
+	if !strings.Contains(stderr, "defaults: labels backend; priority 1") {
+		t.Errorf("expected a note about applied defaults, got %q", stderr)
+	}
+	item, err := database.GetItem(strings.TrimSpace(stdout))
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	labels, err := database.GetItemLabels(item.ID)
+	if err != nil {
+		t.Fatalf("GetItemLabels: %v", err)
+	}
+	if item.Priority != 1 || len(labels) != 1 || labels[0].Name != "backend" {
+		t.Errorf("got priority %d, labels %v; want 1, [backend]", item.Priority, labels)
+	}
+
+	// Required parent is enforced unless defaults are skipped
+	config.AddDefaults["*"] = db.AddDefaults{RequireParent: true}
+	if err := db.SaveConfig(config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	resetAddCmdFlags()
+	captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Orphan task"})
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "requires a parent") {
+		t.Errorf("expected require_parent error, got %v", runErr)
+	}
+	flagAddNoDefaults = true
+	captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Orphan task"})
+	})
+	if runErr != nil {
+		t.Errorf("--no-defaults should bypass require_parent, got %v", runErr)
+	}
+}
+
+func TestAddCmd_LabelRoutes(t *testing.T) {
+	database := setupAddCommandTest(t)
+	resetAddCmdFlags()
+	t.Cleanup(resetAddCmdFlags)
+
+	createTestItem(t, database, "ep-docs", "Docs", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ep-old", "Old", withType(model.ItemTypeEpic), withStatus(model.StatusDone))
+	config := &db.Config{
+		LabelRoutes: map[string]string{"docs": "ep-docs", "old": "ep-old"},
+		AddDefaults: map[string]db.AddDefaults{"*": {RequireParent: true}},
+	}
+	if err := db.SaveConfig(config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	flagAddLabels = []string{"old", "docs"}
+	var runErr error
+	stdout, stderr := captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Document the API"})
+	})
+	if runErr != nil {
+		t.Fatalf("add failed: %v", runErr)
+	}
+	if !strings.Contains(stderr, "not routing by label old") || !strings.Contains(stderr, "routed under ep-docs by label docs") {
+		t.Errorf("expected notes about the skipped and used routes, got %q", stderr)
+	}
+	id := strings.TrimSpace(stdout)
+	item, err := database.GetItem(id)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if item.ParentID == nil || *item.ParentID != "ep-docs" {
+		t.Errorf("parent = %v, want ep-docs", item.ParentID)
+	}
+	logs, err := database.GetLogs(id)
+	if err != nil {
+		t.Fatalf("GetLogs: %v", err)
+	}
+	if len(logs) != 1 || !strings.Contains(logs[0].Message, "Routed under ep-docs by label docs") {
+		t.Errorf("logs = %+v, want a routing note", logs)
+	}
+
+	// An explicit parent wins over the route.
+	resetAddCmdFlags()
+	flagAddLabels = []string{"docs"}
+	flagParent = "ep-other"
+	createTestItem(t, database, "ep-other", "Other", withType(model.ItemTypeEpic))
+	stdout, _ = captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Explicit parent"})
+	})
+	if runErr != nil {
+		t.Fatalf("add with --parent failed: %v", runErr)
+	}
+	if item, _ = database.GetItem(strings.TrimSpace(stdout)); item.ParentID == nil || *item.ParentID != "ep-other" {
+		t.Errorf("parent = %v, want ep-other", item.ParentID)
+	}
+}
+
+func TestAddCmd_StrictPolicy(t *testing.T) {
+	setupAddCommandTest(t)
+	resetAddCmdFlags()
+	t.Cleanup(resetAddCmdFlags)
+
+	config := &db.Config{Policy: db.PolicyConfig{RequireParent: true, MinDescriptionWords: 3}}
+	if err := db.SaveConfig(config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	// Without strict, broken rules are warnings
+	var runErr error
+	_, stderr := captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Loose task"})
+	})
+	if runErr != nil {
+		t.Fatalf("expected add to succeed without strict, got %v", runErr)
+	}
+	if !strings.Contains(stderr, "WARNING: policy: tasks must have a parent epic") {
+		t.Errorf("expected policy warning, got %q", stderr)
+	}
+
+	config.Policy.Strict = true
+	if err := db.SaveConfig(config); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	resetAddCmdFlags()
+	flagDescription = "Too short"
+	captureStdoutAndStderr(func() {
+		runErr = addCmd.RunE(addCmd, []string{"Strict task"})
+	})
+	if runErr == nil {
+		t.Fatal("expected strict policy to reject the task")
+	}
+	for _, want := range []string{"parent epic", "at least 3 words (has 2)"} {
+		if !strings.Contains(runErr.Error(), want) {
+			t.Errorf("error %q missing %q", runErr, want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagAgentName        string
+	flagAgentType        string
+	flagAgentDescription string
+	flagHeartbeatQuiet   bool
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Manage registered agent identities",
+	Long: `Register readable names and metadata for agent IDs.
+
+Agents identify themselves through $AGENT_ID, which is usually an opaque
+string. Registering an agent lets show, history, and status display its name
+instead.
+
+Examples:
+  tpg agent register --name "Build bot" --type opencode --description "CI fixer"
+  tpg agent register ses_4f2a9c --name reviewer --type claude
+  tpg agent list
+  tpg agent rm ses_4f2a9c`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return agentListCmd.RunE(cmd, args)
+	},
+}
+
+var agentRegisterCmd = &cobra.Command{
+	Use:   "register [agent-id]",
+	Short: "Register or update an agent's metadata",
+	Long: `Register or update metadata for an agent ID. Without an ID, the current
+$AGENT_ID is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := os.Getenv("AGENT_ID")
+		if len(args) > 0 {
+			id = args[0]
+		}
+		if id == "" {
+			return fmt.Errorf("agent id is required (pass it as an argument or set AGENT_ID)")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		// Keep existing values for flags that were not given
+		agent := &db.Agent{ID: id}
+		existing, err := database.GetAgent(id)
+		if err == nil {
+			agent = existing
+		}
+		if cmd.Flags().Changed("name") {
+			agent.Name = flagAgentName
+		}
+		if cmd.Flags().Changed("type") {
+			agent.Type = flagAgentType
+		}
+		if cmd.Flags().Changed("description") {
+			agent.Description = flagAgentDescription
+		}
+
+		if err := database.RegisterAgent(agent); err != nil {
+			return err
+		}
+		if existing != nil {
+			fmt.Printf("Updated agent %s (%s)\n", agent.ID, agent.DisplayName())
+		} else {
+			fmt.Printf("Registered agent %s (%s)\n", agent.ID, agent.DisplayName())
+		}
+		return nil
+	},
+}
+
+var agentListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List registered agents",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		agents, err := database.ListAgents()
+		if err != nil {
+			return err
+		}
+		if len(agents) == 0 {
+			fmt.Println("No registered agents")
+			return nil
+		}
+		current := os.Getenv("AGENT_ID")
+		fmt.Printf("%-2s %-24s %-20s %-12s %s\n", "", "ID", "NAME", "TYPE", "DESCRIPTION")
+		for _, a := range agents {
+			marker := ""
+			if a.ID == current {
+				marker = "*"
+			}
+			fmt.Printf("%-2s %-24s %-20s %-12s %s\n", marker, a.ID, a.Name, a.Type, a.Description)
+		}
+		return nil
+	},
+}
+
+var agentRmCmd = &cobra.Command{
+	Use:   "rm <agent-id>",
+	Short: "Remove an agent's registered metadata",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if err := database.RemoveAgent(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed agent %s\n", args[0])
+		return nil
+	},
+}
+
+var heartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Record that the current agent is still alive",
+	Long: `Record a liveness heartbeat for $AGENT_ID. Every in-progress task claimed
+by the agent is marked as recently active, so 'tpg status' can tell tasks held
+by an active agent apart from tasks whose agent disappeared.
+
+The OpenCode plugin calls this periodically; agents without the plugin can
+call it themselves. An agent counts as active if it was seen within the last
+10 minutes.
+
+Examples:
+  AGENT_ID=ses_123 tpg heartbeat
+  tpg heartbeat --quiet`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		agentCtx := db.GetAgentContext()
+		if !agentCtx.IsActive() {
+			return fmt.Errorf("AGENT_ID is not set; heartbeats identify the agent through it")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, _ := resolveProject()
+		n, err := database.Heartbeat(agentCtx.ID, project)
+		if err != nil {
+			return err
+		}
+		if !flagHeartbeatQuiet {
+			fmt.Printf("Heartbeat recorded for %s (%d task(s) in progress)\n", agentDisplayName(database.AgentNames(), agentCtx.ID), n)
+		}
+		return nil
+	},
+}
+
+func init() {
+	heartbeatCmd.Flags().BoolVarP(&flagHeartbeatQuiet, "quiet", "q", false, "Print nothing on success")
+	rootCmd.AddCommand(heartbeatCmd)
+
+	agentRegisterCmd.Flags().StringVar(&flagAgentName, "name", "", "Readable name shown instead of the agent ID")
+	agentRegisterCmd.Flags().StringVar(&flagAgentType, "type", "", "Agent type (e.g. opencode, claude)")
+	agentRegisterCmd.Flags().StringVar(&flagAgentDescription, "description", "", "What this agent is used for")
+
+	agentCmd.AddCommand(agentRegisterCmd)
+	agentCmd.AddCommand(agentListCmd)
+	agentCmd.AddCommand(agentRmCmd)
+	rootCmd.AddCommand(agentCmd)
+}
+
+// agentDisplayName returns the registered name for an agent ID, or the ID
+// itself when the agent is not registered.
+func agentDisplayName(names map[string]string, id string) string {
+	if name, ok := names[id]; ok && name != "" {
+		return name
+	}
+	return id
+}
+
+// resolveAgentID maps a registered agent name back to its ID, so filters can
+// be given either way. Unknown values are returned unchanged.
+func resolveAgentID(names map[string]string, nameOrID string) string {
+	if _, ok := names[nameOrID]; ok {
+		return nameOrID
+	}
+	for id, name := range names {
+		if name == nameOrID {
+			return id
+		}
+	}
+	return nameOrID
+}
+
+// agentLivenessMarker describes whether the agent claiming an in-progress
+// item is still around, based on its heartbeats and task updates.
+func agentLivenessMarker(item model.Item, now time.Time) string {
+	lastSeen, ok := format.AgentLastSeen(item)
+	if !ok {
+		return ""
+	}
+	if format.IsAgentActive(item, now) {
+		return "● active"
+	}
+	return "○ silent, last seen " + formatTimeAgo(lastSeen)
+}
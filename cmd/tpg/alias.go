@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage command aliases",
+	Long: `Manage user-defined command aliases.
+
+Aliases are stored in .tpg/config.json under "alias" and are expanded
+before the command is dispatched. Any extra arguments are appended to
+the expansion. For short names of items, see 'tpg item-alias'.
+
+Examples:
+  tpg alias set rd "ready --project myproject -l bug"
+  tpg rd                     # runs: tpg ready --project myproject -l bug
+  tpg rd --json              # runs: tpg ready --project myproject -l bug --json
+  tpg alias list
+  tpg alias rm rd`,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List configured aliases",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := db.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if len(config.Aliases) == 0 {
+			fmt.Println("No aliases")
+			return nil
+		}
+		names := make([]string, 0, len(config.Aliases))
+		for name := range config.Aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s = %s\n", name, config.Aliases[name])
+		}
+		return nil
+	},
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <expansion>",
+	Short: "Create or update an alias",
+	Long: `Create or update an alias.
+
+The expansion may be passed as a single quoted string or as multiple
+arguments. Alias names cannot shadow built-in commands.
+
+Examples:
+  tpg alias set rd "ready --project myproject -l bug"
+  tpg alias set mine -- list --status in_progress   # "--" before flags of the expansion`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := validateAliasName(name); err != nil {
+			return err
+		}
+		expansion := strings.Join(args[1:], " ")
+		if _, err := splitAliasArgs(expansion); err != nil {
+			return err
+		}
+
+		config, err := db.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if config.Aliases == nil {
+			config.Aliases = make(map[string]string)
+		}
+		config.Aliases[name] = expansion
+		if err := db.SaveConfig(config); err != nil {
+			return err
+		}
+		fmt.Printf("Set alias %s = %s\n", name, expansion)
+		return nil
+	},
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := db.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := config.Aliases[args[0]]; !ok {
+			return fmt.Errorf("alias not found: %s", args[0])
+		}
+		delete(config.Aliases, args[0])
+		if err := db.SaveConfig(config); err != nil {
+			return err
+		}
+		fmt.Printf("Removed alias %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasRmCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
+
+// validateAliasName rejects empty names, names that look like flags, and
+// names that would shadow a built-in command or its aliases.
+func validateAliasName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid alias name: %q", name)
+	}
+	if isBuiltinCommand(name) {
+		return fmt.Errorf("alias %q would shadow a built-in command", name)
+	}
+	return nil
+}
+
+func isBuiltinCommand(name string) bool {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	// cobra adds these lazily, so they aren't in Commands() yet
+	return name == "help" || name == "completion"
+}
+
+// expandAliases rewrites args (without the program name) when the command
+// word matches a configured alias. Built-in commands always win. Aliases are
+// expanded once; an alias pointing at another alias is not followed.
+func expandAliases(args []string, aliases map[string]string) ([]string, error) {
+	if len(aliases) == 0 {
+		return args, nil
+	}
+
+	// Locate the command word, skipping global flags and their values.
+	idx := -1
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if arg == "--project" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		idx = i
+		break
+	}
+	if idx < 0 || isBuiltinCommand(args[idx]) {
+		return args, nil
+	}
+
+	expansion, ok := aliases[args[idx]]
+	if !ok {
+		return args, nil
+	}
+	words, err := splitAliasArgs(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("alias %s: %w", args[idx], err)
+	}
+
+	expanded := make([]string, 0, len(args)+len(words))
+	expanded = append(expanded, args[:idx]...)
+	expanded = append(expanded, words...)
+	expanded = append(expanded, args[idx+1:]...)
+	return expanded, nil
+}
+
+// splitAliasArgs splits an alias expansion into words, honoring single and
+// double quotes and backslash escapes.
+func splitAliasArgs(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("alias expansion is empty")
+	}
+	return words, nil
+}
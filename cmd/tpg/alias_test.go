@@ -0,0 +1,164 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestSplitAliasArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"ready -p myproject -l bug", []string{"ready", "-p", "myproject", "-l", "bug"}},
+		{`list --search "auth bug"`, []string{"list", "--search", "auth bug"}},
+		{`log . 'it''s fine'`, []string{"log", ".", "its fine"}},
+		{`add a\ b`, []string{"add", "a b"}},
+		{"  ready   ", []string{"ready"}},
+	}
+	for _, tt := range tests {
+		got, err := splitAliasArgs(tt.in)
+		if err != nil {
+			t.Errorf("splitAliasArgs(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitAliasArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "   ", `ready "unterminated`, `ready \`} {
+		if _, err := splitAliasArgs(bad); err == nil {
+			t.Errorf("splitAliasArgs(%q) expected error", bad)
+		}
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"rd":   "ready -p myproject -l bug",
+		"list": "ready", // shadowing a built-in is ignored
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"simple", []string{"rd"}, []string{"ready", "-p", "myproject", "-l", "bug"}},
+		{"extra args appended", []string{"rd", "--json"}, []string{"ready", "-p", "myproject", "-l", "bug", "--json"}},
+		{"global flags before", []string{"-v", "--project", "x", "rd"}, []string{"-v", "--project", "x", "ready", "-p", "myproject", "-l", "bug"}},
+		{"builtin wins", []string{"list"}, []string{"list"}},
+		{"unknown untouched", []string{"nope"}, []string{"nope"}},
+		{"no command", []string{"--help"}, []string{"--help"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandAliases(tt.args, aliases)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandAliases(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasSetAndRm(t *testing.T) {
+	setupAddCommandTest(t)
+
+	if err := aliasSetCmd.RunE(aliasSetCmd, []string{"rd", "ready -l bug"}); err != nil {
+		t.Fatalf("alias set failed: %v", err)
+	}
+	config, err := db.LoadConfig()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := config.Aliases["rd"]; got != "ready -l bug" {
+		t.Errorf("alias rd = %q, want %q", got, "ready -l bug")
+	}
+
+	if err := aliasSetCmd.RunE(aliasSetCmd, []string{"ready", "list"}); err == nil {
+		t.Error("expected error when shadowing built-in command")
+	}
+
+	if err := aliasRmCmd.RunE(aliasRmCmd, []string{"rd"}); err != nil {
+		t.Fatalf("alias rm failed: %v", err)
+	}
+	config, _ = db.LoadConfig()
+	if _, ok := config.Aliases["rd"]; ok {
+		t.Error("alias rd should have been removed")
+	}
+	if err := aliasRmCmd.RunE(aliasRmCmd, []string{"rd"}); err == nil {
+		t.Error("expected error removing missing alias")
+	}
+}
+
+func TestItemAliasAddResolveRm(t *testing.T) {
+	database := setupAddCommandTest(t)
+	item := createTestItem(t, database, "ts-login", "Fix login")
+
+	if err := itemAliasAddCmd.RunE(itemAliasAddCmd, []string{"login-bug", item.ID}); err != nil {
+		t.Fatalf("item-alias add failed: %v", err)
+	}
+	if id, err := resolveItemArg(database, "login-bug"); err != nil || id != item.ID {
+		t.Errorf("resolveItemArg(login-bug) = %q, %v; want %s", id, err, item.ID)
+	}
+	if id, _ := resolveItemArg(database, "other"); id != "other" {
+		t.Errorf("unknown names should pass through, got %q", id)
+	}
+	if err := itemAliasAddCmd.RunE(itemAliasAddCmd, []string{"Login Bug", item.ID}); err == nil {
+		t.Error("expected an error for an invalid alias")
+	}
+
+	// Command aliases and item aliases are removed separately
+	if err := aliasRmCmd.RunE(aliasRmCmd, []string{"login-bug"}); err == nil {
+		t.Error("alias rm removed an item alias, want an error")
+	}
+	if err := itemAliasRmCmd.RunE(itemAliasRmCmd, []string{"login-bug"}); err != nil {
+		t.Fatalf("item-alias rm failed: %v", err)
+	}
+	if id, _ := resolveItemArg(database, "login-bug"); id != "login-bug" {
+		t.Errorf("removed alias still resolves to %q", id)
+	}
+	if err := itemAliasRmCmd.RunE(itemAliasRmCmd, []string{"login-bug"}); err == nil {
+		t.Error("expected an error removing a missing item alias")
+	}
+}
+
+func TestItemAliasAcceptedByCommands(t *testing.T) {
+	database := setupAddCommandTest(t)
+	item := createTestItem(t, database, "ts-login", "Fix login", withStatus(model.StatusDone))
+	if err := database.SetItemAlias("login-bug", item.ID); err != nil {
+		t.Fatalf("SetItemAlias: %v", err)
+	}
+
+	var errs []error
+	captureStdoutAndStderr(func() {
+		errs = append(errs,
+			reopenCmd.RunE(reopenCmd, []string{"login-bug"}),
+			fieldSetCmd.RunE(fieldSetCmd, []string{"login-bug", "reviewer", "alice"}),
+			labelCmd.RunE(labelCmd, []string{"login-bug", "auth"}),
+		)
+	})
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("command with an alias failed: %v", err)
+		}
+	}
+
+	got, _ := database.GetItem(item.ID)
+	if got.Status != model.StatusOpen {
+		t.Errorf("status = %s, want reopened", got.Status)
+	}
+	if fields, _ := database.GetFields(item.ID); fields["reviewer"] != "alice" {
+		t.Errorf("fields = %v, want reviewer set", fields)
+	}
+	if labels, _ := database.GetItemLabels(item.ID); len(labels) != 1 || labels[0].Name != "auth" {
+		t.Errorf("labels = %v, want auth", labels)
+	}
+}
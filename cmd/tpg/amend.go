@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var amendCmd = &cobra.Command{
+	Use:   "amend",
+	Short: "Edit the item you created last",
+	Long: `Apply edits to the most recent item created in this session, so a mistake
+can be fixed right after 'tpg add' without copying its ID back.
+
+The session is the agent ($AGENT_ID), or $TPG_SESSION when set, or otherwise
+the terminal, as for 'tpg current'. Items created by someone else never
+count, and a deleted item falls back to the one created before it.
+
+The flags work as for 'tpg edit'. With none, the description opens in your
+editor.
+
+Examples:
+  tpg add "Fix login bug"
+  tpg amend --priority 1 --parent ep-abc123
+  tpg amend --desc - <<EOF
+  Tokens expire after 5 minutes instead of an hour.
+  EOF
+  tpg amend --title "Fix login redirect" --add-label bug`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		session := db.CurrentSession()
+		id, err := database.LastCreatedItem(session)
+		_ = database.Close()
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return fmt.Errorf("nothing to amend: no item was created in this session (%s)", session)
+		}
+		fmt.Fprintf(os.Stderr, "Amending %s\n", id)
+		return editCmd.RunE(cmd, []string{id})
+	},
+}
+
+func init() {
+	amendCmd.Flags().StringVar(&flagEditTitle, "title", "", "New title")
+	amendCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Allow a multi-line or overlong title")
+	addPriorityFlag(amendCmd, &flagEditPriority, 0, false)
+	amendCmd.Flags().StringVar(&flagEditType, "type", "", "New item type (task, epic, or a custom type)")
+	amendCmd.Flags().StringVar(&flagEditParent, "parent", "", "New parent epic ID (use \"\" to remove)")
+	amendCmd.Flags().StringArrayVar(&flagEditAddLabels, "add-label", nil, "Label to add (repeatable)")
+	amendCmd.Flags().StringArrayVar(&flagEditRmLabels, "remove-label", nil, "Label to remove (repeatable)")
+	amendCmd.Flags().StringVar(&flagEditDesc, "desc", "", "New description (use '-' for stdin)")
+	amendCmd.Flags().StringVar(&flagEditRankBefore, "rank-before", "", "Order the item just before this sibling")
+	amendCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Preview changes without applying")
+
+	rootCmd.AddCommand(amendCmd)
+}
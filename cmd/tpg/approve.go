@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <id> [note]",
+	Short: "Approve a task pending review, completing it",
+	Long: `Approve a task that 'tpg done' submitted for review, completing it with
+the results it was submitted with. Parent epics auto-complete and dependent
+tasks become ready as with any completion.
+
+Tasks need approval when added with 'tpg add --needs-approval' (or marked
+with 'tpg edit --needs-approval'). 'tpg done' on such a task moves it to
+pending_review instead of done, so a human or a reviewer agent can check
+the work first. The reviewer ($AGENT_ID, or your login name) is recorded in history.
+'tpg review' sends a task back with changes requested instead.
+
+Examples:
+  tpg review queue
+  tpg approve ts-a1b2c3
+  tpg approve ts-a1b2c3 "Checked the migration on a copy of prod"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+
+		openEpics := openAncestorEpics(database, id)
+		if _, err := database.ApproveItem(id, db.CurrentReviewer(), db.GetAgentContext()); err != nil {
+			return err
+		}
+
+		message := "Approved"
+		if note := strings.TrimSpace(strings.Join(args[1:], " ")); note != "" {
+			message += ": " + note
+		}
+		if err := database.AddLog(id, message); err != nil {
+			return err
+		}
+		fmt.Printf("Approved %s\n", id)
+		announceCompletedEpics(database, openEpics)
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(approveCmd)
+}
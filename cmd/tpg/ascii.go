@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
+)
+
+var flagASCII bool
+
+// stopASCIIOutput restores stdout and flushes the ASCII filter; main calls
+// it once the command has finished. It is nil when the filter isn't active.
+var stopASCIIOutput func()
+
+// asciiRequested reports whether ASCII output was asked for with --ascii,
+// TPG_ASCII=1, or output.ascii in the config.
+func asciiRequested() bool {
+	if flagASCII || os.Getenv("TPG_ASCII") == "1" {
+		return true
+	}
+	config, err := db.LoadConfig()
+	return err == nil && config.Output.ASCII
+}
+
+// setupASCIIOutput enables ASCII output for cmd when requested. Commands
+// that hand the terminal to something else (the TUI, 'tpg run') or emit
+// data rather than text (--json, --format jsonl, export) keep stdout untouched.
+func setupASCIIOutput(cmd *cobra.Command) {
+	if !asciiRequested() {
+		return
+	}
+	format.SetASCIIEnabled(true)
+
+	if cmd == tuiCmd || cmd == runCmd || cmd == exportCmd {
+		return
+	}
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Changed {
+		return
+	}
+	if f := cmd.Flags().Lookup("format"); f != nil && (f.Value.String() == "json" || f.Value.String() == formatJSONL) {
+		return
+	}
+	stopASCIIOutput = filterStdout()
+}
+
+// filterStdout routes os.Stdout through format.ASCIIWriter and returns a
+// function that restores it once everything written has been flushed.
+func filterStdout() func() {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+	orig := os.Stdout
+	os.Stdout = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		aw := format.NewASCIIWriter(orig)
+		_, _ = io.Copy(aw, r)
+		_ = aw.Close()
+		_ = r.Close()
+	}()
+
+	return func() {
+		_ = w.Close()
+		<-done
+		os.Stdout = orig
+	}
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagBriefEpic      string
+	flagBriefReady     int
+	flagBriefConcepts  int
+	flagBriefLearnings int
+
+	flagProjectsDescribeClear bool
+)
+
+// briefDescriptionLimit caps the characters of a description quoted in a
+// briefing, to keep the whole briefing within a few thousand tokens.
+const briefDescriptionLimit = 800
+
+var briefCmd = &cobra.Command{
+	Use:   "brief",
+	Short: "Print a compact briefing for an agent starting a session",
+	Long: `Print a short Markdown briefing meant as the first message of an agent
+session: what the project is, which epics are in flight and how far along
+they are, what is ready to work on, the key concepts, and the most recent
+learnings. The lists are capped so the briefing stays within a few thousand
+tokens.
+
+The project description comes from 'tpg projects describe'.
+
+With --epic, the briefing covers that epic instead: its description, its
+sub-epics, its ready tasks, and the learnings and concepts relevant to it.
+
+Examples:
+  tpg brief
+  tpg brief --epic ep-abc123
+  tpg brief --ready 5 --learnings 3`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		var epic *model.Item
+		project := ""
+		if flagBriefEpic != "" {
+			id, err := resolveItemArg(database, flagBriefEpic)
+			if err != nil {
+				return err
+			}
+			if epic, err = database.GetItem(id); err != nil {
+				return err
+			}
+			if !epic.Type.CanHaveChildren() {
+				return fmt.Errorf("%s is not an epic (type: %s)", epic.ID, epic.Type)
+			}
+			project = epic.Project
+		} else if project, err = resolveProject(); err != nil {
+			return err
+		}
+
+		brief, err := buildBrief(database, project, epic, briefLimits{
+			Ready:     flagBriefReady,
+			Concepts:  flagBriefConcepts,
+			Learnings: flagBriefLearnings,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(os.Stdout, brief)
+		return err
+	},
+}
+
+var projectsDescribeCmd = &cobra.Command{
+	Use:   "describe [description]",
+	Short: "Show or set the project description used by 'tpg brief'",
+	Long: `Show the description of the current project, or set it. The description
+opens every 'tpg brief', so a new agent learns what the project is before
+anything else. Use '-' to read it from stdin and --clear to remove it.
+
+Examples:
+  tpg projects describe
+  tpg projects describe "CLI task tracker for AI agents, Go + SQLite"
+  tpg projects describe - < docs/overview.md
+  tpg projects describe --clear`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 && !flagProjectsDescribeClear {
+			description, err := database.ProjectDescription(project)
+			if err != nil {
+				return err
+			}
+			if description == "" {
+				fmt.Printf("No description for %s (set one with 'tpg projects describe \"...\"')\n", project)
+				return nil
+			}
+			fmt.Println(description)
+			return nil
+		}
+		if len(args) > 0 && flagProjectsDescribeClear {
+			return fmt.Errorf("--clear takes no description")
+		}
+
+		description := ""
+		if len(args) > 0 {
+			description = args[0]
+			if description == "-" {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("failed to read from stdin: %w", err)
+				}
+				description = string(data)
+			}
+			description = strings.TrimSpace(description)
+			if description == "" {
+				return fmt.Errorf("description is empty (use --clear to remove it)")
+			}
+		}
+		if err := database.SetProjectDescription(project, description); err != nil {
+			return err
+		}
+		if description == "" {
+			fmt.Printf("Cleared the description of %s\n", project)
+		} else {
+			fmt.Printf("Updated the description of %s\n", project)
+		}
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// briefLimits caps the lists in a briefing.
+type briefLimits struct {
+	Ready     int
+	Concepts  int
+	Learnings int
+}
+
+// buildBrief renders the briefing for a project, or for an epic when epic is
+// set.
+func buildBrief(database *db.DB, project string, epic *model.Item, limits briefLimits) (string, error) {
+	description, err := database.ProjectDescription(project)
+	if err != nil {
+		return "", err
+	}
+
+	var epics, ready, descendants []model.Item
+	var learnings []model.Learning
+	if epic != nil {
+		if descendants, err = database.GetDescendants(epic.ID); err != nil {
+			return "", err
+		}
+		for _, item := range descendants {
+			if item.ParentID != nil && *item.ParentID == epic.ID && item.Type.CanHaveChildren() && !isClosedStatus(item.Status) {
+				epics = append(epics, item)
+			}
+		}
+		if ready, err = database.ReadyItemsForEpic(epic.ID, db.SortOrder{}); err != nil {
+			return "", err
+		}
+		if learnings, err = packLearnings(database, epic, descendants); err != nil {
+			return "", err
+		}
+		sort.SliceStable(learnings, func(i, j int) bool { return learnings[i].CreatedAt.After(learnings[j].CreatedAt) })
+	} else {
+		all, err := database.GetEpics(project)
+		if err != nil {
+			return "", err
+		}
+		for _, item := range all {
+			if item.ParentID == nil && !isClosedStatus(item.Status) {
+				epics = append(epics, item)
+			}
+		}
+		if ready, err = database.ReadyItems(project); err != nil {
+			return "", err
+		}
+		if learnings, err = database.GetAllLearnings(project, false); err != nil {
+			return "", err
+		}
+	}
+	if err := renderTemplatesForItems(ready); err != nil {
+		return "", err
+	}
+
+	concepts, err := database.ListConcepts(project, false)
+	if err != nil {
+		return "", err
+	}
+	if epic != nil {
+		// Only the concepts the epic's learnings are tagged with.
+		used := make(map[string]bool)
+		for _, l := range learnings {
+			for _, c := range l.Concepts {
+				used[c] = true
+			}
+		}
+		var relevant []model.Concept
+		for _, c := range concepts {
+			if used[c.Name] {
+				relevant = append(relevant, c)
+			}
+		}
+		concepts = relevant
+	}
+
+	var b strings.Builder
+	if epic != nil {
+		fmt.Fprintf(&b, "# Briefing: %s (%s)\n", epic.Title, epic.ID)
+	} else {
+		fmt.Fprintf(&b, "# Briefing: %s\n", project)
+	}
+	if description != "" {
+		fmt.Fprintf(&b, "\n%s\n", briefExcerpt(description, briefDescriptionLimit))
+	} else {
+		b.WriteString("\n(No project description; set one with 'tpg projects describe'.)\n")
+	}
+
+	if epic != nil {
+		stats := calculateEpicStats(descendants)
+		fmt.Fprintf(&b, "\n## Epic\n\nStatus: %s | %d/%d tasks done", epic.Status, stats.Done, stats.Total)
+		if stats.InProgress > 0 {
+			fmt.Fprintf(&b, " | %d in progress", stats.InProgress)
+		}
+		if stats.Blocked > 0 {
+			fmt.Fprintf(&b, " | %d blocked", stats.Blocked)
+		}
+		b.WriteString("\n")
+		if desc := strings.TrimSpace(epic.Description); desc != "" {
+			fmt.Fprintf(&b, "\n%s\n", briefExcerpt(desc, briefDescriptionLimit))
+		}
+	}
+
+	if epic == nil || len(epics) > 0 {
+		if epic != nil {
+			b.WriteString("\n## Sub-epics\n\n")
+		} else {
+			b.WriteString("\n## Epics\n\n")
+		}
+		if len(epics) == 0 {
+			b.WriteString("(none open)\n")
+		}
+		for _, e := range epics {
+			tasks, err := database.GetDescendants(e.ID)
+			if err != nil {
+				return "", err
+			}
+			stats := calculateEpicStats(tasks)
+			fmt.Fprintf(&b, "- %s %s: %d/%d done", e.ID, e.Title, stats.Done, stats.Total)
+			if stats.InProgress > 0 {
+				fmt.Fprintf(&b, ", %d in progress", stats.InProgress)
+			}
+			if stats.Blocked > 0 {
+				fmt.Fprintf(&b, ", %d blocked", stats.Blocked)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Ready%s\n\n", briefCount(len(ready), limits.Ready))
+	if len(ready) == 0 {
+		b.WriteString("(nothing ready)\n")
+	}
+	for _, item := range briefCap(ready, limits.Ready) {
+		fmt.Fprintf(&b, "- %s P%d %s\n", item.ID, item.Priority, item.Title)
+	}
+
+	if len(concepts) > 0 {
+		fmt.Fprintf(&b, "\n## Concepts%s\n\n", briefCount(len(concepts), limits.Concepts))
+		for _, c := range briefCap(concepts, limits.Concepts) {
+			fmt.Fprintf(&b, "- %s (%d)", c.Name, c.LearningCount)
+			if c.Summary != "" {
+				fmt.Fprintf(&b, ": %s", truncateLine(c.Summary, 160))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(learnings) > 0 {
+		fmt.Fprintf(&b, "\n## Recent Learnings%s\n\n", briefCount(len(learnings), limits.Learnings))
+		for _, l := range briefCap(learnings, limits.Learnings) {
+			fmt.Fprintf(&b, "- %s %s", l.ID, l.Summary)
+			if len(l.Concepts) > 0 {
+				concepts := append([]string(nil), l.Concepts...)
+				sort.Strings(concepts)
+				fmt.Fprintf(&b, " [%s]", strings.Join(concepts, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\nNext: 'tpg show <id>' for details, 'tpg start <id>' to begin, 'tpg context -c <concept>' for learnings.\n")
+	return b.String(), nil
+}
+
+func isClosedStatus(s model.Status) bool {
+	return s == model.StatusDone || s == model.StatusCanceled
+}
+
+// briefCap returns at most limit elements of s; a negative limit keeps all.
+func briefCap[T any](s []T, limit int) []T {
+	if limit >= 0 && len(s) > limit {
+		return s[:limit]
+	}
+	return s
+}
+
+// briefCount returns " (N of M)" when a list is cut short, for its heading.
+func briefCount(total, limit int) string {
+	if limit >= 0 && total > limit {
+		return fmt.Sprintf(" (%d of %d)", limit, total)
+	}
+	return ""
+}
+
+// briefExcerpt shortens text to about n characters, cutting at a word
+// boundary.
+func briefExcerpt(text string, n int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	cut := string(runes[:n])
+	if i := strings.LastIndexAny(cut, " \n\t"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " \n\t.,;:") + " ..."
+}
+
+func init() {
+	briefCmd.Flags().StringVar(&flagBriefEpic, "epic", "", "Brief on this epic instead of the whole project")
+	briefCmd.Flags().IntVar(&flagBriefReady, "ready", 10, "Number of ready tasks to list (-1 for all)")
+	briefCmd.Flags().IntVar(&flagBriefConcepts, "concepts", 10, "Number of concepts to list (-1 for all)")
+	briefCmd.Flags().IntVar(&flagBriefLearnings, "learnings", 5, "Number of recent learnings to list (-1 for all)")
+	projectsDescribeCmd.Flags().BoolVar(&flagProjectsDescribeClear, "clear", false, "Remove the description")
+
+	projectsCmd.AddCommand(projectsDescribeCmd)
+	rootCmd.AddCommand(briefCmd)
+}
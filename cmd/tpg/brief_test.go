@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestBuildBrief(t *testing.T) {
+	database := setupTestDB(t)
+
+	if err := database.SetProjectDescription("test", "A tracker for agents."); err != nil {
+		t.Fatalf("SetProjectDescription: %v", err)
+	}
+	epic := createTestItem(t, database, "ep-auth", "Auth rewrite", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ts-refresh", "Token refresh", withParent("ep-auth"))
+	createTestItem(t, database, "ts-other", "Unrelated fix", withPriority(1))
+	now := time.Now()
+	taskID := "ts-refresh"
+	if err := database.CreateLearning(&model.Learning{
+		ID: "lrn-race", Project: "test", TaskID: &taskID, CreatedAt: now, UpdatedAt: now,
+		Summary: "Refresh races with logout", Status: model.LearningStatusActive, Concepts: []string{"auth"},
+	}); err != nil {
+		t.Fatalf("CreateLearning: %v", err)
+	}
+
+	brief, err := buildBrief(database, "test", nil, briefLimits{Ready: 1, Concepts: 10, Learnings: 5})
+	if err != nil {
+		t.Fatalf("buildBrief: %v", err)
+	}
+	for _, want := range []string{
+		"# Briefing: test",
+		"A tracker for agents.",
+		"- ep-auth Auth rewrite: 0/1 done",
+		"## Ready (1 of 2)",
+		"- ts-other P1 Unrelated fix",
+		"- auth (1)",
+		"- lrn-race Refresh races with logout [auth]",
+	} {
+		if !strings.Contains(brief, want) {
+			t.Errorf("brief missing %q:\n%s", want, brief)
+		}
+	}
+
+	brief, err = buildBrief(database, "test", epic, briefLimits{Ready: 10, Concepts: 10, Learnings: 5})
+	if err != nil {
+		t.Fatalf("buildBrief --epic: %v", err)
+	}
+	if !strings.Contains(brief, "# Briefing: Auth rewrite (ep-auth)") || !strings.Contains(brief, "- ts-refresh P2 Token refresh") {
+		t.Errorf("epic brief missing the epic or its ready task:\n%s", brief)
+	}
+	if strings.Contains(brief, "ts-other") {
+		t.Errorf("epic brief lists a task outside the epic:\n%s", brief)
+	}
+}
+
+func TestBriefExcerpt(t *testing.T) {
+	if got := briefExcerpt("short", 10); got != "short" {
+		t.Errorf("briefExcerpt(short) = %q", got)
+	}
+	if got := briefExcerpt("one two three four five", 12); got != "one two ..." {
+		t.Errorf("briefExcerpt = %q, want %q", got, "one two ...")
+	}
+}
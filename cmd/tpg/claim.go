@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// claimReadyTask implements 'tpg ready --claim': it starts the top ready task
+// (honoring --epic, --label, and --sort) for the calling agent. Candidates are
+// claimed with a conditional update, so if another agent takes the top task
+// first the next one is tried instead.
+func claimReadyTask(cmd *cobra.Command, database *db.DB, project string, agentCtx db.AgentContext) error {
+	var candidates []model.Item
+	var err error
+	if flagReadyEpic != "" {
+		_, candidates, err = readyEpicTasks(database, flagReadyEpic, project)
+	} else {
+		candidates, err = database.ReadyItemsSorted(project, flagFilterLabels, sortOrderFromFlags())
+	}
+	if err != nil {
+		return err
+	}
+
+	item, err := database.ClaimFirst(candidates, agentCtx)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		fmt.Println("No ready tasks to claim")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return &exitStatusError{code: 1}
+	}
+
+	_ = database.SetCurrentTask(db.CurrentSession(), item.ID)
+	logMsg := "Started (claimed from ready)"
+	if agentCtx.IsActive() {
+		logMsg = fmt.Sprintf("Started (claimed from ready, agent: %s)", agentCtx.ID)
+	}
+	_ = database.AddLog(item.ID, logMsg)
+
+	database.BackupQuiet()
+	fmt.Printf("Claimed %s: %s\n", item.ID, item.Title)
+	return nil
+}
+
+// readyEpicTasks returns the epic named by epicArg and its ready tasks for
+// 'tpg ready --epic': child epics and items outside project (when set) are
+// dropped, and unless --sort was given the tasks are ordered by label weight,
+// then priority. Labels are populated on the returned tasks.
+func readyEpicTasks(database *db.DB, epicArg, project string) (*model.Item, []model.Item, error) {
+	epicID, err := resolveItemArg(database, epicArg)
+	if err != nil {
+		return nil, nil, err
+	}
+	epic, err := database.GetItem(epicID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if epic.Type != model.ItemTypeEpic {
+		return nil, nil, fmt.Errorf("%s is not an epic", epicID)
+	}
+	order := sortOrderFromFlags()
+	items, err := database.ReadyItemsForEpic(epicID, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tasks []model.Item
+	for _, item := range items {
+		if item.Type != model.ItemTypeEpic && (project == "" || item.Project == project) {
+			tasks = append(tasks, item)
+		}
+	}
+	if err := database.PopulateItemLabels(tasks); err != nil {
+		return nil, nil, err
+	}
+	if order.IsZero() {
+		sort.SliceStable(tasks, func(i, j int) bool { return database.LabelWeights().ReadyLess(tasks[i], tasks[j]) })
+	}
+	return epic, tasks, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestReadyClaim(t *testing.T) {
+	database := setupCommandDB(t)
+	createTestItem(t, database, "ts-low", "Low priority", func(i *model.Item) { i.Priority = 3 })
+	createTestItem(t, database, "ts-high", "High priority", func(i *model.Item) { i.Priority = 1 })
+	t.Setenv("AGENT_ID", "agent-a")
+	t.Setenv("TPG_SESSION", "claim-test")
+
+	flagReadyClaim, flagProject = true, "test"
+	t.Cleanup(func() { flagReadyClaim, flagProject = false, "" })
+
+	for _, want := range []string{"ts-high", "ts-low"} {
+		if err := readyCmd.RunE(readyCmd, nil); err != nil {
+			t.Fatalf("ready --claim: %v", err)
+		}
+		item, err := database.GetItem(want)
+		if err != nil {
+			t.Fatalf("GetItem: %v", err)
+		}
+		if item.Status != model.StatusInProgress || item.AgentID == nil || *item.AgentID != "agent-a" {
+			t.Errorf("%s = %s by %v, want in_progress by agent-a", want, item.Status, item.AgentID)
+		}
+	}
+
+	var exitErr *exitStatusError
+	if err := readyCmd.RunE(readyCmd, nil); !errors.As(err, &exitErr) || exitErr.code != 1 {
+		t.Errorf("ready --claim with nothing ready = %v, want exit status 1", err)
+	}
+}
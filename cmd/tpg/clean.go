@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// printCleanItems lists the items 'tpg clean' is about to delete, under a
+// count line, with the references --prune-refs will detach.
+func printCleanItems(items []model.Item, status string, days int, refs map[string]db.ItemRefs) {
+	if len(items) == 0 {
+		fmt.Printf("  0 %s tasks older than %d days\n", status, days)
+		return
+	}
+	fmt.Printf("  %d %s tasks older than %d days:\n", len(items), status, days)
+	for _, item := range items {
+		fmt.Printf("    %s  %s\n", item.ID, item.Title)
+		if r, ok := refs[item.ID]; ok {
+			fmt.Printf("      detaching: %s\n", describeRefs(r))
+		}
+	}
+}
+
+// printKeptItems warns about the items 'tpg clean' leaves alone because
+// something still refers to them.
+func printKeptItems(items []model.Item, refs map[string]db.ItemRefs) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("Warning: keeping %d tasks that are still referenced (--prune-refs detaches and deletes them):\n", len(items))
+	for _, item := range items {
+		fmt.Printf("    %s  %s\n", item.ID, item.Title)
+		fmt.Printf("      %s\n", describeRefs(refs[item.ID]))
+	}
+}
+
+// describeRefs says what refers to an item, e.g. "needed by open ts-a, ts-b;
+// linked from learning lr-c".
+func describeRefs(r db.ItemRefs) string {
+	var parts []string
+	if len(r.OpenDependents) > 0 {
+		parts = append(parts, "needed by open "+strings.Join(r.OpenDependents, ", "))
+	}
+	if n := len(r.Learnings); n == 1 {
+		parts = append(parts, "linked from learning "+r.Learnings[0])
+	} else if n > 1 {
+		parts = append(parts, "linked from learnings "+strings.Join(r.Learnings, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// splitReferenced separates the items with references from the rest.
+func splitReferenced(items []model.Item, refs map[string]db.ItemRefs) (free, referenced []model.Item) {
+	for _, item := range items {
+		if _, ok := refs[item.ID]; ok {
+			referenced = append(referenced, item)
+		} else {
+			free = append(free, item)
+		}
+	}
+	return free, referenced
+}
+
+// saveCleanItems writes items to path in the 'tpg export --jsonl' format,
+// including the items that depend on them and the learnings linked to them,
+// so 'tpg import jsonl' can put them back with those links.
+func saveCleanItems(database *db.DB, path string, items []model.Item) error {
+	data, err := gatherExportData(database, items)
+	if err != nil {
+		return err
+	}
+	for i := range data {
+		dependents, err := database.GetDependents(data[i].Item.ID)
+		if err != nil {
+			return err
+		}
+		data[i].Dependents = dependents
+		learnings, err := database.LinkedLearnings(data[i].Item.ID)
+		if err != nil {
+			return err
+		}
+		data[i].Learnings = learnings
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := exportJSONL(f, data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func itemIDs(items []model.Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
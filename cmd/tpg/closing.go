@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/templates"
+)
+
+// closingItem converts an item for closing instruction templates.
+func closingItem(item model.Item) templates.ClosingItem {
+	return templates.ClosingItem{
+		ID:          item.ID,
+		Title:       item.Title,
+		Type:        string(item.Type),
+		Status:      string(item.Status),
+		Description: item.Description,
+		Results:     item.Results,
+		Labels:      item.Labels,
+	}
+}
+
+// closingData builds the template data for an epic's closing instructions
+// from the epic and its descendants (with labels populated).
+func closingData(epic model.Item, descendants []model.Item) templates.ClosingData {
+	data := templates.ClosingData{Epic: closingItem(epic), Vars: epic.TemplateVars}
+	var results []string
+	for _, d := range descendants {
+		data.Children = append(data.Children, closingItem(d))
+		if strings.TrimSpace(d.Results) != "" {
+			results = append(results, strings.TrimSpace(d.Results))
+		}
+	}
+	data.Results = strings.Join(results, "\n\n")
+	return data
+}
+
+// renderClosing renders an epic's closing instructions. When the template
+// is broken it warns on stderr and returns the raw text.
+func renderClosing(epic model.Item, descendants []model.Item) string {
+	text, err := templates.RenderClosing(epic.ClosingInstructions, closingData(epic, descendants))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: closing instructions for %s did not render: %v\n", epic.ID, err)
+	}
+	return text
+}
+
+// renderClosingInstructions loads an epic's descendants and renders its
+// closing instructions against them.
+func renderClosingInstructions(database *db.DB, epic *model.Item) (string, error) {
+	if epic.ClosingInstructions == "" {
+		return "", nil
+	}
+	descendants, err := database.GetDescendants(epic.ID)
+	if err != nil {
+		return "", err
+	}
+	items := append([]model.Item{*epic}, descendants...)
+	if err := database.PopulateItemLabels(items); err != nil {
+		return "", err
+	}
+	return renderClosing(items[0], items[1:]), nil
+}
+
+// openAncestorEpics returns the IDs of itemID's ancestor epics that are not
+// closed yet, so the caller can tell which ones a change auto-completed.
+func openAncestorEpics(database *db.DB, itemID string) []string {
+	chain, err := database.GetParentChain(itemID)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, a := range chain {
+		if a.Type == model.ItemTypeEpic && a.Status != model.StatusDone && a.Status != model.StatusCanceled {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids
+}
+
+// announceCompletedEpics reports each of epicIDs that is now done, with its
+// closing instructions rendered against what its children did. The rendered
+// text is also logged on the epic, so the checklist stays with it.
+func announceCompletedEpics(database *db.DB, epicIDs []string) {
+	for _, id := range epicIDs {
+		epic, err := database.GetItem(id)
+		if err != nil || epic.Status != model.StatusDone {
+			continue
+		}
+		fmt.Printf("Epic %s auto-completed\n", id)
+		text, err := renderClosingInstructions(database, epic)
+		if err != nil || strings.TrimSpace(text) == "" {
+			continue
+		}
+		fmt.Printf("\nClosing instructions for %s:\n%s\n", id, strings.TrimRight(text, "\n"))
+		_ = database.AddLog(id, "Closing instructions:\n"+strings.TrimSpace(text))
+	}
+}
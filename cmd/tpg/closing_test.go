@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestDone_RendersClosingInstructionsOnAutoComplete(t *testing.T) {
+	database := setupAddCommandTest(t)
+	createTestItem(t, database, "ep-rel", "Release", withType(model.ItemTypeEpic), func(i *model.Item) {
+		i.ClosingInstructions = "Review:\n{{range files .Results}}- [ ] {{.}}\n{{end}}"
+	})
+	createTestItem(t, database, "ts-a", "Parser", withParent("ep-rel"), withStatus(model.StatusDone), func(i *model.Item) {
+		i.Results = "Rewrote internal/parse/lexer.go"
+	})
+	createTestItem(t, database, "ts-b", "Docs", withParent("ep-rel"), withStatus(model.StatusInProgress))
+
+	var runErr error
+	out := captureOutput(func() {
+		runErr = doneCmd.RunE(doneCmd, []string{"ts-b", "Updated docs/CLI.md"})
+	})
+	if runErr != nil {
+		t.Fatalf("done failed: %v", runErr)
+	}
+
+	want := "- [ ] docs/CLI.md\n- [ ] internal/parse/lexer.go"
+	if !strings.Contains(out, "Epic ep-rel auto-completed") || !strings.Contains(out, want) {
+		t.Errorf("output missing rendered closing instructions:\n%s", out)
+	}
+	logs, err := database.GetLogs("ep-rel")
+	if err != nil {
+		t.Fatalf("GetLogs: %v", err)
+	}
+	found := false
+	for _, l := range logs {
+		if strings.Contains(l.Message, want) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("rendered closing instructions not logged on the epic: %+v", logs)
+	}
+}
@@ -25,7 +25,7 @@ func TestCompleteStatusValues(t *testing.T) {
 		{
 			name:       "empty returns all",
 			toComplete: "",
-			want:       []string{"open", "in_progress", "blocked", "done", "canceled"},
+			want:       []string{"open", "in_progress", "blocked", "pending_review", "done", "canceled"},
 		},
 	}
 
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var flagAssumeYes bool
+
+// assumeYes reports whether confirmation prompts should be answered yes
+// without asking, via the global --yes flag or the TPG_ASSUME_YES
+// environment variable (any true value, e.g. 1 or true).
+func assumeYes() bool {
+	if flagAssumeYes {
+		return true
+	}
+	v, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("TPG_ASSUME_YES")))
+	return err == nil && v
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+func stdinIsTerminal() bool {
+	fi, _ := os.Stdin.Stat()
+	return fi != nil && (fi.Mode()&os.ModeCharDevice) != 0
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes.
+// It answers yes without asking when assumeYes is set, and no without
+// asking when stdin is not a terminal, so scripts never hang on a prompt.
+func confirm(prompt string) bool {
+	if assumeYes() {
+		fmt.Printf("%s [y/N]: y (assumed)\n", prompt)
+		return true
+	}
+	if !stdinIsTerminal() {
+		fmt.Printf("%s [y/N]: n (no terminal; pass --yes or set TPG_ASSUME_YES=1)\n", prompt)
+		return false
+	}
+	fmt.Printf("%s [y/N]: ", prompt)
+	var response string
+	fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
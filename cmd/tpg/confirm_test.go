@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestAssumeYes(t *testing.T) {
+	defer func() { flagAssumeYes = false }()
+
+	for _, tt := range []struct {
+		flag bool
+		env  string
+		want bool
+	}{
+		{false, "", false},
+		{false, "1", true},
+		{false, "true", true},
+		{false, "0", false},
+		{false, "nope", false},
+		{true, "", true},
+		{true, "0", true},
+	} {
+		flagAssumeYes = tt.flag
+		t.Setenv("TPG_ASSUME_YES", tt.env)
+		if got := assumeYes(); got != tt.want {
+			t.Errorf("assumeYes() with flag=%v env=%q = %v, want %v", tt.flag, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestConfirm_AssumeYes(t *testing.T) {
+	t.Setenv("TPG_ASSUME_YES", "1")
+	if !confirm("Proceed?") {
+		t.Error("confirm should return true when TPG_ASSUME_YES is set")
+	}
+}
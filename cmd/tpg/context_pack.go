@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagPackEpic    string
+	flagPackOut     string
+	flagPackResults int
+)
+
+// packConceptLimit caps the concepts matched against an epic's text when
+// choosing learnings for a context pack.
+const packConceptLimit = 5
+
+var contextPackCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Write an epic's context to a single Markdown file",
+	Long: `Assemble everything an agent needs to work on an epic into one Markdown
+file: the epic and its inherited context, ready tasks, relevant learnings,
+and recent results.
+
+The output contains no generation timestamps or relative times, so packing
+an unchanged epic produces an identical file. It can be committed or fed
+into a prompt pipeline outside of tpg.
+
+Learnings are included when they were recorded against a task in the epic,
+or tagged with a concept that matches the epic's text.
+
+Examples:
+  tpg context pack --epic ep-abc123 --out pack.md
+  tpg context pack --epic ep-abc123 --results 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagPackEpic == "" {
+			return fmt.Errorf("--epic is required")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		epicID, err := resolveItemArg(database, flagPackEpic)
+		if err != nil {
+			return err
+		}
+		pack, err := buildContextPack(database, epicID, flagPackResults)
+		if err != nil {
+			return err
+		}
+
+		if flagPackOut == "-" {
+			_, err := io.WriteString(os.Stdout, pack)
+			return err
+		}
+		if err := os.WriteFile(flagPackOut, []byte(pack), 0644); err != nil {
+			return fmt.Errorf("failed to write context pack: %w", err)
+		}
+		fmt.Printf("Wrote %s\n", flagPackOut)
+		return nil
+	},
+}
+
+// buildContextPack renders the context pack for an epic. resultsLimit caps
+// the recent results included (-1 for all).
+func buildContextPack(database *db.DB, epicID string, resultsLimit int) (string, error) {
+	epic, err := database.GetItem(epicID)
+	if err != nil {
+		return "", err
+	}
+	if !epic.Type.CanHaveChildren() {
+		return "", fmt.Errorf("%s is not an epic (type: %s)", epicID, epic.Type)
+	}
+	descendants, err := database.GetDescendants(epicID)
+	if err != nil {
+		return "", err
+	}
+	ready, err := database.ReadyItemsForEpic(epicID, db.SortOrder{})
+	if err != nil {
+		return "", err
+	}
+	inherited, err := database.GetAncestorSharedContext(epicID)
+	if err != nil {
+		return "", err
+	}
+
+	items := append([]model.Item{*epic}, descendants...)
+	if err := database.PopulateItemLabels(items); err != nil {
+		return "", err
+	}
+	if err := database.PopulateItemLabels(ready); err != nil {
+		return "", err
+	}
+	if err := renderTemplatesForItems(items); err != nil {
+		return "", err
+	}
+	if err := renderTemplatesForItems(ready); err != nil {
+		return "", err
+	}
+	epic = &items[0]
+	descendants = items[1:]
+
+	learnings, err := packLearnings(database, epic, descendants)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (%s)\n\n", epic.Title, epic.ID)
+	fmt.Fprintf(&b, "Status: %s | Priority: %d", epic.Status, epic.Priority)
+	if len(epic.Labels) > 0 {
+		fmt.Fprintf(&b, " | Labels: %s", strings.Join(epic.Labels, ", "))
+	}
+	b.WriteString("\n")
+	packSection(&b, 0, "", epic.Description)
+
+	if len(inherited) > 0 || epic.SharedContext != "" {
+		b.WriteString("\n## Shared Context\n")
+		for _, entry := range inherited {
+			packSection(&b, 3, fmt.Sprintf("From %s: %s", entry.EpicID, entry.EpicTitle), entry.SharedContext)
+		}
+		if epic.SharedContext != "" {
+			packSection(&b, 3, fmt.Sprintf("From %s: %s", epic.ID, epic.Title), epic.SharedContext)
+		}
+	}
+	if epic.ClosingInstructions != "" {
+		packSection(&b, 2, "Closing Instructions", renderClosing(*epic, descendants))
+	}
+
+	stats := calculateEpicStats(descendants)
+	fmt.Fprintf(&b, "\n## Progress\n\n%d/%d tasks done | open %d | in progress %d | blocked %d | canceled %d\n",
+		stats.Done, stats.Total, stats.Open, stats.InProgress, stats.Blocked, stats.Canceled)
+
+	b.WriteString("\n## Ready Tasks\n")
+	if len(ready) == 0 {
+		b.WriteString("\n(none)\n")
+	}
+	for _, item := range ready {
+		fmt.Fprintf(&b, "\n### %s: %s\n\nPriority: %d", item.ID, item.Title, item.Priority)
+		if len(item.Labels) > 0 {
+			fmt.Fprintf(&b, " | Labels: %s", strings.Join(item.Labels, ", "))
+		}
+		b.WriteString("\n")
+		packSection(&b, 0, "", item.Description)
+	}
+
+	if len(learnings) > 0 {
+		b.WriteString("\n## Learnings\n")
+		for _, l := range learnings {
+			fmt.Fprintf(&b, "\n### %s: %s\n", l.ID, l.Summary)
+			if len(l.Concepts) > 0 {
+				concepts := append([]string(nil), l.Concepts...)
+				sort.Strings(concepts)
+				fmt.Fprintf(&b, "\nConcepts: %s\n", strings.Join(concepts, ", "))
+			}
+			packSection(&b, 0, "", l.Detail)
+		}
+	}
+
+	results := packResults(descendants, resultsLimit)
+	if len(results) > 0 {
+		b.WriteString("\n## Recent Results\n")
+		for _, item := range results {
+			fmt.Fprintf(&b, "\n### %s: %s (done %s)\n", item.ID, item.Title, item.ClosedAt.UTC().Format("2006-01-02"))
+			packSection(&b, 0, "", item.Results)
+		}
+	}
+	return b.String(), nil
+}
+
+// packSection writes a blank line, an optional heading at the given level,
+// and the body. Empty bodies are skipped.
+func packSection(b *strings.Builder, level int, heading, body string) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return
+	}
+	if heading != "" {
+		fmt.Fprintf(b, "\n%s %s\n", strings.Repeat("#", level), heading)
+	}
+	fmt.Fprintf(b, "\n%s\n", body)
+}
+
+// packLearnings returns the active learnings relevant to an epic, sorted by
+// ID: those recorded against one of its items, and those tagged with a
+// concept that confidently matches the epic's text.
+func packLearnings(database *db.DB, epic *model.Item, descendants []model.Item) ([]model.Learning, error) {
+	inEpic := map[string]bool{epic.ID: true}
+	text := []string{epic.Title, epic.Description, epic.SharedContext}
+	for _, item := range descendants {
+		inEpic[item.ID] = true
+		text = append(text, item.Title, item.Description)
+	}
+
+	suggestions, err := database.SuggestConcepts(epic.Project, strings.Join(text, "\n"), packConceptLimit)
+	if err != nil {
+		return nil, err
+	}
+	concepts := make(map[string]bool)
+	for _, name := range confidentConcepts(suggestions) {
+		concepts[name] = true
+	}
+
+	all, err := database.GetAllLearnings(epic.Project, false)
+	if err != nil {
+		return nil, err
+	}
+	var relevant []model.Learning
+	for _, l := range all {
+		keep := l.TaskID != nil && inEpic[*l.TaskID]
+		for _, c := range l.Concepts {
+			keep = keep || concepts[c]
+		}
+		if keep {
+			relevant = append(relevant, l)
+		}
+	}
+	sort.Slice(relevant, func(i, j int) bool { return relevant[i].ID < relevant[j].ID })
+	return relevant, nil
+}
+
+// packResults returns done items with results, most recently closed first.
+func packResults(items []model.Item, limit int) []model.Item {
+	var done []model.Item
+	for _, item := range items {
+		if item.Status == model.StatusDone && item.Results != "" && item.ClosedAt != nil {
+			done = append(done, item)
+		}
+	}
+	sort.SliceStable(done, func(i, j int) bool {
+		if !done[i].ClosedAt.Equal(*done[j].ClosedAt) {
+			return done[i].ClosedAt.After(*done[j].ClosedAt)
+		}
+		return done[i].ID < done[j].ID
+	})
+	if limit >= 0 && len(done) > limit {
+		done = done[:limit]
+	}
+	return done
+}
+
+func init() {
+	contextPackCmd.Flags().StringVar(&flagPackEpic, "epic", "", "Epic to pack (required)")
+	contextPackCmd.Flags().StringVarP(&flagPackOut, "out", "o", "-", "Output file ('-' for stdout)")
+	contextPackCmd.Flags().IntVar(&flagPackResults, "results", 10, "Number of recent results to include (-1 for all)")
+	contextCmd.AddCommand(contextPackCmd)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestBuildContextPack(t *testing.T) {
+	database := setupTestDB(t)
+
+	createTestItem(t, database, "ep-pack", "Auth rewrite", withType(model.ItemTypeEpic), withDescription("Replace session auth."))
+	if err := database.SetSharedContext("ep-pack", "Use the new token store."); err != nil {
+		t.Fatalf("SetSharedContext: %v", err)
+	}
+	createTestItem(t, database, "ts-ready", "Add token store", withParent("ep-pack"), withDescription("Store tokens in sqlite."))
+	createTestItem(t, database, "ts-later", "Migrate users", withParent("ep-pack"))
+	createTestItem(t, database, "ts-done", "Spike", withParent("ep-pack"))
+	createTestItem(t, database, "ts-other", "Unrelated")
+	if err := database.AddDep("ts-later", "ts-ready"); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := database.CompleteItem("ts-done", "Tokens fit in one table.", db.AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+
+	addLearning := func(summary string, taskID *string, concepts ...string) {
+		now := time.Now()
+		if err := database.CreateLearning(&model.Learning{
+			ID: model.GenerateLearningID(), Project: "test", CreatedAt: now, UpdatedAt: now,
+			TaskID: taskID, Summary: summary, Status: model.LearningStatusActive, Concepts: concepts,
+		}); err != nil {
+			t.Fatalf("CreateLearning: %v", err)
+		}
+	}
+	spike := "ts-done"
+	addLearning("Linked to the spike", &spike, "misc")
+	addLearning("Auth tokens expire hourly", nil, "auth")
+	addLearning("CSS grid quirk", nil, "frontend")
+
+	pack, err := buildContextPack(database, "ep-pack", 10)
+	if err != nil {
+		t.Fatalf("buildContextPack: %v", err)
+	}
+	for _, want := range []string{
+		"# Auth rewrite (ep-pack)",
+		"Replace session auth.",
+		"Use the new token store.",
+		"### ts-ready: Add token store",
+		"Store tokens in sqlite.",
+		"Linked to the spike",
+		"Auth tokens expire hourly",
+		"### ts-done: Spike (done ",
+		"Tokens fit in one table.",
+	} {
+		if !strings.Contains(pack, want) {
+			t.Errorf("pack missing %q:\n%s", want, pack)
+		}
+	}
+	for _, unwanted := range []string{"ts-later: Migrate users", "CSS grid quirk", "Unrelated"} {
+		if strings.Contains(pack, unwanted) {
+			t.Errorf("pack should not contain %q:\n%s", unwanted, pack)
+		}
+	}
+
+	again, err := buildContextPack(database, "ep-pack", 10)
+	if err != nil {
+		t.Fatalf("buildContextPack: %v", err)
+	}
+	if again != pack {
+		t.Error("expected identical output for an unchanged epic")
+	}
+
+	if _, err := buildContextPack(database, "ts-ready", 10); err == nil {
+		t.Error("expected error packing a task")
+	}
+}
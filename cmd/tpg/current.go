@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show or set the task you are working on",
+	Long: `Show, set, or clear the current task.
+
+The current task is remembered per agent ($AGENT_ID), or per terminal when no
+agent ID is set ($TPG_SESSION if set, otherwise the parent shell). 'tpg start'
+makes the started task current, and 'tpg done'/'tpg cancel' clear it.
+
+Commands that take a task ID accept "." for the current task:
+  tpg log . "found the cause"
+  tpg show .
+  tpg done . "fixed"
+
+Examples:
+  tpg current
+  tpg current set ts-a1b2c3
+  tpg current clear`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := database.GetCurrentTask(db.CurrentSession())
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			fmt.Println("No current task")
+			return nil
+		}
+		item, err := database.GetItem(id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s [%s] %s\n", item.ID, item.Status, item.Title)
+		return nil
+	},
+}
+
+var currentSetCmd = &cobra.Command{
+	Use:   "set <id>",
+	Short: "Make a task the current task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+		if err := database.SetCurrentTask(db.CurrentSession(), args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Current task: %s\n", args[0])
+		return nil
+	},
+}
+
+var currentClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget the current task",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if err := database.ClearCurrentTask(db.CurrentSession()); err != nil {
+			return err
+		}
+		fmt.Println("Cleared current task")
+		return nil
+	},
+}
+
+// resolveItemArg replaces "." with the session's current task ID and an
+// item alias with the ID it points at. Other values are returned unchanged.
+func resolveItemArg(database *db.DB, id string) (string, error) {
+	if id != "." {
+		target, err := database.ResolveItemAlias(id)
+		if err != nil || target == "" {
+			return id, err
+		}
+		return target, nil
+	}
+	current, err := database.GetCurrentTask(db.CurrentSession())
+	if err != nil {
+		return "", err
+	}
+	if current == "" {
+		return "", fmt.Errorf("no current task (set one with 'tpg current set <id>' or 'tpg start <id>')")
+	}
+	return current, nil
+}
+
+func init() {
+	currentCmd.AddCommand(currentSetCmd)
+	currentCmd.AddCommand(currentClearCmd)
+	rootCmd.AddCommand(currentCmd)
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestResolveCurrentArg(t *testing.T) {
+	database := setupTestDB(t)
+	t.Setenv("AGENT_ID", "agent-current")
+	item := createTestItem(t, database, "ts-cur", "Current task")
+
+	if id, err := resolveItemArg(database, "ts-other"); err != nil || id != "ts-other" {
+		t.Errorf("resolveItemArg(ts-other) = %q, %v; want unchanged", id, err)
+	}
+	if _, err := resolveItemArg(database, "."); err == nil {
+		t.Error("expected error when no current task is set")
+	}
+
+	if err := database.SetCurrentTask("agent:agent-current", item.ID); err != nil {
+		t.Fatalf("SetCurrentTask failed: %v", err)
+	}
+	id, err := resolveItemArg(database, ".")
+	if err != nil {
+		t.Fatalf("resolveItemArg failed: %v", err)
+	}
+	if id != item.ID {
+		t.Errorf("resolveItemArg(.) = %q, want %q", id, item.ID)
+	}
+}
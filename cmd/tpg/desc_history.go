@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var descHistoryCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "List previous versions of a task's description",
+	Long: `List the descriptions a task had before they were replaced by 'tpg desc',
+'tpg edit', 'tpg append', a merge, or a template resync. Revisions are
+numbered from 1 (the oldest); compare one with the current description
+using 'tpg desc diff'.
+
+Examples:
+  tpg desc history ts-a1b2c3
+  tpg desc diff ts-a1b2c3 2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		item, err := database.GetItem(id)
+		if err != nil {
+			return err
+		}
+		versions, err := database.DescriptionVersions(id)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			fmt.Printf("%s has no previous descriptions\n", id)
+			return nil
+		}
+
+		fmt.Printf("%s %s\n\n", item.ID, item.Title)
+		for _, v := range versions {
+			by := ""
+			if v.AgentID != "" {
+				by = " by " + v.AgentID
+			}
+			fmt.Printf("  %3d  replaced %s%s, %s  %s\n", v.Rev, formatTimeAgo(v.ReplacedAt), by, descLines(v.Description), descSummary(v.Description))
+		}
+		fmt.Printf("  now  %s  %s\n", descLines(item.Description), descSummary(item.Description))
+		fmt.Printf("\nCompare with: tpg desc diff %s <rev>\n", id)
+		return nil
+	},
+}
+
+var descDiffCmd = &cobra.Command{
+	Use:   "diff <id> <rev>",
+	Short: "Compare a previous description with the current one",
+	Long: `Show a line diff from a previous description (see 'tpg desc history')
+to the current one. Lines starting with "-" were only in the old revision,
+lines starting with "+" are only in the current description.
+
+To bring an old revision back, pass it to 'tpg desc'; the current text is
+kept as a new revision:
+  tpg desc ts-a1b2c3 - <<'EOF'
+  ...
+  EOF
+
+Examples:
+  tpg desc diff ts-a1b2c3 1`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		rev, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid revision %q: use a number from 'tpg desc history %s'", args[1], id)
+		}
+		item, err := database.GetItem(id)
+		if err != nil {
+			return err
+		}
+		version, err := database.GetDescriptionVersion(id, rev)
+		if err != nil {
+			return err
+		}
+
+		printDescDiff(version, item.Description)
+		return nil
+	},
+}
+
+// printDescDiff prints the line diff from a previous description to current.
+func printDescDiff(v *db.DescriptionVersion, current string) {
+	fmt.Printf("--- %s revision %d (replaced %s)\n", v.ItemID, v.Rev, v.ReplacedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("+++ %s current\n", v.ItemID)
+	if v.Description == current {
+		fmt.Println("(no differences)")
+		return
+	}
+	for _, line := range lineDiff(v.Description, current) {
+		fmt.Println(line)
+	}
+}
+
+// descSummary returns the first non-empty line of a description, shortened
+// for one-line listings.
+func descSummary(desc string) string {
+	for _, line := range strings.Split(desc, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			if len(line) > 60 {
+				line = line[:57] + "..."
+			}
+			return line
+		}
+	}
+	return "(empty)"
+}
+
+// descLines reports how many lines a description has.
+func descLines(desc string) string {
+	n := 0
+	if desc != "" {
+		n = strings.Count(desc, "\n") + 1
+	}
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
+
+func init() {
+	descCmd.AddCommand(descHistoryCmd)
+	descCmd.AddCommand(descDiffCmd)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <backup> [other-backup]",
+	Short: "Show what changed between a backup and the database",
+	Long: `Compare the current database against a backup, or two backups against
+each other, and list created, deleted, and changed items, status changes,
+and new learnings.
+
+Backups can be given as a path or by name as shown in 'tpg backups'. With two
+backups, the first is treated as "before" and the second as "after".
+Backup files are never modified.
+
+Useful for reviewing what an agent session changed.
+
+Examples:
+  tpg backups                                  # Find a backup name
+  tpg diff tpg-2024-01-09T12-00-00.000-ab12cd34.db
+  tpg diff before.db after.db`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		beforePath, err := db.ResolveBackupPath(args[0])
+		if err != nil {
+			return err
+		}
+		before, cleanupBefore, err := db.OpenSnapshot(beforePath)
+		if err != nil {
+			return err
+		}
+		defer cleanupBefore()
+
+		var after *db.DB
+		afterName := "current database"
+		if len(args) == 2 {
+			afterPath, err := db.ResolveBackupPath(args[1])
+			if err != nil {
+				return err
+			}
+			snapshot, cleanupAfter, err := db.OpenSnapshot(afterPath)
+			if err != nil {
+				return err
+			}
+			defer cleanupAfter()
+			after = snapshot
+			afterName = filepath.Base(afterPath)
+		} else {
+			database, err := openDB()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = database.Close() }()
+			after = database
+		}
+
+		diff, err := db.DiffDatabases(before, after)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Comparing %s -> %s\n", filepath.Base(beforePath), afterName)
+		printDatabaseDiff(diff)
+		return nil
+	},
+}
+
+func printDatabaseDiff(diff *db.DatabaseDiff) {
+	if diff.IsEmpty() {
+		fmt.Println("\nNo changes")
+		return
+	}
+
+	if len(diff.Created) > 0 {
+		fmt.Printf("\nCreated (%d):\n", len(diff.Created))
+		for _, item := range diff.Created {
+			fmt.Printf("  + %s  [%s] %s\n", item.ID, item.Status, item.Title)
+		}
+	}
+	if len(diff.Deleted) > 0 {
+		fmt.Printf("\nDeleted (%d):\n", len(diff.Deleted))
+		for _, item := range diff.Deleted {
+			fmt.Printf("  - %s  [%s] %s\n", item.ID, item.Status, item.Title)
+		}
+	}
+
+	var statusChanges, fieldChanges []db.ItemChange
+	for _, c := range diff.Changed {
+		if c.StatusChanged() {
+			statusChanges = append(statusChanges, c)
+		}
+		if len(c.Fields) > 0 {
+			fieldChanges = append(fieldChanges, c)
+		}
+	}
+	if len(statusChanges) > 0 {
+		fmt.Printf("\nStatus changes (%d):\n", len(statusChanges))
+		for _, c := range statusChanges {
+			fmt.Printf("  %s  %s -> %s  %s\n", c.ID, c.OldStatus, c.NewStatus, c.Title)
+		}
+	}
+	if len(fieldChanges) > 0 {
+		fmt.Printf("\nChanged (%d):\n", len(fieldChanges))
+		for _, c := range fieldChanges {
+			fmt.Printf("  %s  %s (%s)\n", c.ID, c.Title, strings.Join(c.Fields, ", "))
+		}
+	}
+	if len(diff.NewLearnings) > 0 {
+		fmt.Printf("\nNew learnings (%d):\n", len(diff.NewLearnings))
+		for _, l := range diff.NewLearnings {
+			fmt.Printf("  %s  %s\n", l.ID, l.Summary)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
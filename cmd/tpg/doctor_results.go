@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// bulkResultsHeader explains the file opened by 'tpg doctor --check results'.
+const bulkResultsHeader = `# Write results under each item's heading, then save and quit.
+# Sections left empty are skipped. Lines starting with '# ' are ignored.
+`
+
+// runDoctorResults finds done items without results and offers to fill them
+// in, first from each item's last progress log, then in one editor session.
+func runDoctorResults(database *db.DB, dryRun bool) error {
+	fmt.Println("\n5. Checking for done items without results...")
+	missing, err := database.DoneWithoutResults("")
+	if err != nil {
+		return fmt.Errorf("failed to check results: %w", err)
+	}
+	if len(missing) == 0 {
+		fmt.Println("   ✓ All done items have results")
+		return nil
+	}
+
+	fmt.Printf("   ⚠️  Found %d done items without results:\n", len(missing))
+	suggested := make(map[string]string)
+	for _, item := range missing {
+		suggestion, err := database.ResultsBackfill(item.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("      - %s: %s\n", item.ID, item.Title)
+		if suggestion != "" {
+			suggested[item.ID] = suggestion
+			fmt.Printf("        from last log: %s\n", truncateLine(suggestion, 70))
+		}
+	}
+	if dryRun {
+		fmt.Println("\n   (dry-run mode - no changes made)")
+		return nil
+	}
+
+	if len(suggested) > 0 {
+		fmt.Println()
+		if confirm(fmt.Sprintf("   Backfill %d items from their last log?", len(suggested))) {
+			filled := 0
+			for _, item := range missing {
+				text, ok := suggested[item.ID]
+				if !ok {
+					continue
+				}
+				if err := database.SetResults(item.ID, text); err != nil {
+					fmt.Printf("      ✗ Failed to set results for %s: %v\n", item.ID, err)
+					continue
+				}
+				filled++
+			}
+			fmt.Printf("   ✓ Backfilled %d items\n", filled)
+		} else {
+			suggested = map[string]string{}
+		}
+	}
+
+	var rest []model.Item
+	for _, item := range missing {
+		if _, ok := suggested[item.ID]; !ok {
+			rest = append(rest, item)
+		}
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+	fmt.Println()
+	if !confirm(fmt.Sprintf("   Write results for the other %d items in your editor?", len(rest))) {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString(bulkResultsHeader)
+	for _, item := range rest {
+		fmt.Fprintf(&b, "\n## %s: %s\n\n", item.ID, item.Title)
+	}
+	edited, changed, err := editTextInEditor(b.String())
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Println("   No changes made")
+		return nil
+	}
+	ids := make([]string, len(rest))
+	for i, item := range rest {
+		ids[i] = item.ID
+	}
+	written := parseBulkResults(edited, ids)
+	filled := 0
+	for _, item := range rest {
+		text := written[item.ID]
+		if text == "" {
+			continue
+		}
+		if err := database.SetResults(item.ID, text); err != nil {
+			fmt.Printf("      ✗ Failed to set results for %s: %v\n", item.ID, err)
+			continue
+		}
+		filled++
+	}
+	fmt.Printf("   ✓ Wrote results for %d items\n", filled)
+	return nil
+}
+
+// parseBulkResults splits an edited bulk results file into results by item
+// ID. Each section starts with a "## <id>: <title>" heading for one of ids;
+// other "## " lines are part of the results.
+func parseBulkResults(text string, ids []string) map[string]string {
+	known := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+	results := make(map[string]string)
+	var id string
+	var body []string
+	flush := func() {
+		if id != "" {
+			if r := strings.TrimSpace(strings.Join(body, "\n")); r != "" {
+				results[id] = r
+			}
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if rest, ok := strings.CutPrefix(line, "## "); ok {
+			if heading, _, _ := strings.Cut(rest, ":"); known[strings.TrimSpace(heading)] {
+				flush()
+				id = strings.TrimSpace(heading)
+				continue
+			}
+		}
+		if strings.HasPrefix(line, "# ") || id == "" {
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return results
+}
+
+// truncateLine shortens s to its first line, at most n characters.
+func truncateLine(s string, n int) string {
+	s, _, cut := strings.Cut(strings.TrimSpace(s), "\n")
+	if len(s) > n {
+		return s[:n-3] + "..."
+	}
+	if cut {
+		return s + " ..."
+	}
+	return s
+}
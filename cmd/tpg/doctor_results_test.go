@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestParseBulkResults(t *testing.T) {
+	text := bulkResultsHeader + `
+## ts-aaa: First task
+Fixed the parser.
+
+## Details
+It was an off-by-one.
+
+## ts-bbb: Second task
+
+## ts-ccc: Third task
+Done in api/handler.go
+`
+	got := parseBulkResults(text, []string{"ts-aaa", "ts-bbb", "ts-ccc"})
+	want := map[string]string{
+		"ts-aaa": "Fixed the parser.\n\n## Details\nIt was an off-by-one.",
+		"ts-ccc": "Done in api/handler.go",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseBulkResults = %q, want %q", got, want)
+	}
+	for id, w := range want {
+		if got[id] != w {
+			t.Errorf("results[%s] = %q, want %q", id, got[id], w)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+// resultTemplateAuto is the --template value used when the flag is given
+// without a name; the template is then picked from the item's labels/type.
+const resultTemplateAuto = "auto"
+
+var htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// resultsFromTemplate opens the result skeleton for an item in the editor and
+// returns the filled-in results. It errors if the skeleton is left untouched.
+func resultsFromTemplate(database *db.DB, id, name string) (string, error) {
+	item, err := database.GetItem(id)
+	if err != nil {
+		return "", err
+	}
+	labels, err := database.GetItemLabels(id)
+	if err != nil {
+		return "", err
+	}
+	for _, l := range labels {
+		item.Labels = append(item.Labels, l.Name)
+	}
+	config, err := db.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	var skeleton string
+	if name == resultTemplateAuto {
+		_, skeleton = config.ResultTemplateFor(item)
+	} else {
+		var ok bool
+		skeleton, ok = config.ResultTemplate(name)
+		if !ok {
+			return "", fmt.Errorf("unknown result template %q (configure it under result_templates in .tpg/config.json)", name)
+		}
+	}
+
+	initial := fmt.Sprintf("<!-- Results for %s: %s -->\n<!-- Fill in the sections below. Lines in HTML comments are dropped. -->\n\n%s", item.ID, item.Title, skeleton)
+	edited, changed, err := editTextInEditor(initial)
+	if err != nil {
+		return "", err
+	}
+
+	results := strings.TrimSpace(htmlCommentRe.ReplaceAllString(edited, ""))
+	if !changed || results == "" || results == strings.TrimSpace(skeleton) {
+		return "", fmt.Errorf("results template was not filled in; %s not completed", id)
+	}
+	return results, nil
+}
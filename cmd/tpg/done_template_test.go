@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// fakeEditor installs a TPG_EDITOR script that runs the given sed expression
+// against the file being edited.
+func fakeEditor(t *testing.T, sedExpr string) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	content := "#!/bin/sh\nsed -i '" + sedExpr + "' \"$1\"\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatalf("failed to write editor script: %v", err)
+	}
+	t.Setenv("TPG_EDITOR", script)
+}
+
+func TestDone_TemplateFillsResults(t *testing.T) {
+	database := setupAddCommandTest(t)
+	item := createTestItem(t, database, "ts-bug", "Fix crash", withStatus(model.StatusInProgress))
+	if err := database.AddLabelToItem(item.ID, item.Project, "bug"); err != nil {
+		t.Fatalf("failed to add label: %v", err)
+	}
+	fakeEditor(t, `s/^## Root cause$/## Root cause\nnil map/`)
+
+	flagDoneTemplate = resultTemplateAuto
+	t.Cleanup(func() { flagDoneTemplate = "" })
+
+	var runErr error
+	captureOutput(func() { runErr = doneCmd.RunE(doneCmd, []string{item.ID}) })
+	if runErr != nil {
+		t.Fatalf("done --template failed: %v", runErr)
+	}
+
+	got, err := database.GetItem(item.ID)
+	if err != nil {
+		t.Fatalf("failed to get item: %v", err)
+	}
+	if got.Status != model.StatusDone {
+		t.Fatalf("expected done, got %s", got.Status)
+	}
+	if !strings.Contains(got.Results, "## Root cause\nnil map") {
+		t.Errorf("results missing filled-in bug section: %q", got.Results)
+	}
+	if strings.Contains(got.Results, "<!--") {
+		t.Errorf("results should not contain template comments: %q", got.Results)
+	}
+}
+
+func TestDone_TemplateUntouchedAborts(t *testing.T) {
+	database := setupAddCommandTest(t)
+	item := createTestItem(t, database, "ts-noop", "Untouched", withStatus(model.StatusInProgress))
+	fakeEditor(t, `s/^<!-- Fill.*//`)
+
+	flagDoneTemplate = resultTemplateAuto
+	t.Cleanup(func() { flagDoneTemplate = "" })
+
+	err := doneCmd.RunE(doneCmd, []string{item.ID})
+	if err == nil || !strings.Contains(err.Error(), "not filled in") {
+		t.Fatalf("expected not-filled-in error, got %v", err)
+	}
+	got, _ := database.GetItem(item.ID)
+	if got.Status != model.StatusInProgress {
+		t.Errorf("item should remain in_progress, got %s", got.Status)
+	}
+}
+
+func TestDone_TemplateUnknownName(t *testing.T) {
+	database := setupAddCommandTest(t)
+	item := createTestItem(t, database, "ts-unk", "Unknown template", withStatus(model.StatusInProgress))
+
+	flagDoneTemplate = "nope"
+	t.Cleanup(func() { flagDoneTemplate = "" })
+
+	err := doneCmd.RunE(doneCmd, []string{item.ID})
+	if err == nil || !strings.Contains(err.Error(), "unknown result template") {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+}
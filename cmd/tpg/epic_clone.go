@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagEpicCloneInto string
+	flagEpicCloneVars []string
+)
+
+var epicCloneCmd = &cobra.Command{
+	Use:   "clone <epic-id>",
+	Short: "Copy an epic's subtree as fresh open items",
+	Long: `Duplicate an epic and all of its descendants as new open items.
+
+Titles, descriptions, types, priorities, labels, and the dependencies between
+copied items are preserved. Statuses, assignments, logs, results, and worktree
+settings are not, and dependencies on items outside the epic are dropped.
+
+The copy is placed next to the original, or under --into. Each --var
+name=value replaces {{.name}} in titles, descriptions, and shared context,
+which makes an epic usable as a recurring checklist.
+
+Examples:
+  tpg epic clone ep-abc123
+  tpg epic clone ep-abc123 --var version=1.4.0
+  tpg epic clone ep-abc123 --into ep-def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rewrite, err := cloneVarReplacer(flagEpicCloneVars)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		epicID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		var parentID *string
+		if flagEpicCloneInto != "" {
+			into, err := resolveItemArg(database, flagEpicCloneInto)
+			if err != nil {
+				return err
+			}
+			parentID = &into
+		}
+
+		result, err := database.CloneEpic(epicID, parentID, rewrite, applyTitlePolicy)
+		if err != nil {
+			return err
+		}
+		database.BackupQuiet()
+
+		fmt.Printf("Cloned %s -> %s (%d items, %d deps)\n", epicID, result.RootID, len(result.Order), result.Deps)
+		for _, origID := range result.Order[1:] {
+			fmt.Printf("  %s -> %s\n", origID, result.IDMap[origID])
+		}
+		return nil
+	},
+}
+
+// cloneVarReplacer builds the text rewrite for --var name=value pairs,
+// replacing {{.name}} with value. It returns nil when there are no pairs.
+func cloneVarReplacer(pairs []string) (func(string) string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	var oldnew []string
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected name=value", pair)
+		}
+		oldnew = append(oldnew, "{{."+name+"}}", value)
+	}
+	return strings.NewReplacer(oldnew...).Replace, nil
+}
+
+func init() {
+	epicCloneCmd.Flags().StringVar(&flagEpicCloneInto, "into", "", "Parent epic for the copy (default: same parent as the original)")
+	epicCloneCmd.Flags().StringArrayVar(&flagEpicCloneVars, "var", nil, "Replace {{.name}} in titles and descriptions, name=value (repeatable)")
+	epicCloneCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Keep multi-line or overlong titles instead of moving them into the description")
+
+	epicCmd.AddCommand(epicCloneCmd)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagEpicRolloverTo    string
+	flagEpicRolloverTitle string
+	flagEpicRolloverNote  string
+	flagEpicRolloverForce bool
+)
+
+var epicRolloverCmd = &cobra.Command{
+	Use:   "rollover <epic-id> --to <epic-id|new>",
+	Short: "Move an epic's unfinished work to another epic and close it",
+	Long: `Carry the unfinished children of an epic over to another epic, then close
+the original. Useful at the end of a sprint or milestone.
+
+Every child that isn't done or canceled moves with its whole subtree, keeping
+its status, logs, and dependencies. Finished children stay with the original,
+which is closed as done with --note as its results (default: "Rolled over N
+unfinished item(s) to <epic>"). Items that were waiting on the original epic
+also come to depend on the target, so they keep waiting for the moved work.
+
+--to new creates the target next to the original, with its description,
+priority, labels, context, and closing instructions; --title names it
+(default: the original's title). The original's closing instructions are not
+run, since its work is continuing elsewhere.
+
+Examples:
+  tpg epic rollover ep-abc123 --to new --title "Sprint 15"
+  tpg epic rollover ep-abc123 --to ep-def456
+  tpg epic rollover ep-abc123 --to new --note "Descoped from 1.4"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagEpicRolloverTo == "" {
+			return fmt.Errorf("--to is required: an epic ID, or \"new\" to create one")
+		}
+		if flagEpicRolloverTitle != "" && flagEpicRolloverTo != "new" {
+			return fmt.Errorf("--title only applies with --to new")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		epicID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		toID := ""
+		if flagEpicRolloverTo != "new" {
+			if toID, err = resolveItemArg(database, flagEpicRolloverTo); err != nil {
+				return err
+			}
+		}
+
+		result, err := database.RolloverEpic(epicID, toID, flagEpicRolloverTitle, flagEpicRolloverNote, flagEpicRolloverForce)
+		if err != nil {
+			return err
+		}
+		database.BackupQuiet()
+
+		if result.Created {
+			fmt.Printf("Created %s\n", result.ToID)
+		}
+		fmt.Printf("Rolled over %d item(s) from %s to %s: %s\n",
+			len(result.Moved), epicID, result.ToID, strings.Join(result.Moved, ", "))
+		if len(result.Dependents) > 0 {
+			fmt.Printf("Now waiting on %s too: %s\n", result.ToID, strings.Join(result.Dependents, ", "))
+		}
+		fmt.Printf("Closed %s\n", epicID)
+		if len(result.Completed) > 1 {
+			announceCompletedEpics(database, result.Completed[1:])
+		}
+		return nil
+	},
+}
+
+func init() {
+	epicRolloverCmd.Flags().StringVar(&flagEpicRolloverTo, "to", "", `Epic to move unfinished work to, or "new" to create one`)
+	epicRolloverCmd.Flags().StringVar(&flagEpicRolloverTitle, "title", "", "Title for the new epic with --to new (default: the original's)")
+	epicRolloverCmd.Flags().StringVar(&flagEpicRolloverNote, "note", "", "Results recorded on the closed epic")
+	epicRolloverCmd.Flags().BoolVar(&flagEpicRolloverForce, "force", false, "Move in-progress items even if their worktree branch changes")
+
+	epicCmd.AddCommand(epicRolloverCmd)
+}
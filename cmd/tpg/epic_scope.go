@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+// formatEpicScope describes an epic's growth since it started, e.g.
+// "+5 tasks since started 3d ago (12 at start, +42%)".
+func formatEpicScope(scope db.EpicScope) string {
+	if !scope.Started() {
+		return "not started"
+	}
+	text := fmt.Sprintf("+%d tasks since started %s (%d at start", scope.Added, formatTimeAgo(scope.StartedAt), scope.Initial)
+	if scope.Initial > 0 {
+		text += fmt.Sprintf(", %+.0f%%", scope.GrowthPct())
+	}
+	return text + ")"
+}
+
+// warnEpicScopeGrowth warns on stderr about each open epic above itemID that
+// has gained more tasks since it started than warnings.epic_scope_growth.
+func warnEpicScopeGrowth(database *db.DB, itemID string) {
+	limit := db.DefaultEpicScopeGrowth
+	if config, err := db.LoadConfig(); err == nil {
+		limit = config.GetEpicScopeGrowth()
+	}
+	for _, epicID := range openAncestorEpics(database, itemID) {
+		scope, err := database.EpicScope(epicID)
+		if err != nil || !scope.Started() || scope.Added <= limit {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Warning: epic %s has grown by %d tasks since it started (%d at start); check the plan isn't running away (warnings.epic_scope_growth: %d)\n",
+			epicID, scope.Added, scope.Initial, limit)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/worktree"
+)
+
+// setupField is the custom field holding an epic's worktree setup commands,
+// one per line. Blank lines and lines starting with # are ignored.
+const setupField = "setup"
+
+// setupLogLines caps the command output kept in a setup log entry.
+const setupLogLines = 20
+
+var flagWorktreeCreate bool
+
+var epicSetupCmd = &cobra.Command{
+	Use:   "setup <epic-id>",
+	Short: "Run an epic's setup commands in its worktree",
+	Long: `Run the setup commands stored in the epic's "setup" field inside its
+worktree, one after another, stopping at the first failure. Each command's
+exit status and the tail of its output are logged to the epic.
+
+Store the commands with 'tpg field set', one per line:
+
+  tpg field set ep-abc123 setup - <<EOF
+  npm install
+  make bootstrap
+  EOF
+
+'tpg epic worktree --create' runs them automatically for a new worktree, and
+'tpg start' offers to run them in a worktree where they haven't succeeded yet.
+
+Examples:
+  tpg epic setup ep-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		epicID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		epic, err := database.GetItem(epicID)
+		if err != nil {
+			return err
+		}
+		if epic.WorktreeBranch == "" {
+			return fmt.Errorf("%s has no worktree (set one up with 'tpg epic worktree %s')", epicID, epicID)
+		}
+		commands, err := epicSetupCommands(database, epicID)
+		if err != nil {
+			return err
+		}
+		if len(commands) == 0 {
+			return fmt.Errorf("%s has no setup commands (set them with 'tpg field set %s %s -')", epicID, epicID, setupField)
+		}
+
+		_, worktrees := detectWorktreeState()
+		path, ok := worktrees[epic.WorktreeBranch]
+		if !ok {
+			return fmt.Errorf("no worktree found for branch %s (create it with 'tpg epic worktree %s --create')", epic.WorktreeBranch, epicID)
+		}
+
+		err = runEpicSetup(database, epic, commands, path)
+		database.BackupQuiet()
+		return err
+	},
+}
+
+// epicSetupCommands returns the setup commands stored on an epic.
+func epicSetupCommands(database *db.DB, epicID string) ([]string, error) {
+	fields, err := database.GetFields(epicID)
+	if err != nil {
+		return nil, err
+	}
+	return parseSetupCommands(fields[setupField]), nil
+}
+
+// parseSetupCommands splits a setup field into commands.
+func parseSetupCommands(value string) []string {
+	var commands []string
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		commands = append(commands, line)
+	}
+	return commands
+}
+
+// runEpicSetup runs setup commands in dir, logging each one to the epic.
+// Once all succeed the worktree is marked as set up.
+func runEpicSetup(database *db.DB, epic *model.Item, commands []string, dir string) error {
+	for i, command := range commands {
+		fmt.Printf("[%d/%d] %s\n", i+1, len(commands), command)
+
+		var output bytes.Buffer
+		c := shellCommand(command)
+		c.Dir = dir
+		c.Stdout = io.MultiWriter(os.Stdout, &output)
+		c.Stderr = io.MultiWriter(os.Stderr, &output)
+		runErr := c.Run()
+
+		msg := fmt.Sprintf("Setup: %s (exit %d)", command, exitCode(runErr))
+		if tail := tailLines(output.String(), setupLogLines); tail != "" {
+			msg += "\n" + tail
+		}
+		_ = database.AddLog(epic.ID, msg)
+
+		if runErr != nil {
+			return fmt.Errorf("setup command %q failed: %w", command, runErr)
+		}
+	}
+
+	if ctx, err := worktree.DetectContext(dir); err == nil && ctx.InWorktree {
+		_ = worktree.MarkSetupDone(ctx.GitDir)
+	}
+	fmt.Printf("Setup complete for %s\n", epic.ID)
+	return nil
+}
+
+// offerEpicSetup asks to run the setup commands of the worktree epic when
+// the current directory is a worktree that hasn't been set up yet.
+func offerEpicSetup(database *db.DB, epic *model.Item) error {
+	ctx, err := worktree.DetectContext("")
+	if err != nil || !ctx.InWorktree || worktree.SetupDone(ctx.GitDir) {
+		return nil
+	}
+	commands, err := epicSetupCommands(database, epic.ID)
+	if err != nil || len(commands) == 0 {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "\nThis worktree hasn't been set up for %s:\n", epic.ID)
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", command)
+	}
+	if !confirm("Run the setup commands now?") {
+		fmt.Fprintf(os.Stderr, "Run them later with: tpg epic setup %s\n", epic.ID)
+		return nil
+	}
+	return runEpicSetup(database, epic, commands, ctx.WorktreeRoot)
+}
+
+// shellCommand runs command through the platform shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return execCommand("cmd", "/C", command)
+	}
+	return execCommand("sh", "-c", command)
+}
+
+// exitCode returns the exit status of a finished command: 0 on success, the
+// process exit code when it ran, and -1 when it couldn't be started.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// tailLines returns the last n lines of s, ignoring trailing newlines.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	epicWorktreeCmd.Flags().BoolVar(&flagWorktreeCreate, "create", false, "Create the git worktree and run the epic's setup commands")
+
+	epicCmd.AddCommand(epicSetupCmd)
+}
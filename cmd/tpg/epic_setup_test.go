@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestParseSetupCommands(t *testing.T) {
+	got := parseSetupCommands("npm install\n\n  # seed data\n  make bootstrap  \n")
+	want := []string{"npm install", "make bootstrap"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSetupCommands = %q, want %q", got, want)
+	}
+	if got := parseSetupCommands(""); len(got) != 0 {
+		t.Errorf("expected no commands for an empty field, got %q", got)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	if got := tailLines("a\nb\nc\n", 2); got != "b\nc" {
+		t.Errorf("tailLines = %q", got)
+	}
+	if got := tailLines("a\n", 5); got != "a" {
+		t.Errorf("tailLines = %q", got)
+	}
+}
+
+func TestRunEpicSetup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+	database := setupTestDB(t)
+	epic := createTestItem(t, database, "ep-setup", "Setup", withType(model.ItemTypeEpic))
+
+	err := runEpicSetup(database, epic, []string{"echo ready", "exit 3", "echo never"}, t.TempDir())
+	if err == nil {
+		t.Fatal("expected the failing command to stop setup")
+	}
+
+	logs, err := database.GetLogs(epic.ID)
+	if err != nil {
+		t.Fatalf("GetLogs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 setup logs, got %d", len(logs))
+	}
+	if logs[0].Message != "Setup: echo ready (exit 0)\nready" {
+		t.Errorf("unexpected first log %q", logs[0].Message)
+	}
+	if !strings.HasPrefix(logs[1].Message, "Setup: exit 3 (exit 3)") {
+		t.Errorf("unexpected second log %q", logs[1].Message)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var flagEpicSnapshotName string
+
+var epicSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <epic-id>",
+	Short: "Save the epic's subtree so it can be rolled back",
+	Long: `Save a snapshot of an epic and all of its descendants: fields, statuses,
+dependencies, and labels. Use 'tpg epic rollback' to restore it later without
+restoring the whole database.
+
+Examples:
+  tpg epic snapshot ep-abc123 --name "before refactor experiment"
+  tpg epic snapshots ep-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
+		snapshot, err := database.SnapshotEpic(args[0], flagEpicSnapshotName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot %d of %s saved (%d items)\n", snapshot.ID, snapshot.EpicID, snapshot.ItemCount)
+		fmt.Printf("Roll back with: tpg epic rollback %s %d\n", snapshot.EpicID, snapshot.ID)
+		return nil
+	},
+}
+
+var epicSnapshotsCmd = &cobra.Command{
+	Use:   "snapshots <epic-id>",
+	Short: "List snapshots of an epic",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
+		snapshots, err := database.ListEpicSnapshots(args[0])
+		if err != nil {
+			return err
+		}
+		if len(snapshots) == 0 {
+			fmt.Printf("No snapshots for %s\n", args[0])
+			return nil
+		}
+		fmt.Printf("%-6s %-6s %-14s %s\n", "ID", "ITEMS", "CREATED", "NAME")
+		for _, s := range snapshots {
+			fmt.Printf("%-6d %-6d %-14s %s\n", s.ID, s.ItemCount, formatTimeAgo(s.CreatedAt), s.Name)
+		}
+		return nil
+	},
+}
+
+var epicRollbackCmd = &cobra.Command{
+	Use:   "rollback <epic-id> <snapshot-id>",
+	Short: "Restore an epic's subtree from a snapshot",
+	Long: `Restore an epic and its descendants to a snapshot taken with
+'tpg epic snapshot'.
+
+  - Items are reset to their saved fields and statuses (deleted ones are re-created)
+  - Dependencies (with their notes) and labels of the saved items are restored
+  - Items created after the snapshot are deleted
+  - Older items moved into the epic since are moved back out to the top level
+
+Logs are kept, and replaced descriptions stay in 'tpg desc history'. The current state is snapshotted first, so a rollback can
+itself be undone.
+
+Examples:
+  tpg epic rollback ep-abc123 3`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		epicID := args[0]
+		snapshotID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot id: %s (use 'tpg epic snapshots %s')", args[1], epicID)
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if epicID, err = resolveItemArg(database, epicID); err != nil {
+			return err
+		}
+		if _, err := database.GetItem(epicID); err == nil {
+			safety, err := database.SnapshotEpic(epicID, fmt.Sprintf("before rollback to %d", snapshotID))
+			if err != nil {
+				return fmt.Errorf("failed to snapshot current state: %w", err)
+			}
+			fmt.Printf("Current state saved as snapshot %d\n", safety.ID)
+		}
+
+		result, err := database.RollbackEpic(epicID, snapshotID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %s to snapshot %d: %d item(s) restored", epicID, snapshotID, len(result.Restored))
+		if len(result.Removed) > 0 {
+			fmt.Printf(", %d removed", len(result.Removed))
+		}
+		if len(result.Detached) > 0 {
+			fmt.Printf(", %d moved out", len(result.Detached))
+		}
+		fmt.Println()
+		for _, id := range result.Removed {
+			fmt.Printf("  - %s\n", id)
+		}
+		for _, id := range result.Detached {
+			fmt.Printf("  %s moved to the top level\n", id)
+		}
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+func init() {
+	epicSnapshotCmd.Flags().StringVar(&flagEpicSnapshotName, "name", "", "Optional name for the snapshot")
+
+	epicCmd.AddCommand(epicSnapshotCmd)
+	epicCmd.AddCommand(epicSnapshotsCmd)
+	epicCmd.AddCommand(epicRollbackCmd)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var flagExplainJSON bool
+
+// ExplainJSON is the JSON output of 'tpg explain'.
+type ExplainJSON struct {
+	ID      string              `json:"id"`
+	Title   string              `json:"title"`
+	Status  string              `json:"status"`
+	Ready   bool                `json:"ready"`
+	Reasons []ExplainReasonJSON `json:"reasons"`
+}
+
+// ExplainReasonJSON is one reason an item is not ready.
+type ExplainReasonJSON struct {
+	Kind          string           `json:"kind"`
+	Message       string           `json:"message"`
+	Dep           *ExplainDepJSON  `json:"dep,omitempty"`
+	InheritedFrom string           `json:"inherited_from,omitempty"`
+	Blockers      []ExplainDepJSON `json:"dep_blockers,omitempty"`
+	Agent         string           `json:"agent,omitempty"`
+	AgentName     string           `json:"agent_name,omitempty"`
+}
+
+// ExplainDepJSON identifies a dependency in explain output.
+type ExplainDepJSON struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <id>",
+	Short: "Explain why a task is not ready",
+	Long: `Explain why a task does not show up in 'tpg ready'.
+
+Every reason is listed:
+  - status is not open (in progress, blocked, done, canceled)
+  - the item is an epic (only its tasks are ever ready)
+  - a dependency is not done, with what that dependency is itself waiting on
+  - a parent epic has a dependency that is not done
+  - another agent has claimed it
+
+Examples:
+  tpg explain ts-a1b2c3
+  tpg explain ts-a1b2c3 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
+		r, err := database.ExplainReadiness(args[0], db.GetAgentContext())
+		if err != nil {
+			return err
+		}
+		names := database.AgentNames()
+
+		if flagExplainJSON {
+			return printExplainJSON(r, names)
+		}
+		printExplain(r, names)
+		return nil
+	},
+}
+
+func printExplain(r *db.Readiness, names map[string]string) {
+	fmt.Printf("%s %s [%s]\n", r.Item.ID, r.Item.Title, r.Item.Status)
+	if r.Ready {
+		fmt.Println("Ready: yes — it shows up in 'tpg ready'")
+		return
+	}
+	fmt.Println("Ready: no")
+	for _, reason := range r.Reasons {
+		msg := reason.Message
+		if reason.Kind == db.NotReadyClaimed {
+			msg = "claimed by agent " + agentDisplayName(names, reason.AgentID)
+		}
+		fmt.Printf("  - %s\n", msg)
+		for _, b := range reason.DepBlockers {
+			fmt.Printf("      └ which waits on %s %s (%s)\n", b.ID, b.Title, b.Status)
+		}
+	}
+}
+
+func printExplainJSON(r *db.Readiness, names map[string]string) error {
+	out := ExplainJSON{
+		ID:      r.Item.ID,
+		Title:   r.Item.Title,
+		Status:  string(r.Item.Status),
+		Ready:   r.Ready,
+		Reasons: []ExplainReasonJSON{},
+	}
+	for _, reason := range r.Reasons {
+		rj := ExplainReasonJSON{Kind: reason.Kind, Message: reason.Message}
+		if reason.AgentID != "" {
+			rj.Agent = reason.AgentID
+			if name := agentDisplayName(names, reason.AgentID); name != reason.AgentID {
+				rj.AgentName = name
+			}
+		}
+		if reason.Dep != nil {
+			rj.Dep = &ExplainDepJSON{ID: reason.Dep.ID, Title: reason.Dep.Title, Status: reason.Dep.Status}
+			rj.InheritedFrom = reason.Dep.InheritedFrom
+		}
+		for _, b := range reason.DepBlockers {
+			rj.Blockers = append(rj.Blockers, ExplainDepJSON{ID: b.ID, Title: b.Title, Status: b.Status})
+		}
+		out.Reasons = append(out.Reasons, rj)
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func init() {
+	explainCmd.Flags().BoolVar(&flagExplainJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(explainCmd)
+}
+
+// unmetDepsError explains why done refused an item: each unmet dependency
+// with its status, the chain down to any work in progress, and the command
+// that overrides the check.
+func unmetDepsError(database *db.DB, id, results string) error {
+	unmet, err := database.UnmetDeps(id)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "cannot mark %s done: %d unmet dependenc", id, len(unmet))
+	if len(unmet) == 1 {
+		b.WriteString("y\n")
+	} else {
+		b.WriteString("ies\n")
+	}
+	for _, u := range unmet {
+		fmt.Fprintf(&b, "  - %s %s (%s)\n", u.Dep.ID, u.Dep.Title, u.Dep.Status)
+		if len(u.Chain) > 0 {
+			var steps []string
+			for _, c := range u.Chain {
+				steps = append(steps, fmt.Sprintf("%s %s (%s)", c.ID, c.Title, c.Status))
+			}
+			fmt.Fprintf(&b, "      └ waits on %s\n", strings.Join(steps, " → "))
+		}
+	}
+	override := "-"
+	if !strings.Contains(results, "\n") {
+		override = strconv.Quote(results)
+	}
+	fmt.Fprintf(&b, "To complete it anyway: tpg done %s --override %s", id, override)
+	return errors.New(b.String())
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/taxilian/tpg/internal/db"
@@ -17,8 +21,22 @@ var (
 	flagExportJSON   bool
 	flagExportJSONL  bool
 	flagExportAll    bool
+	flagExportFormat string
+	flagExportFields []string
+	flagExportEpic   string
 )
 
+// Export formats accepted by --format.
+const (
+	exportFormatMarkdown = "markdown"
+	exportFormatJSON     = "json"
+	exportFormatJSONL    = "jsonl"
+	exportFormatCSV      = "csv"
+)
+
+// defaultCSVFields are the columns written by --format csv without --fields.
+var defaultCSVFields = []string{"id", "title", "status", "priority", "labels", "created_at", "done_at"}
+
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export tasks to a single file for LLM consumption",
@@ -27,9 +45,18 @@ var exportCmd = &cobra.Command{
 By default, outputs markdown format optimized for LLM consumption.
 Use --json for JSON output or --jsonl for JSON Lines format (one object per line).
 
+--format csv writes one row per item for spreadsheets. Pick the columns with
+--fields (default: id,title,status,priority,labels,created_at,done_at).
+Available fields:
+  id, type, project, title, status, priority, parent_id, labels, deps,
+  description, results, template_id, created_at, updated_at, closed_at, done_at,
+  field.<name> (a custom field, see 'tpg field')
+Multi-valued fields (labels, deps) are joined with ";". Times are RFC 3339.
+
 Supports the same filters as 'tpg list':
   --status, --label, --parent, --type, --all, --project,
-  --has-blockers, --no-blockers, --blocking, --blocked-by
+  --has-blockers, --no-blockers, --blocking, --blocked-by, --field
+Use --epic to export an epic together with all of its descendants.
 
 By default, excludes done and canceled items. Use --all to include everything.
 
@@ -38,6 +65,8 @@ Examples:
   tpg export -o tasks.md              # Export to file
   tpg export --json                   # Export as JSON
   tpg export --jsonl                  # Export as JSON Lines (one object per line)
+  tpg export --format csv -o tasks.csv --all
+  tpg export --format csv --fields id,title,status --epic ep-abc123
   tpg export --all                    # Include done/canceled
   tpg export --status open            # Only open tasks
   tpg export -l bug                   # Only tasks with 'bug' label
@@ -47,6 +76,25 @@ Examples:
 		if flagExportJSON && flagExportJSONL {
 			return fmt.Errorf("--json and --jsonl are mutually exclusive")
 		}
+		format, err := exportFormat()
+		if err != nil {
+			return err
+		}
+		fieldFilters, err := parseFieldFilters(flagFilterFields)
+		if err != nil {
+			return err
+		}
+		fields := flagExportFields
+		if len(fields) == 0 {
+			fields = defaultCSVFields
+		}
+		if format == exportFormatCSV {
+			if err := validateCSVFields(fields); err != nil {
+				return err
+			}
+		} else if len(flagExportFields) > 0 {
+			return fmt.Errorf("--fields only applies to --format csv")
+		}
 
 		database, err := openDB()
 		if err != nil {
@@ -79,6 +127,7 @@ Examples:
 			HasBlockers: flagHasBlockers,
 			NoBlockers:  flagNoBlockers,
 			Labels:      flagFilterLabels,
+			Fields:      fieldFilters,
 		}
 
 		items, err := database.ListItemsFiltered(filter)
@@ -86,6 +135,13 @@ Examples:
 			return err
 		}
 
+		if flagExportEpic != "" {
+			items, err = filterToEpic(database, items, flagExportEpic)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Filter out done/canceled items by default (unless --all or --status is set)
 		if !flagExportAll && !statusExplicitlySet {
 			filtered := make([]model.Item, 0, len(items))
@@ -97,42 +153,9 @@ Examples:
 			items = filtered
 		}
 
-		// Gather full details for each item
-		exportData := make([]ExportData, 0, len(items))
-		for i := range items {
-			item := &items[i]
-
-			// Get labels
-			labels, err := database.GetItemLabels(item.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get labels for %s: %w", item.ID, err)
-			}
-
-			// Get logs
-			logs, err := database.GetLogs(item.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get logs for %s: %w", item.ID, err)
-			}
-
-			// Get dependencies
-			deps, err := database.GetDeps(item.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get deps for %s: %w", item.ID, err)
-			}
-
-			// Get dependency statuses for richer output
-			depStatuses, err := database.GetDepStatuses(item.ID)
-			if err != nil {
-				return fmt.Errorf("failed to get dep statuses for %s: %w", item.ID, err)
-			}
-
-			exportData = append(exportData, ExportData{
-				Item:         item,
-				Labels:       labels,
-				Logs:         logs,
-				Dependencies: deps,
-				DepStatuses:  depStatuses,
-			})
+		exportData, err := gatherExportData(database, items)
+		if err != nil {
+			return err
 		}
 
 		// Determine output destination
@@ -147,16 +170,111 @@ Examples:
 		}
 
 		// Generate output
-		if flagExportJSON {
+		switch format {
+		case exportFormatJSON:
 			return exportJSON(output, exportData)
-		}
-		if flagExportJSONL {
+		case exportFormatJSONL:
 			return exportJSONL(output, exportData)
+		case exportFormatCSV:
+			return exportCSV(output, exportData, fields)
 		}
 		return exportMarkdown(output, exportData)
 	},
 }
 
+// exportFormat resolves --format together with the --json and --jsonl shorthands.
+func exportFormat() (string, error) {
+	format := strings.ToLower(flagExportFormat)
+	shorthand := ""
+	if flagExportJSON {
+		shorthand = exportFormatJSON
+	} else if flagExportJSONL {
+		shorthand = exportFormatJSONL
+	}
+	switch {
+	case format == "":
+		format = shorthand
+		if format == "" {
+			format = exportFormatMarkdown
+		}
+	case shorthand != "" && shorthand != format:
+		return "", fmt.Errorf("--%s conflicts with --format %s", shorthand, format)
+	}
+	switch format {
+	case exportFormatMarkdown, exportFormatJSON, exportFormatJSONL, exportFormatCSV:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (valid: markdown, json, jsonl, csv)", flagExportFormat)
+}
+
+// filterToEpic keeps only the epic and its descendants.
+func filterToEpic(database *db.DB, items []model.Item, epicID string) ([]model.Item, error) {
+	if _, err := database.GetItem(epicID); err != nil {
+		return nil, err
+	}
+	descendants, err := database.GetDescendants(epicID)
+	if err != nil {
+		return nil, err
+	}
+	keep := map[string]bool{epicID: true}
+	for _, d := range descendants {
+		keep[d.ID] = true
+	}
+	filtered := make([]model.Item, 0, len(items))
+	for _, item := range items {
+		if keep[item.ID] {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// gatherExportData loads the labels, fields, logs, and dependencies of each
+// item for export.
+func gatherExportData(database *db.DB, items []model.Item) ([]ExportData, error) {
+	if err := database.PopulateItemFields(items); err != nil {
+		return nil, err
+	}
+
+	exportData := make([]ExportData, 0, len(items))
+	for i := range items {
+		item := &items[i]
+
+		// Get labels
+		labels, err := database.GetItemLabels(item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get labels for %s: %w", item.ID, err)
+		}
+
+		// Get logs
+		logs, err := database.GetLogs(item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get logs for %s: %w", item.ID, err)
+		}
+
+		// Get dependencies
+		deps, err := database.GetDeps(item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deps for %s: %w", item.ID, err)
+		}
+
+		// Get dependency statuses for richer output
+		depStatuses, err := database.GetDepStatuses(item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dep statuses for %s: %w", item.ID, err)
+		}
+
+		exportData = append(exportData, ExportData{
+			Item:         item,
+			Labels:       labels,
+			Logs:         logs,
+			Dependencies: deps,
+			DepStatuses:  depStatuses,
+		})
+	}
+	return exportData, nil
+}
+
 // ExportData represents the data structure for a single exported task.
 type ExportData struct {
 	Item         *model.Item
@@ -164,6 +282,8 @@ type ExportData struct {
 	Logs         []model.Log
 	Dependencies []string
 	DepStatuses  []db.DepStatus
+	Dependents   []string // only filled in by 'tpg clean --save', so the deps can be restored
+	Learnings    []string // likewise, the learnings linked to the item
 }
 
 // ExportDataJSON is the JSON representation of export data
@@ -178,13 +298,17 @@ type ExportDataJSON struct {
 	Description  string            `json:"description,omitempty"`
 	Results      string            `json:"results,omitempty"`
 	Labels       []string          `json:"labels,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
 	TemplateID   string            `json:"template_id,omitempty"`
 	StepIndex    *int              `json:"step_index,omitempty"`
 	TemplateVars map[string]string `json:"template_vars,omitempty"`
 	Dependencies []DepStatusJSON   `json:"dependencies,omitempty"`
+	Dependents   []string          `json:"dependents,omitempty"`
+	Learnings    []string          `json:"learnings,omitempty"`
 	Logs         []ExportLogJSON   `json:"logs,omitempty"`
 	CreatedAt    string            `json:"created_at"`
 	UpdatedAt    string            `json:"updated_at"`
+	ClosedAt     string            `json:"closed_at,omitempty"`
 }
 
 // DepStatusJSON is the JSON representation of a dependency status
@@ -197,6 +321,8 @@ type DepStatusJSON struct {
 // ExportLogJSON is the JSON representation of a log entry for export
 type ExportLogJSON struct {
 	Message   string `json:"message"`
+	Category  string `json:"category,omitempty"`
+	Progress  *int   `json:"progress,omitempty"`
 	CreatedAt string `json:"created_at"`
 }
 
@@ -236,10 +362,17 @@ func convertToJSONItem(d ExportData) ExportDataJSON {
 	for _, log := range d.Logs {
 		logs = append(logs, ExportLogJSON{
 			Message:   log.Message,
+			Category:  string(log.Category),
+			Progress:  log.Progress,
 			CreatedAt: log.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
 	}
 
+	closedAt := ""
+	if item.ClosedAt != nil {
+		closedAt = item.ClosedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
 	return ExportDataJSON{
 		ID:           item.ID,
 		Type:         string(item.Type),
@@ -251,13 +384,17 @@ func convertToJSONItem(d ExportData) ExportDataJSON {
 		Description:  item.Description,
 		Results:      item.Results,
 		Labels:       labelNames,
+		Fields:       item.Fields,
 		TemplateID:   item.TemplateID,
 		StepIndex:    item.StepIndex,
 		TemplateVars: item.TemplateVars,
 		Dependencies: deps,
+		Dependents:   d.Dependents,
+		Learnings:    d.Learnings,
 		Logs:         logs,
 		CreatedAt:    item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:    item.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ClosedAt:     closedAt,
 	}
 }
 
@@ -272,6 +409,95 @@ func exportJSONL(w io.Writer, data []ExportData) error {
 	return nil
 }
 
+// csvFields maps each --fields name to the value it writes for an item.
+var csvFields = map[string]func(d ExportData) string{
+	"id":          func(d ExportData) string { return d.Item.ID },
+	"type":        func(d ExportData) string { return string(d.Item.Type) },
+	"project":     func(d ExportData) string { return d.Item.Project },
+	"title":       func(d ExportData) string { return d.Item.Title },
+	"status":      func(d ExportData) string { return string(d.Item.Status) },
+	"priority":    func(d ExportData) string { return strconv.Itoa(d.Item.Priority) },
+	"description": func(d ExportData) string { return d.Item.Description },
+	"results":     func(d ExportData) string { return d.Item.Results },
+	"template_id": func(d ExportData) string { return d.Item.TemplateID },
+	"deps":        func(d ExportData) string { return strings.Join(d.Dependencies, ";") },
+	"created_at":  func(d ExportData) string { return csvTime(&d.Item.CreatedAt) },
+	"updated_at":  func(d ExportData) string { return csvTime(&d.Item.UpdatedAt) },
+	"closed_at":   func(d ExportData) string { return csvTime(d.Item.ClosedAt) },
+	"parent_id": func(d ExportData) string {
+		if d.Item.ParentID == nil {
+			return ""
+		}
+		return *d.Item.ParentID
+	},
+	"labels": func(d ExportData) string {
+		names := make([]string, 0, len(d.Labels))
+		for _, l := range d.Labels {
+			names = append(names, l.Name)
+		}
+		return strings.Join(names, ";")
+	},
+	"done_at": func(d ExportData) string {
+		if d.Item.Status != model.StatusDone {
+			return ""
+		}
+		return csvTime(d.Item.ClosedAt)
+	},
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// csvFieldPrefix selects a custom field as a CSV column, e.g. field.reviewer.
+const csvFieldPrefix = "field."
+
+func validateCSVFields(fields []string) error {
+	for _, f := range fields {
+		if key, ok := strings.CutPrefix(f, csvFieldPrefix); ok && key != "" {
+			continue
+		}
+		if _, ok := csvFields[f]; !ok {
+			names := make([]string, 0, len(csvFields))
+			for name := range csvFields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown CSV field %q (valid: %s)", f, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// exportCSV writes a header row of fields followed by one row per item.
+func exportCSV(w io.Writer, data []ExportData, fields []string) error {
+	if err := validateCSVFields(fields); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(fields); err != nil {
+		return err
+	}
+	row := make([]string, len(fields))
+	for _, d := range data {
+		for i, f := range fields {
+			if key, ok := strings.CutPrefix(f, csvFieldPrefix); ok {
+				row[i] = d.Item.Fields[key]
+				continue
+			}
+			row[i] = csvFields[f](d)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 func exportMarkdown(w io.Writer, data []ExportData) error {
 	if len(data) == 0 {
 		fmt.Fprintln(w, "# Task Export")
@@ -369,6 +595,9 @@ func init() {
 	exportCmd.Flags().StringVarP(&flagExportOutput, "output", "o", "", "Output file path (default: stdout)")
 	exportCmd.Flags().BoolVar(&flagExportJSON, "json", false, "Output as JSON instead of markdown")
 	exportCmd.Flags().BoolVar(&flagExportJSONL, "jsonl", false, "Output as JSON Lines (one object per line)")
+	exportCmd.Flags().StringVar(&flagExportFormat, "format", "", "Output format: markdown, json, jsonl, csv (default markdown)")
+	exportCmd.Flags().StringSliceVar(&flagExportFields, "fields", nil, "Comma-separated columns for --format csv")
+	exportCmd.Flags().StringVar(&flagExportEpic, "epic", "", "Only export this epic and all of its descendants")
 	exportCmd.Flags().BoolVarP(&flagExportAll, "all", "a", false, "Include done and canceled tasks")
 	exportCmd.Flags().StringVar(&flagStatus, "status", "", "Filter by status (open, in_progress, blocked, done, canceled)")
 	exportCmd.Flags().StringVar(&flagListParent, "parent", "", "Filter by parent epic ID")
@@ -378,6 +607,7 @@ func init() {
 	exportCmd.Flags().BoolVar(&flagHasBlockers, "has-blockers", false, "Show only items with unresolved blockers")
 	exportCmd.Flags().BoolVar(&flagNoBlockers, "no-blockers", false, "Show only items with no blockers")
 	exportCmd.Flags().StringArrayVarP(&flagFilterLabels, "label", "l", nil, "Filter by label (can be repeated, AND logic)")
+	exportCmd.Flags().StringArrayVar(&flagFilterFields, "field", nil, "Filter by custom field, key=value (can be repeated, AND logic)")
 
 	rootCmd.AddCommand(exportCmd)
 }
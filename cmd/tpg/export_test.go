@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"io"
 	"os"
@@ -1770,3 +1771,117 @@ func TestExport_JSONAndJSONLMutuallyExclusive(t *testing.T) {
 		t.Errorf("Error should mention mutually exclusive, got: %v", err)
 	}
 }
+
+// =============================================================================
+// CSV Export Tests
+// =============================================================================
+
+func TestExportCSV_DefaultFields(t *testing.T) {
+	// Arrange
+	database := setupTestDB(t)
+	task := createTestItem(t, database, "ts-csv1", `Fix "quoted", comma title`, withPriority(1))
+	closed := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	task.Status = model.StatusDone
+	task.ClosedAt = &closed
+	exportData := []ExportData{{
+		Item:   task,
+		Labels: []model.Label{{Name: "bug"}, {Name: "urgent"}},
+	}}
+
+	// Act
+	var buf bytes.Buffer
+	if err := exportCSV(&buf, exportData, defaultCSVFields); err != nil {
+		t.Fatalf("exportCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	// Assert
+	if len(rows) != 2 {
+		t.Fatalf("expected header + 1 row, got %d rows", len(rows))
+	}
+	if strings.Join(rows[0], ",") != "id,title,status,priority,labels,created_at,done_at" {
+		t.Errorf("unexpected header: %v", rows[0])
+	}
+	want := []string{"ts-csv1", `Fix "quoted", comma title`, "done", "1", "bug;urgent"}
+	for i, v := range want {
+		if rows[1][i] != v {
+			t.Errorf("column %s = %q, want %q", rows[0][i], rows[1][i], v)
+		}
+	}
+	if rows[1][6] != "2025-03-04T05:06:07Z" {
+		t.Errorf("done_at = %q", rows[1][6])
+	}
+}
+
+func TestExportCSV_UnknownField(t *testing.T) {
+	err := exportCSV(io.Discard, nil, []string{"id", "nope"})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestExport_FormatFlag(t *testing.T) {
+	defer func() {
+		flagExportFormat = ""
+		flagExportJSON = false
+	}()
+
+	flagExportFormat = "CSV"
+	if f, err := exportFormat(); err != nil || f != exportFormatCSV {
+		t.Errorf("exportFormat() = %q, %v; want csv", f, err)
+	}
+	flagExportJSON = true
+	if _, err := exportFormat(); err == nil {
+		t.Error("expected --json to conflict with --format csv")
+	}
+	flagExportFormat = "xml"
+	flagExportJSON = false
+	if _, err := exportFormat(); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestExport_FilterToEpic(t *testing.T) {
+	// Arrange
+	database := setupTestDB(t)
+	epic := createTestItem(t, database, "ep-scope1", "Scope Epic", withType(model.ItemTypeEpic))
+	sub := createTestItem(t, database, "ep-scope2", "Sub Epic", withType(model.ItemTypeEpic), withParent(epic.ID))
+	createTestItem(t, database, "ts-scope1", "Direct child", withParent(epic.ID))
+	createTestItem(t, database, "ts-scope2", "Grandchild", withParent(sub.ID))
+	createTestItem(t, database, "ts-scope3", "Outside")
+
+	items, err := database.ListItemsFiltered(db.ListFilter{})
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+
+	// Act
+	scoped, err := filterToEpic(database, items, epic.ID)
+	if err != nil {
+		t.Fatalf("filterToEpic failed: %v", err)
+	}
+
+	// Assert
+	got := map[string]bool{}
+	for _, item := range scoped {
+		got[item.ID] = true
+	}
+	if len(got) != 4 || got["ts-scope3"] || !got["ep-scope1"] || !got["ts-scope2"] {
+		t.Errorf("unexpected scope: %v", got)
+	}
+}
+
+func TestExportCSV_CustomFieldColumn(t *testing.T) {
+	task := &model.Item{ID: "ts-cf1", Fields: map[string]string{"reviewer": "alice"}}
+
+	var buf bytes.Buffer
+	if err := exportCSV(&buf, []ExportData{{Item: task}}, []string{"id", "field.reviewer", "field.missing"}); err != nil {
+		t.Fatalf("exportCSV failed: %v", err)
+	}
+	if got, want := buf.String(), "id,field.reviewer,field.missing\nts-cf1,alice,\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var fieldCmd = &cobra.Command{
+	Use:   "field",
+	Short: "Manage custom fields on tasks",
+	Long: `Manage custom key/value fields on tasks and epics.
+
+Fields hold structured metadata (reviewer, estimate, ticket URL, ...) that
+would otherwise be encoded in labels or description headers. Values may span
+multiple lines. Fields are shown by 'tpg show', included in JSON/YAML output
+and exports, and can be filtered with 'tpg list --field key=value'.
+
+Examples:
+  tpg field set ts-a1b2c3 reviewer alice
+  tpg field set ts-a1b2c3 notes - < notes.txt
+  tpg field get ts-a1b2c3 reviewer
+  tpg field list ts-a1b2c3
+  tpg field rm ts-a1b2c3 reviewer
+  tpg list --field reviewer=alice`,
+}
+
+var fieldSetCmd = &cobra.Command{
+	Use:   "set <id> <key> <value>",
+	Short: "Set a custom field (use '-' to read the value from stdin)",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, key, value := args[0], args[1], args[2]
+		if value == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read from stdin: %w", err)
+			}
+			value = strings.TrimRight(string(data), "\n")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if id, err = resolveItemArg(database, id); err != nil {
+			return err
+		}
+		if err := database.SetField(id, key, value); err != nil {
+			return err
+		}
+		fmt.Printf("Set %s on %s\n", key, id)
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+var fieldGetCmd = &cobra.Command{
+	Use:   "get <id> <key>",
+	Short: "Print the value of a custom field",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+		if _, err := database.GetItem(args[0]); err != nil {
+			return err
+		}
+		fields, err := database.GetFields(args[0])
+		if err != nil {
+			return err
+		}
+		value, ok := fields[args[1]]
+		if !ok {
+			return fmt.Errorf("%s has no field %q", args[0], args[1])
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var fieldListCmd = &cobra.Command{
+	Use:     "list <id>",
+	Aliases: []string{"ls"},
+	Short:   "List the custom fields of a task",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+		if _, err := database.GetItem(args[0]); err != nil {
+			return err
+		}
+		fields, err := database.GetFields(args[0])
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			fmt.Printf("%s has no fields\n", args[0])
+			return nil
+		}
+		printFields(fields, "")
+		return nil
+	},
+}
+
+var fieldRmCmd = &cobra.Command{
+	Use:     "rm <id> <key>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a custom field",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+		if err := database.RemoveField(args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s from %s\n", args[1], args[0])
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// parseFieldFilters turns repeated --field key=value flags into a filter map.
+func parseFieldFilters(specs []string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	filters := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		key, value, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --field %q: expected key=value", spec)
+		}
+		if err := db.ValidateFieldKey(key); err != nil {
+			return nil, err
+		}
+		filters[key] = value
+	}
+	return filters, nil
+}
+
+// sortedFieldKeys returns the keys of fields in alphabetical order.
+func sortedFieldKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printFields prints fields as "key: value", indenting multi-line values.
+func printFields(fields map[string]string, indent string) {
+	for _, key := range sortedFieldKeys(fields) {
+		value := fields[key]
+		if strings.Contains(value, "\n") {
+			fmt.Printf("%s%s:\n%s\n", indent, key, indentLines(value, indent+"  "))
+		} else {
+			fmt.Printf("%s%s: %s\n", indent, key, value)
+		}
+	}
+}
+
+func init() {
+	fieldCmd.AddCommand(fieldSetCmd)
+	fieldCmd.AddCommand(fieldGetCmd)
+	fieldCmd.AddCommand(fieldListCmd)
+	fieldCmd.AddCommand(fieldRmCmd)
+	rootCmd.AddCommand(fieldCmd)
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestParseFieldFilters(t *testing.T) {
+	filters, err := parseFieldFilters([]string{"reviewer=alice", "ticket=ABC=1", "empty="})
+	if err != nil {
+		t.Fatalf("parseFieldFilters failed: %v", err)
+	}
+	if filters["reviewer"] != "alice" || filters["ticket"] != "ABC=1" || filters["empty"] != "" || len(filters) != 3 {
+		t.Errorf("unexpected filters: %v", filters)
+	}
+
+	for _, bad := range []string{"reviewer", "=alice", "a b=c"} {
+		if _, err := parseFieldFilters([]string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
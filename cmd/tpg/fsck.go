@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+// Exit statuses of 'tpg fsck'.
+const (
+	fsckExitCorrupt = 1 // integrity or foreign key problems
+	fsckExitBackup  = 2 // database fine, but backups missing or behind
+)
+
+// fsckBackupSlack is the least lag behind the newest backup that fsck
+// reports, so writes made while a command closes the database don't count.
+const fsckBackupSlack = time.Minute
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check the database file for corruption",
+	Long: `Check the database file itself, without changing anything:
+
+  1. PRAGMA integrity_check (damaged pages, broken indexes, FTS tables)
+  2. PRAGMA foreign_key_check (rows pointing at missing rows)
+  3. Backup freshness (the newest backup against the last database write)
+
+The database is opened without running migrations, so fsck works on files
+other commands fail to open. Use 'tpg doctor' for problems in the data
+itself, such as circular dependencies.
+
+Exit status is 0 when everything passes, 1 when the database has integrity
+or foreign key problems, and 2 when only the backups are missing or more
+than backup.interval behind.
+
+Examples:
+  tpg fsck
+  tpg fsck || tpg backups          # In a cron job or CI step`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := db.DefaultPath()
+		if err != nil {
+			return err
+		}
+		// Stat the file before opening it, since opening may write to it
+		freshness, err := db.CheckBackupFreshness(path)
+		if err != nil {
+			return err
+		}
+		database, err := db.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		code := 0
+		fmt.Println("1. Integrity check...")
+		problems, err := database.IntegrityProblems()
+		if err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			fmt.Println("   ✓ ok")
+		} else {
+			code = fsckExitCorrupt
+			fmt.Printf("   ✗ %d problem(s):\n", len(problems))
+			for _, p := range problems {
+				fmt.Printf("      - %s\n", p)
+			}
+		}
+
+		fmt.Println("\n2. Foreign key check...")
+		violations, err := database.ForeignKeyViolations()
+		if err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			fmt.Println("   ✓ ok")
+		} else {
+			code = fsckExitCorrupt
+			fmt.Printf("   ✗ %d row(s) reference missing rows:\n", len(violations))
+			for _, v := range violations {
+				fmt.Printf("      - %s row %d -> %s\n", v.Table, v.RowID, v.Parent)
+			}
+		}
+
+		fmt.Println("\n3. Backup freshness...")
+		slack := fsckBackupSlack
+		if config, err := db.LoadConfig(); err == nil {
+			if interval, err := config.BackupInterval(); err == nil && interval > slack {
+				slack = interval
+			}
+		}
+		switch behind := freshness.Behind(); {
+		case freshness.Latest == nil:
+			fmt.Println("   ✗ no backups (run 'tpg backup')")
+		case behind > slack:
+			fmt.Printf("   ✗ newest backup %s is %s older than the last change (run 'tpg backup')\n",
+				freshness.Latest.Name, behind.Round(time.Second))
+		default:
+			fmt.Printf("   ✓ newest backup %s (%s)\n", freshness.Latest.Name, formatTimeAgo(freshness.Latest.ModTime))
+		}
+		if code == 0 && (freshness.Latest == nil || freshness.Behind() > slack) {
+			code = fsckExitBackup
+		}
+
+		if code == 0 {
+			fmt.Println("\nNo problems found")
+			return nil
+		}
+		if code == fsckExitCorrupt {
+			fmt.Println("\nThe database is damaged. Restore a backup with 'tpg backups' and 'tpg restore',")
+			fmt.Println("or try: sqlite3 .tpg/tpg.db '.recover' | sqlite3 .tpg/tpg.db.recovered")
+		}
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return &exitStatusError{code: code}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fsckCmd)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+const (
+	gitHookBegin = "# >>> tpg auto-log >>>"
+	gitHookEnd   = "# <<< tpg auto-log <<<"
+)
+
+// gitHookBlock is the snippet added to .git/hooks/post-commit. Failures are
+// ignored so that a missing tpg binary or database never blocks a commit.
+const gitHookBlock = gitHookBegin + `
+tpg git-hook post-commit >/dev/null 2>&1 || true
+` + gitHookEnd + "\n"
+
+var gitHookCmd = &cobra.Command{
+	Use:   "git-hook",
+	Short: "Log git commits to the active task automatically",
+	Long: `Install a git post-commit hook that logs every commit to the task you
+are working on, so commits and task logs don't drift apart.
+
+After each commit the hook adds "progress: commit <sha> <subject>" to the
+active task: the current task (see 'tpg current') if it is in progress, else
+the in-progress task most recently updated by $AGENT_ID, or, without an agent
+ID, the only in-progress task in the project. Commits are not logged when
+there is no such task.
+
+An existing post-commit hook is kept; the tpg lines are added to the end and
+removed again by 'tpg git-hook uninstall'.
+
+Examples:
+  tpg git-hook install
+  tpg git-hook uninstall`,
+}
+
+var gitHookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the post-commit hook in the current repository",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := postCommitHookPath()
+		if err != nil {
+			return err
+		}
+		existing, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read hook: %w", err)
+		}
+		content := string(existing)
+		if strings.Contains(content, gitHookBegin) {
+			fmt.Printf("Hook already installed: %s\n", path)
+			return nil
+		}
+		if content == "" {
+			content = "#!/bin/sh\n"
+		} else if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += gitHookBlock
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create hooks directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			return fmt.Errorf("failed to write hook: %w", err)
+		}
+		// WriteFile keeps the mode of an existing file; make sure it runs.
+		if err := os.Chmod(path, 0755); err != nil {
+			return fmt.Errorf("failed to make hook executable: %w", err)
+		}
+		fmt.Printf("Installed post-commit hook: %s\n", path)
+		return nil
+	},
+}
+
+var gitHookUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the post-commit hook from the current repository",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := postCommitHookPath()
+		if err != nil {
+			return err
+		}
+		existing, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			fmt.Println("Hook not installed")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read hook: %w", err)
+		}
+		content, removed := removeGitHookBlock(string(existing))
+		if !removed {
+			fmt.Println("Hook not installed")
+			return nil
+		}
+		if strings.TrimSpace(strings.TrimPrefix(content, "#!/bin/sh")) == "" {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove hook: %w", err)
+			}
+		} else if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			return fmt.Errorf("failed to write hook: %w", err)
+		}
+		fmt.Printf("Removed post-commit hook: %s\n", path)
+		return nil
+	},
+}
+
+var gitHookPostCommitCmd = &cobra.Command{
+	Use:   "post-commit",
+	Short: "Log the latest commit to the active task (run by the hook)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := execCommand("git", "log", "-1", "--format=%h%x00%s").Output()
+		if err != nil {
+			return fmt.Errorf("failed to read commit: %w", err)
+		}
+		sha, subject, _ := strings.Cut(strings.TrimSpace(string(out)), "\x00")
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+		item, err := activeTaskForCommit(database, project, db.GetAgentContext())
+		if err != nil || item == nil {
+			return err
+		}
+		if err := database.AddLog(item.ID, fmt.Sprintf("progress: commit %s %s", sha, subject)); err != nil {
+			return err
+		}
+		fmt.Printf("Logged commit %s to %s\n", sha, item.ID)
+		return nil
+	},
+}
+
+// activeTaskForCommit picks the task a commit belongs to: the session's
+// current task if it is in progress, else the agent's most recently updated
+// in-progress task, or without an agent, the project's only in-progress task.
+// It returns nil when there is no clear choice.
+func activeTaskForCommit(database *db.DB, project string, agent db.AgentContext) (*model.Item, error) {
+	current, err := database.GetCurrentTask(db.CurrentSession())
+	if err != nil {
+		return nil, err
+	}
+	if current != "" {
+		item, err := database.GetItem(current)
+		if err == nil && item.Status == model.StatusInProgress {
+			return item, nil
+		}
+	}
+	if agent.IsActive() {
+		items, err := database.InProgressItemsByAgent(agent.ID)
+		if err != nil || len(items) == 0 {
+			return nil, err
+		}
+		return &items[0], nil
+	}
+	status := model.StatusInProgress
+	items, err := database.ListItemsFiltered(db.ListFilter{Project: project, Status: &status})
+	if err != nil || len(items) != 1 {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// postCommitHookPath returns where git looks for the post-commit hook,
+// honoring core.hooksPath and linked worktrees.
+func postCommitHookPath() (string, error) {
+	out, err := execCommand("git", "rev-parse", "--git-path", "hooks/post-commit").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(wd, path)
+	}
+	return path, nil
+}
+
+// removeGitHookBlock strips the tpg lines from a hook script.
+func removeGitHookBlock(content string) (string, bool) {
+	start := strings.Index(content, gitHookBegin)
+	if start < 0 {
+		return content, false
+	}
+	end := strings.Index(content[start:], gitHookEnd)
+	if end < 0 {
+		return content, false
+	}
+	end += start + len(gitHookEnd)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:start] + content[end:], true
+}
+
+func init() {
+	gitHookCmd.AddCommand(gitHookInstallCmd)
+	gitHookCmd.AddCommand(gitHookUninstallCmd)
+	gitHookCmd.AddCommand(gitHookPostCommitCmd)
+	rootCmd.AddCommand(gitHookCmd)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestRemoveGitHookBlock(t *testing.T) {
+	existing := "#!/bin/sh\nmake lint\n"
+
+	content, removed := removeGitHookBlock(existing + gitHookBlock)
+	if !removed {
+		t.Fatal("expected block to be removed")
+	}
+	if content != existing {
+		t.Errorf("content = %q, want %q", content, existing)
+	}
+
+	if _, removed := removeGitHookBlock(existing); removed {
+		t.Error("expected nothing to remove from a hook without the block")
+	}
+}
+
+func TestActiveTaskForCommit(t *testing.T) {
+	database := setupTestDB(t)
+	mine := createTestItem(t, database, "ts-mine", "Mine")
+	other := createTestItem(t, database, "ts-other", "Other")
+	createTestItem(t, database, "ts-open", "Open")
+
+	if err := database.UpdateStatus(mine.ID, model.StatusInProgress, db.AgentContext{ID: "agent-a"}, false); err != nil {
+		t.Fatalf("failed to start task: %v", err)
+	}
+
+	// Only one task in progress: used even without an agent ID.
+	item, err := activeTaskForCommit(database, "test", db.AgentContext{})
+	if err != nil {
+		t.Fatalf("activeTaskForCommit failed: %v", err)
+	}
+	if item == nil || item.ID != mine.ID {
+		t.Fatalf("got %v, want %s", item, mine.ID)
+	}
+
+	if err := database.UpdateStatus(other.ID, model.StatusInProgress, db.AgentContext{ID: "agent-b"}, false); err != nil {
+		t.Fatalf("failed to start task: %v", err)
+	}
+
+	// Two tasks in progress: ambiguous without an agent ID.
+	item, err = activeTaskForCommit(database, "test", db.AgentContext{})
+	if err != nil {
+		t.Fatalf("activeTaskForCommit failed: %v", err)
+	}
+	if item != nil {
+		t.Errorf("got %s, want no task when ambiguous", item.ID)
+	}
+
+	// The agent's own task is chosen.
+	item, err = activeTaskForCommit(database, "test", db.AgentContext{ID: "agent-b"})
+	if err != nil {
+		t.Fatalf("activeTaskForCommit failed: %v", err)
+	}
+	if item == nil || item.ID != other.ID {
+		t.Errorf("got %v, want %s", item, other.ID)
+	}
+
+	// An agent with nothing in progress gets nothing.
+	item, err = activeTaskForCommit(database, "test", db.AgentContext{ID: "agent-c"})
+	if err != nil {
+		t.Fatalf("activeTaskForCommit failed: %v", err)
+	}
+	if item != nil {
+		t.Errorf("got %s, want no task for idle agent", item.ID)
+	}
+}
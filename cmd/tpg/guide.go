@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var flagGuideKeep bool
+
+var guideCmd = &cobra.Command{
+	Use:   "guide",
+	Short: "Walk through the tpg workflow in a sandbox project",
+	Long: `Walk through a short scripted scenario to learn the tpg workflow:
+create an epic, add tasks, order them with a dependency, start work, log
+progress, finish with results, and record a learning.
+
+The guide creates a throwaway project in a temporary directory, so your own
+tasks are never touched. At each step it explains what to do and suggests a
+command; type the command at the prompt (the leading "tpg" is optional) and
+the guide runs it in the sandbox and checks the result before moving on.
+
+Type "hint" to repeat the suggestion or "quit" to stop. The sandbox is
+deleted when the guide ends unless --keep is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate the tpg binary: %w", err)
+		}
+		sandbox, err := os.MkdirTemp("", "tpg-guide-")
+		if err != nil {
+			return err
+		}
+		if !flagGuideKeep {
+			defer func() { _ = os.RemoveAll(sandbox) }()
+		}
+
+		g := &guide{
+			dbPath: filepath.Join(sandbox, db.DataDir, db.DBFile),
+			out:    os.Stdout,
+		}
+		sandboxCmd := func(args ...string) *exec.Cmd {
+			c := exec.Command(exe, args...)
+			c.Dir = sandbox
+			c.Env = append(os.Environ(), "TPG_DB="+g.dbPath)
+			return c
+		}
+		if out, err := sandboxCmd("init").CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create the sandbox project: %w\n%s", err, out)
+		}
+		g.run = func(args []string) error {
+			c := sandboxCmd(args...)
+			c.Stdout = g.out
+			c.Stderr = g.out
+			return c.Run()
+		}
+
+		if err := g.walk(os.Stdin); err != nil {
+			return err
+		}
+		if flagGuideKeep {
+			fmt.Printf("\nThe sandbox was kept at %s\n", sandbox)
+			fmt.Printf("Explore it with: cd %s && tpg list\n", sandbox)
+		}
+		return nil
+	},
+}
+
+// guide runs the scripted tutorial. run executes a tpg command in the
+// sandbox; the steps then inspect the sandbox database at dbPath.
+type guide struct {
+	dbPath string
+	out    io.Writer
+	run    func(args []string) error
+	state  guideState
+}
+
+// guideState carries what earlier steps created into later ones.
+type guideState struct {
+	epic    string
+	first   string // the blocking task
+	second  string // the task that waits on first
+	current string // the task being worked on
+	logs    int    // log entries on current when it was started
+}
+
+// guideStep is one stage of the tutorial. check inspects the sandbox after
+// each successful command and reports whether the step is complete, with
+// feedback when it isn't.
+type guideStep struct {
+	title   string
+	explain string
+	hint    func(s guideState) string
+	check   func(database *db.DB, s *guideState, args []string) (bool, string)
+}
+
+var guideSteps = []guideStep{
+	{
+		title: "Create an epic",
+		explain: `An epic groups related tasks. Its --context is shared with every task
+inside it, so agents picking up any task get the background they need.`,
+		hint: func(guideState) string {
+			return `tpg epic add "Build login page" --context "React app; the auth API is POST /api/login"`
+		},
+		check: func(database *db.DB, s *guideState, _ []string) (bool, string) {
+			epics, err := database.GetEpics("")
+			if err != nil || len(epics) == 0 {
+				return false, "No epic yet. Use 'tpg epic add'."
+			}
+			s.epic = epics[0].ID
+			return true, fmt.Sprintf("Created epic %s.", s.epic)
+		},
+	},
+	{
+		title: "Add tasks to the epic",
+		explain: `Tasks are the units of work. Add two tasks to the epic with --parent;
+--desc explains the task to whoever picks it up. tpg warns about short
+descriptions: real ones should let someone new start without asking.`,
+		hint: func(s guideState) string {
+			return fmt.Sprintf(`tpg add "Design the form" --parent %s --desc "Email and password fields with validation"
+      tpg add "Wire up the API call" --parent %s --desc "Submit the form and store the token"`, s.epic, s.epic)
+		},
+		check: func(database *db.DB, s *guideState, _ []string) (bool, string) {
+			tasks := guideEpicTasks(database, s.epic)
+			switch len(tasks) {
+			case 0:
+				return false, fmt.Sprintf("No tasks in %s yet. Remember --parent %s.", s.epic, s.epic)
+			case 1:
+				return false, "One task added; add one more."
+			}
+			s.first, s.second = tasks[0].ID, tasks[1].ID
+			return true, fmt.Sprintf("The epic has %d tasks.", len(tasks))
+		},
+	},
+	{
+		title: "Order the work with a dependency",
+		explain: `Dependencies say which task must finish first. A blocked task doesn't
+show up as ready until its blockers are done.`,
+		hint: func(s guideState) string {
+			return fmt.Sprintf("tpg dep %s blocks %s", s.first, s.second)
+		},
+		check: func(database *db.DB, s *guideState, _ []string) (bool, string) {
+			tasks := guideEpicTasks(database, s.epic)
+			for _, task := range tasks {
+				deps, err := database.GetDeps(task.ID)
+				if err != nil {
+					continue
+				}
+				for _, dep := range deps {
+					for _, other := range tasks {
+						if other.ID == dep {
+							s.first, s.second = dep, task.ID
+							return true, fmt.Sprintf("%s now waits for %s.", s.second, s.first)
+						}
+					}
+				}
+			}
+			return false, "No dependency between the epic's tasks yet."
+		},
+	},
+	{
+		title: "See what's ready",
+		explain: `'tpg ready' lists tasks whose dependencies are all done. This is how an
+agent picks its next task.`,
+		hint: func(guideState) string { return "tpg ready" },
+		check: func(_ *db.DB, s *guideState, args []string) (bool, string) {
+			if args[0] != "ready" {
+				return false, "Run 'tpg ready' to see the ready tasks."
+			}
+			return true, fmt.Sprintf("Only %s is ready; %s waits for it.", s.first, s.second)
+		},
+	},
+	{
+		title:   "Start work",
+		explain: `Starting a task marks it in progress so nobody else picks it up.`,
+		hint:    func(s guideState) string { return "tpg start " + s.first },
+		check: func(database *db.DB, s *guideState, _ []string) (bool, string) {
+			item, err := database.GetItem(s.first)
+			if err == nil && item.Status == model.StatusInProgress {
+				s.current = s.first
+				logs, _ := database.GetLogs(s.current)
+				s.logs = len(logs)
+				return true, fmt.Sprintf("%s is in progress.", s.current)
+			}
+			if second, err := database.GetItem(s.second); err == nil && second.Status == model.StatusInProgress {
+				return false, fmt.Sprintf("%s is still waiting for %s, so it isn't ready. Start %s instead.", s.second, s.first, s.first)
+			}
+			return false, fmt.Sprintf("%s isn't in progress yet.", s.first)
+		},
+	},
+	{
+		title: "Log progress",
+		explain: `Logs record what happened while you worked: decisions, dead ends,
+partial progress. They help whoever continues the task.`,
+		hint: func(s guideState) string {
+			return fmt.Sprintf(`tpg log %s "Sketched the layout; using the shared Input component"`, s.current)
+		},
+		check: func(database *db.DB, s *guideState, _ []string) (bool, string) {
+			logs, err := database.GetLogs(s.current)
+			if err != nil || len(logs) <= s.logs {
+				return false, fmt.Sprintf("No new log on %s yet.", s.current)
+			}
+			return true, fmt.Sprintf("Progress logged on %s.", s.current)
+		},
+	},
+	{
+		title: "Finish with results",
+		explain: `'tpg done' closes a task with a results message: what was built and
+where to find it. Finishing a blocker makes the tasks waiting on it ready.`,
+		hint: func(s guideState) string {
+			return fmt.Sprintf(`tpg done %s "Form built in src/LoginForm.tsx with field validation"`, s.current)
+		},
+		check: func(database *db.DB, s *guideState, _ []string) (bool, string) {
+			item, err := database.GetItem(s.current)
+			if err != nil || item.Status != model.StatusDone {
+				return false, fmt.Sprintf("%s isn't done yet.", s.current)
+			}
+			return true, fmt.Sprintf("%s is done.", s.current)
+		},
+	},
+	{
+		title: "Record what you learned",
+		explain: `Learnings capture knowledge worth keeping beyond one task. They are
+tagged with concepts (-c) and surface in 'tpg prime' and 'tpg context'.`,
+		hint: func(guideState) string {
+			return `tpg learn "The form library needs an explicit reset after submit" -c forms`
+		},
+		check: func(database *db.DB, _ *guideState, _ []string) (bool, string) {
+			count, err := database.GetLearningCount("")
+			if err != nil || count == 0 {
+				return false, "No learning recorded yet."
+			}
+			return true, "Learning recorded."
+		},
+	},
+}
+
+// guideEpicTasks returns the children of the sandbox epic, oldest first.
+func guideEpicTasks(database *db.DB, epicID string) []model.Item {
+	if epicID == "" {
+		return nil
+	}
+	children, err := database.GetChildren(epicID)
+	if err != nil {
+		return nil
+	}
+	return children
+}
+
+// walk runs the steps in order, reading commands from in. It returns nil when
+// the user quits or input ends.
+func (g *guide) walk(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	fmt.Fprintln(g.out, "Welcome to the tpg guide. Everything here happens in a throwaway project.")
+
+	for i, step := range guideSteps {
+		fmt.Fprintf(g.out, "\n── Step %d of %d: %s ──\n%s\n\n  Try: %s\n", i+1, len(guideSteps), step.title, step.explain, step.hint(g.state))
+
+		for done := false; !done; {
+			fmt.Fprint(g.out, "\ntpg> ")
+			if !scanner.Scan() {
+				fmt.Fprintln(g.out, "\nGuide stopped.")
+				return scanner.Err()
+			}
+			line := strings.TrimSpace(scanner.Text())
+			switch line {
+			case "":
+				continue
+			case "quit", "exit", "q":
+				fmt.Fprintln(g.out, "Guide stopped.")
+				return nil
+			case "hint", "help", "?":
+				fmt.Fprintf(g.out, "  Try: %s\n", step.hint(g.state))
+				continue
+			}
+
+			args, err := splitAliasArgs(strings.TrimPrefix(line, "tpg "))
+			if err != nil {
+				fmt.Fprintf(g.out, "  %v\n", err)
+				continue
+			}
+			if args[0] == "guide" {
+				fmt.Fprintln(g.out, "  You're already in the guide.")
+				continue
+			}
+			if err := g.run(args); err != nil {
+				fmt.Fprintln(g.out, "  That command failed; check the message above and try again.")
+				continue
+			}
+
+			var feedback string
+			done, feedback = g.check(step, args)
+			mark := "✗"
+			if done {
+				mark = "✓"
+			}
+			fmt.Fprintf(g.out, "  %s %s\n", mark, feedback)
+		}
+	}
+
+	fmt.Fprintln(g.out, `
+You've done the whole loop. In your own project:
+  tpg init      create the database
+  tpg onboard   set up agent integration
+  tpg prime     see what agents are told at the start of a session`)
+	return nil
+}
+
+// check opens the sandbox database and runs the step's check.
+func (g *guide) check(step guideStep, args []string) (bool, string) {
+	database, err := db.Open(g.dbPath)
+	if err != nil {
+		return false, err.Error()
+	}
+	defer func() { _ = database.Close() }()
+	return step.check(database, &g.state, args)
+}
+
+func init() {
+	guideCmd.Flags().BoolVar(&flagGuideKeep, "keep", false, "Keep the sandbox project when the guide ends")
+	rootCmd.AddCommand(guideCmd)
+}
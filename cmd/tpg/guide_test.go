@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestGuideWalk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tpg.db")
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := database.Init(); err != nil {
+		t.Fatalf("failed to init db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	// Stand in for the tpg binary by applying each command to the database.
+	now := time.Now()
+	epic := "ep-1"
+	var out bytes.Buffer
+	g := &guide{dbPath: path, out: &out}
+	g.run = func(args []string) error {
+		switch args[0] {
+		case "epic":
+			return database.CreateItem(&model.Item{ID: epic, Type: model.ItemTypeEpic, Title: args[2], Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now})
+		case "add":
+			id := "ts-1"
+			if children, _ := database.GetChildren(epic); len(children) > 0 {
+				id = "ts-2"
+			}
+			return database.CreateItem(&model.Item{ID: id, Type: model.ItemTypeTask, Title: args[1], ParentID: &epic, Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now.Add(time.Second)})
+		case "dep":
+			return database.AddDep(args[3], args[1])
+		case "start":
+			return database.UpdateStatus(args[1], model.StatusInProgress, db.AgentContext{}, true)
+		case "log":
+			return database.AddLog(args[1], args[2])
+		case "done":
+			return database.UpdateStatus(args[1], model.StatusDone, db.AgentContext{}, true)
+		case "learn":
+			return database.CreateLearning(&model.Learning{ID: "lrn-1", Summary: args[1], Status: model.LearningStatusActive, CreatedAt: now, UpdatedAt: now})
+		}
+		return nil
+	}
+
+	input := strings.Join([]string{
+		`tpg epic add "Build login page"`,
+		`add "Design the form" --parent ep-1`,
+		`add "Wire up" --parent ep-1`,
+		`dep ts-1 blocks ts-2`,
+		`list`,
+		`ready`,
+		`start ts-2`,
+		`start ts-1`,
+		`log ts-1 "Sketched the layout"`,
+		`done ts-1 "Form built"`,
+		`learn "Reset the form after submit" -c forms`,
+	}, "\n")
+	if err := g.walk(strings.NewReader(input)); err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"✓ Created epic ep-1.",
+		"✗ One task added; add one more.",
+		"Try: tpg dep ts-1 blocks ts-2",
+		"✗ Run 'tpg ready' to see the ready tasks.",
+		"✓ Only ts-1 is ready; ts-2 waits for it.",
+		"Start ts-1 instead.",
+		"✓ Progress logged on ts-1.",
+		"✓ ts-1 is done.",
+		"✓ Learning recorded.",
+		"You've done the whole loop.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGuideWalkStopsOnQuit(t *testing.T) {
+	var out bytes.Buffer
+	g := &guide{out: &out, run: func([]string) error {
+		t.Fatal("quit should not run a command")
+		return nil
+	}}
+	if err := g.walk(strings.NewReader("quit\n")); err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	if !strings.Contains(out.String(), "Guide stopped.") || strings.Contains(out.String(), "Step 2") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
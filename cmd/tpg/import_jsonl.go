@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var importJSONLCmd = &cobra.Command{
+	Use:   "jsonl <path>",
+	Short: "Import items from a 'tpg export --jsonl' file",
+	Long: `Import items written by 'tpg export --jsonl' or 'tpg clean --save', keeping
+their IDs, status, timestamps, parents, labels, fields, logs, dependencies,
+and links from learnings.
+
+This is how to undo a clean: re-import the file 'tpg clean --save' wrote
+before deleting. Items whose ID already exists are skipped, so importing the
+same file twice is harmless.
+
+Examples:
+  tpg clean --all --save cleaned.jsonl
+  tpg import jsonl cleaned.jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", args[0], err)
+		}
+		defer func() { _ = file.Close() }()
+
+		result, err := importExportJSONL(database, file)
+		if err != nil {
+			return err
+		}
+		for _, w := range result.Warnings {
+			fmt.Printf("Warning: %s\n", w)
+		}
+		fmt.Printf("Imported %d items\n", result.Imported)
+		if result.Skipped > 0 {
+			fmt.Printf("Skipped %d existing items\n", result.Skipped)
+		}
+		if result.Deps > 0 {
+			fmt.Printf("Restored %d dependencies\n", result.Deps)
+		}
+		if result.Learnings > 0 {
+			fmt.Printf("Relinked %d learnings\n", result.Learnings)
+		}
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// jsonlImportResult summarizes an import of export JSONL.
+type jsonlImportResult struct {
+	Imported  int
+	Skipped   int
+	Deps      int
+	Learnings int
+	Warnings  []string
+}
+
+// importExportJSONL restores the items in r, one ExportDataJSON per line,
+// parents before their children.
+func importExportJSONL(database *db.DB, r io.Reader) (jsonlImportResult, error) {
+	var result jsonlImportResult
+
+	var records []ExportDataJSON
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var rec ExportDataJSON
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return result, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+		}
+		if rec.ID == "" {
+			return result, fmt.Errorf("line %d has no item id", lineNum)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	inFile := make(map[string]bool, len(records))
+	for _, rec := range records {
+		inFile[rec.ID] = true
+	}
+
+	// Restore items in passes so a parent in the file goes in before its
+	// children.
+	restored := make(map[string]bool)
+	pending := records
+	for len(pending) > 0 {
+		var next []ExportDataJSON
+		for _, rec := range pending {
+			if rec.ParentID != nil && inFile[*rec.ParentID] && !restored[*rec.ParentID] {
+				next = append(next, rec)
+				continue
+			}
+			ok, err := restoreExportedItem(database, rec, &result)
+			if err != nil {
+				return result, err
+			}
+			// Mark skipped items too: their children can go in under them.
+			restored[rec.ID] = true
+			if ok {
+				result.Imported++
+			} else {
+				result.Skipped++
+			}
+		}
+		if len(next) == len(pending) {
+			return result, fmt.Errorf("items %s have parents that form a cycle", exportedIDs(next))
+		}
+		pending = next
+	}
+
+	// A dependency between two items in the file is listed on both.
+	seen := make(map[[2]string]bool)
+	for _, rec := range records {
+		var edges [][2]string
+		for _, dep := range rec.Dependencies {
+			edges = append(edges, [2]string{rec.ID, dep.ID})
+		}
+		for _, dependent := range rec.Dependents {
+			edges = append(edges, [2]string{dependent, rec.ID})
+		}
+		for _, e := range edges {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			added, err := database.RestoreDep(e[0], e[1])
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("dependency %s -> %s not restored: %v", e[0], e[1], err))
+				continue
+			}
+			if added {
+				result.Deps++
+			}
+		}
+	}
+
+	for _, rec := range records {
+		for _, learningID := range rec.Learnings {
+			relinked, err := database.RelinkLearning(learningID, rec.ID)
+			if err != nil {
+				return result, err
+			}
+			if relinked {
+				result.Learnings++
+			}
+		}
+	}
+	return result, nil
+}
+
+// restoreExportedItem restores one item with its fields, labels, and logs.
+// It returns false if an item with that ID already exists.
+func restoreExportedItem(database *db.DB, rec ExportDataJSON, result *jsonlImportResult) (bool, error) {
+	if _, err := database.GetItem(rec.ID); err == nil {
+		return false, nil
+	}
+
+	createdAt, err := parseExportTime(rec.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid created_at: %w", rec.ID, err)
+	}
+	updatedAt, err := parseExportTime(rec.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid updated_at: %w", rec.ID, err)
+	}
+	item := &model.Item{
+		ID:           rec.ID,
+		Project:      rec.Project,
+		Type:         model.ItemType(rec.Type),
+		Title:        rec.Title,
+		Description:  rec.Description,
+		Status:       model.Status(rec.Status),
+		Priority:     rec.Priority,
+		ParentID:     rec.ParentID,
+		Results:      rec.Results,
+		TemplateID:   rec.TemplateID,
+		StepIndex:    rec.StepIndex,
+		TemplateVars: rec.TemplateVars,
+		Fields:       rec.Fields,
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+	}
+	if rec.ClosedAt != "" {
+		closedAt, err := parseExportTime(rec.ClosedAt)
+		if err != nil {
+			return false, fmt.Errorf("%s: invalid closed_at: %w", rec.ID, err)
+		}
+		item.ClosedAt = &closedAt
+	}
+	if item.ParentID != nil {
+		if _, err := database.GetItem(*item.ParentID); err != nil {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("%s: parent %s no longer exists, imported without a parent", rec.ID, *item.ParentID))
+			item.ParentID = nil
+		}
+	}
+	if err := database.RestoreItem(item); err != nil {
+		return false, err
+	}
+
+	for _, name := range rec.Labels {
+		if err := database.AddLabelToItem(rec.ID, rec.Project, name); err != nil {
+			return true, fmt.Errorf("%s: %w", rec.ID, err)
+		}
+	}
+	for _, l := range rec.Logs {
+		created, err := parseExportTime(l.CreatedAt)
+		if err != nil {
+			return true, fmt.Errorf("%s: invalid log created_at: %w", rec.ID, err)
+		}
+		log := model.Log{
+			ItemID:    rec.ID,
+			Message:   l.Message,
+			Category:  model.LogCategory(l.Category),
+			Progress:  l.Progress,
+			CreatedAt: created,
+		}
+		if err := database.RestoreLog(log); err != nil {
+			return true, fmt.Errorf("%s: %w", rec.ID, err)
+		}
+	}
+	return true, nil
+}
+
+func parseExportTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
+
+func exportedIDs(records []ExportDataJSON) string {
+	ids := make([]string, len(records))
+	for i, rec := range records {
+		ids[i] = rec.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+func init() {
+	importCmd.AddCommand(importJSONLCmd)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestCleanSaveAndImportJSONL(t *testing.T) {
+	database := setupTestDB(t)
+
+	createTestItem(t, database, "ep-clean", "Old epic", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ts-clean", "Old task", withParent("ep-clean"))
+	createTestItem(t, database, "ts-after", "Follow-up")
+	if err := database.AddLabelToItem("ts-clean", "test", "bug"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+	if err := database.SetField("ts-clean", "reviewer", "alice"); err != nil {
+		t.Fatalf("SetField: %v", err)
+	}
+	if err := database.AddLog("ts-clean", "decision: keep it simple"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	if err := database.AddDep("ts-after", "ts-clean"); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	taskID := "ts-clean"
+	learning := &model.Learning{ID: model.GenerateLearningID(), Project: "test", TaskID: &taskID, Summary: "Keep it simple"}
+	if err := database.CreateLearning(learning); err != nil {
+		t.Fatalf("CreateLearning: %v", err)
+	}
+	if _, err := database.Exec(`UPDATE items SET status = 'done', closed_at = updated_at WHERE id IN ('ep-clean', 'ts-clean')`); err != nil {
+		t.Fatalf("failed to close items: %v", err)
+	}
+
+	// Save the child first, so the import has to put the parent in before it.
+	all, err := database.ListItemsFiltered(db.ListFilter{Project: "test"})
+	if err != nil {
+		t.Fatalf("ListItemsFiltered: %v", err)
+	}
+	byID := make(map[string]model.Item)
+	for _, item := range all {
+		byID[item.ID] = item
+	}
+	doomed := []model.Item{byID["ts-clean"], byID["ep-clean"]}
+	path := filepath.Join(t.TempDir(), "cleaned.jsonl")
+	if err := saveCleanItems(database, path, doomed); err != nil {
+		t.Fatalf("saveCleanItems: %v", err)
+	}
+	if _, err := database.DeleteItemsByID([]string{"ts-clean"}); err != nil {
+		t.Fatalf("DeleteItemsByID: %v", err)
+	}
+	if _, err := database.DeleteItemsByID([]string{"ep-clean"}); err != nil {
+		t.Fatalf("DeleteItemsByID: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	result, err := importExportJSONL(database, f)
+	if err != nil {
+		t.Fatalf("importExportJSONL: %v", err)
+	}
+	if result.Imported != 2 || result.Skipped != 0 || result.Deps != 1 || result.Learnings != 1 || len(result.Warnings) != 0 {
+		t.Errorf("result = %+v, want 2 imported, 1 dep, 1 learning, no warnings", result)
+	}
+
+	item, err := database.GetItem("ts-clean")
+	if err != nil {
+		t.Fatalf("ts-clean not restored: %v", err)
+	}
+	var closed int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM items WHERE id = 'ts-clean' AND closed_at IS NOT NULL`).Scan(&closed); err != nil {
+		t.Fatal(err)
+	}
+	if item.Status != model.StatusDone || closed != 1 {
+		t.Errorf("status = %s, closed_at set = %v; want done with closed_at", item.Status, closed == 1)
+	}
+	if item.ParentID == nil || *item.ParentID != "ep-clean" {
+		t.Errorf("parent = %v, want ep-clean", item.ParentID)
+	}
+	labels, _ := database.GetItemLabels("ts-clean")
+	if len(labels) != 1 || labels[0].Name != "bug" {
+		t.Errorf("labels = %v, want [bug]", labels)
+	}
+	fields, _ := database.GetFields("ts-clean")
+	if fields["reviewer"] != "alice" {
+		t.Errorf("fields = %v, want reviewer=alice", fields)
+	}
+	logs, _ := database.GetLogs("ts-clean")
+	if len(logs) != 1 || logs[0].Category != model.LogCategoryDecision {
+		t.Errorf("logs = %+v, want the decision log", logs)
+	}
+	deps, _ := database.GetDeps("ts-after")
+	if strings.Join(deps, ",") != "ts-clean" {
+		t.Errorf("ts-after deps = %v, want [ts-clean]", deps)
+	}
+
+	if l, err := database.GetLearning(learning.ID); err != nil || l.TaskID == nil || *l.TaskID != "ts-clean" {
+		t.Errorf("learning after import = %+v, %v; want it linked to ts-clean", l, err)
+	}
+
+	// Importing again changes nothing.
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	result, err = importExportJSONL(database, f)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	if result.Imported != 0 || result.Skipped != 2 || result.Deps != 0 {
+		t.Errorf("second import = %+v, want everything skipped", result)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var itemAliasCmd = &cobra.Command{
+	Use:   "item-alias",
+	Short: "Manage short names for items",
+	Long: `Manage item aliases: short names usable anywhere an item's ID is
+accepted (e.g. 'tpg show login-bug'). Each item has at most one, and list
+output shows it as @alias before the title.
+
+Item aliases are stored in the database. For shortcuts to commands, see
+'tpg alias'.
+
+Examples:
+  tpg item-alias add login-bug ts-a1b2c3
+  tpg done login-bug
+  tpg item-alias list
+  tpg item-alias rm login-bug`,
+}
+
+var itemAliasAddCmd = &cobra.Command{
+	Use:   "add <alias> <id>",
+	Short: "Give an item a short alias usable in place of its ID",
+	Long: `Give an item a short alias usable anywhere the item's ID is accepted.
+
+Aliases use lowercase letters, digits, and hyphens and start with a
+letter, and can't have the shape of an item ID. An item has at most one
+alias; adding another replaces it.
+
+Examples:
+  tpg item-alias add login-bug ts-a1b2c3
+  tpg item-alias add auth-epic .`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[1])
+		if err != nil {
+			return err
+		}
+		if err := database.SetItemAlias(args[0], id); err != nil {
+			return err
+		}
+		fmt.Printf("Alias %s -> %s\n", args[0], id)
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+var itemAliasListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List item aliases",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		aliases, err := database.ItemAliases()
+		if err != nil {
+			return err
+		}
+		if len(aliases) == 0 {
+			fmt.Println("No item aliases")
+			return nil
+		}
+		for _, a := range aliases {
+			title := ""
+			if item, err := database.GetItem(a.ItemID); err == nil {
+				title = " " + item.Title
+			}
+			fmt.Printf("%s -> %s%s\n", a.Alias, a.ItemID, title)
+		}
+		return nil
+	},
+}
+
+var itemAliasRmCmd = &cobra.Command{
+	Use:     "rm <alias>",
+	Aliases: []string{"remove"},
+	Short:   "Remove an item alias",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		removed, err := database.RemoveItemAlias(args[0])
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return fmt.Errorf("item alias not found: %s", args[0])
+		}
+		fmt.Printf("Removed item alias %s\n", args[0])
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+func init() {
+	itemAliasCmd.AddCommand(itemAliasAddCmd)
+	itemAliasCmd.AddCommand(itemAliasListCmd)
+	itemAliasCmd.AddCommand(itemAliasRmCmd)
+	rootCmd.AddCommand(itemAliasCmd)
+}
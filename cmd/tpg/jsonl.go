@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// formatJSONL is the --format value that streams JSON Lines: one object per
+// line, written as rows are read rather than collected into an array.
+const formatJSONL = "jsonl"
+
+// checkStreamFormat validates --format on commands that print text by
+// default and can stream JSON Lines. It reports whether jsonl was chosen.
+func checkStreamFormat(format string) (bool, error) {
+	switch format {
+	case "", "text":
+		return false, nil
+	case formatJSONL:
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown format %q (valid: text, jsonl)", format)
+}
+
+// jsonlStream writes one JSON object per line to a buffered stdout.
+type jsonlStream struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLStream(w io.Writer) *jsonlStream {
+	buf := bufio.NewWriter(w)
+	return &jsonlStream{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+// write encodes v as one line.
+func (s *jsonlStream) write(v any) error {
+	return s.enc.Encode(v)
+}
+
+// close flushes anything still buffered.
+func (s *jsonlStream) close() error {
+	return s.buf.Flush()
+}
+
+// streamItemsJSONL writes each item matching filter for which keep returns
+// true as a JSON line, in the same shape as 'tpg export --jsonl' (without
+// logs and dependencies).
+func streamItemsJSONL(database *db.DB, filter db.ListFilter, keep func(model.Item) bool) error {
+	out := newJSONLStream(os.Stdout)
+	cache := &templateCache{}
+	err := database.EachItemFiltered(filter, func(item model.Item) error {
+		if !keep(item) {
+			return nil
+		}
+		return writeItemJSONL(out, database, cache, item)
+	})
+	if err != nil {
+		_ = out.close()
+		return err
+	}
+	return out.close()
+}
+
+// writeItemJSONL renders a templated item and writes it with its labels.
+func writeItemJSONL(out *jsonlStream, database *db.DB, cache *templateCache, item model.Item) error {
+	one := []model.Item{item}
+	if err := renderTemplatesWithCache(cache, one); err != nil {
+		return err
+	}
+	labels, err := database.GetItemLabels(item.ID)
+	if err != nil {
+		return err
+	}
+	return out.write(convertToJSONItem(ExportData{Item: &one[0], Labels: labels}))
+}
+
+// historyEventJSON is the JSON form of a history event.
+type historyEventJSON struct {
+	ID        int64          `json:"id"`
+	ItemID    string         `json:"item_id"`
+	EventType string         `json:"event_type"`
+	ActorID   string         `json:"actor_id,omitempty"`
+	ActorName string         `json:"actor_name,omitempty"`
+	ActorType string         `json:"actor_type,omitempty"`
+	Session   string         `json:"session,omitempty"`
+	Changes   map[string]any `json:"changes,omitempty"`
+	CreatedAt string         `json:"created_at"`
+}
+
+func newHistoryEventJSON(e db.HistoryEntry, agentNames map[string]string) historyEventJSON {
+	return historyEventJSON{
+		ID:        e.ID,
+		ItemID:    e.ItemID,
+		EventType: e.EventType,
+		ActorID:   e.ActorID,
+		ActorName: agentNames[e.ActorID],
+		ActorType: e.ActorType,
+		Session:   e.Session,
+		Changes:   e.Changes,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// streamHistoryJSONL writes each history event matching opts as a JSON line.
+func streamHistoryJSONL(database *db.DB, opts db.HistoryQueryOptions, agentNames map[string]string) error {
+	out := newJSONLStream(os.Stdout)
+	err := database.EachHistory(opts, func(e db.HistoryEntry) error {
+		return out.write(newHistoryEventJSON(e, agentNames))
+	})
+	if err != nil {
+		_ = out.close()
+		return err
+	}
+	return out.close()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestStreamItemsJSONL(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now()
+	for _, item := range []*model.Item{
+		{ID: "ts-j1", Project: "test", Type: model.ItemTypeTask, Title: "First", Status: model.StatusOpen, Priority: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "ts-j2", Project: "test", Type: model.ItemTypeTask, Title: "Second", Status: model.StatusDone, Priority: 2, CreatedAt: now, UpdatedAt: now},
+		{ID: "ts-j3", Project: "test", Type: model.ItemTypeTask, Title: "Third", Status: model.StatusOpen, Priority: 3, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := database.CreateItem(item); err != nil {
+			t.Fatalf("CreateItem: %v", err)
+		}
+	}
+	if err := database.AddLabelToItem("ts-j1", "test", "bug"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+
+	var streamErr error
+	out := captureOutput(func() {
+		streamErr = streamItemsJSONL(database, db.ListFilter{Project: "test"}, func(item model.Item) bool {
+			return item.Status != model.StatusDone
+		})
+	})
+	if streamErr != nil {
+		t.Fatalf("streamItemsJSONL: %v", streamErr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out)
+	}
+	var first ExportDataJSON
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line is not a JSON object: %v\n%s", err, lines[0])
+	}
+	if first.ID != "ts-j1" || len(first.Labels) != 1 || first.Labels[0] != "bug" {
+		t.Errorf("first line = %+v, want ts-j1 with label bug", first)
+	}
+	if !strings.Contains(lines[1], `"id":"ts-j3"`) {
+		t.Errorf("second line = %s, want ts-j3", lines[1])
+	}
+}
+
+func TestCheckStreamFormat(t *testing.T) {
+	for format, want := range map[string]bool{"": false, "text": false, "jsonl": true} {
+		if got, err := checkStreamFormat(format); err != nil || got != want {
+			t.Errorf("checkStreamFormat(%q) = %v, %v; want %v", format, got, err, want)
+		}
+	}
+	if _, err := checkStreamFormat("json"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// harvestScanLimit caps how many recently closed tasks harvest reads.
+const harvestScanLimit = 500
+
+var flagHarvestSince string
+
+var learnHarvestCmd = &cobra.Command{
+	Use:   "harvest",
+	Short: "Turn notes in recent done results into learnings",
+	Long: `Scan the results of recently done tasks for candidate learnings and offer
+each one for confirmation.
+
+Candidates are the lines under a Notes, Gotchas, Caveats, or Lessons
+heading, and sentences that say something like "turns out", "beware", or
+"watch out". For each one, answer y to record it, e to edit the summary
+first, n to skip, or q to stop. Accepted learnings are linked to the task
+whose results they came from; concepts default to the suggested ones.
+
+Candidates that already exist as learnings are skipped. With --yes, the
+ones with confidently matching concepts are recorded without asking, even in
+a terminal. Without a terminal or --yes the candidates are only listed.
+
+Examples:
+  tpg learn harvest
+  tpg learn harvest --since 30d`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := parseSince("since", flagHarvestSince)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		candidates, err := findHarvestCandidates(database, project, since)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			fmt.Printf("No candidate learnings in results since %s\n", since.Local().Format("2006-01-02"))
+			return nil
+		}
+
+		h := &harvester{db: database, out: os.Stdout}
+		switch {
+		case assumeYes():
+			err = h.acceptConfident(candidates)
+		case stdinIsTerminal():
+			err = h.review(candidates, bufio.NewReader(os.Stdin))
+		default:
+			h.list(candidates)
+			fmt.Println("\nRun in a terminal to confirm them, or pass --yes to record those with matching concepts.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.recorded > 0 {
+			database.BackupQuiet()
+		}
+		fmt.Printf("Recorded %d learning(s)\n", h.recorded)
+		return nil
+	},
+}
+
+// harvestCandidate is a possible learning found in a task's results.
+type harvestCandidate struct {
+	item model.Item
+	text string
+}
+
+// harvestHeading matches headings that introduce notes worth keeping, in
+// Markdown ("## Gotchas") or label ("Notes:") form. Text after a label's
+// colon is captured.
+var harvestHeading = regexp.MustCompile(`(?i)^(?:#+\s*)?(notes?|gotchas?|caveats?|lessons?(?: learned)?|learnings?)\s*(?::\s*(.*))?$`)
+
+// harvestCues are phrases that mark a sentence as a likely learning.
+var harvestCues = []string{"turns out", "turned out", "beware", "watch out", "gotcha", "be careful"}
+
+// extractHarvestCandidates returns the candidate learnings in a results
+// text: entries under a notes-like heading, then sentences containing a cue
+// phrase. A Markdown heading's section runs to the next heading, a label's
+// ("Notes:") to the next blank line or label. Duplicates are dropped.
+func extractHarvestCandidates(results string) []string {
+	var found []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		s = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(s), "-*• "))
+		if len(s) < 10 || seen[strings.ToLower(s)] {
+			return
+		}
+		seen[strings.ToLower(s)] = true
+		found = append(found, s)
+	}
+
+	inSection, inLabel := false, false
+	for _, raw := range strings.Split(results, "\n") {
+		line := strings.TrimSpace(raw)
+		if m := harvestHeading.FindStringSubmatch(line); m != nil {
+			inSection, inLabel = true, !strings.HasPrefix(line, "#")
+			add(m[2])
+			continue
+		}
+		isLabel := strings.HasSuffix(line, ":") && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "*")
+		switch {
+		case strings.HasPrefix(line, "#"), isLabel:
+			inSection = false
+		case line == "" && inLabel:
+			inSection = false
+		}
+		if inSection && line != "" {
+			add(line)
+		}
+	}
+
+	for _, sentence := range splitSentences(results) {
+		if strings.HasPrefix(sentence, "#") || harvestHeading.MatchString(sentence) || harvestContains(found, sentence) {
+			continue
+		}
+		lower := strings.ToLower(sentence)
+		for _, cue := range harvestCues {
+			if strings.Contains(lower, cue) {
+				add(sentence)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// harvestContains reports whether any candidate already includes s.
+func harvestContains(candidates []string, s string) bool {
+	s = strings.TrimSpace(s)
+	for _, c := range candidates {
+		if strings.Contains(c, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSentences splits text into sentences at line breaks and at ". ",
+// "! ", and "? ".
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, line := range strings.Split(text, "\n") {
+		start := 0
+		for i := 0; i < len(line)-1; i++ {
+			if (line[i] == '.' || line[i] == '!' || line[i] == '?') && line[i+1] == ' ' {
+				sentences = append(sentences, line[start:i+1])
+				start = i + 2
+			}
+		}
+		if rest := strings.TrimSpace(line[start:]); rest != "" {
+			sentences = append(sentences, rest)
+		}
+	}
+	return sentences
+}
+
+// findHarvestCandidates collects candidates from tasks done since the cutoff,
+// leaving out any already recorded as a learning.
+func findHarvestCandidates(database *db.DB, project string, since time.Time) ([]harvestCandidate, error) {
+	items, err := database.GetRecentlyClosed(harvestScanLimit, since)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := database.GetAllLearnings(project, true)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, l := range existing {
+		known[strings.ToLower(strings.TrimSpace(l.Summary))] = true
+	}
+
+	var candidates []harvestCandidate
+	for _, item := range items {
+		if item.Status != model.StatusDone || item.Results == "" || (project != "" && item.Project != project) {
+			continue
+		}
+		for _, text := range extractHarvestCandidates(item.Results) {
+			if !known[strings.ToLower(text)] {
+				candidates = append(candidates, harvestCandidate{item: item, text: text})
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// harvester records the candidates the user accepts.
+type harvester struct {
+	db       *db.DB
+	out      io.Writer
+	recorded int
+}
+
+// list prints the candidates without recording anything.
+func (h *harvester) list(candidates []harvestCandidate) {
+	for _, c := range candidates {
+		fmt.Fprintf(h.out, "%s %s\n    %s\n", c.item.ID, c.item.Title, c.text)
+	}
+}
+
+// review asks about each candidate in turn, reading answers from in.
+func (h *harvester) review(candidates []harvestCandidate, in *bufio.Reader) error {
+	for i, c := range candidates {
+		fmt.Fprintf(h.out, "\n[%d/%d] %s %s\n    %s\n", i+1, len(candidates), c.item.ID, c.item.Title, c.text)
+
+		answer, ok := harvestPrompt(h.out, in, "Record as a learning? [y/n/e/q]: ")
+		if !ok {
+			return nil
+		}
+		summary := c.text
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+		case "e", "edit":
+			edited, ok := harvestPrompt(h.out, in, "Summary: ")
+			if !ok {
+				return nil
+			}
+			if edited != "" {
+				summary = edited
+			}
+		case "q", "quit":
+			return nil
+		default:
+			continue
+		}
+
+		suggested, err := h.suggest(c.item, summary)
+		if err != nil {
+			return err
+		}
+		var concepts []string
+		for len(concepts) == 0 {
+			prompt := "Concepts (comma-separated): "
+			if len(suggested) > 0 {
+				prompt = fmt.Sprintf("Concepts [%s]: ", strings.Join(suggested, ", "))
+			}
+			answer, ok := harvestPrompt(h.out, in, prompt)
+			if !ok {
+				return nil
+			}
+			concepts = splitConceptList(answer)
+			if len(concepts) == 0 {
+				concepts = suggested
+			}
+			if len(concepts) == 0 {
+				fmt.Fprintln(h.out, "  At least one concept is required.")
+			}
+		}
+		if err := h.record(c.item, summary, concepts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// acceptConfident records every candidate whose text confidently matches
+// existing concepts and skips the rest.
+func (h *harvester) acceptConfident(candidates []harvestCandidate) error {
+	for _, c := range candidates {
+		concepts, err := h.suggest(c.item, c.text)
+		if err != nil {
+			return err
+		}
+		if len(concepts) == 0 {
+			fmt.Fprintf(h.out, "Skipped (no matching concept): %s\n", c.text)
+			continue
+		}
+		if err := h.record(c.item, c.text, concepts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suggest returns the concepts confidently matching a candidate.
+func (h *harvester) suggest(item model.Item, text string) ([]string, error) {
+	suggestions, err := h.db.SuggestConcepts(item.Project, text+"\n"+item.Title, learnSuggestLimit)
+	if err != nil {
+		return nil, err
+	}
+	return confidentConcepts(suggestions), nil
+}
+
+// record saves a learning linked to the task it was harvested from.
+func (h *harvester) record(item model.Item, summary string, concepts []string) error {
+	now := time.Now()
+	taskID := item.ID
+	learning := &model.Learning{
+		ID:        model.GenerateLearningID(),
+		Project:   item.Project,
+		CreatedAt: now,
+		UpdatedAt: now,
+		TaskID:    &taskID,
+		Summary:   summary,
+		Status:    model.LearningStatusActive,
+		Concepts:  concepts,
+	}
+	if err := h.db.CreateLearning(learning); err != nil {
+		return err
+	}
+	h.recorded++
+	fmt.Fprintf(h.out, "  %s (linked to %s; %s)\n", learning.ID, item.ID, strings.Join(concepts, ", "))
+	return nil
+}
+
+// harvestPrompt prints prompt and reads a trimmed line. It reports false
+// when input has ended.
+func harvestPrompt(out io.Writer, in *bufio.Reader, prompt string) (string, bool) {
+	fmt.Fprint(out, prompt)
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// splitConceptList splits a comma- or space-separated list of concepts.
+func splitConceptList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+func init() {
+	learnHarvestCmd.Flags().StringVar(&flagHarvestSince, "since", "7d", "Scan tasks done since this long ago or date (e.g. 7d, 2006-01-02)")
+	learnCmd.AddCommand(learnHarvestCmd)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestExtractHarvestCandidates(t *testing.T) {
+	results := `Added the rate limiter in api/limit.go.
+
+## Gotchas
+- Redis MULTI does not roll back on errors
+- The bucket must refill lazily on read
+
+## Files
+- api/limit.go
+
+Notes: config is read once at startup
+
+Tests live in api/limit_test.go. It turns out the clock mock needs UTC. Beware of DST in cron specs!`
+
+	got := extractHarvestCandidates(results)
+	want := []string{
+		"Redis MULTI does not roll back on errors",
+		"The bucket must refill lazily on read",
+		"config is read once at startup",
+		"It turns out the clock mock needs UTC.",
+		"Beware of DST in cron specs!",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("candidates =\n%q\nwant\n%q", got, want)
+	}
+
+	got = extractHarvestCandidates("Done. It turns out the API paginates at 100 items. All tests pass.")
+	want = []string{"It turns out the API paginates at 100 items."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cue candidates = %q, want %q", got, want)
+	}
+}
+
+func TestHarvestReview(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now()
+	item := &model.Item{ID: "ts-h1", Project: "test", Type: model.ItemTypeTask, Title: "Limiter", Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := database.CreateItem(item); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	if err := database.CompleteItem(item.ID, "Gotchas:\n- Redis MULTI does not roll back\n- The bucket refills lazily on read\n- Cron specs ignore DST changes", db.AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+
+	candidates, err := findHarvestCandidates(database, "test", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("findHarvestCandidates: %v", err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("got %d candidates, want 3", len(candidates))
+	}
+
+	// Accept the first, edit the second, skip the third
+	var out bytes.Buffer
+	h := &harvester{db: database, out: &out}
+	input := "y\nredis\ne\nBuckets refill on read\n\ncache, perf\nn\n"
+	if err := h.review(candidates, bufio.NewReader(strings.NewReader(input))); err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if h.recorded != 2 {
+		t.Fatalf("recorded %d learnings, want 2:\n%s", h.recorded, out.String())
+	}
+	if !strings.Contains(out.String(), "At least one concept is required.") {
+		t.Errorf("expected a prompt for concepts:\n%s", out.String())
+	}
+
+	learnings, err := database.GetAllLearnings("test", false)
+	if err != nil {
+		t.Fatalf("GetAllLearnings: %v", err)
+	}
+	summaries := map[string]bool{}
+	for _, l := range learnings {
+		summaries[l.Summary] = true
+		if l.TaskID == nil || *l.TaskID != item.ID {
+			t.Errorf("learning %q not linked to %s", l.Summary, item.ID)
+		}
+	}
+	if !summaries["Redis MULTI does not roll back"] || !summaries["Buckets refill on read"] {
+		t.Errorf("unexpected learnings: %v", summaries)
+	}
+
+	// Recorded candidates are not offered again
+	candidates, _ = findHarvestCandidates(database, "test", now.Add(-time.Hour))
+	if len(candidates) != 2 {
+		t.Errorf("got %d candidates after harvesting, want 2 (edited summary differs)", len(candidates))
+	}
+}
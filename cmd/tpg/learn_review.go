@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var flagLearnReviewAfter string
+
+var learnDueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List learnings past their scheduled review",
+	Long: `List active learnings whose --review-after date has passed.
+
+For each one, either confirm it is still valid (which schedules the next
+review) or mark it stale.
+
+Examples:
+  tpg learn due
+  tpg learn confirm lrn-abc123
+  tpg learn stale lrn-abc123 --reason "Replaced by the v2 API"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		due, err := database.DueLearnings(project, time.Now())
+		if err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			fmt.Println("No learnings due for review")
+			return nil
+		}
+		printDueLearnings(due)
+		fmt.Println("\nStill valid: tpg learn confirm <id> [--review-after <duration>]")
+		fmt.Println("Outdated:    tpg learn stale <id> --reason \"...\"")
+		return nil
+	},
+}
+
+var learnConfirmCmd = &cobra.Command{
+	Use:   "confirm <learning-id> [learning-id...]",
+	Short: "Confirm learnings are still valid and schedule the next review",
+	Long: `Mark learnings as reviewed and still valid. The next review is scheduled
+using --review-after, or the learning's existing interval.
+
+Examples:
+  tpg learn confirm lrn-abc123
+  tpg learn confirm lrn-abc123 --review-after 180d`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		for _, id := range args {
+			next, err := database.ConfirmLearning(id, flagLearnReviewAfter)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Confirmed %s (next review %s)\n", id, next.Local().Format("2006-01-02"))
+		}
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// printDueLearnings prints one line per learning due for review.
+func printDueLearnings(learnings []model.Learning) {
+	for _, l := range learnings {
+		overdue := ""
+		if l.ReviewAt != nil {
+			overdue = fmt.Sprintf(" (due %s)", formatTimeAgo(*l.ReviewAt))
+		}
+		concepts := ""
+		if len(l.Concepts) > 0 {
+			concepts = " [" + strings.Join(l.Concepts, ", ") + "]"
+		}
+		fmt.Printf("%s%s %s%s\n", l.ID, concepts, l.Summary, overdue)
+	}
+}
+
+func init() {
+	learnCmd.Flags().StringVar(&flagLearnReviewAfter, "review-after", "", "Schedule a review after this long, e.g. 90d")
+	learnConfirmCmd.Flags().StringVar(&flagLearnReviewAfter, "review-after", "", "Interval until the next review (default: the learning's current interval)")
+
+	learnCmd.AddCommand(learnDueCmd)
+	learnCmd.AddCommand(learnConfirmCmd)
+}
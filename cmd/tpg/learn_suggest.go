@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+// learnSuggestLimit is how many concepts 'tpg learn' proposes.
+const learnSuggestLimit = 3
+
+var flagLearnSuggest bool
+
+// confidentConcepts returns the names of suggestions at or above the
+// auto-tagging threshold.
+func confidentConcepts(suggestions []db.ConceptSuggestion) []string {
+	var names []string
+	for _, s := range suggestions {
+		if s.Confidence >= db.ConceptSuggestThreshold {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// printConceptSuggestions prints proposed concepts with their confidence.
+func printConceptSuggestions(suggestions []db.ConceptSuggestion) {
+	if len(suggestions) == 0 {
+		fmt.Println("No matching concepts (see 'tpg concepts')")
+		return
+	}
+	fmt.Println("Suggested concepts:")
+	for _, s := range suggestions {
+		marker := " "
+		if s.Confidence >= db.ConceptSuggestThreshold {
+			marker = "*"
+		}
+		fmt.Printf("  %s %-20s %3.0f%%\n", marker, s.Name, s.Confidence*100)
+	}
+	fmt.Println("  (* = applied automatically when -c is omitted)")
+}
+
+func init() {
+	learnCmd.Flags().BoolVar(&flagLearnSuggest, "suggest", false, "Show suggested concepts for the learning without saving it")
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var (
+	flagLintEpic   string
+	flagLintJSON   bool
+	flagLintStrict bool
+)
+
+// LintIssueJSON is one issue in 'tpg lint --json' output.
+type LintIssueJSON struct {
+	Rule    string `json:"rule"`
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check planning quality of open work",
+	Long: `Check open, in-progress, and blocked items against planning quality rules.
+
+Rules (enable or disable each under "lint.rules" in .tpg/config.json):
+  short-description     description shorter than lint.min_description_words
+                        (default: warnings.min_description_words)
+  acceptance-criteria   task has no "Acceptance criteria" section, "done when",
+                        or - [ ] checklist
+  stale-no-logs         open task older than lint.stale_days (default 14) with
+                        no progress logged
+  epic-context          epic has no shared context
+  dep-note              dependency added without --note (off by default)
+
+With --strict the command exits non-zero when any issue is found, for gating
+planning quality in CI.
+
+Examples:
+  tpg lint
+  tpg lint --epic ep-a1b2c3
+  tpg lint --json --strict`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project := ""
+		if flagLintEpic == "" {
+			project, err = resolveProject()
+			if err != nil {
+				return err
+			}
+		}
+		items, err := database.LintScope(project, flagLintEpic)
+		if err != nil {
+			return err
+		}
+		if err := renderTemplatesForItems(items); err != nil {
+			return err
+		}
+
+		config, err := db.LoadConfig()
+		if err != nil {
+			return err
+		}
+		issues, err := database.Lint(items, config.LintOptions())
+		if err != nil {
+			return err
+		}
+
+		if flagLintJSON {
+			out := make([]LintIssueJSON, 0, len(issues))
+			for _, issue := range issues {
+				out = append(out, LintIssueJSON{
+					Rule:    issue.Rule,
+					ID:      issue.Item.ID,
+					Title:   issue.Item.Title,
+					Type:    string(issue.Item.Type),
+					Message: issue.Message,
+				})
+			}
+			b, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(b))
+		} else {
+			printLintIssues(issues, len(items))
+		}
+
+		if flagLintStrict && len(issues) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("lint found %d issue(s)", len(issues))
+		}
+		return nil
+	},
+}
+
+func printLintIssues(issues []db.LintIssue, checked int) {
+	if len(issues) == 0 {
+		fmt.Printf("No issues in %d item(s)\n", checked)
+		return
+	}
+	lastID := ""
+	flagged := 0
+	for _, issue := range issues {
+		if issue.Item.ID != lastID {
+			if lastID != "" {
+				fmt.Println()
+			}
+			fmt.Printf("%s %s\n", issue.Item.ID, issue.Item.Title)
+			lastID = issue.Item.ID
+			flagged++
+		}
+		fmt.Printf("  warning [%s]: %s\n", issue.Rule, issue.Message)
+	}
+	fmt.Printf("\n%d issue(s) in %d of %d item(s)\n", len(issues), flagged, checked)
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&flagLintEpic, "epic", "", "Only check this epic and its descendants")
+	lintCmd.Flags().BoolVar(&flagLintJSON, "json", false, "Output as JSON")
+	lintCmd.Flags().BoolVar(&flagLintStrict, "strict", false, "Exit non-zero when any issue is found")
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if got, err := parseSince("updated-since", ""); err != nil || !got.IsZero() {
+		t.Errorf("empty value = %v, %v; want zero time", got, err)
+	}
+	if got, err := parseSince("updated-since", "today"); err != nil || !got.Equal(midnight) {
+		t.Errorf("today = %v, %v; want %v", got, err, midnight)
+	}
+	got, err := parseSince("created-since", "2026-01-15")
+	if err != nil {
+		t.Fatalf("date: %v", err)
+	}
+	if want := time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("date = %v, want %v", got, want)
+	}
+	got, err = parseSince("done-since", "7d")
+	if err != nil {
+		t.Fatalf("duration: %v", err)
+	}
+	if diff := now.Add(-7 * 24 * time.Hour).Sub(got); diff < -time.Second || diff > time.Second {
+		t.Errorf("7d = %v, want about a week ago", got)
+	}
+	if _, err := parseSince("done-since", "last tuesday"); err == nil {
+		t.Error("expected error for unparseable value")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var logEditCmd = &cobra.Command{
+	Use:   "edit <log-id> <message>",
+	Short: "Replace the text of a log entry",
+	Long: `Replace the text of a log entry, e.g. to fix a typo or scrub a secret
+that was pasted by accident. The old text is not kept in history.
+
+Log IDs are shown by 'tpg show' (#12) and in 'tpg show --format json'.
+Use '-' to read the new text from stdin.
+
+Examples:
+  tpg log edit 12 "Switched to the v2 token endpoint"
+  tpg log edit 12 - < fixed.txt`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseLogID(args[0])
+		if err != nil {
+			return err
+		}
+		message := strings.Join(args[1:], " ")
+		if message == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read from stdin: %w", err)
+			}
+			message = strings.TrimSpace(string(data))
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		log, err := database.EditLog(id, message)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Edited log #%d on %s\n", log.ID, log.ItemID)
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+var logRmCmd = &cobra.Command{
+	Use:     "rm <log-id>",
+	Aliases: []string{"remove"},
+	Short:   "Delete a log entry",
+	Long: `Delete a log entry from a task's audit trail. The deletion is recorded
+in history, but the deleted text is not.
+
+Example:
+  tpg log rm 12`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := parseLogID(args[0])
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		log, err := database.DeleteLog(id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted log #%d from %s\n", log.ID, log.ItemID)
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// parseLogID parses a log ID as printed by 'tpg show', with or without '#'.
+func parseLogID(s string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid log ID: %s (use the number shown by 'tpg show')", s)
+	}
+	return id, nil
+}
+
+func init() {
+	logCmd.AddCommand(logEditCmd)
+	logCmd.AddCommand(logRmCmd)
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagLogsTypes []string
+	flagLogsLimit int
+	flagLogsJSON  bool
+
+	flagLogsSearchSince string
+	flagLogsSearchTypes []string
+	flagLogsSearchLimit int
+	flagLogsSearchJSON  bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <id>",
+	Short: "List an item's log entries, optionally by category",
+	Long: `List the log entries of an item, oldest first.
+
+Each entry has a category: progress, decision, blocker, or note (see
+'tpg log'). --type limits the list to some categories, so the decisions made
+on a task can be read without the routine notes around them.
+
+Examples:
+  tpg logs ts-a1b2c3
+  tpg logs ts-a1b2c3 --type decision
+  tpg logs ts-a1b2c3 --type decision,blocker
+  tpg logs ts-a1b2c3 -n 5 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		categories, err := parseLogCategories(flagLogsTypes)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		logs, err := database.QueryLogs(db.LogQueryOptions{ItemID: id, Categories: categories, Limit: flagLogsLimit})
+		if err != nil {
+			return err
+		}
+
+		if flagLogsJSON {
+			return printLogsJSON(logs)
+		}
+		if len(logs) == 0 {
+			fmt.Println("No logs")
+			return nil
+		}
+		for _, log := range logs {
+			fmt.Printf("[%s] #%d %-8s %s\n", log.CreatedAt.Format("2006-01-02 15:04"), log.ID, log.Category, log.Message)
+		}
+		return nil
+	},
+}
+
+var logsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over the logs of every item",
+	Long: `Search the log entries of all items in the project, best matches first,
+showing the item each entry belongs to. Useful for "where did we decide X?"
+when you don't remember the task.
+
+The query uses SQLite FTS5 syntax: words match anywhere in the message,
+"quoted phrases" match exactly, and AND, OR, NOT and prefix* work as usual.
+
+Examples:
+  tpg logs search sqlite
+  tpg logs search '"connection pool"' --since 7d
+  tpg logs search auth* --type decision
+  tpg logs search cache --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		categories, err := parseLogCategories(flagLogsSearchTypes)
+		if err != nil {
+			return err
+		}
+		since, err := parseSince("since", flagLogsSearchSince)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		query := strings.Join(args, " ")
+		matches, err := database.SearchLogs(project, query, db.LogQueryOptions{
+			Categories: categories,
+			Since:      since,
+			Limit:      flagLogsSearchLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("%w (check the FTS5 query syntax; quote phrases with \")", err)
+		}
+
+		if flagLogsSearchJSON {
+			return printLogMatchesJSON(matches)
+		}
+		if len(matches) == 0 {
+			fmt.Println("No matching logs")
+			return nil
+		}
+		for i, m := range matches {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s %s [%s]  #%d %s, %s\n", m.Item.ID, m.Item.Title, m.Item.Status,
+				m.Log.ID, m.Log.Category, formatTimeAgo(m.Log.CreatedAt))
+			for _, line := range strings.Split(strings.TrimRight(m.Log.Message, "\n"), "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+		return nil
+	},
+}
+
+// LogEntryJSON is a log entry in JSON output.
+type LogEntryJSON struct {
+	ID        int64     `json:"id"`
+	ItemID    string    `json:"item_id"`
+	Category  string    `json:"category"`
+	Message   string    `json:"message"`
+	Progress  *int      `json:"progress,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newLogEntryJSON(log model.Log) LogEntryJSON {
+	return LogEntryJSON{
+		ID:        log.ID,
+		ItemID:    log.ItemID,
+		Category:  string(log.Category),
+		Message:   log.Message,
+		Progress:  log.Progress,
+		CreatedAt: log.CreatedAt,
+	}
+}
+
+func printLogsJSON(logs []model.Log) error {
+	out := make([]LogEntryJSON, len(logs))
+	for i, log := range logs {
+		out[i] = newLogEntryJSON(log)
+	}
+	return printIndentedJSON(out)
+}
+
+// LogMatchJSON is a log search match in JSON output.
+type LogMatchJSON struct {
+	LogEntryJSON
+	ItemTitle  string `json:"item_title"`
+	ItemStatus string `json:"item_status"`
+}
+
+func printLogMatchesJSON(matches []db.LogMatch) error {
+	out := make([]LogMatchJSON, len(matches))
+	for i, m := range matches {
+		out[i] = LogMatchJSON{
+			LogEntryJSON: newLogEntryJSON(m.Log),
+			ItemTitle:    m.Item.Title,
+			ItemStatus:   string(m.Item.Status),
+		}
+	}
+	return printIndentedJSON(out)
+}
+
+func printIndentedJSON(v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// parseLogCategories parses the values of a category filter flag.
+func parseLogCategories(values []string) ([]model.LogCategory, error) {
+	var categories []model.LogCategory
+	for _, v := range values {
+		c, err := model.ParseLogCategory(v)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
+
+// filterLogsByCategory returns the logs in one of categories.
+func filterLogsByCategory(logs []model.Log, categories []model.LogCategory) []model.Log {
+	var filtered []model.Log
+	for _, log := range logs {
+		for _, c := range categories {
+			if log.Category == c {
+				filtered = append(filtered, log)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// logHistoryEntries returns the logs in categories as history entries of type
+// "log", newest first, for 'tpg history --log-type'. It honors the item, time,
+// and limit filters of opts.
+func logHistoryEntries(database *db.DB, opts db.HistoryQueryOptions, categories []model.LogCategory) ([]db.HistoryEntry, error) {
+	limit := opts.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	logs, err := database.QueryLogs(db.LogQueryOptions{
+		ItemID:     opts.ItemID,
+		Categories: categories,
+		Since:      opts.Since,
+		Limit:      max(limit, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]db.HistoryEntry, 0, len(logs))
+	for i := len(logs) - 1; i >= 0; i-- {
+		log := logs[i]
+		entries = append(entries, db.HistoryEntry{
+			ID:        log.ID,
+			ItemID:    log.ItemID,
+			EventType: "log",
+			Changes:   map[string]any{"log_id": log.ID, "category": string(log.Category), "value": log.Message},
+			CreatedAt: log.CreatedAt,
+		})
+	}
+	return entries, nil
+}
+
+func init() {
+	logsCmd.Flags().StringSliceVar(&flagLogsTypes, "type", nil, "Only list logs in these categories (progress, decision, blocker, note)")
+	logsCmd.Flags().IntVarP(&flagLogsLimit, "limit", "n", 0, "Only list the most recent N entries")
+	logsCmd.Flags().BoolVar(&flagLogsJSON, "json", false, "Output as JSON")
+	logsSearchCmd.Flags().StringVar(&flagLogsSearchSince, "since", "", "Only search logs since a time (e.g. '24h', '7d', 2006-01-02, today)")
+	logsSearchCmd.Flags().StringSliceVar(&flagLogsSearchTypes, "type", nil, "Only search logs in these categories (progress, decision, blocker, note)")
+	logsSearchCmd.Flags().IntVarP(&flagLogsSearchLimit, "limit", "n", 0, "Show at most N matches")
+	logsSearchCmd.Flags().BoolVar(&flagLogsSearchJSON, "json", false, "Output as JSON")
+
+	logsCmd.AddCommand(logsSearchCmd)
+	rootCmd.AddCommand(logsCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -18,6 +19,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/editor"
 	"github.com/taxilian/tpg/internal/format"
 	"github.com/taxilian/tpg/internal/model"
 	"github.com/taxilian/tpg/internal/plugin"
@@ -48,11 +50,14 @@ var (
 	flagInitEpicPrefix   string
 	flagStatus           string
 	flagEpic             bool
+	flagLogProgress      int
+	flagLogType          string
 	flagPriority         int
 	flagForce            bool
 	flagDeleteForce      bool
 	flagDeleteRecursive  bool
 	flagCancelForce      bool
+	flagCancelCascade    bool
 	flagParent           string
 	flagBlocks           string
 	flagAfter            string
@@ -63,6 +68,8 @@ var (
 	flagListEpic         string
 	flagBlocking         string
 	flagBlockedBy        string
+	flagDepNote          string
+	flagDepEpic          string
 	flagHasBlockers      bool
 	flagNoBlockers       bool
 	flagEditTitle        string
@@ -87,15 +94,20 @@ var (
 	flagContextJSON      bool
 	flagLearnDetail      string
 	flagLabelsColor      string
+	flagLabelsDryRun     bool
 	flagAddLabels        []string
+	flagAddNeedsApproval bool
 	flagFilterLabels     []string
+	flagFilterFields     []string
 	flagStaleThreshold   string
 	flagDoneOverride     bool
+	flagDoneTemplate     string
 
 	flagDescription      string
 	flagTemplateVarsYAML bool
 	flagPrimeCustomize   bool
 	flagPrimeRender      string
+	flagPrimeFull        bool
 	flagVerbose          bool
 	flagMergeConfirm     bool
 	flagType             string
@@ -106,22 +118,34 @@ var (
 	flagShowWithParent   bool
 	flagShowFormat       string
 	flagShowVars         bool
+	flagShowLogTypes     []string
 	flagDryRun           bool
 	flagReadyEpic        string
+	flagReadyClaim       bool
 	flagListAll          bool
 	flagIdsOnly          bool
 	flagListFlat         bool
+	flagListFormat       string
+	flagSort             string
+	flagCreatedSince     string
+	flagUpdatedSince     string
+	flagDoneSince        string
+	flagReverse          bool
 
 	// Edit command flags
-	flagEditPriority  int
-	flagEditParent    string
-	flagEditAddLabels []string
-	flagEditRmLabels  []string
-	flagEditDesc      string
-	flagEditStatus    string
-	flagEditVars      []string
-	flagEditVarsYAML  bool
-	flagEditParentSet bool // tracks if --parent was explicitly set (to allow empty string)
+	flagEditPriority        int
+	flagEditType            string
+	flagEditWithDescendants bool
+	flagEditParent          string
+	flagEditAddLabels       []string
+	flagEditRmLabels        []string
+	flagEditDesc            string
+	flagEditStatus          string
+	flagEditNeedsApproval   bool
+	flagEditVars            []string
+	flagEditVarsYAML        bool
+	flagEditRankBefore      string
+	flagEditParentSet       bool // tracks if --parent was explicitly set (to allow empty string)
 
 	flagWorktree       bool
 	flagWorktreeBranch string
@@ -129,17 +153,21 @@ var (
 	flagWorktreeAllow  bool
 
 	flagDoctorDryRun bool
+	flagDoctorCheck  string
 	flagResume       bool
 	flagFromYAML     bool
+	flagNoColor      bool
 
 	// history command flags
 	flagHistoryLimit     int
 	flagHistoryAgent     string
 	flagHistorySince     string
 	flagHistoryEventType string
+	flagHistoryLogTypes  []string
 	flagHistoryCleanup   bool
 	flagHistoryDryRun    bool
 	flagHistoryJSON      bool
+	flagHistoryFormat    string
 
 	// closed command flags
 	flagClosedLimit  int
@@ -161,6 +189,9 @@ func openDB() (*db.DB, error) {
 		_ = database.Close()
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
+	applyProjectConfig(database)
+	registerLabelColors(database)
+	templates.SetIndex(templateIndex{database})
 	return database, nil
 }
 
@@ -183,8 +214,8 @@ Database: .tpg/tpg.db (in project root)
 Quick start:
   tpg init
   tpg onboard
-  tpg add "Build feature X" -p myproject
-  tpg ready -p myproject
+  tpg add "Build feature X" --project myproject
+  tpg ready --project myproject
   tpg start <id>
   tpg log <id> "progress: made progress on X"
   tpg done <id> "Completed X, results in Y"
@@ -238,11 +269,13 @@ var epicWorktreeCmd = &cobra.Command{
 If --branch is not specified, a branch name will be auto-generated.
 If --base is not specified, "main" will be used.
 
-Note: This command only stores worktree metadata in tpg. It does NOT create
-the actual git worktree. Run 'git worktree add' separately.
+Note: By default this command only stores worktree metadata in tpg. Pass
+--create to also run 'git worktree add' and then the epic's setup commands
+(see 'tpg epic setup').
 
 Examples:
   tpg epic worktree ep-abc123
+  tpg epic worktree ep-abc123 --create
   tpg epic worktree ep-abc123 --branch feature/my-custom-branch
   tpg epic worktree ep-abc123 --base develop`,
 	Args: cobra.ExactArgs(1),
@@ -253,6 +286,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		epicID := args[0]
 
 		// Verify the item exists and is an epic
@@ -311,6 +348,25 @@ Examples:
 			}
 		}
 
+		if flagWorktreeCreate {
+			if repoRoot == "" {
+				return fmt.Errorf("not in a git repository; cannot create the worktree")
+			}
+			path := filepath.Join(repoRoot, worktreeRoot(config), item.ID)
+			if err := worktree.Add(repoRoot, path, branch, base); err != nil {
+				return fmt.Errorf("failed to create worktree: %w", err)
+			}
+			fmt.Printf("\nCreated worktree at %s\n", location)
+			_ = database.AddLog(item.ID, fmt.Sprintf("Created worktree %s on branch %s", location, branch))
+
+			commands, err := epicSetupCommands(database, item.ID)
+			if err == nil && len(commands) > 0 {
+				err = runEpicSetup(database, item, commands, path)
+			}
+			database.BackupQuiet()
+			return err
+		}
+
 		fmt.Printf("\nWorktree not found. Create it with:\n")
 		fmt.Printf("  git worktree add -b %s %s %s\n", branch, location, base)
 		fmt.Printf("  cd %s\n", location)
@@ -374,17 +430,18 @@ Examples:
 			return err
 		}
 
+		if flagParent != "" {
+			if flagParent, err = resolveItemArg(database, flagParent); err != nil {
+				return err
+			}
+		}
+
 		itemType := model.ItemTypeEpic
 
 		// Generate ID with custom prefix if provided
-		var itemID string
-		if flagPrefix != "" {
-			itemID = model.GenerateIDWithPrefixN(flagPrefix, itemType, model.DefaultIDLength)
-		} else {
-			itemID, err = database.GenerateItemID(itemType)
-			if err != nil {
-				return err
-			}
+		itemID, err := database.GenerateItemIDWithPrefix(flagPrefix, itemType)
+		if err != nil {
+			return err
 		}
 
 		// Initialize from flags
@@ -427,11 +484,13 @@ Examples:
 			onClose = strings.TrimSpace(string(data))
 		}
 
+		title, description := applyTitlePolicy(strings.Join(args, " "), description)
+
 		item := &model.Item{
 			ID:                  itemID,
 			Project:             project,
 			Type:                itemType,
-			Title:               strings.Join(args, " "),
+			Title:               title,
 			Description:         description,
 			Status:              model.StatusOpen,
 			Priority:            flagPriority,
@@ -552,6 +611,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		id := args[0]
 
 		// Verify it's an epic
@@ -566,6 +629,9 @@ Examples:
 		updated := false
 
 		if flagEditTitle != "" {
+			if err := checkTitlePolicy(flagEditTitle); err != nil {
+				return err
+			}
 			if err := database.SetTitle(id, flagEditTitle); err != nil {
 				return err
 			}
@@ -645,6 +711,12 @@ Examples:
 			return err
 		}
 
+		if len(args) == 1 {
+			if args[0], err = resolveItemArg(database, args[0]); err != nil {
+				return err
+			}
+		}
+
 		var items []model.Item
 
 		if len(args) == 1 {
@@ -653,6 +725,25 @@ Examples:
 			if err != nil {
 				return fmt.Errorf("failed to get descendants: %w", err)
 			}
+			order := sortOrderFromFlags()
+			if !order.IsZero() {
+				// Re-query so the requested ordering is applied in SQL;
+				// otherwise keep the sibling order (including manual ranks)
+				descendantIDs := make(map[string]bool, len(descendants))
+				for _, d := range descendants {
+					descendantIDs[d.ID] = true
+				}
+				all, err := database.ListItemsFiltered(db.ListFilter{Sort: order})
+				if err != nil {
+					return err
+				}
+				descendants = descendants[:0]
+				for _, d := range all {
+					if descendantIDs[d.ID] {
+						descendants = append(descendants, d)
+					}
+				}
+			}
 			// Filter out done/canceled by default
 			for _, d := range descendants {
 				if d.Status != model.StatusDone && d.Status != model.StatusCanceled {
@@ -664,6 +755,7 @@ Examples:
 			filter := db.ListFilter{
 				Project: project,
 				Type:    string(model.ItemTypeEpic),
+				Sort:    sortOrderFromFlags(),
 			}
 			var err error
 			items, err = database.ListItemsFiltered(filter)
@@ -693,6 +785,9 @@ Examples:
 		if err := database.PopulateItemLabels(items); err != nil {
 			return err
 		}
+		if err := database.PopulateItemProgress(items); err != nil {
+			return err
+		}
 
 		printItemsTree(items)
 		return nil
@@ -748,20 +843,18 @@ Examples:
 			return err
 		}
 
-		oldID := args[0]
+		oldID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
 		title := strings.Join(args[1:], " ")
 
 		itemType := model.ItemTypeEpic
 
 		// Generate ID with custom prefix if provided
-		var newItemID string
-		if flagPrefix != "" {
-			newItemID = model.GenerateIDWithPrefixN(flagPrefix, itemType, model.DefaultIDLength)
-		} else {
-			newItemID, err = database.GenerateItemID(itemType)
-			if err != nil {
-				return err
-			}
+		newItemID, err := database.GenerateItemIDWithPrefix(flagPrefix, itemType)
+		if err != nil {
+			return err
 		}
 
 		// Initialize from flags
@@ -804,6 +897,8 @@ Examples:
 			onClose = strings.TrimSpace(string(data))
 		}
 
+		title, description = applyTitlePolicy(title, description)
+
 		newItem := &model.Item{
 			ID:                  newItemID,
 			Project:             project,
@@ -856,6 +951,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		epicID := args[0]
 
 		// Verify the item exists and is an epic
@@ -902,7 +1001,11 @@ Examples:
 
 		// Show closing instructions if any
 		if item.ClosingInstructions != "" {
-			fmt.Printf("\nClosing instructions:\n%s\n", item.ClosingInstructions)
+			text, err := renderClosingInstructions(database, item)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nClosing instructions:\n%s\n", text)
 		}
 
 		// Print worktree cleanup instructions if applicable
@@ -920,6 +1023,10 @@ Examples:
 				}
 			}
 
+			if warning := epicDriftWarning(item, config); warning != "" {
+				fmt.Printf("\n⚠ %s\n", warning)
+			}
+
 			fmt.Printf("\nWorktree cleanup:\n")
 
 			// Determine merge target
@@ -967,17 +1074,12 @@ Examples:
   tpg epic set-merged ep-abc123 --confirm`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if !flagMergeConfirm {
-			fi, _ := os.Stdin.Stat()
-			if fi == nil || (fi.Mode()&os.ModeCharDevice) == 0 {
+		if !flagMergeConfirm && !assumeYes() {
+			if !stdinIsTerminal() {
 				return fmt.Errorf("refusing to mark epic as merged without --confirm flag\n" +
 					"Double-check that the git merge was completed correctly before re-running with --confirm")
 			}
-			fmt.Print("Mark epic as merged? This should only be done after git merge is complete. [y/N]: ")
-			var response string
-			fmt.Scanln(&response)
-			response = strings.ToLower(strings.TrimSpace(response))
-			if response != "y" && response != "yes" {
+			if !confirm("Mark epic as merged? This should only be done after git merge is complete.") {
 				return fmt.Errorf("cancelled")
 			}
 		}
@@ -988,7 +1090,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
-		epicID := args[0]
+		epicID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
 
 		item, err := database.GetItem(epicID)
 		if err != nil {
@@ -1036,7 +1141,11 @@ Examples:
 		fmt.Printf("  Children: %d/%d done\n", done, total)
 
 		if item.ClosingInstructions != "" {
-			fmt.Printf("\nClosing instructions:\n%s\n", item.ClosingInstructions)
+			text, err := renderClosingInstructions(database, item)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nClosing instructions:\n%s\n", text)
 		}
 
 		base := item.WorktreeBase
@@ -1099,7 +1208,21 @@ Examples:
     Detailed description here
   priority: 1
   parent: ep-abc123
-  EOF`,
+  EOF
+
+Project defaults:
+  The add_defaults config sets labels, priority, and type for fields left out,
+  and can require --parent. Use --no-defaults to bypass them:
+    {"add_defaults": {"backend": {"labels": ["api"], "priority": 1, "require_parent": true}}}
+  The label_routes config puts items without --parent under an epic by
+  label, logging the routing on the new item:
+    {"label_routes": {"docs": "ep-docs"}}
+
+Policies:
+  The policy config adds planning rules. They are warnings unless
+  policy.strict is true, which makes them errors (including short
+  descriptions, below policy.min_description_words):
+    {"policy": {"strict": true, "require_parent": true, "require_priority": true, "min_description_words": 20}}`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate --type flag early
@@ -1118,8 +1241,23 @@ Examples:
 			return err
 		}
 
+		if flagParent != "" {
+			if flagParent, err = resolveItemArg(database, flagParent); err != nil {
+				return err
+			}
+		}
+
+		priorityDefaulted, routedBy, err := applyAddDefaults(cmd, database, project)
+		if err != nil {
+			return err
+		}
+		prioritySet := priorityDefaulted || cmd.Flags().Changed("priority")
+
 		// Handle template instantiation
 		if flagTemplateID != "" {
+			if flagAddNeedsApproval {
+				return fmt.Errorf("--needs-approval cannot be used with --template (set it on the steps with 'tpg edit')")
+			}
 			// Handle template vars from stdin (YAML)
 			varPairs := flagTemplateVars
 			if flagTemplateVarsYAML {
@@ -1142,7 +1280,17 @@ Examples:
 				parentType = model.ItemType(flagType)
 			}
 
-			parentID, err := instantiateTemplate(database, project, strings.Join(args, " "), flagTemplateID, varPairs, flagPriority, parentType)
+			if config, err := db.LoadConfig(); err == nil {
+				violations := addPolicyViolations(config, parentType, flagParent, "", prioritySet, false)
+				if err := enforceAddPolicy(config, violations); err != nil {
+					return err
+				}
+			}
+
+			if err := checkTitlePolicy(strings.Join(args, " ")); err != nil {
+				return err
+			}
+			parentID, err := instantiateTemplate(database, project, strings.Join(args, " "), flagTemplateID, varPairs, flagPriority, parentType, flagParent)
 			if err != nil {
 				return err
 			}
@@ -1177,6 +1325,10 @@ Examples:
 					return err
 				}
 			}
+			if err := logLabelRoute(database, parentID, routedBy); err != nil {
+				return err
+			}
+			warnEpicScopeGrowth(database, parentID)
 			fmt.Println(parentID)
 			database.BackupQuiet()
 			return nil
@@ -1188,14 +1340,9 @@ Examples:
 		}
 
 		// Generate ID with custom prefix if provided
-		var itemID string
-		if flagPrefix != "" {
-			itemID = model.GenerateIDWithPrefixN(flagPrefix, itemType, model.DefaultIDLength)
-		} else {
-			itemID, err = database.GenerateItemID(itemType)
-			if err != nil {
-				return err
-			}
+		itemID, err := database.GenerateItemIDWithPrefix(flagPrefix, itemType)
+		if err != nil {
+			return err
 		}
 
 		// Handle description from stdin or flag
@@ -1208,14 +1355,22 @@ Examples:
 			description = strings.TrimSpace(string(data))
 		}
 
+		title, description := applyTitlePolicy(strings.Join(args, " "), description)
+
+		// Custom types may define their own default priority
+		priority := flagPriority
+		if info, ok := model.LookupItemType(itemType); ok && !info.Builtin && !cmd.Flags().Changed("priority") && !priorityDefaulted {
+			priority = info.DefaultPriority
+		}
+
 		item := &model.Item{
 			ID:          itemID,
 			Project:     project,
 			Type:        itemType,
-			Title:       strings.Join(args, " "),
+			Title:       title,
 			Description: description,
 			Status:      model.StatusOpen,
-			Priority:    flagPriority,
+			Priority:    priority,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
@@ -1227,7 +1382,7 @@ Examples:
 			fmt.Printf("  Title:       %s\n", item.Title)
 			fmt.Printf("  Type:        %s\n", item.Type)
 			fmt.Printf("  Project:     %s\n", item.Project)
-			fmt.Printf("  Priority:    %d\n", item.Priority)
+			fmt.Printf("  Priority:    %d (%s)\n", item.Priority, model.PriorityName(item.Priority))
 			if flagParent != "" {
 				fmt.Printf("  Parent:      %s\n", flagParent)
 			}
@@ -1240,6 +1395,9 @@ Examples:
 			if len(flagAddLabels) > 0 {
 				fmt.Printf("  Labels:      %s\n", strings.Join(flagAddLabels, ", "))
 			}
+			if flagAddNeedsApproval {
+				fmt.Println("  Approval:    needed ('tpg approve' after 'tpg done')")
+			}
 			if item.Description != "" {
 				desc := item.Description
 				if len(desc) > 100 {
@@ -1253,8 +1411,15 @@ Examples:
 
 		config, _ := db.LoadConfig()
 
+		if config != nil {
+			violations := addPolicyViolations(config, itemType, flagParent, description, prioritySet, true)
+			if err := enforceAddPolicy(config, violations); err != nil {
+				return err
+			}
+		}
+
 		// Warn if description is very short (including empty) - configurable
-		if config != nil && config.ShortDescriptionWarningEnabled() {
+		if config != nil && !config.Policy.Strict && config.ShortDescriptionWarningEnabled() {
 			minWords := config.GetMinDescriptionWords()
 			if countWords(description) < minWords {
 				fmt.Fprintf(os.Stderr, "\nWARNING: This description is very short (%d words, recommend %d+). Does it include\nall context needed for someone not part of the main discussion to understand the task?\nConsider extending with: tpg edit %s --desc\n", countWords(description), minWords, item.ID)
@@ -1297,6 +1462,16 @@ Examples:
 			}
 		}
 
+		if flagAddNeedsApproval {
+			if err := database.SetNeedsApproval(item.ID, true); err != nil {
+				return err
+			}
+		}
+		if err := logLabelRoute(database, item.ID, routedBy); err != nil {
+			return err
+		}
+		warnEpicScopeGrowth(database, item.ID)
+
 		fmt.Println(item.ID)
 
 		// Backup after successful mutation
@@ -1364,7 +1539,10 @@ Examples:
 			return err
 		}
 
-		oldID := args[0]
+		oldID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
 		title := strings.Join(args[1:], " ")
 
 		// Determine item type
@@ -1377,14 +1555,9 @@ Examples:
 		}
 
 		// Generate ID with custom prefix if provided
-		var newItemID string
-		if flagPrefix != "" {
-			newItemID = model.GenerateIDWithPrefixN(flagPrefix, itemType, model.DefaultIDLength)
-		} else {
-			newItemID, err = database.GenerateItemID(itemType)
-			if err != nil {
-				return err
-			}
+		newItemID, err := database.GenerateItemIDWithPrefix(flagPrefix, itemType)
+		if err != nil {
+			return err
 		}
 
 		// Initialize from flags
@@ -1427,6 +1600,8 @@ Examples:
 			onClose = strings.TrimSpace(string(data))
 		}
 
+		title, description = applyTitlePolicy(title, description)
+
 		newItem := &model.Item{
 			ID:                  newItemID,
 			Project:             project,
@@ -1512,7 +1687,8 @@ func applyYAMLFlagsFromData(cmd *cobra.Command, yamlData map[string]interface{})
 }
 
 // setFlagFromYAML sets a flag's value from a YAML-parsed interface{} value.
-// Handles type conversion for string, int, bool, and []string (StringArray).
+// Handles type conversion for string, int, priority, bool, and []string
+// (StringArray). yamlFlagTypes must list the same types.
 // Also marks the flag as Changed so cmd.Flags().Changed() returns true.
 func setFlagFromYAML(flag *pflag.Flag, value interface{}) error {
 	if value == nil {
@@ -1549,6 +1725,25 @@ func setFlagFromYAML(flag *pflag.Flag, value interface{}) error {
 		}
 		return err
 
+	case "priority":
+		// A number or a name like "high"
+		switch v := value.(type) {
+		case int:
+			err = flag.Value.Set(fmt.Sprintf("%d", v))
+		case int64:
+			err = flag.Value.Set(fmt.Sprintf("%d", v))
+		case float64:
+			err = flag.Value.Set(fmt.Sprintf("%d", int(v)))
+		case string:
+			err = flag.Value.Set(v)
+		default:
+			return fmt.Errorf("expected priority number or name, got %T", value)
+		}
+		if err == nil {
+			flag.Changed = true
+		}
+		return err
+
 	case "bool":
 		boolVal, ok := value.(bool)
 		if !ok {
@@ -1624,12 +1819,23 @@ func countWords(s string) int {
 	return len(strings.Fields(s))
 }
 
-// validateTypeFlag validates that --type is either "task" or "epic"
+// validateTypeFlag validates that --type is "task", "epic", or a custom type
+// registered with 'tpg types add'.
 func validateTypeFlag(typeValue string) error {
-	if typeValue != "" && typeValue != "task" && typeValue != "epic" {
-		return fmt.Errorf("--type must be \"task\" or \"epic\"\nUse labels for categorization: tpg add --label <type> \"title\"")
+	if typeValue == "" {
+		return nil
 	}
-	return nil
+	registerConfiguredTypes()
+	if model.ItemType(typeValue).IsValid() {
+		return nil
+	}
+	names := typeNames()
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	allowed := strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+	return fmt.Errorf("--type must be %s\nUse labels for categorization: tpg add --label <type> \"title\"\nOr register a new type: tpg types add %s", allowed, typeValue)
 }
 
 // generateWorktreeBranch generates a branch name from epic ID and title.
@@ -1726,11 +1932,11 @@ func detectWorktreeState() (*worktree.Context, map[string]string) {
 }
 
 func displayWorktreePath(repoRoot, path string) string {
-	if path == "" || repoRoot == "" {
+	if !worktree.IsWithinDir(path, repoRoot) {
 		return path
 	}
 	rel, err := filepath.Rel(repoRoot, path)
-	if err != nil || strings.HasPrefix(rel, "..") {
+	if err != nil {
 		return path
 	}
 	return rel
@@ -1827,22 +2033,40 @@ Examples:
   tpg list -f                     # Flat list (no hierarchy)
   tpg list --flat                 # Same as -f
   tpg list --epic ep-abc          # Tree view of an epic's descendants
-  tpg list -p myproject
+  tpg list --project myproject
   tpg list --status open
   tpg list --status done          # Explicitly show done items
-  tpg list -p myproject --status blocked
+  tpg list --project myproject --status blocked
   tpg list --parent ep-abc123
   tpg list --type epic
   tpg list --blocking ts-xyz789
   tpg list --blocked-by ts-abc123
   tpg list --has-blockers
   tpg list --no-blockers
-  tpg list -l bug -l urgent`,
+  tpg list -l bug -l urgent
+  tpg list --field reviewer=alice
+  tpg list --sort updated         # Recently touched first
+  tpg list --sort created --reverse   # Newest first
+  tpg list --updated-since today  # Touched since midnight
+  tpg list --done-since 7d        # Finished in the last week
+  tpg list --created-since 2026-01-15
+  tpg list --all --format jsonl | jq -r 'select(.priority == 1) | .id'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate --type flag early
 		if err := validateTypeFlag(flagListType); err != nil {
 			return err
 		}
+		jsonl, err := checkStreamFormat(flagListFormat)
+		if err != nil {
+			return err
+		}
+		if jsonl && flagIdsOnly {
+			return fmt.Errorf("--format jsonl cannot be combined with --ids-only")
+		}
+		fieldFilters, err := parseFieldFilters(flagFilterFields)
+		if err != nil {
+			return err
+		}
 
 		database, err := openDB()
 		if err != nil {
@@ -1856,7 +2080,8 @@ Examples:
 		}
 
 		var status *model.Status
-		statusExplicitlySet := flagStatus != ""
+		// --done-since only matches done items, so don't hide them
+		statusExplicitlySet := flagStatus != "" || flagDoneSince != ""
 		if flagStatus != "" {
 			s := model.Status(flagStatus)
 			if !s.IsValid() {
@@ -1875,50 +2100,72 @@ Examples:
 			HasBlockers: flagHasBlockers,
 			NoBlockers:  flagNoBlockers,
 			Labels:      flagFilterLabels,
+			Fields:      fieldFilters,
+			Sort:        sortOrderFromFlags(),
 		}
-
-		items, err := database.ListItemsFiltered(filter)
-		if err != nil {
+		if filter.CreatedSince, err = parseSince("created-since", flagCreatedSince); err != nil {
 			return err
 		}
-
-		// Filter out done/canceled items by default (unless --all or --status is set)
-		if !flagListAll && !statusExplicitlySet {
-			filtered := make([]model.Item, 0, len(items))
-			for _, item := range items {
-				if item.Status != model.StatusDone && item.Status != model.StatusCanceled {
-					filtered = append(filtered, item)
-				}
-			}
-			items = filtered
+		if filter.UpdatedSince, err = parseSince("updated-since", flagUpdatedSince); err != nil {
+			return err
+		}
+		if filter.DoneSince, err = parseSince("done-since", flagDoneSince); err != nil {
+			return err
 		}
 
+		hideClosed := !flagListAll && !statusExplicitlySet
+
 		// Filter to epic descendants if --epic is set
+		var descendantIDs map[string]bool
 		if flagListEpic != "" {
+			if flagListEpic, err = resolveItemArg(database, flagListEpic); err != nil {
+				return err
+			}
 			descendants, err := database.GetDescendants(flagListEpic)
 			if err != nil {
 				return fmt.Errorf("failed to get descendants of epic %s: %w", flagListEpic, err)
 			}
-			// Create a map of descendant IDs for fast lookup
-			descendantIDs := make(map[string]bool)
+			descendantIDs = make(map[string]bool, len(descendants))
 			for _, d := range descendants {
 				descendantIDs[d.ID] = true
 			}
-			// Filter items to only those in the descendant set
-			filtered := make([]model.Item, 0, len(items))
-			for _, item := range items {
-				if descendantIDs[item.ID] {
-					filtered = append(filtered, item)
-				}
+		}
+
+		// Done/canceled items are hidden by default (unless --all or --status is set)
+		keep := func(item model.Item) bool {
+			if hideClosed && (item.Status == model.StatusDone || item.Status == model.StatusCanceled) {
+				return false
 			}
-			items = filtered
+			return descendantIDs == nil || descendantIDs[item.ID]
+		}
+
+		if jsonl {
+			return streamItemsJSONL(database, filter, keep)
 		}
 
+		items, err := database.ListItemsFiltered(filter)
+		if err != nil {
+			return err
+		}
+		filtered := make([]model.Item, 0, len(items))
+		for _, item := range items {
+			if keep(item) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+
 		// Populate labels for display (skip if ids-only)
 		if !flagIdsOnly {
 			if err := database.PopulateItemLabels(items); err != nil {
 				return err
 			}
+			if err := database.PopulateItemProgress(items); err != nil {
+				return err
+			}
+			if err := database.PopulateItemAliases(items); err != nil {
+				return err
+			}
 			if err := renderTemplatesForItems(items); err != nil {
 				return err
 			}
@@ -1944,13 +2191,25 @@ A task is "ready" when:
   - Status is "open" (not in_progress, blocked, or done)
   - All dependencies are "done"
 
-Results are sorted by priority (1=high first).
+Results are sorted by priority (1=high first). Labels listed under
+"label_weights" in .tpg/config.json act as service classes: items whose
+labels add up to a higher weight always come first (e.g. "hotfix": 100),
+and negative weights sink items below everything else (e.g. "backlog": -100).
+
+When nothing is ready, the unmet dependencies that gate the most open tasks
+are listed, along with what each of them is still waiting on.
+
+With --claim, the top ready task is started for $AGENT_ID instead of listed.
+Selecting and starting happen in one conditional update, so two agents
+claiming at once never get the same task. Exits 1 when nothing is ready.
 
 Examples:
   tpg ready
-  tpg ready -p myproject
+  tpg ready --claim               # Start the top ready task
+  tpg ready --project myproject
   tpg ready -l bug
-  tpg ready --epic ep-abc123`,
+  tpg ready --epic ep-abc123
+  tpg ready --sort created        # Oldest ready tasks first`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
@@ -1969,61 +2228,34 @@ Examples:
 			_ = database.RecordAgentProjectAccess(agentCtx.ID, project)
 		}
 
+		if flagReadyClaim {
+			return claimReadyTask(cmd, database, project, agentCtx)
+		}
+
 		var items []model.Item
 
 		// Check if filtering by epic
 		if flagReadyEpic != "" {
-			// Verify the epic exists
-			epic, err := database.GetItem(flagReadyEpic)
-			if err != nil {
-				return err
-			}
-			if epic.Type != model.ItemTypeEpic {
-				return fmt.Errorf("%s is not an epic", flagReadyEpic)
-			}
-
-			items, err = database.ReadyItemsForEpic(flagReadyEpic)
+			epic, tasks, err := readyEpicTasks(database, flagReadyEpic, project)
 			if err != nil {
 				return err
 			}
-
-			// Filter by project if specified and exclude child epics (only show tasks)
-			var filtered []model.Item
-			for _, item := range items {
-				// Skip child epics - only show tasks
-				if item.Type == model.ItemTypeEpic {
-					continue
-				}
-				if project != "" && item.Project != project {
-					continue
-				}
-				filtered = append(filtered, item)
-			}
-			items = filtered
+			items = tasks
 
 			if len(items) == 0 {
 				fmt.Println("No ready tasks for this epic")
+				if err := printBlockingChains(database, epic.ID, project); err != nil {
+					return err
+				}
 			} else {
 				// Show epic title in header with counts
-				totalActive, _ := database.CountActiveDescendantsForEpic(flagReadyEpic)
+				totalActive, _ := database.CountActiveDescendantsForEpic(epic.ID)
 				fmt.Printf("%s %s (%d / %d tasks ready)\n", epic.ID, epic.Title, len(items), totalActive)
 
-				// Populate labels for display
-				if err := database.PopulateItemLabels(items); err != nil {
-					return err
-				}
 				if err := renderTemplatesForItems(items); err != nil {
 					return err
 				}
 
-				// Sort by priority
-				sort.Slice(items, func(i, j int) bool {
-					if items[i].Priority != items[j].Priority {
-						return items[i].Priority < items[j].Priority
-					}
-					return items[i].Title < items[j].Title
-				})
-
 				// Print tasks with tree connectors
 				for i, task := range items {
 					connector := "├──"
@@ -2039,7 +2271,7 @@ Examples:
 			}
 		} else {
 			// Use ReadyItemsWithCounts for tree display with epic counts
-			result, err := database.ReadyItemsWithCounts(project, flagFilterLabels)
+			result, err := database.ReadyItemsWithCounts(project, flagFilterLabels, sortOrderFromFlags())
 			if err != nil {
 				return err
 			}
@@ -2047,6 +2279,9 @@ Examples:
 
 			if len(items) == 0 {
 				fmt.Println("No ready tasks")
+				if err := printBlockingChains(database, "", project); err != nil {
+					return err
+				}
 			} else {
 				// Populate labels for display
 				if err := database.PopulateItemLabels(items); err != nil {
@@ -2056,7 +2291,7 @@ Examples:
 					return err
 				}
 
-				printReadyTreeWithEpicCounts(result)
+				printReadyTreeWithEpicCounts(result, database.LabelWeights(), !sortOrderFromFlags().IsZero())
 			}
 		}
 
@@ -2090,8 +2325,12 @@ Displays:
 For templated tasks, the description is rendered from the current template.
 A notice appears if the template changed since instantiation.
 
-Example:
-  tpg show ts-a1b2c3`,
+--log-type limits the logs shown to some categories (progress, decision,
+blocker, note).
+
+Examples:
+  tpg show ts-a1b2c3
+  tpg show ts-a1b2c3 --log-type decision,blocker`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -2100,6 +2339,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		item, err := database.GetItem(args[0])
 		if err != nil {
 			return err
@@ -2120,6 +2363,17 @@ Example:
 			item.Labels = append(item.Labels, l.Name)
 		}
 
+		fields, err := database.GetFields(args[0])
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			item.Fields = fields
+		}
+		if item.NeedsApproval, err = database.NeedsApproval(args[0]); err != nil {
+			return err
+		}
+
 		logs, err := database.GetLogs(args[0])
 		if err != nil {
 			return err
@@ -2158,12 +2412,20 @@ Example:
 				}
 			}
 			if mismatch {
-				templateNotice = "Template has changed since instantiation"
+				templateNotice = fmt.Sprintf("Template has changed since instantiation (run 'tpg template resync %s' to update)", item.ID)
 			}
 		}
 
 		latestProgress := latestProgressLog(logs)
+		item.Progress = latestProgressPercent(logs)
 		blockers := filterBlockers(depStatuses)
+		if len(flagShowLogTypes) > 0 {
+			categories, err := parseLogCategories(flagShowLogTypes)
+			if err != nil {
+				return err
+			}
+			logs = filterLogsByCategory(logs, categories)
+		}
 
 		// Gather additional data based on flags
 		var children []model.Item
@@ -2228,7 +2490,11 @@ Example:
 		case "markdown":
 			return printItemMarkdown(item, logs, deps, blockers, latestProgress, concepts, templateNotice, children, parentChain, depChain, worktreeInfo)
 		default:
-			printItemDetail(item, logs, deps, blockers, latestProgress, concepts, templateNotice, flagShowVars, worktreeInfo, epicPath, sharedContext, incompleteChildren)
+			agentName := ""
+			if item.AgentID != nil {
+				agentName = agentDisplayName(database.AgentNames(), *item.AgentID)
+			}
+			printItemDetail(item, logs, deps, blockers, latestProgress, concepts, templateNotice, flagShowVars, worktreeInfo, epicPath, sharedContext, incompleteChildren, agentName)
 			if flagShowWithParent && len(parentChain) > 0 {
 				fmt.Printf("\nParent Chain:\n")
 				for _, parent := range parentChain {
@@ -2247,6 +2513,9 @@ Example:
 					fmt.Printf("  %s depends on %s [%s]\n", edge.ItemID, edge.DependsOnID, edge.DependsOnStatus)
 				}
 			}
+			if n, err := database.CountTranscripts(item.ID); err == nil && n > 0 {
+				fmt.Printf("\nTranscripts: %d (tpg transcript list %s)\n", n, item.ID)
+			}
 			return nil
 		}
 	},
@@ -2260,6 +2529,9 @@ var historyCmd = &cobra.Command{
 Without arguments, shows recent history events globally (last 50 by default).
 With a task ID, shows history for that specific task only.
 
+Use --log-type to list log entries in some categories (progress, decision,
+blocker, note) instead of events.
+
 Use --cleanup to run history cleanup instead of viewing history.
 
 Examples:
@@ -2270,7 +2542,9 @@ Examples:
   tpg history --since 24h          # Events in last 24 hours
   tpg history --since 7d           # Events in last 7 days
   tpg history --event-type status_changed  # Filter by event type
+  tpg history --log-type decision  # Decisions logged on any task
   tpg history --json               # Output as JSON
+  tpg history -n -1 --format jsonl # Stream every event, one per line
   tpg history --cleanup            # Run cleanup
   tpg history --cleanup --dry-run  # Preview cleanup`,
 	Args: cobra.MaximumNArgs(1),
@@ -2282,6 +2556,13 @@ Examples:
 		if flagHistoryCleanup && len(args) > 0 {
 			return fmt.Errorf("--cleanup cannot be combined with a task ID (cleanup is global)")
 		}
+		jsonl, err := checkStreamFormat(flagHistoryFormat)
+		if err != nil {
+			return err
+		}
+		if jsonl && flagHistoryJSON {
+			return fmt.Errorf("--format jsonl cannot be combined with --json")
+		}
 
 		database, err := openDB()
 		if err != nil {
@@ -2298,11 +2579,14 @@ Examples:
 		opts := db.HistoryQueryOptions{}
 
 		if len(args) > 0 {
-			opts.ItemID = args[0]
+			if opts.ItemID, err = resolveItemArg(database, args[0]); err != nil {
+				return err
+			}
 		}
 
+		names := database.AgentNames()
 		if flagHistoryAgent != "" {
-			opts.ActorID = flagHistoryAgent
+			opts.ActorID = resolveAgentID(names, flagHistoryAgent)
 		}
 
 		if flagHistorySince != "" {
@@ -2317,19 +2601,44 @@ Examples:
 			opts.EventTypes = []string{flagHistoryEventType}
 		}
 
-		if flagHistoryLimit > 0 {
+		if flagHistoryLimit != 0 {
 			opts.Limit = flagHistoryLimit
 		}
 
-		// Query history
-		entries, err := database.GetHistory(opts)
-		if err != nil {
-			return err
+		var entries []db.HistoryEntry
+		if len(flagHistoryLogTypes) > 0 {
+			if opts.ActorID != "" || len(opts.EventTypes) > 0 {
+				return fmt.Errorf("--log-type cannot be combined with --agent or --event-type")
+			}
+			categories, err := parseLogCategories(flagHistoryLogTypes)
+			if err != nil {
+				return err
+			}
+			if entries, err = logHistoryEntries(database, opts, categories); err != nil {
+				return err
+			}
+			if jsonl {
+				out := newJSONLStream(os.Stdout)
+				for _, e := range entries {
+					if err := out.write(newHistoryEventJSON(e, names)); err != nil {
+						_ = out.close()
+						return err
+					}
+				}
+				return out.close()
+			}
+		} else {
+			if jsonl {
+				return streamHistoryJSONL(database, opts, names)
+			}
+			if entries, err = database.GetHistory(opts); err != nil {
+				return err
+			}
 		}
 
 		// Handle JSON output
 		if flagHistoryJSON {
-			return printHistoryJSON(entries)
+			return printHistoryJSON(entries, names)
 		}
 
 		// Handle empty results
@@ -2339,7 +2648,7 @@ Examples:
 		}
 
 		// Print table format
-		printHistoryTable(entries)
+		printHistoryTable(entries, names)
 		return nil
 	},
 }
@@ -2362,6 +2671,27 @@ func parseDuration(s string) (time.Duration, error) {
 	return 0, fmt.Errorf("invalid duration format: %s (use e.g., '24h', '7d')", s)
 }
 
+// parseSince turns a --*-since flag value into a cutoff time. It accepts a
+// duration ("24h", "7d"), a local date ("2006-01-02"), or "today". An empty
+// value returns the zero time (no filter).
+func parseSince(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	now := time.Now()
+	if value == "today" {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	d, err := parseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: use a duration (24h, 7d), a date (2006-01-02), or 'today'", flag, value)
+	}
+	return now.Add(-d), nil
+}
+
 // runHistoryCleanup executes history cleanup
 func runHistoryCleanup(database *db.DB, dryRun bool) error {
 	result, err := database.CleanupHistory(db.CleanupHistoryOptions{DryRun: dryRun})
@@ -2387,28 +2717,10 @@ func runHistoryCleanup(database *db.DB, dryRun bool) error {
 }
 
 // printHistoryJSON outputs history entries as JSON
-func printHistoryJSON(entries []db.HistoryEntry) error {
-	type jsonEntry struct {
-		ID        int64          `json:"id"`
-		ItemID    string         `json:"item_id"`
-		EventType string         `json:"event_type"`
-		ActorID   string         `json:"actor_id,omitempty"`
-		ActorType string         `json:"actor_type,omitempty"`
-		Changes   map[string]any `json:"changes,omitempty"`
-		CreatedAt string         `json:"created_at"`
-	}
-
-	jsonEntries := make([]jsonEntry, len(entries))
+func printHistoryJSON(entries []db.HistoryEntry, agentNames map[string]string) error {
+	jsonEntries := make([]historyEventJSON, len(entries))
 	for i, e := range entries {
-		jsonEntries[i] = jsonEntry{
-			ID:        e.ID,
-			ItemID:    e.ItemID,
-			EventType: e.EventType,
-			ActorID:   e.ActorID,
-			ActorType: e.ActorType,
-			Changes:   e.Changes,
-			CreatedAt: e.CreatedAt.Format(time.RFC3339),
-		}
+		jsonEntries[i] = newHistoryEventJSON(e, agentNames)
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -2417,17 +2729,21 @@ func printHistoryJSON(entries []db.HistoryEntry) error {
 }
 
 // printHistoryTable outputs history entries as a table
-func printHistoryTable(entries []db.HistoryEntry) {
+func printHistoryTable(entries []db.HistoryEntry, agentNames map[string]string) {
 	// Header
 	fmt.Printf("%-18s %-18s %-10s %-15s %s\n", "TIME", "TYPE", "ITEM", "ACTOR", "CHANGES")
 
 	for _, e := range entries {
 		timeStr := e.CreatedAt.Format("2006-01-02 15:04")
-		actor := truncateActor(e.ActorID)
+		actor := truncateActor(agentDisplayName(agentNames, e.ActorID))
 		changes := formatChanges(e.Changes)
+		eventType := e.EventType
+		if category, ok := e.Changes["category"].(string); ok && eventType == "log" {
+			eventType += " (" + category + ")"
+		}
 
 		fmt.Printf("%-18s %-18s %-10s %-15s %s\n",
-			timeStr, e.EventType, e.ItemID, actor, changes)
+			timeStr, eventType, e.ItemID, actor, changes)
 	}
 }
 
@@ -2580,6 +2896,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		// Get item to record project access
 		item, err := database.GetItem(args[0])
 		if err != nil {
@@ -2603,7 +2923,7 @@ An epic is a container that organizes related tasks. Work on its children instea
 		if resuming && !flagResume {
 			agentInfo := ""
 			if item.AgentID != nil && *item.AgentID != "" {
-				agentInfo = fmt.Sprintf(" (claimed by %s)", *item.AgentID)
+				agentInfo = fmt.Sprintf(" (claimed by %s)", agentDisplayName(database.AgentNames(), *item.AgentID))
 			}
 			return fmt.Errorf("task %s is already in progress%s. Use --resume to take over or continue work", item.ID, agentInfo)
 		}
@@ -2618,6 +2938,7 @@ An epic is a container that organizes related tasks. Work on its children instea
 		if err := database.UpdateStatus(args[0], model.StatusInProgress, agentCtx, false); err != nil {
 			return err
 		}
+		_ = database.SetCurrentTask(db.CurrentSession(), item.ID)
 
 		// Auto-log the start event for timeline
 		logMsg := "Started"
@@ -2662,6 +2983,14 @@ An epic is a container that organizes related tasks. Work on its children instea
 					fmt.Fprintf(os.Stderr, "    cd %s\n", worktreeInfo.Location)
 				}
 			}
+			if warning := epicDriftWarning(rootEpic, config); warning != "" {
+				fmt.Fprintf(os.Stderr, "\n⚠ %s\n", warning)
+			}
+			if worktreeInfo != nil && worktreeInfo.InWorktree {
+				if err := offerEpicSetup(database, rootEpic); err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -2678,7 +3007,9 @@ The results message is required and should provide context for future work:
   - For investigation: findings, decisions made, next steps
   - For fixes: root cause, solution applied, verification steps
 
-Blocked if the task has unmet dependencies (use --override to force).
+Blocked if the task has unmet dependencies (use --override to force). The error
+lists each one with its status, the chain of deps leading to any work already
+in progress, and the exact override command.
 
 Use stdin with '-' for detailed results (recommended):
 
@@ -2711,19 +3042,44 @@ Examples:
   # Override dependency check
   tpg done ts-a1b2c3 --override "Work superseded by different approach"
 
+  # Fill in a results skeleton in $TPG_EDITOR (picked by label/type)
+  tpg done ts-a1b2c3 --template
+  tpg done ts-a1b2c3 --template=investigation
+
+Tasks added with --needs-approval are not completed: they move to
+pending_review with the results, and 'tpg approve <id>' completes them.
+
 Note: Completing a task with zero log entries will trigger a warning.
 Consider logging progress milestones before marking done.`,
-	Args: cobra.MinimumNArgs(2),
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagDoneTemplate == "" && len(args) < 2 {
+			return fmt.Errorf("results message is required (or use --template to fill one in an editor)")
+		}
+		if flagDoneTemplate != "" && len(args) > 1 {
+			return fmt.Errorf("--template cannot be combined with a results message")
+		}
+
 		database, err := openDB()
 		if err != nil {
 			return err
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		id := args[0]
 		results := strings.TrimSpace(strings.Join(args[1:], " "))
 
+		if flagDoneTemplate != "" {
+			results, err = resultsFromTemplate(database, id, flagDoneTemplate)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Handle stdin
 		if results == "-" {
 			data, err := io.ReadAll(os.Stdin)
@@ -2743,7 +3099,8 @@ Consider logging progress milestones before marking done.`,
 				return err
 			}
 			if hasUnmet {
-				return fmt.Errorf("cannot mark done with unmet dependencies (use --override to force)")
+				cmd.SilenceUsage = true
+				return unmetDepsError(database, id, results)
 			}
 		}
 
@@ -2767,15 +3124,33 @@ Triggers that should always produce a log entry:
 `, id, id)
 		}
 
+		needsApproval, err := database.NeedsApproval(id)
+		if err != nil {
+			return err
+		}
+		if needsApproval {
+			if err := database.SubmitForReview(id, results); err != nil {
+				return err
+			}
+			_ = database.ClearCurrentTaskIf(db.CurrentSession(), id)
+			_ = database.AddLog(id, "Submitted for approval")
+			fmt.Printf("Submitted %s for approval; it is done once someone runs 'tpg approve %s'\n", id, id)
+			database.BackupQuiet()
+			return nil
+		}
+
 		agentCtx := db.GetAgentContext()
+		openEpics := openAncestorEpics(database, id)
 		if err := database.CompleteItem(id, results, agentCtx); err != nil {
 			return err
 		}
+		_ = database.ClearCurrentTaskIf(db.CurrentSession(), id)
 
 		// Auto-log completion for timeline
 		_ = database.AddLog(id, "Completed")
 
 		fmt.Printf("Completed %s\n", id)
+		announceCompletedEpics(database, openEpics)
 
 		// Prompt reflection
 		fmt.Println(`
@@ -2801,6 +3176,11 @@ Example:
   tpg cancel ts-a1b2c3
   tpg cancel ts-a1b2c3 "Requirements changed, no longer needed"
   tpg cancel ts-a1b2c3 --force   # Cancel even if other tasks depend on it
+  tpg cancel ep-a1b2c3 --cascade "Approach abandoned"   # Cancel epic and all open descendants
+
+With --cascade, every open, in-progress, or blocked descendant is canceled
+with the same reason, and any open items outside the subtree that depend on
+a canceled item are listed so they can be re-planned.
 
 See also: 'tpg delete' to remove a task entirely (no history preserved).`,
 	Args: cobra.MinimumNArgs(1),
@@ -2811,12 +3191,31 @@ See also: 'tpg delete' to remove a task entirely (no history preserved).`,
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		id := args[0]
 
 		agentCtx := db.GetAgentContext()
+		if flagCancelCascade {
+			reason := strings.Join(args[1:], " ")
+			result, err := database.CancelCascade(id, reason, agentCtx, flagCancelForce)
+			if err != nil {
+				return err
+			}
+			printCancelCascade(id, reason, result)
+			announceCompletedEpics(database, result.CompletedEpics)
+			printCascadeDependents(id, result)
+			database.BackupQuiet()
+			return nil
+		}
+
+		openEpics := openAncestorEpics(database, id)
 		if err := database.UpdateStatus(id, model.StatusCanceled, agentCtx, flagCancelForce); err != nil {
 			return err
 		}
+		_ = database.ClearCurrentTaskIf(db.CurrentSession(), id)
 
 		if len(args) > 1 {
 			reason := strings.Join(args[1:], " ")
@@ -2827,6 +3226,7 @@ See also: 'tpg delete' to remove a task entirely (no history preserved).`,
 		} else {
 			fmt.Printf("Canceled %s\n", id)
 		}
+		announceCompletedEpics(database, openEpics)
 
 		// Backup after successful mutation
 		database.BackupQuiet()
@@ -2835,6 +3235,33 @@ See also: 'tpg delete' to remove a task entirely (no history preserved).`,
 	},
 }
 
+func printCancelCascade(id, reason string, result *db.CancelCascadeResult) {
+	descendants := len(result.Canceled) - 1
+	msg := fmt.Sprintf("Canceled %s", id)
+	if descendants > 0 {
+		msg += fmt.Sprintf(" and %d descendant(s)", descendants)
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	fmt.Println(msg)
+	for _, cid := range result.Canceled[1:] {
+		fmt.Printf("  %s\n", cid)
+	}
+}
+
+// printCascadeDependents warns about open items outside a canceled subtree
+// that depend on something in it.
+func printCascadeDependents(id string, result *db.CancelCascadeResult) {
+	if len(result.ExternalDependents) > 0 {
+		fmt.Printf("\nWARNING: %d item(s) outside %s depend on canceled items:\n", len(result.ExternalDependents), id)
+		for _, d := range result.ExternalDependents {
+			fmt.Printf("  %s %q (%s) depends on %s\n", d.ItemID, d.Title, d.Status, d.DependsOn)
+		}
+		fmt.Println("Re-plan them with 'tpg dep <id> remove <canceled-id>', or cancel them too.")
+	}
+}
+
 var reopenCmd = &cobra.Command{
 	Use:   "reopen <id> [reason]",
 	Short: "Reopen a closed task, setting it back to open",
@@ -2855,7 +3282,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
-		id := args[0]
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
 
 		agentCtx := db.GetAgentContext()
 		if err := database.UpdateStatus(id, model.StatusOpen, agentCtx, false); err != nil {
@@ -2884,6 +3314,13 @@ var staleCmd = &cobra.Command{
 	Short: "List stale in-progress tasks",
 	Long: `List in-progress tasks with no updates within a threshold.
 
+By default each task uses the threshold configured for its type or priority
+under "stale" in .tpg/config.json (5m when unset):
+
+  {"stale": {"default": "10m", "priority": {"1": "30m", "3": "1d"}, "type": {"research": "2d"}}}
+
+--threshold applies one threshold to every task instead.
+
 Example:
   tpg stale
   tpg stale --threshold 30m`,
@@ -2899,22 +3336,32 @@ Example:
 			return err
 		}
 
-		threshold, err := time.ParseDuration(flagStaleThreshold)
-		if err != nil {
-			return fmt.Errorf("invalid threshold: %w", err)
-		}
-		cutoff := time.Now().Add(-threshold)
-
-		items, err := database.StaleItems(project, cutoff)
-		if err != nil {
-			return err
-		}
-		if len(items) == 0 {
-			fmt.Println("No stale tasks")
-			return nil
+		var items []model.Item
+		if cmd.Flags().Changed("threshold") {
+			threshold, err := parseDuration(flagStaleThreshold)
+			if err != nil {
+				return fmt.Errorf("invalid threshold: %w", err)
+			}
+			items, err = database.StaleItems(project, time.Now().Add(-threshold))
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				fmt.Println("No stale tasks")
+				return nil
+			}
+			fmt.Printf("Stale tasks (no updates in %s):\n\n", threshold)
+		} else {
+			items, err = database.StaleItemsNow(project, time.Now())
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				fmt.Println("No stale tasks")
+				return nil
+			}
+			fmt.Printf("Stale tasks (no updates within their threshold):\n\n")
 		}
-
-		fmt.Printf("Stale tasks (no updates in %s):\n\n", threshold)
 		for _, item := range items {
 			age := time.Since(item.UpdatedAt)
 			fmt.Printf("%s [%s] %s (%s since update)\n", item.ID, item.Status, item.Title, formatDuration(age))
@@ -2940,6 +3387,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		if err := database.TouchItem(args[0]); err != nil {
 			return err
 		}
@@ -2986,6 +3437,10 @@ If you must use block (e.g., external dependency with no tpg task), re-run with
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		id := args[0]
 		reason := strings.Join(args[1:], " ")
 
@@ -3011,6 +3466,7 @@ This action cannot be undone.
 
 By default, deletion is blocked if:
 - Other tasks depend on this item (use --force to remove dependencies)
+- Learnings link to it (use --force to unlink them; the learnings are kept)
 - The item has children (use -r to delete recursively)
 
 Examples:
@@ -3028,6 +3484,10 @@ See also: 'tpg cancel' to close a task while preserving history.`,
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		if err := database.DeleteItem(args[0], flagDeleteForce, flagDeleteRecursive); err != nil {
 			return err
 		}
@@ -3037,12 +3497,14 @@ See also: 'tpg cancel' to close a task while preserving history.`,
 }
 
 var (
-	flagCleanDone     bool
-	flagCleanCanceled bool
-	flagCleanLogs     bool
-	flagCleanVacuum   bool
-	flagCleanAll      bool
-	flagCleanDays     int
+	flagCleanDone      bool
+	flagCleanCanceled  bool
+	flagCleanLogs      bool
+	flagCleanVacuum    bool
+	flagCleanAll       bool
+	flagCleanDays      int
+	flagCleanSave      string
+	flagCleanPruneRefs bool
 )
 
 var cleanCmd = &cobra.Command{
@@ -3050,8 +3512,19 @@ var cleanCmd = &cobra.Command{
 	Short: "Clean up old tasks and compact database",
 	Long: `Remove old done/canceled tasks and compact the database.
 
-By default, requires confirmation before deleting. Use --dry-run to preview
-what would be deleted, or --force to skip confirmation.
+Lists the ID and title of every task it will delete, then asks for
+confirmation. Use --dry-run to only see the list, or --force to skip
+confirmation. Tasks that still have children are kept until their children
+are gone.
+
+Tasks that an open item depends on, or that a learning links to, are kept
+too, with a warning naming the references, since deleting them would break
+the dependency chain or the learning's link. --prune-refs deletes them
+anyway: the dependencies are removed and the learnings unlinked.
+
+--save writes the tasks, with their logs, labels, fields, and dependencies,
+to a JSONL file before deleting them. Re-importing it with
+'tpg import jsonl <file>' undoes the clean.
 
 Examples:
   tpg clean --done              # Remove done tasks older than 30 days
@@ -3059,6 +3532,8 @@ Examples:
   tpg clean --all               # Remove old done+canceled and vacuum
   tpg clean --all --days 7      # More aggressive: 7 day threshold
   tpg clean --dry-run --all     # Preview what would be deleted
+  tpg clean --all --save cleaned.jsonl  # Keep a copy to undo with
+  tpg clean --done --prune-refs # Also delete still-referenced tasks
   tpg clean --vacuum            # Just compact the database`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -3081,19 +3556,20 @@ Examples:
 
 		cutoff := time.Now().AddDate(0, 0, -flagCleanDays)
 
-		// Count what would be deleted
-		var doneCount, canceledCount, orphanedLogCount int
+		// List what would be deleted
+		var doneItems, canceledItems []model.Item
+		var orphanedLogCount int
 		var err2 error
 
 		if flagCleanDone {
-			doneCount, err2 = database.CountOldItems(cutoff, model.StatusDone)
+			doneItems, err2 = database.DeletableOldItems(cutoff, model.StatusDone)
 			if err2 != nil {
 				return err2
 			}
 		}
 
 		if flagCleanCanceled {
-			canceledCount, err2 = database.CountOldItems(cutoff, model.StatusCanceled)
+			canceledItems, err2 = database.DeletableOldItems(cutoff, model.StatusCanceled)
 			if err2 != nil {
 				return err2
 			}
@@ -3106,19 +3582,37 @@ Examples:
 			}
 		}
 
+		// Keep tasks that open items still depend on or learnings link to,
+		// unless --prune-refs says to detach them
+		refList, err := database.ItemReferences(append(itemIDs(doneItems), itemIDs(canceledItems)...))
+		if err != nil {
+			return err
+		}
+		refs := make(map[string]db.ItemRefs, len(refList))
+		for _, r := range refList {
+			refs[r.ItemID] = r
+		}
+		var kept, keptCanceled []model.Item
+		if !flagCleanPruneRefs {
+			doneItems, kept = splitReferenced(doneItems, refs)
+			canceledItems, keptCanceled = splitReferenced(canceledItems, refs)
+			kept = append(kept, keptCanceled...)
+		}
+
 		// Show what would be deleted
-		hasWork := doneCount > 0 || canceledCount > 0 || orphanedLogCount > 0 || flagCleanVacuum
+		hasWork := len(doneItems) > 0 || len(canceledItems) > 0 || orphanedLogCount > 0 || flagCleanVacuum
 		if !hasWork {
+			printKeptItems(kept, refs)
 			fmt.Println("Nothing to clean up")
 			return nil
 		}
 
 		fmt.Println("Found:")
 		if flagCleanDone {
-			fmt.Printf("  %d done tasks older than %d days\n", doneCount, flagCleanDays)
+			printCleanItems(doneItems, "done", flagCleanDays, refs)
 		}
 		if flagCleanCanceled {
-			fmt.Printf("  %d canceled tasks older than %d days\n", canceledCount, flagCleanDays)
+			printCleanItems(canceledItems, "canceled", flagCleanDays, refs)
 		}
 		if flagCleanLogs && orphanedLogCount > 0 {
 			fmt.Printf("  %d orphaned log entries\n", orphanedLogCount)
@@ -3126,6 +3620,10 @@ Examples:
 		if flagCleanVacuum {
 			fmt.Println("  Database will be compacted")
 		}
+		if len(kept) > 0 {
+			fmt.Println()
+			printKeptItems(kept, refs)
+		}
 
 		// Dry run - just show what would happen
 		if flagDryRun {
@@ -3135,10 +3633,8 @@ Examples:
 
 		// Confirm unless --force
 		if !flagForce {
-			fmt.Print("\nDelete these items? [y/N]: ")
-			var response string
-			fmt.Scanln(&response)
-			if response != "y" && response != "Y" {
+			fmt.Println()
+			if !confirm("Delete these items?") {
 				fmt.Println("Aborted")
 				return nil
 			}
@@ -3146,6 +3642,15 @@ Examples:
 
 		fmt.Println()
 
+		// Save the items before they go, so the clean can be undone
+		if flagCleanSave != "" && len(doneItems)+len(canceledItems) > 0 {
+			doomed := append(append([]model.Item{}, doneItems...), canceledItems...)
+			if err := saveCleanItems(database, flagCleanSave, doomed); err != nil {
+				return err
+			}
+			fmt.Printf("Saved %d items to %s (undo with 'tpg import jsonl %s')\n", len(doomed), flagCleanSave, flagCleanSave)
+		}
+
 		// Get database size before vacuum
 		var sizeBefore int64
 		if flagCleanVacuum {
@@ -3153,16 +3658,16 @@ Examples:
 		}
 
 		// Perform deletions
-		if flagCleanDone && doneCount > 0 {
-			deleted, err := database.DeleteOldItems(cutoff, model.StatusDone)
+		if len(doneItems) > 0 {
+			deleted, err := database.DeleteItemsByID(itemIDs(doneItems))
 			if err != nil {
 				return err
 			}
 			fmt.Printf("Deleted %d done tasks\n", deleted)
 		}
 
-		if flagCleanCanceled && canceledCount > 0 {
-			deleted, err := database.DeleteOldItems(cutoff, model.StatusCanceled)
+		if len(canceledItems) > 0 {
+			deleted, err := database.DeleteItemsByID(itemIDs(canceledItems))
 			if err != nil {
 				return err
 			}
@@ -3218,6 +3723,15 @@ Progress logs: Start message with "progress:" to mark major milestones.
 Progress logs appear in the "Latest Update" section of tpg show, visible
 to agents resuming work. Use them to communicate state to your future self.
 
+Each entry has a category: progress, decision, blocker, or note. A message
+starting with "decision:", "blocker:", or "progress:" is filed under it;
+anything else is a note. --type sets the category and adds the prefix.
+Find entries by category with 'tpg logs <id> --type decision'.
+
+Use --progress N to also record how far along the task is (0-100). The
+latest percentage is drawn as a progress bar in show, list, and the TUI
+while the task is in progress.
+
 For detailed progress updates, use stdin with '-' (recommended):
 
 Examples:
@@ -3227,6 +3741,12 @@ Examples:
   # Progress milestone (visible in Latest Update)
   tpg log ts-a1b2c3 "progress: Auth complete, starting validation"
 
+  # Progress milestone with a percentage
+  tpg log ts-a1b2c3 --progress 60 "core logic done"
+
+  # Record a decision (same as starting the message with "decision:")
+  tpg log ts-a1b2c3 --type decision "Use JWT over sessions: stateless API"
+
   # Detailed progress via stdin (recommended for handoffs)
   tpg log ts-a1b2c3 - <<EOF
   progress: JWT implementation complete, moving to refresh tokens
@@ -3243,7 +3763,11 @@ Examples:
   ## Issues found
   - Public key loading needs caching (performance)
   - Error messages could be more specific
-  EOF`,
+  EOF
+
+Fix or remove an entry by its ID (shown by 'tpg show' as #N):
+  tpg log edit 12 "corrected text"
+  tpg log rm 12`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -3252,6 +3776,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		id := args[0]
 		message := strings.Join(args[1:], " ")
 
@@ -3264,7 +3792,31 @@ Examples:
 			message = strings.TrimSpace(string(data))
 		}
 
-		if err := database.AddLog(id, message); err != nil {
+		category := model.LogCategoryOf(message)
+		if flagLogType != "" {
+			if category, err = model.ParseLogCategory(flagLogType); err != nil {
+				return err
+			}
+		}
+
+		if cmd.Flags().Changed("progress") {
+			if flagLogType != "" && category != model.LogCategoryProgress {
+				return fmt.Errorf("--progress records a progress log; it cannot be combined with --type %s", category)
+			}
+			if !isProgressMessage(message) {
+				message = "progress: " + message
+			}
+			if err := database.AddProgressLog(id, flagLogProgress, message); err != nil {
+				return err
+			}
+			fmt.Printf("Logged to %s (%d%%)\n", id, flagLogProgress)
+			return nil
+		}
+
+		if category != model.LogCategoryNote && model.LogCategoryOf(message) != category {
+			message = string(category) + ": " + message
+		}
+		if err := database.AddCategorizedLog(id, category, message); err != nil {
 			return err
 		}
 		fmt.Printf("Logged to %s\n", id)
@@ -3293,8 +3845,8 @@ Use 'tpg dep <id> list' to see dependencies for a specific task.
 Use 'tpg ready' to see only unblocked tasks available to start.
 
 Examples:
-  tpg graph              # Show full dependency graph
-  tpg graph -p myproject # Show graph for specific project`,
+  tpg graph                       # Show full dependency graph
+  tpg graph --project myproject   # Show graph for specific project`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
@@ -3333,6 +3885,7 @@ Displays:
   - All child tasks with status in tree format
   - Ready tasks highlighted (unblocked and can be started)
   - Dependency chains and blockers
+  - Dependencies on items outside the epic, with the epic they belong to
 
 Examples:
   tpg plan ep-abc123      # Show full plan for epic
@@ -3345,6 +3898,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		epicID := args[0]
 		epic, err := database.GetItem(epicID)
 		if err != nil {
@@ -3413,12 +3970,22 @@ Examples:
 		// Calculate statistics
 		stats := calculateEpicStats(descendants)
 
+		external, err := planExternalDeps(database, epicID, descendants, depInfo)
+		if err != nil {
+			return err
+		}
+
+		scope, err := database.EpicScope(epicID)
+		if err != nil {
+			return err
+		}
+
 		if flagContextJSON {
-			return printPlanJSON(epic, descendants, childrenMap, depInfo, blockedBy, readyTasks, stats)
+			return printPlanJSON(epic, descendants, childrenMap, depInfo, blockedBy, readyTasks, stats, scope, external)
 		}
 
 		// Print epic header
-		fmt.Printf("\n%s [%s] %s\n", epic.ID, epic.Status, epic.Title)
+		fmt.Printf("\n%s [%s] %s\n", epic.ID, format.Status(string(epic.Status), string(epic.Status)), epic.Title)
 		fmt.Println(strings.Repeat("=", len(epic.ID)+len(epic.Status)+len(epic.Title)+6))
 		if epic.Description != "" {
 			fmt.Printf("\n%s\n", epic.Description)
@@ -3429,6 +3996,9 @@ Examples:
 			stats.Done, stats.Total, stats.CompletionPct)
 		fmt.Printf("   Open: %d | In Progress: %d | Blocked: %d | Done: %d | Canceled: %d\n",
 			stats.Open, stats.InProgress, stats.Blocked, stats.Done, stats.Canceled)
+		if scope.Started() {
+			fmt.Printf("   Scope: %s\n", formatEpicScope(scope))
+		}
 
 		// Print tree view of all tasks
 		fmt.Println("\n📋 Task Tree:")
@@ -3452,7 +4022,7 @@ Examples:
 				if len(item.Labels) > 0 {
 					labels = formatLabels(item.Labels) + " "
 				}
-				fmt.Printf("   %s [pri %d] %s%s\n", item.ID, item.Priority, labels, item.Title)
+				fmt.Printf("   %s %s %s%s\n", item.ID, format.Priority(item.Priority, fmt.Sprintf("[pri %d]", item.Priority)), labels, item.Title)
 			}
 		}
 
@@ -3473,10 +4043,12 @@ Examples:
 				blockersFound = true
 			}
 			for _, dep := range unmetDeps {
-				fmt.Printf("   %s blocked by %s [%s] %s\n", item.ID, dep.ID, dep.Status, dep.Title)
+				fmt.Printf("   %s blocked by %s [%s] %s\n", item.ID, dep.ID, format.Status(dep.Status, dep.Status), dep.Title)
 			}
 		}
 
+		printPlanExternalDeps(external)
+
 		fmt.Println()
 		return nil
 	},
@@ -3500,7 +4072,7 @@ func calculateEpicStats(tasks []model.Item) epicStats {
 		switch t.Status {
 		case model.StatusOpen:
 			s.Open++
-		case model.StatusInProgress:
+		case model.StatusInProgress, model.StatusPendingReview:
 			s.InProgress++
 		case model.StatusBlocked:
 			s.Blocked++
@@ -3557,7 +4129,8 @@ func printPlanTree(database *db.DB, parentID string, prefix string, childrenMap
 			}
 		}
 
-		fmt.Printf("%s%s %s%s [%s] %s%s\n", prefix, branch, statusIndicator, child.ID, child.Status, child.Title, depIndicator)
+		fmt.Printf("%s%s %s%s [%s] %s%s\n", prefix, branch, statusIndicator, child.ID,
+			format.Status(string(child.Status), string(child.Status)), child.Title, depIndicator)
 
 		// Recurse into children
 		childPrefix := prefix
@@ -3582,6 +4155,15 @@ type PlanJSON struct {
 	Tasks         []PlanTaskJSON     `json:"tasks"`
 	ReadyTasks    []string           `json:"ready_tasks"`
 	BlockedChains []BlockedChainJSON `json:"blocked_chains,omitempty"`
+	ExternalDeps  []ExternalDepJSON  `json:"external_deps,omitempty"`
+	Scope         *EpicScopeJSON     `json:"scope,omitempty"`
+}
+
+// EpicScopeJSON is an epic's growth since it started (see db.EpicScope)
+type EpicScopeJSON struct {
+	StartedAt string `json:"started_at"`
+	Initial   int    `json:"initial_tasks"`
+	Added     int    `json:"added_tasks"`
 }
 
 // EpicSummaryJSON is a minimal epic representation
@@ -3619,7 +4201,7 @@ type BlockedChainJSON struct {
 }
 
 // printPlanJSON outputs the plan as JSON
-func printPlanJSON(epic *model.Item, descendants []model.Item, childrenMap map[string][]model.Item, depInfo map[string][]db.DepStatus, blockedBy map[string][]db.DepStatus, readyTasks map[string]bool, stats epicStats) error {
+func printPlanJSON(epic *model.Item, descendants []model.Item, childrenMap map[string][]model.Item, depInfo map[string][]db.DepStatus, blockedBy map[string][]db.DepStatus, readyTasks map[string]bool, stats epicStats, scope db.EpicScope, external []*planExternalDep) error {
 	output := PlanJSON{
 		Epic: EpicSummaryJSON{
 			ID:          epic.ID,
@@ -3627,8 +4209,12 @@ func printPlanJSON(epic *model.Item, descendants []model.Item, childrenMap map[s
 			Status:      string(epic.Status),
 			Description: epic.Description,
 		},
-		Stats:      stats,
-		ReadyTasks: []string{},
+		Stats:        stats,
+		ReadyTasks:   []string{},
+		ExternalDeps: externalDepsJSON(external),
+	}
+	if scope.Started() {
+		output.Scope = &EpicScopeJSON{StartedAt: scope.StartedAt.Format(time.RFC3339), Initial: scope.Initial, Added: scope.Added}
 	}
 
 	// Build task list
@@ -3724,13 +4310,14 @@ Includes:
   - Recently completed tasks
   - Currently in-progress tasks
   - Blocked tasks with reasons
+  - Tasks unblocked in the last 24 hours
   - Ready tasks by priority (limited to 10 by default)
 
 Use --all to show all ready tasks.
 
 Examples:
   tpg status
-  tpg status -p myproject
+  tpg status --project myproject
   tpg status --all
   tpg status -l bug`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -3762,6 +4349,7 @@ Examples:
 		_ = database.PopulateItemLabels(report.InProgItems)
 		_ = database.PopulateItemLabels(report.BlockedItems)
 		_ = database.PopulateItemLabels(report.ReadyItems)
+		_ = database.PopulateItemLabels(report.NewlyUnblocked)
 
 		cache := &templateCache{}
 		if err := renderTemplatesWithCache(cache, report.RecentDone); err != nil {
@@ -3776,6 +4364,9 @@ Examples:
 		if err := renderTemplatesWithCache(cache, report.ReadyItems); err != nil {
 			return err
 		}
+		if err := renderTemplatesWithCache(cache, report.NewlyUnblocked); err != nil {
+			return err
+		}
 
 		printStatusReport(report, flagStatusAll)
 		return nil
@@ -3793,10 +4384,17 @@ Displays:
   - Ready count (tasks available to work on)
   - Epics in progress count
   - Stale tasks count (in-progress with no updates >5min)
+  - Sparklines of tasks completed per day and the open task count
+
+With --epics, also shows a 0-100 health score for each open epic, least
+healthy first. Stale tasks, a high share of blocked work, days without
+activity, and ready tasks nobody has started all lower the score.
 
 Examples:
   tpg summary
-  tpg summary -p myproject`,
+  tpg summary --project myproject
+  tpg summary --days 7
+  tpg summary --epics`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
@@ -3815,6 +4413,23 @@ Examples:
 		}
 
 		printSummaryStats(stats)
+
+		if flagSummaryDays > 0 {
+			trend, err := database.DailyTrend(project, flagSummaryDays, time.Now())
+			if err != nil {
+				return err
+			}
+			printSummaryTrend(trend)
+		}
+
+		if flagSummaryEpics {
+			now := time.Now()
+			report, err := database.EpicHealthReport(project, now)
+			if err != nil {
+				return err
+			}
+			printEpicHealth(report, now)
+		}
 		return nil
 	},
 }
@@ -3842,6 +4457,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		id := args[0]
 		text := strings.Join(args[1:], " ")
 
@@ -3874,8 +4493,28 @@ SELECTION:
 FIELD CHANGES:
   --title, --desc        Single item only (opens editor if no field flags)
   --priority, --parent   Can apply to multiple items
+  --type                 Can apply to multiple items (see 'tpg types')
   --add-label, --remove-label   Can apply to multiple items
-  --status               Requires --force (prefer start/done/block/cancel commands)
+  --status               Follows the status graph (prefer start/done/block/cancel)
+  --needs-approval       Require 'tpg approve' to finish (--needs-approval=false clears)
+  --rank-before          Order among siblings (multiple items keep their given order)
+
+With --with-descendants, --parent moves the item together with its whole
+subtree after validating it first: the new parent must not be inside the
+subtree, no dependency may link a moved item to one of its new ancestors, and
+in-progress work may not switch worktree branch without --force.
+
+--rank-before sets a soft presentation order among siblings, which must share
+a parent. 'tpg plan' and 'tpg epic list' show ranked siblings first, in rank
+order; 'tpg ready' uses rank to break ties between equal priorities.
+Dependencies remain the way to express hard ordering.
+
+--status may only move along the status graph: open, in_progress, and
+blocked move freely among themselves and to done, canceled, or
+pending_review; pending_review goes back to work or is canceled (approval
+completes it); done and canceled only reopen. --force makes any other change and records it as a
+"status_forced" history event. Add moves to the graph with status_transitions
+in the config.
 
 For epic-specific fields (--context, --on-close), use 'tpg epic edit'.
 
@@ -3883,13 +4522,17 @@ Examples:
   tpg edit ts-abc                            # Open description in editor
   tpg edit ts-abc --title "New title"        # Change title
   tpg edit ts-abc --priority 1               # Set high priority
+  tpg edit ts-abc --type bug                 # Change type
   tpg edit ts-abc ts-def --priority 2        # Set priority on multiple
   tpg edit ts-abc --parent ep-xyz            # Move under epic
   tpg edit ts-abc --parent ""                # Remove from parent
+  tpg edit ep-abc --parent ep-xyz --with-descendants   # Move a whole subtree (validated)
+  tpg edit ts-abc --rank-before ts-def      # Show ts-abc just before ts-def
   tpg edit ts-abc --add-label bug            # Add label
   tpg edit --select-label bug --priority 1   # All items with 'bug' label
   tpg edit --select-epic ep-xyz --add-label done   # All descendants of epic
-  tpg edit ts-abc --status open --force      # Force status change
+  tpg edit ts-abc --status open              # Reopen
+  tpg edit ts-abc --status blocked --force   # Change a done task anyway
   tpg edit ts-abc --dry-run --priority 1     # Preview changes`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -3902,6 +4545,7 @@ Examples:
 		// Check if --parent was explicitly set (to distinguish "" from unset)
 		flagEditParentSet = cmd.Flags().Changed("parent")
 		flagEditDescSet := cmd.Flags().Changed("desc")
+		needsApprovalSet := cmd.Flags().Changed("needs-approval")
 
 		// Determine if any select flags are set
 		hasFilters := flagStatus != "" || flagListParent != "" || flagListType != "" ||
@@ -3954,7 +4598,11 @@ Examples:
 			}
 		} else if len(args) > 0 {
 			// Use explicit IDs
-			for _, id := range args {
+			for _, arg := range args {
+				id, err := resolveItemArg(database, arg)
+				if err != nil {
+					return err
+				}
 				item, err := database.GetItem(id)
 				if err != nil {
 					return fmt.Errorf("item not found: %s", id)
@@ -3975,15 +4623,24 @@ Examples:
 			}
 		}
 
-		// Check if --status requires --force
-		if flagEditStatus != "" && !flagForce {
-			return fmt.Errorf("--status requires --force (prefer: tpg start/done/block/cancel)")
+		if flagEditTitle != "" {
+			if err := checkTitlePolicy(flagEditTitle); err != nil {
+				return err
+			}
+		}
+
+		if err := validateTypeFlag(flagEditType); err != nil {
+			return err
+		}
+
+		if flagEditWithDescendants && !flagEditParentSet {
+			return fmt.Errorf("--with-descendants requires --parent")
 		}
 
 		// Check if any field flags are set
-		hasFieldFlags := flagEditTitle != "" || flagEditPriority != 0 || flagEditParentSet ||
+		hasFieldFlags := flagEditTitle != "" || flagEditPriority != 0 || flagEditParentSet || flagEditType != "" ||
 			len(flagEditAddLabels) > 0 || len(flagEditRmLabels) > 0 || flagEditDescSet ||
-			flagEditStatus != "" || len(flagEditVars) > 0 || flagEditVarsYAML
+			flagEditStatus != "" || len(flagEditVars) > 0 || flagEditVarsYAML || flagEditRankBefore != "" || needsApprovalSet
 
 		// If no field flags and single item, open editor for description
 		if !hasFieldFlags && len(items) == 1 {
@@ -3992,7 +4649,13 @@ Examples:
 
 		// If no field flags and multiple items, error
 		if !hasFieldFlags {
-			return fmt.Errorf("no field flags specified for %d items (use --title, --priority, --parent, --add-label, --remove-label, --desc, --status, or --var)", len(items))
+			return fmt.Errorf("no field flags specified for %d items (use --title, --priority, --type, --parent, --add-label, --remove-label, --desc, --status, --needs-approval, --rank-before, or --var)", len(items))
+		}
+
+		if flagEditRankBefore != "" {
+			if flagEditRankBefore, err = resolveItemArg(database, flagEditRankBefore); err != nil {
+				return err
+			}
 		}
 
 		// Read description from stdin if needed
@@ -4023,9 +4686,15 @@ Examples:
 				fmt.Printf("  title: %q\n", flagEditTitle)
 			}
 			if flagEditPriority != 0 {
-				fmt.Printf("  priority: %d\n", flagEditPriority)
+				fmt.Printf("  priority: %d (%s)\n", flagEditPriority, model.PriorityName(flagEditPriority))
+			}
+			if flagEditType != "" {
+				fmt.Printf("  type: %s\n", flagEditType)
 			}
 			if flagEditParentSet {
+				if flagEditWithDescendants {
+					fmt.Println("  move: with all descendants")
+				}
 				if flagEditParent == "" {
 					fmt.Println("  parent: (remove)")
 				} else {
@@ -4042,7 +4711,20 @@ Examples:
 				fmt.Printf("  description: (%d chars)\n", len(descValue))
 			}
 			if flagEditStatus != "" {
-				fmt.Printf("  status: %s (forced)\n", flagEditStatus)
+				forced := ""
+				for _, item := range items {
+					if !model.CanTransition(item.Status, model.Status(flagEditStatus)) {
+						forced = " (forced for " + item.ID + ")"
+						break
+					}
+				}
+				fmt.Printf("  status: %s%s\n", flagEditStatus, forced)
+			}
+			if needsApprovalSet {
+				fmt.Printf("  needs approval: %v\n", flagEditNeedsApproval)
+			}
+			if flagEditRankBefore != "" {
+				fmt.Printf("  rank: before %s\n", flagEditRankBefore)
 			}
 			if flagEditVarsYAML {
 				fmt.Printf("  vars: (from YAML stdin)\n")
@@ -4069,7 +4751,21 @@ Examples:
 					return fmt.Errorf("failed to set priority for %s: %w", item.ID, err)
 				}
 			}
-			if flagEditParentSet {
+			if flagEditType != "" {
+				if err := database.SetType(item.ID, model.ItemType(flagEditType)); err != nil {
+					return fmt.Errorf("failed to set type for %s: %w", item.ID, err)
+				}
+			}
+			if flagEditParentSet && flagEditWithDescendants {
+				result, err := database.MoveSubtree(item.ID, flagEditParent, flagForce)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Moved %s with %d descendant(s)\n", item.ID, len(result.Moved)-1)
+				if result.WorktreeChanged() {
+					fmt.Printf("  Worktree branch: %s -> %s\n", db.BranchOrNone(result.OldWorktree), db.BranchOrNone(result.NewWorktree))
+				}
+			} else if flagEditParentSet {
 				if flagEditParent == "" {
 					// Remove parent
 					if err := database.ClearParent(item.ID); err != nil {
@@ -4081,6 +4777,11 @@ Examples:
 					}
 				}
 			}
+			if flagEditRankBefore != "" {
+				if err := database.RankBefore(item.ID, flagEditRankBefore); err != nil {
+					return fmt.Errorf("failed to rank %s: %w", item.ID, err)
+				}
+			}
 			for _, label := range flagEditAddLabels {
 				if err := database.AddLabelToItem(item.ID, item.Project, label); err != nil {
 					return fmt.Errorf("failed to add label %q to %s: %w", label, item.ID, err)
@@ -4101,9 +4802,22 @@ Examples:
 				}
 			}
 			if flagEditStatus != "" {
-				if err := database.UpdateStatus(item.ID, model.Status(flagEditStatus), db.AgentContext{}, false); err != nil {
+				status := model.Status(flagEditStatus)
+				if err := database.UpdateStatus(item.ID, status, db.AgentContext{}, flagForce); err != nil {
+					var transErr *model.TransitionError
+					if errors.As(err, &transErr) {
+						return fmt.Errorf("%w\nUse --force to make the change anyway; it is recorded in history", err)
+					}
 					return fmt.Errorf("failed to set status for %s: %w", item.ID, err)
 				}
+				if !model.CanTransition(item.Status, status) {
+					fmt.Fprintf(os.Stderr, "Warning: forced %s from %s to %s (recorded in history)\n", item.ID, item.Status, status)
+				}
+			}
+			if needsApprovalSet {
+				if err := database.SetNeedsApproval(item.ID, flagEditNeedsApproval); err != nil {
+					return err
+				}
 			}
 
 			// Handle template variables
@@ -4155,73 +4869,69 @@ func editInEditor(database *db.DB, id string) error {
 		return fmt.Errorf("cannot edit description on template-backed task %s: descriptions are generated from template variables. Edit variables with 'tpg edit %s --var NAME=VALUE' or use 'tpg show %s --vars'", id, id, id)
 	}
 
-	// Get editor (prefer $TPG_EDITOR, then nvim, then nano)
-	editor := os.Getenv("TPG_EDITOR")
-	if editor == "" {
-		if _, err := exec.LookPath("nvim"); err == nil {
-			editor = "nvim"
-		} else if _, err := exec.LookPath("nano"); err == nil {
-			editor = "nano"
-		} else {
-			editor = "vi"
-		}
+	newContent, changed, err := editTextInEditor(item.Description)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Println("No changes made")
+		return nil
+	}
+
+	// Update description
+	if err := database.SetDescription(id, newContent); err != nil {
+		return err
 	}
+	fmt.Printf("Updated description for %s\n", id)
+	return nil
+}
 
+// editTextInEditor opens initial in the user's editor and returns the edited
+// text. changed is false if the file was saved without modification.
+func editTextInEditor(initial string) (string, bool, error) {
 	// Create temp file
 	tmpfile, err := os.CreateTemp("", "tpg-edit-*.md")
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", false, fmt.Errorf("failed to create temp file: %w", err)
 	}
 	tmpPath := tmpfile.Name()
 	defer func() { _ = os.Remove(tmpPath) }()
 
-	// Write current description
-	if _, err := tmpfile.WriteString(item.Description); err != nil {
+	if _, err := tmpfile.WriteString(initial); err != nil {
 		_ = tmpfile.Close()
-		return fmt.Errorf("failed to write temp file: %w", err)
+		return "", false, fmt.Errorf("failed to write temp file: %w", err)
 	}
 	if err := tmpfile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp file: %w", err)
+		return "", false, fmt.Errorf("failed to close temp file: %w", err)
 	}
 
 	// Get original stat for comparison
 	origStat, err := os.Stat(tmpPath)
 	if err != nil {
-		return fmt.Errorf("failed to stat temp file: %w", err)
+		return "", false, fmt.Errorf("failed to stat temp file: %w", err)
 	}
 
 	// Open editor
-	editorCmd := execCommand(editor, tmpPath)
+	name, args := editor.Command(editor.Resolve(), tmpPath)
+	editorCmd := execCommand(name, args...)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
 	editorCmd.Stderr = os.Stderr
 	if err := editorCmd.Run(); err != nil {
-		return fmt.Errorf("editor failed: %w", err)
+		return "", false, fmt.Errorf("editor failed: %w", err)
 	}
 
 	// Check if file was modified
 	newStat, err := os.Stat(tmpPath)
 	if err != nil {
-		return fmt.Errorf("failed to stat temp file: %w", err)
+		return "", false, fmt.Errorf("failed to stat temp file: %w", err)
 	}
-
-	if newStat.ModTime().Equal(origStat.ModTime()) {
-		fmt.Println("No changes made")
-		return nil
-	}
-
-	// Read new content
 	newContent, err := os.ReadFile(tmpPath)
 	if err != nil {
-		return fmt.Errorf("failed to read temp file: %w", err)
-	}
-
-	// Update description
-	if err := database.SetDescription(id, string(newContent)); err != nil {
-		return err
+		return "", false, fmt.Errorf("failed to read temp file: %w", err)
 	}
-	fmt.Printf("Updated description for %s\n", id)
-	return nil
+	changed := !newStat.ModTime().Equal(origStat.ModTime()) || string(newContent) != initial
+	return string(newContent), changed, nil
 }
 
 // execCommand wraps exec.Command for testing
@@ -4237,6 +4947,9 @@ var descCmd = &cobra.Command{
 Use this when you need to rewrite or fix the description content.
 For adding to existing content, use 'tpg append' instead.
 
+The replaced description is kept; list previous versions with
+'tpg desc history <id>' and compare one with 'tpg desc diff <id> <rev>'.
+
 Examples:
   tpg desc ts-a1b2c3 "New description text here"
   
@@ -4252,7 +4965,11 @@ Examples:
 		if err != nil {
 			return err
 		}
-		defer func() { _ = database.Close() }()
+		defer func() { _ = database.Close() }()
+
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
 
 		id := args[0]
 		text := strings.Join(args[1:], " ")
@@ -4292,6 +5009,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		if err := database.SetProject(args[0], args[1]); err != nil {
 			return err
 		}
@@ -4312,6 +5033,8 @@ Actions:
   list                  Show all dependencies for this task
   remove <other-id>     Remove a dependency relationship
   unblock <other-id>    Alias for remove (symmetric with blocks)
+  after-label <label>   Wait for every task with a label (use --epic to limit it to one epic)
+  remove-label <label>  Remove a label group dependency (with the same --epic)
 
 Understanding blocks vs after:
 
@@ -4323,15 +5046,28 @@ Understanding blocks vs after:
 
 The arrow shows execution order: the blocker must complete first.
 
+Dependencies that would create a cycle are rejected with the offending path.
+This includes cycles through the hierarchy: tasks inherit their epics' deps,
+and an epic cannot finish before its children.
+
+Use --note with blocks or after to record why the dependency exists.
+Re-running the command on an existing dependency updates its note.
+
+Label group dependencies are evaluated when readiness is checked, so tasks
+labeled later join the group without re-running the command. Canceled tasks
+drop out of the group, and the waiting item's own subtree never counts. A
+group dependency on an epic applies to all of its tasks.
+
 Examples:
   tpg dep ts-a1b2c3 blocks ts-d4e5f6     # ts-d4e5f6 waits for ts-a1b2c3
   tpg dep ts-d4e5f6 after ts-a1b2c3      # same thing, other direction
+  tpg dep ts-d4e5f6 after ts-a1b2c3 --note "needs the new schema"
   tpg dep ts-a1b2c3 list                  # show all deps for ts-a1b2c3
   tpg dep ts-a1b2c3 remove ts-d4e5f6     # remove dependency between them
-  tpg dep ts-a1b2c3 unblock ts-d4e5f6    # same as remove`,
+  tpg dep ts-a1b2c3 unblock ts-d4e5f6    # same as remove
+  tpg dep ts-d4e5f6 after-label migration --epic ep-abc   # wait for all migration tasks in ep-abc`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		id := args[0]
 		action := args[1]
 
 		database, err := openDB()
@@ -4340,6 +5076,21 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		if len(args) > 2 && action != "after-label" && action != "remove-label" {
+			if args[2], err = resolveItemArg(database, args[2]); err != nil {
+				return err
+			}
+		}
+		if flagDepEpic != "" {
+			if flagDepEpic, err = resolveItemArg(database, flagDepEpic); err != nil {
+				return err
+			}
+		}
+
 		switch action {
 		case "blocks":
 			if len(args) < 3 {
@@ -4350,6 +5101,11 @@ Examples:
 			if err := database.AddDep(otherID, id); err != nil {
 				return err
 			}
+			if flagDepNote != "" {
+				if err := database.SetDepNote(otherID, id, flagDepNote); err != nil {
+					return err
+				}
+			}
 			fmt.Printf("%s now blocks %s\n", id, otherID)
 
 		case "after":
@@ -4361,6 +5117,11 @@ Examples:
 			if err := database.AddDep(id, otherID); err != nil {
 				return err
 			}
+			if flagDepNote != "" {
+				if err := database.SetDepNote(id, otherID, flagDepNote); err != nil {
+					return err
+				}
+			}
 			fmt.Printf("%s now depends on %s\n", id, otherID)
 
 		case "remove", "unblock":
@@ -4376,6 +5137,34 @@ Examples:
 			}
 			fmt.Printf("Removed dependency between %s and %s\n", id, otherID)
 
+		case "after-label":
+			if len(args) < 3 {
+				return fmt.Errorf("usage: tpg dep <id> after-label <label> [--epic <epic-id>]")
+			}
+			if err := database.AddGroupDep(id, args[2], flagDepEpic); err != nil {
+				return err
+			}
+			group := db.GroupDep{ItemID: id, Label: args[2], EpicID: flagDepEpic}
+			members, err := database.GroupMembers(group)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s now waits on every task with %s (%d so far)\n", id, group, len(members))
+
+		case "remove-label":
+			if len(args) < 3 {
+				return fmt.Errorf("usage: tpg dep <id> remove-label <label> [--epic <epic-id>]")
+			}
+			removed, err := database.RemoveGroupDep(id, args[2], flagDepEpic)
+			if err != nil {
+				return err
+			}
+			group := db.GroupDep{ItemID: id, Label: args[2], EpicID: flagDepEpic}
+			if !removed {
+				return fmt.Errorf("%s does not wait on %s", id, group)
+			}
+			fmt.Printf("Removed %s's dependency on %s\n", id, group)
+
 		case "list":
 			// Show what this task depends on (including inherited deps)
 			waitingOn, err := database.GetAllDepStatuses(id)
@@ -4397,6 +5186,12 @@ Examples:
 				fmt.Printf("Waiting on:\n")
 				for _, dep := range waitingOn {
 					fmt.Printf("  %s [%s] %s\n", dep.ID, dep.Status, dep.Title)
+					if dep.Note != "" {
+						fmt.Printf("      why: %s\n", dep.Note)
+					}
+					if dep.Group != "" {
+						fmt.Printf("      group: %s\n", dep.Group)
+					}
 				}
 			}
 			if len(blocking) > 0 {
@@ -4407,7 +5202,7 @@ Examples:
 			}
 
 		default:
-			return fmt.Errorf("unknown action %q (use: blocks, after, list, remove)", action)
+			return fmt.Errorf("unknown action %q (use: blocks, after, after-label, list, remove, remove-label)", action)
 		}
 
 		return nil
@@ -4427,6 +5222,12 @@ var blocksCmd = &cobra.Command{
 		}
 		defer func() { _ = database.Close() }()
 
+		for i := range args {
+			if args[i], err = resolveItemArg(database, args[i]); err != nil {
+				return err
+			}
+		}
+
 		if err := database.AddDep(args[1], args[0]); err != nil {
 			return err
 		}
@@ -4453,6 +5254,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		// Get item to find its project
 		item, err := database.GetItem(args[0])
 		if err != nil {
@@ -4482,6 +5287,10 @@ Example:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		// Get item to find its project
 		item, err := database.GetItem(args[0])
 		if err != nil {
@@ -4502,22 +5311,21 @@ var learnCmd = &cobra.Command{
 	Long: `Log a learning discovered during work.
 
 Learnings are tagged with concepts for organized retrieval.
-Concepts are created automatically if they don't exist.
+Concepts are created automatically if they don't exist. Without -c, existing
+concepts matching the summary and detail are applied (see --suggest); if none
+match confidently, -c is required.
 
 If a task is in progress for the project, the learning is linked to it.
 
 Examples:
-  tpg learn "Token refresh has race condition" -p myproject -c auth -c concurrency
-  tpg learn "Config loaded from env first" -p myproject -c config -f config.go
-  tpg learn "Token refresh issue" -c auth -p myproject --detail "The mutex only protects..."
-  echo "multi-line detail" | tpg learn "summary" -c auth -p myproject --detail -`,
+  tpg learn "Token refresh has race condition" --project myproject -c auth -c concurrency
+  tpg learn "Config loaded from env first" --project myproject -c config -f config.go
+  tpg learn "Token refresh issue" -c auth --project myproject --detail "The mutex only protects..."
+  echo "multi-line detail" | tpg learn "summary" -c auth --project myproject --detail -
+  tpg learn "API v1 is deprecated" -c api --review-after 90d
+  tpg learn "Retry loop ignores ctx cancel" --suggest  # propose concepts only`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Validate required flags
-		if len(flagLearnConcept) == 0 {
-			return fmt.Errorf("at least one concept is required (-c)")
-		}
-
 		database, err := openDB()
 		if err != nil {
 			return err
@@ -4541,19 +5349,41 @@ Examples:
 			}
 			detail = strings.TrimSpace(string(data))
 		}
+		summary := strings.Join(args, " ")
+
+		concepts := flagLearnConcept
+		if flagLearnSuggest || len(concepts) == 0 {
+			suggestions, err := database.SuggestConcepts(project, summary+"\n"+detail, learnSuggestLimit)
+			if err != nil {
+				return err
+			}
+			if flagLearnSuggest {
+				printConceptSuggestions(suggestions)
+				return nil
+			}
+			concepts = confidentConcepts(suggestions)
+			if len(concepts) == 0 {
+				if len(suggestions) > 0 {
+					printConceptSuggestions(suggestions)
+				}
+				return fmt.Errorf("no concept matched confidently; at least one concept is required (-c)")
+			}
+			fmt.Printf("Tagged with suggested concepts: %s\n", strings.Join(concepts, ", "))
+		}
 
 		now := time.Now()
 		learning := &model.Learning{
-			ID:        model.GenerateLearningID(),
-			Project:   project,
-			CreatedAt: now,
-			UpdatedAt: now,
-			TaskID:    taskID,
-			Summary:   strings.Join(args, " "),
-			Detail:    detail,
-			Status:    model.LearningStatusActive,
-			Concepts:  flagLearnConcept,
-			Files:     flagLearnFile,
+			ID:          model.GenerateLearningID(),
+			Project:     project,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			TaskID:      taskID,
+			Summary:     summary,
+			Detail:      detail,
+			Status:      model.LearningStatusActive,
+			Concepts:    concepts,
+			Files:       flagLearnFile,
+			ReviewAfter: flagLearnReviewAfter,
 		}
 
 		if err := database.CreateLearning(learning); err != nil {
@@ -4565,6 +5395,9 @@ Examples:
 		if taskID != nil {
 			output += fmt.Sprintf(" (linked to %s)", *taskID)
 		}
+		if learning.ReviewAt != nil {
+			output += fmt.Sprintf(" (review on %s)", learning.ReviewAt.Local().Format("2006-01-02"))
+		}
 		fmt.Println(output)
 
 		// Backup after successful mutation
@@ -4692,12 +5525,12 @@ Concepts are knowledge categories that group related learnings.
 Default sort is by learning count (most used first).
 
 Examples:
-  tpg concepts -p myproject                        # list concepts
-  tpg concepts -p myproject --recent               # sort by last updated
-  tpg concepts -p myproject --stats                # show count and oldest age
+  tpg concepts --project myproject                        # list concepts
+  tpg concepts --project myproject --recent               # sort by last updated
+  tpg concepts --project myproject --stats                # show count and oldest age
   tpg concepts --related ts-abc123                 # suggest concepts for a task
-  tpg concepts fts -p myproject --summary "..."    # set concept summary
-  tpg concepts fts -p myproject --rename "search"  # rename concept`,
+  tpg concepts fts --project myproject --summary "..."    # set concept summary
+  tpg concepts fts --project myproject --rename "search"  # rename concept`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
@@ -4784,10 +5617,11 @@ Labels are tags for categorizing tasks (bug, feature, refactor, etc).
 Labels are project-scoped and identified by name.
 
 Examples:
-  tpg labels -p myproject           # list all labels
-  tpg labels add bug -p myproject   # create a label
-  tpg labels rm bug -p myproject    # delete a label
-  tpg labels rename bug critical -p myproject`,
+  tpg labels --project myproject           # list all labels
+  tpg labels add bug --project myproject   # create a label
+  tpg labels rm bug --project myproject    # delete a label
+  tpg labels rename bug critical --project myproject
+  tpg labels merge bugfix bug --project myproject`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
@@ -4823,8 +5657,8 @@ Labels are created on first use when attached to items, but you can
 also create them explicitly with this command.
 
 Examples:
-  tpg labels add bug -p myproject
-  tpg labels add urgent -p myproject --color "#ff0000"`,
+  tpg labels add bug --project myproject
+  tpg labels add urgent --project myproject --color "#ff0000"`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -4862,7 +5696,7 @@ var labelsRmCmd = &cobra.Command{
 	Long: `Delete a label and remove it from all items.
 
 Example:
-  tpg labels rm bug -p myproject`,
+  tpg labels rm bug --project myproject`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -4889,7 +5723,7 @@ var labelsRenameCmd = &cobra.Command{
 	Long: `Rename a label. All items with this label will be updated.
 
 Example:
-  tpg labels rename bug critical -p myproject`,
+  tpg labels rename bug critical --project myproject`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -4910,6 +5744,54 @@ Example:
 	},
 }
 
+var labelsMergeCmd = &cobra.Command{
+	Use:   "merge <source> <target>",
+	Short: "Merge one label into another",
+	Long: `Move every item tagged with the source label to the target label, then
+delete the source. The target is created if it doesn't exist.
+
+Use this to consolidate synonymous labels (bugfix/bug, feat/feature) that
+rename can't combine. --dry-run shows how many items would change.
+
+Examples:
+  tpg labels merge bugfix bug --project myproject --dry-run
+  tpg labels merge bugfix bug --project myproject`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+		result, err := database.MergeLabel(project, args[0], args[1], flagLabelsDryRun)
+		if err != nil {
+			return err
+		}
+
+		verb := "Merged"
+		if flagLabelsDryRun {
+			verb = "Would merge"
+		}
+		fmt.Printf("%s label: %s -> %s\n", verb, args[0], args[1])
+		fmt.Printf("  %d items retagged\n", result.Retagged)
+		if result.AlreadyTagged > 0 {
+			fmt.Printf("  %d items already had %s\n", result.AlreadyTagged, args[1])
+		}
+		if result.CreatedTarget {
+			fmt.Printf("  label %s created\n", args[1])
+		}
+		if !flagLabelsDryRun {
+			database.BackupQuiet()
+		}
+		return nil
+	},
+}
+
 var contextCmd = &cobra.Command{
 	Use:   "context",
 	Short: "Retrieve learnings for context",
@@ -4918,13 +5800,14 @@ var contextCmd = &cobra.Command{
 Use this to load relevant context before starting work on a task.
 
 Examples:
-  tpg context -p myproject --summary                # all learnings, grouped by concept
-  tpg context -c auth -c concurrency -p myproject   # by concepts
-  tpg context -q "rate limit" -p myproject          # full-text search
-  tpg context -c auth --summary -p myproject        # one-liner per learning
+  tpg context --project myproject --summary                # all learnings, grouped by concept
+  tpg context -c auth -c concurrency --project myproject   # by concepts
+  tpg context -q "rate limit" --project myproject          # full-text search
+  tpg context -c auth --summary --project myproject        # one-liner per learning
   tpg context --id lrn-abc123                       # specific learning by ID
-  tpg context -c auth --include-stale -p myproject  # include stale learnings
-  tpg context -c auth --json -p myproject           # JSON output for agents`,
+  tpg context --id note-abc123                      # specific note by ID
+  tpg context -c auth --include-stale --project myproject  # include stale learnings
+  tpg context -c auth --json --project myproject           # JSON output for agents`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
@@ -4932,7 +5815,18 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
-		// Mode 1: Specific learning by ID
+		// Mode 1: Specific note or learning by ID
+		if strings.HasPrefix(flagContextID, "note-") {
+			note, err := database.GetNote(flagContextID)
+			if err != nil {
+				return err
+			}
+			if flagContextJSON {
+				return printNotesJSON([]model.Note{*note})
+			}
+			printNote(note)
+			return nil
+		}
 		if flagContextID != "" {
 			learning, err := database.GetLearning(flagContextID)
 			if err != nil {
@@ -4963,20 +5857,26 @@ Examples:
 					return nil
 				}
 				fmt.Println("No learnings found")
-				return nil
-			}
-
-			if flagContextJSON {
+			} else if flagContextJSON {
 				return printLearningsJSON(learnings)
+			} else {
+				// Get concept summaries for grouped output
+				concepts, _ := database.ListConcepts(project, false)
+				conceptMap := make(map[string]string)
+				for _, c := range concepts {
+					conceptMap[c.Name] = c.Summary
+				}
+				printAllLearningSummaries(learnings, conceptMap)
 			}
 
-			// Get concept summaries for grouped output
-			concepts, _ := database.ListConcepts(project, false)
-			conceptMap := make(map[string]string)
-			for _, c := range concepts {
-				conceptMap[c.Name] = c.Summary
+			notes, err := database.ListNotes(project, "")
+			if err != nil {
+				return err
+			}
+			if len(notes) > 0 {
+				fmt.Println("\nNotes (tpg note show <id>):")
+				printNoteSummaries(notes)
 			}
-			printAllLearningSummaries(learnings, conceptMap)
 			return nil
 		}
 
@@ -5389,6 +6289,11 @@ var primeCmd = &cobra.Command{
 Designed to run on session start hooks to ensure agents maintain
 context about the tpg workflow.
 
+When the session already has a task in progress (its current task, or
+one claimed by this agent), the output leads with that task: its
+description, latest progress, and what it is waiting on. The general
+workflow guide is condensed; --full shows all of it.
+
 Customize the output template with --customize. Use --render to test
 a specific template file.
 
@@ -5433,6 +6338,7 @@ For Opencode: The plugin installed by 'tpg onboard' handles this automatically.`
 func init() {
 	primeCmd.Flags().BoolVar(&flagPrimeCustomize, "customize", false, "Create/edit custom prime template")
 	primeCmd.Flags().StringVar(&flagPrimeRender, "render", "", "Render specific template file (for testing)")
+	primeCmd.Flags().BoolVar(&flagPrimeFull, "full", false, "Show the full workflow guide even when resuming an in-progress task")
 }
 
 var compactCmd = &cobra.Command{
@@ -5448,24 +6354,25 @@ The workflow uses two phases:
 2. Selection: Load detail for candidates, groom, repeat
 
 Example:
-  tpg compact              # Output compaction guidance
-  tpg compact -p myproject # Include project-specific stats`,
+  tpg compact                       # Output compaction guidance
+  tpg compact --project myproject   # Include project-specific stats`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
 		if err != nil {
-			printCompactContent(nil)
+			printCompactContent(nil, nil)
 			return nil
 		}
 		defer func() { _ = database.Close() }()
 
 		project, err := resolveProject()
 		if err != nil {
-			printCompactContent(nil)
+			printCompactContent(nil, nil)
 			return nil
 		}
 		stats, _ := database.ListConceptsWithStats(project)
+		due, _ := database.DueLearnings(project, time.Now())
 
-		printCompactContent(stats)
+		printCompactContent(stats, due)
 		return nil
 	},
 }
@@ -5480,10 +6387,15 @@ var doctorCmd = &cobra.Command{
 	  2. General circular dependencies (A depends on B depends on C depends on A)
 	  3. Tasks with non-epic parents
 	  4. Open epics with all children done (stuck epics)
+	  5. Done items without results (backfilled from their last progress
+	     log, or written in one editor session)
+
+Use --check to run a single check: stuck-epics or results.
 
 Examples:
-  tpg doctor              # Check and optionally fix issues
-  tpg doctor --dry-run    # Show issues without fixing`,
+  tpg doctor                  # Check and optionally fix issues
+  tpg doctor --dry-run        # Show issues without fixing
+  tpg doctor --check results  # Only backfill missing results`,
 	RunE: runDoctor,
 }
 
@@ -5494,6 +6406,16 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = database.Close() }()
 
+	switch flagDoctorCheck {
+	case "":
+	case "stuck-epics":
+		return runDoctorStuckEpics(database, flagDoctorDryRun)
+	case "results":
+		return runDoctorResults(database, flagDoctorDryRun)
+	default:
+		return fmt.Errorf("unknown check %q (available: stuck-epics, results)", flagDoctorCheck)
+	}
+
 	fmt.Println("🔍 Checking for data integrity issues...")
 	fmt.Println()
 
@@ -5513,10 +6435,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 
 		if !flagDoctorDryRun {
-			fmt.Print("\n   Fix these dependencies? [y/N]: ")
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) == "y" {
+			fmt.Println()
+			if confirm("   Fix these dependencies?") {
 				fixed, err := database.FixAllParentChildCircularDeps()
 				if err != nil {
 					return fmt.Errorf("failed to fix deps: %w", err)
@@ -5561,10 +6481,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 
 		if !flagDoctorDryRun {
-			fmt.Print("\n   Remove invalid parent relationships? [y/N]: ")
-			var response string
-			fmt.Scanln(&response)
-			if strings.ToLower(response) == "y" {
+			fmt.Println()
+			if confirm("   Remove invalid parent relationships?") {
 				fixed := 0
 				for _, inv := range invalidParents {
 					if err := database.ClearParent(inv.ItemID); err != nil {
@@ -5585,6 +6503,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := runDoctorResults(database, flagDoctorDryRun); err != nil {
+		return err
+	}
+
 	fmt.Println("\n✅ Doctor check complete!")
 	return nil
 }
@@ -5607,10 +6529,8 @@ func runDoctorStuckEpics(database *db.DB, dryRun bool) error {
 	}
 
 	if !dryRun {
-		fmt.Print("\n   Auto-complete these epics? [y/N]: ")
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) == "y" {
+		fmt.Println()
+		if confirm("   Auto-complete these epics?") {
 			fixed := 0
 			for _, e := range stuck {
 				if _, err := database.AutoCompleteEpic(e.ID); err != nil {
@@ -5797,6 +6717,12 @@ Example:
 			if len(step.Depends) > 0 {
 				fmt.Printf("       Depends: %s\n", strings.Join(step.Depends, ", "))
 			}
+			if step.When != "" {
+				fmt.Printf("       When: %s\n", step.When)
+			}
+			if step.ForEach != "" {
+				fmt.Printf("       For each: %s\n", step.ForEach)
+			}
 		}
 
 		return nil
@@ -5917,12 +6843,18 @@ var backupCmd = &cobra.Command{
 Backups are stored in ~/.tpg/backups/ by default with timestamped names.
 The last 10 backups are kept; older ones are automatically pruned.
 
+Commands that change the database also back it up, at most once per
+backup.interval (default 5m; "0" backs up after every change). Scripts that
+run many commands can set TPG_BATCH=1 to skip those backups and run
+'tpg backup' once at the end.
+
 Optionally specify a custom path for the backup file.
 
 Examples:
   tpg backup                    # Create backup in ~/.tpg/backups/
   tpg backup ~/my-backup.db     # Create backup at custom path
-  tpg backup --quiet            # Silent backup (for hooks)`,
+  tpg backup --quiet            # Silent backup (for hooks)
+  TPG_BATCH=1 ./bulk-edit.sh && tpg backup`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := openDB()
@@ -6051,14 +6983,15 @@ Actions:
   b   Block task (prompts for reason)
   L   Log progress (prompts for message)
   c   Cancel task (prompts for optional reason)
-  n   Create new task (inherits project from selected item)
+  n   Create a task, an epic, or items from a template
   D   Delete task
-  a   Add dependency (prompts for blocker ID)
+  a   Add dependency (searchable blocker picker)
   r   Refresh task list
 
 Filtering:
   /       Search by title/ID/description
   p       Filter by project
+  P       Switch project (picker with per-project summaries)
   1-5     Toggle status: 1=open 2=in_progress 3=blocked 4=done 5=canceled
   0       Show all statuses
   esc     Clear filters, or quit if none set
@@ -6076,7 +7009,10 @@ Press q to quit.`,
 			return err
 		}
 
-		return tui.Run(database, project)
+		return tui.Run(database, project, func(project, title, templateID string, vars map[string]string) (string, error) {
+			result, err := createFromTemplate(database, project, title, templateID, vars, 2, "")
+			return result.ParentID, err
+		})
 	},
 }
 
@@ -6104,6 +7040,10 @@ Examples:
 		}
 		defer func() { _ = database.Close() }()
 
+		if args[0], err = resolveItemArg(database, args[0]); err != nil {
+			return err
+		}
+
 		itemID := args[0]
 		impact, err := database.GetImpact(itemID)
 		if err != nil {
@@ -6130,7 +7070,7 @@ var mergeCmd = &cobra.Command{
 	Long: `Merge source task into target, combining all metadata.
 
 This is a destructive operation — the source item is deleted after merging.
-Requires --yes-i-am-sure to confirm.
+Requires --yes-i-am-sure (or the global --yes / TPG_ASSUME_YES) to confirm.
 
 What gets merged:
   - Dependencies (both directions) are transferred to target
@@ -6144,19 +7084,25 @@ Examples:
   tpg merge ts-abc ts-xyz --yes-i-am-sure   # merge ts-abc into ts-xyz`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if !flagMergeConfirm {
+		if !flagMergeConfirm && !assumeYes() {
 			return fmt.Errorf("this permanently deletes the source item — pass --yes-i-am-sure to confirm")
 		}
 
-		sourceID := args[0]
-		targetID := args[1]
-
 		database, err := openDB()
 		if err != nil {
 			return err
 		}
 		defer func() { _ = database.Close() }()
 
+		sourceID, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		targetID, err := resolveItemArg(database, args[1])
+		if err != nil {
+			return err
+		}
+
 		// Show what will happen before merging
 		src, err := database.GetItem(sourceID)
 		if err != nil {
@@ -6234,10 +7180,21 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&flagProject, "project", "", "Project scope")
 	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "Show agent context and other debug info")
+	rootCmd.PersistentFlags().BoolVar(&flagAssumeYes, "yes", false, "Answer yes to confirmation prompts (or set TPG_ASSUME_YES=1)")
 	rootCmd.PersistentFlags().BoolVar(&flagFromYAML, "from-yaml", false, "Read flag values from stdin as YAML (keys use underscores, e.g. desc: value)")
+	rootCmd.PersistentFlags().BoolVar(&flagNoColor, "no-color", false, "Disable colored output (or set NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&flagNoPager, "no-pager", false, "Don't send long output from show, plan, list, and context through $PAGER")
+	rootCmd.PersistentFlags().BoolVar(&flagASCII, "ascii", false, "Use plain ASCII instead of unicode symbols (or set output.ascii / TPG_ASCII=1)")
+	rootCmd.PersistentFlags().BoolVar(&flagTimings, "timings", false, "Report how long each phase took on stderr (or set TPG_PROFILE=1)")
 
 	// Handle --from-yaml and show agent context when verbose
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		startTimings()
+		startMetrics(cmd)
+		format.SetColorEnabled(!flagNoColor && format.ShouldColor(os.Stdout))
+		startPager(cmd)
+		setupASCIIOutput(cmd)
+
 		// Handle --from-yaml: read YAML from stdin and set flag values
 		if flagFromYAML {
 			// Check for conflicting '-' stdin markers on any flag
@@ -6251,6 +7208,10 @@ func init() {
 			}
 		}
 
+		if err := checkRole(cmd); err != nil {
+			return err
+		}
+
 		// Show agent context when verbose
 		if flagVerbose {
 			agentID := os.Getenv("AGENT_ID")
@@ -6263,18 +7224,21 @@ func init() {
 	}
 
 	// add flags
-	addCmd.Flags().IntVarP(&flagPriority, "priority", "p", 2, "Priority (1=high, 2=medium, 3=low)")
+	addPriorityFlag(addCmd, &flagPriority, 2, true)
 	addCmd.Flags().StringVar(&flagParent, "parent", "", "Parent epic ID")
 	addCmd.Flags().StringVar(&flagBlocks, "blocks", "", "ID of task this will block (it depends on this)")
 	addCmd.Flags().StringVar(&flagAfter, "after", "", "ID of task this depends on (must complete first)")
 	addCmd.Flags().StringArrayVarP(&flagAddLabels, "label", "l", nil, "Label to attach (can be repeated)")
+	addCmd.Flags().BoolVar(&flagAddNeedsApproval, "needs-approval", false, "Require 'tpg approve' after 'tpg done' before the task counts as done")
 	addCmd.Flags().StringVar(&flagTemplateID, "template", "", "Template ID to instantiate")
 	addCmd.Flags().StringArrayVar(&flagTemplateVars, "var", nil, "Template variable value (name=json-string)")
 	addCmd.Flags().BoolVar(&flagTemplateVarsYAML, "vars-yaml", false, "Read template variables from stdin as YAML")
 	addCmd.Flags().StringVar(&flagDescription, "desc", "", "Description (use '-' for stdin)")
 	addCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Preview what would be created without actually creating")
-	addCmd.Flags().StringVar(&flagType, "type", "", "Item type (default: task)")
+	addCmd.Flags().StringVar(&flagType, "type", "", "Item type: task, epic, or a custom type from 'tpg types' (default: task)")
 	addCmd.Flags().StringVar(&flagPrefix, "prefix", "", "Custom ID prefix (overrides auto-generated prefix)")
+	addCmd.Flags().BoolVar(&flagAddNoDefaults, "no-defaults", false, "Ignore the project's add_defaults and label_routes config")
+	addCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Keep a multi-line or overlong title instead of moving it into the description")
 
 	// init flags
 	initCmd.Flags().StringVar(&flagInitTaskPrefix, "prefix", "", "Task ID prefix (default: ts)")
@@ -6285,7 +7249,7 @@ func init() {
 	listCmd.Flags().BoolVarP(&flagListAll, "all", "a", false, "Show all items including done and canceled (default: hide done/canceled)")
 	listCmd.Flags().StringVar(&flagStatus, "status", "", "Filter by status (open, in_progress, blocked, done, canceled)")
 	listCmd.Flags().StringVar(&flagListParent, "parent", "", "Filter by parent epic ID")
-	listCmd.Flags().StringVar(&flagListType, "type", "", "Filter by item type (task, epic)")
+	listCmd.Flags().StringVar(&flagListType, "type", "", "Filter by item type (task, epic, or a custom type)")
 	listCmd.Flags().StringVar(&flagListEpic, "epic", "", "Filter to descendants of this epic ID")
 	listCmd.Flags().StringVar(&flagBlocking, "blocking", "", "Show items that block the given ID")
 	listCmd.Flags().StringVar(&flagBlockedBy, "blocked-by", "", "Show items blocked by the given ID")
@@ -6293,16 +7257,24 @@ func init() {
 	listCmd.Flags().BoolVar(&flagNoBlockers, "no-blockers", false, "Show only items with no blockers")
 	listCmd.Flags().BoolVar(&flagIdsOnly, "ids-only", false, "Output only IDs, one per line (pipe-friendly)")
 	listCmd.Flags().BoolVarP(&flagListFlat, "flat", "f", false, "Show flat list instead of tree view")
+	listCmd.Flags().StringVar(&flagListFormat, "format", "", "Output format: text, or jsonl to stream one JSON object per line")
 	listCmd.Flags().StringArrayVarP(&flagFilterLabels, "label", "l", nil, "Filter by label (can be repeated, AND logic)")
+	listCmd.Flags().StringArrayVar(&flagFilterFields, "field", nil, "Filter by custom field, key=value (can be repeated, AND logic)")
+	listCmd.Flags().StringVar(&flagCreatedSince, "created-since", "", "Only items created since a duration ago (24h, 7d), date (2006-01-02), or 'today'")
+	listCmd.Flags().StringVar(&flagUpdatedSince, "updated-since", "", "Only items updated since a duration ago (24h, 7d), date (2006-01-02), or 'today'")
+	listCmd.Flags().StringVar(&flagDoneSince, "done-since", "", "Only items completed since a duration ago (24h, 7d), date (2006-01-02), or 'today'")
+	addSortFlags(listCmd)
 
 	// merge flags
 	mergeCmd.Flags().BoolVar(&flagMergeConfirm, "yes-i-am-sure", false, "Confirm destructive merge operation")
 
 	// stale flags
-	staleCmd.Flags().StringVar(&flagStaleThreshold, "threshold", "5m", "Threshold for stale in-progress tasks")
+	staleCmd.Flags().StringVar(&flagStaleThreshold, "threshold", "", "Use one threshold for all tasks instead of the configured ones (e.g. 30m, 2d)")
 
 	// done flags
 	doneCmd.Flags().BoolVar(&flagDoneOverride, "override", false, "Allow completion with unmet dependencies")
+	doneCmd.Flags().StringVar(&flagDoneTemplate, "template", "", "Write results from a template in $TPG_EDITOR (optionally --template=<name>)")
+	doneCmd.Flags().Lookup("template").NoOptDefVal = resultTemplateAuto
 
 	// start flags
 	startCmd.Flags().BoolVar(&flagResume, "resume", false, "Resume an already in-progress task")
@@ -6312,14 +7284,18 @@ func init() {
 
 	// edit flags - field setters
 	editCmd.Flags().StringVar(&flagEditTitle, "title", "", "New title (single item only)")
-	editCmd.Flags().IntVar(&flagEditPriority, "priority", 0, "New priority (1=high, 2=medium, 3=low)")
+	editCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Allow a multi-line or overlong title")
+	addPriorityFlag(editCmd, &flagEditPriority, 0, false)
+	editCmd.Flags().StringVar(&flagEditType, "type", "", "New item type (task, epic, or a custom type)")
 	editCmd.Flags().StringVar(&flagEditParent, "parent", "", "New parent epic ID (use \"\" to remove)")
 	editCmd.Flags().StringArrayVar(&flagEditAddLabels, "add-label", nil, "Label to add (repeatable)")
 	editCmd.Flags().StringArrayVar(&flagEditRmLabels, "remove-label", nil, "Label to remove (repeatable)")
 	editCmd.Flags().StringVar(&flagEditDesc, "desc", "", "New description (single item only, use '-' for stdin)")
-	editCmd.Flags().StringVar(&flagEditStatus, "status", "", "Force status change (requires --force)")
+	editCmd.Flags().StringVar(&flagEditStatus, "status", "", "Set status directly (prefer: tpg start/done/block/cancel)")
+	editCmd.Flags().BoolVar(&flagEditNeedsApproval, "needs-approval", false, "Require 'tpg approve' after 'tpg done' (--needs-approval=false clears)")
 	editCmd.Flags().StringArrayVar(&flagEditVars, "var", nil, "Template variable NAME=json-string (repeatable, for template tasks)")
 	editCmd.Flags().BoolVar(&flagEditVarsYAML, "vars-yaml", false, "Read template variables from stdin as YAML")
+	editCmd.Flags().StringVar(&flagEditRankBefore, "rank-before", "", "Order the item(s) just before this sibling")
 
 	// edit flags - selection filters (reuse list flag variables)
 	editCmd.Flags().StringVar(&flagStatus, "select-status", "", "Select items by status")
@@ -6330,11 +7306,14 @@ func init() {
 
 	// edit flags - control
 	editCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Preview changes without applying")
-	editCmd.Flags().BoolVar(&flagForce, "force", false, "Required for --status changes")
+	editCmd.Flags().BoolVar(&flagForce, "force", false, "Allow --status changes outside the status graph; allows --with-descendants to switch worktree branch")
+	editCmd.Flags().BoolVar(&flagEditWithDescendants, "with-descendants", false, "With --parent, move the whole subtree after validating it")
 
 	// ready flags
 	readyCmd.Flags().StringArrayVarP(&flagFilterLabels, "label", "l", nil, "Filter by label (can be repeated, AND logic)")
 	readyCmd.Flags().StringVar(&flagReadyEpic, "epic", "", "Show ready tasks for a specific epic")
+	readyCmd.Flags().BoolVar(&flagReadyClaim, "claim", false, "Start the top ready task for this agent")
+	addSortFlags(readyCmd)
 
 	// status flags
 	statusCmd.Flags().BoolVar(&flagStatusAll, "all", false, "Show all ready tasks (default: limit to 10)")
@@ -6346,6 +7325,7 @@ func init() {
 	showCmd.Flags().BoolVar(&flagShowWithParent, "with-parent", false, "Show parent chain up to root")
 	showCmd.Flags().StringVar(&flagShowFormat, "format", "", "Output format (json, yaml, markdown)")
 	showCmd.Flags().BoolVar(&flagShowVars, "vars", false, "Show raw template variables instead of rendered description")
+	showCmd.Flags().StringSliceVar(&flagShowLogTypes, "log-type", nil, "Only show logs in these categories (progress, decision, blocker, note)")
 
 	// learn flags
 	learnCmd.Flags().StringArrayVarP(&flagLearnConcept, "concept", "c", nil, "Concept to tag this learning with (can be repeated)")
@@ -6378,13 +7358,15 @@ func init() {
 	labelsCmd.AddCommand(labelsAddCmd)
 	labelsCmd.AddCommand(labelsRmCmd)
 	labelsCmd.AddCommand(labelsRenameCmd)
+	labelsMergeCmd.Flags().BoolVar(&flagLabelsDryRun, "dry-run", false, "Show what would change without merging")
+	labelsCmd.AddCommand(labelsMergeCmd)
 
 	// context flags
 	contextCmd.Flags().StringArrayVarP(&flagContextConcept, "concept", "c", nil, "Concept to retrieve learnings for (can be repeated)")
 	contextCmd.Flags().StringVarP(&flagContextQuery, "query", "q", "", "Full-text search query")
 	contextCmd.Flags().BoolVar(&flagContextStale, "include-stale", false, "Include stale learnings in results")
 	contextCmd.Flags().BoolVar(&flagContextSummary, "summary", false, "Show one-liner per learning (no detail)")
-	contextCmd.Flags().StringVar(&flagContextID, "id", "", "Load specific learning by ID")
+	contextCmd.Flags().StringVar(&flagContextID, "id", "", "Load specific learning or note by ID")
 	contextCmd.Flags().BoolVar(&flagContextJSON, "json", false, "Output as JSON for machine processing")
 
 	// backup flags
@@ -6405,12 +7387,15 @@ func init() {
 	cleanCmd.Flags().IntVar(&flagCleanDays, "days", 30, "Age threshold in days")
 	cleanCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show what would be deleted without actually deleting")
 	cleanCmd.Flags().BoolVar(&flagForce, "force", false, "Skip confirmation prompt")
+	cleanCmd.Flags().BoolVar(&flagCleanPruneRefs, "prune-refs", false, "Also delete tasks open items depend on or learnings link to, detaching those references")
+	cleanCmd.Flags().StringVar(&flagCleanSave, "save", "", "Write the items to this JSONL file before deleting them (undo with 'tpg import jsonl')")
 
 	// delete flags
-	deleteCmd.Flags().BoolVarP(&flagDeleteForce, "force", "f", false, "Delete even if tasks depend on this item")
+	deleteCmd.Flags().BoolVarP(&flagDeleteForce, "force", "f", false, "Delete even if tasks depend on this item or learnings link to it")
 	deleteCmd.Flags().BoolVarP(&flagDeleteRecursive, "recursive", "r", false, "Recursively delete all children (for epics)")
 	// cancel flags
 	cancelCmd.Flags().BoolVar(&flagCancelForce, "force", false, "Cancel even if tasks depend on this item")
+	cancelCmd.Flags().BoolVar(&flagCancelCascade, "cascade", false, "Also cancel all open descendants and report external dependents")
 
 	rootCmd.AddCommand(initCmd)
 
@@ -6420,11 +7405,12 @@ func init() {
 	epicWorktreeCmd.Flags().BoolVar(&flagWorktreeAllow, "allow-any-branch", false, "Allow branch names that do not include the epic ID")
 
 	// epicAddCmd flags
-	epicAddCmd.Flags().IntVarP(&flagPriority, "priority", "p", 2, "Priority (1=high, 2=medium, 3=low)")
+	addPriorityFlag(epicAddCmd, &flagPriority, 2, true)
 	epicAddCmd.Flags().StringVar(&flagParent, "parent", "", "Parent epic ID")
 	epicAddCmd.Flags().StringArrayVarP(&flagAddLabels, "label", "l", nil, "Label to attach (can be repeated)")
 	epicAddCmd.Flags().StringVar(&flagDescription, "desc", "", "Description (use '-' for stdin)")
 	epicAddCmd.Flags().StringVar(&flagPrefix, "prefix", "", "Custom ID prefix (overrides auto-generated prefix)")
+	epicAddCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Keep a multi-line or overlong title instead of moving it into the description")
 	epicAddCmd.Flags().StringVar(&flagContext, "context", "", "Context shared with all descendants (use '-' for stdin)")
 	epicAddCmd.Flags().StringVar(&flagOnClose, "on-close", "", "Instructions shown when epic auto-completes (use '-' for stdin)")
 	epicAddCmd.Flags().BoolVar(&flagWorktree, "worktree", false, "Create epic with worktree metadata (generates branch name)")
@@ -6434,14 +7420,16 @@ func init() {
 
 	// epicEditCmd flags
 	epicEditCmd.Flags().StringVar(&flagEditTitle, "title", "", "New title for the epic")
+	epicEditCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Allow a multi-line or overlong title")
 	epicEditCmd.Flags().StringVar(&flagContext, "context", "", "Context shared with all descendants (use '-' for stdin)")
 	epicEditCmd.Flags().StringVar(&flagOnClose, "on-close", "", "Instructions shown when epic auto-completes (use '-' for stdin)")
 
 	// epicReplaceCmd flags
-	epicReplaceCmd.Flags().IntVarP(&flagPriority, "priority", "p", 2, "Priority (1=high, 2=medium, 3=low)")
+	addPriorityFlag(epicReplaceCmd, &flagPriority, 2, true)
 	epicReplaceCmd.Flags().StringArrayVarP(&flagAddLabels, "label", "l", nil, "Label to attach (can be repeated)")
 	epicReplaceCmd.Flags().StringVar(&flagDescription, "desc", "", "Description (use '-' for stdin)")
 	epicReplaceCmd.Flags().StringVar(&flagPrefix, "prefix", "", "Custom ID prefix (overrides auto-generated prefix)")
+	epicReplaceCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Keep a multi-line or overlong title instead of moving it into the description")
 	epicReplaceCmd.Flags().StringVar(&flagContext, "context", "", "Context shared with all descendants (use '-' for stdin)")
 	epicReplaceCmd.Flags().StringVar(&flagOnClose, "on-close", "", "Instructions shown when epic auto-completes (use '-' for stdin)")
 
@@ -6450,6 +7438,7 @@ func init() {
 
 	epicCmd.AddCommand(epicAddCmd)
 	epicCmd.AddCommand(epicEditCmd)
+	addSortFlags(epicListCmd)
 	epicCmd.AddCommand(epicListCmd)
 	epicCmd.AddCommand(epicReplaceCmd)
 	epicCmd.AddCommand(epicWorktreeCmd)
@@ -6461,11 +7450,12 @@ func init() {
 
 	// replace flags (subset of add flags that make sense for replacement)
 	replaceCmd.Flags().BoolVarP(&flagEpic, "epic", "e", false, "Replace with an epic instead of a task")
-	replaceCmd.Flags().IntVarP(&flagPriority, "priority", "p", 2, "Priority (1=high, 2=medium, 3=low)")
+	addPriorityFlag(replaceCmd, &flagPriority, 2, true)
 	replaceCmd.Flags().StringArrayVarP(&flagAddLabels, "label", "l", nil, "Label to attach (can be repeated)")
 	replaceCmd.Flags().StringVar(&flagDescription, "desc", "", "Description (use '-' for stdin)")
 	replaceCmd.Flags().StringVar(&flagType, "type", "", "Item type (default: task, or epic if -e flag used)")
 	replaceCmd.Flags().StringVar(&flagPrefix, "prefix", "", "Custom ID prefix (overrides auto-generated prefix)")
+	replaceCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Keep a multi-line or overlong title instead of moving it into the description")
 	replaceCmd.Flags().StringVar(&flagContext, "context", "", "Context shared with all descendants (use '-' for stdin, epics only)")
 	replaceCmd.Flags().StringVar(&flagOnClose, "on-close", "", "Instructions shown when epic auto-completes (use '-' for stdin)")
 	rootCmd.AddCommand(replaceCmd)
@@ -6475,13 +7465,15 @@ func init() {
 	rootCmd.AddCommand(showCmd)
 
 	// history flags
-	historyCmd.Flags().IntVarP(&flagHistoryLimit, "limit", "n", 0, "Max number of results (default 50)")
+	historyCmd.Flags().IntVarP(&flagHistoryLimit, "limit", "n", 0, "Max number of results (default 50, -1 for all)")
 	historyCmd.Flags().StringVarP(&flagHistoryAgent, "agent", "a", "", "Filter by agent ID")
 	historyCmd.Flags().StringVarP(&flagHistorySince, "since", "s", "", "Filter by time (e.g., '24h', '7d')")
 	historyCmd.Flags().StringVar(&flagHistoryEventType, "event-type", "", "Filter by event type")
+	historyCmd.Flags().StringSliceVar(&flagHistoryLogTypes, "log-type", nil, "List log entries in these categories instead of events")
 	historyCmd.Flags().BoolVar(&flagHistoryCleanup, "cleanup", false, "Run history cleanup")
 	historyCmd.Flags().BoolVar(&flagHistoryDryRun, "dry-run", false, "With --cleanup, show what would be deleted")
 	historyCmd.Flags().BoolVar(&flagHistoryJSON, "json", false, "Output as JSON")
+	historyCmd.Flags().StringVar(&flagHistoryFormat, "format", "", "Output format: text, or jsonl to stream one JSON object per line")
 	rootCmd.AddCommand(historyCmd)
 
 	// closed flags
@@ -6500,6 +7492,8 @@ func init() {
 	rootCmd.AddCommand(touchCmd)
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(cleanCmd)
+	logCmd.Flags().IntVar(&flagLogProgress, "progress", 0, "Record percent complete (0-100) with the entry")
+	logCmd.Flags().StringVar(&flagLogType, "type", "", "Log category: progress, decision, blocker, or note (default: from the message prefix)")
 	rootCmd.AddCommand(logCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(summaryCmd)
@@ -6531,6 +7525,7 @@ func init() {
 
 	// doctor flags
 	doctorCmd.Flags().BoolVar(&flagDoctorDryRun, "dry-run", false, "Show issues without fixing")
+	doctorCmd.Flags().StringVar(&flagDoctorCheck, "check", "", "Run only one check (stuck-epics, results)")
 	rootCmd.AddCommand(doctorCmd)
 
 	// Import subcommands
@@ -6580,10 +7575,17 @@ func addCompletionFunctions() {
 	// Commands that accept any item ID
 	showCmd.ValidArgsFunction = itemIDCompletion
 	descCmd.ValidArgsFunction = itemIDCompletion
+	descHistoryCmd.ValidArgsFunction = itemIDCompletion
+	logsCmd.ValidArgsFunction = itemIDCompletion
+	descDiffCmd.ValidArgsFunction = itemIDCompletion
 	appendCmd.ValidArgsFunction = itemIDCompletion
 	editCmd.ValidArgsFunction = itemIDCompletion
 	logCmd.ValidArgsFunction = itemIDCompletion
 	doneCmd.ValidArgsFunction = itemIDCompletion
+	approveCmd.ValidArgsFunction = itemIDCompletion
+	reviewCmd.ValidArgsFunction = itemIDCompletion
+	transcriptAddCmd.ValidArgsFunction = itemIDCompletion
+	transcriptListCmd.ValidArgsFunction = itemIDCompletion
 	cancelCmd.ValidArgsFunction = itemIDCompletion
 	blockCmd.ValidArgsFunction = itemIDCompletion
 	startCmd.ValidArgsFunction = itemIDCompletion
@@ -6593,6 +7595,12 @@ func addCompletionFunctions() {
 	impactCmd.ValidArgsFunction = itemIDCompletion
 	replaceCmd.ValidArgsFunction = itemIDCompletion
 	touchCmd.ValidArgsFunction = itemIDCompletion
+	runCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeItemIDs(toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	}
 	planCmd.ValidArgsFunction = epicIDCompletion
 
 	// Commands that need two item IDs
@@ -6603,7 +7611,9 @@ func addCompletionFunctions() {
 	epicListCmd.ValidArgsFunction = epicIDCompletion
 	epicReplaceCmd.ValidArgsFunction = taskIDCompletion // Can replace tasks
 	epicWorktreeCmd.ValidArgsFunction = epicIDCompletion
+	epicSetupCmd.ValidArgsFunction = epicIDCompletion
 	epicFinishCmd.ValidArgsFunction = epicIDCompletion
+	epicCloneCmd.ValidArgsFunction = epicIDCompletion
 
 	// Flag completions
 	addCmd.RegisterFlagCompletionFunc("parent", epicIDCompletion)
@@ -6621,12 +7631,16 @@ func addCompletionFunctions() {
 	readyCmd.RegisterFlagCompletionFunc("epic", epicIDCompletion)
 	readyCmd.RegisterFlagCompletionFunc("label", labelCompletion)
 
+	depCmd.Flags().StringVar(&flagDepNote, "note", "", "Why the dependency exists (blocks/after)")
+	depCmd.Flags().StringVar(&flagDepEpic, "epic", "", "Limit a label group to one epic (after-label/remove-label)")
 	depCmd.RegisterFlagCompletionFunc("blocks", itemIDCompletion)
 	depCmd.RegisterFlagCompletionFunc("after", itemIDCompletion)
 
 	editCmd.RegisterFlagCompletionFunc("parent", epicIDCompletion)
 
 	exportCmd.RegisterFlagCompletionFunc("parent", epicIDCompletion)
+	exportCmd.RegisterFlagCompletionFunc("epic", epicIDCompletion)
+	lintCmd.RegisterFlagCompletionFunc("epic", epicIDCompletion)
 
 	closedCmd.RegisterFlagCompletionFunc("status", completeClosedStatusValues)
 
@@ -6636,6 +7650,11 @@ func addCompletionFunctions() {
 	// Epic flag completions
 	epicAddCmd.RegisterFlagCompletionFunc("parent", epicIDCompletion)
 	epicAddCmd.RegisterFlagCompletionFunc("label", labelCompletion)
+	epicCloneCmd.RegisterFlagCompletionFunc("into", epicIDCompletion)
+
+	noteAddCmd.RegisterFlagCompletionFunc("epic", epicIDCompletion)
+	noteListCmd.RegisterFlagCompletionFunc("epic", epicIDCompletion)
+	contextPackCmd.RegisterFlagCompletionFunc("epic", epicIDCompletion)
 	epicEditCmd.RegisterFlagCompletionFunc("label", labelCompletion)
 	epicReplaceCmd.RegisterFlagCompletionFunc("label", labelCompletion)
 }
@@ -6756,7 +7775,7 @@ func completeProjects(prefix string) []string {
 
 // completeStatusValues returns valid status values
 func completeStatusValues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	statuses := []string{"open", "in_progress", "blocked", "done", "canceled"}
+	statuses := []string{"open", "in_progress", "blocked", "pending_review", "done", "canceled"}
 	var matches []string
 	for _, s := range statuses {
 		if strings.HasPrefix(s, toComplete) {
@@ -6768,7 +7787,8 @@ func completeStatusValues(cmd *cobra.Command, args []string, toComplete string)
 
 // completeTypeValues returns valid type values
 func completeTypeValues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	types := []string{"task", "epic"}
+	registerConfiguredTypes()
+	types := typeNames()
 	var matches []string
 	for _, t := range types {
 		if strings.HasPrefix(t, toComplete) {
@@ -6807,7 +7827,32 @@ func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// Expand user-defined aliases before cobra sees the arguments.
+	// Outside a project there is no config, so aliases are simply skipped.
+	if config, err := db.LoadConfig(); err == nil && len(config.Aliases) > 0 {
+		args, err := expandAliases(os.Args[1:], config.Aliases)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		rootCmd.SetArgs(args)
+	}
+
+	start := time.Now()
+	err := rootCmd.Execute()
+	if stopASCIIOutput != nil {
+		stopASCIIOutput()
+	}
+	if stopPager != nil {
+		stopPager()
+	}
+	reportTimings(os.Stderr, time.Since(start))
+	flushMetrics(err)
+	if err != nil {
+		var exitErr *exitStatusError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -6828,19 +7873,20 @@ func printItemsTable(items []model.Item) {
 	}
 
 	now := time.Now()
-	fmt.Printf("%-12s %-12s %-4s %-6s %s\n", "ID", "STATUS", "PRI", "TYPE", "TITLE")
+	fmt.Printf("%-12s %-12s %-6s %-6s %s\n", "ID", "STATUS", "PRI", "TYPE", "TITLE")
 	for _, item := range items {
-		title := item.Title
+		title := aliasPrefix(item) + item.Title
 		if len(item.Labels) > 0 {
 			title = formatLabels(item.Labels) + " " + title
 		}
 		status := format.StatusDisplay(item, now)
 		// Add ⚠ prefix for stale items
 		if format.IsStale(item, now) {
-			title = "⚠ " + title
+			title = format.Warning("⚠") + " " + title
 		}
-		itemType := string(item.Type)
-		fmt.Printf("%-12s %-12s %-4d %-6s %s\n", item.ID, status, item.Priority, itemType, title)
+		itemType := formatItemType(item.Type)
+		fmt.Printf("%-12s %s %s %s %s%s\n", item.ID, format.Status(status, fmt.Sprintf("%-12s", status)),
+			formatPriorityCell(item.Priority), itemType, title, progressSuffix(item))
 	}
 }
 
@@ -6850,14 +7896,14 @@ func printReadyTable(items []model.Item) {
 		return
 	}
 
-	fmt.Printf("%-12s %-4s %-6s %s\n", "ID", "PRI", "TYPE", "TITLE")
+	fmt.Printf("%-12s %-6s %-6s %s\n", "ID", "PRI", "TYPE", "TITLE")
 	for _, item := range items {
 		title := item.Title
 		if len(item.Labels) > 0 {
 			title = formatLabels(item.Labels) + " " + title
 		}
-		itemType := string(item.Type)
-		fmt.Printf("%-12s %-4d %-6s %s\n", item.ID, item.Priority, itemType, title)
+		itemType := formatItemType(item.Type)
+		fmt.Printf("%-12s %s %s %s\n", item.ID, formatPriorityCell(item.Priority), itemType, title)
 	}
 }
 
@@ -6870,7 +7916,10 @@ func printReadyTable(items []model.Item) {
 //	└── ts-jkl Ready Task 3
 //
 //	ts-pqr Top-level Ready Task 5
-func printReadyTreeWithEpicCounts(result *db.ReadyResult) {
+//
+// With keepOrder, tasks keep the order of result.ReadyItems and epics are
+// listed in the order their first task appears.
+func printReadyTreeWithEpicCounts(result *db.ReadyResult, weights model.LabelWeights, keepOrder bool) {
 	if len(result.ReadyItems) == 0 {
 		fmt.Println("No items")
 		return
@@ -6894,23 +7943,45 @@ func printReadyTreeWithEpicCounts(result *db.ReadyResult) {
 		}
 	}
 
-	// Sort epic IDs for consistent output (by epic priority, then title)
+	// Sort epic IDs for consistent output (epics holding weighted-label tasks
+	// first, then by epic priority, then title)
 	type epicSort struct {
 		id       string
+		weight   int
 		priority int
 		title    string
 	}
 	var sortedEpics []epicSort
+	firstSeen := make(map[string]int)
+	for _, item := range result.ReadyItems {
+		if epicID, ok := result.TaskEpicMap[item.ID]; ok {
+			if _, seen := firstSeen[epicID]; !seen {
+				firstSeen[epicID] = len(firstSeen)
+			}
+		}
+	}
 	for epicID, count := range result.EpicCounts {
 		if count.Epic != nil {
-			sortedEpics = append(sortedEpics, epicSort{
+			es := epicSort{
 				id:       epicID,
 				priority: count.Epic.Priority,
 				title:    count.Epic.Title,
-			})
+			}
+			for i, task := range epicTasks[epicID] {
+				if w := weights.Weight(task.Labels); i == 0 || w > es.weight {
+					es.weight = w
+				}
+			}
+			sortedEpics = append(sortedEpics, es)
 		}
 	}
 	sort.Slice(sortedEpics, func(i, j int) bool {
+		if keepOrder {
+			return firstSeen[sortedEpics[i].id] < firstSeen[sortedEpics[j].id]
+		}
+		if sortedEpics[i].weight != sortedEpics[j].weight {
+			return sortedEpics[i].weight > sortedEpics[j].weight
+		}
 		if sortedEpics[i].priority != sortedEpics[j].priority {
 			return sortedEpics[i].priority < sortedEpics[j].priority
 		}
@@ -6925,13 +7996,10 @@ func printReadyTreeWithEpicCounts(result *db.ReadyResult) {
 			continue // Skip epics with no ready tasks in our list
 		}
 
-		// Sort tasks by priority
-		sort.Slice(tasks, func(i, j int) bool {
-			if tasks[i].Priority != tasks[j].Priority {
-				return tasks[i].Priority < tasks[j].Priority
-			}
-			return tasks[i].Title < tasks[j].Title
-		})
+		// Sort tasks by label weight, then priority
+		if !keepOrder {
+			sort.SliceStable(tasks, func(i, j int) bool { return weights.ReadyLess(tasks[i], tasks[j]) })
+		}
 
 		// Print epic header
 		fmt.Printf("%s %s (%d / %d tasks ready)\n",
@@ -6957,13 +8025,10 @@ func printReadyTreeWithEpicCounts(result *db.ReadyResult) {
 
 	// Print top-level tasks (no epic parent)
 	if len(topLevelTasks) > 0 {
-		// Sort by priority
-		sort.Slice(topLevelTasks, func(i, j int) bool {
-			if topLevelTasks[i].Priority != topLevelTasks[j].Priority {
-				return topLevelTasks[i].Priority < topLevelTasks[j].Priority
-			}
-			return topLevelTasks[i].Title < topLevelTasks[j].Title
-		})
+		// Sort by label weight, then priority
+		if !keepOrder {
+			sort.SliceStable(topLevelTasks, func(i, j int) bool { return weights.ReadyLess(topLevelTasks[i], topLevelTasks[j]) })
+		}
 
 		for _, task := range topLevelTasks {
 			title := task.Title
@@ -6975,6 +8040,34 @@ func printReadyTreeWithEpicCounts(result *db.ReadyResult) {
 	}
 }
 
+// readyBlockerLimit caps how many blocking chains 'tpg ready' prints.
+const readyBlockerLimit = 3
+
+// printBlockingChains explains an empty ready list by showing the unmet
+// dependencies that gate the most open tasks in scope.
+func printBlockingChains(database *db.DB, epicID, project string) error {
+	chains, err := database.BlockingChains(epicID, project, readyBlockerLimit)
+	if err != nil {
+		return err
+	}
+	if len(chains) == 0 {
+		return nil
+	}
+
+	fmt.Println("\nTop blockers:")
+	for _, c := range chains {
+		fmt.Printf("  %s %s [%s] — gates %d task(s)", c.Blocker.ID, c.Blocker.Title, c.Blocker.Status, c.Gated)
+		if len(c.Unlocks) > 0 {
+			fmt.Printf(", unlocks %d when done", len(c.Unlocks))
+		}
+		fmt.Println()
+		for _, p := range c.Path {
+			fmt.Printf("    waiting on %s %s [%s]\n", p.ID, p.Title, p.Status)
+		}
+	}
+	return nil
+}
+
 // treeNode represents an item in the hierarchical tree view.
 type treeNode struct {
 	Item        model.Item
@@ -7096,9 +8189,9 @@ func printItemsTree(items []model.Item) {
 	nodes := buildTreeNodes(items)
 	now := time.Now()
 
-	fmt.Printf("%-12s %-12s %-4s %-6s %s\n", "ID", "STATUS", "PRI", "TYPE", "TITLE")
+	fmt.Printf("%-12s %-12s %-6s %-6s %s\n", "ID", "STATUS", "PRI", "TYPE", "TITLE")
 	for _, node := range nodes {
-		title := node.Item.Title
+		title := aliasPrefix(node.Item) + node.Item.Title
 		if len(node.Item.Labels) > 0 {
 			title = formatLabels(node.Item.Labels) + " " + title
 		}
@@ -7106,13 +8199,25 @@ func printItemsTree(items []model.Item) {
 		status := format.StatusDisplay(node.Item, now)
 		// Add ⚠ prefix for stale items
 		if format.IsStale(node.Item, now) {
-			title = "⚠ " + title
+			title = format.Warning("⚠") + " " + title
 		}
-		itemType := string(node.Item.Type)
-		fmt.Printf("%-12s %-12s %-4d %-6s %s%s\n", node.Item.ID, status, node.Item.Priority, itemType, prefix, title)
+		itemType := formatItemType(node.Item.Type)
+		fmt.Printf("%-12s %s %s %s %s%s%s\n", node.Item.ID, format.Status(status, fmt.Sprintf("%-12s", status)),
+			formatPriorityCell(node.Item.Priority), itemType, prefix, title, progressSuffix(node.Item))
 	}
 }
 
+// addSortFlags registers --sort and --reverse on a listing command.
+func addSortFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagSort, "sort", "", "Sort by "+strings.Join(db.SortFields, "|")+" (default: priority)")
+	cmd.Flags().BoolVar(&flagReverse, "reverse", false, "Reverse the sort order")
+}
+
+// sortOrderFromFlags returns the ordering requested by --sort and --reverse.
+func sortOrderFromFlags() db.SortOrder {
+	return db.SortOrder{Field: flagSort, Reverse: flagReverse}
+}
+
 // formatLabels returns labels in [label1] [label2] format.
 func formatLabels(labels []string) string {
 	if len(labels) == 0 {
@@ -7120,11 +8225,40 @@ func formatLabels(labels []string) string {
 	}
 	var parts []string
 	for _, l := range labels {
-		parts = append(parts, "["+l+"]")
+		parts = append(parts, format.Label(l))
 	}
 	return strings.Join(parts, " ")
 }
 
+// formatPriorityCell returns a priority name padded for the PRI column and
+// colored for its level.
+func formatPriorityCell(p int) string {
+	return format.Priority(p, fmt.Sprintf("%-6s", model.PriorityName(p)))
+}
+
+// registerLabelColors passes the label colors from the labels table to the
+// output formatter. It does nothing when color is off.
+func registerLabelColors(database *db.DB) {
+	if !format.ColorEnabled() {
+		return
+	}
+	project, err := resolveProject()
+	if err != nil {
+		return
+	}
+	labels, err := database.ListLabels(project)
+	if err != nil {
+		return
+	}
+	colors := make(map[string]string)
+	for _, l := range labels {
+		if l.Color != "" {
+			colors[l.Name] = l.Color
+		}
+	}
+	format.SetLabelColors(colors)
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
@@ -7144,9 +8278,37 @@ func isProgressMessage(message string) bool {
 	return strings.HasPrefix(trimmed, "progress:")
 }
 
+// latestProgressPercent returns the percentage from the most recent log that
+// recorded one, or nil if none did.
+func latestProgressPercent(logs []model.Log) *int {
+	for i := len(logs) - 1; i >= 0; i-- {
+		if logs[i].Progress != nil {
+			return logs[i].Progress
+		}
+	}
+	return nil
+}
+
+// aliasPrefix shows an item's alias ahead of its title.
+func aliasPrefix(item model.Item) string {
+	if item.Alias == "" {
+		return ""
+	}
+	return format.Dim("@"+item.Alias) + " "
+}
+
+// progressSuffix returns a progress bar to append to an item's list entry,
+// or "" unless the item is in progress with a recorded percentage.
+func progressSuffix(item model.Item) string {
+	if item.Status != model.StatusInProgress || item.Progress == nil {
+		return ""
+	}
+	return "  " + format.ProgressBar(*item.Progress)
+}
+
 func latestProgressLog(logs []model.Log) *model.Log {
 	for i := len(logs) - 1; i >= 0; i-- {
-		if isProgressMessage(logs[i].Message) {
+		if logs[i].Category == model.LogCategoryProgress {
 			return &logs[i]
 		}
 	}
@@ -7228,7 +8390,7 @@ type DepEdgeJSON struct {
 	DependsOnStatus string `json:"depends_on_status"`
 }
 
-func printItemDetail(item *model.Item, logs []model.Log, deps []string, blockers []db.DepStatus, latestProgress *model.Log, concepts []model.Concept, templateNotice string, showVars bool, worktreeInfo *WorktreeInfo, epicPath []model.Item, sharedContext []db.SharedContextEntry, incompleteChildren []model.Item) {
+func printItemDetail(item *model.Item, logs []model.Log, deps []string, blockers []db.DepStatus, latestProgress *model.Log, concepts []model.Concept, templateNotice string, showVars bool, worktreeInfo *WorktreeInfo, epicPath []model.Item, sharedContext []db.SharedContextEntry, incompleteChildren []model.Item, agentName string) {
 	fmt.Printf("ID:          %s\n", item.ID)
 	fmt.Printf("Type:        %s\n", item.Type)
 	fmt.Printf("Project:     %s\n", item.Project)
@@ -7236,17 +8398,32 @@ func printItemDetail(item *model.Item, logs []model.Log, deps []string, blockers
 	now := time.Now()
 	status := format.StatusDisplay(*item, now)
 	if format.IsStale(*item, now) {
-		fmt.Printf("Status:      %s [STALE]\n", status)
+		fmt.Printf("Status:      %s %s\n", format.Status(status, status), format.Warning("[STALE]"))
 	} else {
-		fmt.Printf("Status:      %s\n", status)
+		fmt.Printf("Status:      %s\n", format.Status(status, status))
+	}
+	if item.Status == model.StatusPendingReview {
+		fmt.Printf("Approval:    pending ('tpg approve %s' completes it)\n", item.ID)
+	} else if item.NeedsApproval && !isClosedStatus(item.Status) {
+		fmt.Println("Approval:    needed ('tpg done' submits it for review)")
+	}
+	if item.Progress != nil {
+		fmt.Printf("Progress:    %s\n", format.ProgressBar(*item.Progress))
+	}
+	fmt.Printf("Priority:    %s\n", format.Priority(item.Priority, fmt.Sprintf("%d (%s)", item.Priority, model.PriorityName(item.Priority))))
+	if agentName != "" {
+		fmt.Printf("Agent:       %s\n", agentName)
 	}
-	fmt.Printf("Priority:    %d\n", item.Priority)
 	if item.ParentID != nil {
 		fmt.Printf("Parent:      %s\n", *item.ParentID)
 	}
 	if len(item.Labels) > 0 {
 		fmt.Printf("Labels:      %s\n", strings.Join(item.Labels, ", "))
 	}
+	if len(item.Fields) > 0 {
+		fmt.Printf("Fields:\n")
+		printFields(item.Fields, "  ")
+	}
 	if item.TemplateID != "" {
 		fmt.Printf("Template:    %s\n", item.TemplateID)
 		if item.StepIndex != nil {
@@ -7330,7 +8507,11 @@ func printItemDetail(item *model.Item, logs []model.Log, deps []string, blockers
 	if len(blockers) > 0 {
 		fmt.Printf("  Blockers:\n")
 		for _, dep := range blockers {
-			fmt.Printf("    - %s [%s] %s\n", dep.ID, dep.Status, dep.Title)
+			group := ""
+			if dep.Group != "" {
+				group = format.Dim(" (group: " + dep.Group + ")")
+			}
+			fmt.Printf("    - %s [%s] %s%s\n", dep.ID, dep.Status, dep.Title, group)
 		}
 	} else {
 		fmt.Printf("  Blockers: none\n")
@@ -7384,7 +8565,7 @@ func printItemDetail(item *model.Item, logs []model.Log, deps []string, blockers
 			fmt.Printf("\nLogs: %d entries\n", len(logs))
 		}
 		for _, log := range displayLogs {
-			fmt.Printf("  [%s] %s\n", log.CreatedAt.Format("2006-01-02 15:04"), log.Message)
+			fmt.Printf("  [%s] #%d %s\n", log.CreatedAt.Format("2006-01-02 15:04"), log.ID, log.Message)
 		}
 		if truncated > 0 {
 			fmt.Printf("  ... (%d earlier logs truncated)\n", truncated)
@@ -7488,6 +8669,17 @@ func printItemYAML(item *model.Item, logs []model.Log, deps []string, blockers [
 	if len(item.Labels) > 0 {
 		fmt.Printf("  labels: [%s]\n", strings.Join(item.Labels, ", "))
 	}
+	if len(item.Fields) > 0 {
+		fmt.Printf("  fields:\n")
+		for _, key := range sortedFieldKeys(item.Fields) {
+			value := item.Fields[key]
+			if strings.Contains(value, "\n") {
+				fmt.Printf("    %s: |\n%s\n", key, indentLines(value, "      "))
+			} else {
+				fmt.Printf("    %s: %q\n", key, value)
+			}
+		}
+	}
 	if item.Description != "" {
 		fmt.Printf("  description: |\n%s\n", indentLines(item.Description, "    "))
 	}
@@ -7500,7 +8692,8 @@ func printItemYAML(item *model.Item, logs []model.Log, deps []string, blockers [
 	if len(logs) > 0 {
 		fmt.Printf("logs:\n")
 		for _, log := range logs {
-			fmt.Printf("  - time: %s\n", log.CreatedAt.Format(time.RFC3339))
+			fmt.Printf("  - id: %d\n", log.ID)
+			fmt.Printf("    time: %s\n", log.CreatedAt.Format(time.RFC3339))
 			fmt.Printf("    message: %q\n", log.Message)
 		}
 	}
@@ -7544,13 +8737,16 @@ func printItemMarkdown(item *model.Item, logs []model.Log, deps []string, blocke
 	fmt.Printf("**Type:** %s  \n", item.Type)
 	fmt.Printf("**Project:** %s  \n", item.Project)
 	fmt.Printf("**Status:** %s  \n", item.Status)
-	fmt.Printf("**Priority:** %d  \n", item.Priority)
+	fmt.Printf("**Priority:** %d (%s)  \n", item.Priority, model.PriorityName(item.Priority))
 	if item.ParentID != nil {
 		fmt.Printf("**Parent:** %s  \n", *item.ParentID)
 	}
 	if len(item.Labels) > 0 {
 		fmt.Printf("**Labels:** %s  \n", strings.Join(item.Labels, ", "))
 	}
+	for _, key := range sortedFieldKeys(item.Fields) {
+		fmt.Printf("**%s:** %s  \n", key, item.Fields[key])
+	}
 	fmt.Println()
 
 	if item.Description != "" {
@@ -7649,17 +8845,25 @@ func printStatusReport(report *db.StatusReport, showAll bool) {
 	if project == "" {
 		project = "(all)"
 	}
-	fmt.Printf("Project: %s\n\n", project)
+	fmt.Printf("Project: %s\n", project)
+	if report.AgentID != "" {
+		fmt.Printf("Agent:   %s\n", agentDisplayName(report.AgentNames, report.AgentID))
+	}
+	fmt.Println()
 
-	fmt.Printf("Summary: %d open, %d in progress, %d blocked, %d done, %d canceled (%d ready)\n\n",
+	fmt.Printf("Summary: %d open, %d in progress, %d blocked, %d done, %d canceled (%d ready)\n",
 		report.Open, report.InProgress, report.Blocked, report.Done, report.Canceled, report.Ready)
+	if report.PendingReview > 0 {
+		fmt.Printf("         %d pending review (see 'tpg list --status pending_review')\n", report.PendingReview)
+	}
+	fmt.Println()
 
 	// Show project in output when viewing all projects
 	showProject := report.Project == ""
 
 	// Show stale items first (important warning)
 	if len(report.StaleItems) > 0 {
-		fmt.Printf("⚠️  Stale (%d task(s) with no updates >5min):\n", len(report.StaleItems))
+		fmt.Println(format.Warning(fmt.Sprintf("⚠️  Stale (%d task(s) with no recent updates):", len(report.StaleItems))))
 		if len(report.StaleItems) <= 20 {
 			for _, item := range report.StaleItems {
 				fmt.Printf("  %s\n", formatStatusItem(item, showProject, false))
@@ -7676,38 +8880,59 @@ func printStatusReport(report *db.StatusReport, showAll bool) {
 	}
 
 	if len(report.RecentDone) > 0 {
-		fmt.Println("Recently completed:")
+		fmt.Println(format.Status("done", "Recently completed:"))
 		for _, item := range report.RecentDone {
 			fmt.Printf("  %s\n", formatStatusItem(item, showProject, false))
 		}
 		fmt.Println()
 	}
 
+	if len(report.NewlyUnblocked) > 0 {
+		fmt.Println("Newly unblocked (last 24h):")
+		for _, item := range report.NewlyUnblocked {
+			fmt.Printf("  %s\n", formatStatusItem(item, showProject, false))
+		}
+		fmt.Println()
+	}
+
 	// Show agent-aware in-progress sections if agent context is active
 	if report.AgentID != "" {
 		if len(report.MyInProgItems) > 0 {
-			fmt.Println("My work in progress:")
+			fmt.Println(format.Status("in_progress", "My work in progress:"))
 			for _, item := range report.MyInProgItems {
 				fmt.Printf("  %s\n", formatStatusItem(item, showProject, false))
 			}
 			fmt.Println()
 		}
 		if report.OtherInProgCount > 0 {
-			fmt.Printf("Other agents: %d task(s) in progress\n\n", report.OtherInProgCount)
+			fmt.Printf("Other agents: %d task(s) in progress\n", report.OtherInProgCount)
+			now := time.Now()
+			for _, item := range report.InProgItems {
+				if item.AgentID == nil || *item.AgentID == "" || *item.AgentID == report.AgentID {
+					continue
+				}
+				fmt.Printf("  %s @%s %s\n", formatStatusItem(item, showProject, false),
+					agentDisplayName(report.AgentNames, *item.AgentID), agentLivenessMarker(item, now))
+			}
+			fmt.Println()
 		}
 	} else {
 		// No agent context - show all in-progress items together
 		if len(report.InProgItems) > 0 {
-			fmt.Println("In progress:")
+			fmt.Println(format.Status("in_progress", "In progress:"))
 			for _, item := range report.InProgItems {
-				fmt.Printf("  %s\n", formatStatusItem(item, showProject, false))
+				line := formatStatusItem(item, showProject, false)
+				if item.AgentID != nil && *item.AgentID != "" {
+					line += " @" + agentDisplayName(report.AgentNames, *item.AgentID) + " " + agentLivenessMarker(item, time.Now())
+				}
+				fmt.Printf("  %s\n", line)
 			}
 			fmt.Println()
 		}
 	}
 
 	if len(report.BlockedItems) > 0 {
-		fmt.Println("Blocked:")
+		fmt.Println(format.Status("blocked", "Blocked:"))
 		for _, item := range report.BlockedItems {
 			fmt.Printf("  %s\n", formatStatusItem(item, showProject, false))
 		}
@@ -7732,7 +8957,7 @@ func printStatusReport(report *db.StatusReport, showAll bool) {
 	}
 
 	if len(report.ReadyItems) > 0 {
-		fmt.Println("Ready for work:")
+		fmt.Println(format.Heading("Ready for work:"))
 		readyLimit := 10
 		displayItems := report.ReadyItems
 		remaining := 0
@@ -7777,7 +9002,7 @@ func printSummaryStats(stats *db.SummaryStats) {
 
 func formatStatusItem(item model.Item, showProject, showPriority bool) string {
 	var parts []string
-	parts = append(parts, fmt.Sprintf("[%s]", item.ID))
+	parts = append(parts, "["+format.ID(item.ID)+"]")
 	if showProject && item.Project != "" {
 		parts = append(parts, fmt.Sprintf("(%s)", item.Project))
 	}
@@ -7786,7 +9011,7 @@ func formatStatusItem(item model.Item, showProject, showPriority bool) string {
 	}
 	parts = append(parts, item.Title)
 	if showPriority {
-		parts = append(parts, fmt.Sprintf("(pri %d)", item.Priority))
+		parts = append(parts, format.Priority(item.Priority, fmt.Sprintf("(pri %d)", item.Priority)))
 	}
 	return strings.Join(parts, " ")
 }
@@ -7869,6 +9094,9 @@ func printLearnings(learnings []model.Learning) {
 		if l.TaskID != nil {
 			fmt.Printf("Task: %s\n", *l.TaskID)
 		}
+		if l.ReviewAt != nil {
+			fmt.Printf("Review: %s (every %s)\n", l.ReviewAt.Local().Format("2006-01-02"), l.ReviewAfter)
+		}
 	}
 }
 
@@ -8110,7 +9338,7 @@ tpg done <id>           # Mark complete
 tpg block <id> "why"    # Mark blocked
 
 # Creating
-tpg add "title" -p project    # New task
+tpg add "title" --project p   # New task in a project
 tpg add "title" -l bug        # With label
 tpg add "title" -e            # New epic
 
@@ -8125,10 +9353,10 @@ tpg concepts                  # List available concepts
 tpg learn "summary" -c X --detail "explanation"  # Log with both parts
 
 # Filtering
-tpg list -p myproject         # Filter by project
+tpg list --project myproject  # Filter by project
 tpg list --status open        # Filter by status
 tpg list -l bug -l urgent     # Filter by labels (AND)
-tpg ready -p myproject        # Ready in project`)
+tpg ready --project myproject # Ready in project`)
 
 	fmt.Println("\n## Current State")
 
@@ -8171,6 +9399,7 @@ func renderPrime(report *db.StatusReport, config *db.Config, agentCtx db.AgentCo
 
 	// Build template data
 	data := prime.BuildPrimeData(report, config, agentCtx, database)
+	data.FullGuide = flagPrimeFull
 
 	// Render
 	output, err := prime.RenderPrime(templateText, data)
@@ -8225,13 +9454,10 @@ func handlePrimeCustomize() error {
 	}
 
 	// Open in editor
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
-	}
-
-	fmt.Printf("Opening %s in %s...\n", primePath, editor)
-	cmd := exec.Command(editor, primePath)
+	editorName := editor.Resolve()
+	fmt.Printf("Opening %s in %s...\n", primePath, editorName)
+	name, args := editor.Command(editorName, primePath)
+	cmd := exec.Command(name, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -8276,6 +9502,8 @@ func handlePrimeRender(templatePath string, database *db.DB, config *db.Config,
 
 	// Build template data
 	data := prime.BuildPrimeData(report, config, agentCtx, database)
+	data.FullGuide = flagPrimeFull
+	data.FullGuide = flagPrimeFull
 
 	// Render
 	output, err := prime.RenderPrime(string(content), data)
@@ -8327,7 +9555,7 @@ func printImpactJSON(items []db.ImpactItem) error {
 	return nil
 }
 
-func printCompactContent(stats []db.ConceptStats) {
+func printCompactContent(stats []db.ConceptStats, due []model.Learning) {
 	fmt.Println(`# Compact Learnings
 
 Groom learnings and concepts using two phases: **discovery** then **selection**.
@@ -8347,7 +9575,7 @@ Compaction keeps the knowledge base high-signal and navigable.
 Scan all learning summaries grouped by concept:
 
 ` + "```" + `bash
-tpg context -p <project> --summary   # All learnings, grouped by concept
+tpg context --project <project> --summary   # All learnings, grouped by concept
 ` + "```" + `
 
 Flag candidates:
@@ -8410,4 +9638,11 @@ After each batch:
 			fmt.Printf("\nCompaction candidates (5+ learnings): %s\n", strings.Join(candidates, ", "))
 		}
 	}
+
+	// Learnings past their scheduled review
+	if len(due) > 0 {
+		fmt.Printf("\n## Due for Review (%d)\n\n", len(due))
+		printDueLearnings(due)
+		fmt.Println("\nConfirm with `tpg learn confirm <id>` or retire with `tpg learn stale <id>`.")
+	}
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var flagMergeDBDryRun bool
+
+var mergeDBCmd = &cobra.Command{
+	Use:   "merge-db <other.db>",
+	Short: "Merge another tpg database into this one",
+	Long: `Merge the items, dependencies, and logs of another tpg database into this
+one, such as the .tpg/tpg.db of another worktree or branch, for teams that
+commit the database. The other database is read from a private copy and is
+never changed.
+
+Conflicts are resolved by fixed rules, so the same merge always gives the
+same result:
+
+  - Items only in the other database are added with their labels, custom
+    fields, and logs.
+  - An item in both (same ID and creation time) keeps the title, status,
+    description, priority, parent, and results of whichever copy was updated
+    last; ours wins a tie. Labels, custom fields, and logs are combined.
+  - The same ID on two different items (created separately on each side) is
+    an ID collision: the other item is added under a new ID, and its
+    children, dependencies, and logs follow it.
+  - Dependencies are combined, except ones that would close a cycle.
+
+Every conflict and how it was resolved is listed after the merge. The
+database is backed up first ('tpg backups', 'tpg restore'). --dry-run
+merges into a scratch copy and prints the report without changing anything.
+
+Examples:
+  tpg merge-db ../feature-worktree/.tpg/tpg.db --dry-run
+  git show main:.tpg/tpg.db > /tmp/main.db && tpg merge-db /tmp/main.db`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		other, closeOther, err := db.OpenCopy(args[0])
+		if err != nil {
+			return err
+		}
+		defer closeOther()
+
+		var database *db.DB
+		if flagMergeDBDryRun {
+			path, err := db.DefaultPath()
+			if err != nil {
+				return err
+			}
+			scratch, closeScratch, err := db.OpenCopy(path)
+			if err != nil {
+				return err
+			}
+			defer closeScratch()
+			database = scratch
+		} else {
+			database, err = openDB()
+			if err != nil {
+				return err
+			}
+			defer func() { _ = database.Close() }()
+			backupPath, err := database.Backup()
+			if err != nil {
+				return fmt.Errorf("failed to back up before merging: %w", err)
+			}
+			fmt.Printf("Backed up to %s\n", backupPath)
+		}
+
+		report, err := database.MergeDatabase(other, args[0])
+		if err != nil {
+			return err
+		}
+		printMergeReport(report, flagMergeDBDryRun)
+		return nil
+	},
+}
+
+// printMergeReport prints the summary and conflicts of a merge-db run.
+func printMergeReport(report *db.MergeReport, dryRun bool) {
+	verb := "Merged"
+	if dryRun {
+		verb = "Would merge"
+	}
+	fmt.Printf("%s: %d items added, %d updated, %d dependencies, %d logs, %d labels, %d fields\n",
+		verb, len(report.Added), len(report.Updated), report.Deps, report.Logs, report.Labels, report.Fields)
+	if len(report.Conflicts) == 0 {
+		fmt.Println("No conflicts")
+		return
+	}
+	fmt.Printf("\nConflicts (%d):\n", len(report.Conflicts))
+	fmt.Println("  " + strings.Join(report.Conflicts, "\n  "))
+}
+
+func init() {
+	mergeDBCmd.Flags().BoolVar(&flagMergeDBDryRun, "dry-run", false, "Show what would be merged without changing anything")
+	rootCmd.AddCommand(mergeDBCmd)
+}
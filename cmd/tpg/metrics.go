@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/metrics"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// defaultMetricsPrefix starts metric names when metrics.prefix is unset.
+const defaultMetricsPrefix = "tpg"
+
+// metricsState is what the current command records metrics into.
+type metricsState struct {
+	config   db.MetricsConfig
+	command  string // command path without "tpg", e.g. "dep add"
+	recorder *metrics.Recorder
+}
+
+// commandMetrics is non-nil while the current command's metrics are being
+// recorded, which happens only when metrics.exporter is configured.
+var commandMetrics *metricsState
+
+// startMetrics begins recording metrics for cmd when the project has them
+// turned on.
+func startMetrics(cmd *cobra.Command) {
+	config, err := db.LoadConfig()
+	if err != nil || config.Metrics.Exporter == "" {
+		return
+	}
+	recorder := metrics.NewRecorder()
+	commandMetrics = &metricsState{
+		config:   config.Metrics,
+		command:  strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name())),
+		recorder: recorder,
+	}
+	db.TransitionObserver = func(from, to model.Status) {
+		tags := map[string]string{"to": string(to)}
+		if from != "" {
+			tags["from"] = string(from)
+		}
+		recorder.Count("status.transitions", tags)
+	}
+}
+
+// flushMetrics records the command's result and the ready-queue depth, then
+// sends everything recorded to the configured exporter. Failures to send
+// never fail the command; they are reported with --verbose.
+func flushMetrics(cmdErr error) {
+	if commandMetrics == nil {
+		return
+	}
+	m := commandMetrics
+	result := "ok"
+	var exitErr *exitStatusError
+	if cmdErr != nil && !(errors.As(cmdErr, &exitErr) && exitErr.code == 0) {
+		result = "error"
+	}
+	m.recorder.Count("commands", map[string]string{"command": m.command, "result": result})
+	recordReadyDepth(m.recorder)
+
+	if err := sendMetrics(m.config, m.recorder); err != nil && flagVerbose {
+		fmt.Fprintf(os.Stderr, "warning: failed to send metrics: %v\n", err)
+	}
+}
+
+// recordReadyDepth records the number of ready tasks in each project.
+func recordReadyDepth(recorder *metrics.Recorder) {
+	path, err := db.DefaultPath()
+	if err != nil {
+		return
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() { _ = database.Close() }()
+	items, err := database.ReadyItems("")
+	if err != nil {
+		return
+	}
+	depth := map[string]int{}
+	if project, err := db.DefaultProject(); err == nil && project != "" {
+		depth[project] = 0
+	}
+	for _, item := range items {
+		depth[item.Project]++
+	}
+	for project, n := range depth {
+		recorder.Gauge("ready.depth", float64(n), map[string]string{"project": project})
+	}
+}
+
+// sendMetrics flushes recorder to the exporter config names.
+func sendMetrics(config db.MetricsConfig, recorder *metrics.Recorder) error {
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = defaultMetricsPrefix
+	}
+	exporter, err := metrics.New(config.Exporter, config.Endpoint, prefix)
+	if err != nil {
+		return err
+	}
+	return recorder.Flush(exporter, config.Tags)
+}
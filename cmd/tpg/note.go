@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagNoteEpic string
+	flagNoteBody string
+	flagNoteJSON bool
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Manage project and epic notes",
+	Long: `Keep free-form notes such as design decisions and runbooks.
+
+Notes belong to the project (--project) or to an epic (--epic). Unlike an
+epic's description they can grow without cluttering 'tpg show', and they
+are listed by 'tpg context --summary'.
+
+Examples:
+  tpg note add "Release runbook" --body - < RELEASE.md
+  tpg note add "Why we chose SQLite" --epic ep-abc123 --body "..."
+  tpg note list
+  tpg note show note-abc123`,
+}
+
+var noteAddCmd = &cobra.Command{
+	Use:   "add <title>",
+	Short: "Add a note to the project or an epic",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		body, err := readNoteBody(flagNoteBody)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		note := &model.Note{
+			ID:        model.GenerateNoteID(),
+			Project:   project,
+			Title:     strings.Join(args, " "),
+			Body:      body,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if flagNoteEpic != "" {
+			epicID, err := resolveItemArg(database, flagNoteEpic)
+			if err != nil {
+				return err
+			}
+			note.EpicID = &epicID
+		}
+		if err := database.CreateNote(note); err != nil {
+			return err
+		}
+		fmt.Println(note.ID)
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+var noteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List notes in the project or on an epic",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+		epicID := ""
+		if flagNoteEpic != "" {
+			if epicID, err = resolveItemArg(database, flagNoteEpic); err != nil {
+				return err
+			}
+		}
+
+		notes, err := database.ListNotes(project, epicID)
+		if err != nil {
+			return err
+		}
+		if flagNoteJSON {
+			return printNotesJSON(notes)
+		}
+		if len(notes) == 0 {
+			fmt.Println("No notes")
+			return nil
+		}
+		printNoteSummaries(notes)
+		return nil
+	},
+}
+
+var noteShowCmd = &cobra.Command{
+	Use:   "show <note-id>",
+	Short: "Show a note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		note, err := database.GetNote(args[0])
+		if err != nil {
+			return err
+		}
+		if flagNoteJSON {
+			return printNotesJSON([]model.Note{*note})
+		}
+		printNote(note)
+		return nil
+	},
+}
+
+var noteEditCmd = &cobra.Command{
+	Use:   "edit <note-id>",
+	Short: "Replace a note's body",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagNoteBody == "" {
+			return fmt.Errorf("--body is required (use '-' for stdin)")
+		}
+		body, err := readNoteBody(flagNoteBody)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if err := database.UpdateNoteBody(args[0], body); err != nil {
+			return err
+		}
+		fmt.Printf("Updated %s\n", args[0])
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+var noteRmCmd = &cobra.Command{
+	Use:     "rm <note-id>",
+	Aliases: []string{"remove"},
+	Short:   "Delete a note",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		if err := database.DeleteNote(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %s\n", args[0])
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// readNoteBody returns the --body value, reading stdin for '-'.
+func readNoteBody(value string) (string, error) {
+	if value != "-" {
+		return value, nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// noteScope describes where a note lives, for display.
+func noteScope(n model.Note) string {
+	if n.EpicID != nil {
+		return *n.EpicID
+	}
+	return "project " + n.Project
+}
+
+// printNoteSummaries prints one line per note.
+func printNoteSummaries(notes []model.Note) {
+	for _, n := range notes {
+		fmt.Printf("%s  %-20s  %s  (%s)\n", n.ID, noteScope(n), n.Title, formatTimeAgo(n.UpdatedAt))
+	}
+}
+
+// printNote prints a note in full.
+func printNote(n *model.Note) {
+	fmt.Printf("%s: %s\n", n.ID, n.Title)
+	fmt.Printf("Scope: %s\n", noteScope(*n))
+	fmt.Printf("Updated: %s\n", n.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	if n.Body != "" {
+		fmt.Printf("\n%s\n", n.Body)
+	}
+}
+
+// noteJSON is the JSON form of a note.
+type noteJSON struct {
+	ID        string    `json:"id"`
+	Project   string    `json:"project"`
+	EpicID    *string   `json:"epic_id,omitempty"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func printNotesJSON(notes []model.Note) error {
+	out := make([]noteJSON, len(notes))
+	for i, n := range notes {
+		out[i] = noteJSON(n)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func init() {
+	noteCmd.PersistentFlags().BoolVar(&flagNoteJSON, "json", false, "Output as JSON")
+	noteAddCmd.Flags().StringVar(&flagNoteEpic, "epic", "", "Attach the note to an epic instead of the project")
+	noteAddCmd.Flags().StringVar(&flagNoteBody, "body", "", "Note text (use '-' for stdin)")
+	noteListCmd.Flags().StringVar(&flagNoteEpic, "epic", "", "Only notes attached to this epic")
+	noteEditCmd.Flags().StringVar(&flagNoteBody, "body", "", "New note text (use '-' for stdin)")
+
+	noteCmd.AddCommand(noteAddCmd)
+	noteCmd.AddCommand(noteListCmd)
+	noteCmd.AddCommand(noteShowCmd)
+	noteCmd.AddCommand(noteEditCmd)
+	noteCmd.AddCommand(noteRmCmd)
+	rootCmd.AddCommand(noteCmd)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/editor"
+)
+
+var flagNoPager bool
+
+// stopPager, when set, sends the output held back by startPager to the
+// terminal or the pager.
+var stopPager func()
+
+// pagedCommands are the commands whose output can run long enough to need a
+// pager.
+func pagedCommands() []*cobra.Command {
+	return []*cobra.Command{showCmd, planCmd, listCmd, contextCmd}
+}
+
+// pagerCommand returns the pager to use: $TPG_PAGER, then $PAGER, then
+// "less -R". It returns "" when paging is turned off by setting either to
+// "cat" or to nothing.
+func pagerCommand() string {
+	for _, env := range []string{"TPG_PAGER", "PAGER"} {
+		if value, ok := os.LookupEnv(env); ok {
+			value = strings.TrimSpace(value)
+			if value == "cat" {
+				return ""
+			}
+			return value
+		}
+	}
+	return "less -R"
+}
+
+// startPager holds back the output of the paged commands when stdout is a
+// terminal, so it can go through the pager if it turns out taller than the
+// screen, like git does. --no-pager turns this off.
+func startPager(cmd *cobra.Command) {
+	if flagNoPager {
+		return
+	}
+	paged := false
+	for _, c := range pagedCommands() {
+		paged = paged || cmd == c
+	}
+	if !paged || !term.IsTerminal(os.Stdout.Fd()) {
+		return
+	}
+	pager := pagerCommand()
+	if pager == "" {
+		return
+	}
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || height <= 0 {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	orig := os.Stdout
+	os.Stdout = w
+
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(&out, r)
+		_ = r.Close()
+	}()
+
+	stopPager = func() {
+		_ = w.Close()
+		<-done
+		os.Stdout = orig
+		if displayLines(out.String(), width) < height {
+			_, _ = orig.Write(out.Bytes())
+			return
+		}
+		if err := runPager(pager, &out, orig); err != nil {
+			_, _ = orig.Write(out.Bytes())
+		}
+	}
+}
+
+// runPager shows output in pager, returning an error when the pager can't be
+// started.
+func runPager(pager string, output io.Reader, stdout *os.File) error {
+	name, args := editor.Program(pager)
+	c := exec.Command(name, args...)
+	c.Stdin = output
+	c.Stdout = stdout
+	c.Stderr = os.Stderr
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		c.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := c.Start(); err != nil {
+		return err
+	}
+	_ = c.Wait()
+	return nil
+}
+
+// displayLines counts the terminal rows s takes up at the given width,
+// counting lines that wrap once per row.
+func displayLines(s string, width int) int {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return 0
+	}
+	rows := 0
+	for _, line := range strings.Split(s, "\n") {
+		w := ansi.StringWidth(line)
+		if width <= 0 || w <= width {
+			rows++
+		} else {
+			rows += (w + width - 1) / width
+		}
+	}
+	return rows
+}
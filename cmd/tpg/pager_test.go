@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("TPG_PAGER", "")
+	t.Setenv("PAGER", "more")
+	if got := pagerCommand(); got != "" {
+		t.Errorf("pagerCommand() with TPG_PAGER empty = %q, want paging off", got)
+	}
+
+	t.Setenv("TPG_PAGER", "bat --plain")
+	if got := pagerCommand(); got != "bat --plain" {
+		t.Errorf("pagerCommand() = %q, want $TPG_PAGER", got)
+	}
+
+	t.Setenv("TPG_PAGER", "cat")
+	if got := pagerCommand(); got != "" {
+		t.Errorf("pagerCommand() with TPG_PAGER=cat = %q, want paging off", got)
+	}
+}
+
+func TestDisplayLines(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  int
+	}{
+		{"", 80, 0},
+		{"one\ntwo\n", 80, 2},
+		{"one\n\nthree", 80, 3},
+		{"0123456789", 4, 3},
+		{"\x1b[31mred\x1b[0m\n", 3, 1},
+	}
+	for _, tt := range tests {
+		if got := displayLines(tt.s, tt.width); got != tt.want {
+			t.Errorf("displayLines(%q, %d) = %d, want %d", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// planExternalDep is an item outside an epic that tasks inside it depend on.
+type planExternalDep struct {
+	Dep     db.DepStatus
+	Epic    *model.Item // Nearest epic containing the dependency (nil if none)
+	Blocked []string    // Open tasks in the plan that wait on it
+}
+
+// ExternalDepJSON represents a dependency pointing outside the epic
+type ExternalDepJSON struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	EpicID     string   `json:"epic_id,omitempty"`
+	EpicTitle  string   `json:"epic_title,omitempty"`
+	EpicStatus string   `json:"epic_status,omitempty"`
+	Tasks      []string `json:"tasks"`
+}
+
+// planExternalDeps collects the dependencies of an epic's unfinished tasks
+// that point outside the epic, grouped by dependency, in first-seen order.
+// Each is annotated with the epic it belongs to, since cross-epic couplings
+// are the usual reason a plan shows nothing ready.
+func planExternalDeps(database *db.DB, epicID string, descendants []model.Item, depInfo map[string][]db.DepStatus) ([]*planExternalDep, error) {
+	inside := map[string]bool{epicID: true}
+	for _, item := range descendants {
+		inside[item.ID] = true
+	}
+
+	byID := make(map[string]*planExternalDep)
+	var result []*planExternalDep
+	for _, item := range descendants {
+		if item.Status == model.StatusDone || item.Status == model.StatusCanceled {
+			continue
+		}
+		for _, dep := range depInfo[item.ID] {
+			if inside[dep.ID] {
+				continue
+			}
+			ext, ok := byID[dep.ID]
+			if !ok {
+				ext = &planExternalDep{Dep: dep}
+				epic, err := containingEpic(database, dep.ID)
+				if err != nil {
+					return nil, err
+				}
+				ext.Epic = epic
+				byID[dep.ID] = ext
+				result = append(result, ext)
+			}
+			ext.Blocked = append(ext.Blocked, item.ID)
+		}
+	}
+	return result, nil
+}
+
+// containingEpic returns the item itself if it can have children, otherwise
+// its nearest ancestor epic, or nil when it has none.
+func containingEpic(database *db.DB, id string) (*model.Item, error) {
+	item, err := database.GetItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type.CanHaveChildren() {
+		return item, nil
+	}
+	chain, err := database.GetParentChain(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].Type.CanHaveChildren() {
+			return &chain[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// printPlanExternalDeps prints the outside-dependency section of 'tpg plan'.
+func printPlanExternalDeps(external []*planExternalDep) {
+	if len(external) == 0 {
+		return
+	}
+	fmt.Println("\n🔗 Outside Dependencies:")
+	for _, ext := range external {
+		fmt.Printf("   %s [%s] %s\n", ext.Dep.ID, ext.Dep.Status, ext.Dep.Title)
+		if ext.Epic == nil {
+			fmt.Println("      epic: (none)")
+		} else if ext.Epic.ID != ext.Dep.ID {
+			fmt.Printf("      epic: %s [%s] %s\n", ext.Epic.ID, ext.Epic.Status, ext.Epic.Title)
+		}
+		fmt.Printf("      needed by: %s\n", strings.Join(ext.Blocked, ", "))
+	}
+}
+
+// externalDepsJSON converts outside dependencies for 'tpg plan --json'.
+func externalDepsJSON(external []*planExternalDep) []ExternalDepJSON {
+	var out []ExternalDepJSON
+	for _, ext := range external {
+		j := ExternalDepJSON{
+			ID:     ext.Dep.ID,
+			Title:  ext.Dep.Title,
+			Status: ext.Dep.Status,
+			Tasks:  ext.Blocked,
+		}
+		if ext.Epic != nil {
+			j.EpicID = ext.Epic.ID
+			j.EpicTitle = ext.Epic.Title
+			j.EpicStatus = string(ext.Epic.Status)
+		}
+		out = append(out, j)
+	}
+	return out
+}
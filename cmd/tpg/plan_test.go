@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestPlanExternalDeps(t *testing.T) {
+	database := setupTestDB(t)
+
+	createTestItem(t, database, "ep-plan", "Plan", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ep-other", "Other", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ts-a", "A", withParent("ep-plan"))
+	createTestItem(t, database, "ts-b", "B", withParent("ep-plan"))
+	createTestItem(t, database, "ts-done", "Done", withParent("ep-plan"), withStatus(model.StatusDone))
+	createTestItem(t, database, "ts-foreign", "Foreign", withParent("ep-other"))
+	createTestItem(t, database, "ts-loose", "Loose")
+
+	for _, d := range [][2]string{
+		{"ts-b", "ts-a"},          // inside the epic
+		{"ts-a", "ts-foreign"},    // other epic's work
+		{"ts-b", "ts-foreign"},    // same dep, second task
+		{"ts-b", "ts-loose"},      // no epic
+		{"ts-done", "ts-foreign"}, // closed task, ignored
+	} {
+		if err := database.AddDep(d[0], d[1]); err != nil {
+			t.Fatalf("AddDep %v: %v", d, err)
+		}
+	}
+
+	descendants, err := database.GetDescendants("ep-plan")
+	if err != nil {
+		t.Fatalf("GetDescendants: %v", err)
+	}
+	depInfo := make(map[string][]db.DepStatus)
+	for _, item := range descendants {
+		if depInfo[item.ID], err = database.GetAllDepStatuses(item.ID); err != nil {
+			t.Fatalf("GetAllDepStatuses: %v", err)
+		}
+	}
+
+	external, err := planExternalDeps(database, "ep-plan", descendants, depInfo)
+	if err != nil {
+		t.Fatalf("planExternalDeps: %v", err)
+	}
+	got := make(map[string]*planExternalDep)
+	for _, ext := range external {
+		got[ext.Dep.ID] = ext
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 outside deps, got %d", len(got))
+	}
+	foreign := got["ts-foreign"]
+	if foreign == nil || foreign.Epic == nil || foreign.Epic.ID != "ep-other" {
+		t.Errorf("expected ts-foreign attributed to ep-other, got %+v", foreign)
+	} else if len(foreign.Blocked) != 2 {
+		t.Errorf("expected ts-foreign needed by 2 open tasks, got %v", foreign.Blocked)
+	}
+	if loose := got["ts-loose"]; loose == nil || loose.Epic != nil {
+		t.Errorf("expected ts-loose with no epic, got %+v", loose)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// addPolicyViolations lists the policy rules a new item breaks. The
+// description length is only a policy rule when the policy is strict;
+// otherwise the short description warning covers it. checkDesc is false for
+// template items, whose descriptions come from the template.
+func addPolicyViolations(config *db.Config, itemType model.ItemType, parent, description string, prioritySet, checkDesc bool) []string {
+	var violations []string
+	if config.Policy.RequireParent && parent == "" && !itemType.CanHaveChildren() {
+		violations = append(violations, "tasks must have a parent epic (use --parent <epic-id>)")
+	}
+	if config.Policy.RequirePriority && !prioritySet {
+		violations = append(violations, "priority must be set explicitly (use --priority)")
+	}
+	if checkDesc && config.Policy.Strict {
+		minWords := config.PolicyMinDescriptionWords()
+		if words := countWords(description); words < minWords {
+			violations = append(violations, fmt.Sprintf("description must have at least %d words (has %d)", minWords, words))
+		}
+	}
+	return violations
+}
+
+// enforceAddPolicy fails with every violation when policy.strict is set,
+// and otherwise prints them as warnings.
+func enforceAddPolicy(config *db.Config, violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	if config.Policy.Strict {
+		return fmt.Errorf("policy violations (policy.strict is on):\n  - %s", strings.Join(violations, "\n  - "))
+	}
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "WARNING: policy: %s\n", v)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// priorityValue is a flag value accepting a priority number or name
+// (--priority high). The priority is stored as its number.
+type priorityValue struct {
+	p *int
+}
+
+func newPriorityValue(p *int, def int) *priorityValue {
+	*p = def
+	return &priorityValue{p: p}
+}
+
+func (v *priorityValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	return strconv.Itoa(*v.p)
+}
+
+func (v *priorityValue) Set(s string) error {
+	p, err := model.ParsePriority(s)
+	if err != nil {
+		return err
+	}
+	*v.p = p
+	return nil
+}
+
+func (v *priorityValue) Type() string { return "priority" }
+
+// priorityFlagUsage is the help text shared by --priority flags.
+const priorityFlagUsage = "Priority: 1-5 or high, medium, low (critical = high)"
+
+// addPriorityFlag registers a --priority/-p flag on cmd.
+func addPriorityFlag(cmd *cobra.Command, p *int, def int, shorthand bool) {
+	short := ""
+	if shorthand {
+		short = "p"
+	}
+	cmd.Flags().VarP(newPriorityValue(p, def), "priority", short, priorityFlagUsage)
+	_ = cmd.RegisterFlagCompletionFunc("priority", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return model.PriorityNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
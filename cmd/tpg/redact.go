@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var (
+	flagRedactPatterns []string
+	flagRedactDryRun   bool
+)
+
+var redactCmd = &cobra.Command{
+	Use:   "redact <id>...",
+	Short: "Scrub sensitive text from a task's description, results, and logs",
+	Long: `Replace every match of the given regular expressions with [REDACTED] in
+a task's description, results, log entries, and transcripts, and in the
+history events and description versions that recorded earlier copies of
+them.
+
+Patterns listed under "redact_patterns" in .tpg/config.json are always
+applied, so common secret formats only need to be configured once:
+
+  "redact_patterns": ["sk-[A-Za-z0-9]{20,}", "AKIA[0-9A-Z]{16}"]
+
+A "redacted" history event records how many matches were replaced, never the
+text itself. Database backups made before the redaction still contain the
+original text; see 'tpg backups'.
+
+Examples:
+  tpg redact ts-a1b2c3 --pattern 'password=\S+'
+  tpg redact ts-a1b2c3 ts-d4e5f6 --dry-run
+  tpg redact ts-a1b2c3              # configured patterns only`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		patterns, err := redactPatterns(flagRedactPatterns)
+		if err != nil {
+			return err
+		}
+		if len(patterns) == 0 {
+			return fmt.Errorf("no patterns: pass --pattern or set redact_patterns in .tpg/config.json")
+		}
+
+		changed := false
+		for _, id := range args {
+			if id, err = resolveItemArg(database, id); err != nil {
+				return err
+			}
+			result, err := database.RedactItem(id, patterns, flagRedactDryRun)
+			if err != nil {
+				return err
+			}
+			verb := "Redacted"
+			if flagRedactDryRun {
+				verb = "Would redact"
+			}
+			if result.Total() == 0 {
+				fmt.Printf("%s: no matches\n", id)
+				continue
+			}
+			fmt.Printf("%s %d matches in %s (description %d, results %d, logs %d, history %d, old descriptions %d, transcripts %d)\n",
+				verb, result.Total(), id, result.Description, result.Results, result.Logs, result.History, result.Versions, result.Transcripts)
+			changed = true
+		}
+		if changed && !flagRedactDryRun {
+			database.BackupQuiet()
+		}
+		return nil
+	},
+}
+
+// redactPatterns compiles the --pattern flags and appends the patterns
+// configured in redact_patterns.
+func redactPatterns(exprs []string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, re)
+	}
+	config, err := db.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	configured, err := config.CompileRedactPatterns()
+	if err != nil {
+		return nil, err
+	}
+	return append(patterns, configured...), nil
+}
+
+func init() {
+	redactCmd.Flags().StringArrayVar(&flagRedactPatterns, "pattern", nil, "Regular expression to redact (repeatable)")
+	redactCmd.Flags().BoolVar(&flagRedactDryRun, "dry-run", false, "Count matches without changing anything")
+	rootCmd.AddCommand(redactCmd)
+}
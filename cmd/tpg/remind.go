@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Show items that need attention",
+	Long: `List hygiene items worth acting on before starting new work:
+
+  - Stale: in progress with no updates past the stale threshold
+  - Overdue: unfinished items whose 'due' field (YYYY-MM-DD) has passed
+  - Unblocked: marked blocked, but every dependency is done
+  - Closable epics: open epics whose children are all closed
+
+The same list appears in 'tpg prime'. Set a due date with
+'tpg field set <id> due 2026-03-01'.
+
+Examples:
+  tpg remind
+  tpg remind --project myapp`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		report, err := database.NeedsAttention(project, time.Now())
+		if err != nil {
+			return err
+		}
+		if report.Total() == 0 {
+			fmt.Println("Nothing needs attention")
+			return nil
+		}
+		printAttention(report)
+		return nil
+	},
+}
+
+// printAttention prints each non-empty section of an attention report with
+// the command that resolves it.
+func printAttention(report *db.AttentionReport) {
+	section := func(title, hint string, items []model.Item, detail func(model.Item) string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Printf("%s (%d) - %s\n", title, len(items), hint)
+		for _, item := range items {
+			fmt.Printf("  %s  %s%s\n", item.ID, item.Title, detail(item))
+		}
+		fmt.Println()
+	}
+
+	section("Stale", "log progress or hand off", report.Stale, func(item model.Item) string {
+		return fmt.Sprintf(" (updated %s)", formatTimeAgo(item.UpdatedAt))
+	})
+	section("Overdue", "finish, re-plan, or move the due date", report.Overdue, func(item model.Item) string {
+		return fmt.Sprintf(" (due %s)", item.Fields[db.DueField])
+	})
+	section("Unblocked", "tpg reopen <id>", report.Unblocked, func(model.Item) string { return "" })
+	section("Closable epics", "tpg done <id>", report.Closable, func(model.Item) string { return "" })
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+}
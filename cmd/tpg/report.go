@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagReportOut       string
+	flagReportLearnings int
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate reports for people who don't use the CLI",
+}
+
+var reportHTMLCmd = &cobra.Command{
+	Use:   "html",
+	Short: "Write a static HTML dashboard of the project",
+	Long: `Write a single self-contained HTML file summarizing the project:
+
+  - counts by status
+  - every epic with its task tree and progress
+  - the dependency graph of unfinished work (mermaid)
+  - recent learnings
+
+Styles are inlined so the file can be published as a CI artifact. The
+dependency graph is drawn by mermaid loaded from a CDN; without network access
+the graph source is shown instead.
+
+Examples:
+  tpg report html --out report.html
+  tpg report html --project myproject --out public/index.html
+  tpg report html --out - > report.html`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		report, err := buildHTMLReport(database, project, flagReportLearnings)
+		if err != nil {
+			return err
+		}
+
+		if flagReportOut == "-" {
+			return renderHTMLReport(os.Stdout, report)
+		}
+		f, err := os.Create(flagReportOut)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		if err := renderHTMLReport(f, report); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		fmt.Printf("Wrote %s\n", flagReportOut)
+		return nil
+	},
+}
+
+// htmlReport is the data rendered by 'tpg report html'.
+type htmlReport struct {
+	Project   string
+	Generated time.Time
+	Status    *db.StatusReport
+	Epics     []*reportNode
+	Graph     string // mermaid flowchart source; empty when there are no deps
+	Learnings []model.Learning
+}
+
+// reportNode is an item in an epic tree.
+type reportNode struct {
+	Item     model.Item
+	Children []*reportNode
+	Done     int // finished descendants (done or canceled)
+	Total    int // all descendants
+}
+
+// Percent is the share of finished descendants.
+func (n *reportNode) Percent() int {
+	if n.Total == 0 {
+		return 0
+	}
+	return n.Done * 100 / n.Total
+}
+
+func buildHTMLReport(database *db.DB, project string, learningLimit int) (*htmlReport, error) {
+	status, err := database.ProjectStatus(project)
+	if err != nil {
+		return nil, err
+	}
+	items, err := database.ListItemsFiltered(db.ListFilter{Project: project})
+	if err != nil {
+		return nil, err
+	}
+	if err := renderTemplatesForItems(items); err != nil {
+		return nil, err
+	}
+	edges, err := database.GetAllDeps(project)
+	if err != nil {
+		return nil, err
+	}
+	learnings, err := database.GetAllLearnings(project, false)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(learnings, func(i, j int) bool {
+		return learnings[i].CreatedAt.After(learnings[j].CreatedAt)
+	})
+	if learningLimit >= 0 && len(learnings) > learningLimit {
+		learnings = learnings[:learningLimit]
+	}
+
+	return &htmlReport{
+		Project:   project,
+		Generated: time.Now(),
+		Status:    status,
+		Epics:     reportEpicTrees(items),
+		Graph:     mermaidDepGraph(edges),
+		Learnings: learnings,
+	}, nil
+}
+
+// reportEpicTrees builds a tree for every top-level epic. Epics nested under
+// another epic appear inside their parent's tree.
+func reportEpicTrees(items []model.Item) []*reportNode {
+	nodes := make(map[string]*reportNode, len(items))
+	for _, item := range items {
+		nodes[item.ID] = &reportNode{Item: item}
+	}
+	var roots []*reportNode
+	for _, item := range items {
+		node := nodes[item.ID]
+		if item.ParentID != nil {
+			if parent, ok := nodes[*item.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		if item.Type == model.ItemTypeEpic {
+			roots = append(roots, node)
+		}
+	}
+
+	var count func(n *reportNode)
+	count = func(n *reportNode) {
+		for _, c := range n.Children {
+			count(c)
+			n.Total += c.Total + 1
+			n.Done += c.Done
+			if c.Item.Status == model.StatusDone || c.Item.Status == model.StatusCanceled {
+				n.Done++
+			}
+		}
+	}
+	for _, r := range roots {
+		count(r)
+	}
+	return roots
+}
+
+// mermaidDepGraph renders the dependencies of unfinished items as a mermaid
+// flowchart, pointing from each blocker to the item waiting on it.
+func mermaidDepGraph(edges []db.DepEdge) string {
+	var b strings.Builder
+	declared := make(map[string]bool)
+	node := func(id, title, status string) string {
+		name := strings.NewReplacer("-", "_", ".", "_").Replace(id)
+		if !declared[id] {
+			declared[id] = true
+			label := strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(id + ": " + title)
+			fmt.Fprintf(&b, "  %s[\"%s\"]:::%s\n", name, label, status)
+		}
+		return name
+	}
+
+	n := 0
+	for _, e := range edges {
+		if e.ItemStatus == string(model.StatusDone) || e.ItemStatus == string(model.StatusCanceled) {
+			continue
+		}
+		if n == 0 {
+			b.WriteString("flowchart LR\n")
+		}
+		from := node(e.DependsOnID, e.DependsOnTitle, e.DependsOnStatus)
+		to := node(e.ItemID, e.ItemTitle, e.ItemStatus)
+		fmt.Fprintf(&b, "  %s --> %s\n", from, to)
+		n++
+	}
+	if n == 0 {
+		return ""
+	}
+	b.WriteString("  classDef open fill:#eef,stroke:#669\n")
+	b.WriteString("  classDef in_progress fill:#ffe9b3,stroke:#c90\n")
+	b.WriteString("  classDef blocked fill:#fdd,stroke:#c33\n")
+	b.WriteString("  classDef done fill:#dfd,stroke:#393\n")
+	b.WriteString("  classDef canceled fill:#eee,stroke:#999\n")
+	return b.String()
+}
+
+func renderHTMLReport(w io.Writer, report *htmlReport) error {
+	if err := htmlReportTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .Project}}{{.Project}} — {{end}}tpg report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; padding: 0 1em; color: #222; }
+h1 { margin-bottom: 0; }
+.generated { color: #777; margin-top: .2em; }
+.counts { display: flex; gap: 1em; flex-wrap: wrap; }
+.count { border: 1px solid #ddd; border-radius: 6px; padding: .6em 1.2em; text-align: center; }
+.count b { display: block; font-size: 1.6em; }
+ul.tree { list-style: none; padding-left: 1.2em; }
+ul.tree li { margin: .2em 0; }
+.id { font-family: monospace; color: #555; }
+.status { font-size: .8em; padding: 0 .4em; border-radius: 3px; background: #eef; }
+.status.in_progress { background: #ffe9b3; }
+.status.blocked { background: #fdd; }
+.status.done { background: #dfd; }
+.status.canceled { background: #eee; color: #777; }
+.bar { display: inline-block; width: 120px; height: .6em; background: #eee; border-radius: 3px; vertical-align: middle; }
+.bar span { display: block; height: 100%; background: #393; border-radius: 3px; }
+.learning { margin: .8em 0; }
+.learning .concepts { color: #777; font-size: .9em; }
+pre.mermaid { background: #fafafa; border: 1px solid #eee; padding: 1em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>{{if .Project}}{{.Project}}{{else}}All projects{{end}}</h1>
+<p class="generated">Generated {{date .Generated}}</p>
+
+<h2>Status</h2>
+<div class="counts">
+  <div class="count"><b>{{.Status.Open}}</b>open</div>
+  <div class="count"><b>{{.Status.Ready}}</b>ready</div>
+  <div class="count"><b>{{.Status.InProgress}}</b>in progress</div>
+  <div class="count"><b>{{.Status.Blocked}}</b>blocked</div>
+  <div class="count"><b>{{.Status.Done}}</b>done</div>
+  <div class="count"><b>{{.Status.Canceled}}</b>canceled</div>
+</div>
+
+<h2>Epics</h2>
+{{- if .Epics}}
+<ul class="tree">
+{{- range .Epics}}{{template "node" .}}{{end}}
+</ul>
+{{- else}}
+<p>No epics.</p>
+{{- end}}
+
+<h2>Dependencies</h2>
+{{- if .Graph}}
+<pre class="mermaid">
+{{.Graph}}</pre>
+<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+mermaid.initialize({ startOnLoad: true });
+</script>
+{{- else}}
+<p>No dependencies between unfinished items.</p>
+{{- end}}
+
+<h2>Recent learnings</h2>
+{{- if .Learnings}}
+{{- range .Learnings}}
+<div class="learning">
+  <span class="id">{{.ID}}</span> <b>{{.Summary}}</b> <span class="generated">{{date .CreatedAt}}</span>
+  {{- if .Concepts}}<div class="concepts">{{range $i, $c := .Concepts}}{{if $i}}, {{end}}{{$c}}{{end}}</div>{{end}}
+  {{- if .Detail}}<div>{{.Detail}}</div>{{end}}
+</div>
+{{- end}}
+{{- else}}
+<p>No learnings yet.</p>
+{{- end}}
+</body>
+</html>
+{{define "node"}}
+<li><span class="id">{{.Item.ID}}</span> <span class="status {{.Item.Status}}">{{.Item.Status}}</span> {{.Item.Title}}
+{{- if .Total}} <span class="bar"><span style="width: {{.Percent}}%"></span></span> {{.Done}}/{{.Total}}{{end}}
+{{- if .Children}}
+<ul class="tree">
+{{- range .Children}}{{template "node" .}}{{end}}
+</ul>
+{{- end}}
+</li>
+{{- end}}
+`))
+
+func init() {
+	reportHTMLCmd.Flags().StringVarP(&flagReportOut, "out", "o", "report.html", "Output file ('-' for stdout)")
+	reportHTMLCmd.Flags().IntVar(&flagReportLearnings, "learnings", 10, "Number of recent learnings to include (-1 for all)")
+	reportCmd.AddCommand(reportHTMLCmd)
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestReportEpicTrees(t *testing.T) {
+	epic := "ep-1"
+	sub := "ep-2"
+	items := []model.Item{
+		{ID: "ep-1", Type: model.ItemTypeEpic, Status: model.StatusOpen},
+		{ID: "ep-2", Type: model.ItemTypeEpic, Status: model.StatusOpen, ParentID: &epic},
+		{ID: "ts-1", Type: model.ItemTypeTask, Status: model.StatusDone, ParentID: &epic},
+		{ID: "ts-2", Type: model.ItemTypeTask, Status: model.StatusOpen, ParentID: &sub},
+		{ID: "ts-3", Type: model.ItemTypeTask, Status: model.StatusOpen},
+	}
+
+	roots := reportEpicTrees(items)
+	if len(roots) != 1 || roots[0].Item.ID != "ep-1" {
+		t.Fatalf("expected ep-1 as the only root, got %+v", roots)
+	}
+	if roots[0].Total != 3 || roots[0].Done != 1 || roots[0].Percent() != 33 {
+		t.Errorf("ep-1 progress = %d/%d (%d%%), want 1/3", roots[0].Done, roots[0].Total, roots[0].Percent())
+	}
+}
+
+func TestMermaidDepGraph(t *testing.T) {
+	if g := mermaidDepGraph(nil); g != "" {
+		t.Errorf("expected empty graph, got %q", g)
+	}
+
+	edges := []db.DepEdge{
+		{ItemID: "ts-b", ItemTitle: `Say "hi"`, ItemStatus: "open", DependsOnID: "ts-a", DependsOnTitle: "First", DependsOnStatus: "in_progress"},
+		{ItemID: "ts-c", ItemTitle: "Finished", ItemStatus: "done", DependsOnID: "ts-a", DependsOnTitle: "First", DependsOnStatus: "in_progress"},
+	}
+	g := mermaidDepGraph(edges)
+	for _, want := range []string{
+		"flowchart LR",
+		`ts_a["ts-a: First"]:::in_progress`,
+		`ts_b["ts-b: Say #quot;hi#quot;"]:::open`,
+		"ts_a --> ts_b",
+	} {
+		if !strings.Contains(g, want) {
+			t.Errorf("graph missing %q:\n%s", want, g)
+		}
+	}
+	if strings.Contains(g, "ts_c") {
+		t.Errorf("done items should be left out of the graph:\n%s", g)
+	}
+}
+
+func TestRenderHTMLReport(t *testing.T) {
+	database := setupTestDB(t)
+	epic := createTestItem(t, database, "ep-rep1", "Report <Epic>", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ts-rep1", "Child", withParent(epic.ID))
+
+	report, err := buildHTMLReport(database, "", 10)
+	if err != nil {
+		t.Fatalf("buildHTMLReport failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := renderHTMLReport(&buf, report); err != nil {
+		t.Fatalf("renderHTMLReport failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<!DOCTYPE html>", "Report &lt;Epic&gt;", "ts-rep1", "0/1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagResultsEpic         string
+	flagResultsSearchFormat string
+)
+
+var resultsCmd = &cobra.Command{
+	Use:   "results",
+	Short: "List and search the results recorded when tasks were done",
+	Long: `Show the results messages written with 'tpg done'. They record what was
+built and where, which makes them the quickest way to find out how earlier
+work turned out.
+
+With --epic, list the results of the epic's done tasks, most recently
+closed first. Use 'tpg results search' to search results across the
+project.
+
+Examples:
+  tpg results --epic ep-abc123
+  tpg results search "rate limit"
+  tpg results search 'token AND refresh'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagResultsEpic == "" {
+			return fmt.Errorf("--epic is required (or search with 'tpg results search <query>')")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		epicID, err := resolveItemArg(database, flagResultsEpic)
+		if err != nil {
+			return err
+		}
+		epic, err := database.GetItem(epicID)
+		if err != nil {
+			return err
+		}
+		descendants, err := database.GetDescendants(epicID)
+		if err != nil {
+			return err
+		}
+
+		done := packResults(descendants, -1)
+		if len(done) == 0 {
+			fmt.Printf("No results recorded in %s yet\n", epicID)
+			return nil
+		}
+		fmt.Printf("Results in %s %s (%d done)\n\n", epic.ID, epic.Title, len(done))
+		printItemResults(done)
+		return nil
+	},
+}
+
+var resultsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over completion results",
+	Long: `Search the results of tasks in the project, best matches first.
+
+The query uses SQLite FTS5 syntax: words match anywhere in the results,
+"quoted phrases" match exactly, and AND, OR, NOT and prefix* work as usual.
+
+Examples:
+  tpg results search migration
+  tpg results search '"rate limit" OR throttl*'
+  tpg results search cache --format jsonl | jq -r .results`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonl, err := checkStreamFormat(flagResultsSearchFormat)
+		if err != nil {
+			return err
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		query := strings.Join(args, " ")
+		items, err := database.SearchResults(project, query)
+		if err != nil {
+			return fmt.Errorf("%w (check the FTS5 query syntax; quote phrases with \")", err)
+		}
+		if jsonl {
+			out := newJSONLStream(os.Stdout)
+			cache := &templateCache{}
+			for _, item := range items {
+				if err := writeItemJSONL(out, database, cache, item); err != nil {
+					_ = out.close()
+					return err
+				}
+			}
+			return out.close()
+		}
+		if len(items) == 0 {
+			fmt.Println("No matching results")
+			return nil
+		}
+		printItemResults(items)
+		return nil
+	},
+}
+
+// printItemResults prints each item's header line followed by its results,
+// indented.
+func printItemResults(items []model.Item) {
+	for i, item := range items {
+		if i > 0 {
+			fmt.Println()
+		}
+		when := ""
+		if item.ClosedAt != nil {
+			when = " " + formatTimeAgo(*item.ClosedAt)
+		}
+		fmt.Printf("%s %s [%s%s]\n", item.ID, item.Title, item.Status, when)
+		for _, line := range strings.Split(strings.TrimRight(item.Results, "\n"), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+}
+
+func init() {
+	resultsCmd.Flags().StringVar(&flagResultsEpic, "epic", "", "List the results of the epic's done tasks")
+	resultsSearchCmd.Flags().StringVar(&flagResultsSearchFormat, "format", "", "Output format: text, or jsonl for one JSON object per line")
+	resultsCmd.AddCommand(resultsSearchCmd)
+	rootCmd.AddCommand(resultsCmd)
+}
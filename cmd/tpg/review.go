@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var flagReviewRequestChanges string
+
+var reviewCmd = &cobra.Command{
+	Use:   "review <id>",
+	Short: "Review a task pending review, or send it back with changes requested",
+	Long: `Show a task that 'tpg done' submitted for review: who submitted it, when,
+and the results it was submitted with.
+
+With --request-changes, send it back instead: the task returns to open with
+your notes appended to its description under a "Review feedback" heading, so
+the next agent to start it sees what to fix. Use "-" to read the notes from
+stdin. 'tpg approve' completes a task that passes review.
+
+The reviewer ($AGENT_ID, or your login name) is recorded in history for both
+approvals and requested changes. 'tpg review queue' lists what is waiting.
+
+Examples:
+  tpg review queue
+  tpg review ts-a1b2c3
+  tpg review ts-a1b2c3 --request-changes "Migration drops the index; keep it"
+  tpg review ts-a1b2c3 --request-changes - < feedback.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("request-changes") {
+			return showReview(database, id)
+		}
+
+		notes := flagReviewRequestChanges
+		if notes == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read from stdin: %w", err)
+			}
+			notes = string(data)
+		}
+		notes = strings.TrimSpace(notes)
+		if notes == "" {
+			return fmt.Errorf("--request-changes needs notes saying what to change")
+		}
+
+		reviewer := db.CurrentReviewer()
+		if err := database.RequestChanges(id, reviewer, notes); err != nil {
+			return err
+		}
+		if err := database.AddLog(id, "Changes requested: "+notes); err != nil {
+			return err
+		}
+		fmt.Printf("Requested changes on %s; it is open again with your feedback in its description\n", id)
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// showReview prints what a reviewer needs to judge a task pending review.
+func showReview(database *db.DB, id string) error {
+	item, err := database.GetItem(id)
+	if err != nil {
+		return err
+	}
+	if item.Status != model.StatusPendingReview {
+		return fmt.Errorf("%s is not pending review (status: %s)", id, item.Status)
+	}
+	submitted, err := reviewRequestFor(database, item)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", item.ID, item.Title)
+	by := submitted.SubmittedBy
+	if by == "" {
+		by = "unknown"
+	}
+	fmt.Printf("Submitted: %s by %s\n", formatTimeAgo(submitted.SubmittedAt), by)
+	fmt.Println()
+	if item.Results != "" {
+		fmt.Printf("Results:\n%s\n", item.Results)
+	} else {
+		fmt.Println("Results: (none)")
+	}
+	fmt.Println()
+	fmt.Printf("Approve:         tpg approve %s\n", item.ID)
+	fmt.Printf("Request changes: tpg review %s --request-changes \"...\"\n", item.ID)
+	return nil
+}
+
+// reviewRequestFor finds an item's entry in its project's review queue.
+func reviewRequestFor(database *db.DB, item *model.Item) (db.ReviewRequest, error) {
+	queue, err := database.ReviewQueue(item.Project)
+	if err != nil {
+		return db.ReviewRequest{}, err
+	}
+	for _, req := range queue {
+		if req.Item.ID == item.ID {
+			return req, nil
+		}
+	}
+	return db.ReviewRequest{Item: *item, SubmittedAt: item.UpdatedAt}, nil
+}
+
+var reviewQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "List tasks pending review, longest-waiting first",
+	Long: `List tasks pending review, longest-waiting first, with who submitted each
+and how long it has waited.
+
+Example:
+  tpg review queue
+  tpg review queue --project api`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+		queue, err := database.ReviewQueue(project)
+		if err != nil {
+			return err
+		}
+		if len(queue) == 0 {
+			fmt.Println("Nothing pending review")
+			return nil
+		}
+
+		fmt.Printf("Pending review (%d):\n\n", len(queue))
+		for _, req := range queue {
+			by := req.SubmittedBy
+			if by == "" {
+				by = "unknown"
+			}
+			fmt.Printf("%s %s (submitted by %s, waiting %s)\n",
+				req.Item.ID, req.Item.Title, by, formatDuration(time.Since(req.SubmittedAt)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	reviewCmd.Flags().StringVar(&flagReviewRequestChanges, "request-changes", "", "Reopen the task with these notes as review feedback (- for stdin)")
+	reviewCmd.AddCommand(reviewQueueCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
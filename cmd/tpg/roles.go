@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+// builtinRoles are the roles available without configuration, selected by
+// $AGENT_TYPE. A "roles" entry in the config with the same name replaces one.
+var builtinRoles = map[string]db.RoleConfig{
+	// Plans work: creates and reshapes items, but doesn't work on them.
+	"planner": {Allow: []string{
+		"read", "add", "edit", "amend", "append", "desc", "replace", "split",
+		"epic", "dep", "label", "unlabel", "labels", "field", "project",
+		"projects describe", "note", "log", "learn", "concepts", "template",
+		"item-alias",
+	}},
+	// Works on tasks: may claim, log, and finish them, but not delete or
+	// reshape them.
+	"executor": {
+		Allow: []string{
+			"read", "ready", "start", "log", "done", "block", "append", "touch",
+			"heartbeat", "current", "field set", "learn", "note add", "transcript add",
+		},
+		Deny: []string{"log rm", "log edit", "learn rm"},
+	},
+	// Reviews: reads everything, comments with 'tpg log', and approves
+	// finished work or requests changes to it.
+	"reviewer": {
+		Allow: []string{"read", "log", "approve", "review"},
+		Deny:  []string{"log rm", "log edit"},
+	},
+}
+
+// readOnlyCommands are the commands that never change the database, unless
+// given one of their writeFlags. The "read" role entry covers them.
+var readOnlyCommands = map[string]bool{
+	"agent list": true, "alias list": true, "backups": true, "brief": true,
+	"closed": true, "compact": true, "concepts": true, "context": true,
+	"context pack": true, "current": true, "desc diff": true, "desc history": true,
+	"diff": true, "epic list": true, "epic snapshots": true, "explain": true,
+	"export": true, "field get": true, "field list": true, "fsck": true,
+	"graph": true, "guide": true, "history": true, "impact": true, "inbox": true,
+	"item-alias list": true, "labels": true, "learn due": true, "lint": true, "list": true, "logs": true,
+	"logs search": true, "note list": true, "note show": true, "plan": true,
+	"prime": true, "projects": true, "ready": true, "remind": true,
+	"report html": true, "results": true,
+	"results search": true, "review": true, "review queue": true, "schema from-yaml": true, "selftest": true,
+	"sessions": true, "sessions diff": true, "show": true, "stale": true,
+	"standup": true, "status": true, "summary": true, "template list": true,
+	"template locations": true, "template show": true, "template usage": true,
+	"transcript list": true, "transcript show": true,
+	"types": true, "types list": true, "worktree status": true,
+	"help": true, "completion": true, "__complete": true,
+}
+
+// writeFlags are the flags that make a read-only command change the
+// database.
+var writeFlags = map[string][]string{
+	"ready":    {"claim"},
+	"concepts": {"summary", "rename"},
+	"history":  {"cleanup"},
+	"review":   {"request-changes"},
+}
+
+// checkRole refuses to run cmd when $AGENT_TYPE names a role that doesn't
+// allow it. Agent types without a role are unrestricted.
+func checkRole(cmd *cobra.Command) error {
+	roleName := os.Getenv("AGENT_TYPE")
+	if roleName == "" || cmd == rootCmd {
+		return nil
+	}
+	var configured map[string]db.RoleConfig
+	if config, err := db.LoadConfig(); err == nil {
+		configured = config.Roles
+	}
+	role, ok := configured[roleName]
+	if !ok {
+		if role, ok = builtinRoles[roleName]; !ok {
+			return nil
+		}
+	}
+
+	path := commandPath(cmd)
+	shown := path
+	readOnly := readOnlyCommands[path] || strings.HasPrefix(path, "completion ")
+	for _, name := range writeFlags[path] {
+		if cmd.Flags().Changed(name) {
+			readOnly = false
+			shown += " --" + name
+		}
+	}
+	if roleAllows(role, path, readOnly) {
+		return nil
+	}
+	cmd.SilenceUsage = true
+	allowed := "every command"
+	if len(role.Allow) > 0 {
+		allowed = strings.Join(role.Allow, ", ")
+	}
+	return fmt.Errorf("AGENT_TYPE=%s may not run 'tpg %s' (role allows: %s; see roles in .tpg/config.json)", roleName, shown, allowed)
+}
+
+// roleAllows reports whether role may run the command at path.
+func roleAllows(role db.RoleConfig, path string, readOnly bool) bool {
+	for _, pattern := range role.Deny {
+		if roleMatches(pattern, path, readOnly) {
+			return false
+		}
+	}
+	if len(role.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range role.Allow {
+		if roleMatches(pattern, path, readOnly) {
+			return true
+		}
+	}
+	return false
+}
+
+// roleMatches reports whether a role entry covers the command at path.
+func roleMatches(pattern, path string, readOnly bool) bool {
+	switch pattern {
+	case "*":
+		return true
+	case "read":
+		return readOnly
+	}
+	return path == pattern || strings.HasPrefix(path, pattern+" ")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		path     string
+		readOnly bool
+		want     bool
+	}{
+		{"reviewer", "show", true, true},
+		{"reviewer", "log", false, true},
+		{"reviewer", "log rm", false, false},
+		{"reviewer", "log edit", false, false},
+		{"executor", "log", false, true},
+		{"executor", "log rm", false, false},
+		{"executor", "log edit", false, false},
+		{"executor", "learn", false, true},
+		{"executor", "learn rm", false, false},
+		{"reviewer", "add", false, false},
+		{"reviewer", "approve", false, true},
+		{"executor", "approve", false, false},
+		{"reviewer", "ready", false, false}, // ready --claim
+		{"executor", "ready", false, true},
+		{"executor", "done", false, true},
+		{"executor", "delete", false, false},
+		{"executor", "field set", false, true},
+		{"executor", "field rm", false, false},
+		{"planner", "epic edit", false, true},
+		{"planner", "add", false, true},
+		{"planner", "start", false, false},
+		{"planner", "clean", false, false},
+	}
+	for _, tt := range tests {
+		if got := roleAllows(builtinRoles[tt.role], tt.path, tt.readOnly); got != tt.want {
+			t.Errorf("%s may run %q (read-only %v) = %v, want %v", tt.role, tt.path, tt.readOnly, got, tt.want)
+		}
+	}
+
+	denyOnly := db.RoleConfig{Deny: []string{"delete", "epic"}}
+	if !roleAllows(denyOnly, "add", false) || roleAllows(denyOnly, "epic add", false) || roleAllows(denyOnly, "delete", false) {
+		t.Error("a role with only deny entries should allow everything else")
+	}
+	if roleAllows(db.RoleConfig{Allow: []string{"ep"}}, "epic add", false) {
+		t.Error(`"ep" should not cover "epic add"`)
+	}
+}
+
+func TestReadOnlyCommandsExist(t *testing.T) {
+	for path := range readOnlyCommands {
+		if path == "help" || path == "completion" || path == "__complete" {
+			continue
+		}
+		target, rest, err := rootCmd.Find(strings.Fields(path))
+		if err != nil || len(rest) > 0 || commandPath(target) != path {
+			t.Errorf("readOnlyCommands lists %q, which is not a command", path)
+		}
+	}
+	for path := range writeFlags {
+		target, _, _ := rootCmd.Find(strings.Fields(path))
+		for _, name := range writeFlags[path] {
+			if target.Flags().Lookup(name) == nil {
+				t.Errorf("writeFlags lists --%s, which 'tpg %s' doesn't have", name, path)
+			}
+		}
+	}
+}
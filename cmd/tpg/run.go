@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagRunInWorktree bool
+	flagRunBumpAfter  int
+)
+
+// Fields maintained on a task by 'tpg run'.
+const (
+	lastFailureField   = "last_failure"   // exit status, time, and command of the latest failure
+	failureStreakField = "failure_streak" // consecutive failed runs; removed on success
+)
+
+// runStderrLines caps the stderr kept in a failure log entry.
+const runStderrLines = 20
+
+// exitStatusError makes tpg exit with a command's exit status without
+// printing an error of its own.
+type exitStatusError struct {
+	code int
+}
+
+func (e *exitStatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <id> -- <command> [args...]",
+	Short: "Run a command with task context in its environment",
+	Long: `Run a command with the task's context exported as environment variables,
+and log its exit status to the task.
+
+  TPG_TASK_ID        the task ID
+  TPG_TASK_TITLE     the task title
+  TPG_EPIC_ID        the nearest epic containing the task (or the epic itself)
+  TPG_WORKTREE_PATH  the worktree of the task's worktree epic, if it exists
+  TPG_BATCH          set to 1, so tpg commands the command runs skip their
+                     automatic backups; tpg backs up once when it exits
+
+With --worktree the command runs inside that worktree. tpg exits with the
+command's exit status, so 'tpg run' can stand in for the command in scripts.
+
+When the command fails, the log entry includes the tail of its stderr, and
+the task's "last_failure" and "failure_streak" fields are updated (see 'tpg
+show'). A successful run clears the streak. With --bump-after N, every N
+consecutive failures raise the task's priority by one.
+
+Examples:
+  tpg run ts-abc123 -- go test ./...
+  tpg run . --worktree -- make lint
+  tpg run . --bump-after 3 -- go test ./...
+  tpg run ts-abc123 -- sh -c 'echo "$TPG_TASK_TITLE"'`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() != 1 {
+			return fmt.Errorf("separate the command with --, e.g. tpg run %s -- make test", args[0])
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		item, err := database.GetItem(id)
+		if err != nil {
+			return err
+		}
+		epicID, worktreePath, err := taskRunContext(database, item)
+		if err != nil {
+			return err
+		}
+
+		dir := ""
+		if flagRunInWorktree {
+			if worktreePath == "" {
+				return fmt.Errorf("%s has no worktree to run in", id)
+			}
+			dir = worktreePath
+		}
+
+		cmd.SilenceUsage = true
+		env := append(os.Environ(), taskRunEnv(item, epicID, worktreePath)...)
+		code, err := runTaskCommand(database, item, args[1:], dir, env)
+		if err != nil {
+			return err
+		}
+		if err := recordRunOutcome(database, item, args[1:], code, time.Now(), flagRunBumpAfter); err != nil {
+			return err
+		}
+		database.BackupAfterBatch()
+		if code != 0 {
+			cmd.SilenceErrors = true
+			return &exitStatusError{code: code}
+		}
+		return nil
+	},
+}
+
+// taskRunContext finds the epic containing an item and, when the item sits
+// under a worktree epic whose worktree exists, the worktree path.
+func taskRunContext(database *db.DB, item *model.Item) (epicID, worktreePath string, err error) {
+	if item.Type.CanHaveChildren() {
+		epicID = item.ID
+	} else {
+		chain, err := database.GetParentChain(item.ID)
+		if err != nil {
+			return "", "", err
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			if chain[i].Type.CanHaveChildren() {
+				epicID = chain[i].ID
+				break
+			}
+		}
+	}
+
+	rootEpic, _, err := database.GetRootEpic(item.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if rootEpic != nil {
+		if _, worktrees := detectWorktreeState(); worktrees != nil {
+			worktreePath = worktrees[rootEpic.WorktreeBranch]
+		}
+	}
+	return epicID, worktreePath, nil
+}
+
+// taskRunEnv returns the environment variables describing a task.
+func taskRunEnv(item *model.Item, epicID, worktreePath string) []string {
+	return []string{
+		"TPG_TASK_ID=" + item.ID,
+		"TPG_TASK_TITLE=" + item.Title,
+		"TPG_EPIC_ID=" + epicID,
+		"TPG_WORKTREE_PATH=" + worktreePath,
+		db.BatchEnv + "=1",
+	}
+}
+
+// runTaskCommand runs argv with the given environment, attached to the
+// terminal, and logs the outcome to the task, including the tail of stderr
+// when it fails. It returns the exit status; err is set only when the
+// command could not be started.
+func runTaskCommand(database *db.DB, item *model.Item, argv []string, dir string, env []string) (int, error) {
+	var stderr bytes.Buffer
+	c := execCommand(argv[0], argv[1:]...)
+	c.Dir = dir
+	c.Env = env
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+	start := time.Now()
+	runErr := c.Run()
+	code := exitCode(runErr)
+	if code < 0 {
+		return code, fmt.Errorf("failed to run %s: %w", argv[0], runErr)
+	}
+
+	elapsed := time.Since(start).Round(100 * time.Millisecond)
+	msg := fmt.Sprintf("Ran: %s (exit %d, %s)", strings.Join(argv, " "), code, elapsed)
+	if tail := tailLines(stderr.String(), runStderrLines); code != 0 && tail != "" {
+		msg += "\nstderr:\n" + tail
+	}
+	_ = database.AddLog(item.ID, msg)
+	return code, nil
+}
+
+// recordRunOutcome updates the task's failure fields after a run. When
+// bumpAfter is positive, every bumpAfter consecutive failures raise the
+// task's priority by one level.
+func recordRunOutcome(database *db.DB, item *model.Item, argv []string, code int, now time.Time, bumpAfter int) error {
+	fields, err := database.GetFields(item.ID)
+	if err != nil {
+		return err
+	}
+	if code == 0 {
+		if _, ok := fields[failureStreakField]; ok {
+			return database.RemoveField(item.ID, failureStreakField)
+		}
+		return nil
+	}
+
+	streak, _ := strconv.Atoi(fields[failureStreakField])
+	streak++
+	failure := fmt.Sprintf("exit %d at %s: %s", code, now.Format("2006-01-02 15:04"), strings.Join(argv, " "))
+	if err := database.SetField(item.ID, lastFailureField, failure); err != nil {
+		return err
+	}
+	if err := database.SetField(item.ID, failureStreakField, strconv.Itoa(streak)); err != nil {
+		return err
+	}
+
+	if bumpAfter > 0 && streak%bumpAfter == 0 && item.Priority > 1 {
+		if err := database.UpdatePriority(item.ID, item.Priority-1); err != nil {
+			return err
+		}
+		_ = database.AddLog(item.ID, fmt.Sprintf("Priority raised to %d after %d consecutive failed runs", item.Priority-1, streak))
+		fmt.Fprintf(os.Stderr, "%s failed %d times in a row; priority raised to %d\n", item.ID, streak, item.Priority-1)
+		item.Priority--
+	}
+	return nil
+}
+
+func init() {
+	runCmd.Flags().BoolVarP(&flagRunInWorktree, "worktree", "w", false, "Run inside the task's epic worktree")
+	runCmd.Flags().IntVar(&flagRunBumpAfter, "bump-after", 0, "Raise priority after this many consecutive failures (0 to never)")
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestTaskRunContext(t *testing.T) {
+	database := setupTestDB(t)
+	createTestItem(t, database, "ep-outer", "Outer", withType(model.ItemTypeEpic))
+	createTestItem(t, database, "ep-inner", "Inner", withType(model.ItemTypeEpic), withParent("ep-outer"))
+	task := createTestItem(t, database, "ts-task", "Task", withParent("ep-inner"))
+	loose := createTestItem(t, database, "ts-loose", "Loose")
+
+	if epicID, _, err := taskRunContext(database, task); err != nil || epicID != "ep-inner" {
+		t.Errorf("expected nearest epic ep-inner, got %q (%v)", epicID, err)
+	}
+	if epicID, _, err := taskRunContext(database, loose); err != nil || epicID != "" {
+		t.Errorf("expected no epic, got %q (%v)", epicID, err)
+	}
+}
+
+func TestRunTaskCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	database := setupTestDB(t)
+	task := createTestItem(t, database, "ts-run", "Run tests")
+
+	out := filepath.Join(t.TempDir(), "env.txt")
+	env := append(os.Environ(), taskRunEnv(task, "ep-x", "")...)
+	argv := []string{"sh", "-c", `printf '%s|%s|%s' "$TPG_TASK_ID" "$TPG_TASK_TITLE" "$TPG_EPIC_ID" > "$0"; echo boom >&2; exit 3`, out}
+	code, err := runTaskCommand(database, task, argv, "", env)
+	if err != nil {
+		t.Fatalf("runTaskCommand: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("expected exit 3, got %d", code)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if got := string(data); got != "ts-run|Run tests|ep-x" {
+		t.Errorf("unexpected environment %q", got)
+	}
+
+	logs, err := database.GetLogs(task.ID)
+	if err != nil {
+		t.Fatalf("GetLogs: %v", err)
+	}
+	if len(logs) != 1 || !strings.Contains(logs[0].Message, "(exit 3,") || !strings.Contains(logs[0].Message, "stderr:\nboom") {
+		t.Errorf("expected an exit status log with stderr, got %v", logs)
+	}
+
+	if _, err := runTaskCommand(database, task, []string{"tpg-no-such-command"}, "", env); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+}
+
+func TestRecordRunOutcome(t *testing.T) {
+	database := setupTestDB(t)
+	task := createTestItem(t, database, "ts-flaky", "Flaky")
+	if err := database.UpdatePriority(task.ID, 3); err != nil {
+		t.Fatalf("UpdatePriority: %v", err)
+	}
+	task.Priority = 3
+	argv := []string{"go", "test", "./..."}
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		if err := recordRunOutcome(database, task, argv, 1, now, 2); err != nil {
+			t.Fatalf("recordRunOutcome: %v", err)
+		}
+	}
+	fields, err := database.GetFields(task.ID)
+	if err != nil {
+		t.Fatalf("GetFields: %v", err)
+	}
+	if fields[failureStreakField] != "2" {
+		t.Errorf("expected streak 2, got %q", fields[failureStreakField])
+	}
+	if want := "exit 1 at 2026-03-01 12:30: go test ./..."; fields[lastFailureField] != want {
+		t.Errorf("expected last failure %q, got %q", want, fields[lastFailureField])
+	}
+	got, err := database.GetItem(task.ID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if got.Priority != 2 {
+		t.Errorf("expected priority raised to 2, got %d", got.Priority)
+	}
+
+	if err := recordRunOutcome(database, task, argv, 0, now, 2); err != nil {
+		t.Fatalf("recordRunOutcome: %v", err)
+	}
+	fields, err = database.GetFields(task.ID)
+	if err != nil {
+		t.Fatalf("GetFields: %v", err)
+	}
+	if _, ok := fields[failureStreakField]; ok {
+		t.Error("expected a successful run to clear the streak")
+	}
+	if fields[lastFailureField] == "" {
+		t.Error("expected the last failure to be kept")
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print JSON Schemas for tpg's inputs",
+	Long:  `Print JSON Schemas that tools can use to validate input before passing it to tpg.`,
+}
+
+var schemaFromYAMLCmd = &cobra.Command{
+	Use:   "from-yaml [command...]",
+	Short: "Print the JSON Schema of the YAML a command accepts with --from-yaml",
+	Long: `Print a JSON Schema (draft 2020-12) describing the YAML document a command
+reads from stdin with --from-yaml, so agent frameworks can validate the YAML
+they generate before piping it in instead of finding out from a flag error.
+
+Each key is a flag name with underscores for hyphens, typed as the flag is:
+strings, integers, booleans, priorities (1-5 or a name such as high), and
+repeatable flags as a string or a list of strings. Unknown keys are rejected,
+as --from-yaml rejects them.
+
+Without a command, prints an object mapping every command ("add",
+"epic add", ...) to its schema.
+
+Examples:
+  tpg schema from-yaml add
+  tpg schema from-yaml epic edit
+  tpg schema from-yaml > tpg-yaml-schemas.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			all := make(map[string]*jsonSchema)
+			visitCommands(rootCmd, func(c *cobra.Command) {
+				if c.Runnable() && !c.Hidden && c != cmd && !strings.HasPrefix(commandPath(c), "completion") && c.Name() != "help" {
+					all[commandPath(c)] = fromYAMLSchema(c)
+				}
+			})
+			return printIndentedJSON(all)
+		}
+
+		target, rest, err := rootCmd.Find(args)
+		if err != nil || len(rest) > 0 || target == rootCmd {
+			return fmt.Errorf("unknown command %q (see 'tpg --help')", strings.Join(args, " "))
+		}
+		if !target.Runnable() {
+			return fmt.Errorf("'tpg %s' is a group of commands; name one of its subcommands", commandPath(target))
+		}
+		return printIndentedJSON(fromYAMLSchema(target))
+	},
+}
+
+// jsonSchema is the subset of JSON Schema used to describe --from-yaml input.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Maximum              *int                   `json:"maximum,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+}
+
+// yamlFlagTypes are the flag types --from-yaml can set (see setFlagFromYAML).
+var yamlFlagTypes = map[string]bool{"string": true, "int": true, "priority": true, "bool": true, "stringArray": true}
+
+// fromYAMLSchema describes the YAML that --from-yaml accepts for cmd: one key
+// per flag of a supported type, the command's own and inherited ones.
+func fromYAMLSchema(cmd *cobra.Command) *jsonSchema {
+	closed := false
+	schema := &jsonSchema{
+		Schema:               "https://json-schema.org/draft/2020-12/schema",
+		Title:                "tpg " + commandPath(cmd) + " --from-yaml",
+		Description:          cmd.Short,
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: &closed,
+	}
+	add := func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" || f.Name == "from-yaml" || !yamlFlagTypes[f.Value.Type()] {
+			return
+		}
+		key := strings.ReplaceAll(f.Name, "-", "_")
+		if _, ok := schema.Properties[key]; !ok {
+			schema.Properties[key] = flagSchema(f)
+		}
+	}
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	return schema
+}
+
+// flagSchema describes the YAML value of one flag.
+func flagSchema(f *pflag.Flag) *jsonSchema {
+	s := &jsonSchema{Description: f.Usage}
+	switch f.Value.Type() {
+	case "string":
+		s.Type = "string"
+		if f.DefValue != "" {
+			s.Default = f.DefValue
+		}
+	case "int":
+		s.Type = "integer"
+		if n, err := strconv.Atoi(f.DefValue); err == nil && n != 0 {
+			s.Default = n
+		}
+	case "priority":
+		lo, hi := model.MinPriority, model.MaxPriority
+		s.AnyOf = []*jsonSchema{
+			{Type: "integer", Minimum: &lo, Maximum: &hi},
+			{Type: "string", Enum: model.PriorityNames()},
+		}
+	case "bool":
+		s.Type = "boolean"
+		if f.DefValue == "true" {
+			s.Default = true
+		}
+	case "stringArray":
+		s.AnyOf = []*jsonSchema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonSchema{Type: "string"}},
+		}
+	}
+	return s
+}
+
+// commandPath returns a command's path without the leading "tpg".
+func commandPath(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+}
+
+// visitCommands calls fn for every command under root, in name order.
+func visitCommands(root *cobra.Command, fn func(*cobra.Command)) {
+	children := append([]*cobra.Command(nil), root.Commands()...)
+	sort.Slice(children, func(i, j int) bool { return children[i].Name() < children[j].Name() })
+	for _, c := range children {
+		fn(c)
+		visitCommands(c, fn)
+	}
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaFromYAMLCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFromYAMLSchema(t *testing.T) {
+	schema := fromYAMLSchema(addCmd)
+	if schema.Title != "tpg add --from-yaml" || schema.AdditionalProperties == nil || *schema.AdditionalProperties {
+		t.Errorf("schema = %+v, want a closed object titled for add", schema)
+	}
+	if p := schema.Properties["desc"]; p == nil || p.Type != "string" {
+		t.Errorf("desc = %+v, want a string", p)
+	}
+	if p := schema.Properties["priority"]; p == nil || len(p.AnyOf) != 2 || p.AnyOf[0].Type != "integer" {
+		t.Errorf("priority = %+v, want integer or name", p)
+	}
+	if p := schema.Properties["label"]; p == nil || len(p.AnyOf) != 2 || p.AnyOf[1].Type != "array" {
+		t.Errorf("label = %+v, want string or list of strings", p)
+	}
+	for _, key := range []string{"from_yaml", "help"} {
+		if _, ok := schema.Properties[key]; ok {
+			t.Errorf("schema has %q, which --from-yaml can't set", key)
+		}
+	}
+
+	// Every key must be one applyYAMLFlagsFromData accepts.
+	for key := range schema.Properties {
+		if addCmd.Flags().Lookup(strings.ReplaceAll(key, "_", "-")) == nil && addCmd.InheritedFlags().Lookup(strings.ReplaceAll(key, "_", "-")) == nil {
+			t.Errorf("schema key %q is not a flag of add", key)
+		}
+	}
+
+	// split's --spec YAML is separate; --from-yaml sets its flags like any other.
+	split := fromYAMLSchema(splitCmd)
+	if split.Properties["into"] == nil || split.Properties["spec"] == nil || split.Properties["tasks"] != nil {
+		out, _ := json.Marshal(split)
+		t.Errorf("split schema = %s, want its flags", out)
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var flagSelftestRun string
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run every documented help example against a scratch database",
+	Long: `Run the examples shown in each command's help against a throwaway
+project, to check that this install works and that the examples still match
+the commands' flags.
+
+Each example runs in its own temporary project, so examples can't affect
+each other and your own tasks are never touched. Placeholder IDs used in
+examples (ts-a1b2c3, ep-a1b2c3) exist there as open tasks and epics.
+Examples that need a shell (heredocs, pipes), a terminal, or outside
+resources are skipped with the reason.
+
+An example fails when tpg rejects its flags or arguments, which means the
+help text is out of date. Examples that run but stop on missing data (an
+unknown label, a learning ID) are counted separately; -v lists them.
+Exits non-zero when any example fails.
+
+Examples:
+  tpg selftest
+  tpg selftest --run "tpg dep"   # only examples containing this text
+  tpg selftest -v                # show every example, not just failures`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate the tpg binary: %w", err)
+		}
+		var examples []helpExample
+		for _, ex := range collectExamples(rootCmd) {
+			if flagSelftestRun == "" || strings.Contains(ex.Line, flagSelftestRun) {
+				examples = append(examples, ex)
+			}
+		}
+
+		failed, skipped, needData := 0, 0, 0
+		for _, ex := range examples {
+			if ex.Skip != "" {
+				skipped++
+				if flagVerbose {
+					fmt.Printf("SKIP %s  (%s)\n", ex.Line, ex.Skip)
+				}
+				continue
+			}
+			out, err := runExample(exe, ex)
+			switch {
+			case err == nil:
+				if flagVerbose {
+					fmt.Printf("ok   %s\n", ex.Line)
+				}
+			case isUsageError(out):
+				failed++
+				fmt.Printf("FAIL %s  (in 'tpg %s --help')\n", ex.Line, ex.Command)
+				fmt.Printf("     %s\n", firstLine(out))
+			default:
+				needData++
+				if flagVerbose {
+					fmt.Printf("DATA %s  (%s)\n", ex.Line, firstLine(out))
+				}
+			}
+		}
+
+		fmt.Printf("\n%d examples: %d passed, %d failed, %d needed existing data, %d skipped\n",
+			len(examples), len(examples)-failed-skipped-needData, failed, needData, skipped)
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return &exitStatusError{code: 1}
+		}
+		return nil
+	},
+}
+
+// helpExample is one example command line from a command's help text.
+type helpExample struct {
+	Command string   // command path without "tpg", e.g. "dep add"
+	Line    string   // the example as documented, without its comment
+	Args    []string // the arguments after "tpg"
+	Skip    string   // why the example can't run unattended, if it can't
+}
+
+// usageErrors are the messages cobra gives when arguments don't match a
+// command's flags or argument count: the signs of an out-of-date example.
+var usageErrors = []string{
+	"unknown flag", "unknown shorthand flag", "unknown command",
+	"flag needs an argument", "invalid argument", "arg(s), received", "arg(s), only received",
+}
+
+// isUsageError reports whether an example's output shows it was rejected
+// for its flags or arguments rather than for the data it ran against.
+func isUsageError(out string) bool {
+	for _, msg := range usageErrors {
+		if strings.Contains(out, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLine returns the first non-empty line of out.
+func firstLine(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// exampleTimeout bounds each example, so one that waits on input can't hang
+// the run.
+const exampleTimeout = 20 * time.Second
+
+// exampleSkips lists examples that can't run unattended in a scratch
+// project, by the leading text of the example, with the reason.
+var exampleSkips = map[string]string{
+	"tpg tui":               "needs a terminal",
+	"tpg guide":             "interactive",
+	"tpg edit":              "opens an editor",
+	"tpg prime --customize": "opens an editor",
+	"tpg run":               "runs outside commands",
+	"tpg restore":           "needs a backup file",
+	"tpg import":            "needs an input file",
+	"tpg git-hook":          "changes the git repository",
+	"tpg worktree":          "needs a git repository",
+	"tpg epic worktree":     "needs a git repository",
+	"tpg epic setup":        "runs outside commands",
+	"tpg epic finish":       "needs a git worktree",
+	"tpg merge-db":          "needs another database",
+	"tpg selftest":          "runs the examples",
+	"tpg rd":                "uses an alias set up by the example before it",
+}
+
+// examplePlaceholder matches the item IDs examples use as placeholders.
+var examplePlaceholder = regexp.MustCompile(`^(ts|ep)-[a-z0-9]+$`)
+
+// collectExamples gathers the examples from the help text of cmd and its
+// subcommands: the "tpg ..." lines under an "Example:" or "Examples:"
+// heading, in command order.
+func collectExamples(cmd *cobra.Command) []helpExample {
+	var examples []helpExample
+	path := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	text := cmd.Long + "\n" + cmd.Example
+	inExamples := false
+	for _, raw := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		switch {
+		case trimmed == "Example:" || trimmed == "Examples:":
+			inExamples = true
+			continue
+		case trimmed == "":
+			continue
+		case !strings.HasPrefix(trimmed, "tpg "):
+			// Prose after the examples ends them; continuation lines of a
+			// heredoc are part of the example before them.
+			if raw == trimmed {
+				inExamples = false
+			}
+			continue
+		}
+		if !inExamples && cmd.Example == "" {
+			continue
+		}
+		examples = append(examples, parseExample(path, trimmed))
+	}
+	for _, sub := range cmd.Commands() {
+		if sub.Hidden || sub.Name() == "help" || sub.Name() == "completion" {
+			continue
+		}
+		examples = append(examples, collectExamples(sub)...)
+	}
+	return examples
+}
+
+// parseExample splits an example line into its arguments and decides
+// whether it can run unattended.
+func parseExample(command, line string) helpExample {
+	line = stripExampleComment(line)
+	ex := helpExample{Command: command, Line: line}
+	keys := make([]string, 0, len(exampleSkips))
+	for k := range exampleSkips {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, prefix := range keys {
+		if line == prefix || strings.HasPrefix(line, prefix+" ") {
+			ex.Skip = exampleSkips[prefix]
+			return ex
+		}
+	}
+	switch {
+	case strings.Contains(line, "<<") || strings.Contains(line, " | ") || strings.Contains(line, "$(") ||
+		strings.Contains(line, " > ") || strings.Contains(line, " < ") || strings.Contains(line, "||") ||
+		strings.Contains(line, "&&") || strings.Contains(line, " ~/") || strings.HasSuffix(line, "\\"):
+		ex.Skip = "needs a shell"
+		return ex
+	case strings.ContainsAny(line, "<>[]") || strings.Contains(line, "..."):
+		ex.Skip = "has placeholders"
+		return ex
+	}
+	words, err := splitAliasArgs(strings.TrimPrefix(line, "tpg "))
+	if err != nil {
+		ex.Skip = "can't be parsed"
+		return ex
+	}
+	ex.Args = words
+	return ex
+}
+
+// stripExampleComment drops a trailing "# comment" outside quotes.
+func stripExampleComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && i > 0 && (line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// runExample runs ex with the tpg binary at exe in a new scratch project
+// where its placeholder IDs exist.
+func runExample(exe string, ex helpExample) (string, error) {
+	sandbox, err := os.MkdirTemp("", "tpg-selftest-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(sandbox) }()
+	dbPath := filepath.Join(sandbox, db.DataDir, db.DBFile)
+	env := append(os.Environ(), "TPG_DB="+dbPath, "TPG_EDITOR=true", "NO_COLOR=1", "AGENT_ID=", "TPG_SESSION=selftest")
+	run := func(args ...string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), exampleTimeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, exe, args...)
+		c.Dir = sandbox
+		c.Env = env
+		var out bytes.Buffer
+		c.Stdout = &out
+		c.Stderr = &out
+		err := c.Run()
+		if ctx.Err() != nil {
+			return out.String(), fmt.Errorf("timed out after %s", exampleTimeout)
+		}
+		return out.String(), err
+	}
+
+	if out, err := run("init"); err != nil {
+		return out, fmt.Errorf("failed to create the scratch project: %w", err)
+	}
+	if err := addExamplePlaceholders(sandbox, dbPath, ex.Args); err != nil {
+		return "", fmt.Errorf("failed to set up the scratch project: %w", err)
+	}
+	return run(ex.Args...)
+}
+
+// addExamplePlaceholders creates an item for each placeholder ID in args:
+// an epic for "ep-" IDs, otherwise a task.
+func addExamplePlaceholders(dir, dbPath string, args []string) error {
+	var config db.Config
+	data, err := os.ReadFile(filepath.Join(dir, db.DataDir, db.ConfigFile))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = database.Close() }()
+
+	now := time.Now()
+	for _, arg := range args {
+		if !examplePlaceholder.MatchString(arg) {
+			continue
+		}
+		if _, err := database.GetItem(arg); err == nil {
+			continue
+		}
+		item := &model.Item{
+			ID:        arg,
+			Project:   config.DefaultProject,
+			Type:      model.ItemTypeTask,
+			Title:     "Example " + arg,
+			Status:    model.StatusOpen,
+			Priority:  2,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if strings.HasPrefix(arg, "ep-") {
+			item.Type = model.ItemTypeEpic
+		}
+		if err := database.CreateItem(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	selftestCmd.Flags().StringVar(&flagSelftestRun, "run", "", "Only run examples containing this text")
+	rootCmd.AddCommand(selftestCmd)
+}
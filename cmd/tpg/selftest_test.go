@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseExample(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantArgs []string
+		wantSkip bool
+	}{
+		{`tpg ready --project myproject   # filter by project`, []string{"ready", "--project", "myproject"}, false},
+		{`tpg add "Fix #12 crash" -p 1`, []string{"add", "Fix #12 crash", "-p", "1"}, false},
+		{`tpg dep ts-a1b2c3 blocks ts-d4e5f6`, []string{"dep", "ts-a1b2c3", "blocks", "ts-d4e5f6"}, false},
+		{`tpg add "Task" --desc - <<EOF`, nil, true},
+		{`tpg list --ids-only | xargs tpg show`, nil, true},
+		{`tpg fsck || tpg backups`, nil, true},
+		{`tpg show <id>`, nil, true},
+		{`tpg tui`, nil, true},
+		{`tpg edit ts-a1b2c3 --title "New"`, nil, true},
+	}
+	for _, tt := range tests {
+		ex := parseExample("test", tt.line)
+		if (ex.Skip != "") != tt.wantSkip {
+			t.Errorf("parseExample(%q) skip = %q, want skipped=%v", tt.line, ex.Skip, tt.wantSkip)
+			continue
+		}
+		if !tt.wantSkip && !reflect.DeepEqual(ex.Args, tt.wantArgs) {
+			t.Errorf("parseExample(%q) args = %q, want %q", tt.line, ex.Args, tt.wantArgs)
+		}
+	}
+}
+
+func TestCollectExamples(t *testing.T) {
+	root := &cobra.Command{Use: "tpg"}
+	sub := &cobra.Command{
+		Use: "thing",
+		Long: `Do a thing.
+
+Run tpg thing to see it in prose, which is not an example.
+
+Examples:
+  tpg thing
+  tpg thing --all   # everything
+
+See also tpg other.`,
+		Run: func(*cobra.Command, []string) {},
+	}
+	hidden := &cobra.Command{Use: "secret", Hidden: true, Example: "  tpg secret", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(sub, hidden)
+
+	got := collectExamples(root)
+	if len(got) != 2 {
+		t.Fatalf("got %d examples, want 2: %+v", len(got), got)
+	}
+	if got[0].Command != "thing" || got[0].Line != "tpg thing" {
+		t.Errorf("first example = %+v", got[0])
+	}
+	if got[1].Line != "tpg thing --all" || !reflect.DeepEqual(got[1].Args, []string{"thing", "--all"}) {
+		t.Errorf("second example = %+v", got[1])
+	}
+}
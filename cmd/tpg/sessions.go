@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
+)
+
+var (
+	flagSessionsSince string
+	flagSessionsLimit int
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List sessions that changed items",
+	Long: `List sessions, most recently active first, with how many changes each made.
+
+Every history event records the session of the command that made it:
+agent:<id> when $AGENT_ID is set, session:<name> for $TPG_SESSION, and
+otherwise shell:<pid> for the terminal. Set TPG_SESSION per run to tell
+separate runs of the same agent apart.
+
+Examples:
+  tpg sessions
+  tpg sessions --since 24h
+  tpg sessions diff agent:worker-1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		since, err := parseSince("since", flagSessionsSince)
+		if err != nil {
+			return err
+		}
+		sessions, err := database.ListSessions(since, flagSessionsLimit)
+		if err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			fmt.Println("No sessions found")
+			return nil
+		}
+		names := database.AgentNames()
+		fmt.Printf("%-28s %-15s %-18s %7s %6s\n", "SESSION", "ACTOR", "LAST ACTIVE", "EVENTS", "ITEMS")
+		for _, s := range sessions {
+			fmt.Printf("%-28s %-15s %-18s %7d %6d\n", s.ID, truncateActor(agentDisplayName(names, s.ActorID)),
+				s.Last.Local().Format("2006-01-02 15:04"), s.Events, s.Items)
+		}
+		return nil
+	},
+}
+
+var sessionsDiffCmd = &cobra.Command{
+	Use:   "diff <session-id>",
+	Short: "Show every change a session made, grouped by item",
+	Long: `Show every change recorded in a session, like a code review: items the
+session created, status transitions, and field edits, grouped by item in the
+order the session first touched them.
+
+Lines start with + for additions (created items, added deps), - for
+removals and cancellations, and ~ for changes.
+
+Examples:
+  tpg sessions diff agent:worker-1
+  tpg sessions diff session:nightly-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		entries, err := database.SessionHistory(args[0])
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no history recorded for session %s (see 'tpg sessions')", args[0])
+		}
+		printSessionDiff(database, args[0], entries)
+		return nil
+	},
+}
+
+// printSessionDiff prints a session's history grouped by item.
+func printSessionDiff(database *db.DB, session string, entries []db.HistoryEntry) {
+	var order []string
+	byItem := map[string][]db.HistoryEntry{}
+	actors := map[string]bool{}
+	for _, e := range entries {
+		if _, ok := byItem[e.ItemID]; !ok {
+			order = append(order, e.ItemID)
+		}
+		byItem[e.ItemID] = append(byItem[e.ItemID], e)
+		if e.ActorID != "" {
+			actors[e.ActorID] = true
+		}
+	}
+
+	fmt.Printf("Session %s", session)
+	if len(actors) > 0 {
+		names := database.AgentNames()
+		var list []string
+		for id := range actors {
+			list = append(list, agentDisplayName(names, id))
+		}
+		sort.Strings(list)
+		fmt.Printf(" (%s)", strings.Join(list, ", "))
+	}
+	first, last := entries[0].CreatedAt.Local(), entries[len(entries)-1].CreatedAt.Local()
+	fmt.Printf("\n%s → %s, %d changes to %d items\n",
+		first.Format("2006-01-02 15:04"), last.Format("2006-01-02 15:04"), len(entries), len(order))
+
+	for _, id := range order {
+		title := format.Dim("(deleted)")
+		if item, err := database.GetItem(id); err == nil {
+			title = item.Title
+		}
+		fmt.Printf("\n%s  %s\n", format.ID(id), title)
+		for _, e := range byItem[id] {
+			fmt.Printf("  %s %s  %s\n", sessionDiffMarker(e.EventType), describeSessionChange(e),
+				format.Dim(e.CreatedAt.Local().Format("15:04")))
+		}
+	}
+}
+
+// sessionDiffMarker returns the review-style marker for an event type.
+func sessionDiffMarker(eventType string) string {
+	switch eventType {
+	case db.EventTypeCreated, db.EventTypeDependencyAdded:
+		return "+"
+	case db.EventTypeCanceled, db.EventTypeDependencyRemoved, db.EventTypeLogRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// describeSessionChange describes one event in full, unlike the truncated
+// CHANGES column of 'tpg history'.
+func describeSessionChange(e db.HistoryEntry) string {
+	label := strings.ReplaceAll(e.EventType, "_", " ")
+	if key, ok := e.Changes["key"]; ok && e.EventType == db.EventTypeFieldChanged {
+		label = fmt.Sprintf("field %v", key)
+	}
+	oldVal, hasOld := e.Changes["old"]
+	newVal, hasNew := e.Changes["new"]
+	if hasOld && hasNew {
+		return fmt.Sprintf("%s: %v → %v", label, oldVal, newVal)
+	}
+	if val, ok := e.Changes["value"]; ok {
+		return fmt.Sprintf("%s: %q", label, fmt.Sprint(val))
+	}
+	var parts []string
+	for k, v := range e.Changes {
+		if k == "key" && e.EventType == db.EventTypeFieldChanged {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(parts)
+	if len(parts) == 0 {
+		return label
+	}
+	return label + ": " + strings.Join(parts, ", ")
+}
+
+func init() {
+	sessionsCmd.Flags().StringVar(&flagSessionsSince, "since", "", "Only sessions active since a duration (24h, 7d), date, or 'today'")
+	sessionsCmd.Flags().IntVar(&flagSessionsLimit, "limit", 20, "Maximum sessions to list (0 for all)")
+	sessionsCmd.AddCommand(sessionsDiffCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestSessionsDiff(t *testing.T) {
+	database := setupCommandDB(t)
+	t.Setenv("AGENT_ID", "")
+	t.Setenv("TPG_SESSION", "review-me")
+	createTestItem(t, database, "ts-one", "Write parser")
+	if err := database.UpdateStatus("ts-one", model.StatusInProgress, db.AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	var runErr error
+	out := captureOutput(func() {
+		runErr = sessionsDiffCmd.RunE(sessionsDiffCmd, []string{"session:review-me"})
+	})
+	if runErr != nil {
+		t.Fatalf("sessions diff: %v", runErr)
+	}
+	for _, want := range []string{"Session session:review-me", "ts-one  Write parser", "+ created", "~ status changed: open → in_progress"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := sessionsDiffCmd.RunE(sessionsDiffCmd, []string{"session:nobody"}); err == nil {
+		t.Error("expected an error for a session without history")
+	}
+}
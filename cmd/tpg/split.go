@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	flagSplitInto  []string
+	flagSplitTitle string
+	flagSplitSpec  string
+)
+
+// splitSpec is the --spec input for 'tpg split'.
+type splitSpec struct {
+	Title string          `yaml:"title"`
+	Tasks []splitTaskSpec `yaml:"tasks"`
+}
+
+type splitTaskSpec struct {
+	Title    string `yaml:"title"`
+	Desc     string `yaml:"desc"`
+	Priority int    `yaml:"priority"`
+}
+
+var splitCmd = &cobra.Command{
+	Use:   "split <id>",
+	Short: "Convert a task into an epic with child tasks",
+	Long: `Convert a task that turned out to be bigger than expected into an epic
+with child tasks.
+
+The new epic takes the task's place and inherits its:
+  - Title, description, and priority (use --title to rename)
+  - Parent
+  - Dependencies (both blocking and blocked-by)
+  - Labels and logs
+  - Status and claiming agent (a task in progress stays in progress)
+
+The original task is removed, the children are created under the epic, and
+the split is recorded in the epic's log. Children default to the task's
+priority.
+
+Examples:
+  tpg split ts-abc123 --into "Parse config" --into "Validate config"
+
+  tpg split ts-abc123 --spec - <<EOF
+  title: Config overhaul
+  tasks:
+    - title: Parse config
+      desc: Read the new format
+    - title: Validate config
+      priority: 1
+  EOF
+
+  tpg split ts-abc123 --spec split.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := splitSpec{Title: flagSplitTitle}
+		for _, title := range flagSplitInto {
+			spec.Tasks = append(spec.Tasks, splitTaskSpec{Title: title})
+		}
+		if flagSplitSpec != "" {
+			var data []byte
+			var err error
+			if flagSplitSpec == "-" {
+				data, err = io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("failed to read from stdin: %w", err)
+				}
+			} else if data, err = os.ReadFile(flagSplitSpec); err != nil {
+				return fmt.Errorf("failed to read split spec: %w", err)
+			}
+			var fromSpec splitSpec
+			if err := yaml.Unmarshal(data, &fromSpec); err != nil {
+				return fmt.Errorf("failed to parse split spec YAML: %w", err)
+			}
+			if fromSpec.Title != "" && !cmd.Flags().Changed("title") {
+				spec.Title = fromSpec.Title
+			}
+			spec.Tasks = append(spec.Tasks, fromSpec.Tasks...)
+		}
+		if len(spec.Tasks) == 0 {
+			return fmt.Errorf("no child tasks given (use --into <title> or --spec <file|->)")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		old, err := database.GetItem(id)
+		if err != nil {
+			return err
+		}
+
+		epicID, err := database.GenerateItemID(model.ItemTypeEpic)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		epic := &model.Item{
+			ID:          epicID,
+			Project:     old.Project,
+			Type:        model.ItemTypeEpic,
+			Title:       old.Title,
+			Description: old.Description,
+			Priority:    old.Priority,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if strings.TrimSpace(spec.Title) != "" {
+			epic.Title, epic.Description = applyTitlePolicy(strings.TrimSpace(spec.Title), epic.Description)
+		}
+
+		var children []*model.Item
+		for _, t := range spec.Tasks {
+			priority := t.Priority
+			if priority == 0 {
+				priority = old.Priority
+			}
+			if err := model.ValidatePriority(priority); err != nil {
+				return fmt.Errorf("%q: %w", t.Title, err)
+			}
+			childID, err := database.GenerateItemID(model.ItemTypeTask)
+			if err != nil {
+				return err
+			}
+			title, description := applyTitlePolicy(strings.TrimSpace(t.Title), t.Desc)
+			children = append(children, &model.Item{
+				ID:          childID,
+				Project:     old.Project,
+				Type:        model.ItemTypeTask,
+				Title:       title,
+				Description: description,
+				Status:      model.StatusOpen,
+				Priority:    priority,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+		}
+
+		if err := database.SplitItem(old.ID, epic, children); err != nil {
+			return err
+		}
+
+		fmt.Printf("Split %s into %s: %s\n", old.ID, epic.ID, epic.Title)
+		for _, c := range children {
+			fmt.Printf("  %s  %s\n", c.ID, c.Title)
+		}
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+func init() {
+	splitCmd.Flags().StringArrayVar(&flagSplitInto, "into", nil, "Title of a child task (repeatable)")
+	splitCmd.Flags().StringVar(&flagSplitTitle, "title", "", "Title for the new epic (default: the task's title)")
+	splitCmd.Flags().StringVar(&flagSplitSpec, "spec", "", "Read the epic title and child tasks from a YAML file (- for stdin)")
+	splitCmd.Flags().BoolVar(&flagAllowLongTitle, "allow-long-title", false, "Keep multi-line or overlong titles instead of moving them into the description")
+	rootCmd.AddCommand(splitCmd)
+}
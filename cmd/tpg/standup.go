@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagStandupAgent string
+	flagStandupSince string
+)
+
+var standupCmd = &cobra.Command{
+	Use:   "standup",
+	Short: "Summarize an agent's recent work, current work, and blockers",
+	Long: `Print a daily check-in for one agent:
+
+  Yesterday        tasks the agent completed or worked on since --since
+  Today            the agent's in-progress tasks and the next ready tasks
+  Newly unblocked  tasks whose last dependency completed since --since
+  Blockers         tasks the agent marked blocked, and in-progress tasks
+                   waiting on unfinished dependencies
+
+Activity comes from history events recorded under the agent's ID, so only
+work done with $AGENT_ID set is attributed. --agent accepts an agent ID, a
+registered agent name, or "me" (the current $AGENT_ID).
+
+Examples:
+  tpg standup
+  tpg standup --since 72h
+  tpg standup --agent reviewer --project myproject`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := parseDuration(flagStandupSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		names := database.AgentNames()
+		agentID := flagStandupAgent
+		if agentID == "me" {
+			agentID = os.Getenv("AGENT_ID")
+			if agentID == "" {
+				return fmt.Errorf("AGENT_ID is not set; pass --agent <id or name>")
+			}
+		}
+		agentID = resolveAgentID(names, agentID)
+
+		report, err := database.Standup(agentID, project, time.Now().Add(-d))
+		if err != nil {
+			return err
+		}
+		if err := database.PopulateItemProgress(report.InProgress); err != nil {
+			return err
+		}
+		printStandup(report, agentDisplayName(names, agentID))
+		return nil
+	},
+}
+
+func printStandup(r *db.StandupReport, agentName string) {
+	fmt.Printf("Standup for %s (since %s)\n", agentName, r.Since.Local().Format("2006-01-02 15:04"))
+
+	logs := func(item model.Item) string {
+		switch n := r.LogCounts[item.ID]; n {
+		case 0:
+			return ""
+		case 1:
+			return " (1 log)"
+		default:
+			return fmt.Sprintf(" (%d logs)", n)
+		}
+	}
+
+	fmt.Println("\nYesterday:")
+	if len(r.Done) == 0 && len(r.Worked) == 0 {
+		fmt.Println("  (no recorded activity)")
+	}
+	for _, item := range r.Done {
+		fmt.Printf("  ✓ %s %s%s\n", item.ID, item.Title, logs(item))
+	}
+	for _, item := range r.Worked {
+		fmt.Printf("  • %s [%s] %s%s\n", item.ID, item.Status, item.Title, logs(item))
+	}
+
+	fmt.Println("\nToday:")
+	if len(r.InProgress) == 0 && len(r.Next) == 0 {
+		fmt.Println("  (nothing in progress or ready)")
+	}
+	for _, item := range r.InProgress {
+		fmt.Printf("  ◐ %s %s%s%s\n", item.ID, item.Title, progressSuffix(item), logs(item))
+	}
+	for _, item := range r.Next {
+		fmt.Printf("  ○ %s %s (ready)\n", item.ID, item.Title)
+	}
+
+	if len(r.Unblocked) > 0 {
+		fmt.Println("\nNewly unblocked:")
+		for _, item := range r.Unblocked {
+			fmt.Printf("  ↻ %s %s\n", item.ID, item.Title)
+		}
+	}
+
+	fmt.Println("\nBlockers:")
+	if len(r.Blocked) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, b := range r.Blocked {
+		if len(b.Waiting) == 0 {
+			fmt.Printf("  ⊘ %s %s (blocked)\n", b.Item.ID, b.Item.Title)
+			continue
+		}
+		fmt.Printf("  ⊘ %s %s waiting on:\n", b.Item.ID, b.Item.Title)
+		for _, dep := range b.Waiting {
+			fmt.Printf("      %s [%s] %s\n", dep.ID, dep.Status, dep.Title)
+		}
+	}
+}
+
+func init() {
+	standupCmd.Flags().StringVar(&flagStandupAgent, "agent", "me", "Agent ID or name (\"me\" for $AGENT_ID)")
+	standupCmd.Flags().StringVar(&flagStandupSince, "since", "24h", "How far back to look (e.g. 24h, 3d)")
+	rootCmd.AddCommand(standupCmd)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
+)
+
+var flagSummaryEpics bool
+
+// epicHealthWarn is the score below which an epic is flagged as neglected.
+const epicHealthWarn = 60
+
+// printEpicHealth prints one line per open epic, least healthy first.
+func printEpicHealth(report []db.EpicHealth, now time.Time) {
+	fmt.Println()
+	if len(report) == 0 {
+		fmt.Println("Epics: none open")
+		return
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Score(now) < report[j].Score(now)
+	})
+
+	growthLimit := db.DefaultEpicScopeGrowth
+	if config, err := db.LoadConfig(); err == nil {
+		growthLimit = config.GetEpicScopeGrowth()
+	}
+
+	fmt.Println("Epic health:")
+	for _, h := range report {
+		score := h.Score(now)
+		scoreText := fmt.Sprintf("%3d", score)
+		if score < epicHealthWarn {
+			scoreText = format.Warning(scoreText)
+		}
+		line := fmt.Sprintf("  %s  [%s] %s  %d/%d left, active %s",
+			scoreText, format.ID(h.Epic.ID), h.Epic.Title, h.Remaining, h.Tasks, formatTimeAgo(h.LastActivity))
+		if h.Scope.Added > 0 {
+			growth := fmt.Sprintf("+%d since start", h.Scope.Added)
+			if h.Scope.Added > growthLimit {
+				growth = format.Warning(growth)
+			}
+			line += ", " + growth
+		}
+		if problems := h.Problems(now); len(problems) > 0 {
+			line += "  " + format.Dim("("+strings.Join(problems, ", ")+")")
+		}
+		fmt.Println(line)
+	}
+}
+
+func init() {
+	summaryCmd.Flags().BoolVar(&flagSummaryEpics, "epics", false, "Show a health score for each open epic")
+}
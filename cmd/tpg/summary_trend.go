@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
+)
+
+var flagSummaryDays int
+
+// printSummaryTrend prints sparklines of daily completions and the open
+// task count.
+func printSummaryTrend(trend *db.Trend) {
+	days := len(trend.Days)
+	if days == 0 {
+		return
+	}
+	total, peak := 0, 0
+	for _, n := range trend.Completed {
+		total += n
+		peak = max(peak, n)
+	}
+
+	fmt.Printf("\nLast %d days (%s to %s):\n", days,
+		trend.Days[0].Format("2006-01-02"), trend.Days[days-1].Format("2006-01-02"))
+	fmt.Printf("  Completed  %s  %d total, peak %d/day\n", format.Sparkline(trend.Completed), total, peak)
+	fmt.Printf("  Open       %s  %d -> %d\n", format.Sparkline(trend.Open), trend.Open[0], trend.Open[days-1])
+}
+
+func init() {
+	summaryCmd.Flags().IntVar(&flagSummaryDays, "days", 30, "Days of history to chart (0 to hide the charts)")
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var flagResyncAll bool
+
+// templateResync is a pending re-render of one templated item.
+type templateResync struct {
+	item     *model.Item // as stored
+	title    string      // rendered from the current template
+	desc     string
+	hash     string
+	template string
+}
+
+var templateResyncCmd = &cobra.Command{
+	Use:   "resync [item-id]",
+	Short: "Re-render templated items after their template changed",
+	Long: `Re-render the stored title and description of items created from a
+template that has changed since they were instantiated.
+
+A diff of every change is shown and confirmation is required before anything
+is written (use the global --yes flag or TPG_ASSUME_YES=1 to skip the prompt). Items whose template is unchanged
+are left alone. Done and canceled items are skipped by --all.
+
+Examples:
+  tpg template resync ts-a1b2c3
+  tpg template resync --all
+  tpg template resync --all --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagResyncAll == (len(args) == 1) {
+			return fmt.Errorf("give an item ID or --all")
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		var items []model.Item
+		if flagResyncAll {
+			project, err := resolveProject()
+			if err != nil {
+				return err
+			}
+			items, err = database.TemplatedItems(project)
+			if err != nil {
+				return err
+			}
+		} else {
+			id, err := resolveItemArg(database, args[0])
+			if err != nil {
+				return err
+			}
+			item, err := database.GetItem(id)
+			if err != nil {
+				return err
+			}
+			if item.TemplateID == "" {
+				return fmt.Errorf("%s was not created from a template", item.ID)
+			}
+			items = []model.Item{*item}
+		}
+
+		cache := &templateCache{}
+		var pending []templateResync
+		for i := range items {
+			r, err := planTemplateResync(cache, &items[i])
+			if err != nil {
+				return err
+			}
+			if r != nil {
+				pending = append(pending, *r)
+			}
+		}
+
+		if len(pending) == 0 {
+			fmt.Println("All templated items are up to date")
+			return nil
+		}
+
+		for _, r := range pending {
+			printTemplateResync(r)
+		}
+
+		if !assumeYes() && !stdinIsTerminal() {
+			return fmt.Errorf("refusing to resync %d item(s) without confirmation; re-run with --yes", len(pending))
+		}
+		if !confirm(fmt.Sprintf("Apply %d resync(s)?", len(pending))) {
+			fmt.Println("Aborted")
+			return nil
+		}
+
+		for _, r := range pending {
+			if err := database.ResyncTemplate(r.item.ID, r.title, r.desc, r.hash); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Re-synced %d item(s)\n", len(pending))
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+// planTemplateResync renders item against its current template. It returns
+// nil when the template is unchanged or can no longer be found.
+func planTemplateResync(cache *templateCache, item *model.Item) (*templateResync, error) {
+	tmpl, err := cache.get(item.TemplateID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: template not found: %s (item: %s)\n", item.TemplateID, item.ID)
+		return nil, nil
+	}
+	if tmpl.Hash == "" || item.TemplateHash == tmpl.Hash {
+		return nil, nil
+	}
+
+	rendered := *item
+	vars := make(map[string]string, len(item.TemplateVars))
+	for k, v := range item.TemplateVars {
+		vars[k] = v
+	}
+	rendered.TemplateVars = vars
+	if _, err := renderItemTemplate(cache, &rendered); err != nil {
+		return nil, err
+	}
+	return &templateResync{
+		item:     item,
+		title:    rendered.Title,
+		desc:     rendered.Description,
+		hash:     tmpl.Hash,
+		template: tmpl.ID,
+	}, nil
+}
+
+func printTemplateResync(r templateResync) {
+	fmt.Printf("%s %s (template %s)\n", r.item.ID, r.item.Title, r.template)
+	if r.title == r.item.Title && r.desc == r.item.Description {
+		fmt.Println("  (text unchanged; only the template version is updated)")
+		fmt.Println()
+		return
+	}
+	if r.title != r.item.Title {
+		fmt.Printf("  - title: %s\n", r.item.Title)
+		fmt.Printf("  + title: %s\n", r.title)
+	}
+	if r.desc != r.item.Description {
+		for _, line := range lineDiff(r.item.Description, r.desc) {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+// lineDiff returns a minimal line diff of a and b, each line prefixed with
+// "- ", "+ ", or "  ".
+func lineDiff(a, b string) []string {
+	var x, y []string
+	if a != "" {
+		x = strings.Split(a, "\n")
+	}
+	if b != "" {
+		y = strings.Split(b, "\n")
+	}
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, "  "+x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, "- "+x[i])
+	}
+	for ; j < len(y); j++ {
+		out = append(out, "+ "+y[j])
+	}
+	return out
+}
+
+func init() {
+	templateResyncCmd.Flags().BoolVar(&flagResyncAll, "all", false, "Resync every open templated item in the project")
+	templateCmd.AddCommand(templateResyncCmd)
+}
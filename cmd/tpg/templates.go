@@ -33,6 +33,37 @@ func (c *templateCache) get(id string) (*templates.Template, error) {
 	return tmpl, nil
 }
 
+// templateIndex stores parsed templates in the database so later commands
+// can skip reading and parsing unchanged template files. Failures only cost
+// a re-parse, so they are ignored.
+type templateIndex struct {
+	database *db.DB
+}
+
+func (idx templateIndex) GetTemplate(path string) (templates.IndexEntry, bool) {
+	entry, err := idx.database.GetTemplateIndex(path)
+	if err != nil || entry == nil {
+		return templates.IndexEntry{}, false
+	}
+	return templates.IndexEntry{
+		Path:    entry.Path,
+		ModTime: entry.ModTime,
+		Size:    entry.Size,
+		Hash:    entry.Hash,
+		Data:    []byte(entry.Data),
+	}, true
+}
+
+func (idx templateIndex) PutTemplate(entry templates.IndexEntry) {
+	_ = idx.database.PutTemplateIndex(db.TemplateIndexEntry{
+		Path:    entry.Path,
+		ModTime: entry.ModTime,
+		Size:    entry.Size,
+		Hash:    entry.Hash,
+		Data:    string(entry.Data),
+	})
+}
+
 func parseTemplateVars(pairs []string) (map[string]string, error) {
 	vars := map[string]string{}
 	for _, pair := range pairs {
@@ -139,18 +170,75 @@ func assignStepIDs(steps []templates.Step) ([]string, error) {
 	return ids, nil
 }
 
-func instantiateTemplate(database *db.DB, project, title, templateID string, varPairs []string, priority int, parentType model.ItemType) (string, error) {
+// templateContextVars returns the epic and config context for a template
+// instantiated under parentID (empty for none).
+func templateContextVars(database *db.DB, project, parentID string) (map[string]string, error) {
+	var epic map[string]string
+	if parentID != "" {
+		parent, err := database.GetItem(parentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent not found: %s (use 'tpg list' to see available items)", parentID)
+		}
+		epic = map[string]string{
+			"ID":                  parent.ID,
+			"Title":               parent.Title,
+			"Description":         parent.Description,
+			"SharedContext":       parent.SharedContext,
+			"ClosingInstructions": parent.ClosingInstructions,
+		}
+	}
+
+	values := map[string]string{"project": project}
+	if config, err := db.LoadConfig(); err == nil {
+		values["default_project"] = config.DefaultProject
+		values["branch_prefix"] = worktreePrefix(config)
+		for k, v := range config.TemplateValues {
+			values[k] = v
+		}
+	}
+	return templates.ContextVars(epic, values), nil
+}
+
+func instantiateTemplate(database *db.DB, project, title, templateID string, varPairs []string, priority int, parentType model.ItemType, parentEpicID string) (string, error) {
 	vars, err := parseTemplateVars(varPairs)
 	if err != nil {
 		return "", err
 	}
+	result, err := createFromTemplate(database, project, title, templateID, vars, priority, parentEpicID)
+	if err != nil {
+		return "", err
+	}
+
+	// Print worktree instructions if applicable
+	if result.WorktreeBranch != "" {
+		fmt.Printf("\n📁 Worktree setup:\n")
+		fmt.Printf("  Branch: %s\n", result.WorktreeBranch)
+		fmt.Printf("  Base: %s\n", result.WorktreeBase)
+		fmt.Printf("\n  Create worktree:\n")
+		fmt.Printf("    git worktree add -b %s .worktrees/%s %s\n", result.WorktreeBranch, result.ParentID, result.WorktreeBase)
+		fmt.Printf("\n  Navigate to worktree:\n")
+		fmt.Printf("    cd .worktrees/%s\n", result.ParentID)
+	}
+
+	// Print what was created
+	printTemplateResult(result)
+	return result.ParentID, nil
+}
+
+// createFromTemplate creates the items for a template without printing
+// anything. vars holds the caller's variable values; defaults are applied
+// and required variables checked here. The TUI uses it too.
+func createFromTemplate(database *db.DB, project, title, templateID string, vars map[string]string, priority int, parentEpicID string) (templateResult, error) {
+	if vars == nil {
+		vars = map[string]string{}
+	}
 	if strings.TrimSpace(title) == "" {
-		return "", fmt.Errorf("title is required for template instantiation")
+		return templateResult{}, fmt.Errorf("title is required for template instantiation")
 	}
 
 	tmpl, err := templates.LoadTemplate(templateID)
 	if err != nil {
-		return "", err
+		return templateResult{}, err
 	}
 
 	// Apply defaults and check required variables
@@ -162,7 +250,7 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 				vars[name] = varDef.Default
 			} else if !varDef.Optional {
 				// Only error if no default AND not optional
-				return "", fmt.Errorf("missing required template variable: %s", name)
+				return templateResult{}, fmt.Errorf("missing required template variable: %s", name)
 			} else {
 				// Optional with no default: use empty string
 				vars[name] = ""
@@ -172,28 +260,32 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 	// Check for unknown variables
 	for name := range vars {
 		if _, ok := tmpl.Variables[name]; !ok {
-			return "", fmt.Errorf("unknown template variable: %s", name)
+			return templateResult{}, fmt.Errorf("unknown template variable: %s", name)
 		}
 	}
 
+	contextVars, err := templateContextVars(database, project, parentEpicID)
+	if err != nil {
+		return templateResult{}, err
+	}
+	for k, v := range contextVars {
+		vars[k] = v
+	}
+
 	stepIDs, err := assignStepIDs(tmpl.Steps)
 	if err != nil {
-		return "", err
+		return templateResult{}, err
 	}
-	stepIDSet := map[string]bool{}
-	for _, id := range stepIDs {
-		stepIDSet[id] = true
+	instances, err := templates.Expand(tmpl.Steps, stepIDs, vars)
+	if err != nil {
+		return templateResult{}, err
 	}
-	for i, step := range tmpl.Steps {
-		for _, dep := range step.Depends {
-			if !stepIDSet[dep] {
-				return "", fmt.Errorf("step %d depends on unknown step id: %s", i, dep)
-			}
-		}
+	if len(instances) == 0 {
+		return templateResult{}, fmt.Errorf("every step of template %s was skipped by its when condition; nothing to create", tmpl.ID)
 	}
 
 	// Single-step template: create just a task
-	if len(tmpl.Steps) == 1 {
+	if len(tmpl.Steps) == 1 && tmpl.Steps[0].ForEach == "" {
 		step := tmpl.Steps[0]
 		renderedStep := templates.RenderStep(step, vars)
 
@@ -205,7 +297,7 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 
 		itemID, err := database.GenerateItemID(model.ItemTypeTask)
 		if err != nil {
-			return "", err
+			return templateResult{}, err
 		}
 
 		now := time.Now()
@@ -227,18 +319,17 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 		}
 
 		if err := database.CreateItem(item); err != nil {
-			return "", err
+			return templateResult{}, err
 		}
 
-		printTemplateResult(templateResult{ParentID: itemID, IsEpic: false})
-		return itemID, nil
+		return templateResult{ParentID: itemID}, nil
 	}
 
 	// Zero-step template: create just a task (no steps to render)
 	if len(tmpl.Steps) == 0 {
 		itemID, err := database.GenerateItemID(model.ItemTypeTask)
 		if err != nil {
-			return "", err
+			return templateResult{}, err
 		}
 
 		now := time.Now()
@@ -257,20 +348,19 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 		}
 
 		if err := database.CreateItem(item); err != nil {
-			return "", err
+			return templateResult{}, err
 		}
 
-		printTemplateResult(templateResult{ParentID: itemID, IsEpic: false})
-		return itemID, nil
+		return templateResult{ParentID: itemID}, nil
 	}
 
 	// Multi-step template: create epic with children
-	parentType = model.ItemTypeEpic
+	parentType := model.ItemTypeEpic
 
 	// ... rest of multi-step logic continues ...
 	parentID, err := database.GenerateItemID(parentType)
 	if err != nil {
-		return "", err
+		return templateResult{}, err
 	}
 	createdIDs := []string{}
 	cleanup := func() {
@@ -315,30 +405,29 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 		WorktreeBranch:      worktreeBranch,
 		WorktreeBase:        worktreeBase,
 		SharedContext:       templates.RenderText(tmpl.Context, vars),
-		ClosingInstructions: templates.RenderText(tmpl.OnClose, vars),
+		ClosingInstructions: renderOnClose(tmpl.OnClose, vars),
 		CreatedAt:           now,
 		UpdatedAt:           now,
 	}
 	if err := database.CreateItem(parent); err != nil {
-		return "", err
+		return templateResult{}, err
 	}
 	createdIDs = append(createdIDs, parentID)
 
-	childIDs := make([]string, len(tmpl.Steps))
-	stepIDToChildID := map[string]string{}
-	for i, step := range tmpl.Steps {
+	childIDs := make([]string, len(instances))
+	for i, inst := range instances {
 		childID, err := database.GenerateItemID(model.ItemTypeTask)
 		if err != nil {
 			cleanup()
-			return "", err
+			return templateResult{}, err
 		}
-		idx := i
+		idx := inst.StepIndex
 
 		// Render step title with variable substitution
-		renderedStep := templates.RenderStep(step, vars)
+		renderedStep := templates.RenderStep(tmpl.Steps[idx], inst.Vars)
 		stepTitle := renderedStep.Title
 		if stepTitle == "" {
-			stepTitle = fmt.Sprintf("%s step %d", tmpl.ID, i+1)
+			stepTitle = fmt.Sprintf("%s step %d", tmpl.ID, idx+1)
 		}
 
 		child := &model.Item{
@@ -351,50 +440,37 @@ func instantiateTemplate(database *db.DB, project, title, templateID string, var
 			ParentID:     &parentID,
 			TemplateID:   tmpl.ID,
 			StepIndex:    &idx,
-			TemplateVars: vars,
+			TemplateVars: inst.Vars,
 			TemplateHash: tmpl.Hash,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
 		if err := database.CreateItem(child); err != nil {
 			cleanup()
-			return "", err
+			return templateResult{}, err
 		}
 		createdIDs = append(createdIDs, childID)
 		childIDs[i] = childID
-		stepIDToChildID[stepIDs[i]] = childID
 	}
 
-	for i, step := range tmpl.Steps {
+	for i, inst := range instances {
 		childID := childIDs[i]
-		for _, dep := range step.Depends {
-			depID := stepIDToChildID[dep]
+		for _, dep := range inst.Depends {
+			depID := childIDs[dep]
 			if err := database.AddDep(childID, depID); err != nil {
 				cleanup()
-				return "", err
+				return templateResult{}, err
 			}
 		}
 	}
 
-	// Print worktree instructions if applicable
-	if worktreeBranch != "" {
-		fmt.Printf("\n📁 Worktree setup:\n")
-		fmt.Printf("  Branch: %s\n", worktreeBranch)
-		fmt.Printf("  Base: %s\n", worktreeBase)
-		fmt.Printf("\n  Create worktree:\n")
-		fmt.Printf("    git worktree add -b %s .worktrees/%s %s\n", worktreeBranch, parentID, worktreeBase)
-		fmt.Printf("\n  Navigate to worktree:\n")
-		fmt.Printf("    cd .worktrees/%s\n", parentID)
-	}
-
-	// Print what was created
-	printTemplateResult(templateResult{
-		ParentID: parentID,
-		ChildIDs: childIDs,
-		IsEpic:   parentType == model.ItemTypeEpic,
-	})
-
-	return parentID, nil
+	return templateResult{
+		ParentID:       parentID,
+		ChildIDs:       childIDs,
+		IsEpic:         true,
+		WorktreeBranch: worktreeBranch,
+		WorktreeBase:   worktreeBase,
+	}, nil
 }
 
 // templateResult holds the result of instantiating a template
@@ -402,6 +478,9 @@ type templateResult struct {
 	ParentID string   // The main item/epic ID
 	ChildIDs []string // Child task IDs (if multi-step)
 	IsEpic   bool     // Whether parent is an epic
+
+	WorktreeBranch string // Worktree branch recorded on the epic, if any
+	WorktreeBase   string
 }
 
 // printTemplateResult outputs what was created
@@ -421,6 +500,16 @@ func (r templateResult) String() string {
 	return fmt.Sprintf("Created task %s", r.ParentID)
 }
 
+// renderOnClose renders a template's on_close text at instantiation. Text
+// that refers to close-time fields (.Children, .Results, files) is kept
+// as-is and rendered when the epic completes, with variables under .Vars.
+func renderOnClose(text string, vars map[string]string) string {
+	if templates.UsesCloseFields(text) {
+		return text
+	}
+	return templates.RenderText(text, vars)
+}
+
 func sanitizeTitle(title string) string {
 	result := strings.ReplaceAll(title, "\n", " ")
 	result = strings.TrimSpace(result)
@@ -474,6 +563,7 @@ func renderItemTemplate(cache *templateCache, item *model.Item) (bool, error) {
 }
 
 func renderTemplatesWithCache(cache *templateCache, items []model.Item) error {
+	defer timePhase("render")()
 	for i := range items {
 		if _, err := renderItemTemplate(cache, &items[i]); err != nil {
 			return err
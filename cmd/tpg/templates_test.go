@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/taxilian/tpg/internal/db"
 	"github.com/taxilian/tpg/internal/model"
@@ -1534,7 +1535,7 @@ steps:
 	}
 
 	// Instantiate the template
-	parentID, err := instantiateTemplate(database, "", "Test Epic", "context-test", vars, 2, model.ItemTypeEpic)
+	parentID, err := instantiateTemplate(database, "", "Test Epic", "context-test", vars, 2, model.ItemTypeEpic, "")
 	if err != nil {
 		t.Fatalf("instantiateTemplate failed: %v", err)
 	}
@@ -1593,6 +1594,79 @@ steps:
 	}
 }
 
+func TestInstantiateTemplate_EpicContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	templatesDir := filepath.Join(tmpDir, ".tpg", "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatalf("failed to create templates dir: %v", err)
+	}
+	templateContent := `title: "Epic context"
+variables:
+  what:
+    description: "What to do"
+steps:
+  - title: "{{.what}} for {{.Epic.Title}}"
+    description: |
+      Project: {{.Config.project}}
+      {{- if .Epic.SharedContext}}
+      Context: {{.Epic.SharedContext}}
+      {{- end}}
+`
+	if err := os.WriteFile(filepath.Join(templatesDir, "epic-context.yaml"), []byte(templateContent), 0644); err != nil {
+		t.Fatalf("failed to write template file: %v", err)
+	}
+
+	originalWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalWd)
+
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	if err := database.Init(); err != nil {
+		t.Fatalf("failed to init database: %v", err)
+	}
+
+	now := time.Now()
+	epic := &model.Item{
+		ID: "ep-ctx", Project: "proj", Type: model.ItemTypeEpic, Title: "Billing revamp",
+		Status: model.StatusOpen, Priority: 2, SharedContext: "Use the v2 API",
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := database.CreateItem(epic); err != nil {
+		t.Fatalf("failed to create epic: %v", err)
+	}
+
+	id, err := instantiateTemplate(database, "proj", "Task", "epic-context", []string{`what="Add tests"`}, 2, model.ItemTypeTask, epic.ID)
+	if err != nil {
+		t.Fatalf("instantiateTemplate failed: %v", err)
+	}
+	item, err := database.GetItem(id)
+	if err != nil {
+		t.Fatalf("failed to get item: %v", err)
+	}
+	if item.Title != "Add tests for Billing revamp" {
+		t.Errorf("title = %q", item.Title)
+	}
+	if !strings.Contains(item.Description, "Project: proj") || !strings.Contains(item.Description, "Context: Use the v2 API") {
+		t.Errorf("description missing context: %q", item.Description)
+	}
+
+	// Display-time rendering sees the same captured context.
+	if _, err := renderItemTemplate(&templateCache{}, item); err != nil {
+		t.Fatalf("renderItemTemplate failed: %v", err)
+	}
+	if item.Title != "Add tests for Billing revamp" {
+		t.Errorf("rendered title = %q", item.Title)
+	}
+
+	if _, err := instantiateTemplate(database, "proj", "Task", "epic-context", []string{`what="x"`}, 2, model.ItemTypeTask, "ep-missing"); err == nil {
+		t.Error("expected error for a missing parent")
+	}
+}
+
 func TestVarsYAML_EndToEndSubstitution(t *testing.T) {
 	// Test: Complete end-to-end test simulating the exact bug scenario:
 	// 1. User provides vars via --vars-yaml
@@ -1700,3 +1774,16 @@ base_branch: "main"`
 		t.Errorf("expected base_branch in description, got: %q", item.Description)
 	}
 }
+
+func TestLineDiff(t *testing.T) {
+	got := lineDiff("a\nb\nc", "a\nc\nd")
+	want := []string{"  a", "- b", "  c", "+ d"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("lineDiff = %q, want %q", got, want)
+	}
+
+	got = lineDiff("", "new")
+	if len(got) != 1 || got[0] != "+ new" {
+		t.Errorf("lineDiff from empty = %q", got)
+	}
+}
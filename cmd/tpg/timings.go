@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var flagTimings bool
+
+// phaseTimings accumulates how long a command spent in each phase, in the
+// order the phases first ran.
+type phaseTimings struct {
+	mu     sync.Mutex
+	order  []string
+	totals map[string]time.Duration
+	counts map[string]int
+}
+
+// timings is non-nil while the current command is being timed.
+var timings *phaseTimings
+
+// timingsRequested reports whether --timings or TPG_PROFILE=1 asked for a
+// per-phase timing report.
+func timingsRequested() bool {
+	return flagTimings || os.Getenv("TPG_PROFILE") == "1"
+}
+
+// startTimings begins collecting phase timings when they were requested.
+func startTimings() {
+	if !timingsRequested() {
+		return
+	}
+	timings = &phaseTimings{totals: map[string]time.Duration{}, counts: map[string]int{}}
+	db.PhaseTimer = timings.add
+}
+
+func (t *phaseTimings) add(phase string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.totals[phase]; !ok {
+		t.order = append(t.order, phase)
+	}
+	t.totals[phase] += d
+	t.counts[phase]++
+}
+
+// timePhase times a phase of the current command when timings are on; call
+// the returned function when the phase ends.
+func timePhase(phase string) func() {
+	if timings == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { timings.add(phase, time.Since(start)) }
+}
+
+// reportTimings writes the collected timings to w. Time not spent in any
+// timed phase is reported as "query": the command's own database work and
+// everything else it did.
+func reportTimings(w io.Writer, total time.Duration) {
+	if timings == nil {
+		return
+	}
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+
+	fmt.Fprintln(w, "timings:")
+	rest := total
+	for _, phase := range timings.order {
+		d := timings.totals[phase]
+		rest -= d
+		line := fmt.Sprintf("  %-10s %10s", phase, formatTiming(d))
+		if n := timings.counts[phase]; n > 1 {
+			line += fmt.Sprintf("  (%d times)", n)
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "  %-10s %10s\n", "query", formatTiming(max(rest, 0)))
+	fmt.Fprintf(w, "  %-10s %10s\n", "total", formatTiming(total))
+}
+
+// formatTiming rounds d to a readable precision.
+func formatTiming(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
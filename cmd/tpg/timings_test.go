@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+func TestReportTimings(t *testing.T) {
+	t.Setenv("TPG_PROFILE", "1")
+	startTimings()
+	t.Cleanup(func() { timings = nil; db.PhaseTimer = nil })
+
+	db.PhaseTimer("db open", 2*time.Millisecond)
+	db.PhaseTimer("migration", time.Millisecond)
+	timings.add("render", time.Millisecond)
+	timings.add("render", time.Millisecond)
+
+	var buf bytes.Buffer
+	reportTimings(&buf, 10*time.Millisecond)
+	out := buf.String()
+	for _, want := range []string{
+		"  db open           2ms\n",
+		"  migration         1ms\n",
+		"  render            2ms  (2 times)\n",
+		"  query             5ms\n",
+		"  total            10ms\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "db open") > strings.Index(out, "render") {
+		t.Errorf("phases not in the order they ran:\n%s", out)
+	}
+}
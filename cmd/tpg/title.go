@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var flagAllowLongTitle bool
+
+// fitTitle shortens a title that breaks the title policy to its first line,
+// cut at a word boundary to fit maxLen, and moves the full text to the top of
+// the description so nothing is lost.
+func fitTitle(title, description string, maxLen int) (string, string) {
+	full := strings.TrimSpace(title)
+	short := full
+	if i := strings.IndexAny(short, "\r\n"); i >= 0 {
+		short = strings.TrimSpace(short[:i])
+	}
+	if utf8.RuneCountInString(short) > maxLen {
+		runes := []rune(short)
+		cut := string(runes[:max(maxLen-3, 1)])
+		if i := strings.LastIndexAny(cut, " \t"); i > len(cut)/2 {
+			cut = cut[:i]
+		}
+		short = strings.TrimRight(cut, " \t.,;:") + "..."
+	}
+	if description != "" {
+		description = full + "\n\n" + description
+	} else {
+		description = full
+	}
+	return short, description
+}
+
+// maxTitleLength returns the configured title limit.
+func maxTitleLength() int {
+	config, err := db.LoadConfig()
+	if err != nil {
+		return db.DefaultMaxTitleLength
+	}
+	return config.PolicyMaxTitleLength()
+}
+
+// applyTitlePolicy returns the title and description to create an item with.
+// Unless --allow-long-title is set, a multi-line or overlong title is
+// shortened and its full text moved into the description, with a note on
+// stderr.
+func applyTitlePolicy(title, description string) (string, string) {
+	if flagAllowLongTitle {
+		return title, description
+	}
+	maxLen := maxTitleLength()
+	problem := db.TitleProblem(title, maxLen)
+	if problem == "" {
+		return title, description
+	}
+	title, description = fitTitle(title, description, maxLen)
+	fmt.Fprintf(os.Stderr, "Note: the title %s; shortened it to %q and moved the full text to the description (use --allow-long-title to keep it)\n", problem, title)
+	return title, description
+}
+
+// checkTitlePolicy returns an error when a title breaks the title policy and
+// --allow-long-title isn't set, for commands that can't move the excess into
+// a description.
+func checkTitlePolicy(title string) error {
+	if flagAllowLongTitle {
+		return nil
+	}
+	maxLen := maxTitleLength()
+	if problem := db.TitleProblem(title, maxLen); problem != "" {
+		return fmt.Errorf("the title %s; shorten it, put the details in the description, or use --allow-long-title", problem)
+	}
+	return nil
+}
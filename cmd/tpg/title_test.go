@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFitTitle(t *testing.T) {
+	title, desc := fitTitle("Fix login bug\nIt fails when the token expires.", "Existing notes", 100)
+	if title != "Fix login bug" {
+		t.Errorf("title = %q, want the first line", title)
+	}
+	if desc != "Fix login bug\nIt fails when the token expires.\n\nExisting notes" {
+		t.Errorf("description = %q", desc)
+	}
+
+	long := "Refactor the authentication middleware so that token refresh happens before expiry"
+	title, desc = fitTitle(long, "", 40)
+	if len([]rune(title)) > 40 || !strings.HasSuffix(title, "...") {
+		t.Errorf("title = %q, want at most 40 characters ending in ...", title)
+	}
+	if !strings.HasPrefix(long, strings.TrimSuffix(title, "...")) || strings.HasSuffix(strings.TrimSuffix(title, "..."), " ") {
+		t.Errorf("title = %q, want a prefix cut at a word boundary", title)
+	}
+	if desc != long {
+		t.Errorf("description = %q, want the full title", desc)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTranscriptName string
+	flagTranscriptTail int
+)
+
+var transcriptCmd = &cobra.Command{
+	Use:   "transcript",
+	Short: "Attach command output to tasks",
+	Long: `Keep large blocks of text, such as the full output of a test run or build,
+with a task. Transcripts are stored compressed in their own table, so they
+don't bloat the task's description or logs; 'tpg show' only counts them.
+
+Examples:
+  go test ./... 2>&1 | tpg transcript add ts-a1b2c3 - --name "go test"
+  tpg transcript add ts-a1b2c3 build.log
+  tpg transcript list ts-a1b2c3
+  tpg transcript show 3 --tail 50`,
+}
+
+var transcriptAddCmd = &cobra.Command{
+	Use:   "add <id> <file|->",
+	Short: "Attach a file or stdin to a task as a transcript",
+	Long: `Attach the contents of a file, or stdin with "-", to a task as a transcript.
+It is named after the file unless --name is given.
+
+Examples:
+  go test ./... 2>&1 | tpg transcript add ts-a1b2c3 - --name "go test"
+  tpg transcript add ts-a1b2c3 build.log`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		name := flagTranscriptName
+		if args[1] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read from stdin: %w", err)
+			}
+			if name == "" {
+				name = "stdin"
+			}
+		} else {
+			data, err = os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read transcript: %w", err)
+			}
+			if name == "" {
+				name = filepath.Base(args[1])
+			}
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("transcript is empty")
+		}
+
+		transcriptID, err := database.AddTranscript(id, name, string(data))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Attached transcript #%d (%s, %s) to %s\n", transcriptID, name, formatSize(int64(len(data))), id)
+
+		database.BackupQuiet()
+		return nil
+	},
+}
+
+var transcriptListCmd = &cobra.Command{
+	Use:   "list <id>",
+	Short: "List a task's transcripts",
+	Long: `List the transcripts attached to a task, oldest first, with their IDs,
+names, sizes (and compressed sizes), and ages.
+
+Example:
+  tpg transcript list ts-a1b2c3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		id, err := resolveItemArg(database, args[0])
+		if err != nil {
+			return err
+		}
+		transcripts, err := database.ListTranscripts(id)
+		if err != nil {
+			return err
+		}
+		if len(transcripts) == 0 {
+			fmt.Printf("No transcripts on %s\n", id)
+			return nil
+		}
+		for _, t := range transcripts {
+			fmt.Printf("#%d  %s  %s (%s stored)  %s\n",
+				t.ID, t.Name, formatSize(t.Size), formatSize(t.StoredSize), formatTimeAgo(t.CreatedAt))
+		}
+		return nil
+	},
+}
+
+var transcriptShowCmd = &cobra.Command{
+	Use:   "show <transcript-id>",
+	Short: "Print a transcript",
+	Long: `Print a transcript, by the ID shown in 'tpg transcript list' (with or
+without '#'). --tail prints only its last N lines.
+
+Examples:
+  tpg transcript show 3
+  tpg transcript show '#3' --tail 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		transcriptID, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+		if err != nil || transcriptID <= 0 {
+			return fmt.Errorf("invalid transcript ID: %s (use the number shown by 'tpg transcript list')", args[0])
+		}
+
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		t, err := database.GetTranscript(transcriptID)
+		if err != nil {
+			return err
+		}
+		text := t.Text
+		if flagTranscriptTail > 0 {
+			text = tailLines(text, flagTranscriptTail)
+		}
+		fmt.Print(text)
+		if !strings.HasSuffix(text, "\n") {
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+func init() {
+	transcriptAddCmd.Flags().StringVar(&flagTranscriptName, "name", "", "Name for the transcript (default: the file name, or \"stdin\")")
+	transcriptShowCmd.Flags().IntVar(&flagTranscriptTail, "tail", 0, "Print only the last N lines")
+	transcriptCmd.AddCommand(transcriptAddCmd)
+	transcriptCmd.AddCommand(transcriptListCmd)
+	transcriptCmd.AddCommand(transcriptShowCmd)
+	rootCmd.AddCommand(transcriptCmd)
+}
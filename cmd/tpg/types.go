@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+var (
+	flagTypesPrefix   string
+	flagTypesPriority int
+	flagTypesChildren bool
+	flagTypesIcon     string
+	flagTypesColor    string
+)
+
+var typesCmd = &cobra.Command{
+	Use:   "types",
+	Short: "Manage item types",
+	Long: `Manage the item types allowed in this project.
+
+The built-in types are "task" and "epic". Custom types are stored in
+.tpg/config.json under "types" and define:
+  - prefix             ID prefix (default: first two letters of the name)
+  - default_priority   Priority used by 'tpg add' when -p is not given
+  - can_have_children  Whether items of this type may be parents
+  - icon, color        Display hints for list output and the TUI
+
+'tpg add --type' and 'tpg edit --type' only accept registered types.
+
+Examples:
+  tpg types list
+  tpg types add bug --prefix bg --priority 1 --icon "🐛" --color "#ff5555"
+  tpg types add story --children
+  tpg types rm bug`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return typesListCmd.RunE(cmd, args)
+	},
+}
+
+var typesListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List item types",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registerConfiguredTypes()
+
+		fmt.Printf("%-12s %-8s %-4s %-9s %-5s %s\n", "TYPE", "PREFIX", "PRI", "CHILDREN", "ICON", "COLOR")
+		for _, info := range model.ItemTypes() {
+			name := string(info.Name)
+			if info.Builtin {
+				name += "*"
+			}
+			children := "no"
+			if info.CanHaveChildren {
+				children = "yes"
+			}
+			icon := info.Icon
+			if icon == "" {
+				icon = "-"
+			}
+			color := info.Color
+			if color == "" {
+				color = "-"
+			}
+			fmt.Printf("%-12s %-8s %-4d %-9s %-5s %s\n", name, info.Prefix+"-", info.DefaultPriority, children, icon, color)
+		}
+		fmt.Println("\n* built-in")
+		return nil
+	},
+}
+
+var typesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add or update a custom item type",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimSpace(args[0])
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("invalid type name: %q", args[0])
+		}
+		if name == string(model.ItemTypeTask) || name == string(model.ItemTypeEpic) {
+			return fmt.Errorf("cannot redefine built-in type %q", name)
+		}
+
+		config, err := db.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if config.Types == nil {
+			config.Types = make(map[string]db.TypeConfig)
+		}
+
+		// Update only the fields that were given when the type already exists
+		tc, exists := config.Types[name]
+		if !exists || cmd.Flags().Changed("prefix") {
+			tc.Prefix = flagTypesPrefix
+		}
+		if !exists || cmd.Flags().Changed("priority") {
+			tc.DefaultPriority = flagTypesPriority
+		}
+		if !exists || cmd.Flags().Changed("children") {
+			tc.CanHaveChildren = flagTypesChildren
+		}
+		if !exists || cmd.Flags().Changed("icon") {
+			tc.Icon = flagTypesIcon
+		}
+		if !exists || cmd.Flags().Changed("color") {
+			tc.Color = flagTypesColor
+		}
+
+		info := tc.TypeInfo(name)
+		for other, otc := range config.Types {
+			if other != name && otc.TypeInfo(other).Prefix == info.Prefix {
+				return fmt.Errorf("prefix %q is already used by type %q", info.Prefix, other)
+			}
+		}
+		if info.Prefix == config.Prefixes.Task || info.Prefix == config.Prefixes.Epic {
+			return fmt.Errorf("prefix %q is already used by a built-in type", info.Prefix)
+		}
+
+		config.Types[name] = tc
+		if err := db.SaveConfig(config); err != nil {
+			return err
+		}
+		if exists {
+			fmt.Printf("Updated type %s (%s-)\n", name, info.Prefix)
+		} else {
+			fmt.Printf("Added type %s (%s-)\n", name, info.Prefix)
+		}
+		return nil
+	},
+}
+
+var typesRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a custom item type",
+	Long: `Remove a custom item type from the registry.
+
+Existing items keep their type, but new items can no longer use it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := db.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := config.Types[args[0]]; !ok {
+			return fmt.Errorf("type not found: %s", args[0])
+		}
+		delete(config.Types, args[0])
+		if err := db.SaveConfig(config); err != nil {
+			return err
+		}
+		fmt.Printf("Removed type %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	typesAddCmd.Flags().StringVar(&flagTypesPrefix, "prefix", "", "ID prefix (default: first two letters of the name)")
+	typesAddCmd.Flags().VarP(newPriorityValue(&flagTypesPriority, 0), "priority", "p", "Default priority for new items (1-5 or a name, default 2)")
+	typesAddCmd.Flags().BoolVar(&flagTypesChildren, "children", false, "Allow items of this type to have children")
+	typesAddCmd.Flags().StringVar(&flagTypesIcon, "icon", "", "Icon shown in list output and the TUI")
+	typesAddCmd.Flags().StringVar(&flagTypesColor, "color", "", "Color used in the TUI (e.g. #ff0000 or 196)")
+
+	typesCmd.AddCommand(typesListCmd)
+	typesCmd.AddCommand(typesAddCmd)
+	typesCmd.AddCommand(typesRmCmd)
+	rootCmd.AddCommand(typesCmd)
+}
+
+// applyProjectConfig loads the project config, applies the settings the
+// model keeps process-wide (item types, extra status transitions, and stale
+// thresholds), and gives the database its label weights. The process-wide
+// ones are read through model helpers such as ItemType.CanHaveChildren and
+// CheckTransition, which run where no database is open (flag validation,
+// formatting); label weights are only ever read next to a query, so they live
+// on the database. Problems are reported as warnings so that one bad entry
+// does not break every command.
+func applyProjectConfig(database *db.DB) {
+	config, err := db.LoadConfig()
+	if err != nil {
+		config = &db.Config{}
+	}
+	if err := config.RegisterTypes(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if err := config.RegisterTransitions(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	database.SetLabelWeights(config.LabelWeights)
+	thresholds, err := config.StaleThresholds()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	model.SetStaleThresholds(thresholds)
+}
+
+// registerConfiguredTypes registers the project's custom item types, for
+// commands that check or list types without opening the database.
+func registerConfiguredTypes() {
+	config, err := db.LoadConfig()
+	if err != nil {
+		model.ResetItemTypes()
+		return
+	}
+	if err := config.RegisterTypes(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// typeNames returns the names of all known item types.
+func typeNames() []string {
+	var names []string
+	for _, info := range model.ItemTypes() {
+		names = append(names, string(info.Name))
+	}
+	return names
+}
+
+// formatItemType returns the type column text, padded to the column width,
+// prefixed with the type's icon and in the type's color when configured.
+func formatItemType(t model.ItemType) string {
+	info, ok := model.LookupItemType(t)
+	text := string(t)
+	if ok && info.Icon != "" {
+		text = info.Icon + " " + text
+	}
+	return format.Type(info.Color, fmt.Sprintf("%-6s", text))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func resetTypesFlags() {
+	flagTypesPrefix = ""
+	flagTypesPriority = 0
+	flagTypesChildren = false
+	flagTypesIcon = ""
+	flagTypesColor = ""
+	for _, name := range []string{"prefix", "priority", "children", "icon", "color"} {
+		if f := typesAddCmd.Flags().Lookup(name); f != nil {
+			f.Changed = false
+		}
+	}
+}
+
+func TestTypesAdd_RegistersCustomType(t *testing.T) {
+	database := setupAddCommandTest(t)
+	resetTypesFlags()
+	resetAddCmdFlags()
+	t.Cleanup(resetTypesFlags)
+	t.Cleanup(resetAddCmdFlags)
+	t.Cleanup(model.ResetItemTypes)
+
+	flagTypesPrefix = "bg"
+	flagTypesPriority = 1
+	flagTypesIcon = "B"
+	captureOutput(func() {
+		if err := typesAddCmd.RunE(typesAddCmd, []string{"bug"}); err != nil {
+			t.Fatalf("types add failed: %v", err)
+		}
+	})
+
+	config, err := db.LoadConfig()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if tc := config.Types["bug"]; tc.Prefix != "bg" || tc.DefaultPriority != 1 {
+		t.Fatalf("unexpected type config: %+v", tc)
+	}
+
+	flagType = "bug"
+	out := captureOutput(func() {
+		if err := addCmd.RunE(addCmd, []string{"Crash on start"}); err != nil {
+			t.Fatalf("add --type bug failed: %v", err)
+		}
+	})
+	id := strings.TrimSpace(out)
+	if !strings.HasPrefix(id, "bg-") {
+		t.Fatalf("expected bg- prefix, got %q", out)
+	}
+	item, err := database.GetItem(id)
+	if err != nil {
+		t.Fatalf("get item: %v", err)
+	}
+	if item.Type != "bug" || item.Priority != 1 {
+		t.Errorf("expected bug with priority 1, got %s/%d", item.Type, item.Priority)
+	}
+
+	// Bugs cannot have children
+	child := createTestItem(t, database, "ts-child", "Child")
+	if err := database.SetParent(child.ID, id); err == nil {
+		t.Error("expected error parenting under a type without children")
+	}
+}
+
+func TestTypesAdd_RejectsBuiltinAndPrefixClash(t *testing.T) {
+	setupAddCommandTest(t)
+	resetTypesFlags()
+	t.Cleanup(resetTypesFlags)
+	t.Cleanup(model.ResetItemTypes)
+
+	if err := typesAddCmd.RunE(typesAddCmd, []string{"epic"}); err == nil {
+		t.Error("expected error redefining built-in type")
+	}
+
+	flagTypesPrefix = "ts"
+	if err := typesAddCmd.RunE(typesAddCmd, []string{"story"}); err == nil {
+		t.Error("expected error for prefix used by task")
+	}
+}
+
+func TestEdit_TypeValidatedAgainstRegistry(t *testing.T) {
+	database := setupAddCommandTest(t)
+	t.Cleanup(model.ResetItemTypes)
+	item := createTestItem(t, database, "ts-edit", "Edit me")
+
+	flagEditType = "story"
+	t.Cleanup(func() { flagEditType = "" })
+
+	err := editCmd.RunE(editCmd, []string{item.ID})
+	if err == nil || !strings.Contains(err.Error(), "--type must be") {
+		t.Fatalf("expected type validation error, got %v", err)
+	}
+
+	config, _ := db.LoadConfig()
+	config.Types = map[string]db.TypeConfig{"story": {CanHaveChildren: true}}
+	if err := db.SaveConfig(config); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	captureOutput(func() {
+		if err := editCmd.RunE(editCmd, []string{item.ID}); err != nil {
+			t.Fatalf("edit --type story failed: %v", err)
+		}
+	})
+	got, _ := database.GetItem(item.ID)
+	if got.Type != "story" {
+		t.Errorf("expected type story, got %s", got.Type)
+	}
+}
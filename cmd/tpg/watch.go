@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+)
+
+var (
+	flagWatchRemove bool
+	flagInboxAll    bool
+	flagInboxPeek   bool
+	flagInboxJSON   bool
+)
+
+var watchItemCmd = &cobra.Command{
+	Use:   "watch-item <id>...",
+	Short: "Watch items to see their changes in 'tpg inbox'",
+	Long: `Add items to your watch list. 'tpg inbox' then lists the watched items that
+changed since you last checked: status changes, new logs, and other edits.
+
+Watches belong to the agent ($AGENT_ID), or to $TPG_SESSION when set, or
+otherwise to your login, so a person's watches are shared by their shells.
+
+Examples:
+  tpg watch-item ts-a1b2c3
+  tpg watch-item ts-a1b2c3 ep-a1b2c3
+  tpg watch-item --remove ts-a1b2c3`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		watcher := db.CurrentWatcher()
+		for _, arg := range args {
+			id, err := resolveItemArg(database, arg)
+			if err != nil {
+				return err
+			}
+			if flagWatchRemove {
+				removed, err := database.Unwatch(watcher, id)
+				if err != nil {
+					return err
+				}
+				if removed {
+					fmt.Printf("Stopped watching %s\n", id)
+				} else {
+					fmt.Printf("Not watching %s\n", id)
+				}
+				continue
+			}
+			added, err := database.Watch(watcher, id)
+			if err != nil {
+				return err
+			}
+			if added {
+				fmt.Printf("Watching %s\n", id)
+			} else {
+				fmt.Printf("Already watching %s\n", id)
+			}
+		}
+		return nil
+	},
+}
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List watched items that changed since you last checked",
+	Long: `Show the items you watch (see 'tpg watch-item') that changed since the last
+'tpg inbox', with each change: status changes, new logs, and other edits.
+
+Reading the inbox marks those changes as seen. --peek leaves them unread, and
+--all also lists watched items with no changes.
+
+Examples:
+  tpg inbox
+  tpg inbox --peek
+  tpg inbox --all
+  tpg inbox --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		watcher := db.CurrentWatcher()
+		inbox, err := database.Inbox(watcher, flagInboxAll)
+		if err != nil {
+			return err
+		}
+		if flagInboxJSON {
+			err = printInboxJSON(inbox.Entries)
+		} else {
+			printInbox(inbox.Entries)
+		}
+		if err != nil {
+			return err
+		}
+		if !flagInboxPeek {
+			return database.MarkInboxChecked(watcher, inbox)
+		}
+		return nil
+	},
+}
+
+func printInbox(entries []db.InboxEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No changes to watched items")
+		return
+	}
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s [%s] %s\n", e.Item.ID, e.Item.Status, e.Item.Title)
+		if len(e.Changes) == 0 {
+			fmt.Println("  (no changes)")
+		}
+		for _, c := range e.Changes {
+			fmt.Printf("  %-9s %-16s %s\n", formatTimeAgo(c.At), c.Event, describeWatchChange(c))
+		}
+	}
+}
+
+// describeWatchChange summarizes a change on one line.
+func describeWatchChange(c db.WatchChange) string {
+	if c.Log != nil {
+		msg, _, more := strings.Cut(strings.TrimSpace(c.Log.Message), "\n")
+		if more {
+			msg += " ..."
+		}
+		return msg
+	}
+	return formatChanges(c.Changes)
+}
+
+// InboxItemJSON is one item in the JSON output of 'tpg inbox'.
+type InboxItemJSON struct {
+	ID      string            `json:"id"`
+	Title   string            `json:"title"`
+	Status  string            `json:"status"`
+	Changes []InboxChangeJSON `json:"changes"`
+}
+
+// InboxChangeJSON is one change to a watched item.
+type InboxChangeJSON struct {
+	At      time.Time      `json:"at"`
+	Event   string         `json:"event"`
+	Changes map[string]any `json:"changes,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+func printInboxJSON(entries []db.InboxEntry) error {
+	out := make([]InboxItemJSON, 0, len(entries))
+	for _, e := range entries {
+		item := InboxItemJSON{ID: e.Item.ID, Title: e.Item.Title, Status: string(e.Item.Status), Changes: []InboxChangeJSON{}}
+		for _, c := range e.Changes {
+			change := InboxChangeJSON{At: c.At, Event: c.Event, Changes: c.Changes}
+			if c.Log != nil {
+				change.Message = c.Log.Message
+			}
+			item.Changes = append(item.Changes, change)
+		}
+		out = append(out, item)
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func init() {
+	watchItemCmd.Flags().BoolVar(&flagWatchRemove, "remove", false, "Stop watching the items")
+	inboxCmd.Flags().BoolVar(&flagInboxAll, "all", false, "Also list watched items with no changes")
+	inboxCmd.Flags().BoolVar(&flagInboxPeek, "peek", false, "Don't mark the changes as seen")
+	inboxCmd.Flags().BoolVar(&flagInboxJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(watchItemCmd)
+	rootCmd.AddCommand(inboxCmd)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/worktree"
+)
+
+var flagWorktreeStatusJSON bool
+
+var worktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Inspect and clean up epic worktrees",
+	Long: `Commands that compare epic worktree metadata with the actual git state
+and remove worktrees left behind by closed epics.
+
+Use 'tpg epic worktree <id>' to attach worktree metadata to an epic.`,
+}
+
+var worktreeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show every worktree epic alongside its git state",
+	Long: `List all epics with worktree metadata together with the state of their
+branch and worktree: whether the branch and worktree exist, the worktree
+path, uncommitted changes, and how far the branch is ahead of or behind its
+base.
+
+Inconsistencies are flagged, such as a branch that was deleted while the
+epic still references it, or a closed epic whose worktree was never removed.
+Open epics whose base has advanced more than worktree.drift_threshold commits
+(default 20) get a rebase suggestion.
+
+Examples:
+  tpg worktree status
+  tpg worktree status --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		ctx, worktrees := detectWorktreeState()
+		if ctx == nil || ctx.RepoRoot == "" {
+			return fmt.Errorf("not in a git repository")
+		}
+
+		epics, err := database.WorktreeEpics(project)
+		if err != nil {
+			return err
+		}
+
+		config, _ := db.LoadConfig()
+		limit := driftLimit(config)
+
+		entries := make([]worktreeStatusEntry, len(epics))
+		for i, epic := range epics {
+			st := worktree.Inspect(ctx.RepoRoot, epic.WorktreeBranch, epic.WorktreeBase, worktrees)
+			st.Path = displayWorktreePath(ctx.RepoRoot, st.Path)
+			entries[i] = worktreeStatusEntry{
+				Epic:   epic,
+				Status: st,
+				Issues: worktreeIssues(epic, st, limit),
+			}
+		}
+
+		if flagWorktreeStatusJSON {
+			return printWorktreeStatusJSON(entries)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No epics with worktree metadata")
+			return nil
+		}
+		printWorktreeStatus(entries)
+		return nil
+	},
+}
+
+// worktreeStatusEntry pairs a worktree epic with its git state.
+type worktreeStatusEntry struct {
+	Epic   model.Item
+	Status worktree.BranchStatus
+	Issues []string
+}
+
+// worktreeIssues returns the inconsistencies between an epic's worktree
+// metadata and the git state.
+// A limit above zero also flags branches that have drifted behind their base.
+func worktreeIssues(epic model.Item, st worktree.BranchStatus, limit int) []string {
+	closed := epic.Status == model.StatusDone || epic.Status == model.StatusCanceled
+	var issues []string
+
+	if !st.BranchExists && !closed {
+		issues = append(issues, fmt.Sprintf("branch %s does not exist (not created yet, or deleted while the epic still references it)", st.Branch))
+	}
+	if st.Path != "" && !st.PathExists {
+		issues = append(issues, fmt.Sprintf("worktree %s is registered but missing on disk (run 'git worktree prune')", st.Path))
+	}
+	if closed && (st.PathExists || st.BranchExists) {
+		issues = append(issues, fmt.Sprintf("epic is %s but its branch or worktree remains (run 'tpg worktree gc')", epic.Status))
+	}
+	if st.Base != "" && !st.BaseExists {
+		issues = append(issues, fmt.Sprintf("base %s not found", st.Base))
+	}
+	if drift := driftIssue(st, limit); drift != "" && !closed {
+		issues = append(issues, drift)
+	}
+	return issues
+}
+
+func printWorktreeStatus(entries []worktreeStatusEntry) {
+	problems := 0
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Println()
+		}
+		st := e.Status
+		fmt.Printf("%s [%s] %s\n", e.Epic.ID, e.Epic.Status, e.Epic.Title)
+
+		branch := "missing"
+		if st.BranchExists {
+			branch = "exists"
+		}
+		fmt.Printf("  Branch:   %s (%s, base %s)\n", st.Branch, branch, st.Base)
+
+		switch {
+		case st.Path == "":
+			fmt.Println("  Worktree: none")
+		case !st.PathExists:
+			fmt.Printf("  Worktree: %s (missing)\n", st.Path)
+		case st.Dirty:
+			fmt.Printf("  Worktree: %s (uncommitted changes)\n", st.Path)
+		default:
+			fmt.Printf("  Worktree: %s (clean)\n", st.Path)
+		}
+
+		if st.BranchExists && st.BaseExists {
+			fmt.Printf("  Commits:  %d ahead, %d behind %s\n", st.Ahead, st.Behind, st.Base)
+		}
+		for _, issue := range e.Issues {
+			fmt.Printf("  ⚠ %s\n", issue)
+		}
+		problems += len(e.Issues)
+	}
+
+	fmt.Printf("\n%d worktree epic(s), %d issue(s)\n", len(entries), problems)
+}
+
+// worktreeStatusJSON is the JSON form of a worktree status entry.
+type worktreeStatusJSON struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Status       string   `json:"status"`
+	Branch       string   `json:"branch"`
+	Base         string   `json:"base"`
+	BranchExists bool     `json:"branch_exists"`
+	BaseExists   bool     `json:"base_exists"`
+	Path         string   `json:"path,omitempty"`
+	PathExists   bool     `json:"path_exists"`
+	Dirty        bool     `json:"dirty"`
+	Ahead        int      `json:"ahead"`
+	Behind       int      `json:"behind"`
+	Issues       []string `json:"issues,omitempty"`
+}
+
+func printWorktreeStatusJSON(entries []worktreeStatusEntry) error {
+	out := make([]worktreeStatusJSON, len(entries))
+	for i, e := range entries {
+		st := e.Status
+		out[i] = worktreeStatusJSON{
+			ID:           e.Epic.ID,
+			Title:        e.Epic.Title,
+			Status:       string(e.Epic.Status),
+			Branch:       st.Branch,
+			Base:         st.Base,
+			BranchExists: st.BranchExists,
+			BaseExists:   st.BaseExists,
+			Path:         st.Path,
+			PathExists:   st.PathExists,
+			Dirty:        st.Dirty,
+			Ahead:        st.Ahead,
+			Behind:       st.Behind,
+			Issues:       e.Issues,
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func init() {
+	worktreeStatusCmd.Flags().BoolVar(&flagWorktreeStatusJSON, "json", false, "Output as JSON")
+
+	worktreeCmd.AddCommand(worktreeStatusCmd)
+	rootCmd.AddCommand(worktreeCmd)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/worktree"
+)
+
+// driftLimit returns the configured drift threshold, or 0 when disabled.
+func driftLimit(config *db.Config) int {
+	if config == nil {
+		return db.DefaultDriftThreshold
+	}
+	return config.Worktree.DriftLimit()
+}
+
+// driftIssue returns a warning when the base has gained more than limit
+// commits the branch doesn't have, or "" when within the limit.
+func driftIssue(st worktree.BranchStatus, limit int) string {
+	if limit <= 0 || !st.BranchExists || !st.BaseExists || st.Behind <= limit {
+		return ""
+	}
+	rebase := fmt.Sprintf("git rebase %s %s", st.Base, st.Branch)
+	if st.PathExists {
+		rebase = fmt.Sprintf("git -C %s rebase %s", st.Path, st.Base)
+	}
+	return fmt.Sprintf("base %s has advanced %d commits past %s (limit %d); rebase with: %s",
+		st.Base, st.Behind, st.Branch, limit, rebase)
+}
+
+// epicDriftWarning checks a worktree epic's branch against its base in the
+// current repository. It returns "" when there is nothing to report or the
+// state can't be determined.
+func epicDriftWarning(epic *model.Item, config *db.Config) string {
+	if epic == nil || epic.WorktreeBranch == "" {
+		return ""
+	}
+	limit := driftLimit(config)
+	if limit <= 0 {
+		return ""
+	}
+	ctx, worktrees := detectWorktreeState()
+	if ctx == nil || ctx.RepoRoot == "" {
+		return ""
+	}
+	base := epic.WorktreeBase
+	if base == "" {
+		base = "main"
+	}
+	st := worktree.Inspect(ctx.RepoRoot, epic.WorktreeBranch, base, worktrees)
+	if st.PathExists {
+		st.Path = displayWorktreePath(ctx.RepoRoot, st.Path)
+	}
+	return driftIssue(st, limit)
+}
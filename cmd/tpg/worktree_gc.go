@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/worktree"
+)
+
+var (
+	flagWorktreeGCDryRun bool
+	flagWorktreeGCForce  bool
+)
+
+var worktreeGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove worktrees and branches left behind by closed epics",
+	Long: `Find done or canceled epics whose worktree or branch still exists and
+offer to remove them with 'git worktree remove' and 'git branch -d'.
+
+Each epic is confirmed separately (--yes answers yes to all). git refuses to
+remove a worktree with uncommitted changes or to delete an unmerged branch;
+--force overrides both. Use --dry-run to only print the commands.
+
+Examples:
+  tpg worktree gc --dry-run
+  tpg worktree gc
+  tpg worktree gc --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := openDB()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = database.Close() }()
+
+		project, err := resolveProject()
+		if err != nil {
+			return err
+		}
+
+		ctx, worktrees := detectWorktreeState()
+		if ctx == nil || ctx.RepoRoot == "" {
+			return fmt.Errorf("not in a git repository")
+		}
+		cwd, _ := os.Getwd()
+
+		epics, err := database.WorktreeEpics(project)
+		if err != nil {
+			return err
+		}
+
+		var candidates []worktreeStatusEntry
+		for _, epic := range epics {
+			if epic.Status != model.StatusDone && epic.Status != model.StatusCanceled {
+				continue
+			}
+			st := worktree.Inspect(ctx.RepoRoot, epic.WorktreeBranch, epic.WorktreeBase, worktrees)
+			if st.Path == "" && !st.BranchExists {
+				continue
+			}
+			candidates = append(candidates, worktreeStatusEntry{Epic: epic, Status: st})
+		}
+		if len(candidates) == 0 {
+			fmt.Println("No leftover worktrees or branches from closed epics")
+			return nil
+		}
+
+		removed := 0
+		for _, c := range candidates {
+			st := c.Status
+			fmt.Printf("%s [%s] %s\n", c.Epic.ID, c.Epic.Status, c.Epic.Title)
+			for _, step := range worktreeGCSteps(st, ctx.RepoRoot, flagWorktreeGCForce) {
+				fmt.Printf("  %s\n", step)
+			}
+			if flagWorktreeGCDryRun {
+				continue
+			}
+			if st.PathExists && worktree.IsWithinDir(cwd, st.Path) {
+				fmt.Println("  skipped: the current directory is inside this worktree")
+				continue
+			}
+			if st.Dirty && !flagWorktreeGCForce {
+				fmt.Println("  skipped: worktree has uncommitted changes (use --force to remove anyway)")
+				continue
+			}
+			if !confirm(fmt.Sprintf("Remove leftovers of %s?", c.Epic.ID)) {
+				continue
+			}
+			if err := removeEpicWorktree(ctx.RepoRoot, st, flagWorktreeGCForce); err != nil {
+				fmt.Printf("  failed: %v\n", err)
+				continue
+			}
+			removed++
+		}
+
+		if flagWorktreeGCDryRun {
+			fmt.Printf("\nDry run: %d closed epic(s) with leftovers, nothing removed\n", len(candidates))
+		} else {
+			fmt.Printf("\nCleaned up %d of %d closed epic(s)\n", removed, len(candidates))
+		}
+		return nil
+	},
+}
+
+// worktreeGCSteps returns the git commands gc runs for an epic's leftovers.
+func worktreeGCSteps(st worktree.BranchStatus, repoRoot string, force bool) []string {
+	var steps []string
+	switch {
+	case st.Path != "" && !st.PathExists:
+		steps = append(steps, "git worktree prune")
+	case st.Path != "":
+		cmd := "git worktree remove " + displayWorktreePath(repoRoot, st.Path)
+		if force {
+			cmd += " --force"
+		}
+		steps = append(steps, cmd)
+	}
+	if st.BranchExists {
+		flag := "-d"
+		if force {
+			flag = "-D"
+		}
+		steps = append(steps, fmt.Sprintf("git branch %s %s", flag, st.Branch))
+	}
+	return steps
+}
+
+// removeEpicWorktree removes an epic's worktree (or prunes its stale record)
+// and then deletes its branch.
+func removeEpicWorktree(repoRoot string, st worktree.BranchStatus, force bool) error {
+	var errs []string
+	switch {
+	case st.Path != "" && !st.PathExists:
+		if err := worktree.Prune(repoRoot); err != nil {
+			errs = append(errs, err.Error())
+		}
+	case st.Path != "":
+		if err := worktree.RemoveWorktree(repoRoot, st.Path, force); err != nil {
+			// The branch can't be deleted while it is checked out
+			return err
+		}
+	}
+	if st.BranchExists {
+		if err := worktree.DeleteBranch(repoRoot, st.Branch, force); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func init() {
+	worktreeGCCmd.Flags().BoolVar(&flagWorktreeGCDryRun, "dry-run", false, "Print what would be removed without removing it")
+	worktreeGCCmd.Flags().BoolVar(&flagWorktreeGCForce, "force", false, "Remove dirty worktrees and unmerged branches")
+
+	worktreeCmd.AddCommand(worktreeGCCmd)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/worktree"
+)
+
+func TestWorktreeIssues(t *testing.T) {
+	healthy := worktree.BranchStatus{
+		Branch: "feature/ep-a", Base: "main",
+		BranchExists: true, BaseExists: true,
+		Path: "/repo/.worktrees/ep-a", PathExists: true,
+	}
+
+	tests := []struct {
+		name   string
+		status model.Status
+		merge  string
+		edit   func(*worktree.BranchStatus)
+		want   []string // substrings, one per expected issue
+	}{
+		{"healthy open epic", model.StatusOpen, "", func(*worktree.BranchStatus) {}, nil},
+		{"no worktree is not an issue", model.StatusOpen, "", func(st *worktree.BranchStatus) {
+			st.Path, st.PathExists = "", false
+		}, nil},
+		{"branch deleted under open epic", model.StatusOpen, "", func(st *worktree.BranchStatus) {
+			st.BranchExists, st.Path, st.PathExists = false, "", false
+		}, []string{"does not exist"}},
+		{"worktree missing on disk", model.StatusInProgress, "", func(st *worktree.BranchStatus) {
+			st.PathExists = false
+		}, []string{"missing on disk"}},
+		{"closed epic with leftovers", model.StatusDone, "merged", func(*worktree.BranchStatus) {}, []string{"remains"}},
+		{"merged and cleaned up", model.StatusDone, "merged", func(st *worktree.BranchStatus) {
+			st.BranchExists, st.Path, st.PathExists = false, "", false
+		}, nil},
+		{"closed and cleaned up without merging", model.StatusCanceled, "", func(st *worktree.BranchStatus) {
+			st.BranchExists, st.Path, st.PathExists = false, "", false
+		}, nil},
+		{"base missing", model.StatusOpen, "", func(st *worktree.BranchStatus) {
+			st.BaseExists = false
+		}, []string{"base main not found"}},
+	}
+
+	for _, tt := range tests {
+		st := healthy
+		tt.edit(&st)
+		epic := model.Item{ID: "ep-a", Type: model.ItemTypeEpic, Status: tt.status, MergeStatus: tt.merge}
+		got := worktreeIssues(epic, st, 0)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got issues %q, want %d", tt.name, got, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if !strings.Contains(got[i], want) {
+				t.Errorf("%s: issue %q does not mention %q", tt.name, got[i], want)
+			}
+		}
+	}
+}
+
+func TestWorktreeGCSteps(t *testing.T) {
+	st := worktree.BranchStatus{
+		Branch: "feature/ep-a", BranchExists: true,
+		Path: "/repo/.worktrees/ep-a", PathExists: true,
+	}
+	got := strings.Join(worktreeGCSteps(st, "/repo", false), "; ")
+	if want := "git worktree remove .worktrees/ep-a; git branch -d feature/ep-a"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = strings.Join(worktreeGCSteps(st, "/repo", true), "; ")
+	if want := "git worktree remove .worktrees/ep-a --force; git branch -D feature/ep-a"; got != want {
+		t.Errorf("forced: got %q, want %q", got, want)
+	}
+
+	st.PathExists, st.BranchExists = false, false
+	got = strings.Join(worktreeGCSteps(st, "/repo", false), "; ")
+	if want := "git worktree prune"; got != want {
+		t.Errorf("missing worktree: got %q, want %q", got, want)
+	}
+}
+
+func TestWorktreeIssues_Drift(t *testing.T) {
+	st := worktree.BranchStatus{
+		Branch: "feature/ep-a", Base: "main",
+		BranchExists: true, BaseExists: true,
+		Path: ".worktrees/ep-a", PathExists: true,
+		Behind: 25,
+	}
+	epic := model.Item{ID: "ep-a", Type: model.ItemTypeEpic, Status: model.StatusOpen}
+
+	issues := worktreeIssues(epic, st, 20)
+	if len(issues) != 1 || !strings.Contains(issues[0], "git -C .worktrees/ep-a rebase main") {
+		t.Errorf("expected a rebase suggestion, got %q", issues)
+	}
+	if issues := worktreeIssues(epic, st, 30); len(issues) != 0 {
+		t.Errorf("expected no issue within the limit, got %q", issues)
+	}
+	if issues := worktreeIssues(epic, st, 0); len(issues) != 0 {
+		t.Errorf("expected no issue with drift checks disabled, got %q", issues)
+	}
+
+	st.Path, st.PathExists = "", false
+	if got := driftIssue(st, 20); !strings.Contains(got, "git rebase main feature/ep-a") {
+		t.Errorf("expected a branch rebase without a worktree, got %q", got)
+	}
+}
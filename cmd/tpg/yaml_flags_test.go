@@ -125,6 +125,47 @@ func TestSetFlagFromYAML_Bool(t *testing.T) {
 	}
 }
 
+func TestSetFlagFromYAML_Priority(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    int
+		wantErr bool
+	}{
+		{"int value", 1, 1, false},
+		{"float64 value", float64(4), 4, false},
+		{"name", "high", 1, false},
+		{"number string", "3", 3, false},
+		{"out of range", 9, 0, true},
+		{"unknown name", "soon", 0, true},
+		{"bool value", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			var priority int
+			fs.Var(newPriorityValue(&priority, 2), "priority", "test")
+			flag := fs.Lookup("priority")
+
+			err := setFlagFromYAML(flag, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if priority != tt.want {
+				t.Errorf("got %d, want %d", priority, tt.want)
+			}
+		})
+	}
+}
+
 func TestSetFlagFromYAML_StringArray(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -2,8 +2,11 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	"os"
 	"time"
+
+	"github.com/taxilian/tpg/internal/model"
 )
 
 // AgentContext holds current agent information from environment variables
@@ -82,3 +85,124 @@ func (db *DB) CleanupOldAgentSessions() error {
 	`)
 	return err
 }
+
+// Agent is registered metadata for an agent ID, so that history and status
+// can show a readable name instead of the raw $AGENT_ID.
+type Agent struct {
+	ID          string
+	Name        string
+	Type        string // e.g. "opencode", "claude"
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// DisplayName returns the agent's name, or its ID if it has no name.
+func (a Agent) DisplayName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.ID
+}
+
+// RegisterAgent creates or updates the metadata for an agent ID.
+func (db *DB) RegisterAgent(a *Agent) error {
+	if a.ID == "" {
+		return fmt.Errorf("agent id is required")
+	}
+	now := sqlTime(time.Now())
+	_, err := db.Exec(`
+		INSERT INTO agents (id, name, type, description, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id)
+		DO UPDATE SET name = excluded.name, type = excluded.type,
+		              description = excluded.description, updated_at = excluded.updated_at
+	`, a.ID, a.Name, a.Type, a.Description, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	return nil
+}
+
+// GetAgent returns the registered metadata for an agent ID.
+func (db *DB) GetAgent(id string) (*Agent, error) {
+	a := &Agent{}
+	err := db.QueryRow(`
+		SELECT id, COALESCE(name, ''), COALESCE(type, ''), COALESCE(description, ''), created_at, updated_at
+		FROM agents WHERE id = ?`, id).Scan(&a.ID, &a.Name, &a.Type, &a.Description, &a.CreatedAt, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("agent not registered: %s (use 'tpg agent register %s')", id, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent: %w", err)
+	}
+	return a, nil
+}
+
+// ListAgents returns all registered agents ordered by ID.
+func (db *DB) ListAgents() ([]Agent, error) {
+	rows, err := db.Query(`
+		SELECT id, COALESCE(name, ''), COALESCE(type, ''), COALESCE(description, ''), created_at, updated_at
+		FROM agents ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []Agent
+	for rows.Next() {
+		var a Agent
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Description, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		agents = append(agents, a)
+	}
+	return agents, rows.Err()
+}
+
+// RemoveAgent deletes the registered metadata for an agent ID.
+func (db *DB) RemoveAgent(id string) error {
+	result, err := db.Exec(`DELETE FROM agents WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to remove agent: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("agent not registered: %s", id)
+	}
+	return nil
+}
+
+// AgentNames maps registered agent IDs to their display names. Errors are
+// treated as "no names" so that display code never fails because of them.
+func (db *DB) AgentNames() map[string]string {
+	names := make(map[string]string)
+	agents, err := db.ListAgents()
+	if err != nil {
+		return names
+	}
+	for _, a := range agents {
+		names[a.ID] = a.DisplayName()
+	}
+	return names
+}
+
+// Heartbeat records that an agent is still alive: every in-progress item it
+// has claimed gets a fresh agent_last_active timestamp, and the agent's
+// project access is updated. Returns the number of items touched.
+func (db *DB) Heartbeat(agentID, project string) (int, error) {
+	if agentID == "" {
+		return 0, fmt.Errorf("agent id is required for a heartbeat")
+	}
+	result, err := db.Exec(`
+		UPDATE items SET agent_last_active = ?
+		WHERE agent_id = ? AND status = ?`,
+		sqlTime(time.Now()), agentID, model.StatusInProgress)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	if err := db.RecordAgentProjectAccess(agentID, project); err != nil {
+		return 0, fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
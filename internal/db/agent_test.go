@@ -465,3 +465,83 @@ func TestRecordAgentProjectAccess_MultipleProjects(t *testing.T) {
 		t.Errorf("lastProject = %q, want %q", lastProject, "project3")
 	}
 }
+
+func TestRegisterAgent(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := db.RegisterAgent(&Agent{ID: "ses_123", Name: "reviewer", Type: "opencode"}); err != nil {
+		t.Fatalf("RegisterAgent failed: %v", err)
+	}
+	// Registering again updates the metadata
+	if err := db.RegisterAgent(&Agent{ID: "ses_123", Name: "senior reviewer", Type: "opencode", Description: "Reviews PRs"}); err != nil {
+		t.Fatalf("RegisterAgent update failed: %v", err)
+	}
+
+	agent, err := db.GetAgent("ses_123")
+	if err != nil {
+		t.Fatalf("GetAgent failed: %v", err)
+	}
+	if agent.Name != "senior reviewer" || agent.Description != "Reviews PRs" {
+		t.Errorf("unexpected agent: %+v", agent)
+	}
+
+	names := db.AgentNames()
+	if names["ses_123"] != "senior reviewer" {
+		t.Errorf("AgentNames()[ses_123] = %q, want %q", names["ses_123"], "senior reviewer")
+	}
+
+	if err := db.RemoveAgent("ses_123"); err != nil {
+		t.Fatalf("RemoveAgent failed: %v", err)
+	}
+	if _, err := db.GetAgent("ses_123"); err == nil {
+		t.Error("expected error after removing agent")
+	}
+	if err := db.RemoveAgent("ses_123"); err == nil {
+		t.Error("expected error removing unregistered agent")
+	}
+}
+
+func TestAgentDisplayName(t *testing.T) {
+	if got := (Agent{ID: "ses_1"}).DisplayName(); got != "ses_1" {
+		t.Errorf("DisplayName() without name = %q, want ses_1", got)
+	}
+	if got := (Agent{ID: "ses_1", Name: "bot"}).DisplayName(); got != "bot" {
+		t.Errorf("DisplayName() = %q, want bot", got)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	db := setupTestDB(t)
+
+	mine := createTestItem(t, db, "Mine")
+	other := createTestItem(t, db, "Other")
+	if err := db.UpdateStatus(mine.ID, model.StatusInProgress, AgentContext{ID: "agent-1"}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := db.UpdateStatus(other.ID, model.StatusInProgress, AgentContext{ID: "agent-2"}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	old := "2020-01-01 00:00:00"
+	db.Exec("UPDATE items SET agent_last_active = ?", old)
+
+	n, err := db.Heartbeat("agent-1", "test")
+	if err != nil {
+		t.Fatalf("Heartbeat failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Heartbeat touched %d items, want 1", n)
+	}
+
+	got, _ := db.GetItem(mine.ID)
+	if got.AgentLastActive == nil || time.Since(*got.AgentLastActive) > time.Minute {
+		t.Errorf("expected fresh agent_last_active, got %v", got.AgentLastActive)
+	}
+	got, _ = db.GetItem(other.ID)
+	if got.AgentLastActive == nil || got.AgentLastActive.Year() != 2020 {
+		t.Errorf("other agent's item should be untouched, got %v", got.AgentLastActive)
+	}
+
+	if _, err := db.Heartbeat("", "test"); err == nil {
+		t.Error("expected error for empty agent id")
+	}
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// aliasPattern is the shape of an item alias: lowercase words joined by
+// hyphens, starting with a letter.
+var aliasPattern = regexp.MustCompile(`^[a-z][a-z0-9]*(?:-[a-z0-9]+)*$`)
+
+// maxAliasLength keeps aliases short enough to type.
+const maxAliasLength = 40
+
+// ItemAlias is a human-friendly name for an item, usable in place of its ID.
+type ItemAlias struct {
+	Alias     string
+	ItemID    string
+	CreatedAt time.Time
+}
+
+// ValidateItemAlias checks an alias's shape and that it cannot be mistaken
+// for an item ID, now or later.
+func (db *DB) ValidateItemAlias(alias string) error {
+	if len(alias) > maxAliasLength || !aliasPattern.MatchString(alias) {
+		return fmt.Errorf("invalid alias %q: use lowercase letters, digits, and hyphens, starting with a letter (at most %d characters)", alias, maxAliasLength)
+	}
+	idShaped, err := db.looksLikeItemID(alias)
+	if err != nil {
+		return err
+	}
+	if idShaped {
+		return fmt.Errorf("invalid alias %q: it has the shape of an item ID", alias)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM items WHERE id = ?`, alias).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check alias: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("alias %q is already an item ID", alias)
+	}
+	return nil
+}
+
+// looksLikeItemID reports whether s could be an item ID: a prefix in use,
+// a hyphen, and characters from the ID alphabet. An alias of that shape
+// would hide an item created with that ID later, as aliases resolve first.
+func (db *DB) looksLikeItemID(s string) (bool, error) {
+	cut := strings.LastIndex(s, "-")
+	if cut <= 0 || cut == len(s)-1 {
+		return false, nil
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		return false, err
+	}
+	_, alphabet, err := config.IDFormat()
+	if err != nil {
+		return false, err
+	}
+	// IDs made before id_alphabet changed use the default alphabet
+	if strings.Trim(s[cut+1:], alphabet+model.DefaultIDAlphabet) != "" {
+		return false, nil
+	}
+	prefix := s[:cut]
+	if configuredIDPrefixes(config)[prefix] {
+		return true, nil
+	}
+	// Items may also carry a prefix given with 'tpg add --prefix'. The alias
+	// pattern has no LIKE wildcards, so the prefix can be matched as is.
+	var inUse int
+	err = db.QueryRow(`SELECT COUNT(*) FROM items
+		WHERE id LIKE ? || '-%' AND instr(substr(id, length(?) + 2), '-') = 0`,
+		prefix, prefix).Scan(&inUse)
+	if err != nil {
+		return false, fmt.Errorf("failed to check ID prefixes: %w", err)
+	}
+	return inUse > 0, nil
+}
+
+// configuredIDPrefixes returns the built-in task and epic prefixes and the
+// prefixes the config gives tasks, epics, and custom types.
+func configuredIDPrefixes(config *Config) map[string]bool {
+	prefixes := map[string]bool{
+		DefaultTaskPrefix:    true,
+		DefaultEpicPrefix:    true,
+		config.Prefixes.Task: true,
+		config.Prefixes.Epic: true,
+	}
+	for name, tc := range config.Types {
+		prefixes[tc.TypeInfo(name).Prefix] = true
+	}
+	return prefixes
+}
+
+// SetItemAlias points alias at an item, replacing the item's previous
+// alias. It fails when the alias belongs to a different item.
+func (db *DB) SetItemAlias(alias, itemID string) error {
+	if err := db.ValidateItemAlias(alias); err != nil {
+		return err
+	}
+	if _, err := db.GetItem(itemID); err != nil {
+		return err
+	}
+	current, err := db.ResolveItemAlias(alias)
+	if err != nil {
+		return err
+	}
+	if current != "" && current != itemID {
+		return fmt.Errorf("alias %q already points at %s (remove it first)", alias, current)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM item_aliases WHERE item_id = ?`, itemID); err != nil {
+		return fmt.Errorf("failed to replace alias: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO item_aliases (alias, item_id, created_at) VALUES (?, ?, ?)`,
+		alias, itemID, sqlTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to set alias: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RemoveItemAlias deletes an alias. It reports false if there was none.
+func (db *DB) RemoveItemAlias(alias string) (bool, error) {
+	res, err := db.Exec(`DELETE FROM item_aliases WHERE alias = ?`, alias)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove alias: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// ResolveItemAlias returns the ID of the item alias points at, or "" when
+// there is no such alias.
+func (db *DB) ResolveItemAlias(alias string) (string, error) {
+	var itemID string
+	err := db.QueryRow(`SELECT item_id FROM item_aliases WHERE alias = ?`, alias).Scan(&itemID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve alias: %w", err)
+	}
+	return itemID, nil
+}
+
+// ItemAliases returns every alias, ordered by name.
+func (db *DB) ItemAliases() ([]ItemAlias, error) {
+	rows, err := db.Query(`SELECT alias, item_id, created_at FROM item_aliases ORDER BY alias`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var aliases []ItemAlias
+	for rows.Next() {
+		var a ItemAlias
+		if err := rows.Scan(&a.Alias, &a.ItemID, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alias: %w", err)
+		}
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+// AliasesByItem maps item IDs to their aliases.
+func (db *DB) AliasesByItem() (map[string]string, error) {
+	aliases, err := db.ItemAliases()
+	if err != nil {
+		return nil, err
+	}
+	byItem := make(map[string]string, len(aliases))
+	for _, a := range aliases {
+		byItem[a.ItemID] = a.Alias
+	}
+	return byItem, nil
+}
+
+// PopulateItemAliases fills in the Alias field of items that have one.
+func (db *DB) PopulateItemAliases(items []model.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	byItem, err := db.AliasesByItem()
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Alias = byItem[items[i].ID]
+	}
+	return nil
+}
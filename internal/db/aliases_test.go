@@ -0,0 +1,63 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestItemAliases(t *testing.T) {
+	db := setupTestDB(t)
+	first := createTestItem(t, db, "Login bug")
+	second := createTestItem(t, db, "Signup bug")
+
+	if err := db.SetItemAlias("login-bug", first.ID); err != nil {
+		t.Fatalf("SetItemAlias: %v", err)
+	}
+	if id, err := db.ResolveItemAlias("login-bug"); err != nil || id != first.ID {
+		t.Errorf("ResolveItemAlias = %q, %v; want %s", id, err, first.ID)
+	}
+	if err := db.SetItemAlias("login-bug", second.ID); err == nil {
+		t.Error("expected an error taking another item's alias")
+	}
+	if err := db.SetItemAlias(second.ID, first.ID); err == nil {
+		t.Error("expected an error for an alias equal to an item ID")
+	}
+	// ID-shaped aliases would hide items created later with that ID
+	custom := &model.Item{ID: "fx-a1", Project: "test", Type: model.ItemTypeTask, Title: "Custom prefix", Status: model.StatusOpen, Priority: 2}
+	if err := db.CreateItem(custom); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	for _, bad := range []string{"", "Login", "9lives", "a--b", "trailing-", "ts-abc123", "ep-x9", "fx-zz"} {
+		if err := db.ValidateItemAlias(bad); err == nil {
+			t.Errorf("ValidateItemAlias(%q) = nil, want an error", bad)
+		}
+	}
+
+	// A new alias replaces the item's old one
+	if err := db.SetItemAlias("auth", first.ID); err != nil {
+		t.Fatalf("SetItemAlias: %v", err)
+	}
+	if id, _ := db.ResolveItemAlias("login-bug"); id != "" {
+		t.Errorf("old alias still resolves to %s", id)
+	}
+
+	items := []model.Item{*first, *second}
+	if err := db.PopulateItemAliases(items); err != nil {
+		t.Fatalf("PopulateItemAliases: %v", err)
+	}
+	if items[0].Alias != "auth" || items[1].Alias != "" {
+		t.Errorf("aliases = %q, %q; want auth and none", items[0].Alias, items[1].Alias)
+	}
+
+	// Deleting the item frees the alias
+	if err := db.DeleteItem(first.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if id, _ := db.ResolveItemAlias("auth"); id != "" {
+		t.Errorf("alias of deleted item resolves to %s", id)
+	}
+	if removed, err := db.RemoveItemAlias("auth"); err != nil || removed {
+		t.Errorf("RemoveItemAlias = %v, %v; want nothing removed", removed, err)
+	}
+}
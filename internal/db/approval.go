@@ -0,0 +1,204 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+const (
+	// EventTypeApproved records that a task pending review was approved.
+	EventTypeApproved = "approved"
+	// EventTypeChangesRequested records that a reviewer sent a task pending
+	// review back for more work.
+	EventTypeChangesRequested = "changes_requested"
+)
+
+// CurrentReviewer names whoever is running the command, for review history:
+// $AGENT_ID, or the login name for a person.
+func CurrentReviewer() string {
+	for _, key := range []string{"AGENT_ID", "USER", "USERNAME", "LOGNAME"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return "unknown"
+}
+
+// SetNeedsApproval sets whether finishing an item needs approval: when set,
+// 'tpg done' moves it to pending_review and 'tpg approve' completes it.
+func (db *DB) SetNeedsApproval(id string, needs bool) error {
+	result, err := db.Exec(`UPDATE items SET needs_approval = ?, updated_at = ? WHERE id = ?`,
+		needs, sqlTime(time.Now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to set needs_approval: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("item not found: %s (use 'tpg list' to see available items)", id)
+	}
+	_ = db.RecordHistory(id, EventTypeFieldChanged, map[string]any{"key": "needs_approval", "value": needs})
+	return nil
+}
+
+// NeedsApproval reports whether finishing an item needs approval.
+func (db *DB) NeedsApproval(id string) (bool, error) {
+	var needs bool
+	err := db.QueryRow(`SELECT needs_approval FROM items WHERE id = ?`, id).Scan(&needs)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("item not found: %s (use 'tpg list' to see available items)", id)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get needs_approval: %w", err)
+	}
+	return needs, nil
+}
+
+// SubmitForReview finishes work on an item that needs approval: it records
+// the results, releases the agent, and moves the item to pending_review.
+func (db *DB) SubmitForReview(id, results string) error {
+	var oldStatus model.Status
+	err := db.QueryRow(`SELECT status FROM items WHERE id = ?`, id).Scan(&oldStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("item not found: %s (use 'tpg list' to see available items)", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get item status: %w", err)
+	}
+	if err := model.CheckTransition(oldStatus, model.StatusPendingReview); err != nil {
+		return fmt.Errorf("%s: %w", id, err)
+	}
+
+	_, err = db.Exec(`
+		UPDATE items SET status = ?, results = ?, agent_id = NULL, agent_last_active = NULL, updated_at = ?
+		WHERE id = ?`,
+		model.StatusPendingReview, results, sqlTime(time.Now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to submit for review: %w", err)
+	}
+	_ = db.RecordHistory(id, EventTypeStatusChanged, map[string]any{
+		"old": string(oldStatus),
+		"new": string(model.StatusPendingReview),
+	})
+	return nil
+}
+
+// ApproveItem completes an item pending review with the results it was
+// submitted with, and cascades like any other completion. The reviewer is
+// recorded in history once the completion has gone through.
+func (db *DB) ApproveItem(id, reviewer string, agentCtx AgentContext) (*CascadeResult, error) {
+	results, err := db.pendingReviewResults(id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := db.CloseAndCascade(id, model.StatusDone, results, agentCtx, true)
+	if err != nil {
+		return nil, err
+	}
+	_ = db.RecordHistory(id, EventTypeApproved, map[string]any{"reviewer": reviewer})
+	return result, nil
+}
+
+// RequestChanges sends an item pending review back to open, with the
+// reviewer's notes appended to its description under a "Review feedback"
+// heading. The reviewer and notes are recorded in history.
+func (db *DB) RequestChanges(id, reviewer, notes string) error {
+	if _, err := db.pendingReviewResults(id); err != nil {
+		return err
+	}
+	feedback := fmt.Sprintf("## Review feedback (%s, %s)\n\n%s", reviewer, time.Now().Format("2006-01-02"), notes)
+	if err := db.AppendDescription(id, feedback); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`UPDATE items SET status = ?, updated_at = ? WHERE id = ?`,
+		model.StatusOpen, sqlTime(time.Now()), id); err != nil {
+		return fmt.Errorf("failed to reopen %s: %w", id, err)
+	}
+	_ = db.RecordHistory(id, EventTypeChangesRequested, map[string]any{
+		"old":      string(model.StatusPendingReview),
+		"new":      string(model.StatusOpen),
+		"reviewer": reviewer,
+		"notes":    notes,
+	})
+	return nil
+}
+
+// pendingReviewResults returns the results an item was submitted for review
+// with, or an error when it isn't pending review.
+func (db *DB) pendingReviewResults(id string) (string, error) {
+	var status model.Status
+	var results sql.NullString
+	err := db.QueryRow(`SELECT status, results FROM items WHERE id = ?`, id).Scan(&status, &results)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("item not found: %s (use 'tpg list' to see available items)", id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get item: %w", err)
+	}
+	if status != model.StatusPendingReview {
+		return "", fmt.Errorf("%s is not pending review (status: %s)", id, status)
+	}
+	return results.String, nil
+}
+
+// ReviewRequest is an item waiting in the review queue.
+type ReviewRequest struct {
+	Item        model.Item
+	SubmittedBy string // Agent that ran 'tpg done', if known
+	SubmittedAt time.Time
+}
+
+// ReviewQueue returns the items pending review in a project (all projects
+// when empty), longest-waiting first.
+func (db *DB) ReviewQueue(project string) ([]ReviewRequest, error) {
+	query := fmt.Sprintf("SELECT %s FROM items WHERE status = ?", itemSelectColumns)
+	args := []any{model.StatusPendingReview}
+	if project != "" {
+		query += " AND project = ?"
+		args = append(args, project)
+	}
+	query += " ORDER BY updated_at, id"
+	items, err := db.queryItems(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make([]ReviewRequest, 0, len(items))
+	for _, item := range items {
+		req := ReviewRequest{Item: item, SubmittedAt: item.UpdatedAt}
+		entries, err := db.GetHistory(HistoryQueryOptions{ItemID: item.ID, EventTypes: []string{EventTypeStatusChanged}, Limit: -1})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Changes["new"] == string(model.StatusPendingReview) {
+				req.SubmittedBy = e.ActorID
+				req.SubmittedAt = e.CreatedAt
+				break
+			}
+		}
+		queue = append(queue, req)
+	}
+	sort.SliceStable(queue, func(i, j int) bool { return queue[i].SubmittedAt.Before(queue[j].SubmittedAt) })
+	return queue, nil
+}
+
+// checkApprovalGate refuses to complete an item that needs approval or is
+// pending review; those are completed by ApproveItem.
+func (db *DB) checkApprovalGate(id string) error {
+	var status model.Status
+	var needs bool
+	if err := db.QueryRow(`SELECT status, needs_approval FROM items WHERE id = ?`, id).Scan(&status, &needs); err != nil {
+		return err
+	}
+	if status == model.StatusPendingReview {
+		return fmt.Errorf("%s is pending review: complete it with 'tpg approve %s'", id, id)
+	}
+	if needs {
+		return fmt.Errorf("%s needs approval: 'tpg done' submits it for review and 'tpg approve %s' completes it", id, id)
+	}
+	return nil
+}
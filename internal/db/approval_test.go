@@ -0,0 +1,124 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestApprovalGate(t *testing.T) {
+	db := setupTestDB(t)
+
+	createCancelTestItem(t, db, "ep-gate", model.ItemTypeEpic, "", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-gated", model.ItemTypeTask, "ep-gate", model.StatusInProgress)
+	if err := db.SetNeedsApproval("ts-gated", true); err != nil {
+		t.Fatalf("SetNeedsApproval: %v", err)
+	}
+
+	if err := db.CompleteItem("ts-gated", "done", AgentContext{}); err == nil || !strings.Contains(err.Error(), "needs approval") {
+		t.Fatalf("CompleteItem on a gated task = %v, want a needs-approval error", err)
+	}
+
+	if err := db.SubmitForReview("ts-gated", "Built it"); err != nil {
+		t.Fatalf("SubmitForReview: %v", err)
+	}
+	item, err := db.GetItem("ts-gated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Status != model.StatusPendingReview || item.Results != "Built it" {
+		t.Errorf("after submit: status %s, results %q; want pending_review with the results", item.Status, item.Results)
+	}
+	if err := db.UpdateStatus("ts-gated", model.StatusDone, AgentContext{}, false); err == nil {
+		t.Error("UpdateStatus to done from pending_review should fail without force")
+	}
+
+	result, err := db.ApproveItem("ts-gated", "reviewer-1", AgentContext{ID: "reviewer-1"})
+	if err != nil {
+		t.Fatalf("ApproveItem: %v", err)
+	}
+	if item, _ = db.GetItem("ts-gated"); item.Status != model.StatusDone || item.Results != "Built it" {
+		t.Errorf("after approve: status %s, results %q; want done with the submitted results", item.Status, item.Results)
+	}
+	if len(result.CompletedEpics) != 1 || result.CompletedEpics[0] != "ep-gate" {
+		t.Errorf("completed epics = %v, want [ep-gate]", result.CompletedEpics)
+	}
+
+	entries, err := db.GetHistory(HistoryQueryOptions{ItemID: "ts-gated", EventTypes: []string{EventTypeApproved}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Changes["reviewer"] != "reviewer-1" {
+		t.Errorf("approved history = %+v, want one entry naming reviewer-1", entries)
+	}
+
+	if _, err := db.ApproveItem("ts-gated", "reviewer-1", AgentContext{}); err == nil {
+		t.Error("approving a done task should fail")
+	}
+
+	// An approval that can't complete the item must not be recorded.
+	createCancelTestItem(t, db, "ep-review", model.ItemTypeEpic, "", model.StatusPendingReview)
+	if _, err := db.ApproveItem("ep-review", "reviewer-1", AgentContext{}); err == nil {
+		t.Fatal("approving an epic should fail: epics auto-complete")
+	}
+	entries, err = db.GetHistory(HistoryQueryOptions{ItemID: "ep-review", EventTypes: []string{EventTypeApproved}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("approved history after a failed approval = %+v, want none", entries)
+	}
+}
+
+func TestRequestChangesAndReviewQueue(t *testing.T) {
+	db := setupTestDB(t)
+
+	createCancelTestItem(t, db, "ts-first", model.ItemTypeTask, "", model.StatusInProgress)
+	createCancelTestItem(t, db, "ts-second", model.ItemTypeTask, "", model.StatusInProgress)
+	t.Setenv("AGENT_ID", "executor-1")
+	for _, id := range []string{"ts-first", "ts-second"} {
+		if err := db.SetNeedsApproval(id, true); err != nil {
+			t.Fatalf("SetNeedsApproval: %v", err)
+		}
+		if err := db.SubmitForReview(id, "Built it"); err != nil {
+			t.Fatalf("SubmitForReview(%s): %v", id, err)
+		}
+	}
+
+	queue, err := db.ReviewQueue("")
+	if err != nil {
+		t.Fatalf("ReviewQueue: %v", err)
+	}
+	if len(queue) != 2 || queue[0].Item.ID != "ts-first" || queue[0].SubmittedBy != "executor-1" {
+		t.Fatalf("queue = %+v, want ts-first then ts-second, submitted by executor-1", queue)
+	}
+
+	if err := db.RequestChanges("ts-first", "reviewer-1", "Handle the empty case"); err != nil {
+		t.Fatalf("RequestChanges: %v", err)
+	}
+	item, err := db.GetItem("ts-first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Status != model.StatusOpen {
+		t.Errorf("status after request changes = %s, want open", item.Status)
+	}
+	if !strings.Contains(item.Description, "## Review feedback (reviewer-1, ") || !strings.HasSuffix(item.Description, "Handle the empty case") {
+		t.Errorf("description missing the feedback:\n%s", item.Description)
+	}
+	entries, err := db.GetHistory(HistoryQueryOptions{ItemID: "ts-first", EventTypes: []string{EventTypeChangesRequested}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Changes["reviewer"] != "reviewer-1" || entries[0].Changes["notes"] != "Handle the empty case" {
+		t.Errorf("changes_requested history = %+v, want reviewer-1 and the notes", entries)
+	}
+
+	if queue, _ = db.ReviewQueue(""); len(queue) != 1 || queue[0].Item.ID != "ts-second" {
+		t.Errorf("queue after request changes = %+v, want only ts-second", queue)
+	}
+	if err := db.RequestChanges("ts-first", "reviewer-1", "again"); err == nil {
+		t.Error("requesting changes on an open task should fail")
+	}
+}
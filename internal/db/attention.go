@@ -0,0 +1,109 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// DueField is the custom field holding an item's due date (YYYY-MM-DD).
+// Set it with 'tpg field set <id> due 2026-03-01'.
+const DueField = "due"
+
+// AttentionReport lists the hygiene items an agent should look at when
+// starting a session.
+type AttentionReport struct {
+	Stale     []model.Item // In progress with no updates past the stale threshold
+	Overdue   []model.Item // Open work whose due date has passed
+	Unblocked []model.Item // Marked blocked, but every dependency is done
+	Closable  []model.Item // Open epics whose children are all closed
+}
+
+// Total returns the number of items needing attention.
+func (r *AttentionReport) Total() int {
+	return len(r.Stale) + len(r.Overdue) + len(r.Unblocked) + len(r.Closable)
+}
+
+// NeedsAttention gathers the items in a project (all projects when empty)
+// that need a human or agent to act on them.
+func (db *DB) NeedsAttention(project string, now time.Time) (*AttentionReport, error) {
+	report := &AttentionReport{}
+	var err error
+
+	if report.Stale, err = db.StaleItemsNow(project, now); err != nil {
+		return nil, err
+	}
+	if report.Overdue, err = db.OverdueItems(project, now); err != nil {
+		return nil, err
+	}
+	if report.Unblocked, err = db.UnblockedBlockedItems(project); err != nil {
+		return nil, err
+	}
+
+	epics, err := db.FindStuckEpics()
+	if err != nil {
+		return nil, err
+	}
+	for _, epic := range epics {
+		if project == "" || epic.Project == project {
+			report.Closable = append(report.Closable, epic)
+		}
+	}
+	return report, nil
+}
+
+// OverdueItems returns unfinished items whose due field is a date before
+// today, earliest due first. Items with an unparseable due value are skipped.
+func (db *DB) OverdueItems(project string, now time.Time) ([]model.Item, error) {
+	query := fmt.Sprintf(`SELECT %s FROM items
+		WHERE status NOT IN ('done', 'canceled')
+		  AND id IN (SELECT item_id FROM item_fields WHERE key = ?)`, itemSelectColumns)
+	args := []any{DueField}
+	if project != "" {
+		query += " AND project = ?"
+		args = append(args, project)
+	}
+	candidates, err := db.queryItems(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PopulateItemFields(candidates); err != nil {
+		return nil, err
+	}
+
+	today := now.Format("2006-01-02")
+	var overdue []model.Item
+	for _, item := range candidates {
+		value := item.Fields[DueField]
+		if _, err := time.Parse("2006-01-02", value); err != nil || value >= today {
+			continue
+		}
+		overdue = append(overdue, item)
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].Fields[DueField] < overdue[j].Fields[DueField]
+	})
+	return overdue, nil
+}
+
+// UnblockedBlockedItems returns items with status blocked that have at least
+// one dependency and no unfinished ones, i.e. their blockers have completed.
+func (db *DB) UnblockedBlockedItems(project string) ([]model.Item, error) {
+	query := fmt.Sprintf(`SELECT %s FROM items
+		WHERE status = 'blocked'
+		  AND EXISTS (SELECT 1 FROM deps d WHERE d.item_id = items.id)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM deps d JOIN items dep ON dep.id = d.depends_on
+		      WHERE d.item_id = items.id AND dep.status != 'done'
+		  )`, itemSelectColumns)
+	var args []any
+	if project != "" {
+		query += " AND project = ?"
+		args = append(args, project)
+	}
+	query += " ORDER BY priority ASC, updated_at ASC"
+	return db.queryItems(query, args...)
+}
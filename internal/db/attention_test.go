@@ -0,0 +1,100 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestNeedsAttention(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	// Overdue: past due, future due, bad value, done
+	late := createTestItem(t, db, "Late")
+	later := createTestItem(t, db, "Later")
+	garbled := createTestItem(t, db, "Garbled")
+	finished := createTestItem(t, db, "Finished")
+	for id, due := range map[string]string{
+		late.ID:     now.AddDate(0, 0, -3).Format("2006-01-02"),
+		later.ID:    now.AddDate(0, 0, 3).Format("2006-01-02"),
+		garbled.ID:  "next week",
+		finished.ID: now.AddDate(0, 0, -3).Format("2006-01-02"),
+	} {
+		if err := db.SetField(id, DueField, due); err != nil {
+			t.Fatalf("SetField: %v", err)
+		}
+	}
+	if err := db.UpdateStatus(finished.ID, model.StatusDone, AgentContext{}, true); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	// Blocked items: blocker done, blocker open, manual block without deps
+	blocker := createTestItem(t, db, "Blocker")
+	freed := createTestItem(t, db, "Freed")
+	waiting := createTestItem(t, db, "Waiting")
+	manual := createTestItem(t, db, "Manual")
+	openBlocker := createTestItem(t, db, "Open blocker")
+	if err := db.AddDep(freed.ID, blocker.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddDep(waiting.ID, openBlocker.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	for _, id := range []string{freed.ID, waiting.ID, manual.ID} {
+		if err := db.UpdateStatus(id, model.StatusBlocked, AgentContext{}, true); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+	}
+	if err := db.UpdateStatus(blocker.ID, model.StatusDone, AgentContext{}, true); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	// Epic whose only child is done
+	epic := createTestEpic(t, db, "Wrapped up", "test")
+	child := &model.Item{
+		ID:       model.GenerateID(model.ItemTypeTask),
+		Project:  "test",
+		Type:     model.ItemTypeTask,
+		Title:    "Child",
+		Status:   model.StatusDone,
+		ParentID: &epic.ID,
+	}
+	if err := db.CreateItem(child); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+
+	report, err := db.NeedsAttention("test", now)
+	if err != nil {
+		t.Fatalf("NeedsAttention: %v", err)
+	}
+	if len(report.Overdue) != 1 || report.Overdue[0].ID != late.ID {
+		t.Errorf("expected only %s overdue, got %v", late.ID, ids(report.Overdue))
+	}
+	if len(report.Unblocked) != 1 || report.Unblocked[0].ID != freed.ID {
+		t.Errorf("expected only %s unblocked, got %v", freed.ID, ids(report.Unblocked))
+	}
+	if len(report.Closable) != 1 || report.Closable[0].ID != epic.ID {
+		t.Errorf("expected only %s closable, got %v", epic.ID, ids(report.Closable))
+	}
+	if report.Total() != 3+len(report.Stale) {
+		t.Errorf("unexpected total %d", report.Total())
+	}
+
+	other, err := db.NeedsAttention("elsewhere", now)
+	if err != nil {
+		t.Fatalf("NeedsAttention: %v", err)
+	}
+	if other.Total() != 0 {
+		t.Errorf("expected nothing for another project, got %d", other.Total())
+	}
+}
+
+func ids(items []model.Item) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.ID
+	}
+	return out
+}
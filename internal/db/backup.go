@@ -16,6 +16,13 @@ const (
 	MaxBackups = 10
 	// BackupDir is the subdirectory for backups within the data directory
 	BackupDir = "backups"
+	// DefaultBackupInterval is the least time between automatic backups
+	// unless backup.interval is configured.
+	DefaultBackupInterval = 5 * time.Minute
+	// BatchEnv is the environment variable that marks a batch of commands.
+	// While it is set, automatic backups are skipped; the batch takes one
+	// backup when it ends.
+	BatchEnv = "TPG_BATCH"
 )
 
 // BackupPath returns the path to the backups directory
@@ -30,6 +37,8 @@ func BackupPath() (string, error) {
 // Backup creates a backup of the database.
 // Returns the path to the backup file.
 func (db *DB) Backup() (string, error) {
+	defer timePhase("backup")()
+
 	backupDir, err := BackupPath()
 	if err != nil {
 		return "", err
@@ -62,12 +71,51 @@ func (db *DB) Backup() (string, error) {
 	return backupFile, nil
 }
 
-// BackupQuiet creates a backup without printing any output.
-// Errors are silently ignored.
+// BackupQuiet creates a backup after a change, without printing any output.
+// It is skipped inside a batch and when the newest backup is younger than
+// the configured interval. Errors are silently ignored.
 func (db *DB) BackupQuiet() {
+	if InBatch() || !backupDue(time.Now()) {
+		return
+	}
 	_, _ = db.Backup()
 }
 
+// BackupAfterBatch takes the final backup of a batch whose commands skipped
+// theirs, regardless of the interval. Inside an enclosing batch it does
+// nothing; that batch backs up when it ends.
+func (db *DB) BackupAfterBatch() {
+	if InBatch() {
+		return
+	}
+	_, _ = db.Backup()
+}
+
+// InBatch reports whether this process runs as part of a batch (BatchEnv
+// is set).
+func InBatch() bool {
+	return os.Getenv(BatchEnv) != ""
+}
+
+// backupDue reports whether the newest backup is at least the configured
+// interval old.
+func backupDue(now time.Time) bool {
+	interval := DefaultBackupInterval
+	if config, err := LoadConfig(); err == nil {
+		if d, err := config.BackupInterval(); err == nil {
+			interval = d
+		}
+	}
+	if interval == 0 {
+		return true
+	}
+	backups, err := ListBackups()
+	if err != nil || len(backups) == 0 {
+		return true
+	}
+	return now.Sub(backups[0].ModTime) >= interval
+}
+
 // ListBackups returns a list of available backup files, newest first.
 func ListBackups() ([]BackupInfo, error) {
 	backupDir, err := BackupPath()
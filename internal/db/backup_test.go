@@ -0,0 +1,101 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupQuietDebounce(t *testing.T) {
+	dir := t.TempDir()
+	tpgDir := filepath.Join(dir, ".tpg")
+	if err := os.MkdirAll(tpgDir, 0755); err != nil {
+		t.Fatalf("failed to create .tpg dir: %v", err)
+	}
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+	t.Setenv(BatchEnv, "")
+
+	db, err := Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	if err := db.Init(); err != nil {
+		t.Fatalf("failed to init db: %v", err)
+	}
+
+	count := func() int {
+		backups, err := ListBackups()
+		if err != nil {
+			t.Fatalf("ListBackups: %v", err)
+		}
+		return len(backups)
+	}
+	start := count()
+
+	// A first backup is always due
+	if start == 0 {
+		db.BackupQuiet()
+		start = count()
+		if start != 1 {
+			t.Fatalf("expected a first backup, got %d", start)
+		}
+	}
+
+	// Within the default interval, further backups are skipped
+	db.BackupQuiet()
+	if got := count(); got != start {
+		t.Errorf("expected the backup to be debounced, got %d backups (was %d)", got, start)
+	}
+
+	// Inside a batch nothing is backed up until the batch ends
+	if err := os.WriteFile(filepath.Join(tpgDir, "config.json"), []byte(`{"backup": {"interval": "0"}}`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv(BatchEnv, "1")
+	db.BackupQuiet()
+	db.BackupAfterBatch()
+	if got := count(); got != start {
+		t.Errorf("expected no backups inside a batch, got %d (was %d)", got, start)
+	}
+	t.Setenv(BatchEnv, "")
+	db.BackupAfterBatch()
+	if got := count(); got != start+1 {
+		t.Errorf("expected a backup at the end of the batch, got %d (was %d)", got, start)
+	}
+
+	// An interval of 0 backs up after every change
+	db.BackupQuiet()
+	if got := count(); got != start+2 {
+		t.Errorf("expected a backup with interval 0, got %d (was %d)", got, start+1)
+	}
+}
+
+func TestBackupInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     string
+		wantErr  bool
+	}{
+		{"", "5m0s", false},
+		{"0", "0s", false},
+		{"30m", "30m0s", false},
+		{"1d", "24h0m0s", false},
+		{"soon", "", true},
+	}
+	for _, tt := range tests {
+		c := &Config{Backup: BackupConfig{Interval: tt.interval}}
+		got, err := c.BackupInterval()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("BackupInterval(%q) error = %v, wantErr %v", tt.interval, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("BackupInterval(%q) = %v, want %s", tt.interval, got, tt.want)
+		}
+	}
+}
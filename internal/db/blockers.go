@@ -0,0 +1,131 @@
+package db
+
+import (
+	"sort"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// BlockingChain describes one unmet dependency that holds back open work.
+type BlockingChain struct {
+	Blocker model.Item   // the unmet dependency
+	Gated   int          // open tasks in scope waiting on it, directly or through an ancestor epic
+	Unlocks []ImpactItem // tasks that become ready once it is done (see GetImpact)
+	Path    []model.Item // the blocker's own unmet deps, followed down to the first actionable item
+}
+
+// maxChainDepth bounds how far BlockingChains follows a blocker's own deps.
+const maxChainDepth = 20
+
+// BlockingChains returns the unmet dependencies that gate the most open tasks,
+// most gating first. Scope is the open tasks under epicID, or every open task in
+// project when epicID is empty. A limit of 0 returns all blockers.
+func (db *DB) BlockingChains(epicID, project string, limit int) ([]BlockingChain, error) {
+	var scope []model.Item
+	if epicID != "" {
+		descendants, err := db.GetDescendants(epicID)
+		if err != nil {
+			return nil, err
+		}
+		scope = descendants
+	} else {
+		status := model.StatusOpen
+		items, err := db.ListItems(project, &status)
+		if err != nil {
+			return nil, err
+		}
+		scope = items
+	}
+
+	gated := make(map[string]int)
+	for _, item := range scope {
+		if item.Status != model.StatusOpen || item.Type.CanHaveChildren() {
+			continue
+		}
+		deps, err := db.GetDepStatuses(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		inherited, err := db.GetAncestorDependencies(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, dep := range append(deps, inherited...) {
+			if dep.Status == string(model.StatusDone) || seen[dep.ID] {
+				continue
+			}
+			seen[dep.ID] = true
+			gated[dep.ID]++
+		}
+	}
+
+	ids := make([]string, 0, len(gated))
+	for id := range gated {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if gated[ids[i]] != gated[ids[j]] {
+			return gated[ids[i]] > gated[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	var chains []BlockingChain
+	for _, id := range ids {
+		blocker, err := db.GetItem(id)
+		if err != nil {
+			return nil, err
+		}
+		impact, err := db.GetImpact(id)
+		if err != nil {
+			return nil, err
+		}
+		path, err := db.unmetDepPath(id)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, BlockingChain{
+			Blocker: *blocker,
+			Gated:   gated[id],
+			Unlocks: impact,
+			Path:    path,
+		})
+	}
+	return chains, nil
+}
+
+// unmetDepPath follows the first unmet dependency of id repeatedly, returning
+// the items passed through. The last item is the one to work on first.
+func (db *DB) unmetDepPath(id string) ([]model.Item, error) {
+	var path []model.Item
+	visited := map[string]bool{id: true}
+	current := id
+	for len(path) < maxChainDepth {
+		deps, err := db.GetDepStatuses(current)
+		if err != nil {
+			return nil, err
+		}
+		next := ""
+		for _, dep := range deps {
+			if dep.Status != string(model.StatusDone) && !visited[dep.ID] {
+				next = dep.ID
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		item, err := db.GetItem(next)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, *item)
+		visited[next] = true
+		current = next
+	}
+	return path, nil
+}
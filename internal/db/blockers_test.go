@@ -0,0 +1,74 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestBlockingChains(t *testing.T) {
+	db := setupTestDB(t)
+
+	root := createTestItem(t, db, "Root cause")
+	schema := createTestItem(t, db, "Schema")
+	a := createTestItem(t, db, "A")
+	b := createTestItem(t, db, "B")
+	c := createTestItem(t, db, "C")
+	other := createTestItem(t, db, "Other blocker")
+
+	// schema waits on root; a, b, c wait on schema; c also waits on other
+	for _, dep := range [][2]string{
+		{schema.ID, root.ID},
+		{a.ID, schema.ID},
+		{b.ID, schema.ID},
+		{c.ID, schema.ID},
+		{c.ID, other.ID},
+	} {
+		if err := db.AddDep(dep[0], dep[1]); err != nil {
+			t.Fatalf("AddDep failed: %v", err)
+		}
+	}
+
+	chains, err := db.BlockingChains("", "test", 2)
+	if err != nil {
+		t.Fatalf("BlockingChains failed: %v", err)
+	}
+	if len(chains) != 2 {
+		t.Fatalf("expected 2 chains, got %d", len(chains))
+	}
+	top := chains[0]
+	if top.Blocker.ID != schema.ID || top.Gated != 3 {
+		t.Errorf("top blocker = %s gating %d, want %s gating 3", top.Blocker.ID, top.Gated, schema.ID)
+	}
+	if len(top.Path) != 1 || top.Path[0].ID != root.ID {
+		t.Errorf("path = %+v, want [%s]", top.Path, root.ID)
+	}
+	if len(top.Unlocks) != 2 {
+		t.Errorf("expected schema to unlock a and b, got %+v", top.Unlocks)
+	}
+}
+
+func TestBlockingChains_InheritedFromEpic(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	blocker := createTestItem(t, db, "External work")
+	child := createTestItem(t, db, "Child")
+	if err := db.SetParent(child.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	if err := db.AddDep(epic.ID, blocker.ID); err != nil {
+		t.Fatalf("AddDep failed: %v", err)
+	}
+
+	chains, err := db.BlockingChains(epic.ID, "", 0)
+	if err != nil {
+		t.Fatalf("BlockingChains failed: %v", err)
+	}
+	if len(chains) != 1 || chains[0].Blocker.ID != blocker.ID || chains[0].Gated != 1 {
+		t.Errorf("unexpected chains: %+v", chains)
+	}
+	if chains[0].Blocker.Status != model.StatusOpen {
+		t.Errorf("blocker status = %s, want open", chains[0].Blocker.Status)
+	}
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// ExternalDependent is an open item outside a canceled subtree that depends
+// on one of the canceled items.
+type ExternalDependent struct {
+	ItemID    string
+	Title     string
+	Status    model.Status
+	DependsOn string
+}
+
+// CancelCascadeResult reports what CancelCascade changed.
+type CancelCascadeResult struct {
+	Canceled           []string // root first, then descendants that were still open
+	ExternalDependents []ExternalDependent
+	CompletedEpics     []string // ancestor epics auto-completed as a result
+}
+
+// CancelCascade cancels an item and every open, in_progress, or blocked
+// descendant with a shared reason, in one transaction. Each cancellation must
+// be allowed by the status graph, as in UpdateStatus, unless force is set.
+// Items outside the subtree that depend on any canceled item are reported so
+// they can be re-planned; they are not modified.
+func (db *DB) CancelCascade(id, reason string, agentCtx AgentContext, force bool) (*CancelCascadeResult, error) {
+	root, err := db.GetItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if root.Status == model.StatusDone || root.Status == model.StatusCanceled {
+		return nil, fmt.Errorf("%s is already %s", id, root.Status)
+	}
+
+	descendants, err := db.GetDescendants(id)
+	if err != nil {
+		return nil, err
+	}
+
+	toCancel := []model.Item{*root}
+	for _, d := range descendants {
+		if d.Status != model.StatusDone && d.Status != model.StatusCanceled {
+			toCancel = append(toCancel, d)
+		}
+	}
+
+	// Check the whole subtree before changing any of it
+	forced := make(map[string]bool)
+	for _, item := range toCancel {
+		if err := model.CheckTransition(item.Status, model.StatusCanceled); err != nil {
+			if !force {
+				return nil, fmt.Errorf("%s: %w", item.ID, err)
+			}
+			forced[item.ID] = true
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result := &CancelCascadeResult{}
+	now := sqlTime(time.Now())
+	for _, item := range toCancel {
+		_, err := tx.Exec(`
+			UPDATE items
+			SET status = ?, results = ?, updated_at = ?, closed_at = ?,
+			    agent_id = NULL, agent_last_active = NULL
+			WHERE id = ?`,
+			model.StatusCanceled, reason, now, now, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel %s: %w", item.ID, err)
+		}
+		msg := "Canceled"
+		if item.ID != id {
+			msg += " (cascade from " + id + ")"
+		}
+		if reason != "" {
+			msg += ": " + reason
+		}
+		if err := insertLog(tx, item.ID, msg); err != nil {
+			return nil, err
+		}
+		result.Canceled = append(result.Canceled, item.ID)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, item := range toCancel {
+		if forced[item.ID] {
+			_ = db.RecordHistory(item.ID, EventTypeStatusForced, map[string]any{
+				"old": string(item.Status),
+				"new": string(model.StatusCanceled),
+			})
+		}
+		_ = db.RecordHistory(item.ID, EventTypeCanceled, map[string]any{
+			"results":    reason,
+			"cascade_of": id,
+		})
+	}
+
+	// Everything in the subtree, including already-closed items, counts as inside.
+	inside := map[string]bool{id: true}
+	for _, d := range descendants {
+		inside[d.ID] = true
+	}
+	result.ExternalDependents, err = db.externalDependents(result.Canceled, inside)
+	if err != nil {
+		return result, err
+	}
+
+	// Canceling the root may leave its parent epic with nothing left to do.
+	currentID := id
+	for {
+		info, err := db.CheckParentEpicCompletion(currentID)
+		if err != nil {
+			return result, err
+		}
+		if info == nil {
+			break
+		}
+		completed, err := db.AutoCompleteEpic(info.Epic.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to auto-complete epic %s: %w", info.Epic.ID, err)
+		}
+		result.CompletedEpics = append(result.CompletedEpics, completed...)
+		currentID = info.Epic.ID
+	}
+
+	return result, nil
+}
+
+// externalDependents returns open items outside inside that depend on any of ids.
+func (db *DB) externalDependents(ids []string, inside map[string]bool) ([]ExternalDependent, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT i.id, i.title, i.status, d.depends_on
+		FROM deps d
+		JOIN items i ON i.id = d.item_id
+		WHERE d.depends_on IN (%s)
+		  AND i.status NOT IN ('done', 'canceled')
+		ORDER BY i.id, d.depends_on`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependents: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []ExternalDependent
+	for rows.Next() {
+		var d ExternalDependent
+		if err := rows.Scan(&d.ItemID, &d.Title, &d.Status, &d.DependsOn); err != nil {
+			return nil, err
+		}
+		if !inside[d.ItemID] {
+			deps = append(deps, d)
+		}
+	}
+	return deps, rows.Err()
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func createCancelTestItem(t *testing.T, db *DB, id string, itemType model.ItemType, parent string, status model.Status) {
+	t.Helper()
+	item := &model.Item{
+		ID:        id,
+		Project:   "test",
+		Type:      itemType,
+		Title:     "Item " + id,
+		Status:    status,
+		Priority:  2,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if parent != "" {
+		item.ParentID = &parent
+	}
+	if err := db.CreateItem(item); err != nil {
+		t.Fatalf("failed to create %s: %v", id, err)
+	}
+}
+
+func TestCancelCascade(t *testing.T) {
+	db := setupTestDB(t)
+
+	createCancelTestItem(t, db, "ep-root", model.ItemTypeEpic, "", model.StatusOpen)
+	createCancelTestItem(t, db, "ep-sub", model.ItemTypeEpic, "ep-root", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-open", model.ItemTypeTask, "ep-root", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-wip", model.ItemTypeTask, "ep-sub", model.StatusInProgress)
+	createCancelTestItem(t, db, "ts-done", model.ItemTypeTask, "ep-root", model.StatusDone)
+	createCancelTestItem(t, db, "ts-outside", model.ItemTypeTask, "", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-closed-out", model.ItemTypeTask, "", model.StatusDone)
+
+	if err := db.AddDep("ts-outside", "ts-wip"); err != nil {
+		t.Fatalf("AddDep failed: %v", err)
+	}
+	if err := db.AddDep("ts-open", "ts-wip"); err != nil {
+		t.Fatalf("AddDep failed: %v", err)
+	}
+
+	result, err := db.CancelCascade("ep-root", "abandoned", AgentContext{}, false)
+	if err != nil {
+		t.Fatalf("CancelCascade failed: %v", err)
+	}
+
+	if len(result.Canceled) != 4 || result.Canceled[0] != "ep-root" {
+		t.Errorf("expected root + 3 open descendants canceled, got %v", result.Canceled)
+	}
+	for _, id := range []string{"ep-root", "ep-sub", "ts-open", "ts-wip"} {
+		item, _ := db.GetItem(id)
+		if item.Status != model.StatusCanceled {
+			t.Errorf("%s: expected canceled, got %s", id, item.Status)
+		}
+		if item.Results != "abandoned" {
+			t.Errorf("%s: expected shared reason, got %q", id, item.Results)
+		}
+		events, _ := db.GetHistory(HistoryQueryOptions{ItemID: id, EventTypes: []string{EventTypeCanceled}})
+		if len(events) != 1 || events[0].Changes["cascade_of"] != "ep-root" {
+			t.Errorf("%s: expected one canceled event from the cascade, got %+v", id, events)
+		}
+		if logs, _ := db.GetLogs(id); len(logs) != 1 || !strings.HasPrefix(logs[0].Message, "Canceled") {
+			t.Errorf("%s: expected a cancellation log, got %+v", id, logs)
+		}
+	}
+	if item, _ := db.GetItem("ts-done"); item.Status != model.StatusDone {
+		t.Errorf("done child should stay done, got %s", item.Status)
+	}
+
+	if len(result.ExternalDependents) != 1 {
+		t.Fatalf("expected 1 external dependent, got %+v", result.ExternalDependents)
+	}
+	dep := result.ExternalDependents[0]
+	if dep.ItemID != "ts-outside" || dep.DependsOn != "ts-wip" {
+		t.Errorf("unexpected external dependent: %+v", dep)
+	}
+	if item, _ := db.GetItem("ts-outside"); item.Status != model.StatusOpen {
+		t.Errorf("external dependent should not be modified, got %s", item.Status)
+	}
+
+	if _, err := db.CancelCascade("ep-root", "again", AgentContext{}, false); err == nil {
+		t.Error("expected error canceling an already canceled item")
+	}
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// ClaimItem marks an item in_progress for agentCtx, but only if it is still
+// open with every dependency done. The check and the update are a single
+// statement, so when two agents race for the same item exactly one wins. It
+// reports false when the item was no longer claimable.
+func (db *DB) ClaimItem(id string, agentCtx AgentContext) (bool, error) {
+	query := `
+		UPDATE items SET status = 'in_progress', updated_at = ?`
+	args := []any{sqlTime(time.Now())}
+	if agentCtx.IsActive() {
+		query += `, agent_id = ?, agent_last_active = CURRENT_TIMESTAMP`
+		args = append(args, agentCtx.ID)
+	}
+	query += `
+		WHERE id = ? AND status = 'open'
+		  AND NOT EXISTS (
+		    SELECT 1 FROM deps d
+		    JOIN items i ON d.depends_on = i.id
+		    WHERE d.item_id = items.id AND i.status != 'done'
+		  )`
+	args = append(args, id)
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim %s: %w", id, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	_ = db.RecordHistory(id, EventTypeStatusChanged, map[string]any{
+		"old": string(model.StatusOpen),
+		"new": string(model.StatusInProgress),
+	})
+	return true, nil
+}
+
+// ClaimFirst claims the first of candidates that is still claimable and
+// returns it, or nil when another agent got to all of them first.
+func (db *DB) ClaimFirst(candidates []model.Item, agentCtx AgentContext) (*model.Item, error) {
+	for i := range candidates {
+		ok, err := db.ClaimItem(candidates[i].ID, agentCtx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed := candidates[i]
+			claimed.Status = model.StatusInProgress
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
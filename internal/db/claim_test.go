@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestClaimItem(t *testing.T) {
+	db := setupTestDB(t)
+	first := createTestItem(t, db, "First")
+	second := createTestItem(t, db, "Second")
+	blocked := createTestItem(t, db, "Blocked")
+	if err := db.AddDep(blocked.ID, second.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+
+	agentA := AgentContext{ID: "agent-a"}
+	agentB := AgentContext{ID: "agent-b"}
+
+	ok, err := db.ClaimItem(first.ID, agentA)
+	if err != nil || !ok {
+		t.Fatalf("ClaimItem = %v, %v; want claimed", ok, err)
+	}
+	if ok, _ := db.ClaimItem(first.ID, agentB); ok {
+		t.Error("a second agent claimed an in-progress item")
+	}
+	if ok, _ := db.ClaimItem(blocked.ID, agentB); ok {
+		t.Error("claimed an item with an unmet dependency")
+	}
+
+	item, err := db.GetItem(first.ID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if item.Status != model.StatusInProgress || item.AgentID == nil || *item.AgentID != "agent-a" {
+		t.Errorf("claimed item = %s by %v, want in_progress by agent-a", item.Status, item.AgentID)
+	}
+
+	// ClaimFirst skips candidates that were taken in the meantime
+	claimed, err := db.ClaimFirst([]model.Item{*first, *blocked, *second}, agentB)
+	if err != nil {
+		t.Fatalf("ClaimFirst: %v", err)
+	}
+	if claimed == nil || claimed.ID != second.ID {
+		t.Fatalf("ClaimFirst = %v, want %s", claimed, second.ID)
+	}
+	if claimed, _ := db.ClaimFirst([]model.Item{*first, *second}, agentB); claimed != nil {
+		t.Errorf("ClaimFirst = %s, want nothing left", claimed.ID)
+	}
+}
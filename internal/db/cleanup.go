@@ -51,33 +51,115 @@ func (db *DB) GetOldItemIDs(before time.Time, status model.Status) ([]string, er
 	return ids, rows.Err()
 }
 
-// DeleteOldItems removes items older than the given date with the given status.
-// Items with children are skipped (they'll be cleaned up after their children are).
-// Returns the number of items deleted.
-func (db *DB) DeleteOldItems(before time.Time, status model.Status) (int, error) {
-	// Get the IDs first
-	ids, err := db.GetOldItemIDs(before, status)
+// DeletableOldItems returns the items DeleteOldItems would remove: those
+// older than the given date with the given status, except ones that still
+// have children. They are ordered by ID.
+func (db *DB) DeletableOldItems(before time.Time, status model.Status) ([]model.Item, error) {
+	items, err := db.queryItems(fmt.Sprintf(`
+		SELECT %s FROM items
+		WHERE status = ? AND updated_at < ?
+		  AND id NOT IN (SELECT parent_id FROM items WHERE parent_id IS NOT NULL)
+		ORDER BY id`, itemSelectColumns),
+		status, sqlTime(before))
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("failed to get old items: %w", err)
 	}
+	return items, nil
+}
 
-	if len(ids) == 0 {
-		return 0, nil
+// ItemRefs lists what still points at an item that is about to be deleted.
+type ItemRefs struct {
+	ItemID         string
+	OpenDependents []string // open items that depend on it
+	Learnings      []string // learnings linked to it
+}
+
+// ItemReferences returns, for each of ids that has any, the open items that
+// depend on it and the learnings linked to it: the references deleting it
+// would break. Dependents that are among ids don't count.
+func (db *DB) ItemReferences(ids []string) ([]ItemRefs, error) {
+	deleting := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleting[id] = true
 	}
 
-	// Filter out items that have children
-	var deletableIDs []string
+	var refs []ItemRefs
 	for _, id := range ids {
-		hasKids, err := db.hasChildren(id)
+		r := ItemRefs{ItemID: id}
+		rows, err := db.Query(`
+			SELECT d.item_id FROM deps d
+			JOIN items i ON i.id = d.item_id
+			WHERE d.depends_on = ? AND i.status NOT IN ('done', 'canceled')
+			ORDER BY d.item_id`, id)
 		if err != nil {
-			return 0, fmt.Errorf("failed to check children for %s: %w", id, err)
+			return nil, fmt.Errorf("failed to get dependents of %s: %w", id, err)
+		}
+		for rows.Next() {
+			var dependent string
+			if err := rows.Scan(&dependent); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("failed to scan dependent: %w", err)
+			}
+			if !deleting[dependent] {
+				r.OpenDependents = append(r.OpenDependents, dependent)
+			}
 		}
-		if !hasKids {
-			deletableIDs = append(deletableIDs, id)
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get dependents of %s: %w", id, err)
+		}
+
+		if r.Learnings, err = db.LinkedLearnings(id); err != nil {
+			return nil, err
+		}
+		if len(r.OpenDependents) > 0 || len(r.Learnings) > 0 {
+			refs = append(refs, r)
+		}
+	}
+	return refs, nil
+}
+
+// LinkedLearnings returns the IDs of the learnings linked to an item.
+func (db *DB) LinkedLearnings(itemID string) ([]string, error) {
+	rows, err := db.Query(`SELECT id FROM learnings WHERE task_id = ? ORDER BY id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get learnings of %s: %w", itemID, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan learning: %w", err)
 		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteOldItems removes items older than the given date with the given status.
+// Items with children are skipped (they'll be cleaned up after their children are).
+// Returns the number of items deleted.
+func (db *DB) DeleteOldItems(before time.Time, status model.Status) (int, error) {
+	items, err := db.DeletableOldItems(before, status)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
 	}
+	return db.DeleteItemsByID(ids)
+}
 
-	if len(deletableIDs) == 0 {
+// DeleteItemsByID removes the given items together with their logs,
+// dependencies, labels, fields, and everything else attached to them, in one
+// transaction, unlinking any learnings from them. Callers must make sure none
+// of them has children, and check ItemReferences first.
+// Returns the number of items deleted.
+func (db *DB) DeleteItemsByID(ids []string) (int, error) {
+	if len(ids) == 0 {
 		return 0, nil
 	}
 
@@ -88,25 +170,9 @@ func (db *DB) DeleteOldItems(before time.Time, status model.Status) (int, error)
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	for _, id := range deletableIDs {
-		// Delete logs
-		if _, err := tx.Exec(`DELETE FROM logs WHERE item_id = ?`, id); err != nil {
-			return 0, fmt.Errorf("failed to delete logs for %s: %w", id, err)
-		}
-
-		// Delete dependencies (both directions)
-		if _, err := tx.Exec(`DELETE FROM deps WHERE item_id = ? OR depends_on = ?`, id, id); err != nil {
-			return 0, fmt.Errorf("failed to delete dependencies for %s: %w", id, err)
-		}
-
-		// Delete item labels
-		if _, err := tx.Exec(`DELETE FROM item_labels WHERE item_id = ?`, id); err != nil {
-			return 0, fmt.Errorf("failed to delete item labels for %s: %w", id, err)
-		}
-
-		// Delete the item
-		if _, err := tx.Exec(`DELETE FROM items WHERE id = ?`, id); err != nil {
-			return 0, fmt.Errorf("failed to delete item %s: %w", id, err)
+	for _, id := range ids {
+		if err := db.deleteItemInternal(tx, id); err != nil {
+			return 0, fmt.Errorf("failed to delete %s: %w", id, err)
 		}
 	}
 
@@ -114,7 +180,7 @@ func (db *DB) DeleteOldItems(before time.Time, status model.Status) (int, error)
 		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return len(deletableIDs), nil
+	return len(ids), nil
 }
 
 // CountOrphanedLogs returns the count of logs that reference non-existent items.
@@ -167,13 +233,6 @@ func (db *DB) DeleteOldLogs(before time.Time) (int, error) {
 	return int(rows), nil
 }
 
-// hasChildren checks if an item has any children.
-func (db *DB) hasChildren(id string) (bool, error) {
-	var count int
-	err := db.QueryRow(`SELECT COUNT(*) FROM items WHERE parent_id = ?`, id).Scan(&count)
-	return count > 0, err
-}
-
 // Vacuum compacts the database by running SQLite VACUUM.
 // Returns the size difference in bytes (positive means space saved).
 func (db *DB) Vacuum() error {
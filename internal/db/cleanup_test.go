@@ -1,6 +1,7 @@
 package db
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -478,3 +479,123 @@ func TestDeleteOldItemsWithLabels(t *testing.T) {
 		t.Errorf("expected 0 item_labels, got %d", count)
 	}
 }
+
+func TestDeletableOldItems(t *testing.T) {
+	db := setupTestDB(t)
+
+	now := time.Now()
+	oldTime := now.AddDate(0, 0, -60)
+
+	epic := &model.Item{ID: "ep-old", Project: "test", Type: model.ItemTypeEpic, Title: "Old epic",
+		Status: model.StatusOpen, Priority: 2, CreatedAt: oldTime, UpdatedAt: oldTime}
+	if err := db.CreateItem(epic); err != nil {
+		t.Fatalf("failed to create epic: %v", err)
+	}
+	for _, item := range []*model.Item{
+		{ID: "ts-old2", Title: "Second", ParentID: &epic.ID},
+		{ID: "ts-old1", Title: "First"},
+		{ID: "ts-new", Title: "Recent"},
+	} {
+		item.Project, item.Type, item.Status, item.Priority = "test", model.ItemTypeTask, model.StatusDone, 2
+		item.CreatedAt, item.UpdatedAt = now, now
+		if err := db.CreateItem(item); err != nil {
+			t.Fatalf("failed to create %s: %v", item.ID, err)
+		}
+	}
+	if _, err := db.Exec("UPDATE items SET status = 'done', updated_at = ? WHERE id IN ('ep-old', 'ts-old1', 'ts-old2')", sqlTime(oldTime)); err != nil {
+		t.Fatalf("failed to age items: %v", err)
+	}
+
+	items, err := db.DeletableOldItems(now.AddDate(0, 0, -30), model.StatusDone)
+	if err != nil {
+		t.Fatalf("DeletableOldItems failed: %v", err)
+	}
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	// The epic is kept while it has children; the recent task isn't old enough.
+	if strings.Join(ids, ",") != "ts-old1,ts-old2" {
+		t.Errorf("DeletableOldItems = %v, want [ts-old1 ts-old2]", ids)
+	}
+}
+
+func TestItemReferences(t *testing.T) {
+	db := setupTestDB(t)
+
+	needed := createTestItem(t, db, "Needed by open work")
+	linked := createTestItem(t, db, "Linked from a learning")
+	free := createTestItem(t, db, "Nothing refers to it")
+	open := createTestItem(t, db, "Still open")
+	closedDependent := createTestItem(t, db, "Closed dependent")
+	if err := db.AddDep(open.ID, needed.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddDep(closedDependent.ID, free.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	learning := &model.Learning{ID: model.GenerateLearningID(), Project: "test", TaskID: &linked.ID, Summary: "Something learned"}
+	if err := db.CreateLearning(learning); err != nil {
+		t.Fatalf("CreateLearning: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE items SET status = 'done' WHERE id IN (?, ?, ?, ?)`,
+		needed.ID, linked.ID, free.ID, closedDependent.ID); err != nil {
+		t.Fatalf("failed to close items: %v", err)
+	}
+
+	refs, err := db.ItemReferences([]string{needed.ID, linked.ID, free.ID})
+	if err != nil {
+		t.Fatalf("ItemReferences: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("ItemReferences = %+v, want refs for 2 items", refs)
+	}
+	if refs[0].ItemID != needed.ID || len(refs[0].OpenDependents) != 1 || refs[0].OpenDependents[0] != open.ID {
+		t.Errorf("refs[0] = %+v, want %s needed by %s", refs[0], needed.ID, open.ID)
+	}
+	if refs[1].ItemID != linked.ID || len(refs[1].Learnings) != 1 || refs[1].Learnings[0] != learning.ID {
+		t.Errorf("refs[1] = %+v, want %s linked from %s", refs[1], linked.ID, learning.ID)
+	}
+
+	// A dependent that goes in the same deletion doesn't count.
+	refs, err = db.ItemReferences([]string{needed.ID, open.ID})
+	if err != nil {
+		t.Fatalf("ItemReferences: %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("ItemReferences = %+v, want none", refs)
+	}
+
+	// Deleting the linked item keeps the learning but unlinks it.
+	if _, err := db.DeleteItemsByID([]string{linked.ID}); err != nil {
+		t.Fatalf("DeleteItemsByID: %v", err)
+	}
+	got, err := db.GetLearning(learning.ID)
+	if err != nil {
+		t.Fatalf("learning was deleted: %v", err)
+	}
+	if got.TaskID != nil {
+		t.Errorf("learning still linked to %s", *got.TaskID)
+	}
+}
+
+func TestDeleteItemRefusesLinkedLearnings(t *testing.T) {
+	db := setupTestDB(t)
+
+	item := createTestItem(t, db, "Learned from")
+	learning := &model.Learning{ID: model.GenerateLearningID(), Project: "test", TaskID: &item.ID, Summary: "Something learned"}
+	if err := db.CreateLearning(learning); err != nil {
+		t.Fatalf("CreateLearning: %v", err)
+	}
+
+	err := db.DeleteItem(item.ID, false, false)
+	if err == nil || !strings.Contains(err.Error(), "learnings link to it") {
+		t.Fatalf("DeleteItem error = %v, want a linked learnings error", err)
+	}
+	if err := db.DeleteItem(item.ID, true, false); err != nil {
+		t.Fatalf("DeleteItem --force: %v", err)
+	}
+	if got, err := db.GetLearning(learning.ID); err != nil || got.TaskID != nil {
+		t.Errorf("learning after forced delete = %+v, %v; want it kept and unlinked", got, err)
+	}
+}
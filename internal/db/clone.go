@@ -0,0 +1,138 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EventTypeCloned is recorded on the root of a cloned subtree.
+const EventTypeCloned = "cloned"
+
+// CloneResult describes a cloned subtree.
+type CloneResult struct {
+	RootID string            // ID of the new root item
+	IDMap  map[string]string // Original ID -> new ID
+	Order  []string          // Original IDs in creation order (root first)
+	Deps   int               // Dependencies recreated between copied items
+}
+
+// CloneEpic copies an epic and its whole subtree as fresh open items. Titles,
+// descriptions, types, priorities, labels, template bindings, and epic context
+// are copied; dependencies are recreated only where both ends are inside the
+// subtree. Status, assignments, logs, results, and worktree metadata are not.
+//
+// The copy is placed under parentID, or alongside the original when parentID
+// is nil. rewrite, if non-nil, is applied to every title and description.
+// fit, if non-nil, then gets each copy's title and description and returns
+// the ones to create it with, e.g. to apply the title policy.
+func (db *DB) CloneEpic(epicID string, parentID *string, rewrite func(string) string, fit func(title, description string) (string, string)) (*CloneResult, error) {
+	root, err := db.GetItem(epicID)
+	if err != nil {
+		return nil, err
+	}
+	if !root.Type.CanHaveChildren() {
+		return nil, fmt.Errorf("%s is a %s; only epics can be cloned", epicID, root.Type)
+	}
+	if rewrite == nil {
+		rewrite = func(s string) string { return s }
+	}
+
+	descendants, err := db.GetDescendants(epicID)
+	if err != nil {
+		return nil, err
+	}
+	items := append([]model.Item{*root}, descendants...)
+	if err := db.PopulateItemLabels(items); err != nil {
+		return nil, err
+	}
+
+	// Walk parents before children so every copy has its parent in place
+	children := make(map[string][]*model.Item)
+	for i := range items[1:] {
+		it := &items[i+1]
+		if it.ParentID != nil {
+			children[*it.ParentID] = append(children[*it.ParentID], it)
+		}
+	}
+	ordered := []*model.Item{&items[0]}
+	for i := 0; i < len(ordered); i++ {
+		ordered = append(ordered, children[ordered[i].ID]...)
+	}
+
+	result := &CloneResult{IDMap: make(map[string]string, len(ordered))}
+	now := time.Now()
+	for i, orig := range ordered {
+		newID, err := db.GenerateItemID(orig.Type)
+		if err != nil {
+			return nil, err
+		}
+		copied := &model.Item{
+			ID:                  newID,
+			Project:             orig.Project,
+			Type:                orig.Type,
+			Title:               rewrite(orig.Title),
+			Description:         rewrite(orig.Description),
+			Status:              model.StatusOpen,
+			Priority:            orig.Priority,
+			TemplateID:          orig.TemplateID,
+			StepIndex:           orig.StepIndex,
+			TemplateVars:        orig.TemplateVars,
+			TemplateHash:        orig.TemplateHash,
+			SharedContext:       rewrite(orig.SharedContext),
+			ClosingInstructions: orig.ClosingInstructions,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+		}
+		if fit != nil {
+			copied.Title, copied.Description = fit(copied.Title, copied.Description)
+		}
+		if i == 0 {
+			copied.ParentID = root.ParentID
+			if parentID != nil {
+				copied.ParentID = parentID
+			}
+		} else {
+			mapped := result.IDMap[*orig.ParentID]
+			copied.ParentID = &mapped
+		}
+		if err := db.CreateItem(copied); err != nil {
+			return nil, fmt.Errorf("failed to clone %s: %w", orig.ID, err)
+		}
+		for _, label := range orig.Labels {
+			if err := db.AddLabelToItem(newID, copied.Project, label); err != nil {
+				return nil, err
+			}
+		}
+		result.IDMap[orig.ID] = newID
+		result.Order = append(result.Order, orig.ID)
+	}
+	result.RootID = result.IDMap[epicID]
+
+	for _, origID := range result.Order {
+		deps, err := db.GetDeps(origID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			target, ok := result.IDMap[dep]
+			if !ok {
+				continue
+			}
+			if err := db.AddDep(result.IDMap[origID], target); err != nil {
+				return nil, err
+			}
+			result.Deps++
+		}
+	}
+
+	_ = db.RecordHistory(result.RootID, EventTypeCloned, map[string]any{
+		"from":  epicID,
+		"items": len(result.Order),
+	})
+	if err := db.AddLog(result.RootID, fmt.Sprintf("Cloned from %s (%d items)", epicID, len(result.Order))); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestCloneEpic(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Release {{.version}}", "test")
+	sub := createTestEpic(t, db, "QA", "test")
+	a := createTestItem(t, db, "Tag {{.version}}")
+	b := createTestItem(t, db, "Announce")
+	outside := createTestItem(t, db, "Outside")
+	for _, p := range [][2]string{{sub.ID, epic.ID}, {a.ID, sub.ID}, {b.ID, epic.ID}} {
+		if err := db.SetParent(p[0], p[1]); err != nil {
+			t.Fatalf("SetParent: %v", err)
+		}
+	}
+	if err := db.AddDep(b.ID, a.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddDep(a.ID, outside.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddLabelToItem(a.ID, "test", "release"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+	if err := db.UpdateStatus(a.ID, model.StatusDone, AgentContext{}, true); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	rewrite := strings.NewReplacer("{{.version}}", "1.4").Replace
+	result, err := db.CloneEpic(epic.ID, nil, rewrite, nil)
+	if err != nil {
+		t.Fatalf("CloneEpic: %v", err)
+	}
+	if len(result.Order) != 4 || result.Order[0] != epic.ID {
+		t.Fatalf("expected 4 items with root first, got %v", result.Order)
+	}
+	if result.Deps != 1 {
+		t.Errorf("expected 1 internal dep copied, got %d", result.Deps)
+	}
+
+	root, err := db.GetItem(result.RootID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if root.Title != "Release 1.4" || root.ParentID != nil {
+		t.Errorf("unexpected root: title=%q parent=%v", root.Title, root.ParentID)
+	}
+
+	newA, err := db.GetItem(result.IDMap[a.ID])
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if newA.Title != "Tag 1.4" || newA.Status != model.StatusOpen {
+		t.Errorf("unexpected copy of A: title=%q status=%s", newA.Title, newA.Status)
+	}
+	if newA.ParentID == nil || *newA.ParentID != result.IDMap[sub.ID] {
+		t.Errorf("expected copy of A under %s, got %v", result.IDMap[sub.ID], newA.ParentID)
+	}
+	labels, err := db.GetItemLabels(newA.ID)
+	if err != nil {
+		t.Fatalf("GetItemLabels: %v", err)
+	}
+	if len(labels) != 1 || labels[0].Name != "release" {
+		t.Errorf("expected label release on copy, got %v", labels)
+	}
+	if deps, _ := db.GetDeps(newA.ID); len(deps) != 0 {
+		t.Errorf("expected external dep dropped, got %v", deps)
+	}
+	if deps, _ := db.GetDeps(result.IDMap[b.ID]); len(deps) != 1 || deps[0] != newA.ID {
+		t.Errorf("expected copy of B to depend on %s, got %v", newA.ID, deps)
+	}
+
+	// Original is untouched
+	if orig, _ := db.GetItem(epic.ID); orig.Title != "Release {{.version}}" {
+		t.Errorf("original title changed: %q", orig.Title)
+	}
+}
+
+func TestCloneEpic_Into(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Checklist", "test")
+	target := createTestEpic(t, db, "Quarter", "test")
+	task := createTestItem(t, db, "Task")
+
+	if _, err := db.CloneEpic(task.ID, nil, nil, nil); err == nil {
+		t.Error("expected error cloning a task")
+	}
+
+	result, err := db.CloneEpic(epic.ID, &target.ID, nil, nil)
+	if err != nil {
+		t.Fatalf("CloneEpic: %v", err)
+	}
+	root, err := db.GetItem(result.RootID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if root.ParentID == nil || *root.ParentID != target.ID {
+		t.Errorf("expected clone under %s, got %v", target.ID, root.ParentID)
+	}
+}
+
+func TestCloneEpic_Fit(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Release {{.version}}", "test")
+	fit := func(title, description string) (string, string) {
+		return "Release", title
+	}
+	rewrite := func(s string) string { return strings.ReplaceAll(s, "{{.version}}", "1.4.0") }
+
+	result, err := db.CloneEpic(epic.ID, nil, rewrite, fit)
+	if err != nil {
+		t.Fatalf("CloneEpic: %v", err)
+	}
+	root, err := db.GetItem(result.RootID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if root.Title != "Release" || root.Description != "Release 1.4.0" {
+		t.Errorf("title = %q, description = %q; want fit applied after rewrite", root.Title, root.Description)
+	}
+}
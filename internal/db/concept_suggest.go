@@ -0,0 +1,133 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ConceptSuggestThreshold is the confidence a suggested concept needs before
+// 'tpg learn' applies it without an explicit -c.
+const ConceptSuggestThreshold = 0.35
+
+// ConceptSuggestion is a concept proposed for a new learning.
+type ConceptSuggestion struct {
+	Name       string
+	Confidence float64 // 0..1
+}
+
+// suggestStopWords are common words ignored when comparing text.
+var suggestStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "are": true, "was": true, "not": true,
+	"but": true, "when": true, "has": true, "have": true, "into": true,
+	"its": true, "use": true, "uses": true, "can": true, "our": true,
+	"all": true, "any": true, "only": true, "then": true, "than": true,
+	"there": true, "they": true, "will": true, "should": true, "must": true,
+}
+
+// suggestWords splits text into its set of lowercase words.
+func suggestWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[w] = true
+	}
+	return words
+}
+
+// suggestTokens returns the words of text worth comparing between texts.
+func suggestTokens(text string) map[string]bool {
+	tokens := suggestWords(text)
+	for w := range tokens {
+		if len(w) < 3 || suggestStopWords[w] {
+			delete(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// coverage returns the fraction of a's words that also appear in b.
+func coverage(a, b map[string]bool) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a))
+}
+
+// overlap returns |a∩b| / min(|a|,|b|), or 0 when either is empty.
+func overlap(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	return coverage(a, b)
+}
+
+// SuggestConcepts proposes existing concepts for a new learning, best first.
+// A concept scores on its name appearing in the text and on how closely the
+// text matches learnings already tagged with it. At most limit suggestions
+// with a non-zero score are returned.
+func (db *DB) SuggestConcepts(project, text string, limit int) ([]ConceptSuggestion, error) {
+	concepts, err := db.ListConcepts(project, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(concepts) == 0 {
+		return nil, nil
+	}
+	learnings, err := db.GetAllLearnings(project, false)
+	if err != nil {
+		return nil, err
+	}
+
+	textWords := suggestWords(text)
+	textTokens := suggestTokens(text)
+
+	// Best learning match per concept
+	learningScore := make(map[string]float64)
+	for _, l := range learnings {
+		score := overlap(textTokens, suggestTokens(l.Summary+" "+l.Detail))
+		for _, c := range l.Concepts {
+			if score > learningScore[c] {
+				learningScore[c] = score
+			}
+		}
+	}
+
+	var suggestions []ConceptSuggestion
+	for _, c := range concepts {
+		// Every word of the name present is a full match; some is partial
+		nameScore := coverage(suggestWords(c.Name), textWords)
+		if nameScore < 1 {
+			nameScore *= 0.8
+		}
+		summaryScore := overlap(textTokens, suggestTokens(c.Summary))
+
+		// Each signal alone can carry a suggestion; together they reinforce
+		confidence := 1 - (1-0.9*nameScore)*(1-0.7*learningScore[c.Name])*(1-0.4*summaryScore)
+		if confidence > 0 {
+			suggestions = append(suggestions, ConceptSuggestion{Name: c.Name, Confidence: confidence})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
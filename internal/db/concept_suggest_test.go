@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestSuggestConcepts(t *testing.T) {
+	db := setupTestDB(t)
+
+	add := func(summary, detail string, concepts ...string) {
+		now := time.Now()
+		if err := db.CreateLearning(&model.Learning{
+			ID:        model.GenerateLearningID(),
+			Project:   "test",
+			CreatedAt: now,
+			UpdatedAt: now,
+			Summary:   summary,
+			Detail:    detail,
+			Status:    model.LearningStatusActive,
+			Concepts:  concepts,
+		}); err != nil {
+			t.Fatalf("CreateLearning: %v", err)
+		}
+	}
+	add("Token refresh races with logout", "The session mutex does not cover refresh tokens.", "auth")
+	add("Migrations must be idempotent", "Check column existence before ALTER TABLE.", "schema")
+	add("Retry loop ignores context", "Backoff keeps sleeping after cancellation.", "rate-limit")
+
+	// Name match
+	got, err := db.SuggestConcepts("test", "Schema version bump needs test updates", 3)
+	if err != nil {
+		t.Fatalf("SuggestConcepts: %v", err)
+	}
+	if len(got) == 0 || got[0].Name != "schema" || got[0].Confidence < ConceptSuggestThreshold {
+		t.Errorf("expected schema suggested confidently, got %+v", got)
+	}
+
+	// Hyphenated names match their separate words
+	got, _ = db.SuggestConcepts("test", "Hit the rate limit on rapid polling", 3)
+	if len(got) == 0 || got[0].Name != "rate-limit" {
+		t.Errorf("expected rate-limit first, got %+v", got)
+	}
+
+	// Similar learning text, no name match
+	got, _ = db.SuggestConcepts("test", "Refresh tokens race the session mutex", 3)
+	if len(got) == 0 || got[0].Name != "auth" || got[0].Confidence < ConceptSuggestThreshold {
+		t.Errorf("expected auth suggested from similar learning, got %+v", got)
+	}
+
+	// Unrelated text
+	got, _ = db.SuggestConcepts("test", "Bumped the logo colors", 3)
+	for _, s := range got {
+		if s.Confidence >= ConceptSuggestThreshold {
+			t.Errorf("expected no confident suggestion for unrelated text, got %+v", got)
+		}
+	}
+
+	// Limit
+	if got, _ := db.SuggestConcepts("test", "auth schema rate limit", 2); len(got) != 2 {
+		t.Errorf("expected 2 suggestions with limit, got %d", len(got))
+	}
+}
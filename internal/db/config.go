@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/taxilian/tpg/internal/model"
 )
@@ -20,8 +24,48 @@ type Config struct {
 	Prefixes       PrefixConfig   `json:"prefixes"`
 	DefaultProject string         `json:"default_project"`
 	IDLength       int            `json:"id_length,omitempty"`
+	IDAlphabet     string         `json:"id_alphabet,omitempty"`
 	Warnings       WarningsConfig `json:"warnings,omitempty"`
 	Worktree       WorktreeConfig `json:"worktree,omitempty"`
+	Lint           LintConfig     `json:"lint,omitempty"`
+	Stale          StaleConfig    `json:"stale,omitempty"`
+	Output         OutputConfig   `json:"output,omitempty"`
+	Backup         BackupConfig   `json:"backup,omitempty"`
+	// Aliases maps a short command name to the tpg arguments it expands to,
+	// e.g. "rd" -> "ready -p myproject -l bug".
+	Aliases map[string]string `json:"alias,omitempty"`
+	// ResultTemplates maps an item type or label to the skeleton used by
+	// 'tpg done --template'. Entries override the built-in defaults.
+	ResultTemplates map[string]string `json:"result_templates,omitempty"`
+	// Types defines custom item types beyond the built-in task and epic.
+	Types map[string]TypeConfig `json:"types,omitempty"`
+	// StatusTransitions adds moves to the built-in status graph, by status,
+	// e.g. {"done": ["in_progress"]} to let finished work be picked up again
+	// without reopening it.
+	StatusTransitions map[string][]string `json:"status_transitions,omitempty"`
+	// LabelWeights assigns service classes to labels for ready ordering.
+	// Items whose labels sum to a higher weight always sort first, e.g.
+	// {"hotfix": 100, "backlog": -100}.
+	LabelWeights map[string]int `json:"label_weights,omitempty"`
+	// TemplateValues are project-wide values available to templates as
+	// {{.Config.<key>}}, alongside the built-in project and branch_prefix.
+	TemplateValues map[string]string `json:"template_values,omitempty"`
+	// RedactPatterns are regular expressions that 'tpg redact' always scrubs,
+	// e.g. API key formats like "sk-[A-Za-z0-9]{20,}".
+	RedactPatterns []string `json:"redact_patterns,omitempty"`
+	// AddDefaults maps a project name to the defaults 'tpg add' applies to
+	// new items in it. The "*" entry covers projects without their own.
+	AddDefaults map[string]AddDefaults `json:"add_defaults,omitempty"`
+	// LabelRoutes maps a label to the epic (ID or alias) that 'tpg add' puts
+	// new items with that label under when no --parent is given, e.g.
+	// {"docs": "ep-docs"}.
+	LabelRoutes map[string]string `json:"label_routes,omitempty"`
+	Policy      PolicyConfig      `json:"policy,omitempty"`
+	Metrics     MetricsConfig     `json:"metrics,omitempty"`
+	// Roles maps an $AGENT_TYPE to the commands agents of that type may
+	// run. Entries replace the built-in planner, executor, and reviewer
+	// roles of the same name.
+	Roles map[string]RoleConfig `json:"roles,omitempty"`
 	// Note: The "custom_prefixes" field in JSON is silently ignored for backward compatibility.
 }
 
@@ -32,6 +76,139 @@ type WarningsConfig struct {
 	ShortDescription *bool `json:"short_description,omitempty"`
 	// MinDescriptionWords is the minimum word count before warning. Default is 15.
 	MinDescriptionWords int `json:"min_description_words,omitempty"`
+	// EpicScopeGrowth is how many tasks a started epic may gain before 'tpg
+	// add' warns that its scope is running away. Default is 10.
+	EpicScopeGrowth int `json:"epic_scope_growth,omitempty"`
+}
+
+// LintConfig controls the planning quality rules applied by 'tpg lint'.
+type LintConfig struct {
+	// Rules turns individual rules on or off by name, e.g. {"dep-note": true}.
+	// Rules not listed use their default.
+	Rules map[string]bool `json:"rules,omitempty"`
+	// MinDescriptionWords for the short-description rule.
+	// Default is warnings.min_description_words.
+	MinDescriptionWords int `json:"min_description_words,omitempty"`
+	// StaleDays is how old an open task with no logs must be to be flagged. Default is 14.
+	StaleDays int `json:"stale_days,omitempty"`
+}
+
+// StaleConfig sets how long an in_progress task may go without updates before
+// it is shown as stale. Durations accept Go syntax ("30m", "4h") or days ("2d").
+type StaleConfig struct {
+	// Default applies when no priority or type entry matches. Default is 5m.
+	Default string `json:"default,omitempty"`
+	// Priority maps a priority number to its threshold, e.g. {"1": "30m", "3": "1d"}.
+	Priority map[string]string `json:"priority,omitempty"`
+	// Type maps an item type to its threshold, e.g. {"research": "2d"}.
+	// Type entries win over priority entries.
+	Type map[string]string `json:"type,omitempty"`
+}
+
+// OutputConfig controls how tpg renders terminal output.
+type OutputConfig struct {
+	// ASCII replaces box-drawing characters, symbols, and emoji with plain
+	// ASCII, for terminals and log files that can't show them.
+	ASCII bool `json:"ascii,omitempty"`
+}
+
+// BackupConfig controls the backups taken automatically after changes.
+type BackupConfig struct {
+	// Interval is the least time between automatic backups, in Go syntax
+	// ("10m", "1h") or days ("1d"). "0" backs up after every change.
+	// Default is 5m.
+	Interval string `json:"interval,omitempty"`
+}
+
+// MetricsConfig turns on metrics for monitoring agents centrally: commands
+// run, status transitions, and ready-queue depth. Metrics are off unless
+// Exporter is set.
+type MetricsConfig struct {
+	// Exporter is "statsd" or "otlp" (OpenTelemetry over HTTP).
+	Exporter string `json:"exporter,omitempty"`
+	// Endpoint is host:port for statsd (default 127.0.0.1:8125) or the
+	// collector's base URL for otlp (default http://localhost:4318).
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix starts every metric name. Default is "tpg".
+	Prefix string `json:"prefix,omitempty"`
+	// Tags are added to every metric, e.g. {"fleet": "ci"}.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// RoleConfig limits the commands an agent may run. Entries are command
+// paths such as "add" or "epic edit"; a command group ("epic") covers its
+// subcommands, "read" covers every command that only reads, and "*" covers
+// everything.
+type RoleConfig struct {
+	// Allow lists the commands the role may run. Empty allows every command.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists commands the role may not run, even when allowed.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// PolicyConfig sets planning rules for 'tpg add'. Broken rules are warnings
+// unless Strict is set, which makes them errors.
+type PolicyConfig struct {
+	Strict bool `json:"strict,omitempty"`
+	// RequireParent requires tasks (items that can't have children) to have a parent epic.
+	RequireParent bool `json:"require_parent,omitempty"`
+	// MinDescriptionWords is the least number of words in a description.
+	// Default is warnings.min_description_words, enforced only when strict.
+	MinDescriptionWords int `json:"min_description_words,omitempty"`
+	// RequirePriority requires --priority (or a project add default).
+	RequirePriority bool `json:"require_priority,omitempty"`
+	// MaxTitleLength is the longest title allowed, in characters. Default is
+	// DefaultMaxTitleLength. Titles must also be a single line.
+	MaxTitleLength int `json:"max_title_length,omitempty"`
+}
+
+// PolicyMinDescriptionWords returns the description length the policy
+// requires.
+func (c *Config) PolicyMinDescriptionWords() int {
+	if c.Policy.MinDescriptionWords > 0 {
+		return c.Policy.MinDescriptionWords
+	}
+	return c.GetMinDescriptionWords()
+}
+
+// PolicyMaxTitleLength returns the longest title the policy allows.
+func (c *Config) PolicyMaxTitleLength() int {
+	if c.Policy.MaxTitleLength > 0 {
+		return c.Policy.MaxTitleLength
+	}
+	return DefaultMaxTitleLength
+}
+
+// TitleProblem describes how title breaks the title policy (one line of at
+// most maxLen characters), or returns "" when it doesn't.
+func TitleProblem(title string, maxLen int) string {
+	title = strings.TrimSpace(title)
+	if strings.ContainsAny(title, "\r\n") {
+		return "spans several lines"
+	}
+	if n := utf8.RuneCountInString(title); n > maxLen {
+		return fmt.Sprintf("is %d characters (max %d)", n, maxLen)
+	}
+	return ""
+}
+
+// AddDefaults are applied by 'tpg add' to fields left out on the command line.
+type AddDefaults struct {
+	Labels   []string `json:"labels,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	// RequireParent makes 'tpg add' refuse items without --parent.
+	RequireParent bool `json:"require_parent,omitempty"`
+}
+
+// AddDefaultsFor returns the add defaults for project, falling back to the
+// "*" entry.
+func (c *Config) AddDefaultsFor(project string) (AddDefaults, bool) {
+	if d, ok := c.AddDefaults[project]; ok {
+		return d, true
+	}
+	d, ok := c.AddDefaults["*"]
+	return d, ok
 }
 
 // WorktreeConfig holds settings for Git worktree integration.
@@ -39,11 +216,25 @@ type WorktreeConfig struct {
 	BranchPrefix  string `json:"branch_prefix,omitempty"`   // Default "feature"
 	RequireEpicID *bool  `json:"require_epic_id,omitempty"` // Default true
 	Root          string `json:"root,omitempty"`            // Default ".worktrees"
+	// Commits the base may gain before an epic branch is flagged for a
+	// rebase. Default 20; negative disables.
+	DriftThreshold int `json:"drift_threshold,omitempty"`
 }
 
+// DefaultDriftThreshold is the default number of base commits an epic
+// branch may fall behind before drift warnings appear.
+const DefaultDriftThreshold = 20
+
 // DefaultMinDescriptionWords is the default threshold for short description warnings.
 const DefaultMinDescriptionWords = 15
 
+// DefaultEpicScopeGrowth is the default number of tasks a started epic may
+// gain before 'tpg add' warns.
+const DefaultEpicScopeGrowth = 10
+
+// DefaultMaxTitleLength is the default longest title, in characters.
+const DefaultMaxTitleLength = 120
+
 // ShortDescriptionWarningEnabled returns whether short description warnings are enabled.
 func (c *Config) ShortDescriptionWarningEnabled() bool {
 	if c.Warnings.ShortDescription == nil {
@@ -60,6 +251,255 @@ func (c *Config) GetMinDescriptionWords() int {
 	return c.Warnings.MinDescriptionWords
 }
 
+// GetEpicScopeGrowth returns how many tasks a started epic may gain before
+// 'tpg add' warns.
+func (c *Config) GetEpicScopeGrowth() int {
+	if c.Warnings.EpicScopeGrowth <= 0 {
+		return DefaultEpicScopeGrowth
+	}
+	return c.Warnings.EpicScopeGrowth
+}
+
+// LintOptions returns the lint settings from the config with defaults applied.
+func (c *Config) LintOptions() LintOptions {
+	opts := LintOptions{
+		Rules:               make(map[string]bool, len(LintRules)),
+		MinDescriptionWords: c.Lint.MinDescriptionWords,
+		StaleDays:           c.Lint.StaleDays,
+	}
+	for _, rule := range LintRules {
+		opts.Rules[rule.Name] = rule.Default
+	}
+	for name, enabled := range c.Lint.Rules {
+		opts.Rules[name] = enabled
+	}
+	if opts.MinDescriptionWords <= 0 {
+		opts.MinDescriptionWords = c.GetMinDescriptionWords()
+	}
+	if opts.StaleDays <= 0 {
+		opts.StaleDays = DefaultLintStaleDays
+	}
+	return opts
+}
+
+// DefaultResultTemplate is the key used when no type or label matches.
+const DefaultResultTemplate = "default"
+
+// DefaultResultTemplates are the built-in result skeletons for 'tpg done --template'.
+var DefaultResultTemplates = map[string]string{
+	"bug": `## Root cause
+
+## Fix
+
+## Verification
+`,
+	"feature": `## What was built
+
+## Key files
+
+## How to use
+
+## Notes
+`,
+	"investigation": `## Findings
+
+## Decisions
+
+## Next steps
+`,
+	DefaultResultTemplate: `## Summary
+
+## Key files
+
+## Notes
+`,
+}
+
+// ResultTemplate returns the result skeleton registered under name,
+// preferring the project config over the built-in defaults.
+func (c *Config) ResultTemplate(name string) (string, bool) {
+	if tmpl, ok := c.ResultTemplates[name]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := DefaultResultTemplates[name]
+	return tmpl, ok
+}
+
+// ResultTemplateFor picks the result skeleton for an item. Labels are checked
+// first (bug, feature, ...), then the item type, then the default template.
+// It returns the matched template name along with the skeleton.
+func (c *Config) ResultTemplateFor(item *model.Item) (string, string) {
+	for _, label := range item.Labels {
+		if tmpl, ok := c.ResultTemplate(label); ok {
+			return label, tmpl
+		}
+	}
+	if tmpl, ok := c.ResultTemplate(string(item.Type)); ok {
+		return string(item.Type), tmpl
+	}
+	tmpl, _ := c.ResultTemplate(DefaultResultTemplate)
+	return DefaultResultTemplate, tmpl
+}
+
+// TypeConfig defines a custom item type.
+type TypeConfig struct {
+	Prefix          string `json:"prefix,omitempty"`           // Default: first two letters of the name
+	DefaultPriority int    `json:"default_priority,omitempty"` // Default 2
+	CanHaveChildren bool   `json:"can_have_children,omitempty"`
+	Icon            string `json:"icon,omitempty"`
+	Color           string `json:"color,omitempty"`
+}
+
+// TypeInfo converts a configured type into its model definition, filling defaults.
+func (tc TypeConfig) TypeInfo(name string) model.TypeInfo {
+	prefix := normalizePrefix(tc.Prefix)
+	if prefix == "" {
+		prefix = name
+		if len(prefix) > 2 {
+			prefix = prefix[:2]
+		}
+	}
+	priority := tc.DefaultPriority
+	if priority == 0 {
+		priority = 2
+	}
+	return model.TypeInfo{
+		Name:            model.ItemType(name),
+		Prefix:          prefix,
+		DefaultPriority: priority,
+		CanHaveChildren: tc.CanHaveChildren,
+		Icon:            tc.Icon,
+		Color:           tc.Color,
+	}
+}
+
+// RegisterTypes replaces the model's custom type registry with the types
+// defined in this config.
+func (c *Config) RegisterTypes() error {
+	model.ResetItemTypes()
+	for name, tc := range c.Types {
+		if err := model.RegisterItemType(tc.TypeInfo(name)); err != nil {
+			return fmt.Errorf("invalid type %q in config: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterTransitions replaces the extra status transitions known to the
+// model with those in the config.
+func (c *Config) RegisterTransitions() error {
+	model.ResetTransitions()
+	for from, tos := range c.StatusTransitions {
+		for _, to := range tos {
+			if err := model.AllowTransition(model.Status(from), model.Status(to)); err != nil {
+				return fmt.Errorf("invalid status_transitions entry %s -> %s: %w", from, to, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CompileRedactPatterns compiles the configured redact_patterns.
+func (c *Config) CompileRedactPatterns() ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(c.RedactPatterns))
+	for _, expr := range c.RedactPatterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact_patterns entry %q: %w", expr, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// StaleThresholds parses the stale config into thresholds for the model.
+func (c *Config) StaleThresholds() (model.StaleThresholds, error) {
+	var t model.StaleThresholds
+	if c.Stale.Default != "" {
+		d, err := parseConfigDuration(c.Stale.Default)
+		if err != nil {
+			return t, fmt.Errorf("invalid stale.default: %w", err)
+		}
+		t.Default = d
+	}
+	for key, value := range c.Stale.Priority {
+		priority, err := strconv.Atoi(key)
+		if err != nil {
+			return t, fmt.Errorf("invalid stale.priority key %q: must be a number", key)
+		}
+		d, err := parseConfigDuration(value)
+		if err != nil {
+			return t, fmt.Errorf("invalid stale.priority.%s: %w", key, err)
+		}
+		if t.Priority == nil {
+			t.Priority = map[int]time.Duration{}
+		}
+		t.Priority[priority] = d
+	}
+	for name, value := range c.Stale.Type {
+		d, err := parseConfigDuration(value)
+		if err != nil {
+			return t, fmt.Errorf("invalid stale.type.%s: %w", name, err)
+		}
+		if t.Type == nil {
+			t.Type = map[model.ItemType]time.Duration{}
+		}
+		t.Type[model.ItemType(name)] = d
+	}
+	return t, nil
+}
+
+// IDFormat returns the length and alphabet of the random part of new IDs.
+func (c *Config) IDFormat() (int, string, error) {
+	length, alphabet := c.IDLength, c.IDAlphabet
+	if length == 0 {
+		length = model.DefaultIDLength
+	}
+	if alphabet == "" {
+		alphabet = model.DefaultIDAlphabet
+	}
+	if length < 1 || length > maxIDLength {
+		return 0, "", fmt.Errorf("invalid id_length %d: must be between 1 and %d", length, maxIDLength)
+	}
+	if err := model.ValidateIDAlphabet(alphabet); err != nil {
+		return 0, "", fmt.Errorf("invalid id_alphabet: %w", err)
+	}
+	return length, alphabet, nil
+}
+
+// BackupInterval returns the least time between automatic backups.
+func (c *Config) BackupInterval() (time.Duration, error) {
+	switch c.Backup.Interval {
+	case "":
+		return DefaultBackupInterval, nil
+	case "0":
+		return 0, nil
+	}
+	d, err := parseConfigDuration(c.Backup.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backup.interval: %w", err)
+	}
+	return d, nil
+}
+
+// parseConfigDuration parses a positive duration, accepting "Nd" for days in
+// addition to Go duration syntax.
+func parseConfigDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		days, ok := strings.CutSuffix(s, "d")
+		n, convErr := strconv.Atoi(days)
+		if !ok || convErr != nil {
+			return 0, fmt.Errorf("%q is not a duration (use e.g. 30m, 4h, 2d)", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q must be positive", s)
+	}
+	return d, nil
+}
+
 // PrefixConfig holds ID prefixes for items.
 type PrefixConfig struct {
 	Task string `json:"task"`
@@ -190,6 +630,18 @@ func DefaultProject() (string, error) {
 	return config.DefaultProject, nil
 }
 
+// DriftLimit returns the drift threshold in commits, or 0 when drift
+// warnings are disabled.
+func (c WorktreeConfig) DriftLimit() int {
+	switch {
+	case c.DriftThreshold < 0:
+		return 0
+	case c.DriftThreshold == 0:
+		return DefaultDriftThreshold
+	}
+	return c.DriftThreshold
+}
+
 // RequireEpicIDEnabled returns whether explicit branch names must include the epic ID.
 func (c WorktreeConfig) RequireEpicIDEnabled() bool {
 	if c.RequireEpicID == nil {
@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
 )
 
 // hasPathSuffix checks if a path ends with the given suffix components
@@ -463,6 +466,26 @@ func TestWorktreeConfig_Defaults(t *testing.T) {
 	if !config.Worktree.RequireEpicIDEnabled() {
 		t.Errorf("RequireEpicIDEnabled() = false, want true")
 	}
+	if got := config.Worktree.DriftLimit(); got != DefaultDriftThreshold {
+		t.Errorf("DriftLimit() = %d, want %d", got, DefaultDriftThreshold)
+	}
+}
+
+func TestWorktreeConfig_DriftLimit(t *testing.T) {
+	tests := []struct {
+		threshold int
+		want      int
+	}{
+		{0, DefaultDriftThreshold},
+		{5, 5},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		c := WorktreeConfig{DriftThreshold: tt.threshold}
+		if got := c.DriftLimit(); got != tt.want {
+			t.Errorf("DriftLimit() with %d = %d, want %d", tt.threshold, got, tt.want)
+		}
+	}
 }
 
 func TestWorktreeConfig_LoadsExistingConfig(t *testing.T) {
@@ -532,3 +555,84 @@ func TestWorktreeConfig_RoundTrip(t *testing.T) {
 		t.Errorf("Root = %q, want %q", loaded.Worktree.Root, "wt")
 	}
 }
+
+func TestResultTemplateFor(t *testing.T) {
+	config := &Config{
+		ResultTemplates: map[string]string{
+			"bug":  "## Custom bug\n",
+			"task": "## Task\n",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		item     *model.Item
+		wantName string
+		wantBody string
+	}{
+		{"label overrides default", &model.Item{Type: model.ItemTypeTask, Labels: []string{"bug"}}, "bug", "## Custom bug\n"},
+		{"builtin label", &model.Item{Type: model.ItemTypeTask, Labels: []string{"ux", "feature"}}, "feature", DefaultResultTemplates["feature"]},
+		{"falls back to type", &model.Item{Type: model.ItemTypeTask, Labels: []string{"ux"}}, "task", "## Task\n"},
+		{"falls back to default", &model.Item{Type: model.ItemTypeEpic}, DefaultResultTemplate, DefaultResultTemplates[DefaultResultTemplate]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotBody := config.ResultTemplateFor(tt.item)
+			if gotName != tt.wantName || gotBody != tt.wantBody {
+				t.Errorf("ResultTemplateFor() = (%q, %q), want (%q, %q)", gotName, gotBody, tt.wantName, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestConfig_StaleThresholds(t *testing.T) {
+	config := &Config{Stale: StaleConfig{
+		Default:  "10m",
+		Priority: map[string]string{"1": "30m", "3": "2d"},
+		Type:     map[string]string{"research": "4h"},
+	}}
+
+	got, err := config.StaleThresholds()
+	if err != nil {
+		t.Fatalf("StaleThresholds failed: %v", err)
+	}
+	if got.Default != 10*time.Minute {
+		t.Errorf("Default = %v, want 10m", got.Default)
+	}
+	if got.Priority[1] != 30*time.Minute || got.Priority[3] != 48*time.Hour {
+		t.Errorf("Priority = %v, want 1:30m 3:48h", got.Priority)
+	}
+	if got.Type["research"] != 4*time.Hour {
+		t.Errorf("Type = %v, want research:4h", got.Type)
+	}
+}
+
+func TestConfig_StaleThresholds_Invalid(t *testing.T) {
+	tests := []StaleConfig{
+		{Default: "soon"},
+		{Default: "-5m"},
+		{Priority: map[string]string{"high": "30m"}},
+		{Type: map[string]string{"research": "2 days"}},
+	}
+	for _, stale := range tests {
+		config := &Config{Stale: stale}
+		if _, err := config.StaleThresholds(); err == nil {
+			t.Errorf("StaleThresholds(%+v) succeeded, want error", stale)
+		}
+	}
+}
+
+func TestTitleProblem(t *testing.T) {
+	if p := TitleProblem("Fix login bug", 20); p != "" {
+		t.Errorf("short title: got problem %q", p)
+	}
+	if p := TitleProblem("Fix login bug\nIt fails when...", 100); p != "spans several lines" {
+		t.Errorf("multi-line title: got %q", p)
+	}
+	if p := TitleProblem(strings.Repeat("x", 21), 20); p != "is 21 characters (max 20)" {
+		t.Errorf("long title: got %q", p)
+	}
+	if p := TitleProblem("  Fix login bug\n", 20); p != "" {
+		t.Errorf("surrounding whitespace counted as a problem: %q", p)
+	}
+}
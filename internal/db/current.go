@@ -0,0 +1,86 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// CurrentSession returns the key under which the current task is stored:
+// $AGENT_ID for agents, otherwise $TPG_SESSION, otherwise the parent shell's
+// process ID so that each terminal has its own current task.
+func CurrentSession() string {
+	if id := os.Getenv("AGENT_ID"); id != "" {
+		return "agent:" + id
+	}
+	if s := os.Getenv("TPG_SESSION"); s != "" {
+		return "session:" + s
+	}
+	return "shell:" + strconv.Itoa(os.Getppid())
+}
+
+// SetCurrentTask records itemID as the current task for a session.
+func (db *DB) SetCurrentTask(session, itemID string) error {
+	item, err := db.GetItem(itemID)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		INSERT INTO current_tasks (session, item_id, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(session) DO UPDATE SET item_id = excluded.item_id, updated_at = excluded.updated_at`,
+		session, item.ID, sqlTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to set current task: %w", err)
+	}
+	return nil
+}
+
+// GetCurrentTask returns the current task for a session, or "" if none is set.
+func (db *DB) GetCurrentTask(session string) (string, error) {
+	var itemID string
+	err := db.QueryRow(`SELECT item_id FROM current_tasks WHERE session = ?`, session).Scan(&itemID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get current task: %w", err)
+	}
+	return itemID, nil
+}
+
+// ClearCurrentTask forgets the current task for a session.
+func (db *DB) ClearCurrentTask(session string) error {
+	if _, err := db.Exec(`DELETE FROM current_tasks WHERE session = ?`, session); err != nil {
+		return fmt.Errorf("failed to clear current task: %w", err)
+	}
+	return nil
+}
+
+// ClearCurrentTaskIf forgets the current task for a session only if it is itemID.
+func (db *DB) ClearCurrentTaskIf(session, itemID string) error {
+	if _, err := db.Exec(`DELETE FROM current_tasks WHERE session = ? AND item_id = ?`, session, itemID); err != nil {
+		return fmt.Errorf("failed to clear current task: %w", err)
+	}
+	return nil
+}
+
+// LastCreatedItem returns the most recent item created in a session that
+// still exists, from the "created" events in history. It returns "" when the
+// session hasn't created any.
+func (db *DB) LastCreatedItem(session string) (string, error) {
+	var itemID string
+	err := db.QueryRow(`
+		SELECT h.item_id FROM history h
+		JOIN items i ON i.id = h.item_id
+		WHERE h.event_type = ? AND h.session = ?
+		ORDER BY h.id DESC LIMIT 1`, EventTypeCreated, session).Scan(&itemID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find last created item: %w", err)
+	}
+	return itemID, nil
+}
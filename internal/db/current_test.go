@@ -0,0 +1,141 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestCurrentTask(t *testing.T) {
+	db := setupTestDB(t)
+	first := createTestItem(t, db, "First")
+	second := createTestItem(t, db, "Second")
+
+	id, err := db.GetCurrentTask("agent:a")
+	if err != nil {
+		t.Fatalf("GetCurrentTask failed: %v", err)
+	}
+	if id != "" {
+		t.Errorf("current = %q, want none", id)
+	}
+
+	if err := db.SetCurrentTask("agent:a", first.ID); err != nil {
+		t.Fatalf("SetCurrentTask failed: %v", err)
+	}
+	if err := db.SetCurrentTask("agent:b", second.ID); err != nil {
+		t.Fatalf("SetCurrentTask failed: %v", err)
+	}
+	if id, _ := db.GetCurrentTask("agent:a"); id != first.ID {
+		t.Errorf("agent:a current = %q, want %q", id, first.ID)
+	}
+	if id, _ := db.GetCurrentTask("agent:b"); id != second.ID {
+		t.Errorf("agent:b current = %q, want %q", id, second.ID)
+	}
+
+	// Setting again replaces the current task.
+	if err := db.SetCurrentTask("agent:a", second.ID); err != nil {
+		t.Fatalf("SetCurrentTask failed: %v", err)
+	}
+	if id, _ := db.GetCurrentTask("agent:a"); id != second.ID {
+		t.Errorf("agent:a current = %q, want %q", id, second.ID)
+	}
+
+	// ClearCurrentTaskIf only clears a matching task.
+	if err := db.ClearCurrentTaskIf("agent:b", first.ID); err != nil {
+		t.Fatalf("ClearCurrentTaskIf failed: %v", err)
+	}
+	if id, _ := db.GetCurrentTask("agent:b"); id != second.ID {
+		t.Errorf("agent:b current = %q, want %q", id, second.ID)
+	}
+
+	if err := db.ClearCurrentTask("agent:a"); err != nil {
+		t.Fatalf("ClearCurrentTask failed: %v", err)
+	}
+	if id, _ := db.GetCurrentTask("agent:a"); id != "" {
+		t.Errorf("agent:a current = %q after clear, want none", id)
+	}
+
+	// Deleting the item forgets it everywhere.
+	if err := db.DeleteItem(second.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem failed: %v", err)
+	}
+	if id, _ := db.GetCurrentTask("agent:b"); id != "" {
+		t.Errorf("agent:b current = %q after delete, want none", id)
+	}
+}
+
+func TestCurrentTask_FollowsReplace(t *testing.T) {
+	db := setupTestDB(t)
+	old := createTestItem(t, db, "Old")
+	if err := db.SetCurrentTask("agent:a", old.ID); err != nil {
+		t.Fatalf("SetCurrentTask: %v", err)
+	}
+
+	now := time.Now()
+	newID, err := db.ReplaceItem(old.ID, &model.Item{
+		ID: "ts-new1", Project: "test", Type: model.ItemTypeTask, Title: "New",
+		Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("ReplaceItem: %v", err)
+	}
+	if id, _ := db.GetCurrentTask("agent:a"); id != newID {
+		t.Errorf("current = %q after replace, want %q", id, newID)
+	}
+}
+
+func TestSetCurrentTask_UnknownItem(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.SetCurrentTask("agent:a", "ts-missing"); err == nil {
+		t.Error("expected error for unknown item")
+	}
+}
+
+func TestCurrentSession(t *testing.T) {
+	t.Setenv("AGENT_ID", "")
+	t.Setenv("TPG_SESSION", "")
+	if got := CurrentSession(); !strings.HasPrefix(got, "shell:") {
+		t.Errorf("CurrentSession() = %q, want shell key", got)
+	}
+
+	t.Setenv("TPG_SESSION", "work")
+	if got := CurrentSession(); got != "session:work" {
+		t.Errorf("CurrentSession() = %q, want session:work", got)
+	}
+
+	t.Setenv("AGENT_ID", "ses_1")
+	if got := CurrentSession(); got != "agent:ses_1" {
+		t.Errorf("CurrentSession() = %q, want agent:ses_1", got)
+	}
+}
+
+func TestLastCreatedItem(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("TPG_SESSION", "")
+
+	t.Setenv("AGENT_ID", "a")
+	first := createTestItem(t, db, "First")
+	second := createTestItem(t, db, "Second")
+	t.Setenv("AGENT_ID", "b")
+	other := createTestItem(t, db, "Other")
+
+	if id, err := db.LastCreatedItem("agent:a"); err != nil || id != second.ID {
+		t.Errorf("agent:a last created = %q, %v; want %q", id, err, second.ID)
+	}
+	if id, _ := db.LastCreatedItem("agent:b"); id != other.ID {
+		t.Errorf("agent:b last created = %q, want %q", id, other.ID)
+	}
+	if id, _ := db.LastCreatedItem("agent:c"); id != "" {
+		t.Errorf("agent:c last created = %q, want none", id)
+	}
+
+	// Deleted items are skipped.
+	if err := db.DeleteItem(second.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem failed: %v", err)
+	}
+	if id, _ := db.LastCreatedItem("agent:a"); id != first.ID {
+		t.Errorf("agent:a last created after delete = %q, want %q", id, first.ID)
+	}
+}
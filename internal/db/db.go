@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/taxilian/tpg/internal/model"
 	_ "modernc.org/sqlite"
 )
 
@@ -33,7 +34,7 @@ func sqlTime(t time.Time) string {
 
 // SchemaVersion is the current schema version.
 // Increment this when adding new migrations.
-const SchemaVersion = 9
+const SchemaVersion = 29
 
 // baseSchema is the original schema (version 1).
 // New tables should be added via migrations, not here.
@@ -195,11 +196,201 @@ ALTER TABLE items ADD COLUMN worktree_base TEXT;
 	// Version 9: Add merged_at column for tracking when epics were merged
 	// This migration is handled specially in runMigrationV9 to be idempotent
 	"", // Empty placeholder - actual logic in runMigrationV9
+	// Version 10: Add epic snapshots for per-epic rollback
+	`
+CREATE TABLE IF NOT EXISTS epic_snapshots (
+	id INTEGER PRIMARY KEY,
+	epic_id TEXT NOT NULL,
+	name TEXT,
+	data TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_epic_snapshots_epic ON epic_snapshots(epic_id);
+`,
+	// Version 11: Add registered agent metadata
+	`
+CREATE TABLE IF NOT EXISTS agents (
+	id TEXT PRIMARY KEY,
+	name TEXT,
+	type TEXT,
+	description TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	// Version 12: Add a note explaining why a dependency exists
+	// This migration is handled specially in runMigrationV12 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV12
+	// Version 13: Add custom key/value fields on items
+	`
+CREATE TABLE IF NOT EXISTS item_fields (
+	item_id TEXT NOT NULL REFERENCES items(id),
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (item_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_item_fields_key ON item_fields(key, value);
+`,
+	// Version 14: Add progress percentage to log entries
+	// This migration is handled specially in runMigrationV14 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV14
+	// Version 15: Track the current task per agent or shell session
+	`
+CREATE TABLE IF NOT EXISTS current_tasks (
+	session TEXT PRIMARY KEY,
+	item_id TEXT NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	// Version 16: Add project- and epic-level notes
+	`
+CREATE TABLE IF NOT EXISTS notes (
+	id TEXT PRIMARY KEY,
+	project TEXT NOT NULL,
+	epic_id TEXT REFERENCES items(id),
+	title TEXT NOT NULL,
+	body TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notes_project ON notes(project);
+CREATE INDEX IF NOT EXISTS idx_notes_epic ON notes(epic_id);
+`,
+	// Version 17: Add review scheduling to learnings
+	// This migration is handled specially in runMigrationV17 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV17
+	// Version 18: Keep previous versions of replaced descriptions
+	`
+CREATE TABLE IF NOT EXISTS description_versions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_id TEXT NOT NULL REFERENCES items(id),
+	description TEXT NOT NULL,
+	agent_id TEXT,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_description_versions_item ON description_versions(item_id);
+`,
+	// Version 19: Full-text search over completion results
+	`
+CREATE VIRTUAL TABLE IF NOT EXISTS items_results_fts USING fts5(
+	results,
+	content='items',
+	content_rowid='rowid'
+);
+
+CREATE TRIGGER IF NOT EXISTS items_results_ai AFTER INSERT ON items BEGIN
+	INSERT INTO items_results_fts(rowid, results)
+	VALUES (NEW.rowid, NEW.results);
+END;
+
+CREATE TRIGGER IF NOT EXISTS items_results_ad AFTER DELETE ON items BEGIN
+	INSERT INTO items_results_fts(items_results_fts, rowid, results)
+	VALUES ('delete', OLD.rowid, OLD.results);
+END;
+
+CREATE TRIGGER IF NOT EXISTS items_results_au AFTER UPDATE OF results ON items BEGIN
+	INSERT INTO items_results_fts(items_results_fts, rowid, results)
+	VALUES ('delete', OLD.rowid, OLD.results);
+	INSERT INTO items_results_fts(rowid, results)
+	VALUES (NEW.rowid, NEW.results);
+END;
+
+INSERT INTO items_results_fts(items_results_fts) VALUES ('rebuild');
+`,
+	// Version 20: Human-friendly aliases for items
+	`
+CREATE TABLE IF NOT EXISTS item_aliases (
+	alias TEXT PRIMARY KEY,
+	item_id TEXT NOT NULL UNIQUE REFERENCES items(id),
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	// Version 21: Manual ordering among siblings
+	// This migration is handled specially in runMigrationV21 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV21
+	// Version 22: Dependencies on every task with a label (group dependencies)
+	`
+CREATE TABLE IF NOT EXISTS group_deps (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_id TEXT NOT NULL REFERENCES items(id),
+	label TEXT NOT NULL,
+	epic_id TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE(item_id, label, epic_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_deps_item ON group_deps(item_id);
+`,
+	// Version 23: Session that recorded each history event
+	// This migration is handled specially in runMigrationV23 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV23
+	// Version 24: Persistent index of parsed template files
+	`
+CREATE TABLE IF NOT EXISTS template_index (
+	path TEXT PRIMARY KEY,
+	mod_time INTEGER NOT NULL,
+	size INTEGER NOT NULL,
+	hash TEXT NOT NULL,
+	data TEXT NOT NULL,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	// Version 25: Items watched by agents and people, for 'tpg inbox'
+	`
+CREATE TABLE IF NOT EXISTS watches (
+	watcher TEXT NOT NULL,
+	item_id TEXT NOT NULL REFERENCES items(id),
+	history_seen INTEGER NOT NULL DEFAULT 0,
+	log_seen INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (watcher, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_watches_item ON watches(item_id);
+`,
+	// Version 26: Log categories (progress, decision, blocker, note)
+	// This migration is handled specially in runMigrationV26 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV26
+	// Version 27: Full-text search over log messages
+	// This migration is handled specially in runMigrationV27, since old test
+	// schemas may lack the logs table
+	"", // Empty placeholder - actual logic in runMigrationV27
+	// Version 28: Approval gates (items.needs_approval)
+	// This migration is handled specially in runMigrationV28 to be idempotent
+	"", // Empty placeholder - actual logic in runMigrationV28
+	// Version 29: Compressed command transcripts attached to items
+	`
+CREATE TABLE IF NOT EXISTS transcripts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_id TEXT NOT NULL REFERENCES items(id),
+	name TEXT NOT NULL DEFAULT '',
+	size INTEGER NOT NULL,
+	content BLOB NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_transcripts_item ON transcripts(item_id, created_at);
+`,
 }
 
 // DB wraps a SQL database connection with task-specific operations.
 type DB struct {
 	*sql.DB
+
+	// labelWeights order ready work ahead of priority; see SetLabelWeights.
+	labelWeights model.LabelWeights
+}
+
+// SetLabelWeights sets the label weights (service classes) used to order
+// ready items, usually from the project config's label_weights.
+func (db *DB) SetLabelWeights(weights map[string]int) {
+	db.labelWeights = model.NewLabelWeights(weights)
+}
+
+// LabelWeights returns the label weights used to order ready items.
+func (db *DB) LabelWeights() model.LabelWeights {
+	return db.labelWeights
 }
 
 // ExecRetry executes a statement with retry logic for transient errors.
@@ -224,6 +415,8 @@ func (db *DB) QueryRetry(query string, args ...any) (*sql.Rows, error) {
 
 // Open opens or creates the database at the given path
 func Open(path string) (*DB, error) {
+	defer timePhase("db open")()
+
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -268,7 +461,7 @@ func Open(path string) (*DB, error) {
 		return nil, err
 	}
 
-	return &DB{sqlDB}, nil
+	return &DB{DB: sqlDB}, nil
 }
 
 // isRetryableError checks if an error is a transient SQLite error that can be retried.
@@ -339,6 +532,14 @@ func (db *DB) Init() error {
 // Safe to call on every startup - only runs migrations newer than current version.
 // Creates a backup before running any migrations.
 func (db *DB) Migrate() error {
+	return db.migrate(true)
+}
+
+// migrate runs pending migrations, first backing up the database to the
+// project's backups when backup is set.
+func (db *DB) migrate(backup bool) error {
+	defer timePhase("migration")()
+
 	currentVersion, err := db.getSchemaVersion()
 	if err != nil {
 		return fmt.Errorf("failed to get schema version: %w", err)
@@ -369,7 +570,7 @@ func (db *DB) Migrate() error {
 	}
 
 	// Create backup before running any migrations
-	if needsMigration {
+	if needsMigration && backup {
 		backupPath, err := db.Backup()
 		if err != nil {
 			return fmt.Errorf("failed to create pre-migration backup: %w", err)
@@ -406,6 +607,38 @@ func (db *DB) Migrate() error {
 			if err := db.runMigrationV9(); err != nil {
 				return fmt.Errorf("migration to v9 failed: %w", err)
 			}
+		} else if targetVersion == 12 {
+			if err := db.runMigrationV12(); err != nil {
+				return fmt.Errorf("migration to v12 failed: %w", err)
+			}
+		} else if targetVersion == 14 {
+			if err := db.runMigrationV14(); err != nil {
+				return fmt.Errorf("migration to v14 failed: %w", err)
+			}
+		} else if targetVersion == 17 {
+			if err := db.runMigrationV17(); err != nil {
+				return fmt.Errorf("migration to v17 failed: %w", err)
+			}
+		} else if targetVersion == 21 {
+			if err := db.runMigrationV21(); err != nil {
+				return fmt.Errorf("migration to v21 failed: %w", err)
+			}
+		} else if targetVersion == 23 {
+			if err := db.runMigrationV23(); err != nil {
+				return fmt.Errorf("migration to v23 failed: %w", err)
+			}
+		} else if targetVersion == 26 {
+			if err := db.runMigrationV26(); err != nil {
+				return fmt.Errorf("migration to v26 failed: %w", err)
+			}
+		} else if targetVersion == 27 {
+			if err := db.runMigrationV27(); err != nil {
+				return fmt.Errorf("migration to v27 failed: %w", err)
+			}
+		} else if targetVersion == 28 {
+			if err := db.runMigrationV28(); err != nil {
+				return fmt.Errorf("migration to v28 failed: %w", err)
+			}
 		} else {
 			if _, err := db.Exec(migration); err != nil {
 				return fmt.Errorf("migration to v%d failed: %w", targetVersion, err)
@@ -628,6 +861,197 @@ func (db *DB) runMigrationV9() error {
 	return nil
 }
 
+// runMigrationV12 adds the note column to deps.
+func (db *DB) runMigrationV12() error {
+	exists, err := db.tableExists("deps")
+	if err != nil {
+		return fmt.Errorf("failed to check deps table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	exists, err = db.columnExists("deps", "note")
+	if err != nil {
+		return fmt.Errorf("failed to check deps.note column: %w", err)
+	}
+	if !exists {
+		if _, err := db.Exec("ALTER TABLE deps ADD COLUMN note TEXT"); err != nil {
+			return fmt.Errorf("failed to add deps.note column: %w", err)
+		}
+	}
+	return nil
+}
+
+// runMigrationV14 adds the progress column to logs.
+func (db *DB) runMigrationV14() error {
+	exists, err := db.tableExists("logs")
+	if err != nil {
+		return fmt.Errorf("failed to check logs table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	exists, err = db.columnExists("logs", "progress")
+	if err != nil {
+		return fmt.Errorf("failed to check logs.progress column: %w", err)
+	}
+	if !exists {
+		if _, err := db.Exec("ALTER TABLE logs ADD COLUMN progress INTEGER"); err != nil {
+			return fmt.Errorf("failed to add logs.progress column: %w", err)
+		}
+	}
+	return nil
+}
+
+// runMigrationV21 adds the sort_rank column to items.
+func (db *DB) runMigrationV21() error {
+	exists, err := db.columnExists("items", "sort_rank")
+	if err != nil {
+		return fmt.Errorf("failed to check items.sort_rank column: %w", err)
+	}
+	if !exists {
+		if _, err := db.Exec("ALTER TABLE items ADD COLUMN sort_rank INTEGER"); err != nil {
+			return fmt.Errorf("failed to add items.sort_rank column: %w", err)
+		}
+	}
+	return nil
+}
+
+// runMigrationV23 adds the session column to history.
+func (db *DB) runMigrationV23() error {
+	exists, err := db.columnExists("history", "session")
+	if err != nil {
+		return fmt.Errorf("failed to check history.session column: %w", err)
+	}
+	if !exists {
+		if _, err := db.Exec("ALTER TABLE history ADD COLUMN session TEXT"); err != nil {
+			return fmt.Errorf("failed to add history.session column: %w", err)
+		}
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_history_session ON history(session, created_at)"); err != nil {
+		return fmt.Errorf("failed to create history session index: %w", err)
+	}
+	return nil
+}
+
+// runMigrationV26 adds the category column to logs and categorizes existing
+// entries by their message prefix.
+func (db *DB) runMigrationV26() error {
+	exists, err := db.tableExists("logs")
+	if err != nil {
+		return fmt.Errorf("failed to check logs table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	exists, err = db.columnExists("logs", "category")
+	if err != nil {
+		return fmt.Errorf("failed to check logs.category column: %w", err)
+	}
+	if !exists {
+		if _, err := db.Exec("ALTER TABLE logs ADD COLUMN category TEXT NOT NULL DEFAULT 'note'"); err != nil {
+			return fmt.Errorf("failed to add logs.category column: %w", err)
+		}
+	}
+	for _, c := range []string{"progress", "decision", "blocker"} {
+		if _, err := db.Exec(`UPDATE logs SET category = ? WHERE lower(ltrim(message, ' '||char(9)||char(10)||char(13))) LIKE ?`,
+			c, c+":%"); err != nil {
+			return fmt.Errorf("failed to categorize logs: %w", err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE logs SET category = 'progress' WHERE progress IS NOT NULL`); err != nil {
+		return fmt.Errorf("failed to categorize logs: %w", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_logs_category ON logs(category, created_at)"); err != nil {
+		return fmt.Errorf("failed to create logs category index: %w", err)
+	}
+	return nil
+}
+
+// logsFTSSchema indexes log messages for 'tpg logs search'.
+const logsFTSSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS logs_fts USING fts5(
+	message,
+	content='logs',
+	content_rowid='id'
+);
+
+CREATE TRIGGER IF NOT EXISTS logs_fts_ai AFTER INSERT ON logs BEGIN
+	INSERT INTO logs_fts(rowid, message)
+	VALUES (NEW.id, NEW.message);
+END;
+
+CREATE TRIGGER IF NOT EXISTS logs_fts_ad AFTER DELETE ON logs BEGIN
+	INSERT INTO logs_fts(logs_fts, rowid, message)
+	VALUES ('delete', OLD.id, OLD.message);
+END;
+
+CREATE TRIGGER IF NOT EXISTS logs_fts_au AFTER UPDATE OF message ON logs BEGIN
+	INSERT INTO logs_fts(logs_fts, rowid, message)
+	VALUES ('delete', OLD.id, OLD.message);
+	INSERT INTO logs_fts(rowid, message)
+	VALUES (NEW.id, NEW.message);
+END;
+
+INSERT INTO logs_fts(logs_fts) VALUES ('rebuild');
+`
+
+// runMigrationV27 creates the full-text index over log messages.
+func (db *DB) runMigrationV27() error {
+	exists, err := db.tableExists("logs")
+	if err != nil {
+		return fmt.Errorf("failed to check logs table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	if _, err := db.Exec(logsFTSSchema); err != nil {
+		return fmt.Errorf("failed to create log search index: %w", err)
+	}
+	return nil
+}
+
+// runMigrationV28 adds the needs_approval column to items.
+func (db *DB) runMigrationV28() error {
+	exists, err := db.columnExists("items", "needs_approval")
+	if err != nil {
+		return fmt.Errorf("failed to check items.needs_approval column: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if _, err := db.Exec("ALTER TABLE items ADD COLUMN needs_approval INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return fmt.Errorf("failed to add items.needs_approval column: %w", err)
+	}
+	return nil
+}
+
+// runMigrationV17 adds the review_after and review_at columns to learnings.
+func (db *DB) runMigrationV17() error {
+	exists, err := db.tableExists("learnings")
+	if err != nil {
+		return fmt.Errorf("failed to check learnings table: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	for _, col := range []struct{ name, typ string }{
+		{"review_after", "TEXT"},
+		{"review_at", "DATETIME"},
+	} {
+		exists, err = db.columnExists("learnings", col.name)
+		if err != nil {
+			return fmt.Errorf("failed to check learnings.%s column: %w", col.name, err)
+		}
+		if !exists {
+			if _, err := db.Exec(fmt.Sprintf("ALTER TABLE learnings ADD COLUMN %s %s", col.name, col.typ)); err != nil {
+				return fmt.Errorf("failed to add learnings.%s column: %w", col.name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // migrateProjects populates the projects table from existing items.
 func (db *DB) migrateProjects() error {
 	_, err := db.Exec(`
@@ -679,7 +1103,7 @@ func (db *DB) checkDatabaseIntegrity() error {
 	}
 	if result != "ok" {
 		// Try to recover FTS5 if that's the issue
-		if strings.Contains(result, "fts5") || strings.Contains(result, "learnings_fts") {
+		if strings.Contains(result, "fts5") || strings.Contains(result, "learnings_fts") || strings.Contains(result, "items_results_fts") || strings.Contains(result, "logs_fts") {
 			// Create backup before attempting repair
 			backupPath, backupErr := db.Backup()
 			if backupErr != nil {
@@ -722,6 +1146,13 @@ func (db *DB) rebuildFTS5() error {
 	if err != nil {
 		return fmt.Errorf("failed to rebuild FTS5 index: %w", err)
 	}
+	// The results index reads straight from items, so it can rebuild itself
+	if _, err := db.Exec(`INSERT INTO items_results_fts(items_results_fts) VALUES ('rebuild')`); err != nil {
+		return fmt.Errorf("failed to rebuild results index: %w", err)
+	}
+	if _, err := db.Exec(`INSERT INTO logs_fts(logs_fts) VALUES ('rebuild')`); err != nil {
+		return fmt.Errorf("failed to rebuild log index: %w", err)
+	}
 	return nil
 }
 
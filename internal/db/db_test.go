@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -213,6 +214,46 @@ func TestUpdateStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateStatus_EnforcesTransitions(t *testing.T) {
+	db := setupTestDB(t)
+
+	item := &model.Item{
+		ID:        model.GenerateID(model.ItemTypeTask),
+		Project:   "test",
+		Type:      model.ItemTypeTask,
+		Title:     "Test",
+		Status:    model.StatusDone,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := db.CreateItem(item); err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+
+	err := db.UpdateStatus(item.ID, model.StatusBlocked, AgentContext{}, false)
+	var transErr *model.TransitionError
+	if !errors.As(err, &transErr) {
+		t.Fatalf("done -> blocked: err = %v, want a *model.TransitionError", err)
+	}
+	if got, _ := db.GetItem(item.ID); got.Status != model.StatusDone {
+		t.Errorf("status = %q after a rejected change, want done", got.Status)
+	}
+
+	if err := db.UpdateStatus(item.ID, model.StatusBlocked, AgentContext{}, true); err != nil {
+		t.Fatalf("forced done -> blocked: %v", err)
+	}
+	if got, _ := db.GetItem(item.ID); got.Status != model.StatusBlocked {
+		t.Errorf("status = %q after a forced change, want blocked", got.Status)
+	}
+	entries, err := db.GetHistory(HistoryQueryOptions{ItemID: item.ID, EventTypes: []string{EventTypeStatusForced}})
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Changes["old"] != "done" || entries[0].Changes["new"] != "blocked" {
+		t.Errorf("status_forced history = %+v, want one done -> blocked entry", entries)
+	}
+}
+
 func TestUpdateStatus_NotFound(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -333,8 +374,8 @@ func TestSetParent_NonEpicParent(t *testing.T) {
 	err := db.SetParent(task2.ID, task1.ID)
 	if err == nil {
 		t.Error("expected error when setting non-epic as parent, got nil")
-	} else if !strings.Contains(err.Error(), "epic") {
-		t.Errorf("expected error mentioning 'epic', got: %v", err)
+	} else if !strings.Contains(err.Error(), "cannot have children") {
+		t.Errorf("expected error saying the parent cannot have children, got: %v", err)
 	}
 
 	// Verify the parent was NOT set
@@ -590,9 +631,9 @@ func TestSetClosingInstructions_NotFound(t *testing.T) {
 }
 
 func TestSchemaVersion(t *testing.T) {
-	// Verify SchemaVersion is set to 9
-	if SchemaVersion != 9 {
-		t.Errorf("SchemaVersion = %d, want 9", SchemaVersion)
+	// Verify SchemaVersion matches the latest migration
+	if SchemaVersion != 29 {
+		t.Errorf("SchemaVersion = %d, want 29", SchemaVersion)
 	}
 }
 
@@ -676,8 +717,8 @@ func TestMigrationV4_ExistingDataPreserved(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to get schema version: %v", err)
 	}
-	if version != 9 {
-		t.Errorf("schema version = %d, want 9", version)
+	if version != 29 {
+		t.Errorf("schema version = %d, want 29", version)
 	}
 
 	// Verify existing data is preserved
@@ -774,8 +815,8 @@ func TestMigrationV5_Idempotent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to get schema version: %v", err)
 	}
-	if version != 9 {
-		t.Errorf("schema version = %d, want 9", version)
+	if version != 29 {
+		t.Errorf("schema version = %d, want 29", version)
 	}
 
 	// Verify existing data is preserved
@@ -865,8 +906,8 @@ func TestMigrationV5_ColumnAlreadyExists(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to get schema version: %v", err)
 	}
-	if version != 9 {
-		t.Errorf("schema version = %d, want 9", version)
+	if version != 29 {
+		t.Errorf("schema version = %d, want 29", version)
 	}
 
 	// Verify closing_instructions was added
@@ -959,8 +1000,8 @@ func TestMigrationV6_ConvertsLegacyTypes(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to get schema version: %v", err)
 	}
-	if version != 9 {
-		t.Errorf("schema version = %d, want 9", version)
+	if version != 29 {
+		t.Errorf("schema version = %d, want 29", version)
 	}
 
 	// Verify legacy types converted to task
@@ -1102,8 +1143,8 @@ func TestMigrationV6_EmptyDatabase(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to get schema version: %v", err)
 	}
-	if version != 9 {
-		t.Errorf("schema version = %d, want 9", version)
+	if version != 29 {
+		t.Errorf("schema version = %d, want 29", version)
 	}
 }
 
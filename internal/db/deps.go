@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"strings"
 	"time"
-
-	"github.com/taxilian/tpg/internal/model"
 )
 
 // AddDep adds a dependency between items.
@@ -32,9 +30,19 @@ func (db *DB) AddDep(itemID, dependsOnID string) error {
 		return err
 	}
 
-	// Check for general circular dependency
-	if wouldCreateCycle(db, itemID, dependsOnID) {
-		return fmt.Errorf("cannot add dependency: %s already depends on %s (would create cycle)", dependsOnID, itemID)
+	// Check for general circular dependency, including through the hierarchy
+	cycle, err := dependencyCycle(db, itemID, dependsOnID)
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		hops := make([]string, len(cycle))
+		for i, h := range cycle {
+			hops[i] = h.String()
+		}
+		hops[0] += " (new)"
+		return fmt.Errorf("cannot add dependency: %s on %s would create a cycle:\n  %s",
+			itemID, dependsOnID, strings.Join(hops, "\n  "))
 	}
 
 	_, err = db.Exec(`
@@ -64,6 +72,23 @@ func (db *DB) AddDep(itemID, dependsOnID string) error {
 	return nil
 }
 
+// SetDepNote records why itemID depends on dependsOnID. An empty note clears it.
+func (db *DB) SetDepNote(itemID, dependsOnID, note string) error {
+	var value any
+	if note = strings.TrimSpace(note); note != "" {
+		value = note
+	}
+	result, err := db.Exec(`UPDATE deps SET note = ? WHERE item_id = ? AND depends_on = ?`, value, itemID, dependsOnID)
+	if err != nil {
+		return fmt.Errorf("failed to set dependency note: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("no dependency found: %s does not depend on %s", itemID, dependsOnID)
+	}
+	return nil
+}
+
 // RemoveDep removes a dependency between items.
 func (db *DB) RemoveDep(itemID, dependsOnID string) error {
 	result, err := db.Exec(`DELETE FROM deps WHERE item_id = ? AND depends_on = ?`, itemID, dependsOnID)
@@ -125,6 +150,25 @@ func (db *DB) GetDeps(itemID string) ([]string, error) {
 	return deps, rows.Err()
 }
 
+// GetDependents returns the IDs of items that depend on itemID.
+func (db *DB) GetDependents(itemID string) ([]string, error) {
+	rows, err := db.Query(`SELECT item_id FROM deps WHERE depends_on = ? ORDER BY item_id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependents: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var dependents []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan dependent: %w", err)
+		}
+		dependents = append(dependents, id)
+	}
+	return dependents, rows.Err()
+}
+
 // HasUnmetDeps returns true if the item has dependencies that are not done.
 func (db *DB) HasUnmetDeps(itemID string) (bool, error) {
 	var count int
@@ -155,12 +199,14 @@ type DepStatus struct {
 	Status        string
 	IsInherited   bool   // True if this dependency is inherited from an ancestor epic
 	InheritedFrom string // The ancestor epic ID from which this dependency is inherited
+	Note          string // Why the dependency exists (direct deps only)
+	Group         string // The label group this member belongs to (group deps only)
 }
 
 // GetDepStatuses returns dependencies for a single item with their statuses.
 func (db *DB) GetDepStatuses(itemID string) ([]DepStatus, error) {
 	rows, err := db.Query(`
-		SELECT d.depends_on, i.title, i.status
+		SELECT d.depends_on, i.title, i.status, COALESCE(d.note, '')
 		FROM deps d
 		JOIN items i ON d.depends_on = i.id
 		WHERE d.item_id = ?`, itemID)
@@ -172,7 +218,7 @@ func (db *DB) GetDepStatuses(itemID string) ([]DepStatus, error) {
 	var deps []DepStatus
 	for rows.Next() {
 		var dep DepStatus
-		if err := rows.Scan(&dep.ID, &dep.Title, &dep.Status); err != nil {
+		if err := rows.Scan(&dep.ID, &dep.Title, &dep.Status, &dep.Note); err != nil {
 			return nil, fmt.Errorf("failed to scan dependency status: %w", err)
 		}
 		deps = append(deps, dep)
@@ -193,8 +239,8 @@ func (db *DB) GetAncestorDependencies(itemID string) ([]DepStatus, error) {
 
 	// For each ancestor, get its dependencies
 	for _, ancestor := range ancestors {
-		// Only consider epics (not intermediate tasks if any)
-		if ancestor.Type != model.ItemTypeEpic {
+		// Only consider epics and other parent types (not intermediate tasks if any)
+		if !ancestor.Type.CanHaveChildren() {
 			continue
 		}
 
@@ -225,7 +271,7 @@ func (db *DB) GetAncestorDependencies(itemID string) ([]DepStatus, error) {
 	return ancestorDeps, nil
 }
 
-// GetAllDepStatuses returns both direct and inherited dependencies for an item.
+// GetAllDepStatuses returns direct, inherited, and group dependencies for an item.
 func (db *DB) GetAllDepStatuses(itemID string) ([]DepStatus, error) {
 	// Get direct dependencies
 	directDeps, err := db.GetDepStatuses(itemID)
@@ -239,8 +285,14 @@ func (db *DB) GetAllDepStatuses(itemID string) ([]DepStatus, error) {
 		return nil, err
 	}
 
-	// Combine both
+	// Members of label groups the item or its epics wait on
+	groupDeps, err := db.GetGroupDepStatuses(itemID)
+	if err != nil {
+		return nil, err
+	}
+
 	allDeps := append(directDeps, inheritedDeps...)
+	allDeps = append(allDeps, groupDeps...)
 	return allDeps, nil
 }
 
@@ -477,36 +529,160 @@ func (db *DB) GetReverseDependencyChain(itemID string) ([]DepEdge, error) {
 	return edges, rows.Err()
 }
 
-// wouldCreateCycle checks if adding itemID -> dependsOnID would create a cycle.
-// A cycle exists if dependsOnID already depends on itemID (directly or transitively).
-func wouldCreateCycle(db *DB, itemID, dependsOnID string) bool {
-	// Use BFS to check if dependsOnID can reach itemID
-	visited := make(map[string]bool)
-	queue := []string{dependsOnID}
+// cycleHop is one step in a dependency cycle: From waits on To.
+type cycleHop struct {
+	From, To string
+	Via      string // ancestor epic for inherited deps, "" otherwise
+	Contains bool   // From is an epic that cannot finish before its child To
+}
+
+func (h cycleHop) String() string {
+	switch {
+	case h.Contains:
+		return fmt.Sprintf("%s contains %s", h.From, h.To)
+	case h.Via != "":
+		return fmt.Sprintf("%s depends on %s (inherited from %s)", h.From, h.To, h.Via)
+	default:
+		return fmt.Sprintf("%s depends on %s", h.From, h.To)
+	}
+}
+
+// dependencyCycle returns the path that adding itemID -> dependsOnID would
+// close into a cycle, or nil if there is none. See depGraph.cycle.
+func dependencyCycle(db *DB, itemID, dependsOnID string) ([]cycleHop, error) {
+	g, err := loadDepGraph(db)
+	if err != nil {
+		return nil, err
+	}
+	return g.cycle(itemID, dependsOnID), nil
+}
+
+// depGraph is the dependency graph and hierarchy, loaded once so that
+// callers checking many deps (such as a database merge) don't reload it for
+// each one.
+type depGraph struct {
+	deps     map[string][]string // item -> the items it depends on
+	parent   map[string]string
+	children map[string][]string
+}
+
+// loadDepGraph reads every dependency and parent link.
+func loadDepGraph(db *DB) (*depGraph, error) {
+	g := &depGraph{
+		deps:     make(map[string][]string),
+		parent:   make(map[string]string),
+		children: make(map[string][]string),
+	}
+	if err := g.loadDeps(db); err != nil {
+		return nil, err
+	}
+	if err := g.loadHierarchy(db); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
 
+func (g *depGraph) loadDeps(db *DB) error {
+	rows, err := db.Query(`SELECT item_id, depends_on FROM deps ORDER BY item_id, depends_on`)
+	if err != nil {
+		return fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var from, to string
+		if err := rows.Scan(&from, &to); err != nil {
+			return fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		g.addDep(from, to)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	return nil
+}
+
+func (g *depGraph) loadHierarchy(db *DB) error {
+	rows, err := db.Query(`SELECT id, parent_id FROM items WHERE parent_id IS NOT NULL ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to load hierarchy: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id, parentID string
+		if err := rows.Scan(&id, &parentID); err != nil {
+			return fmt.Errorf("failed to scan hierarchy: %w", err)
+		}
+		g.parent[id] = parentID
+		g.children[parentID] = append(g.children[parentID], id)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load hierarchy: %w", err)
+	}
+	return nil
+}
+
+// addDep records itemID -> dependsOnID, e.g. once it has been inserted.
+func (g *depGraph) addDep(itemID, dependsOnID string) {
+	g.deps[itemID] = append(g.deps[itemID], dependsOnID)
+}
+
+// cycle returns the path that adding itemID -> dependsOnID would close into
+// a cycle, or nil if there is none. Besides explicit deps it follows the
+// implicit edges readiness uses: tasks inherit their ancestor epics' deps,
+// and an epic can't be done before its children. Reaching any descendant of
+// itemID counts too, since descendants inherit the new dep.
+func (g *depGraph) cycle(itemID, dependsOnID string) []cycleHop {
+	// The new dep applies to itemID and, by inheritance, to its whole subtree.
+	targets := map[string]bool{itemID: true}
+	for queue := []string{itemID}; len(queue) > 0; queue = queue[1:] {
+		for _, c := range g.children[queue[0]] {
+			if !targets[c] {
+				targets[c] = true
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	prev := map[string]cycleHop{}
+	visited := map[string]bool{dependsOnID: true}
+	queue := []string{dependsOnID}
 	for len(queue) > 0 {
 		current := queue[0]
 		queue = queue[1:]
 
-		if visited[current] {
-			continue
+		if targets[current] {
+			var path []cycleHop
+			for n := current; n != dependsOnID; n = prev[n].From {
+				path = append([]cycleHop{prev[n]}, path...)
+			}
+			path = append([]cycleHop{{From: itemID, To: dependsOnID}}, path...)
+			if current != itemID {
+				path = append(path, cycleHop{From: current, To: dependsOnID, Via: itemID})
+			}
+			return path
 		}
-		visited[current] = true
 
-		// If we can reach itemID from dependsOnID, adding itemID -> dependsOnID creates cycle
-		if current == itemID {
-			return true
+		var next []cycleHop
+		for _, d := range g.deps[current] {
+			next = append(next, cycleHop{From: current, To: d})
 		}
-
-		// Get all items that current depends on
-		deps, err := db.GetDeps(current)
-		if err != nil {
-			continue // Skip on error, conservative approach
+		for a := g.parent[current]; a != ""; a = g.parent[a] {
+			for _, d := range g.deps[a] {
+				next = append(next, cycleHop{From: current, To: d, Via: a})
+			}
+		}
+		for _, c := range g.children[current] {
+			next = append(next, cycleHop{From: current, To: c, Contains: true})
+		}
+		for _, hop := range next {
+			if !visited[hop.To] {
+				visited[hop.To] = true
+				prev[hop.To] = hop
+				queue = append(queue, hop.To)
+			}
 		}
-		queue = append(queue, deps...)
 	}
-
-	return false
+	return nil
 }
 
 // checkParentChildCycle specifically checks for parent-child circular dependencies.
@@ -400,6 +400,91 @@ func TestAddDep_ParentChildCycle(t *testing.T) {
 	}
 }
 
+func TestAddDep_CyclePath(t *testing.T) {
+	db := setupTestDB(t)
+
+	taskA := createTestItem(t, db, "Task A")
+	taskB := createTestItem(t, db, "Task B")
+	taskC := createTestItem(t, db, "Task C")
+	if err := db.AddDep(taskA.ID, taskB.ID); err != nil {
+		t.Fatalf("failed to add dep: %v", err)
+	}
+	if err := db.AddDep(taskB.ID, taskC.ID); err != nil {
+		t.Fatalf("failed to add dep: %v", err)
+	}
+
+	err := db.AddDep(taskC.ID, taskA.ID)
+	if err == nil {
+		t.Fatal("expected cycle error")
+	}
+	for _, hop := range []string{
+		taskC.ID + " depends on " + taskA.ID + " (new)",
+		taskA.ID + " depends on " + taskB.ID,
+		taskB.ID + " depends on " + taskC.ID,
+	} {
+		if !strings.Contains(err.Error(), hop) {
+			t.Errorf("expected path to include %q, got: %v", hop, err)
+		}
+	}
+}
+
+func TestAddDep_CycleThroughInheritance(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	child := createTestItem(t, db, "Child")
+	outside := createTestItem(t, db, "Outside")
+	if err := db.SetParent(child.ID, epic.ID); err != nil {
+		t.Fatalf("failed to set parent: %v", err)
+	}
+	if err := db.AddDep(epic.ID, outside.ID); err != nil {
+		t.Fatalf("failed to add dep: %v", err)
+	}
+
+	// child inherits the epic's dep on outside, so outside can't wait on child
+	err := db.AddDep(outside.ID, child.ID)
+	if err == nil {
+		t.Fatal("expected cycle error through inherited dependency")
+	}
+	if !strings.Contains(err.Error(), "inherited from "+epic.ID) {
+		t.Errorf("expected inherited hop in path, got: %v", err)
+	}
+
+	// outside can't wait on the epic either: the epic already waits on it
+	if err := db.AddDep(outside.ID, epic.ID); err == nil {
+		t.Fatal("expected cycle error")
+	}
+}
+
+func TestAddDep_CycleThroughEpicCompletion(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	child := createTestItem(t, db, "Child")
+	other := createTestItem(t, db, "Other")
+	if err := db.SetParent(child.ID, epic.ID); err != nil {
+		t.Fatalf("failed to set parent: %v", err)
+	}
+	if err := db.AddDep(child.ID, other.ID); err != nil {
+		t.Fatalf("failed to add dep: %v", err)
+	}
+
+	// The epic can't finish before child, which waits on other.
+	err := db.AddDep(other.ID, epic.ID)
+	if err == nil {
+		t.Fatal("expected cycle error through epic completion")
+	}
+	if !strings.Contains(err.Error(), epic.ID+" contains "+child.ID) {
+		t.Errorf("expected containment hop in path, got: %v", err)
+	}
+
+	// Unrelated deps are still fine.
+	sibling := createTestItem(t, db, "Sibling")
+	if err := db.AddDep(sibling.ID, epic.ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestAddDep_SelfDependency(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DescriptionVersion is a description an item had before it was replaced.
+type DescriptionVersion struct {
+	id          int64
+	Rev         int // 1 for the oldest version of the item
+	ItemID      string
+	Description string
+	AgentID     string
+	ReplacedAt  time.Time
+}
+
+// saveDescriptionVersion keeps old as a previous version of the item's
+// description. Empty descriptions are not kept, and neither is old when it
+// matches the newest saved version.
+func (db *DB) saveDescriptionVersion(itemID, old string) {
+	saveDescriptionVersionIn(db, itemID, old)
+}
+
+// saveDescriptionVersionIn is saveDescriptionVersion run with q, so callers
+// holding a transaction can save the version inside it.
+func saveDescriptionVersionIn(q queryExecer, itemID, old string) {
+	if old == "" {
+		return
+	}
+	var latest string
+	err := q.QueryRow(`
+		SELECT description FROM description_versions
+		WHERE item_id = ? ORDER BY id DESC LIMIT 1`, itemID).Scan(&latest)
+	if err == nil && latest == old {
+		return
+	}
+	// Non-fatal like history: a failure here must not block the edit.
+	_, _ = q.Exec(`
+		INSERT INTO description_versions (item_id, description, agent_id, created_at)
+		VALUES (?, ?, ?, ?)`,
+		itemID, old, nullString(GetAgentContext().ID), sqlTime(time.Now()))
+}
+
+// DescriptionVersions returns the previous descriptions of an item, oldest
+// first.
+func (db *DB) DescriptionVersions(itemID string) ([]DescriptionVersion, error) {
+	rows, err := db.Query(`
+		SELECT id, item_id, description, agent_id, created_at
+		FROM description_versions
+		WHERE item_id = ?
+		ORDER BY id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query description versions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var versions []DescriptionVersion
+	for rows.Next() {
+		var v DescriptionVersion
+		var agentID sql.NullString
+		if err := rows.Scan(&v.id, &v.ItemID, &v.Description, &agentID, &v.ReplacedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan description version: %w", err)
+		}
+		v.Rev = len(versions) + 1
+		v.AgentID = agentID.String
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// GetDescriptionVersion returns revision rev of an item's previous
+// descriptions, as numbered by DescriptionVersions.
+func (db *DB) GetDescriptionVersion(itemID string, rev int) (*DescriptionVersion, error) {
+	versions, err := db.DescriptionVersions(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if rev < 1 || rev > len(versions) {
+		return nil, fmt.Errorf("%s has no description revision %d (use 'tpg desc history %s' to list them)", itemID, rev, itemID)
+	}
+	return &versions[rev-1], nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestDescriptionVersions(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Task")
+	t.Setenv("AGENT_ID", "agent-1")
+
+	// The empty starting description is not kept
+	if err := db.SetDescription(item.ID, "Requirements v1"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+	if err := db.AppendDescription(item.ID, "Extra note"); err != nil {
+		t.Fatalf("AppendDescription: %v", err)
+	}
+	if err := db.SetDescription(item.ID, "Overwritten"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+	// Setting the same text again keeps nothing new
+	if err := db.SetDescription(item.ID, "Overwritten"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+
+	versions, err := db.DescriptionVersions(item.ID)
+	if err != nil {
+		t.Fatalf("DescriptionVersions: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("got %d versions, want 2: %+v", len(versions), versions)
+	}
+	if versions[0].Rev != 1 || versions[0].Description != "Requirements v1" || versions[0].AgentID != "agent-1" {
+		t.Errorf("version 1 = %+v", versions[0])
+	}
+	if versions[1].Rev != 2 || versions[1].Description != "Requirements v1\n\nExtra note" {
+		t.Errorf("version 2 = %+v", versions[1])
+	}
+
+	v, err := db.GetDescriptionVersion(item.ID, 1)
+	if err != nil || v.Description != "Requirements v1" {
+		t.Errorf("GetDescriptionVersion(1) = %+v, %v", v, err)
+	}
+	if _, err := db.GetDescriptionVersion(item.ID, 3); err == nil {
+		t.Error("expected an error for a missing revision")
+	}
+
+	if err := db.DeleteItem(item.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if versions, _ := db.DescriptionVersions(item.ID); len(versions) != 0 {
+		t.Errorf("versions left after delete: %+v", versions)
+	}
+}
+
+func TestRedactItemScrubsDescriptionVersions(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Leaky")
+	if err := db.SetDescription(item.ID, "token sk-abc123"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+	if err := db.SetDescription(item.ID, "no secrets here"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+
+	result, err := db.RedactItem(item.ID, []*regexp.Regexp{regexp.MustCompile(`sk-[a-z0-9]+`)}, false)
+	if err != nil {
+		t.Fatalf("RedactItem: %v", err)
+	}
+	if result.Versions != 1 {
+		t.Errorf("redacted %d version matches, want 1", result.Versions)
+	}
+	versions, _ := db.DescriptionVersions(item.ID)
+	if len(versions) != 1 || versions[0].Description != "token [REDACTED]" {
+		t.Errorf("versions = %+v", versions)
+	}
+}
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// ItemChange describes how an item differs between two databases.
+type ItemChange struct {
+	ID        string
+	Title     string
+	OldStatus model.Status
+	NewStatus model.Status
+	Fields    []string // changed fields other than status, e.g. "title", "priority"
+}
+
+// StatusChanged reports whether the item's status differs.
+func (c ItemChange) StatusChanged() bool {
+	return c.OldStatus != c.NewStatus
+}
+
+// DatabaseDiff lists the differences between two databases.
+type DatabaseDiff struct {
+	Created      []model.Item
+	Deleted      []model.Item
+	Changed      []ItemChange
+	NewLearnings []model.Learning
+}
+
+// IsEmpty reports whether the databases have no differences.
+func (d *DatabaseDiff) IsEmpty() bool {
+	return len(d.Created) == 0 && len(d.Deleted) == 0 && len(d.Changed) == 0 && len(d.NewLearnings) == 0
+}
+
+// ResolveBackupPath returns path if it exists, otherwise the backup with that
+// name in the backups directory (as shown by 'tpg backups').
+func ResolveBackupPath(path string) (string, error) {
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if dir, err := BackupPath(); err == nil {
+		candidate := filepath.Join(dir, path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("backup file not found: %s (use 'tpg backups' to list backups)", path)
+}
+
+// OpenSnapshot opens a copy of a backup file for reading, so that inspecting
+// it never modifies the backup itself. The returned cleanup function closes the
+// database and removes the copy.
+func OpenSnapshot(path string) (*DB, func(), error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer src.Close()
+
+	dir, err := os.MkdirTemp("", "tpg-snapshot-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanupDir := func() { _ = os.RemoveAll(dir) }
+
+	copyPath := filepath.Join(dir, filepath.Base(path))
+	dst, err := os.Create(copyPath)
+	if err != nil {
+		cleanupDir()
+		return nil, nil, fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		cleanupDir()
+		return nil, nil, fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		cleanupDir()
+		return nil, nil, fmt.Errorf("failed to copy backup: %w", err)
+	}
+
+	snapshot, err := Open(copyPath)
+	if err != nil {
+		cleanupDir()
+		return nil, nil, err
+	}
+	if ok, err := snapshot.tableExists("items"); err != nil || !ok {
+		_ = snapshot.Close()
+		cleanupDir()
+		return nil, nil, fmt.Errorf("%s is not a tpg database", path)
+	}
+	return snapshot, func() {
+		_ = snapshot.Close()
+		cleanupDir()
+	}, nil
+}
+
+// DiffDatabases compares two databases, typically a backup (before) and the
+// current database (after). Only columns present in every schema version are
+// compared, so backups from older versions can be diffed too.
+func DiffDatabases(before, after *DB) (*DatabaseDiff, error) {
+	oldItems, err := before.snapshotItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read items: %w", err)
+	}
+	newItems, err := after.snapshotItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read items: %w", err)
+	}
+
+	diff := &DatabaseDiff{}
+	for id, n := range newItems {
+		o, ok := oldItems[id]
+		if !ok {
+			diff.Created = append(diff.Created, n)
+			continue
+		}
+		change := ItemChange{ID: id, Title: n.Title, OldStatus: o.Status, NewStatus: n.Status}
+		if o.Title != n.Title {
+			change.Fields = append(change.Fields, "title")
+		}
+		if o.Type != n.Type {
+			change.Fields = append(change.Fields, "type")
+		}
+		if o.Priority != n.Priority {
+			change.Fields = append(change.Fields, "priority")
+		}
+		if parentOf(o) != parentOf(n) {
+			change.Fields = append(change.Fields, "parent")
+		}
+		if o.Description != n.Description {
+			change.Fields = append(change.Fields, "description")
+		}
+		if o.Results != n.Results {
+			change.Fields = append(change.Fields, "results")
+		}
+		if change.StatusChanged() || len(change.Fields) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for id, o := range oldItems {
+		if _, ok := newItems[id]; !ok {
+			diff.Deleted = append(diff.Deleted, o)
+		}
+	}
+
+	oldLearnings, err := before.snapshotLearnings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read learnings: %w", err)
+	}
+	newLearnings, err := after.snapshotLearnings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read learnings: %w", err)
+	}
+	for id, l := range newLearnings {
+		if _, ok := oldLearnings[id]; !ok {
+			diff.NewLearnings = append(diff.NewLearnings, l)
+		}
+	}
+
+	sort.Slice(diff.Created, func(i, j int) bool { return diff.Created[i].ID < diff.Created[j].ID })
+	sort.Slice(diff.Deleted, func(i, j int) bool { return diff.Deleted[i].ID < diff.Deleted[j].ID })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].ID < diff.Changed[j].ID })
+	sort.Slice(diff.NewLearnings, func(i, j int) bool { return diff.NewLearnings[i].ID < diff.NewLearnings[j].ID })
+	return diff, nil
+}
+
+func parentOf(item model.Item) string {
+	if item.ParentID == nil {
+		return ""
+	}
+	return *item.ParentID
+}
+
+// snapshotItems loads the comparable fields of every item, keyed by ID.
+func (db *DB) snapshotItems() (map[string]model.Item, error) {
+	cols := []string{"id", "type", "title", "COALESCE(description, '')", "status", "COALESCE(priority, 0)", "parent_id"}
+	hasResults, err := db.columnExists("items", "results")
+	if err != nil {
+		return nil, err
+	}
+	if hasResults {
+		cols = append(cols, "COALESCE(results, '')")
+	}
+
+	rows, err := db.Query("SELECT " + strings.Join(cols, ", ") + " FROM items")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[string]model.Item)
+	for rows.Next() {
+		var item model.Item
+		var parentID sql.NullString
+		dest := []any{&item.ID, &item.Type, &item.Title, &item.Description, &item.Status, &item.Priority, &parentID}
+		if hasResults {
+			dest = append(dest, &item.Results)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if parentID.Valid {
+			item.ParentID = &parentID.String
+		}
+		items[item.ID] = item
+	}
+	return items, rows.Err()
+}
+
+// snapshotLearnings loads every learning's ID and summary, keyed by ID.
+func (db *DB) snapshotLearnings() (map[string]model.Learning, error) {
+	learnings := make(map[string]model.Learning)
+	if ok, err := db.tableExists("learnings"); err != nil || !ok {
+		return learnings, err
+	}
+
+	rows, err := db.Query("SELECT id, project, summary FROM learnings")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l model.Learning
+		if err := rows.Scan(&l.ID, &l.Project, &l.Summary); err != nil {
+			return nil, err
+		}
+		learnings[l.ID] = l
+	}
+	return learnings, rows.Err()
+}
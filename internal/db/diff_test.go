@@ -0,0 +1,81 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestDiffDatabases(t *testing.T) {
+	db := setupTestDB(t)
+
+	kept := createTestItem(t, db, "Kept")
+	removed := createTestItem(t, db, "Removed")
+	renamed := createTestItem(t, db, "Old title")
+
+	backupPath := filepath.Join(t.TempDir(), "before.db")
+	if _, err := db.Exec(fmt.Sprintf("VACUUM INTO '%s'", backupPath)); err != nil {
+		t.Fatalf("VACUUM INTO: %v", err)
+	}
+
+	added := createTestItem(t, db, "Added")
+	if err := db.UpdateStatus(kept.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := db.SetTitle(renamed.ID, "New title"); err != nil {
+		t.Fatalf("SetTitle: %v", err)
+	}
+	if err := db.DeleteItem(removed.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	learning := &model.Learning{ID: model.GenerateLearningID(), Project: "test", Summary: "Something learned"}
+	if err := db.CreateLearning(learning); err != nil {
+		t.Fatalf("CreateLearning: %v", err)
+	}
+
+	before, cleanup, err := OpenSnapshot(backupPath)
+	if err != nil {
+		t.Fatalf("OpenSnapshot: %v", err)
+	}
+	defer cleanup()
+
+	diff, err := DiffDatabases(before, db)
+	if err != nil {
+		t.Fatalf("DiffDatabases: %v", err)
+	}
+
+	if len(diff.Created) != 1 || diff.Created[0].ID != added.ID {
+		t.Errorf("expected %s created, got %+v", added.ID, diff.Created)
+	}
+	if len(diff.Deleted) != 1 || diff.Deleted[0].ID != removed.ID {
+		t.Errorf("expected %s deleted, got %+v", removed.ID, diff.Deleted)
+	}
+	if len(diff.NewLearnings) != 1 || diff.NewLearnings[0].ID != learning.ID {
+		t.Errorf("expected new learning %s, got %+v", learning.ID, diff.NewLearnings)
+	}
+
+	changes := map[string]ItemChange{}
+	for _, c := range diff.Changed {
+		changes[c.ID] = c
+	}
+	if c, ok := changes[kept.ID]; !ok || c.OldStatus != model.StatusOpen || c.NewStatus != model.StatusInProgress {
+		t.Errorf("expected status change for %s, got %+v", kept.ID, c)
+	}
+	if c, ok := changes[renamed.ID]; !ok || len(c.Fields) != 1 || c.Fields[0] != "title" {
+		t.Errorf("expected title change for %s, got %+v", renamed.ID, c)
+	}
+	if len(diff.Changed) != 2 {
+		t.Errorf("expected 2 changed items, got %d", len(diff.Changed))
+	}
+
+	// Diffing a database against itself finds nothing
+	same, err := DiffDatabases(before, before)
+	if err != nil {
+		t.Fatalf("DiffDatabases: %v", err)
+	}
+	if !same.IsEmpty() {
+		t.Errorf("expected no differences, got %+v", same)
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EpicHealth summarizes how well an open epic is moving, for spotting
+// neglected epics without opening each plan.
+type EpicHealth struct {
+	Epic         model.Item
+	Tasks        int // descendant tasks, excluding child epics
+	Remaining    int // tasks not done or canceled
+	InProgress   int
+	Stale        int // in progress past their stale threshold
+	Blocked      int // remaining tasks that are blocked or waiting on deps
+	Ready        int // ready tasks nobody has picked up
+	LastActivity time.Time
+	Scope        EpicScope
+}
+
+// BlockedRatio is the share of remaining tasks that cannot be worked on.
+func (h EpicHealth) BlockedRatio() float64 {
+	if h.Remaining == 0 {
+		return 0
+	}
+	return float64(h.Blocked) / float64(h.Remaining)
+}
+
+// IdleDays is the number of whole days since anything in the epic changed.
+func (h EpicHealth) IdleDays(now time.Time) int {
+	return int(now.Sub(h.LastActivity).Hours() / 24)
+}
+
+// Score rates the epic from 0 (neglected) to 100 (healthy). Stale tasks,
+// blocked work, idle days, and ready work nobody has started all count
+// against it.
+func (h EpicHealth) Score(now time.Time) int {
+	score := 100
+	score -= min(h.Stale*15, 30)
+	score -= int(h.BlockedRatio() * 30)
+	if idle := h.IdleDays(now); idle > 2 {
+		score -= min((idle-2)*5, 30)
+	}
+	if h.Ready > 0 && h.InProgress == 0 {
+		score -= 20
+	}
+	return max(score, 0)
+}
+
+// Problems lists what lowers the score, e.g. "2 stale", "idle 5d".
+func (h EpicHealth) Problems(now time.Time) []string {
+	var problems []string
+	if h.Stale > 0 {
+		problems = append(problems, fmt.Sprintf("%d stale", h.Stale))
+	}
+	if h.Blocked > 0 {
+		problems = append(problems, fmt.Sprintf("%d/%d blocked", h.Blocked, h.Remaining))
+	}
+	if idle := h.IdleDays(now); idle > 2 {
+		problems = append(problems, fmt.Sprintf("idle %dd", idle))
+	}
+	if h.Ready > 0 && h.InProgress == 0 {
+		problems = append(problems, fmt.Sprintf("%d ready, none started", h.Ready))
+	}
+	return problems
+}
+
+// EpicHealthReport returns the health of every open epic in project (all
+// projects when empty), in ID order.
+func (db *DB) EpicHealthReport(project string, now time.Time) ([]EpicHealth, error) {
+	query := fmt.Sprintf(`SELECT %s FROM items
+		WHERE type = 'epic' AND status NOT IN ('done', 'canceled')`, itemSelectColumns)
+	var args []any
+	if project != "" {
+		query += " AND project = ?"
+		args = append(args, project)
+	}
+	query += " ORDER BY id"
+	epics, err := db.queryItems(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []EpicHealth
+	for _, epic := range epics {
+		h, err := db.epicHealth(epic, now)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, h)
+	}
+	return report, nil
+}
+
+// epicHealth computes the health of one epic.
+func (db *DB) epicHealth(epic model.Item, now time.Time) (EpicHealth, error) {
+	h := EpicHealth{Epic: epic, LastActivity: epic.UpdatedAt}
+	descendants, err := db.GetDescendants(epic.ID)
+	if err != nil {
+		return h, err
+	}
+	if h.Scope, err = db.EpicScope(epic.ID); err != nil {
+		return h, err
+	}
+	ready, err := db.ReadyItemsForEpic(epic.ID, SortOrder{})
+	if err != nil {
+		return h, err
+	}
+	readyIDs := make(map[string]bool, len(ready))
+	for _, r := range ready {
+		readyIDs[r.ID] = true
+	}
+
+	ids := []any{epic.ID}
+	for _, d := range descendants {
+		ids = append(ids, d.ID)
+		if d.UpdatedAt.After(h.LastActivity) {
+			h.LastActivity = d.UpdatedAt
+		}
+		if d.Type == model.ItemTypeEpic {
+			continue
+		}
+		h.Tasks++
+		switch d.Status {
+		case model.StatusDone, model.StatusCanceled:
+			continue
+		case model.StatusInProgress:
+			h.InProgress++
+			if now.Sub(d.UpdatedAt) > model.StaleThresholdFor(d) {
+				h.Stale++
+			}
+		case model.StatusBlocked:
+			h.Blocked++
+		case model.StatusOpen:
+			if readyIDs[d.ID] {
+				h.Ready++
+			} else {
+				h.Blocked++
+			}
+		}
+		h.Remaining++
+	}
+
+	// Logs count as activity even when they don't touch the item itself
+	var lastLog time.Time
+	err = db.QueryRow(fmt.Sprintf(`SELECT created_at FROM logs WHERE item_id IN (?%s)
+		ORDER BY created_at DESC LIMIT 1`, strings.Repeat(", ?", len(ids)-1)), ids...).Scan(&lastLog)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return h, fmt.Errorf("failed to get last log: %w", err)
+	}
+	if lastLog.After(h.LastActivity) {
+		h.LastActivity = lastLog
+	}
+	return h, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestEpicHealthReport(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	old := now.Add(-10 * 24 * time.Hour)
+	add := func(id string, typ model.ItemType, status model.Status, parent string, updated time.Time) {
+		t.Helper()
+		item := &model.Item{ID: id, Project: "test", Type: typ, Title: id, Status: status,
+			Priority: 2, CreatedAt: old, UpdatedAt: updated}
+		if parent != "" {
+			item.ParentID = &parent
+		}
+		if err := db.CreateItem(item); err != nil {
+			t.Fatalf("CreateItem(%s): %v", id, err)
+		}
+	}
+
+	// A neglected epic: idle for days, one task blocked, one ready but unstarted
+	add("ep-old", model.ItemTypeEpic, model.StatusOpen, "", old)
+	add("ts-first", model.ItemTypeTask, model.StatusOpen, "ep-old", old)
+	add("ts-second", model.ItemTypeTask, model.StatusOpen, "ep-old", old)
+	add("ts-shipped", model.ItemTypeTask, model.StatusDone, "ep-old", old)
+	if err := db.AddDep("ts-second", "ts-first"); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	// A healthy epic with fresh work in progress
+	add("ep-new", model.ItemTypeEpic, model.StatusInProgress, "", now)
+	add("ts-busy", model.ItemTypeTask, model.StatusInProgress, "ep-new", now)
+	add("ts-next", model.ItemTypeTask, model.StatusOpen, "ep-new", now)
+	// Closed epics are not reported
+	add("ep-gone", model.ItemTypeEpic, model.StatusDone, "", old)
+
+	report, err := db.EpicHealthReport("test", now)
+	if err != nil {
+		t.Fatalf("EpicHealthReport: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("got %d epics, want 2", len(report))
+	}
+	byID := map[string]EpicHealth{}
+	for _, h := range report {
+		byID[h.Epic.ID] = h
+	}
+
+	stale := byID["ep-old"]
+	if stale.Tasks != 3 || stale.Remaining != 2 || stale.Blocked != 1 || stale.Ready != 1 || stale.InProgress != 0 {
+		t.Errorf("ep-old = %+v", stale)
+	}
+	if got := stale.IdleDays(now); got != 10 {
+		t.Errorf("ep-old idle = %d days, want 10", got)
+	}
+	healthy := byID["ep-new"]
+	if healthy.Score(now) != 100 {
+		t.Errorf("ep-new score = %d, want 100 (problems: %v)", healthy.Score(now), healthy.Problems(now))
+	}
+	if stale.Score(now) >= healthy.Score(now) || len(stale.Problems(now)) != 3 {
+		t.Errorf("ep-old score = %d, problems %v; want it flagged", stale.Score(now), stale.Problems(now))
+	}
+
+	// A log counts as activity
+	if err := db.AddLog("ts-first", "looked into it"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	report, err = db.EpicHealthReport("test", now)
+	if err != nil {
+		t.Fatalf("EpicHealthReport: %v", err)
+	}
+	for _, h := range report {
+		if h.Epic.ID == "ep-old" && h.IdleDays(now) != 0 {
+			t.Errorf("ep-old idle = %d days after a log, want 0", h.IdleDays(now))
+		}
+	}
+}
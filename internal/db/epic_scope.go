@@ -0,0 +1,89 @@
+package db
+
+import (
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EpicScope tracks how much an epic grew after work on it began, to catch
+// runaway planning.
+type EpicScope struct {
+	StartedAt time.Time // When the epic or one of its tasks was first started; zero if never
+	Initial   int       // Tasks in the epic when it started
+	Added     int       // Tasks created in or moved into the epic since
+}
+
+// Started reports whether work on the epic has begun.
+func (s EpicScope) Started() bool {
+	return !s.StartedAt.IsZero()
+}
+
+// GrowthPct is Added as a percentage of Initial, or 0 when the epic started
+// empty.
+func (s EpicScope) GrowthPct() float64 {
+	if s.Initial == 0 {
+		return 0
+	}
+	return float64(s.Added) * 100 / float64(s.Initial)
+}
+
+// EpicScope returns the scope growth of an epic: its descendant tasks
+// (excluding child epics) split into those present when the epic started and
+// those that joined later, by creation or by a change of parent.
+func (db *DB) EpicScope(epicID string) (EpicScope, error) {
+	var scope EpicScope
+	descendants, err := db.GetDescendants(epicID)
+	if err != nil {
+		return scope, err
+	}
+
+	ids := []string{epicID}
+	for _, d := range descendants {
+		ids = append(ids, d.ID)
+	}
+	started := make(map[string]time.Time, len(ids))
+	joined := make(map[string]time.Time, len(descendants))
+	for _, id := range ids {
+		events, err := db.GetHistory(HistoryQueryOptions{
+			ItemID:     id,
+			EventTypes: []string{EventTypeStatusChanged, EventTypeParentChanged},
+			Limit:      historyScanLimit,
+		})
+		if err != nil {
+			return scope, err
+		}
+		// Events are newest first
+		for _, e := range events {
+			switch {
+			case e.EventType == EventTypeStatusChanged && e.Changes["new"] == string(model.StatusInProgress):
+				started[id] = e.CreatedAt
+			case e.EventType == EventTypeParentChanged && joined[id].IsZero():
+				joined[id] = e.CreatedAt
+			}
+		}
+	}
+	for _, at := range started {
+		if scope.StartedAt.IsZero() || at.Before(scope.StartedAt) {
+			scope.StartedAt = at
+		}
+	}
+
+	for _, d := range descendants {
+		if d.Type == model.ItemTypeEpic {
+			continue
+		}
+		at := d.CreatedAt
+		if joined[d.ID].After(at) {
+			at = joined[d.ID]
+		}
+		// Tasks added in the same second as the first start (such as the
+		// task whose start it was) count as initial scope.
+		if scope.Started() && at.Truncate(time.Second).After(scope.StartedAt.Truncate(time.Second)) {
+			scope.Added++
+		} else {
+			scope.Initial++
+		}
+	}
+	return scope, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestEpicScope(t *testing.T) {
+	db := setupTestDB(t)
+
+	createCancelTestItem(t, db, "ep-scope", model.ItemTypeEpic, "", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-first", model.ItemTypeTask, "ep-scope", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-second", model.ItemTypeTask, "ep-scope", model.StatusOpen)
+	createCancelTestItem(t, db, "ts-moved", model.ItemTypeTask, "", model.StatusOpen)
+
+	scope, err := db.EpicScope("ep-scope")
+	if err != nil {
+		t.Fatalf("EpicScope: %v", err)
+	}
+	if scope.Started() || scope.Initial != 2 || scope.Added != 0 {
+		t.Errorf("before start: %+v, want not started with 2 initial tasks", scope)
+	}
+
+	if err := db.UpdateStatus("ts-first", model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatal(err)
+	}
+	// Backdate everything so far, so later additions come after the start.
+	past := sqlTime(time.Now().Add(-time.Hour))
+	if _, err := db.Exec(`UPDATE history SET created_at = ?`, past); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE items SET created_at = ?`, past); err != nil {
+		t.Fatal(err)
+	}
+
+	createCancelTestItem(t, db, "ts-new", model.ItemTypeTask, "ep-scope", model.StatusOpen)
+	if err := db.SetParent("ts-moved", "ep-scope"); err != nil {
+		t.Fatal(err)
+	}
+
+	scope, err = db.EpicScope("ep-scope")
+	if err != nil {
+		t.Fatalf("EpicScope: %v", err)
+	}
+	if !scope.Started() || scope.Initial != 2 || scope.Added != 2 {
+		t.Errorf("after growth: %+v, want started with 2 initial and 2 added", scope)
+	}
+	if scope.GrowthPct() != 100 {
+		t.Errorf("GrowthPct = %v, want 100", scope.GrowthPct())
+	}
+}
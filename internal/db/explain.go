@@ -0,0 +1,194 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// Reasons an item is not ready, as reported by ExplainReadiness.
+const (
+	NotReadyStatus       = "status"        // not open
+	NotReadyType         = "type"          // epics and other parent types are never ready themselves
+	NotReadyDep          = "dep"           // a direct dependency is not done
+	NotReadyInheritedDep = "inherited_dep" // an ancestor epic has a dependency that is not done
+	NotReadyClaimed      = "claimed"       // another agent is working on it
+	NotReadyGroupDep     = "group_dep"     // a task in a label group it waits on is not done
+)
+
+// NotReadyReason is one thing keeping an item out of 'tpg ready'.
+type NotReadyReason struct {
+	Kind    string
+	Message string
+	// Dep is the unmet dependency for dep, inherited_dep, and group_dep reasons.
+	Dep *DepStatus
+	// DepBlockers are the dependency's own unmet deps, i.e. what has to
+	// happen before the dependency itself can be worked on.
+	DepBlockers []DepStatus
+	// AgentID is the claiming agent for claimed reasons.
+	AgentID string
+}
+
+// Readiness explains whether an item is ready for work and why not.
+type Readiness struct {
+	Item    *model.Item
+	Ready   bool
+	Reasons []NotReadyReason
+}
+
+// ExplainReadiness reports every reason itemID would not appear in 'tpg ready'.
+// The checks mirror ReadyItemsFiltered; claims by agents other than
+// agentCtx are reported as well.
+func (db *DB) ExplainReadiness(itemID string, agentCtx AgentContext) (*Readiness, error) {
+	item, err := db.GetItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	r := &Readiness{Item: item}
+
+	if item.Type.CanHaveChildren() {
+		r.Reasons = append(r.Reasons, NotReadyReason{
+			Kind:    NotReadyType,
+			Message: fmt.Sprintf("%s is an %s; only its child tasks show up in ready", item.ID, item.Type),
+		})
+	}
+
+	switch item.Status {
+	case model.StatusOpen:
+	case model.StatusInProgress:
+		if item.AgentID != nil && *item.AgentID != "" && *item.AgentID != agentCtx.ID {
+			r.Reasons = append(r.Reasons, NotReadyReason{
+				Kind:    NotReadyClaimed,
+				Message: fmt.Sprintf("claimed by agent %s", *item.AgentID),
+				AgentID: *item.AgentID,
+			})
+		} else {
+			r.Reasons = append(r.Reasons, NotReadyReason{
+				Kind:    NotReadyStatus,
+				Message: "already in progress",
+			})
+		}
+	case model.StatusBlocked:
+		r.Reasons = append(r.Reasons, NotReadyReason{
+			Kind:    NotReadyStatus,
+			Message: "marked blocked (see 'tpg show' logs for the reason)",
+		})
+	default:
+		r.Reasons = append(r.Reasons, NotReadyReason{
+			Kind:    NotReadyStatus,
+			Message: fmt.Sprintf("status is %s", item.Status),
+		})
+	}
+
+	deps, err := db.GetDepStatuses(itemID)
+	if err != nil {
+		return nil, err
+	}
+	inherited, err := db.GetAncestorDependencies(itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range append(deps, inherited...) {
+		if dep.Status == string(model.StatusDone) {
+			continue
+		}
+		reason := NotReadyReason{Kind: NotReadyDep, Dep: &dep}
+		if dep.IsInherited {
+			reason.Kind = NotReadyInheritedDep
+			reason.Message = fmt.Sprintf("parent epic %s waits on %s %s (%s)", dep.InheritedFrom, dep.ID, dep.Title, dep.Status)
+		} else {
+			reason.Message = fmt.Sprintf("depends on %s %s (%s)", dep.ID, dep.Title, dep.Status)
+		}
+		blockers, err := db.GetDepStatuses(dep.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range blockers {
+			if b.Status != string(model.StatusDone) {
+				reason.DepBlockers = append(reason.DepBlockers, b)
+			}
+		}
+		r.Reasons = append(r.Reasons, reason)
+	}
+
+	groupDeps, err := db.GetGroupDepStatuses(itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range groupDeps {
+		if dep.Status == string(model.StatusDone) {
+			continue
+		}
+		r.Reasons = append(r.Reasons, NotReadyReason{
+			Kind:    NotReadyGroupDep,
+			Message: fmt.Sprintf("waits on %s: %s %s (%s)", dep.Group, dep.ID, dep.Title, dep.Status),
+			Dep:     &dep,
+		})
+	}
+
+	r.Ready = len(r.Reasons) == 0
+	return r, nil
+}
+
+// UnmetDep is a dependency that is not done, with the shortest chain of
+// unmet dependencies leading from it to work already in progress.
+type UnmetDep struct {
+	Dep DepStatus
+	// Chain runs from Dep's first unmet dependency to an in_progress item.
+	// It is empty when Dep is itself in progress or nothing down the line is.
+	Chain []DepStatus
+}
+
+// UnmetDeps returns itemID's direct dependencies that are not done, each
+// with the chain to the in-progress work it is waiting on, if any.
+func (db *DB) UnmetDeps(itemID string) ([]UnmetDep, error) {
+	deps, err := db.GetDepStatuses(itemID)
+	if err != nil {
+		return nil, err
+	}
+	var unmet []UnmetDep
+	for _, dep := range deps {
+		if dep.Status == string(model.StatusDone) {
+			continue
+		}
+		u := UnmetDep{Dep: dep}
+		if dep.Status != string(model.StatusInProgress) {
+			if u.Chain, err = db.chainToInProgress(dep.ID); err != nil {
+				return nil, err
+			}
+		}
+		unmet = append(unmet, u)
+	}
+	return unmet, nil
+}
+
+// chainToInProgress finds the shortest path through unmet dependencies from
+// fromID to an in_progress item, breadth first.
+func (db *DB) chainToInProgress(fromID string) ([]DepStatus, error) {
+	type node struct {
+		id   string
+		path []DepStatus
+	}
+	seen := map[string]bool{fromID: true}
+	queue := []node{{id: fromID}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		deps, err := db.GetDepStatuses(n.id)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if dep.Status == string(model.StatusDone) || seen[dep.ID] {
+				continue
+			}
+			seen[dep.ID] = true
+			path := append(append([]DepStatus(nil), n.path...), dep)
+			if dep.Status == string(model.StatusInProgress) {
+				return path, nil
+			}
+			queue = append(queue, node{id: dep.ID, path: path})
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestExplainReadiness_Ready(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Task")
+
+	r, err := db.ExplainReadiness(item.ID, AgentContext{})
+	if err != nil {
+		t.Fatalf("ExplainReadiness failed: %v", err)
+	}
+	if !r.Ready || len(r.Reasons) != 0 {
+		t.Errorf("expected ready with no reasons, got %+v", r.Reasons)
+	}
+}
+
+func TestExplainReadiness_Reasons(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	gate := createTestItem(t, db, "Epic gate")
+	root := createTestItem(t, db, "Root")
+	dep := createTestItem(t, db, "Dep")
+	item := createTestItem(t, db, "Task")
+	if err := db.SetParent(item.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	for _, d := range [][2]string{{epic.ID, gate.ID}, {item.ID, dep.ID}, {dep.ID, root.ID}} {
+		if err := db.AddDep(d[0], d[1]); err != nil {
+			t.Fatalf("AddDep failed: %v", err)
+		}
+	}
+	if err := db.UpdateStatus(item.ID, model.StatusInProgress, AgentContext{ID: "agent-other"}, true); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	r, err := db.ExplainReadiness(item.ID, AgentContext{ID: "agent-me"})
+	if err != nil {
+		t.Fatalf("ExplainReadiness failed: %v", err)
+	}
+	if r.Ready {
+		t.Fatal("expected not ready")
+	}
+	kinds := map[string]NotReadyReason{}
+	for _, reason := range r.Reasons {
+		kinds[reason.Kind] = reason
+	}
+	if c, ok := kinds[NotReadyClaimed]; !ok || c.AgentID != "agent-other" {
+		t.Errorf("expected claimed by agent-other, got %+v", r.Reasons)
+	}
+	if d, ok := kinds[NotReadyDep]; !ok || d.Dep.ID != dep.ID || len(d.DepBlockers) != 1 || d.DepBlockers[0].ID != root.ID {
+		t.Errorf("expected dep on %s blocked by %s, got %+v", dep.ID, root.ID, d)
+	}
+	if d, ok := kinds[NotReadyInheritedDep]; !ok || d.Dep.ID != gate.ID || d.Dep.InheritedFrom != epic.ID {
+		t.Errorf("expected inherited dep on %s from %s, got %+v", gate.ID, epic.ID, d)
+	}
+
+	// The claiming agent sees its own work as in progress, not claimed.
+	r, _ = db.ExplainReadiness(item.ID, AgentContext{ID: "agent-other"})
+	for _, reason := range r.Reasons {
+		if reason.Kind == NotReadyClaimed {
+			t.Error("own claim should not be reported as claimed")
+		}
+	}
+}
+
+func TestExplainReadiness_Epic(t *testing.T) {
+	db := setupTestDB(t)
+	epic := createTestEpic(t, db, "Epic", "test")
+
+	r, err := db.ExplainReadiness(epic.ID, AgentContext{})
+	if err != nil {
+		t.Fatalf("ExplainReadiness failed: %v", err)
+	}
+	if r.Ready || len(r.Reasons) != 1 || r.Reasons[0].Kind != NotReadyType {
+		t.Errorf("expected a single type reason, got %+v", r.Reasons)
+	}
+}
+
+func TestUnmetDeps_ChainToInProgress(t *testing.T) {
+	db := setupTestDB(t)
+
+	item := createTestItem(t, db, "Task")
+	dep := createTestItem(t, db, "Dep")
+	mid := createTestItem(t, db, "Mid")
+	busy := createTestItem(t, db, "Busy")
+	idle := createTestItem(t, db, "Idle")
+	for _, d := range [][2]string{{item.ID, dep.ID}, {item.ID, idle.ID}, {dep.ID, mid.ID}, {mid.ID, busy.ID}} {
+		if err := db.AddDep(d[0], d[1]); err != nil {
+			t.Fatalf("AddDep failed: %v", err)
+		}
+	}
+	if err := db.UpdateStatus(busy.ID, model.StatusInProgress, AgentContext{}, true); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	unmet, err := db.UnmetDeps(item.ID)
+	if err != nil {
+		t.Fatalf("UnmetDeps failed: %v", err)
+	}
+	if len(unmet) != 2 {
+		t.Fatalf("got %d unmet deps, want 2", len(unmet))
+	}
+	for _, u := range unmet {
+		switch u.Dep.ID {
+		case dep.ID:
+			if len(u.Chain) != 2 || u.Chain[0].ID != mid.ID || u.Chain[1].ID != busy.ID {
+				t.Errorf("chain from %s = %+v, want %s -> %s", dep.ID, u.Chain, mid.ID, busy.ID)
+			}
+		case idle.ID:
+			if len(u.Chain) != 0 {
+				t.Errorf("chain from %s = %+v, want none", idle.ID, u.Chain)
+			}
+		default:
+			t.Errorf("unexpected unmet dep %s", u.Dep.ID)
+		}
+	}
+}
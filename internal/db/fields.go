@@ -0,0 +1,128 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// ValidateFieldKey checks that a custom field key is usable on the command
+// line and in filters: non-empty, no whitespace, and no "=".
+func ValidateFieldKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("field name cannot be empty")
+	}
+	if strings.ContainsAny(key, "= \t\n") {
+		return fmt.Errorf("invalid field name %q: must not contain spaces or '='", key)
+	}
+	return nil
+}
+
+// SetField sets a custom field on an item, replacing any existing value.
+func (db *DB) SetField(itemID, key, value string) error {
+	if err := ValidateFieldKey(key); err != nil {
+		return err
+	}
+	var old *string
+	err := db.QueryRow(`SELECT value FROM item_fields WHERE item_id = ? AND key = ?`, itemID, key).Scan(&old)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read field: %w", err)
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO item_fields (item_id, key, value)
+		SELECT id, ?, ? FROM items WHERE id = ?
+		ON CONFLICT (item_id, key) DO UPDATE SET value = excluded.value`,
+		key, value, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to set field: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("item not found: %s", itemID)
+	}
+	if _, err := db.Exec(`UPDATE items SET updated_at = ? WHERE id = ?`, sqlTime(time.Now()), itemID); err != nil {
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+
+	details := map[string]any{"key": key, "value": value}
+	if old != nil {
+		details["old_value"] = *old
+	}
+	_ = db.RecordHistory(itemID, EventTypeFieldChanged, details)
+	return nil
+}
+
+// RemoveField deletes a custom field from an item.
+func (db *DB) RemoveField(itemID, key string) error {
+	result, err := db.Exec(`DELETE FROM item_fields WHERE item_id = ? AND key = ?`, itemID, key)
+	if err != nil {
+		return fmt.Errorf("failed to remove field: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("%s has no field %q", itemID, key)
+	}
+	_, _ = db.Exec(`UPDATE items SET updated_at = ? WHERE id = ?`, sqlTime(time.Now()), itemID)
+	_ = db.RecordHistory(itemID, EventTypeFieldChanged, map[string]any{"key": key, "removed": true})
+	return nil
+}
+
+// GetFields returns the custom fields of an item.
+func (db *DB) GetFields(itemID string) (map[string]string, error) {
+	rows, err := db.Query(`SELECT key, value FROM item_fields WHERE item_id = ?`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan field: %w", err)
+		}
+		fields[key] = value
+	}
+	return fields, rows.Err()
+}
+
+// PopulateItemFields fills in the Fields map for a slice of items.
+func (db *DB) PopulateItemFields(items []model.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]any, len(items))
+	placeholders := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+		placeholders[i] = "?"
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT item_id, key, value FROM item_fields
+		WHERE item_id IN (%s)`, strings.Join(placeholders, ", ")), ids...)
+	if err != nil {
+		return fmt.Errorf("failed to query item fields: %w", err)
+	}
+	defer rows.Close()
+
+	fieldMap := make(map[string]map[string]string)
+	for rows.Next() {
+		var itemID, key, value string
+		if err := rows.Scan(&itemID, &key, &value); err != nil {
+			return fmt.Errorf("failed to scan field: %w", err)
+		}
+		if fieldMap[itemID] == nil {
+			fieldMap[itemID] = make(map[string]string)
+		}
+		fieldMap[itemID][key] = value
+	}
+
+	for i := range items {
+		items[i].Fields = fieldMap[items[i].ID]
+	}
+	return rows.Err()
+}
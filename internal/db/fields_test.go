@@ -0,0 +1,101 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestSetField(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Task")
+
+	if err := db.SetField(item.ID, "reviewer", "alice"); err != nil {
+		t.Fatalf("SetField failed: %v", err)
+	}
+	if err := db.SetField(item.ID, "notes", "line one\nline two"); err != nil {
+		t.Fatalf("SetField failed: %v", err)
+	}
+	if err := db.SetField(item.ID, "reviewer", "bob"); err != nil {
+		t.Fatalf("SetField (update) failed: %v", err)
+	}
+
+	fields, err := db.GetFields(item.ID)
+	if err != nil {
+		t.Fatalf("GetFields failed: %v", err)
+	}
+	if len(fields) != 2 || fields["reviewer"] != "bob" || fields["notes"] != "line one\nline two" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+
+	if err := db.SetField("ts-missing", "reviewer", "x"); err == nil {
+		t.Error("expected error for missing item")
+	}
+	if err := db.SetField(item.ID, "bad key", "x"); err == nil {
+		t.Error("expected error for key with a space")
+	}
+
+	if err := db.RemoveField(item.ID, "reviewer"); err != nil {
+		t.Fatalf("RemoveField failed: %v", err)
+	}
+	if err := db.RemoveField(item.ID, "reviewer"); err == nil {
+		t.Error("expected error removing a field that is not set")
+	}
+}
+
+func TestListItemsFiltered_Fields(t *testing.T) {
+	db := setupTestDB(t)
+	a := createTestItem(t, db, "A")
+	b := createTestItem(t, db, "B")
+	createTestItem(t, db, "C")
+
+	for _, f := range []struct{ id, key, value string }{
+		{a.ID, "reviewer", "alice"},
+		{a.ID, "size", "s"},
+		{b.ID, "reviewer", "alice"},
+		{b.ID, "size", "l"},
+	} {
+		if err := db.SetField(f.id, f.key, f.value); err != nil {
+			t.Fatalf("SetField failed: %v", err)
+		}
+	}
+
+	items, err := db.ListItemsFiltered(ListFilter{Fields: map[string]string{"reviewer": "alice"}})
+	if err != nil {
+		t.Fatalf("ListItemsFiltered failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 items reviewed by alice, got %d", len(items))
+	}
+
+	items, err = db.ListItemsFiltered(ListFilter{Fields: map[string]string{"reviewer": "alice", "size": "l"}})
+	if err != nil {
+		t.Fatalf("ListItemsFiltered failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != b.ID {
+		t.Errorf("expected only %s, got %+v", b.ID, items)
+	}
+
+	if err := db.PopulateItemFields(items); err != nil {
+		t.Fatalf("PopulateItemFields failed: %v", err)
+	}
+	if items[0].Fields["size"] != "l" {
+		t.Errorf("fields not populated: %v", items[0].Fields)
+	}
+}
+
+func TestDeleteItem_RemovesFields(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Task")
+	if err := db.SetField(item.ID, "reviewer", "alice"); err != nil {
+		t.Fatalf("SetField failed: %v", err)
+	}
+	if err := db.DeleteItem(item.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem failed: %v", err)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM item_fields WHERE item_id = ?`, item.ID).Scan(&count); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected fields to be deleted, %d left", count)
+	}
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ForeignKeyViolation is a row whose reference points at a missing row, as
+// reported by PRAGMA foreign_key_check.
+type ForeignKeyViolation struct {
+	Table  string
+	RowID  int64
+	Parent string
+}
+
+// IntegrityProblems runs PRAGMA integrity_check and returns each problem it
+// reports. Unlike CheckIntegrity it never repairs anything.
+func (db *DB) IntegrityProblems() ([]string, error) {
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("integrity check failed: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+	return problems, nil
+}
+
+// ForeignKeyViolations runs PRAGMA foreign_key_check over every table.
+func (db *DB) ForeignKeyViolations() ([]ForeignKeyViolation, error) {
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("foreign key check failed: %w", err)
+	}
+	defer rows.Close()
+
+	var violations []ForeignKeyViolation
+	for rows.Next() {
+		var v ForeignKeyViolation
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&v.Table, &rowID, &v.Parent, &fkID); err != nil {
+			return nil, fmt.Errorf("foreign key check failed: %w", err)
+		}
+		v.RowID = rowID.Int64
+		violations = append(violations, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("foreign key check failed: %w", err)
+	}
+	return violations, nil
+}
+
+// BackupFreshness compares the newest backup with the last write to the
+// database.
+type BackupFreshness struct {
+	Latest    *BackupInfo // nil when there are no backups
+	LastWrite time.Time
+}
+
+// Behind returns how much later than the newest backup the database was
+// last written, or zero when the backup is up to date.
+func (f BackupFreshness) Behind() time.Duration {
+	if f.Latest == nil || !f.LastWrite.After(f.Latest.ModTime) {
+		return 0
+	}
+	return f.LastWrite.Sub(f.Latest.ModTime)
+}
+
+// CheckBackupFreshness reports the newest backup and when the database at
+// path (including its write-ahead log) was last written.
+func CheckBackupFreshness(path string) (BackupFreshness, error) {
+	var f BackupFreshness
+	for _, p := range []string{path, path + "-wal"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return f, err
+		}
+		if info.ModTime().After(f.LastWrite) {
+			f.LastWrite = info.ModTime()
+		}
+	}
+	backups, err := ListBackups()
+	if err != nil {
+		return f, err
+	}
+	if len(backups) > 0 {
+		f.Latest = &backups[0]
+	}
+	return f, nil
+}
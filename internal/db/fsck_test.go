@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIntegrityProblems_Clean(t *testing.T) {
+	db := setupTestDB(t)
+	createTestItem(t, db, "Task")
+
+	problems, err := db.IntegrityProblems()
+	if err != nil {
+		t.Fatalf("IntegrityProblems: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+func TestForeignKeyViolations(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Task")
+
+	if violations, err := db.ForeignKeyViolations(); err != nil || len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v, %v", violations, err)
+	}
+
+	// Sneak in a dependency on a missing item with enforcement off
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatalf("disable foreign keys: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "INSERT INTO deps (item_id, depends_on) VALUES (?, ?)", item.ID, "ts-missing"); err != nil {
+		t.Fatalf("insert dep: %v", err)
+	}
+	_, _ = conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	_ = conn.Close()
+
+	violations, err := db.ForeignKeyViolations()
+	if err != nil {
+		t.Fatalf("ForeignKeyViolations: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Table != "deps" || violations[0].Parent != "items" {
+		t.Errorf("violations = %+v, want one in deps referencing items", violations)
+	}
+}
+
+func TestBackupFreshnessBehind(t *testing.T) {
+	now := time.Now()
+	f := BackupFreshness{LastWrite: now}
+	if f.Behind() != 0 {
+		t.Errorf("no backups: Behind() = %v, want 0", f.Behind())
+	}
+	f.Latest = &BackupInfo{ModTime: now.Add(-time.Hour)}
+	if f.Behind() != time.Hour {
+		t.Errorf("Behind() = %v, want 1h", f.Behind())
+	}
+	f.Latest.ModTime = now.Add(time.Minute)
+	if f.Behind() != 0 {
+		t.Errorf("backup newer than last write: Behind() = %v, want 0", f.Behind())
+	}
+}
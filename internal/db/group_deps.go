@@ -0,0 +1,202 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// GroupDep makes an item wait for every task carrying a label, either in the
+// item's whole project or only under one epic. Members are looked up each
+// time readiness is checked, so tasks added to the group later count too.
+type GroupDep struct {
+	ID        int64
+	ItemID    string
+	Label     string
+	EpicID    string // "" for the whole project
+	CreatedAt time.Time
+}
+
+// String describes the group, e.g. "label migration in ep-abc".
+func (g GroupDep) String() string {
+	if g.EpicID == "" {
+		return fmt.Sprintf("label %s in the project", g.Label)
+	}
+	return fmt.Sprintf("label %s in %s", g.Label, g.EpicID)
+}
+
+// AddGroupDep makes itemID wait until every task labeled label (under
+// epicID, or anywhere in the item's project when epicID is empty) is done.
+// Canceled tasks drop out of the group. Adding an existing group dependency
+// does nothing.
+func (db *DB) AddGroupDep(itemID, label, epicID string) error {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return fmt.Errorf("label cannot be empty")
+	}
+	if _, err := db.GetItem(itemID); err != nil {
+		return err
+	}
+	if epicID != "" {
+		epic, err := db.GetItem(epicID)
+		if err != nil {
+			return err
+		}
+		if !epic.Type.CanHaveChildren() {
+			return fmt.Errorf("%s is not an epic (type: %s)", epicID, epic.Type)
+		}
+	}
+	_, err := db.Exec(`INSERT OR IGNORE INTO group_deps (item_id, label, epic_id, created_at) VALUES (?, ?, ?, ?)`,
+		itemID, label, epicID, sqlTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to add group dependency: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupDep deletes a group dependency. It reports false if there was none.
+func (db *DB) RemoveGroupDep(itemID, label, epicID string) (bool, error) {
+	res, err := db.Exec(`DELETE FROM group_deps WHERE item_id = ? AND label = ? AND epic_id = ?`, itemID, label, epicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove group dependency: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// GetGroupDeps returns the group dependencies declared on itemID.
+func (db *DB) GetGroupDeps(itemID string) ([]GroupDep, error) {
+	rows, err := db.Query(`
+		SELECT id, item_id, label, epic_id, created_at
+		FROM group_deps WHERE item_id = ? ORDER BY label, epic_id`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group dependencies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deps []GroupDep
+	for rows.Next() {
+		var g GroupDep
+		if err := rows.Scan(&g.ID, &g.ItemID, &g.Label, &g.EpicID, &g.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group dependency: %w", err)
+		}
+		deps = append(deps, g)
+	}
+	return deps, rows.Err()
+}
+
+// GroupMembers returns the current members of g as dependency statuses
+// tagged with the group. The waiting item and its own descendants are never
+// members, so an epic can wait on a label without waiting on itself.
+func (db *DB) GroupMembers(g GroupDep) ([]DepStatus, error) {
+	query := `
+		WITH RECURSIVE own(id) AS (
+			SELECT ?
+			UNION ALL
+			SELECT i.id FROM items i JOIN own o ON i.parent_id = o.id
+		)
+		SELECT DISTINCT m.id, m.title, m.status
+		FROM items m
+		JOIN item_labels il ON il.item_id = m.id
+		JOIN labels l ON l.id = il.label_id
+		WHERE l.name = ? AND m.status != 'canceled'
+		  AND m.id NOT IN (SELECT id FROM own)`
+	args := []any{g.ItemID, g.Label}
+	if g.EpicID != "" {
+		query += ` AND m.id IN (
+			WITH RECURSIVE scope(id) AS (
+				SELECT id FROM items WHERE parent_id = ?
+				UNION ALL
+				SELECT i.id FROM items i JOIN scope s ON i.parent_id = s.id
+			)
+			SELECT id FROM scope)`
+		args = append(args, g.EpicID)
+	} else {
+		query += ` AND m.project = (SELECT project FROM items WHERE id = ?)`
+		args = append(args, g.ItemID)
+	}
+	query += ` ORDER BY m.id`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group members: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var members []DepStatus
+	for rows.Next() {
+		dep := DepStatus{Group: g.String()}
+		if err := rows.Scan(&dep.ID, &dep.Title, &dep.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+		members = append(members, dep)
+	}
+	return members, rows.Err()
+}
+
+// GetGroupDepStatuses returns the members of every group itemID waits on,
+// including groups declared on its ancestor epics (marked inherited).
+func (db *DB) GetGroupDepStatuses(itemID string) ([]DepStatus, error) {
+	ancestors, err := db.GetParentChain(itemID)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{itemID}
+	for _, a := range ancestors {
+		if a.Type == model.ItemTypeEpic {
+			ids = append(ids, a.ID)
+		}
+	}
+
+	var statuses []DepStatus
+	for _, id := range ids {
+		groups, err := db.GetGroupDeps(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			members, err := db.GroupMembers(g)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range members {
+				if m.ID == itemID {
+					continue
+				}
+				if id != itemID {
+					m.IsInherited = true
+					m.InheritedFrom = id
+				}
+				statuses = append(statuses, m)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// hasGroupDeps reports whether any group dependency exists, so ready checks
+// can skip the per-item lookups when the feature is unused.
+func (db *DB) hasGroupDeps() (bool, error) {
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM group_deps`).Scan(&n); err != nil {
+		return false, fmt.Errorf("failed to count group dependencies: %w", err)
+	}
+	return n > 0, nil
+}
+
+// hasUnmetGroupDeps reports whether any group itemID waits on still has a
+// member that is not done.
+func (db *DB) hasUnmetGroupDeps(itemID string) (bool, error) {
+	statuses, err := db.GetGroupDepStatuses(itemID)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range statuses {
+		if s.Status != string(model.StatusDone) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
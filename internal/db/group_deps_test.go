@@ -0,0 +1,151 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestGroupDeps(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	epic := &model.Item{ID: "ep-grp", Project: "test", Type: model.ItemTypeEpic, Title: "Migrations",
+		Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := db.CreateItem(epic); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	newChild := func(id, label string) *model.Item {
+		t.Helper()
+		item := &model.Item{ID: id, Project: "test", Type: model.ItemTypeTask, Title: id,
+			Status: model.StatusOpen, Priority: 2, ParentID: &epic.ID, CreatedAt: now, UpdatedAt: now}
+		if err := db.CreateItem(item); err != nil {
+			t.Fatalf("CreateItem: %v", err)
+		}
+		if label != "" {
+			if err := db.AddLabelToItem(id, "test", label); err != nil {
+				t.Fatalf("AddLabelToItem: %v", err)
+			}
+		}
+		return item
+	}
+	newChild("ts-keep", "") // keeps the epic open
+	m1 := newChild("ts-mig1", "migration")
+	outside := createTestItem(t, db, "Outside the epic")
+	if err := db.AddLabelToItem(outside.ID, "test", "migration"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+	waiter := createTestItem(t, db, "Deploy")
+
+	if err := db.AddGroupDep(waiter.ID, "migration", epic.ID); err != nil {
+		t.Fatalf("AddGroupDep: %v", err)
+	}
+	if err := db.AddGroupDep(waiter.ID, "migration", outside.ID); err == nil {
+		t.Error("expected an error scoping a group to a task")
+	}
+
+	isReady := func(id string) bool {
+		t.Helper()
+		ready, err := db.ReadyItems("test")
+		if err != nil {
+			t.Fatalf("ReadyItems: %v", err)
+		}
+		for _, item := range ready {
+			if item.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	if isReady(waiter.ID) {
+		t.Fatal("waiter is ready while a migration task is open")
+	}
+	if err := db.UpdateStatus(m1.ID, model.StatusDone, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if !isReady(waiter.ID) {
+		t.Fatal("waiter not ready once the group is done (tasks outside the epic must not count)")
+	}
+
+	// A task labeled later joins the group
+	m2 := newChild("ts-mig2", "migration")
+	if isReady(waiter.ID) {
+		t.Fatal("a newly labeled task did not join the group")
+	}
+	deps, err := db.GetAllDepStatuses(waiter.ID)
+	if err != nil {
+		t.Fatalf("GetAllDepStatuses: %v", err)
+	}
+	if len(deps) != 2 || deps[1].ID != m2.ID || deps[1].Group != "label migration in ep-grp" {
+		t.Errorf("deps = %+v, want both group members", deps)
+	}
+	r, err := db.ExplainReadiness(waiter.ID, AgentContext{})
+	if err != nil {
+		t.Fatalf("ExplainReadiness: %v", err)
+	}
+	if len(r.Reasons) != 1 || r.Reasons[0].Kind != NotReadyGroupDep || r.Reasons[0].Dep.ID != m2.ID {
+		t.Errorf("reasons = %+v, want one group_dep reason for %s", r.Reasons, m2.ID)
+	}
+
+	// Canceled tasks drop out of the group
+	if err := db.UpdateStatus(m2.ID, model.StatusCanceled, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if !isReady(waiter.ID) {
+		t.Fatal("a canceled task still holds the group")
+	}
+
+	removed, err := db.RemoveGroupDep(waiter.ID, "migration", epic.ID)
+	if err != nil || !removed {
+		t.Fatalf("RemoveGroupDep = %v, %v; want removed", removed, err)
+	}
+	if groups, _ := db.GetGroupDeps(waiter.ID); len(groups) != 0 {
+		t.Errorf("groups after removal = %v", groups)
+	}
+}
+
+func TestGroupDepsIgnoreOwnSubtree(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	epic := &model.Item{ID: "ep-own", Project: "test", Type: model.ItemTypeEpic, Title: "Epic",
+		Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := db.CreateItem(epic); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	child := &model.Item{ID: "ts-own", Project: "test", Type: model.ItemTypeTask, Title: "Child",
+		Status: model.StatusOpen, Priority: 2, ParentID: &epic.ID, CreatedAt: now, UpdatedAt: now}
+	if err := db.CreateItem(child); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	if err := db.AddLabelToItem(child.ID, "test", "docs"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+	other := createTestItem(t, db, "Docs elsewhere")
+	if err := db.AddLabelToItem(other.ID, "test", "docs"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+
+	// The epic waits on every docs task in the project except its own
+	if err := db.AddGroupDep(epic.ID, "docs", ""); err != nil {
+		t.Fatalf("AddGroupDep: %v", err)
+	}
+	statuses, err := db.GetGroupDepStatuses(child.ID)
+	if err != nil {
+		t.Fatalf("GetGroupDepStatuses: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].ID != other.ID || statuses[0].InheritedFrom != epic.ID {
+		t.Errorf("statuses = %+v, want only %s inherited from %s", statuses, other.ID, epic.ID)
+	}
+
+	// Deleting the epic drops its group dependency
+	if err := db.DeleteItem(child.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem child: %v", err)
+	}
+	if err := db.DeleteItem(epic.ID, false, false); err != nil {
+		t.Fatalf("DeleteItem epic: %v", err)
+	}
+	if ok, _ := db.hasGroupDeps(); ok {
+		t.Error("group dependency outlived its item")
+	}
+}
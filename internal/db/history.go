@@ -18,6 +18,7 @@ const (
 	EventTypeTitleChanged       = "title_changed"
 	EventTypeDescriptionChanged = "description_changed"
 	EventTypePriorityChanged    = "priority_changed"
+	EventTypeTypeChanged        = "type_changed"
 	EventTypeParentChanged      = "parent_changed"
 	EventTypeAssigned           = "assigned"
 	EventTypeCompleted          = "completed"
@@ -25,6 +26,13 @@ const (
 	EventTypeReopened           = "reopened"
 	EventTypeDependencyAdded    = "dependency_added"
 	EventTypeDependencyRemoved  = "dependency_removed"
+	EventTypeSplit              = "split"
+	EventTypeTemplateResynced   = "template_resynced"
+	EventTypeFieldChanged       = "field_changed"
+	EventTypeLogEdited          = "log_edited"
+	EventTypeLogRemoved         = "log_removed"
+	EventTypeRedacted           = "redacted"
+	EventTypeStatusForced       = "status_forced"
 )
 
 // HistoryEntry represents a single history event for an item.
@@ -34,6 +42,7 @@ type HistoryEntry struct {
 	EventType string
 	ActorID   string
 	ActorType string
+	Session   string         // CurrentSession() of the command that made the change
 	Changes   map[string]any // Parsed JSON
 	CreatedAt time.Time
 }
@@ -42,9 +51,10 @@ type HistoryEntry struct {
 type HistoryQueryOptions struct {
 	ItemID     string    // Filter by specific item
 	ActorID    string    // Filter by actor/agent
+	Session    string    // Filter by session (see CurrentSession)
 	Since      time.Time // Filter by time (entries >= since)
 	EventTypes []string  // Filter by event type(s)
-	Limit      int       // Max results (default 50)
+	Limit      int       // Max results (default 50, negative for no limit)
 }
 
 // defaultHistoryLimit is the default limit for history queries.
@@ -70,11 +80,13 @@ func (db *DB) RecordHistory(itemID, eventType string, changes map[string]any) er
 		}
 	}
 
+	observeTransition(eventType, changes)
+
 	// Insert history entry
 	_, err = db.Exec(`
-		INSERT INTO history (item_id, event_type, actor_id, actor_type, changes, created_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, itemID, eventType, nullString(agentCtx.ID), nullString(agentCtx.Type), string(changesJSON), sqlTime(time.Now()))
+		INSERT INTO history (item_id, event_type, actor_id, actor_type, session, changes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, itemID, eventType, nullString(agentCtx.ID), nullString(agentCtx.Type), CurrentSession(), string(changesJSON), sqlTime(time.Now()))
 	if err != nil {
 		log.Printf("warning: failed to record history for %s: %v", itemID, err)
 		return nil // Non-fatal, don't break the operation
@@ -83,6 +95,31 @@ func (db *DB) RecordHistory(itemID, eventType string, changes map[string]any) er
 	return nil
 }
 
+// TransitionObserver, when set, is told about every status change recorded
+// in history. from is empty when the event doesn't record the old status
+// (completions and cancellations). 'tpg' uses it for metrics.
+var TransitionObserver func(from, to model.Status)
+
+// observeTransition passes a status-changing history event to
+// TransitionObserver.
+func observeTransition(eventType string, changes map[string]any) {
+	if TransitionObserver == nil {
+		return
+	}
+	switch eventType {
+	case EventTypeStatusChanged, EventTypeReopened:
+		from, _ := changes["old"].(string)
+		to, _ := changes["new"].(string)
+		if to != "" {
+			TransitionObserver(model.Status(from), model.Status(to))
+		}
+	case EventTypeCompleted:
+		TransitionObserver("", model.StatusDone)
+	case EventTypeCanceled:
+		TransitionObserver("", model.StatusCanceled)
+	}
+}
+
 // nullString returns a sql.NullString that is NULL if s is empty.
 func nullString(s string) sql.NullString {
 	if s == "" {
@@ -95,14 +132,28 @@ func nullString(s string) sql.NullString {
 // Results are ordered by created_at DESC (newest first).
 // Uses the appropriate index based on provided filters.
 func (db *DB) GetHistory(opts HistoryQueryOptions) ([]HistoryEntry, error) {
+	var entries []HistoryEntry
+	err := db.EachHistory(opts, func(entry HistoryEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// EachHistory calls fn for each history entry matching opts, newest first,
+// without holding them all in memory. A negative Limit means no limit.
+func (db *DB) EachHistory(opts HistoryQueryOptions, fn func(HistoryEntry) error) error {
 	// Apply default limit if not specified
 	limit := opts.Limit
-	if limit <= 0 {
+	if limit == 0 {
 		limit = defaultHistoryLimit
 	}
 
 	// Build query dynamically based on filters
-	query := `SELECT id, item_id, event_type, actor_id, actor_type, changes, created_at
+	query := `SELECT id, item_id, event_type, actor_id, actor_type, session, changes, created_at
 		FROM history WHERE 1=1`
 	args := []any{}
 
@@ -118,6 +169,12 @@ func (db *DB) GetHistory(opts HistoryQueryOptions) ([]HistoryEntry, error) {
 		args = append(args, opts.ActorID)
 	}
 
+	// Filter by session (uses idx_history_session)
+	if opts.Session != "" {
+		query += ` AND session = ?`
+		args = append(args, opts.Session)
+	}
+
 	// Filter by time (since)
 	if !opts.Since.IsZero() {
 		query += ` AND created_at >= ?`
@@ -137,10 +194,10 @@ func (db *DB) GetHistory(opts HistoryQueryOptions) ([]HistoryEntry, error) {
 	}
 
 	// Order by created_at DESC (uses idx_history_recent for general queries)
-	query += ` ORDER BY created_at DESC LIMIT ?`
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
 	args = append(args, limit)
 
-	return db.queryHistoryEntries(query, args...)
+	return db.eachHistoryEntry(query, args, fn)
 }
 
 // GetItemHistory is a convenience wrapper for getting history of a specific item.
@@ -180,25 +237,38 @@ func (db *DB) GetRecentlyClosed(limit int, since time.Time) ([]model.Item, error
 
 // queryHistoryEntries is a helper to scan history entry rows.
 func (db *DB) queryHistoryEntries(query string, args ...any) ([]HistoryEntry, error) {
+	var entries []HistoryEntry
+	err := db.eachHistoryEntry(query, args, func(entry HistoryEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// eachHistoryEntry scans history rows one at a time, passing each to fn.
+func (db *DB) eachHistoryEntry(query string, args []any, fn func(HistoryEntry) error) error {
 	rows, err := db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query history: %w", err)
+		return fmt.Errorf("failed to query history: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var entries []HistoryEntry
 	for rows.Next() {
 		var entry HistoryEntry
 		var actorID sql.NullString
 		var actorType sql.NullString
+		var session sql.NullString
 		var changesJSON sql.NullString
 
 		if err := rows.Scan(
 			&entry.ID, &entry.ItemID, &entry.EventType,
-			&actorID, &actorType, &changesJSON,
+			&actorID, &actorType, &session, &changesJSON,
 			&entry.CreatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan history entry: %w", err)
+			return fmt.Errorf("failed to scan history entry: %w", err)
 		}
 
 		// Handle nullable fields
@@ -208,6 +278,7 @@ func (db *DB) queryHistoryEntries(query string, args ...any) ([]HistoryEntry, er
 		if actorType.Valid {
 			entry.ActorType = actorType.String
 		}
+		entry.Session = session.String
 
 		// Parse JSON changes gracefully
 		if changesJSON.Valid && changesJSON.String != "" {
@@ -221,12 +292,14 @@ func (db *DB) queryHistoryEntries(query string, args ...any) ([]HistoryEntry, er
 			}
 		}
 
-		entries = append(entries, entry)
+		if err := fn(entry); err != nil {
+			return err
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate history rows: %w", err)
+		return fmt.Errorf("failed to iterate history rows: %w", err)
 	}
 
-	return entries, nil
+	return nil
 }
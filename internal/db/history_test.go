@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"path/filepath"
 	"testing"
 	"time"
@@ -1668,3 +1669,44 @@ func TestIntegration_ClearParentRecordsHistory(t *testing.T) {
 		t.Errorf("expected changes.new='', got %v", parentEntry.Changes["new"])
 	}
 }
+
+// TestEachHistory_NoLimit verifies that a negative limit streams every event
+// and that an error from the callback stops the scan.
+func TestEachHistory_NoLimit(t *testing.T) {
+	db := setupTestDBWithHistory(t)
+	insertTestItemWithoutHistory(t, db, "ts-test1", "test")
+
+	now := time.Now()
+	for i := 0; i < 60; i++ {
+		insertHistoryEntry(t, db, "ts-test1", "updated", "agent-1", "subagent", nil, now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	count := 0
+	err := db.EachHistory(HistoryQueryOptions{Limit: -1}, func(HistoryEntry) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachHistory failed: %v", err)
+	}
+	if count != 60 {
+		t.Errorf("expected 60 entries with no limit, got %d", count)
+	}
+
+	if entries, _ := db.GetHistory(HistoryQueryOptions{}); len(entries) != 50 {
+		t.Errorf("expected the default limit of 50, got %d", len(entries))
+	}
+
+	stop := errors.New("stop")
+	count = 0
+	err = db.EachHistory(HistoryQueryOptions{Limit: -1}, func(HistoryEntry) error {
+		count++
+		if count == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 3 {
+		t.Errorf("expected the scan to stop after 3 entries with the callback's error, got %d, %v", count, err)
+	}
+}
@@ -6,33 +6,51 @@ import (
 	"github.com/taxilian/tpg/internal/model"
 )
 
-const maxIDRetries = 10
+const (
+	// maxIDRetries is how many random IDs are tried at one length before
+	// the length grows.
+	maxIDRetries = 10
+	// maxIDGrowth is how many characters longer than id_length an ID may
+	// grow when the configured length is crowded.
+	maxIDGrowth = 2
+	// maxIDLength caps id_length.
+	maxIDLength = 32
+)
 
-// GenerateItemID returns a new unique item ID using hardcoded prefixes (ts- for task, ep- for epic).
-// Retries with a new random hash on collision.
+// GenerateItemID returns a new unique item ID using the type's prefix.
 func (db *DB) GenerateItemID(itemType model.ItemType) (string, error) {
+	return db.GenerateItemIDWithPrefix("", itemType)
+}
+
+// GenerateItemIDWithPrefix returns a new item ID with the given prefix (or
+// the type's prefix when empty) that no existing item or item alias uses, so
+// an alias never hides a new item. The random part
+// follows id_length and id_alphabet from the config. Each collision retries
+// with a new random part; when every try at one length collides, the ID
+// grows by a character.
+func (db *DB) GenerateItemIDWithPrefix(prefix string, itemType model.ItemType) (string, error) {
 	config, err := LoadConfig()
 	if err != nil {
 		return "", err
 	}
-	idLen := config.IDLength
-	if idLen == 0 {
-		idLen = model.DefaultIDLength
+	idLen, alphabet, err := config.IDFormat()
+	if err != nil {
+		return "", err
 	}
 
-	// Hardcoded prefixes: ts for task, ep for epic (handled by model.GenerateIDWithPrefixN)
-	prefix := ""
-
-	for i := 0; i < maxIDRetries; i++ {
-		id := model.GenerateIDWithPrefixN(prefix, itemType, idLen)
-		var count int
-		err := db.QueryRow(`SELECT COUNT(*) FROM items WHERE id = ?`, id).Scan(&count)
-		if err != nil {
-			return "", fmt.Errorf("failed to check ID uniqueness: %w", err)
-		}
-		if count == 0 {
-			return id, nil
+	for n := idLen; n <= idLen+maxIDGrowth; n++ {
+		for i := 0; i < maxIDRetries; i++ {
+			id := model.GenerateIDFrom(prefix, itemType, n, alphabet)
+			var count int
+			err := db.QueryRow(`SELECT (SELECT COUNT(*) FROM items WHERE id = ?) +
+				(SELECT COUNT(*) FROM item_aliases WHERE alias = ?)`, id, id).Scan(&count)
+			if err != nil {
+				return "", fmt.Errorf("failed to check ID uniqueness: %w", err)
+			}
+			if count == 0 {
+				return id, nil
+			}
 		}
 	}
-	return "", fmt.Errorf("failed to generate unique ID after %d attempts (consider increasing id_length in config)", maxIDRetries)
+	return "", fmt.Errorf("failed to generate a unique ID after %d attempts (consider increasing id_length in config)", maxIDRetries*(maxIDGrowth+1))
 }
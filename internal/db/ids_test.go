@@ -0,0 +1,92 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// chdirWithConfig moves into a temp dir whose .tpg/config.json holds config.
+func chdirWithConfig(t *testing.T, config string) {
+	t.Helper()
+	dir := t.TempDir()
+	tpgDir := filepath.Join(dir, ".tpg")
+	if err := os.MkdirAll(tpgDir, 0755); err != nil {
+		t.Fatalf("failed to create .tpg dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tpgDir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(oldWd) })
+}
+
+func TestGenerateItemIDWithPrefix_GrowsOnCollision(t *testing.T) {
+	db := setupTestDB(t)
+	chdirWithConfig(t, `{"id_length": 1, "id_alphabet": "ab"}`)
+
+	// Take every one-character ID so the next one has to grow
+	now := time.Now()
+	for _, id := range []string{"fx-a", "fx-b"} {
+		item := &model.Item{ID: id, Project: "test", Type: model.ItemTypeTask, Title: id, Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now}
+		if err := db.CreateItem(item); err != nil {
+			t.Fatalf("CreateItem: %v", err)
+		}
+	}
+
+	id, err := db.GenerateItemIDWithPrefix("fx", model.ItemTypeTask)
+	if err != nil {
+		t.Fatalf("GenerateItemIDWithPrefix: %v", err)
+	}
+	suffix, ok := strings.CutPrefix(id, "fx-")
+	if !ok || len(suffix) != 2 || strings.Trim(suffix, "ab") != "" {
+		t.Errorf("id = %q, want fx- and two characters from the alphabet", id)
+	}
+
+	id, err = db.GenerateItemID(model.ItemTypeTask)
+	if err != nil {
+		t.Fatalf("GenerateItemID: %v", err)
+	}
+	if !strings.HasPrefix(id, "ts-") || len(id) != 4 {
+		t.Errorf("id = %q, want ts- and one character", id)
+	}
+}
+
+func TestGenerateItemIDWithPrefix_SkipsAliases(t *testing.T) {
+	db := setupTestDB(t)
+	chdirWithConfig(t, `{"id_length": 1, "id_alphabet": "ab"}`)
+
+	now := time.Now()
+	item := &model.Item{ID: "fx-a", Project: "test", Type: model.ItemTypeTask, Title: "A", Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := db.CreateItem(item); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	// An ID-shaped alias from before such aliases were refused
+	if _, err := db.Exec(`INSERT INTO item_aliases (alias, item_id) VALUES ('fx-b', 'fx-a')`); err != nil {
+		t.Fatalf("insert alias: %v", err)
+	}
+
+	id, err := db.GenerateItemIDWithPrefix("fx", model.ItemTypeTask)
+	if err != nil {
+		t.Fatalf("GenerateItemIDWithPrefix: %v", err)
+	}
+	if len(id) != 5 {
+		t.Errorf("id = %q, want it to grow past the alias fx-b", id)
+	}
+}
+
+func TestGenerateItemIDWithPrefix_InvalidConfig(t *testing.T) {
+	db := setupTestDB(t)
+	chdirWithConfig(t, `{"id_alphabet": "ABC"}`)
+
+	if _, err := db.GenerateItemID(model.ItemTypeTask); err == nil || !strings.Contains(err.Error(), "id_alphabet") {
+		t.Errorf("expected an id_alphabet error, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/taxilian/tpg/internal/model"
@@ -26,9 +27,9 @@ func (db *DB) CreateItem(item *model.Item) error {
 		if err != nil {
 			return fmt.Errorf("parent not found: %s (use 'tpg list' to see available items)", *item.ParentID)
 		}
-		// Only epics can have children
-		if parentType != string(model.ItemTypeEpic) {
-			return fmt.Errorf("cannot set parent: %s is not an epic (only epics can have children)", *item.ParentID)
+		// Only epics (and custom types that allow it) can have children
+		if !model.ItemType(parentType).CanHaveChildren() {
+			return fmt.Errorf("cannot set parent: %s (type %s) cannot have children", *item.ParentID, parentType)
 		}
 		if parentStatus == model.StatusDone || parentStatus == model.StatusCanceled {
 			return fmt.Errorf("cannot add child to closed parent %s", *item.ParentID)
@@ -42,12 +43,33 @@ func (db *DB) CreateItem(item *model.Item) error {
 		}
 	}
 
+	if err := insertItem(db, item); err != nil {
+		return err
+	}
+	db.recordCreated(item)
+	return nil
+}
+
+// execer runs a statement on either the database or a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// queryExecer is an execer that can also read, for helpers that check
+// before they write.
+type queryExecer interface {
+	execer
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// insertItem inserts item's row without validating it.
+func insertItem(ex execer, item *model.Item) error {
 	varsJSON, err := marshalTemplateVars(item.TemplateVars)
 	if err != nil {
 		return err
 	}
 
-	_, err = db.Exec(`
+	_, err = ex.Exec(`
 		INSERT INTO items (
 			id, project, type, title, description, status, priority, parent_id,
 			template_id, step_index, variables, template_hash, results,
@@ -66,16 +88,17 @@ func (db *DB) CreateItem(item *model.Item) error {
 	if err != nil {
 		return fmt.Errorf("failed to create item: %w", err)
 	}
+	return nil
+}
 
-	// Record history for item creation
+// recordCreated records the history event for a new item.
+func (db *DB) recordCreated(item *model.Item) {
 	_ = db.RecordHistory(item.ID, EventTypeCreated, map[string]any{
 		"title":    item.Title,
 		"type":     string(item.Type),
 		"status":   string(item.Status),
 		"priority": item.Priority,
 	})
-
-	return nil
 }
 
 // GetItem retrieves an item by ID.
@@ -172,7 +195,9 @@ func (db *DB) GetItem(id string) (*model.Item, error) {
 
 // UpdateStatus changes an item's status.
 // UpdateStatus changes an item's status and optionally assigns it to an agent.
-func (db *DB) UpdateStatus(id string, status model.Status, agentCtx AgentContext, force bool) error {
+// Changes the status graph doesn't allow (see model.CanTransition) fail with
+// a *model.TransitionError unless force is set.
+func (db *DB) UpdateStatus(id string, status model.Status, agentCtx AgentContext, force bool) (retErr error) {
 	if !status.IsValid() {
 		return fmt.Errorf("invalid status: %s", status)
 	}
@@ -187,6 +212,26 @@ func (db *DB) UpdateStatus(id string, status model.Status, agentCtx AgentContext
 		return fmt.Errorf("failed to get item status: %w", err)
 	}
 
+	// Enforce the status graph. force skips the check, and the skip is
+	// recorded in history so the jump can be found later.
+	forced := false
+	if err := model.CheckTransition(oldStatus, status); err != nil {
+		if !force {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		forced = true
+	}
+	if forced {
+		defer func() {
+			if retErr == nil {
+				_ = db.RecordHistory(id, EventTypeStatusForced, map[string]any{
+					"old": string(oldStatus),
+					"new": string(status),
+				})
+			}
+		}()
+	}
+
 	// Handle status-specific logic
 	if status == model.StatusDone || status == model.StatusCanceled {
 		// Delegate to CloseAndCascade for proper cascade behavior
@@ -195,7 +240,7 @@ func (db *DB) UpdateStatus(id string, status model.Status, agentCtx AgentContext
 		if err != nil {
 			return err
 		}
-		if itemType == string(model.ItemTypeEpic) {
+		if model.ItemType(itemType).CanHaveChildren() {
 			// Epic done/canceled - check open children then use AutoCompleteEpic
 			var openChildren int
 			err := db.QueryRow(`SELECT COUNT(*) FROM items WHERE parent_id = ? AND status NOT IN ('done', 'canceled')`, id).Scan(&openChildren)
@@ -285,11 +330,11 @@ func (db *DB) CompleteItem(id, results string, agentCtx AgentContext) error {
 	if err != nil {
 		return fmt.Errorf("failed to get item: %w", err)
 	}
-	if itemType == string(model.ItemTypeEpic) {
+	if model.ItemType(itemType).CanHaveChildren() {
 		if worktreeBranch.Valid && worktreeBranch.String != "" {
 			return fmt.Errorf("cannot complete worktree epic %s directly: use 'tpg epic merge' instead", id)
 		}
-		return fmt.Errorf("cannot complete epic %s directly: epics auto-complete when all children are done", id)
+		return fmt.Errorf("cannot complete %s %s directly: %ss auto-complete when all children are done", itemType, id, itemType)
 	}
 
 	_, err = db.CloseAndCascade(id, model.StatusDone, results, agentCtx, false)
@@ -325,6 +370,7 @@ func (db *DB) MarkEpicMerged(id string) error {
 	_ = db.RecordHistory(id, EventTypeCompleted, map[string]any{
 		"merged": true,
 	})
+	db.notifyUnblocked(id)
 
 	return nil
 }
@@ -362,16 +408,20 @@ func (db *DB) CheckParentEpicCompletion(itemID string) (*EpicCompletionInfo, err
 	}
 
 	var parentStatus model.Status
+	var parentType model.ItemType
 	var closingInstructions, worktreeBranch, worktreeBase sql.NullString
 	err = db.QueryRow(`
-		SELECT status, closing_instructions, worktree_branch, worktree_base
-		FROM items WHERE id = ? AND type = 'epic'`, parentID.String).Scan(&parentStatus, &closingInstructions, &worktreeBranch, &worktreeBase)
+		SELECT status, type, closing_instructions, worktree_branch, worktree_base
+		FROM items WHERE id = ?`, parentID.String).Scan(&parentStatus, &parentType, &closingInstructions, &worktreeBranch, &worktreeBase)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	if !parentType.CanHaveChildren() {
+		return nil, nil
+	}
 
 	if parentStatus == model.StatusDone || parentStatus == model.StatusCanceled {
 		return nil, nil
@@ -402,6 +452,8 @@ func (db *DB) CheckParentEpicCompletion(itemID string) (*EpicCompletionInfo, err
 	}, nil
 }
 
+// CloseAndCascade closes a task and auto-completes the epics it finishes.
+// Completing a task that needs approval fails unless force is set.
 func (db *DB) CloseAndCascade(id string, status model.Status, results string, agentCtx AgentContext, force bool) (*CascadeResult, error) {
 	if status != model.StatusDone && status != model.StatusCanceled {
 		return nil, fmt.Errorf("CloseAndCascade only supports StatusDone or StatusCanceled, got %s", status)
@@ -415,7 +467,7 @@ func (db *DB) CloseAndCascade(id string, status model.Status, results string, ag
 	if err != nil {
 		return nil, err
 	}
-	if itemType == string(model.ItemTypeEpic) {
+	if model.ItemType(itemType).CanHaveChildren() {
 		return nil, fmt.Errorf("use AutoCompleteEpic to close epics, not CloseAndCascade")
 	}
 
@@ -427,6 +479,11 @@ func (db *DB) CloseAndCascade(id string, status model.Status, results string, ag
 	if openChildren > 0 {
 		return nil, fmt.Errorf("cannot close %s: has %d open children", id, openChildren)
 	}
+	if status == model.StatusDone && !force {
+		if err := db.checkApprovalGate(id); err != nil {
+			return nil, err
+		}
+	}
 
 	now := sqlTime(time.Now())
 	result := &CascadeResult{}
@@ -446,6 +503,9 @@ func (db *DB) CloseAndCascade(id string, status model.Status, results string, ag
 		eventType = EventTypeCanceled
 	}
 	_ = db.RecordHistory(id, eventType, map[string]any{"results": results})
+	if status == model.StatusDone {
+		db.notifyUnblocked(id)
+	}
 
 	currentID := id
 	for {
@@ -479,17 +539,24 @@ func (db *DB) AutoCompleteEpic(epicID string) ([]string, error) {
 		return nil, err
 	}
 
-	results := fmt.Sprintf("All %d child tasks completed (%d done)", total, done)
+	return db.completeEpic(epicID, fmt.Sprintf("All %d child tasks completed (%d done)", total, done))
+}
+
+// completeEpic closes an epic as done with results, then completes any parent
+// epics this leaves with only closed children. It returns the IDs of every
+// epic it closed, epicID first.
+func (db *DB) completeEpic(epicID, results string) ([]string, error) {
 	now := sqlTime(time.Now())
-	_, err = db.Exec(`
+	_, err := db.Exec(`
 		UPDATE items SET status = ?, results = ?, closed_at = ?, updated_at = ?
-		WHERE id = ? AND type = 'epic'`,
+		WHERE id = ?`,
 		model.StatusDone, results, now, now, epicID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to auto-complete epic: %w", err)
 	}
 
 	_ = db.RecordHistory(epicID, EventTypeCompleted, map[string]any{"results": results})
+	db.notifyUnblocked(epicID)
 
 	completed := []string{epicID}
 	currentID := epicID
@@ -537,8 +604,15 @@ func (db *DB) GetWorktreeEpicsReadyToMerge(project string) ([]model.Item, error)
 	return db.queryItems(query, args...)
 }
 
-// AppendDescription appends text to an item's description.
+// AppendDescription appends text to an item's description. The description
+// before the append is kept as a previous version.
 func (db *DB) AppendDescription(id string, text string) error {
+	var oldDesc sql.NullString
+	if err := db.QueryRow(`SELECT description FROM items WHERE id = ?`, id).Scan(&oldDesc); err != nil {
+		return fmt.Errorf("item not found: %s (use 'tpg list' to see available items)", id)
+	}
+	db.saveDescriptionVersion(id, oldDesc.String)
+
 	result, err := db.Exec(`
 		UPDATE items
 		SET description = COALESCE(description, '') || ? || char(10) || ?,
@@ -573,9 +647,9 @@ func (db *DB) SetParent(itemID, parentID string) error {
 		return fmt.Errorf("parent not found: %s (use 'tpg list' to see available items)", parentID)
 	}
 
-	// Only epics can have children
-	if itemType != string(model.ItemTypeEpic) {
-		return fmt.Errorf("cannot set parent: %s is not an epic (only epics can have children)", parentID)
+	// Only epics (and custom types that allow it) can have children
+	if !model.ItemType(itemType).CanHaveChildren() {
+		return fmt.Errorf("cannot set parent: %s (type %s) cannot have children", parentID, itemType)
 	}
 
 	// Cannot add child to closed parent
@@ -664,7 +738,8 @@ func (db *DB) SetProject(id string, project string) error {
 	return nil
 }
 
-// SetDescription replaces an item's description entirely.
+// SetDescription replaces an item's description entirely. The replaced
+// description is kept as a previous version.
 func (db *DB) SetDescription(id string, text string) error {
 	// Get old description and template_id for validation
 	var oldDesc sql.NullString
@@ -679,6 +754,10 @@ func (db *DB) SetDescription(id string, text string) error {
 		return fmt.Errorf("cannot set description on template-backed task %s: descriptions are generated from template variables. Edit variables with 'tpg edit %s --var NAME=VALUE' or 'tpg show %s --vars', or use --force to override", id, id, id)
 	}
 
+	if oldDesc.String != text {
+		db.saveDescriptionVersion(id, oldDesc.String)
+	}
+
 	result, err := db.Exec(`
 		UPDATE items
 		SET description = ?,
@@ -843,10 +922,48 @@ func (db *DB) SetTemplateVar(id string, varName string, value string) error {
 	return nil
 }
 
+// SetType changes an item's type. Items with children can only be changed
+// to a type that can have children.
+func (db *DB) SetType(id string, itemType model.ItemType) error {
+	if !itemType.IsValid() {
+		return fmt.Errorf("invalid item type: %s", itemType)
+	}
+
+	var oldType string
+	err := db.QueryRow(`SELECT type FROM items WHERE id = ?`, id).Scan(&oldType)
+	if err != nil {
+		return fmt.Errorf("item not found: %s (use 'tpg list' to see available items)", id)
+	}
+	if oldType == string(itemType) {
+		return nil
+	}
+
+	if !itemType.CanHaveChildren() {
+		hasChildren, err := db.HasChildren(id)
+		if err != nil {
+			return err
+		}
+		if hasChildren {
+			return fmt.Errorf("cannot change %s to %s: it has children and %s items cannot have children", id, itemType, itemType)
+		}
+	}
+
+	if _, err := db.Exec(`UPDATE items SET type = ?, updated_at = ? WHERE id = ?`,
+		string(itemType), sqlTime(time.Now()), id); err != nil {
+		return fmt.Errorf("failed to update type: %w", err)
+	}
+
+	_ = db.RecordHistory(id, EventTypeTypeChanged, map[string]any{
+		"old": oldType,
+		"new": string(itemType),
+	})
+	return nil
+}
+
 // UpdatePriority changes an item's priority.
 func (db *DB) UpdatePriority(id string, priority int) error {
-	if priority < 1 || priority > 5 {
-		return fmt.Errorf("invalid priority: %d (must be 1-5)", priority)
+	if err := model.ValidatePriority(priority); err != nil {
+		return err
 	}
 
 	// Get old priority for history
@@ -910,6 +1027,31 @@ func (db *DB) DeleteItem(id string, force, recursive bool) error {
 		if blockedCount > 0 {
 			return fmt.Errorf("cannot delete %s: %d tasks depend on it (use --force to delete anyway)", id, blockedCount)
 		}
+
+		// Check for learnings linked to it, or to a descendant going with it
+		linked := []string{id}
+		if recursive {
+			descendants, err := db.getDescendantIDs(tx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get descendants: %w", err)
+			}
+			linked = append(linked, descendants...)
+		}
+		args := make([]any, len(linked))
+		placeholders := make([]string, len(linked))
+		for i, linkedID := range linked {
+			args[i] = linkedID
+			placeholders[i] = "?"
+		}
+		var learningCount int
+		err = tx.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM learnings WHERE task_id IN (%s)`,
+			strings.Join(placeholders, ", ")), args...).Scan(&learningCount)
+		if err != nil {
+			return fmt.Errorf("failed to check learnings: %w", err)
+		}
+		if learningCount > 0 {
+			return fmt.Errorf("cannot delete %s: %d learnings link to it (use --force to unlink them)", id, learningCount)
+		}
 	}
 
 	// Check for children
@@ -949,7 +1091,7 @@ func (db *DB) DeleteItem(id string, force, recursive bool) error {
 	return nil
 }
 
-// deleteItemInternal deletes a single item and its associated data (logs, deps, labels).
+// deleteItemInternal deletes a single item and its associated data (logs, deps, labels, fields).
 // This is used internally by DeleteItem and should be called within a transaction.
 func (db *DB) deleteItemInternal(tx *sql.Tx, id string) error {
 	// Delete logs
@@ -976,6 +1118,60 @@ func (db *DB) deleteItemInternal(tx *sql.Tx, id string) error {
 		return fmt.Errorf("failed to delete item labels: %w", err)
 	}
 
+	// Delete custom fields
+	_, err = tx.Exec(`DELETE FROM item_fields WHERE item_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete item fields: %w", err)
+	}
+
+	// Forget it as anyone's current task
+	_, err = tx.Exec(`DELETE FROM current_tasks WHERE item_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to clear current task: %w", err)
+	}
+
+	// Delete notes attached to it
+	_, err = tx.Exec(`DELETE FROM notes WHERE epic_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notes: %w", err)
+	}
+
+	// Delete previous descriptions
+	_, err = tx.Exec(`DELETE FROM description_versions WHERE item_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete description versions: %w", err)
+	}
+
+	// Free its alias
+	_, err = tx.Exec(`DELETE FROM item_aliases WHERE item_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alias: %w", err)
+	}
+
+	// Stop anyone watching it
+	_, err = tx.Exec(`DELETE FROM watches WHERE item_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete watches: %w", err)
+	}
+
+	// Delete attached transcripts
+	_, err = tx.Exec(`DELETE FROM transcripts WHERE item_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete transcripts: %w", err)
+	}
+
+	// Drop group dependencies it declares or that are scoped to it
+	_, err = tx.Exec(`DELETE FROM group_deps WHERE item_id = ? OR epic_id = ?`, id, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete group dependencies: %w", err)
+	}
+
+	// Keep learnings it led to, but unlink them
+	_, err = tx.Exec(`UPDATE learnings SET task_id = NULL WHERE task_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to unlink learnings: %w", err)
+	}
+
 	// Delete the item
 	_, err = tx.Exec(`DELETE FROM items WHERE id = ?`, id)
 	if err != nil {
@@ -1013,9 +1209,10 @@ func (db *DB) getDescendantIDs(tx *sql.Tx, itemID string) ([]string, error) {
 	return ids, rows.Err()
 }
 
-// GetChildren returns all items that have the given ID as their parent.
+// GetChildren returns all items that have the given ID as their parent, in
+// sibling order.
 func (db *DB) GetChildren(parentID string) ([]model.Item, error) {
-	query := fmt.Sprintf("SELECT %s FROM items WHERE parent_id = ? ORDER BY priority ASC, created_at ASC", itemSelectColumns)
+	query := fmt.Sprintf("SELECT %s FROM items WHERE parent_id = ? ORDER BY %s", itemSelectColumns, siblingOrder)
 	return db.queryItems(query, parentID)
 }
 
@@ -1074,6 +1271,7 @@ func (db *DB) GetEpics(project string) ([]model.Item, error) {
 }
 
 // GetDescendants returns all descendants of an item (children, grandchildren, etc.)
+// Siblings keep their manual rank order.
 func (db *DB) GetDescendants(itemID string) ([]model.Item, error) {
 	query := fmt.Sprintf(`
 		WITH RECURSIVE descendants(id) AS (
@@ -1085,8 +1283,8 @@ func (db *DB) GetDescendants(itemID string) ([]model.Item, error) {
 			JOIN descendants d ON i.parent_id = d.id
 		)
 		SELECT %s FROM items WHERE id IN (SELECT id FROM descendants)
-		ORDER BY priority ASC, created_at ASC
-	`, itemSelectColumns)
+		ORDER BY %s
+	`, itemSelectColumns, siblingOrder)
 	return db.queryItems(query, itemID)
 }
 
@@ -1183,10 +1381,28 @@ func (db *DB) ReplaceItem(oldID string, newItem *model.Item) (string, error) {
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := replaceItemTx(tx, oldItem, newItem, false); err != nil {
+		return "", err
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return newItem.ID, nil
+}
+
+// replaceItemTx inserts newItem in place of oldItem within tx: the parent,
+// children, dependencies, and logs move to the new item, labels move too when
+// keepLabels is set (otherwise they are dropped), and the old item is deleted.
+func replaceItemTx(tx *sql.Tx, oldItem, newItem *model.Item, keepLabels bool) error {
+	oldID := oldItem.ID
+
 	// 1. Create the new item
 	varsJSON, err := marshalTemplateVars(newItem.TemplateVars)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	_, err = tx.Exec(`
@@ -1204,51 +1420,109 @@ func (db *DB) ReplaceItem(oldID string, newItem *model.Item) (string, error) {
 		sqlTime(newItem.CreatedAt), sqlTime(newItem.UpdatedAt),
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to create replacement item: %w", err)
+		return fmt.Errorf("failed to create replacement item: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE items SET sort_rank = (SELECT sort_rank FROM items WHERE id = ?) WHERE id = ?`, oldID, newItem.ID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer rank: %w", err)
 	}
 
 	// 2. Update children to point to new parent
 	_, err = tx.Exec(`UPDATE items SET parent_id = ? WHERE parent_id = ?`, newItem.ID, oldID)
 	if err != nil {
-		return "", fmt.Errorf("failed to update children: %w", err)
+		return fmt.Errorf("failed to update children: %w", err)
 	}
 
 	// 3. Update dependencies where old item was the dependent (item_id)
 	_, err = tx.Exec(`UPDATE deps SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
 	if err != nil {
-		return "", fmt.Errorf("failed to update outgoing dependencies: %w", err)
+		return fmt.Errorf("failed to update outgoing dependencies: %w", err)
 	}
 
 	// 4. Update dependencies where old item was the dependency (depends_on)
 	_, err = tx.Exec(`UPDATE deps SET depends_on = ? WHERE depends_on = ?`, newItem.ID, oldID)
 	if err != nil {
-		return "", fmt.Errorf("failed to update incoming dependencies: %w", err)
+		return fmt.Errorf("failed to update incoming dependencies: %w", err)
 	}
 
 	// 5. Copy logs from old item to new item
 	_, err = tx.Exec(`UPDATE logs SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
 	if err != nil {
-		return "", fmt.Errorf("failed to transfer logs: %w", err)
+		return fmt.Errorf("failed to transfer logs: %w", err)
 	}
 
-	// 6. Delete item_labels associations (labels are NOT inherited)
-	_, err = tx.Exec(`DELETE FROM item_labels WHERE item_id = ?`, oldID)
+	// 6. Transfer or delete item_labels associations
+	if keepLabels {
+		_, err = tx.Exec(`UPDATE item_labels SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to transfer labels: %w", err)
+		}
+	} else {
+		_, err = tx.Exec(`DELETE FROM item_labels WHERE item_id = ?`, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to remove old item labels: %w", err)
+		}
+	}
+
+	// 7. Transfer custom fields
+	_, err = tx.Exec(`UPDATE item_fields SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
 	if err != nil {
-		return "", fmt.Errorf("failed to remove old item labels: %w", err)
+		return fmt.Errorf("failed to transfer fields: %w", err)
 	}
 
-	// 7. Delete the old item
-	_, err = tx.Exec(`DELETE FROM items WHERE id = ?`, oldID)
+	// Transfer notes
+	_, err = tx.Exec(`UPDATE notes SET epic_id = ? WHERE epic_id = ?`, newItem.ID, oldID)
 	if err != nil {
-		return "", fmt.Errorf("failed to delete old item: %w", err)
+		return fmt.Errorf("failed to transfer notes: %w", err)
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	// Transfer previous descriptions
+	_, err = tx.Exec(`UPDATE description_versions SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer description versions: %w", err)
 	}
 
-	return newItem.ID, nil
+	// Transfer the alias
+	_, err = tx.Exec(`UPDATE item_aliases SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer alias: %w", err)
+	}
+
+	// Transfer watches
+	_, err = tx.Exec(`UPDATE watches SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer watches: %w", err)
+	}
+
+	// Transfer transcripts
+	_, err = tx.Exec(`UPDATE transcripts SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer transcripts: %w", err)
+	}
+
+	// Transfer group dependencies, both declared and scoped
+	_, err = tx.Exec(`UPDATE group_deps SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer group dependencies: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE group_deps SET epic_id = ? WHERE epic_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer group dependency scopes: %w", err)
+	}
+
+	// Keep sessions whose current task this was pointing at it
+	_, err = tx.Exec(`UPDATE current_tasks SET item_id = ? WHERE item_id = ?`, newItem.ID, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer current task: %w", err)
+	}
+
+	// 8. Delete the old item
+	_, err = tx.Exec(`DELETE FROM items WHERE id = ?`, oldID)
+	if err != nil {
+		return fmt.Errorf("failed to delete old item: %w", err)
+	}
+
+	return nil
 }
 
 // InvalidParent represents a task that has a non-epic parent
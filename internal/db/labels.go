@@ -292,3 +292,83 @@ func (db *DB) SetLabelColor(project, name, color string) error {
 	}
 	return nil
 }
+
+// LabelMergeResult describes what MergeLabel changed, or would change in a dry run.
+type LabelMergeResult struct {
+	Retagged      int  // items that had only the source label and gain the target
+	AlreadyTagged int  // items that had both labels and just lose the source
+	CreatedTarget bool // the target label did not exist and is created
+}
+
+// MergeLabel moves every item tagged with source to target and deletes the
+// source label. The target is created if it doesn't exist. With dryRun, the
+// counts are computed but nothing is changed.
+func (db *DB) MergeLabel(project, source, target string, dryRun bool) (*LabelMergeResult, error) {
+	if source == target {
+		return nil, fmt.Errorf("cannot merge label %s into itself", source)
+	}
+	src, err := db.GetLabelByName(project, source)
+	if err != nil {
+		return nil, fmt.Errorf("label not found: %s", source)
+	}
+
+	result := &LabelMergeResult{}
+	dst, err := db.GetLabelByName(project, target)
+	if err != nil {
+		result.CreatedTarget = true
+	}
+
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM item_labels WHERE label_id = ?`, src.ID).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count labeled items: %w", err)
+	}
+	if dst != nil {
+		err := db.QueryRow(`
+			SELECT COUNT(*) FROM item_labels a
+			JOIN item_labels b ON b.item_id = a.item_id AND b.label_id = ?
+			WHERE a.label_id = ?`, dst.ID, src.ID).Scan(&result.AlreadyTagged)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count labeled items: %w", err)
+		}
+	}
+	result.Retagged = total - result.AlreadyTagged
+	if dryRun {
+		return result, nil
+	}
+
+	if dst == nil {
+		dst = &model.Label{
+			ID:        model.GenerateLabelID(),
+			Name:      target,
+			Project:   project,
+			Color:     src.Color,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := db.CreateLabel(dst); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO item_labels (item_id, label_id)
+		SELECT item_id, ? FROM item_labels WHERE label_id = ?`, dst.ID, src.ID); err != nil {
+		return nil, fmt.Errorf("failed to retag items: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM item_labels WHERE label_id = ?`, src.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete label associations: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM labels WHERE id = ?`, src.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete label: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
@@ -452,3 +452,85 @@ func TestEnsureLabel(t *testing.T) {
 		t.Error("ensure created duplicate label")
 	}
 }
+
+func TestMergeLabel(t *testing.T) {
+	db := setupTestDB(t)
+	onlySource := createTestItem(t, db, "Only source")
+	both := createTestItem(t, db, "Both")
+	onlyTarget := createTestItem(t, db, "Only target")
+
+	for _, tag := range []struct{ id, label string }{
+		{onlySource.ID, "bugfix"},
+		{both.ID, "bugfix"},
+		{both.ID, "bug"},
+		{onlyTarget.ID, "bug"},
+	} {
+		if err := db.AddLabelToItem(tag.id, "test", tag.label); err != nil {
+			t.Fatalf("AddLabelToItem failed: %v", err)
+		}
+	}
+
+	preview, err := db.MergeLabel("test", "bugfix", "bug", true)
+	if err != nil {
+		t.Fatalf("MergeLabel dry run failed: %v", err)
+	}
+	if preview.Retagged != 1 || preview.AlreadyTagged != 1 || preview.CreatedTarget {
+		t.Errorf("dry run = %+v, want 1 retagged, 1 already tagged", preview)
+	}
+	if _, err := db.GetLabelByName("test", "bugfix"); err != nil {
+		t.Fatal("dry run deleted the source label")
+	}
+
+	result, err := db.MergeLabel("test", "bugfix", "bug", false)
+	if err != nil {
+		t.Fatalf("MergeLabel failed: %v", err)
+	}
+	if *result != *preview {
+		t.Errorf("result = %+v, want %+v", result, preview)
+	}
+	if _, err := db.GetLabelByName("test", "bugfix"); err == nil {
+		t.Error("source label still exists after merge")
+	}
+	for _, item := range []*model.Item{onlySource, both, onlyTarget} {
+		labels, err := db.GetItemLabels(item.ID)
+		if err != nil {
+			t.Fatalf("GetItemLabels failed: %v", err)
+		}
+		if len(labels) != 1 || labels[0].Name != "bug" {
+			t.Errorf("%s labels = %v, want [bug]", item.Title, labels)
+		}
+	}
+}
+
+func TestMergeLabel_CreatesTarget(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Tagged")
+	if err := db.AddLabelToItem(item.ID, "test", "feat"); err != nil {
+		t.Fatalf("AddLabelToItem failed: %v", err)
+	}
+
+	result, err := db.MergeLabel("test", "feat", "feature", false)
+	if err != nil {
+		t.Fatalf("MergeLabel failed: %v", err)
+	}
+	if !result.CreatedTarget || result.Retagged != 1 {
+		t.Errorf("result = %+v, want created target and 1 retagged", result)
+	}
+	labels, _ := db.GetItemLabels(item.ID)
+	if len(labels) != 1 || labels[0].Name != "feature" {
+		t.Errorf("labels = %v, want [feature]", labels)
+	}
+}
+
+func TestMergeLabel_Errors(t *testing.T) {
+	db := setupTestDB(t)
+	if _, err := db.MergeLabel("test", "missing", "bug", false); err == nil {
+		t.Error("expected error for missing source label")
+	}
+	if _, err := db.EnsureLabel("test", "bug"); err != nil {
+		t.Fatalf("EnsureLabel failed: %v", err)
+	}
+	if _, err := db.MergeLabel("test", "bug", "bug", false); err == nil {
+		t.Error("expected error merging a label into itself")
+	}
+}
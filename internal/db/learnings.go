@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -28,11 +29,26 @@ func (db *DB) CreateLearning(l *model.Learning) error {
 		filesJSON = string(b)
 	}
 
+	// Schedule the first review
+	if l.ReviewAfter != "" && l.ReviewAt == nil {
+		d, err := parseConfigDuration(l.ReviewAfter)
+		if err != nil {
+			return fmt.Errorf("invalid review interval: %w", err)
+		}
+		reviewAt := l.CreatedAt.Add(d)
+		l.ReviewAt = &reviewAt
+	}
+	var reviewAt *string
+	if l.ReviewAt != nil {
+		t := sqlTime(*l.ReviewAt)
+		reviewAt = &t
+	}
+
 	// Insert learning
 	_, err = tx.Exec(`
-		INSERT INTO learnings (id, project, created_at, updated_at, task_id, summary, detail, files, status)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, l.ID, l.Project, l.CreatedAt, l.UpdatedAt, l.TaskID, l.Summary, l.Detail, filesJSON, l.Status)
+		INSERT INTO learnings (id, project, created_at, updated_at, task_id, summary, detail, files, status, review_after, review_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, l.ID, l.Project, l.CreatedAt, l.UpdatedAt, l.TaskID, l.Summary, l.Detail, filesJSON, l.Status, l.ReviewAfter, reviewAt)
 	if err != nil {
 		return fmt.Errorf("failed to insert learning: %w", err)
 	}
@@ -82,15 +98,21 @@ func (db *DB) GetLearning(id string) (*model.Learning, error) {
 	var l model.Learning
 	var filesJSON string
 	var taskID *string
+	var reviewAfter sql.NullString
+	var reviewAt sql.NullTime
 
 	err := db.QueryRow(`
-		SELECT id, project, created_at, updated_at, task_id, summary, detail, files, status
+		SELECT id, project, created_at, updated_at, task_id, summary, detail, files, status, review_after, review_at
 		FROM learnings WHERE id = ?
-	`, id).Scan(&l.ID, &l.Project, &l.CreatedAt, &l.UpdatedAt, &taskID, &l.Summary, &l.Detail, &filesJSON, &l.Status)
+	`, id).Scan(&l.ID, &l.Project, &l.CreatedAt, &l.UpdatedAt, &taskID, &l.Summary, &l.Detail, &filesJSON, &l.Status, &reviewAfter, &reviewAt)
 	if err != nil {
 		return nil, fmt.Errorf("learning not found: %s", id)
 	}
 	l.TaskID = taskID
+	l.ReviewAfter = reviewAfter.String
+	if reviewAt.Valid {
+		l.ReviewAt = &reviewAt.Time
+	}
 
 	// Parse files JSON
 	if filesJSON != "" && filesJSON != "[]" {
@@ -245,6 +267,71 @@ func (db *DB) UpdateLearningDetail(id, detail string) error {
 	return nil
 }
 
+// DueLearnings returns the active learnings whose review date has passed,
+// oldest due first.
+func (db *DB) DueLearnings(project string, now time.Time) ([]model.Learning, error) {
+	rows, err := db.Query(`
+		SELECT id FROM learnings
+		WHERE project = ? AND status = 'active'
+		  AND review_at IS NOT NULL AND review_at <= ?
+		ORDER BY review_at ASC
+	`, project, sqlTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due learnings: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan learning: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	learnings := make([]model.Learning, 0, len(ids))
+	for _, id := range ids {
+		l, err := db.GetLearning(id)
+		if err != nil {
+			return nil, err
+		}
+		learnings = append(learnings, *l)
+	}
+	return learnings, nil
+}
+
+// ConfirmLearning records that a learning is still valid and schedules its
+// next review. An empty reviewAfter reuses the learning's stored interval.
+// Returns the next review time.
+func (db *DB) ConfirmLearning(id, reviewAfter string) (time.Time, error) {
+	l, err := db.GetLearning(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if reviewAfter == "" {
+		reviewAfter = l.ReviewAfter
+	}
+	if reviewAfter == "" {
+		return time.Time{}, fmt.Errorf("%s has no review interval; pass one with --review-after", id)
+	}
+	d, err := parseConfigDuration(reviewAfter)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid review interval: %w", err)
+	}
+
+	now := time.Now()
+	next := now.Add(d)
+	_, err = db.Exec(`
+		UPDATE learnings SET status = ?, review_after = ?, review_at = ?, updated_at = ?
+		WHERE id = ?
+	`, model.LearningStatusActive, reviewAfter, sqlTime(next), sqlTime(now), id)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to confirm learning: %w", err)
+	}
+	return next, nil
+}
+
 // DeleteLearning removes a learning and its concept associations.
 func (db *DB) DeleteLearning(id string) error {
 	tx, err := db.Begin()
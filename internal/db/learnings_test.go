@@ -315,6 +315,79 @@ func TestDeleteLearning_NotFound(t *testing.T) {
 	}
 }
 
+func TestLearningReview(t *testing.T) {
+	db := setupTestDB(t)
+
+	newLearning := func(summary, reviewAfter string, created time.Time) *model.Learning {
+		l := &model.Learning{
+			ID:          model.GenerateLearningID(),
+			Project:     "test",
+			CreatedAt:   created,
+			UpdatedAt:   created,
+			Summary:     summary,
+			Status:      model.LearningStatusActive,
+			Concepts:    []string{"api"},
+			ReviewAfter: reviewAfter,
+		}
+		if err := db.CreateLearning(l); err != nil {
+			t.Fatalf("CreateLearning(%s): %v", summary, err)
+		}
+		return l
+	}
+
+	old := time.Now().Add(-100 * 24 * time.Hour)
+	due := newLearning("Due", "90d", old)
+	newLearning("Not due yet", "180d", old)
+	never := newLearning("Never reviewed", "", old)
+	stale := newLearning("Stale", "30d", old)
+	if err := db.UpdateLearningStatus(stale.ID, model.LearningStatusStale); err != nil {
+		t.Fatalf("UpdateLearningStatus: %v", err)
+	}
+
+	got, err := db.GetLearning(due.ID)
+	if err != nil {
+		t.Fatalf("GetLearning: %v", err)
+	}
+	if got.ReviewAfter != "90d" || got.ReviewAt == nil {
+		t.Fatalf("expected review schedule to round-trip, got %q %v", got.ReviewAfter, got.ReviewAt)
+	}
+
+	list, err := db.DueLearnings("test", time.Now())
+	if err != nil {
+		t.Fatalf("DueLearnings: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != due.ID {
+		t.Fatalf("expected only %s due, got %v", due.ID, list)
+	}
+
+	next, err := db.ConfirmLearning(due.ID, "")
+	if err != nil {
+		t.Fatalf("ConfirmLearning: %v", err)
+	}
+	if next.Before(time.Now().Add(89 * 24 * time.Hour)) {
+		t.Errorf("expected next review ~90d out, got %v", next)
+	}
+	if list, _ := db.DueLearnings("test", time.Now()); len(list) != 0 {
+		t.Errorf("expected nothing due after confirm, got %v", list)
+	}
+
+	if err := db.CreateLearning(&model.Learning{
+		ID: model.GenerateLearningID(), Project: "test", Summary: "Bad",
+		Status: model.LearningStatusActive, ReviewAfter: "soon",
+	}); err == nil {
+		t.Error("expected error for invalid review interval")
+	}
+	later, _ := db.DueLearnings("test", time.Now().Add(365*24*time.Hour))
+	for _, l := range later {
+		if l.ID == never.ID {
+			t.Error("learning without an interval should never be due")
+		}
+	}
+	if _, err := db.ConfirmLearning(never.ID, ""); err == nil {
+		t.Error("expected error confirming a learning with no interval")
+	}
+}
+
 // --- Concepts ---
 
 func TestListConcepts(t *testing.T) {
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// Lint rule names, as used in config lint.rules and in lint output.
+const (
+	LintShortDescription   = "short-description"
+	LintAcceptanceCriteria = "acceptance-criteria"
+	LintStaleNoLogs        = "stale-no-logs"
+	LintEpicContext        = "epic-context"
+	LintDepNote            = "dep-note"
+)
+
+// DefaultLintStaleDays is the default age for the stale-no-logs rule.
+const DefaultLintStaleDays = 14
+
+// LintRule describes one planning quality check.
+type LintRule struct {
+	Name        string
+	Description string
+	Default     bool // enabled when the config does not mention it
+}
+
+// LintRules lists every rule in the order they are reported.
+var LintRules = []LintRule{
+	{LintShortDescription, "description is shorter than the minimum word count", true},
+	{LintAcceptanceCriteria, "task description has no acceptance criteria", true},
+	{LintStaleNoLogs, "open task is older than the stale threshold and has no logs", true},
+	{LintEpicContext, "epic has no shared context", true},
+	{LintDepNote, "dependency has no note explaining it", false},
+}
+
+// LintOptions configures a lint run. Use Config.LintOptions for defaults.
+type LintOptions struct {
+	Rules               map[string]bool // rule name -> enabled
+	MinDescriptionWords int
+	StaleDays           int
+	Now                 time.Time // zero means time.Now()
+}
+
+// LintIssue is one rule violation.
+type LintIssue struct {
+	Rule    string
+	Item    model.Item
+	Message string
+}
+
+// acceptanceMarkers are the phrases that count as acceptance criteria.
+var acceptanceMarkers = []string{
+	"acceptance criteria",
+	"acceptance:",
+	"done when",
+	"definition of done",
+	"- [ ]",
+	"- [x]",
+}
+
+// HasAcceptanceCriteria reports whether a description spells out when the
+// work is done: an "Acceptance criteria" section, "done when", or a checklist.
+func HasAcceptanceCriteria(description string) bool {
+	lower := strings.ToLower(description)
+	for _, marker := range acceptanceMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// LintScope returns the unfinished items lint should check: everything open,
+// in progress, or blocked in the project, or the epic and its descendants
+// when epicID is set.
+func (db *DB) LintScope(project, epicID string) ([]model.Item, error) {
+	var items []model.Item
+	if epicID != "" {
+		epic, err := db.GetItem(epicID)
+		if err != nil {
+			return nil, err
+		}
+		descendants, err := db.GetDescendants(epicID)
+		if err != nil {
+			return nil, err
+		}
+		items = append([]model.Item{*epic}, descendants...)
+	} else {
+		all, err := db.ListItemsFiltered(ListFilter{Project: project})
+		if err != nil {
+			return nil, err
+		}
+		items = all
+	}
+
+	var scope []model.Item
+	for _, item := range items {
+		switch item.Status {
+		case model.StatusDone, model.StatusCanceled:
+			continue
+		}
+		scope = append(scope, item)
+	}
+	return scope, nil
+}
+
+// Lint checks items against the enabled rules. Issues are ordered by item,
+// then by rule order in LintRules.
+func (db *DB) Lint(items []model.Item, opts LintOptions) ([]LintIssue, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	staleBefore := now.AddDate(0, 0, -opts.StaleDays)
+
+	var issues []LintIssue
+	for _, item := range items {
+		isParent := item.Type.CanHaveChildren()
+
+		if opts.Rules[LintShortDescription] {
+			if words := len(strings.Fields(item.Description)); words < opts.MinDescriptionWords {
+				issues = append(issues, LintIssue{
+					Rule:    LintShortDescription,
+					Item:    item,
+					Message: fmt.Sprintf("description has %d words (minimum %d)", words, opts.MinDescriptionWords),
+				})
+			}
+		}
+
+		if opts.Rules[LintAcceptanceCriteria] && !isParent && !HasAcceptanceCriteria(item.Description) {
+			issues = append(issues, LintIssue{
+				Rule:    LintAcceptanceCriteria,
+				Item:    item,
+				Message: "no acceptance criteria (add an \"Acceptance criteria\" section or a - [ ] checklist)",
+			})
+		}
+
+		if opts.Rules[LintStaleNoLogs] && !isParent && item.CreatedAt.Before(staleBefore) {
+			logs, err := db.GetLogs(item.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(logs) == 0 {
+				days := int(now.Sub(item.CreatedAt).Hours() / 24)
+				issues = append(issues, LintIssue{
+					Rule:    LintStaleNoLogs,
+					Item:    item,
+					Message: fmt.Sprintf("created %d days ago with no progress logged", days),
+				})
+			}
+		}
+
+		if opts.Rules[LintEpicContext] && item.Type == model.ItemTypeEpic && strings.TrimSpace(item.SharedContext) == "" {
+			issues = append(issues, LintIssue{
+				Rule:    LintEpicContext,
+				Item:    item,
+				Message: "epic has no shared context (set with: tpg epic edit " + item.ID + " --context)",
+			})
+		}
+
+		if opts.Rules[LintDepNote] {
+			deps, err := db.GetDepStatuses(item.ID)
+			if err != nil {
+				return nil, err
+			}
+			sort.Slice(deps, func(i, j int) bool { return deps[i].ID < deps[j].ID })
+			for _, dep := range deps {
+				if dep.Note == "" {
+					issues = append(issues, LintIssue{
+						Rule:    LintDepNote,
+						Item:    item,
+						Message: fmt.Sprintf("dependency on %s has no note (add with: tpg dep %s after %s --note ...)", dep.ID, item.ID, dep.ID),
+					})
+				}
+			}
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func lintRules(issues []LintIssue, itemID string) []string {
+	var rules []string
+	for _, issue := range issues {
+		if issue.Item.ID == itemID {
+			rules = append(rules, issue.Rule)
+		}
+	}
+	return rules
+}
+
+func TestLint_Rules(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	vague := createTestItem(t, db, "Vague task")
+	good := createTestItem(t, db, "Good task")
+	if err := db.SetDescription(good.ID, "Add retry handling to the sync client so transient network failures do not abort the whole run.\n\nAcceptance criteria:\n- [ ] retries three times"); err != nil {
+		t.Fatalf("SetDescription failed: %v", err)
+	}
+	if err := db.AddDep(vague.ID, good.ID); err != nil {
+		t.Fatalf("AddDep failed: %v", err)
+	}
+
+	config := &Config{}
+	opts := config.LintOptions()
+	opts.Rules[LintDepNote] = true
+	opts.Now = time.Now().AddDate(0, 0, 30)
+
+	items, err := db.LintScope("test", "")
+	if err != nil {
+		t.Fatalf("LintScope failed: %v", err)
+	}
+	issues, err := db.Lint(items, opts)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	want := map[string][]string{
+		epic.ID:  {LintShortDescription, LintEpicContext},
+		vague.ID: {LintShortDescription, LintAcceptanceCriteria, LintStaleNoLogs, LintDepNote},
+		good.ID:  {LintStaleNoLogs},
+	}
+	for id, rules := range want {
+		got := lintRules(issues, id)
+		if len(got) != len(rules) {
+			t.Errorf("%s: rules = %v, want %v", id, got, rules)
+			continue
+		}
+		for i := range rules {
+			if got[i] != rules[i] {
+				t.Errorf("%s: rules = %v, want %v", id, got, rules)
+				break
+			}
+		}
+	}
+
+	// A log clears stale-no-logs; a note clears dep-note
+	if err := db.AddLog(vague.ID, "started looking"); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+	if err := db.SetDepNote(vague.ID, good.ID, "uses the retry helper"); err != nil {
+		t.Fatalf("SetDepNote failed: %v", err)
+	}
+	issues, err = db.Lint(items, opts)
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	for _, rule := range lintRules(issues, vague.ID) {
+		if rule == LintStaleNoLogs || rule == LintDepNote {
+			t.Errorf("unexpected %s issue after log and note", rule)
+		}
+	}
+}
+
+func TestLint_ConfigDisablesRules(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Short")
+
+	config := &Config{Lint: LintConfig{Rules: map[string]bool{
+		LintShortDescription:   false,
+		LintAcceptanceCriteria: false,
+	}}}
+	issues, err := db.Lint([]model.Item{*item}, config.LintOptions())
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues with rules disabled, got %+v", issues)
+	}
+}
+
+func TestLintScope_Epic(t *testing.T) {
+	db := setupTestDB(t)
+	epic := createTestEpic(t, db, "Epic", "test")
+	child := createTestItem(t, db, "Child")
+	if err := db.SetParent(child.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	done := createTestItem(t, db, "Done child")
+	if err := db.SetParent(done.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	if err := db.UpdateStatus(done.ID, model.StatusDone, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	createTestItem(t, db, "Outside")
+
+	items, err := db.LintScope("", epic.ID)
+	if err != nil {
+		t.Fatalf("LintScope failed: %v", err)
+	}
+	if len(items) != 2 || items[0].ID != epic.ID || items[1].ID != child.ID {
+		t.Errorf("unexpected scope: %+v", items)
+	}
+}
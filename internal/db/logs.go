@@ -1,17 +1,41 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/taxilian/tpg/internal/model"
 )
 
-// AddLog adds a log entry to an item.
+// AddLog adds a log entry to an item, in the category its message declares
+// with a prefix such as "decision:" (see model.LogCategoryOf).
 func (db *DB) AddLog(itemID, message string) error {
+	return db.addLog(itemID, message, model.LogCategoryOf(message), nil)
+}
+
+// AddCategorizedLog adds a log entry to an item in the given category.
+func (db *DB) AddCategorizedLog(itemID string, category model.LogCategory, message string) error {
+	if !category.IsValid() {
+		return fmt.Errorf("invalid log category: %s", category)
+	}
+	return db.addLog(itemID, message, category, nil)
+}
+
+// AddProgressLog adds a progress log entry recording that an item is percent
+// complete.
+func (db *DB) AddProgressLog(itemID string, percent int, message string) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("progress must be between 0 and 100, got %d", percent)
+	}
+	return db.addLog(itemID, message, model.LogCategoryProgress, &percent)
+}
+
+func (db *DB) addLog(itemID, message string, category model.LogCategory, progress *int) error {
 	_, err := db.Exec(`
-		INSERT INTO logs (item_id, message) VALUES (?, ?)`,
-		itemID, message)
+		INSERT INTO logs (item_id, message, category, progress) VALUES (?, ?, ?, ?)`,
+		itemID, message, string(category), progress)
 	if err != nil {
 		return fmt.Errorf("failed to add log: %w", err)
 	}
@@ -21,11 +45,131 @@ func (db *DB) AddLog(itemID, message string) error {
 	return nil
 }
 
+// insertLog adds a log entry in the category its message declares, without
+// touching the item. Transactions that change the item use it.
+func insertLog(ex execer, itemID, message string) error {
+	_, err := ex.Exec(`INSERT INTO logs (item_id, message, category) VALUES (?, ?, ?)`,
+		itemID, message, string(model.LogCategoryOf(message)))
+	if err != nil {
+		return fmt.Errorf("failed to add log: %w", err)
+	}
+	return nil
+}
+
+// PopulateItemProgress sets Progress on each item to the percentage from its
+// most recent progress log, leaving it nil when none was recorded.
+func (db *DB) PopulateItemProgress(items []model.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]any, len(items))
+	placeholders := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+		placeholders[i] = "?"
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT item_id, progress FROM logs
+		WHERE item_id IN (%s) AND progress IS NOT NULL
+		ORDER BY created_at ASC, id ASC`, strings.Join(placeholders, ", ")), ids...)
+	if err != nil {
+		return fmt.Errorf("failed to query progress: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	latest := make(map[string]int)
+	for rows.Next() {
+		var itemID string
+		var progress int
+		if err := rows.Scan(&itemID, &progress); err != nil {
+			return fmt.Errorf("failed to scan progress: %w", err)
+		}
+		latest[itemID] = progress
+	}
+	for i := range items {
+		if p, ok := latest[items[i].ID]; ok {
+			items[i].Progress = &p
+		} else {
+			items[i].Progress = nil
+		}
+	}
+	return rows.Err()
+}
+
+// GetLog returns a single log entry by ID.
+func (db *DB) GetLog(id int64) (*model.Log, error) {
+	var log model.Log
+	err := db.QueryRow(`
+		SELECT id, item_id, message, category, progress, created_at
+		FROM logs WHERE id = ?`, id).Scan(&log.ID, &log.ItemID, &log.Message, &log.Category, &log.Progress, &log.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("log not found: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log: %w", err)
+	}
+	return &log, nil
+}
+
+// EditLog replaces the message of a log entry. The old text is not kept in
+// history so that pasted secrets can be scrubbed. A category prefix on the new
+// message recategorizes the entry; otherwise its category is kept.
+func (db *DB) EditLog(id int64, message string) (*model.Log, error) {
+	if strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("log message cannot be empty")
+	}
+	log, err := db.GetLog(id)
+	if err != nil {
+		return nil, err
+	}
+	if c := model.LogCategoryOf(message); c != model.LogCategoryNote {
+		log.Category = c
+	}
+	if _, err := db.Exec(`UPDATE logs SET message = ?, category = ? WHERE id = ?`, message, string(log.Category), id); err != nil {
+		return nil, fmt.Errorf("failed to edit log: %w", err)
+	}
+	_ = db.RecordHistory(log.ItemID, EventTypeLogEdited, map[string]any{"log_id": id})
+	log.Message = message
+	return log, nil
+}
+
+// DeleteLog removes a log entry and returns what was removed.
+func (db *DB) DeleteLog(id int64) (*model.Log, error) {
+	log, err := db.GetLog(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`DELETE FROM logs WHERE id = ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to delete log: %w", err)
+	}
+	_ = db.RecordHistory(log.ItemID, EventTypeLogRemoved, map[string]any{"log_id": id})
+	return log, nil
+}
+
 // GetLogs retrieves all logs for an item, ordered by creation time.
 func (db *DB) GetLogs(itemID string) ([]model.Log, error) {
-	rows, err := db.Query(`
-		SELECT id, item_id, message, created_at
-		FROM logs WHERE item_id = ? ORDER BY created_at ASC`, itemID)
+	return db.QueryLogs(LogQueryOptions{ItemID: itemID})
+}
+
+// LogQueryOptions configures log queries.
+type LogQueryOptions struct {
+	ItemID     string              // Filter by item
+	Categories []model.LogCategory // Filter by category
+	Since      time.Time           // Filter by time (entries >= since)
+	Limit      int                 // Keep only the most recent entries (0 for all)
+}
+
+// QueryLogs returns the logs matching opts, ordered by creation time.
+func (db *DB) QueryLogs(opts LogQueryOptions) ([]model.Log, error) {
+	query, args := appendLogFilters(`SELECT id, item_id, message, category, progress, created_at FROM logs WHERE 1=1`, nil, opts)
+	if opts.Limit > 0 {
+		query = `SELECT * FROM (` + query + ` ORDER BY created_at DESC, id DESC LIMIT ?)`
+		args = append(args, opts.Limit)
+	}
+	query += ` ORDER BY created_at ASC, id ASC`
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get logs: %w", err)
 	}
@@ -34,10 +178,93 @@ func (db *DB) GetLogs(itemID string) ([]model.Log, error) {
 	var logs []model.Log
 	for rows.Next() {
 		var log model.Log
-		if err := rows.Scan(&log.ID, &log.ItemID, &log.Message, &log.CreatedAt); err != nil {
+		if err := rows.Scan(&log.ID, &log.ItemID, &log.Message, &log.Category, &log.Progress, &log.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan log: %w", err)
 		}
 		logs = append(logs, log)
 	}
 	return logs, rows.Err()
 }
+
+// appendLogFilters adds the item, category, and time filters of opts to a
+// query over the logs table.
+func appendLogFilters(query string, args []any, opts LogQueryOptions) (string, []any) {
+	if opts.ItemID != "" {
+		query += ` AND logs.item_id = ?`
+		args = append(args, opts.ItemID)
+	}
+	if len(opts.Categories) > 0 {
+		placeholders := make([]string, len(opts.Categories))
+		for i, c := range opts.Categories {
+			placeholders[i] = "?"
+			args = append(args, string(c))
+		}
+		query += fmt.Sprintf(` AND logs.category IN (%s)`, strings.Join(placeholders, ", "))
+	}
+	if !opts.Since.IsZero() {
+		query += ` AND logs.created_at >= ?`
+		args = append(args, sqlTime(opts.Since))
+	}
+	return query, args
+}
+
+// LogMatch is a log entry found by SearchLogs, with the item it belongs to.
+type LogMatch struct {
+	Log  model.Log
+	Item model.Item
+}
+
+// SearchLogs finds log entries whose message matches an FTS5 query, best
+// matches first. An empty project searches every project; opts narrows the
+// search by category and time, and Limit caps the number of matches.
+func (db *DB) SearchLogs(project, query string, opts LogQueryOptions) ([]LogMatch, error) {
+	sqlQuery := `
+		SELECT logs.id, logs.item_id, logs.message, logs.category, logs.progress, logs.created_at
+		FROM logs
+		JOIN logs_fts ON logs.id = logs_fts.rowid
+		JOIN items ON items.id = logs.item_id
+		WHERE logs_fts MATCH ?`
+	args := []any{query}
+	if project != "" {
+		sqlQuery += ` AND items.project = ?`
+		args = append(args, project)
+	}
+	sqlQuery, args = appendLogFilters(sqlQuery, args, opts)
+	sqlQuery += ` ORDER BY logs_fts.rank, logs.created_at DESC`
+	if opts.Limit > 0 {
+		sqlQuery += ` LIMIT ?`
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search logs: %w", err)
+	}
+	var logs []model.Log
+	for rows.Next() {
+		var log model.Log
+		if err := rows.Scan(&log.ID, &log.ItemID, &log.Message, &log.Category, &log.Progress, &log.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		logs = append(logs, log)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]*model.Item)
+	matches := make([]LogMatch, 0, len(logs))
+	for _, log := range logs {
+		item, ok := items[log.ItemID]
+		if !ok {
+			if item, err = db.GetItem(log.ItemID); err != nil {
+				return nil, err
+			}
+			items[log.ItemID] = item
+		}
+		matches = append(matches, LogMatch{Log: log, Item: *item})
+	}
+	return matches, nil
+}
@@ -106,3 +106,256 @@ func TestGetLogs_Order(t *testing.T) {
 		t.Error("logs not in chronological order")
 	}
 }
+
+func TestAddProgressLog(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Progress")
+	other := createTestItem(t, db, "No progress")
+
+	if err := db.AddProgressLog(item.ID, 30, "progress: started"); err != nil {
+		t.Fatalf("AddProgressLog failed: %v", err)
+	}
+	if err := db.AddLog(item.ID, "plain note"); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+	if err := db.AddProgressLog(item.ID, 60, "progress: core logic done"); err != nil {
+		t.Fatalf("AddProgressLog failed: %v", err)
+	}
+	if err := db.AddLog(other.ID, "plain note"); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+
+	logs, err := db.GetLogs(item.ID)
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if logs[0].Progress == nil || *logs[0].Progress != 30 {
+		t.Errorf("first log progress = %v, want 30", logs[0].Progress)
+	}
+	if logs[1].Progress != nil {
+		t.Errorf("plain log progress = %d, want nil", *logs[1].Progress)
+	}
+
+	items := []model.Item{*item, *other}
+	if err := db.PopulateItemProgress(items); err != nil {
+		t.Fatalf("PopulateItemProgress failed: %v", err)
+	}
+	if items[0].Progress == nil || *items[0].Progress != 60 {
+		t.Errorf("item progress = %v, want 60", items[0].Progress)
+	}
+	if items[1].Progress != nil {
+		t.Errorf("other progress = %d, want nil", *items[1].Progress)
+	}
+}
+
+func TestAddProgressLog_OutOfRange(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Progress")
+
+	for _, percent := range []int{-1, 101} {
+		if err := db.AddProgressLog(item.ID, percent, "bad"); err == nil {
+			t.Errorf("AddProgressLog(%d) succeeded, want error", percent)
+		}
+	}
+	logs, _ := db.GetLogs(item.ID)
+	if len(logs) != 0 {
+		t.Errorf("got %d logs, want 0", len(logs))
+	}
+}
+
+func TestEditLog(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Test")
+	if err := db.AddLog(item.ID, "token=sekrit"); err != nil {
+		t.Fatalf("failed to add log: %v", err)
+	}
+	logs, _ := db.GetLogs(item.ID)
+
+	edited, err := db.EditLog(logs[0].ID, "token=[redacted]")
+	if err != nil {
+		t.Fatalf("EditLog failed: %v", err)
+	}
+	if edited.ItemID != item.ID || edited.Message != "token=[redacted]" {
+		t.Errorf("EditLog returned %+v", edited)
+	}
+	logs, _ = db.GetLogs(item.ID)
+	if len(logs) != 1 || logs[0].Message != "token=[redacted]" {
+		t.Errorf("logs = %+v, want edited message", logs)
+	}
+
+	history, err := db.GetItemHistory(item.ID, 0)
+	if err != nil {
+		t.Fatalf("GetItemHistory failed: %v", err)
+	}
+	var event *HistoryEntry
+	for i := range history {
+		if history[i].EventType == EventTypeLogEdited {
+			event = &history[i]
+		}
+	}
+	if event == nil {
+		t.Fatalf("no %s event in history: %+v", EventTypeLogEdited, history)
+	}
+	if _, hasOld := event.Changes["old"]; hasOld {
+		t.Error("history must not keep the old log text")
+	}
+
+	if _, err := db.EditLog(logs[0].ID, "  "); err == nil {
+		t.Error("expected error for empty message")
+	}
+	if _, err := db.EditLog(9999, "x"); err == nil {
+		t.Error("expected error for missing log")
+	}
+}
+
+func TestDeleteLog(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Test")
+	if err := db.AddLog(item.ID, "keep"); err != nil {
+		t.Fatalf("failed to add log: %v", err)
+	}
+	if err := db.AddLog(item.ID, "oops"); err != nil {
+		t.Fatalf("failed to add log: %v", err)
+	}
+	logs, _ := db.GetLogs(item.ID)
+
+	removed, err := db.DeleteLog(logs[1].ID)
+	if err != nil {
+		t.Fatalf("DeleteLog failed: %v", err)
+	}
+	if removed.Message != "oops" {
+		t.Errorf("removed = %q, want oops", removed.Message)
+	}
+	logs, _ = db.GetLogs(item.ID)
+	if len(logs) != 1 || logs[0].Message != "keep" {
+		t.Errorf("logs = %+v, want only 'keep'", logs)
+	}
+	if _, err := db.DeleteLog(removed.ID); err == nil {
+		t.Error("expected error deleting a missing log")
+	}
+}
+
+func TestQueryLogs_Categories(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Test")
+
+	if err := db.AddLog(item.ID, "looked around"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddLog(item.ID, "Decision: use sqlite"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddCategorizedLog(item.ID, model.LogCategoryBlocker, "waiting on API keys"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddProgressLog(item.ID, 50, "halfway"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddCategorizedLog(item.ID, "bogus", "x"); err == nil {
+		t.Error("AddCategorizedLog accepted an invalid category")
+	}
+
+	logs, err := db.GetLogs(item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.LogCategory{model.LogCategoryNote, model.LogCategoryDecision, model.LogCategoryBlocker, model.LogCategoryProgress}
+	if len(logs) != len(want) {
+		t.Fatalf("got %d logs, want %d", len(logs), len(want))
+	}
+	for i, c := range want {
+		if logs[i].Category != c {
+			t.Errorf("log %d (%q) category = %q, want %q", i, logs[i].Message, logs[i].Category, c)
+		}
+	}
+
+	logs, err = db.QueryLogs(LogQueryOptions{ItemID: item.ID, Categories: []model.LogCategory{model.LogCategoryDecision, model.LogCategoryBlocker}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].Message != "Decision: use sqlite" || logs[1].Message != "waiting on API keys" {
+		t.Errorf("filtered logs = %+v, want the decision then the blocker", logs)
+	}
+
+	logs, err = db.QueryLogs(LogQueryOptions{ItemID: item.ID, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Message != "halfway" {
+		t.Errorf("limited logs = %+v, want only the latest", logs)
+	}
+
+	edited, err := db.EditLog(logs[0].ID, "blocker: CI is down")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Category != model.LogCategoryBlocker {
+		t.Errorf("edited category = %q, want blocker", edited.Category)
+	}
+}
+
+func TestSearchLogs(t *testing.T) {
+	db := setupTestDB(t)
+	auth := createTestItem(t, db, "Auth")
+	cache := createTestItem(t, db, "Cache")
+	other := createTestItemWithProject(t, db, "Elsewhere", "other", model.StatusOpen, 2)
+
+	if err := db.AddLog(auth.ID, "decision: use sqlite for the session store"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddLog(cache.ID, "Tried sqlite, too slow for the hot path"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddLog(other.ID, "sqlite everywhere"); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := db.SearchLogs("test", "sqlite", LogQueryOptions{})
+	if err != nil {
+		t.Fatalf("SearchLogs: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("search in project = %d matches, want 2", len(matches))
+	}
+	for _, m := range matches {
+		if m.Item.ID != m.Log.ItemID || m.Item.Title == "" {
+			t.Errorf("match %+v is missing its item", m)
+		}
+	}
+	if matches, _ := db.SearchLogs("", "sqlite", LogQueryOptions{}); len(matches) != 3 {
+		t.Errorf("search across projects = %d matches, want 3", len(matches))
+	}
+	matches, err = db.SearchLogs("", "sqlite", LogQueryOptions{Categories: []model.LogCategory{model.LogCategoryDecision}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Item.ID != auth.ID {
+		t.Errorf("decision search = %+v, want only the %s decision", matches, auth.ID)
+	}
+	if matches, _ := db.SearchLogs("", "sqlite", LogQueryOptions{Since: time.Now().Add(time.Hour)}); len(matches) != 0 {
+		t.Errorf("search since the future = %d matches, want 0", len(matches))
+	}
+	if matches, _ := db.SearchLogs("", `"hot path"`, LogQueryOptions{}); len(matches) != 1 {
+		t.Errorf("phrase search = %d matches, want 1", len(matches))
+	}
+
+	// Edited and deleted logs are re-indexed
+	logs, _ := db.GetLogs(cache.ID)
+	if _, err := db.EditLog(logs[0].ID, "Tried redis instead"); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := db.SearchLogs("test", "sqlite", LogQueryOptions{}); len(matches) != 1 {
+		t.Errorf("search after edit = %d matches, want 1", len(matches))
+	}
+	logs, _ = db.GetLogs(auth.ID)
+	if _, err := db.DeleteLog(logs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := db.SearchLogs("test", "sqlite", LogQueryOptions{}); len(matches) != 0 {
+		t.Errorf("search after delete = %d matches, want 0", len(matches))
+	}
+
+	if _, err := db.SearchLogs("", `"unterminated`, LogQueryOptions{}); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+}
@@ -129,12 +129,43 @@ func (db *DB) MergeItems(sourceID, targetID string) error {
 	}
 	_, _ = db.Exec(`DELETE FROM item_labels WHERE item_id = ?`, sourceID)
 
+	// Copy custom fields the target doesn't already have
+	_, err = db.Exec(`
+		INSERT OR IGNORE INTO item_fields (item_id, key, value)
+		SELECT ?, key, value FROM item_fields WHERE item_id = ?`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer fields: %w", err)
+	}
+	_, _ = db.Exec(`DELETE FROM item_fields WHERE item_id = ?`, sourceID)
+
+	// Previous descriptions go with the source's text into the target
+	_, err = db.Exec(`UPDATE description_versions SET item_id = ? WHERE item_id = ?`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer description versions: %w", err)
+	}
+
+	_, err = db.Exec(`UPDATE transcripts SET item_id = ? WHERE item_id = ?`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer transcripts: %w", err)
+	}
+
+	// The source's alias moves to the target unless the target has one
+	_, _ = db.Exec(`UPDATE OR IGNORE item_aliases SET item_id = ? WHERE item_id = ?`, targetID, sourceID)
+	_, _ = db.Exec(`DELETE FROM item_aliases WHERE item_id = ?`, sourceID)
+
+	// Group dependencies move to the target, skipping ones it already has
+	_, _ = db.Exec(`UPDATE OR IGNORE group_deps SET item_id = ? WHERE item_id = ?`, targetID, sourceID)
+	_, _ = db.Exec(`DELETE FROM group_deps WHERE item_id = ?`, sourceID)
+	_, _ = db.Exec(`UPDATE OR IGNORE group_deps SET epic_id = ? WHERE epic_id = ?`, targetID, sourceID)
+	_, _ = db.Exec(`DELETE FROM group_deps WHERE epic_id = ?`, sourceID)
+
 	// 6. Append source description to target if non-empty
 	if srcItem.Description != "" {
 		sep := ""
 		if tgtItem.Description != "" {
 			sep = "\n\n---\nMerged from " + sourceID + ":\n"
 		}
+		db.saveDescriptionVersion(targetID, tgtItem.Description)
 		_, err = db.Exec(`UPDATE items SET description = description || ?, updated_at = ? WHERE id = ?`,
 			sep+srcItem.Description, sqlTime(time.Now()), targetID)
 		if err != nil {
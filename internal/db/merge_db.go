@@ -0,0 +1,417 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EventTypeMergedFromDB records that 'tpg merge-db' added or changed an item.
+const EventTypeMergedFromDB = "merged_from_db"
+
+// OpenCopy opens a private copy of the database at path, migrated to the
+// current schema, so it can be read without changing the original. The
+// cleanup function closes the copy and deletes it.
+func OpenCopy(path string) (*DB, func(), error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, fmt.Errorf("cannot read database: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "tpg-copy-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	copyPath := filepath.Join(dir, "tpg.db")
+
+	src, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err == nil {
+		_, err = src.Exec(fmt.Sprintf("VACUUM INTO '%s'", strings.ReplaceAll(copyPath, "'", "''")))
+		_ = src.Close()
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("failed to copy %s: %w", path, err)
+	}
+
+	copyDB, err := Open(copyPath)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	cleanup := func() {
+		_ = copyDB.Close()
+		_ = os.RemoveAll(dir)
+	}
+	if ok, err := copyDB.tableExists("items"); err != nil || !ok {
+		cleanup()
+		return nil, nil, fmt.Errorf("%s is not a tpg database", path)
+	}
+	if version, err := copyDB.getSchemaVersion(); err == nil && version > SchemaVersion {
+		cleanup()
+		return nil, nil, fmt.Errorf("%s is at schema version %d, newer than this tpg supports (%d); upgrade tpg", path, version, SchemaVersion)
+	}
+	if err := copyDB.migrate(false); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to migrate copy of %s: %w", path, err)
+	}
+	return copyDB, cleanup, nil
+}
+
+// MergeReport describes what MergeDatabase changed and which conflicts it
+// resolved.
+type MergeReport struct {
+	Added     []string          // Items copied from the other database
+	Updated   []string          // Items where the other database's copy won
+	Renamed   map[string]string // Other database's ID -> new ID, for ID collisions
+	Labels    int               // Labels attached to existing items
+	Fields    int               // Custom fields added to existing items
+	Deps      int               // Dependencies added
+	Logs      int               // Log entries added
+	Conflicts []string          // One line per conflict and how it was resolved
+}
+
+// MergeDatabase merges the items, dependencies, and logs of other into db.
+// The rules are deterministic, so merging the same databases always gives the
+// same result:
+//
+//   - An item only in other is added with its labels, fields, and logs.
+//   - An item in both (same ID and creation time) takes the scalar fields of
+//     whichever copy was updated last; on a tie ours is kept. Labels, custom
+//     fields missing from one side, and logs are unioned.
+//   - The same ID with a different creation time is an ID collision: the
+//     other item is added under a new ID and references to it are rewritten.
+//   - Dependencies are unioned, except ones that would close a cycle.
+//
+// Every difference resolved by a rule is listed in the report's Conflicts.
+func (db *DB) MergeDatabase(other *DB, source string) (*MergeReport, error) {
+	report := &MergeReport{Renamed: make(map[string]string)}
+
+	theirs, err := other.queryItems(fmt.Sprintf("SELECT %s FROM items ORDER BY created_at, id", itemSelectColumns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read items to merge: %w", err)
+	}
+	if err := other.PopulateItemFields(theirs); err != nil {
+		return nil, err
+	}
+	oursList, err := db.queryItems(fmt.Sprintf("SELECT %s FROM items", itemSelectColumns))
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PopulateItemFields(oursList); err != nil {
+		return nil, err
+	}
+	ours := make(map[string]*model.Item, len(oursList))
+	for i := range oursList {
+		ours[oursList[i].ID] = &oursList[i]
+	}
+
+	// Their ID -> our ID, so parents, deps, and logs follow renamed items.
+	ids := make(map[string]string, len(theirs))
+	for _, item := range theirs {
+		ids[item.ID] = item.ID
+		if mine, ok := ours[item.ID]; ok && !mine.CreatedAt.Equal(item.CreatedAt) {
+			newID := collisionID(item)
+			if renamed, ok := ours[newID]; ok && renamed.CreatedAt.Equal(item.CreatedAt) {
+				// Renamed by an earlier merge of the same database.
+				ids[item.ID] = newID
+				continue
+			} else if ok {
+				prefix := item.ID[:strings.LastIndex(item.ID, "-")+1]
+				if newID, err = db.GenerateItemIDWithPrefix(prefix, item.Type); err != nil {
+					return nil, err
+				}
+			}
+			ids[item.ID] = newID
+			report.Renamed[item.ID] = newID
+			report.Conflicts = append(report.Conflicts,
+				fmt.Sprintf("%s: ID used by different items on each side; theirs added as %s", item.ID, newID))
+		}
+	}
+
+	// Items first, without parents, since a parent may come later.
+	parents := make(map[string]string)
+	for _, item := range theirs {
+		id := ids[item.ID]
+		if item.ParentID != nil {
+			parents[id] = ids[*item.ParentID]
+		}
+		if item.NeedsApproval, err = other.NeedsApproval(item.ID); err != nil {
+			return nil, err
+		}
+		if mine, ok := ours[id]; ok {
+			if mine.NeedsApproval, err = db.NeedsApproval(id); err != nil {
+				return nil, err
+			}
+			if err := db.mergeItem(mine, item, ids, source, report); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		added := item
+		added.ID = id
+		added.ParentID = nil
+		if err := db.RestoreItem(&added); err != nil {
+			return nil, err
+		}
+		labels, err := other.GetItemLabels(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			if err := db.AddLabelToItem(id, added.Project, label.Name); err != nil {
+				return nil, fmt.Errorf("%s: %w", id, err)
+			}
+		}
+		if item.NeedsApproval {
+			if _, err := db.Exec(`UPDATE items SET needs_approval = 1 WHERE id = ?`, id); err != nil {
+				return nil, fmt.Errorf("%s: %w", id, err)
+			}
+		}
+		action := "added"
+		if id != item.ID {
+			action = "added from " + item.ID
+		}
+		_ = db.RecordHistory(id, EventTypeMergedFromDB, map[string]any{"source": source, "action": action})
+		report.Added = append(report.Added, id)
+	}
+	for _, id := range slices.Concat(report.Added, report.Updated) {
+		var parent any
+		if p, ok := parents[id]; ok {
+			parent = p
+		}
+		if _, err := db.Exec(`UPDATE items SET parent_id = ? WHERE id = ?`, parent, id); err != nil {
+			return nil, fmt.Errorf("failed to set parent of %s: %w", id, err)
+		}
+	}
+
+	for _, item := range theirs {
+		if _, ok := ours[ids[item.ID]]; ok {
+			if err := db.mergeLabels(other, item.ID, ids[item.ID], report); err != nil {
+				return nil, err
+			}
+		}
+		if err := db.mergeLogs(other, item.ID, ids[item.ID], report); err != nil {
+			return nil, err
+		}
+	}
+	if err := db.mergeDeps(other, ids, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// collisionID is the ID an item gets when its ID is taken by a different
+// item. It is derived from the ID and creation time, so merging the same
+// database again finds the item under the same new ID.
+func collisionID(item model.Item) string {
+	cut := strings.LastIndex(item.ID, "-") + 1
+	sum := sha256.Sum256([]byte(item.ID + "@" + item.CreatedAt.UTC().Format(time.RFC3339)))
+	return item.ID[:cut] + hex.EncodeToString(sum[:])[:len(item.ID)-cut+2]
+}
+
+// mergeItem reconciles our copy of an item with theirs: the copy updated
+// last wins every scalar field that differs, and a description it replaces
+// is kept as a version. MergeDatabase sets the parent afterwards, as it may
+// be an item the merge hasn't added yet.
+func (db *DB) mergeItem(mine *model.Item, theirs model.Item, ids map[string]string, source string, report *MergeReport) error {
+	theirParent := ""
+	if theirs.ParentID != nil {
+		theirParent = ids[*theirs.ParentID]
+	}
+	myParent := ""
+	if mine.ParentID != nil {
+		myParent = *mine.ParentID
+	}
+
+	var differ []string
+	check := func(name string, same bool) {
+		if !same {
+			differ = append(differ, name)
+		}
+	}
+	check("type", mine.Type == theirs.Type)
+	check("title", mine.Title == theirs.Title)
+	check("description", mine.Description == theirs.Description)
+	check("status", mine.Status == theirs.Status)
+	check("priority", mine.Priority == theirs.Priority)
+	check("parent", myParent == theirParent)
+	check("results", mine.Results == theirs.Results)
+	check("needs approval", mine.NeedsApproval == theirs.NeedsApproval)
+	var changedFields []string
+	for _, key := range slices.Sorted(maps.Keys(theirs.Fields)) {
+		if value, ok := mine.Fields[key]; ok && value != theirs.Fields[key] {
+			changedFields = append(changedFields, key)
+		}
+	}
+	if len(changedFields) > 0 {
+		differ = append(differ, "fields "+strings.Join(changedFields, ", "))
+	}
+
+	theirsWin := len(differ) > 0 && theirs.UpdatedAt.After(mine.UpdatedAt)
+	if len(differ) > 0 {
+		resolution := "kept ours (updated last)"
+		if theirsWin {
+			resolution = "took theirs (updated last)"
+		} else if theirs.UpdatedAt.Equal(mine.UpdatedAt) {
+			resolution = "kept ours (updated at the same time)"
+		}
+		report.Conflicts = append(report.Conflicts,
+			fmt.Sprintf("%s: differs in %s; %s", mine.ID, strings.Join(differ, ", "), resolution))
+	}
+
+	if theirsWin {
+		var closedAt any
+		if theirs.ClosedAt != nil {
+			closedAt = sqlTime(*theirs.ClosedAt)
+		}
+		if theirs.Description != mine.Description {
+			db.saveDescriptionVersion(mine.ID, mine.Description)
+		}
+		if _, err := db.Exec(`
+			UPDATE items SET type = ?, title = ?, description = ?, status = ?, priority = ?,
+				results = ?, needs_approval = ?, closed_at = ?, updated_at = ?
+			WHERE id = ?`,
+			theirs.Type, theirs.Title, theirs.Description, theirs.Status, theirs.Priority,
+			theirs.Results, theirs.NeedsApproval, closedAt, sqlTime(theirs.UpdatedAt), mine.ID); err != nil {
+			return fmt.Errorf("failed to update %s: %w", mine.ID, err)
+		}
+		for _, key := range changedFields {
+			if _, err := db.Exec(`UPDATE item_fields SET value = ? WHERE item_id = ? AND key = ?`, theirs.Fields[key], mine.ID, key); err != nil {
+				return fmt.Errorf("failed to update field %s of %s: %w", key, mine.ID, err)
+			}
+		}
+		_ = db.RecordHistory(mine.ID, EventTypeMergedFromDB, map[string]any{
+			"source": source, "action": "updated", "fields": strings.Join(differ, ", "),
+		})
+		report.Updated = append(report.Updated, mine.ID)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(theirs.Fields)) {
+		if _, ok := mine.Fields[key]; ok {
+			continue
+		}
+		if _, err := db.Exec(`INSERT INTO item_fields (item_id, key, value) VALUES (?, ?, ?)`, mine.ID, key, theirs.Fields[key]); err != nil {
+			return fmt.Errorf("failed to add field %s to %s: %w", key, mine.ID, err)
+		}
+		report.Fields++
+	}
+	return nil
+}
+
+// mergeLabels attaches the labels their copy of an item has and ours lacks.
+func (db *DB) mergeLabels(other *DB, theirID, ourID string, report *MergeReport) error {
+	theirLabels, err := other.GetItemLabels(theirID)
+	if err != nil || len(theirLabels) == 0 {
+		return err
+	}
+	ourLabels, err := db.GetItemLabels(ourID)
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(ourLabels))
+	for _, label := range ourLabels {
+		have[label.Name] = true
+	}
+	for _, label := range theirLabels {
+		if have[label.Name] {
+			continue
+		}
+		if err := db.AddLabelToItem(ourID, label.Project, label.Name); err != nil {
+			return fmt.Errorf("%s: %w", ourID, err)
+		}
+		report.Labels++
+	}
+	return nil
+}
+
+// mergeLogs adds their log entries for an item that ours lacks. Entries are
+// the same when their message and time match.
+func (db *DB) mergeLogs(other *DB, theirID, ourID string, report *MergeReport) error {
+	theirLogs, err := other.GetLogs(theirID)
+	if err != nil || len(theirLogs) == 0 {
+		return err
+	}
+	ourLogs, err := db.GetLogs(ourID)
+	if err != nil {
+		return err
+	}
+	type logKey struct {
+		message string
+		at      int64
+	}
+	have := make(map[logKey]bool, len(ourLogs))
+	for _, l := range ourLogs {
+		have[logKey{l.Message, l.CreatedAt.Unix()}] = true
+	}
+	for _, l := range theirLogs {
+		if have[logKey{l.Message, l.CreatedAt.Unix()}] {
+			continue
+		}
+		l.ItemID = ourID
+		if err := db.RestoreLog(l); err != nil {
+			return err
+		}
+		report.Logs++
+	}
+	return nil
+}
+
+// mergeDeps adds their dependencies that ours lacks, skipping any that would
+// close a cycle with ours.
+func (db *DB) mergeDeps(other *DB, ids map[string]string, report *MergeReport) error {
+	rows, err := other.Query(`SELECT item_id, depends_on FROM deps ORDER BY item_id, depends_on`)
+	if err != nil {
+		return fmt.Errorf("failed to read dependencies to merge: %w", err)
+	}
+	var edges [][2]string
+	for rows.Next() {
+		var itemID, dependsOn string
+		if err := rows.Scan(&itemID, &dependsOn); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		edges = append(edges, [2]string{ids[itemID], ids[dependsOn]})
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	graph, err := loadDepGraph(db)
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		var exists int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM deps WHERE item_id = ? AND depends_on = ?`, edge[0], edge[1]).Scan(&exists); err != nil {
+			return err
+		}
+		if exists > 0 {
+			continue
+		}
+		if cycle := graph.cycle(edge[0], edge[1]); cycle != nil {
+			hops := make([]string, len(cycle))
+			for i, hop := range cycle {
+				hops[i] = hop.String()
+			}
+			report.Conflicts = append(report.Conflicts,
+				fmt.Sprintf("%s depends on %s: skipped, it would close a cycle (%s)", edge[0], edge[1], strings.Join(hops, ", ")))
+			continue
+		}
+		if _, err := db.RestoreDep(edge[0], edge[1]); err != nil {
+			return err
+		}
+		graph.addDep(edge[0], edge[1])
+		report.Deps++
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func restoreMergeTestItem(t *testing.T, db *DB, id, title, parent string, created, updated time.Time) {
+	t.Helper()
+	item := &model.Item{
+		ID: id, Project: "test", Type: model.ItemTypeTask, Title: title,
+		Status: model.StatusOpen, Priority: 2, CreatedAt: created, UpdatedAt: updated,
+	}
+	if strings.HasPrefix(id, "ep-") {
+		item.Type = model.ItemTypeEpic
+	}
+	if parent != "" {
+		item.ParentID = &parent
+	}
+	if err := db.RestoreItem(item); err != nil {
+		t.Fatalf("RestoreItem(%s): %v", id, err)
+	}
+}
+
+func TestMergeDatabase(t *testing.T) {
+	ours := setupTestDB(t)
+	chdirWithConfig(t, `{}`)
+
+	otherPath := filepath.Join(t.TempDir(), "other.db")
+	other, err := Open(otherPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, db := range []*DB{ours, other} {
+		restoreMergeTestItem(t, db, "ts-shared", "Shared", "", base, base)
+		restoreMergeTestItem(t, db, "ts-a", "A", "", base, base)
+		restoreMergeTestItem(t, db, "ts-b", "B", "", base, base)
+	}
+	// Ours renamed the shared task first; theirs renamed it later and won.
+	if _, err := ours.Exec(`UPDATE items SET title = 'Ours', description = 'Our notes', updated_at = ? WHERE id = 'ts-shared'`, sqlTime(base.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Exec(`UPDATE items SET title = 'Theirs', description = 'Their notes', needs_approval = 1, updated_at = ? WHERE id = 'ts-shared'`, sqlTime(base.Add(2*time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	// Each side created a different ts-dup.
+	restoreMergeTestItem(t, ours, "ts-dup", "Our dup", "", base.Add(time.Minute), base.Add(time.Minute))
+	restoreMergeTestItem(t, other, "ts-dup", "Their dup", "", base.Add(2*time.Minute), base.Add(2*time.Minute))
+	// Theirs added an epic with a child that depends on their ts-dup.
+	restoreMergeTestItem(t, other, "ep-new", "New epic", "", base, base)
+	restoreMergeTestItem(t, other, "ts-new", "New child", "ep-new", base, base)
+	if _, err := other.RestoreDep("ts-new", "ts-dup"); err != nil {
+		t.Fatal(err)
+	}
+	// Opposite deps between ts-a and ts-b would close a cycle.
+	if _, err := ours.RestoreDep("ts-a", "ts-b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.RestoreDep("ts-b", "ts-a"); err != nil {
+		t.Fatal(err)
+	}
+	shared := model.Log{ItemID: "ts-shared", Message: "both sides", CreatedAt: base}
+	for _, db := range []*DB{ours, other} {
+		if err := db.RestoreLog(shared); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := other.RestoreLog(model.Log{ItemID: "ts-shared", Message: "theirs only", CreatedAt: base.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	_ = other.Close()
+
+	copyDB, cleanup, err := OpenCopy(otherPath)
+	if err != nil {
+		t.Fatalf("OpenCopy: %v", err)
+	}
+	defer cleanup()
+
+	report, err := ours.MergeDatabase(copyDB, otherPath)
+	if err != nil {
+		t.Fatalf("MergeDatabase: %v", err)
+	}
+
+	newDup := report.Renamed["ts-dup"]
+	if newDup == "" || !strings.HasPrefix(newDup, "ts-") {
+		t.Fatalf("renamed = %v, want ts-dup given a new ts- ID", report.Renamed)
+	}
+	slices.Sort(report.Added)
+	wantAdded := []string{"ep-new", newDup, "ts-new"}
+	slices.Sort(wantAdded)
+	if !slices.Equal(report.Added, wantAdded) {
+		t.Errorf("added = %v, want %v", report.Added, wantAdded)
+	}
+	if !slices.Equal(report.Updated, []string{"ts-shared"}) {
+		t.Errorf("updated = %v, want [ts-shared]", report.Updated)
+	}
+	if report.Deps != 1 || report.Logs != 1 {
+		t.Errorf("deps = %d, logs = %d; want 1 and 1", report.Deps, report.Logs)
+	}
+	if len(report.Conflicts) != 3 {
+		t.Errorf("conflicts = %q, want the collision, the title, and the cycle", report.Conflicts)
+	}
+
+	if item, _ := ours.GetItem("ts-shared"); item.Title != "Theirs" || item.Description != "Their notes" {
+		t.Errorf("ts-shared title = %q, description = %q; want the later copy's", item.Title, item.Description)
+	}
+	if needs, _ := ours.NeedsApproval("ts-shared"); !needs {
+		t.Error("ts-shared needs_approval was not taken from the later copy")
+	}
+	if versions, _ := ours.DescriptionVersions("ts-shared"); len(versions) != 1 || versions[0].Description != "Our notes" {
+		t.Errorf("ts-shared description versions = %+v, want our replaced description", versions)
+	}
+	if item, _ := ours.GetItem("ts-dup"); item.Title != "Our dup" {
+		t.Errorf("ts-dup title = %q, want ours kept", item.Title)
+	}
+	child, err := ours.GetItem("ts-new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.ParentID == nil || *child.ParentID != "ep-new" {
+		t.Errorf("ts-new parent = %v, want ep-new", child.ParentID)
+	}
+	if deps, _ := ours.GetDeps("ts-new"); !slices.Equal(deps, []string{newDup}) {
+		t.Errorf("ts-new deps = %v, want [%s]", deps, newDup)
+	}
+	if deps, _ := ours.GetDeps("ts-b"); len(deps) != 0 {
+		t.Errorf("ts-b deps = %v, want the cycle skipped", deps)
+	}
+	if logs, _ := ours.GetLogs("ts-shared"); len(logs) != 2 {
+		t.Errorf("ts-shared has %d logs, want 2", len(logs))
+	}
+
+	// Merging again changes nothing.
+	report, err = ours.MergeDatabase(copyDB, otherPath)
+	if err != nil {
+		t.Fatalf("second MergeDatabase: %v", err)
+	}
+	if len(report.Added) != 0 || len(report.Updated) != 0 || report.Logs != 0 || report.Deps != 0 {
+		t.Errorf("second merge changed things: %+v", report)
+	}
+}
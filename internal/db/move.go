@@ -0,0 +1,196 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// MoveResult describes a subtree move.
+type MoveResult struct {
+	Moved       []string // the item followed by all of its descendants
+	OldWorktree string   // branch inherited before the move ("" if none)
+	NewWorktree string   // branch inherited after the move ("" if none)
+}
+
+// BranchOrNone returns branch, or "(none)" when it is empty, for showing a
+// MoveResult's worktree branches.
+func BranchOrNone(branch string) string {
+	if branch == "" {
+		return "(none)"
+	}
+	return branch
+}
+
+// WorktreeChanged reports whether the move changed the inherited worktree branch.
+func (r *MoveResult) WorktreeChanged() bool {
+	return r.OldWorktree != r.NewWorktree
+}
+
+// MoveSubtree re-parents an item together with its whole subtree. The move is
+// validated up front so the hierarchy is never left half-moved:
+//   - the new parent must exist, be open, allow children, and not be inside the subtree
+//   - no dependency may link a subtree item and one of its new ancestors
+//   - in-progress work may not silently switch worktree branches unless force is set
+//
+// An empty newParentID moves the subtree to the top level.
+func (db *DB) MoveSubtree(itemID, newParentID string, force bool) (*MoveResult, error) {
+	target := &moveTarget{}
+	if newParentID != "" {
+		var err error
+		if target, err = db.moveTargetFor(newParentID); err != nil {
+			return nil, err
+		}
+	}
+	result, item, err := db.checkMove(itemID, target, force)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.applyMove(item, newParentID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// moveTarget is where a subtree is being moved.
+type moveTarget struct {
+	parentID  string   // the new parent ("" for the top level)
+	ancestors []string // the new parent and its ancestors
+	worktree  string   // the worktree branch items under the parent inherit
+}
+
+// moveTargetFor checks that parentID can take children and returns it as a
+// move target.
+func (db *DB) moveTargetFor(parentID string) (*moveTarget, error) {
+	parent, err := db.GetItem(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("parent not found: %s (use 'tpg list' to see available items)", parentID)
+	}
+	if !parent.Type.CanHaveChildren() {
+		return nil, fmt.Errorf("cannot set parent: %s (type %s) cannot have children", parentID, parent.Type)
+	}
+	if parent.Status == model.StatusDone || parent.Status == model.StatusCanceled {
+		return nil, fmt.Errorf("cannot add child to closed parent %s", parentID)
+	}
+
+	target := &moveTarget{parentID: parentID, ancestors: []string{parentID}}
+	chain, err := db.GetParentChain(parentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range chain {
+		target.ancestors = append(target.ancestors, a.ID)
+	}
+	if root, _, err := db.GetRootEpic(parentID); err == nil && root != nil {
+		target.worktree = root.WorktreeBranch
+	}
+	return target, nil
+}
+
+// checkMove validates moving itemID's subtree to target without changing
+// anything, and returns what the move would do along with the item.
+func (db *DB) checkMove(itemID string, target *moveTarget, force bool) (*MoveResult, *model.Item, error) {
+	item, err := db.GetItem(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	descendants, err := db.GetDescendants(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subtree := map[string]bool{itemID: true}
+	result := &MoveResult{Moved: []string{itemID}}
+	inProgress := item.Status == model.StatusInProgress
+	for _, d := range descendants {
+		subtree[d.ID] = true
+		result.Moved = append(result.Moved, d.ID)
+		if d.Status == model.StatusInProgress {
+			inProgress = true
+		}
+	}
+	if target.parentID != "" && subtree[target.parentID] {
+		return nil, nil, fmt.Errorf("cannot move %s under %s: %s is inside the subtree being moved", itemID, target.parentID, target.parentID)
+	}
+
+	// Worktree inherited today
+	if root, _, err := db.GetRootEpic(itemID); err == nil && root != nil {
+		result.OldWorktree = root.WorktreeBranch
+	}
+	if item.WorktreeBranch != "" {
+		// The item carries its own worktree, so the move doesn't change it.
+		result.NewWorktree = result.OldWorktree
+	} else {
+		result.NewWorktree = target.worktree
+	}
+
+	if conflicts, err := db.ancestorDepConflicts(result.Moved, target.ancestors); err != nil {
+		return nil, nil, err
+	} else if len(conflicts) > 0 {
+		return nil, nil, fmt.Errorf("cannot move %s: dependencies would link items to their own ancestors:\n  %s\nRemove them first with 'tpg dep <id> remove <other-id>'",
+			itemID, strings.Join(conflicts, "\n  "))
+	}
+
+	if result.WorktreeChanged() && inProgress && !force {
+		return nil, nil, fmt.Errorf("cannot move %s: in-progress items would switch worktree branch from %s to %s (use --force to move anyway)",
+			itemID, BranchOrNone(result.OldWorktree), BranchOrNone(result.NewWorktree))
+	}
+	return result, item, nil
+}
+
+// applyMove re-parents a checked item under parentID, or to the top level
+// when parentID is empty.
+func (db *DB) applyMove(item *model.Item, parentID string) error {
+	if parentID == "" {
+		if item.ParentID == nil {
+			return nil
+		}
+		return db.ClearParent(item.ID)
+	}
+	return db.SetParent(item.ID, parentID)
+}
+
+// ancestorDepConflicts returns "a depends on b" descriptions of dependencies between
+// any of ids and any of ancestors, in either direction.
+func (db *DB) ancestorDepConflicts(ids, ancestors []string) ([]string, error) {
+	if len(ids) == 0 || len(ancestors) == 0 {
+		return nil, nil
+	}
+	idPh := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	ancPh := strings.TrimSuffix(strings.Repeat("?,", len(ancestors)), ",")
+
+	var args []any
+	for _, id := range ancestors {
+		args = append(args, id)
+	}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	for _, id := range ancestors {
+		args = append(args, id)
+	}
+
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT item_id, depends_on FROM deps
+		WHERE (item_id IN (%s) AND depends_on IN (%s))
+		   OR (item_id IN (%s) AND depends_on IN (%s))
+		ORDER BY item_id, depends_on`, ancPh, idPh, idPh, ancPh), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []string
+	for rows.Next() {
+		var from, to string
+		if err := rows.Scan(&from, &to); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s depends on %s", from, to))
+	}
+	return conflicts, rows.Err()
+}
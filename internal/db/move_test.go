@@ -0,0 +1,137 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestMoveSubtree(t *testing.T) {
+	db := setupTestDB(t)
+
+	oldEpic := createTestEpic(t, db, "Old Epic", "test")
+	newEpic := createTestEpic(t, db, "New Epic", "test")
+	sub := createTestEpic(t, db, "Sub Epic", "test")
+	child := createTestItem(t, db, "Child")
+	if err := db.SetParent(sub.ID, oldEpic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+	if err := db.SetParent(child.ID, sub.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+
+	result, err := db.MoveSubtree(sub.ID, newEpic.ID, false)
+	if err != nil {
+		t.Fatalf("MoveSubtree: %v", err)
+	}
+	if len(result.Moved) != 2 {
+		t.Errorf("expected 2 moved items, got %v", result.Moved)
+	}
+
+	moved, _ := db.GetItem(sub.ID)
+	if moved.ParentID == nil || *moved.ParentID != newEpic.ID {
+		t.Errorf("expected parent %s, got %v", newEpic.ID, moved.ParentID)
+	}
+	c, _ := db.GetItem(child.ID)
+	if c.ParentID == nil || *c.ParentID != sub.ID {
+		t.Errorf("child should stay under %s, got %v", sub.ID, c.ParentID)
+	}
+
+	// Moving to the top level clears the parent
+	if _, err := db.MoveSubtree(sub.ID, "", false); err != nil {
+		t.Fatalf("MoveSubtree to top level: %v", err)
+	}
+	moved, _ = db.GetItem(sub.ID)
+	if moved.ParentID != nil {
+		t.Errorf("expected no parent, got %v", *moved.ParentID)
+	}
+}
+
+func TestMoveSubtree_IntoOwnSubtree(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	sub := createTestEpic(t, db, "Sub", "test")
+	if err := db.SetParent(sub.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+
+	_, err := db.MoveSubtree(epic.ID, sub.ID, false)
+	if err == nil || !strings.Contains(err.Error(), "inside the subtree") {
+		t.Fatalf("expected subtree error, got %v", err)
+	}
+}
+
+func TestMoveSubtree_ParentCannotHaveChildren(t *testing.T) {
+	db := setupTestDB(t)
+
+	task := createTestItem(t, db, "Task")
+	other := createTestItem(t, db, "Other")
+
+	_, err := db.MoveSubtree(other.ID, task.ID, false)
+	if err == nil || !strings.Contains(err.Error(), task.ID+" (type task) cannot have children") {
+		t.Fatalf("expected cannot-have-children error, got %v", err)
+	}
+}
+
+func TestMoveSubtree_DependencyOnNewAncestor(t *testing.T) {
+	db := setupTestDB(t)
+
+	target := createTestEpic(t, db, "Target", "test")
+	sub := createTestEpic(t, db, "Sub", "test")
+	child := createTestItem(t, db, "Child")
+	if err := db.SetParent(child.ID, sub.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+	if err := db.AddDep(child.ID, target.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+
+	_, err := db.MoveSubtree(sub.ID, target.ID, false)
+	if err == nil {
+		t.Fatal("expected dependency conflict")
+	}
+	if !strings.Contains(err.Error(), child.ID+" depends on "+target.ID) {
+		t.Errorf("error should name the conflicting dependency, got: %v", err)
+	}
+
+	// Nothing moved
+	s, _ := db.GetItem(sub.ID)
+	if s.ParentID != nil {
+		t.Errorf("subtree should not have moved, parent = %v", *s.ParentID)
+	}
+}
+
+func TestMoveSubtree_WorktreeChange(t *testing.T) {
+	db := setupTestDB(t)
+
+	oldEpic := createTestEpic(t, db, "Old", "test")
+	newEpic := createTestEpic(t, db, "New", "test")
+	if err := db.SetWorktreeMetadata(oldEpic.ID, "feature/old", "main"); err != nil {
+		t.Fatalf("SetWorktreeMetadata: %v", err)
+	}
+	if err := db.SetWorktreeMetadata(newEpic.ID, "feature/new", "main"); err != nil {
+		t.Fatalf("SetWorktreeMetadata: %v", err)
+	}
+	task := createTestItem(t, db, "Task")
+	if err := db.SetParent(task.ID, oldEpic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+	if err := db.UpdateStatus(task.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	_, err := db.MoveSubtree(task.ID, newEpic.ID, false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected worktree switch to require force, got %v", err)
+	}
+
+	result, err := db.MoveSubtree(task.ID, newEpic.ID, true)
+	if err != nil {
+		t.Fatalf("MoveSubtree with force: %v", err)
+	}
+	if !result.WorktreeChanged() || result.OldWorktree != "feature/old" || result.NewWorktree != "feature/new" {
+		t.Errorf("unexpected worktree change: %+v", result)
+	}
+}
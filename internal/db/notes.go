@@ -0,0 +1,117 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// CreateNote inserts a new note. An epic-scoped note takes the project of
+// its epic.
+func (db *DB) CreateNote(n *model.Note) error {
+	if strings.TrimSpace(n.Title) == "" {
+		return fmt.Errorf("note title cannot be empty")
+	}
+	if n.EpicID != nil {
+		epic, err := db.GetItem(*n.EpicID)
+		if err != nil {
+			return err
+		}
+		if !epic.Type.CanHaveChildren() {
+			return fmt.Errorf("%s is a %s; notes can only be attached to epics", epic.ID, epic.Type)
+		}
+		n.Project = epic.Project
+	}
+	_, err := db.Exec(`
+		INSERT INTO notes (id, project, epic_id, title, body, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		n.ID, n.Project, n.EpicID, n.Title, n.Body, sqlTime(n.CreatedAt), sqlTime(n.UpdatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+	return nil
+}
+
+// GetNote retrieves a note by ID.
+func (db *DB) GetNote(id string) (*model.Note, error) {
+	notes, err := db.queryNotes(`WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("note not found: %s (use 'tpg note list' to see notes)", id)
+	}
+	return &notes[0], nil
+}
+
+// ListNotes returns notes, newest first. With an epic ID only that epic's
+// notes are returned; otherwise all notes in the project (every project when
+// empty), both project-level and epic-level.
+func (db *DB) ListNotes(project, epicID string) ([]model.Note, error) {
+	var where []string
+	var args []any
+	if epicID != "" {
+		where = append(where, "epic_id = ?")
+		args = append(args, epicID)
+	} else if project != "" {
+		where = append(where, "project = ?")
+		args = append(args, project)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = "WHERE " + strings.Join(where, " AND ")
+	}
+	return db.queryNotes(clause+" ORDER BY updated_at DESC, created_at DESC", args...)
+}
+
+// UpdateNoteBody replaces a note's body.
+func (db *DB) UpdateNoteBody(id, body string) error {
+	result, err := db.Exec(`UPDATE notes SET body = ?, updated_at = ? WHERE id = ?`,
+		body, sqlTime(time.Now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("note not found: %s (use 'tpg note list' to see notes)", id)
+	}
+	return nil
+}
+
+// DeleteNote permanently deletes a note.
+func (db *DB) DeleteNote(id string) error {
+	result, err := db.Exec(`DELETE FROM notes WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("note not found: %s (use 'tpg note list' to see notes)", id)
+	}
+	return nil
+}
+
+func (db *DB) queryNotes(clause string, args ...any) ([]model.Note, error) {
+	rows, err := db.Query(`
+		SELECT id, project, epic_id, title, body, created_at, updated_at
+		FROM notes `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var notes []model.Note
+	for rows.Next() {
+		var n model.Note
+		var epicID sql.NullString
+		if err := rows.Scan(&n.ID, &n.Project, &epicID, &n.Title, &n.Body, &n.CreatedAt, &n.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+		if epicID.Valid {
+			n.EpicID = &epicID.String
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
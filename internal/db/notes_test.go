@@ -0,0 +1,95 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func newTestNote(title string, epicID *string) *model.Note {
+	now := time.Now()
+	return &model.Note{
+		ID:        model.GenerateNoteID(),
+		Project:   "test",
+		EpicID:    epicID,
+		Title:     title,
+		Body:      "body of " + title,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func TestNotes(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Epic", "test")
+	task := createTestItem(t, db, "Task")
+
+	projectNote := newTestNote("Runbook", nil)
+	if err := db.CreateNote(projectNote); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	epicNote := newTestNote("Design", &epic.ID)
+	epicNote.Project = ""
+	if err := db.CreateNote(epicNote); err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	if epicNote.Project != "test" {
+		t.Errorf("expected epic note to take the epic's project, got %q", epicNote.Project)
+	}
+	if err := db.CreateNote(newTestNote("On task", &task.ID)); err == nil {
+		t.Error("expected error attaching a note to a task")
+	}
+	if err := db.CreateNote(newTestNote("  ", nil)); err == nil {
+		t.Error("expected error for empty title")
+	}
+
+	got, err := db.GetNote(epicNote.ID)
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if got.Title != "Design" || got.EpicID == nil || *got.EpicID != epic.ID {
+		t.Errorf("unexpected note: %+v", got)
+	}
+
+	all, err := db.ListNotes("test", "")
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 notes in project, got %d", len(all))
+	}
+	onEpic, err := db.ListNotes("test", epic.ID)
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if len(onEpic) != 1 || onEpic[0].ID != epicNote.ID {
+		t.Errorf("expected only the epic note, got %v", onEpic)
+	}
+
+	if err := db.UpdateNoteBody(projectNote.ID, "updated"); err != nil {
+		t.Fatalf("UpdateNoteBody: %v", err)
+	}
+	if got, _ := db.GetNote(projectNote.ID); got.Body != "updated" {
+		t.Errorf("expected updated body, got %q", got.Body)
+	}
+
+	if err := db.DeleteNote(projectNote.ID); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if _, err := db.GetNote(projectNote.ID); err == nil {
+		t.Error("expected deleted note to be gone")
+	}
+	if err := db.DeleteNote(projectNote.ID); err == nil {
+		t.Error("expected error deleting a missing note")
+	}
+
+	// Deleting the epic removes its notes
+	if err := db.DeleteItem(epic.ID, true, false); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if _, err := db.GetNote(epicNote.ID); err == nil {
+		t.Error("expected epic note deleted with the epic")
+	}
+}
@@ -600,3 +600,106 @@ func TestCompleteItem_WorktreeEpicError(t *testing.T) {
 		t.Errorf("expected error to mention 'tpg epic merge', got: %v", err)
 	}
 }
+
+func TestSetType_CustomTypeWithChildren(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(model.ResetItemTypes)
+	if err := model.RegisterItemType(model.TypeInfo{Name: "story", Prefix: "st", CanHaveChildren: true}); err != nil {
+		t.Fatalf("register type: %v", err)
+	}
+	if err := model.RegisterItemType(model.TypeInfo{Name: "bug", Prefix: "bg"}); err != nil {
+		t.Fatalf("register type: %v", err)
+	}
+
+	parent := createTestItem(t, db, "Story")
+	child := createTestItem(t, db, "Child")
+
+	err := db.SetParent(child.ID, parent.ID)
+	if err == nil {
+		t.Fatal("expected error: tasks cannot have children")
+	}
+	if !strings.Contains(err.Error(), parent.ID+" (type task) cannot have children") {
+		t.Errorf("expected error to name the parent's type, got: %v", err)
+	}
+	if err := db.SetType(parent.ID, "story"); err != nil {
+		t.Fatalf("SetType(story) failed: %v", err)
+	}
+	if err := db.SetParent(child.ID, parent.ID); err != nil {
+		t.Fatalf("SetParent under story failed: %v", err)
+	}
+
+	// A parent with children cannot become a type without children
+	if err := db.SetType(parent.ID, "bug"); err == nil {
+		t.Error("expected error changing parent to a childless type")
+	}
+	if err := db.SetType(parent.ID, "nope"); err == nil {
+		t.Error("expected error for unregistered type")
+	}
+
+	entries, err := db.GetItemHistory(parent.ID, 50)
+	if err != nil {
+		t.Fatalf("GetItemHistory failed: %v", err)
+	}
+	found := false
+	for _, e := range entries {
+		if e.EventType == EventTypeTypeChanged {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected type_changed history entry")
+	}
+}
+
+func TestCustomParentType_BehavesLikeEpic(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(model.ResetItemTypes)
+	if err := model.RegisterItemType(model.TypeInfo{Name: "story", Prefix: "st", CanHaveChildren: true}); err != nil {
+		t.Fatalf("register type: %v", err)
+	}
+
+	story := createTestItem(t, db, "Story")
+	if err := db.SetType(story.ID, "story"); err != nil {
+		t.Fatalf("SetType(story) failed: %v", err)
+	}
+	child := createTestItem(t, db, "Child")
+	if err := db.SetParent(child.ID, story.ID); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	blocker := createTestItem(t, db, "Blocker")
+	if err := db.AddDep(story.ID, blocker.ID); err != nil {
+		t.Fatalf("AddDep failed: %v", err)
+	}
+
+	// The story is a container, and its child inherits the story's dependency
+	ready, err := db.ReadyItems("test")
+	if err != nil {
+		t.Fatalf("ReadyItems failed: %v", err)
+	}
+	for _, item := range ready {
+		if item.ID == story.ID || item.ID == child.ID {
+			t.Errorf("expected only the blocker to be ready, got %s", item.ID)
+		}
+	}
+	if err := db.CompleteItem(story.ID, "", AgentContext{}); err == nil || !strings.Contains(err.Error(), "cannot complete story") {
+		t.Errorf("expected story to refuse direct completion, got %v", err)
+	}
+
+	if err := db.CompleteItem(blocker.ID, "Done", AgentContext{}); err != nil {
+		t.Fatalf("complete blocker: %v", err)
+	}
+	result, err := db.CloseAndCascade(child.ID, model.StatusDone, "Done", AgentContext{}, false)
+	if err != nil {
+		t.Fatalf("CloseAndCascade failed: %v", err)
+	}
+	if len(result.CompletedEpics) != 1 || result.CompletedEpics[0] != story.ID {
+		t.Errorf("expected the story to auto-complete, got %v", result.CompletedEpics)
+	}
+	got, err := db.GetItem(story.ID)
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	if got.Status != model.StatusDone {
+		t.Errorf("story status = %s, want done", got.Status)
+	}
+}
@@ -1,11 +1,11 @@
 package db
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+
+	"github.com/taxilian/tpg/internal/worktree"
 )
 
 const (
@@ -100,55 +100,13 @@ func FindWorktreeRoot(startDir string) (string, error) {
 }
 
 // parseGitFile parses a .git file (used by worktrees) and extracts the main repo path.
-// The file format is: "gitdir: <path>" where path points to the main repo's .git directory.
+// The file format is: "gitdir: <path>" where path points to the main repo's .git directory
+// or to a worktree's directory inside it (.git/worktrees/<name>).
 func parseGitFile(gitFilePath string) (string, error) {
-	file, err := os.Open(gitFilePath)
+	_, repoRoot, _, err := worktree.ParseGitFile(gitFilePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open .git file: %w", err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	if !scanner.Scan() {
-		if err := scanner.Err(); err != nil {
-			return "", fmt.Errorf("failed to read .git file: %w", err)
-		}
-		return "", fmt.Errorf(".git file is empty")
-	}
-
-	line := strings.TrimSpace(scanner.Text())
-
-	// Parse "gitdir: <path>" format
-	const prefix = "gitdir:"
-	if !strings.HasPrefix(line, prefix) {
-		return "", fmt.Errorf("malformed .git file: missing gitdir prefix")
-	}
-
-	gitDir := strings.TrimSpace(line[len(prefix):])
-	if gitDir == "" {
-		return "", fmt.Errorf("malformed .git file: empty gitdir path")
-	}
-
-	// Extract the main repo path from the gitdir path
-	// gitdir points to something like /path/to/repo/.git or /path/to/repo/.git/worktrees/myworktree
-	// We need to find the main repo root (parent of .git)
-
-	// If it's a worktrees path, go up to find the main .git directory
-	gitDir = filepath.Clean(gitDir)
-
-	// Walk up through "worktrees" directories to find the main .git
-	for strings.Contains(gitDir, "worktrees") {
-		parent := filepath.Dir(gitDir)
-		if parent == gitDir {
-			break
-		}
-		gitDir = parent
+		return "", err
 	}
-
-	// Now gitDir should be the main .git directory
-	// The repo root is the parent of .git
-	repoRoot := filepath.Dir(gitDir)
-
 	return repoRoot, nil
 }
 
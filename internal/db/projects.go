@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 )
@@ -19,3 +20,35 @@ func (db *DB) EnsureProject(name string) error {
 	}
 	return nil
 }
+
+// ProjectDescription returns a project's description, or "" if it has none
+// or doesn't exist.
+func (db *DB) ProjectDescription(name string) (string, error) {
+	var description sql.NullString
+	err := db.QueryRow(`SELECT description FROM projects WHERE name = ?`, name).Scan(&description)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get project description: %w", err)
+	}
+	return description.String, nil
+}
+
+// SetProjectDescription sets a project's description, creating the project if
+// needed. An empty description clears it.
+func (db *DB) SetProjectDescription(name, description string) error {
+	if err := db.EnsureProject(name); err != nil {
+		return err
+	}
+	var value any
+	if description != "" {
+		value = description
+	}
+	_, err := db.Exec(`UPDATE projects SET description = ?, updated_at = ? WHERE name = ?`,
+		value, sqlTime(time.Now()), name)
+	if err != nil {
+		return fmt.Errorf("failed to set project description: %w", err)
+	}
+	return nil
+}
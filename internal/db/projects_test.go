@@ -42,3 +42,27 @@ func TestListProjectsEmpty(t *testing.T) {
 		t.Errorf("expected empty list, got %v", projects)
 	}
 }
+
+func TestProjectDescription(t *testing.T) {
+	db := setupTestDB(t)
+
+	got, err := db.ProjectDescription("missing")
+	if err != nil || got != "" {
+		t.Fatalf("ProjectDescription(missing) = %q, %v; want empty", got, err)
+	}
+
+	if err := db.SetProjectDescription("myproject", "A task tracker for agents"); err != nil {
+		t.Fatalf("SetProjectDescription: %v", err)
+	}
+	got, err = db.ProjectDescription("myproject")
+	if err != nil || got != "A task tracker for agents" {
+		t.Errorf("ProjectDescription = %q, %v; want the description", got, err)
+	}
+
+	if err := db.SetProjectDescription("myproject", ""); err != nil {
+		t.Fatalf("SetProjectDescription clear: %v", err)
+	}
+	if got, _ = db.ProjectDescription("myproject"); got != "" {
+		t.Errorf("ProjectDescription after clear = %q, want empty", got)
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/taxilian/tpg/internal/model"
@@ -12,15 +13,77 @@ const itemSelectColumns = "id, project, type, title, description, status, priori
 
 // ListFilter contains optional filters for listing items.
 type ListFilter struct {
-	Project     string        // Filter by project
-	Status      *model.Status // Filter by status
-	Parent      string        // Filter by parent epic ID
-	Type        string        // Filter by item type (task, epic)
-	Blocking    string        // Show items that block this ID
-	BlockedBy   string        // Show items blocked by this ID
-	HasBlockers bool          // Show only items with unresolved blockers
-	NoBlockers  bool          // Show only items with no blockers
-	Labels      []string      // Filter by label names (AND - items must have all)
+	Project      string            // Filter by project
+	Status       *model.Status     // Filter by status
+	Parent       string            // Filter by parent epic ID
+	Type         string            // Filter by item type (task, epic)
+	Blocking     string            // Show items that block this ID
+	BlockedBy    string            // Show items blocked by this ID
+	HasBlockers  bool              // Show only items with unresolved blockers
+	NoBlockers   bool              // Show only items with no blockers
+	Labels       []string          // Filter by label names (AND - items must have all)
+	Fields       map[string]string // Filter by custom field values (AND - items must match all)
+	CreatedSince time.Time         // Only items created at or after this time
+	UpdatedSince time.Time         // Only items updated at or after this time
+	DoneSince    time.Time         // Only items completed (status done) at or after this time
+	Sort         SortOrder         // Result ordering (default: priority, then oldest first)
+}
+
+// Sort fields accepted by SortOrder.
+const (
+	SortPriority = "priority" // highest priority first
+	SortUpdated  = "updated"  // most recently updated first
+	SortCreated  = "created"  // oldest first
+	SortStatus   = "status"   // in_progress, open, blocked, done, canceled
+	SortTitle    = "title"    // alphabetical
+)
+
+// SortFields lists the valid SortOrder fields.
+var SortFields = []string{SortPriority, SortUpdated, SortCreated, SortStatus, SortTitle}
+
+// SortOrder selects the ORDER BY clause for item queries. The zero value
+// sorts by priority. Reverse flips the natural direction of the field.
+type SortOrder struct {
+	Field   string
+	Reverse bool
+}
+
+// IsZero reports whether o is the default ordering.
+func (o SortOrder) IsZero() bool {
+	return o.Field == "" && !o.Reverse
+}
+
+// orderBy returns the ORDER BY clause for o. Ties fall back to priority and
+// creation time so output is stable; under the default ordering, manually
+// ranked items come first among equal priorities.
+func (o SortOrder) orderBy() (string, error) {
+	var keys []string
+	switch o.Field {
+	case "", SortPriority:
+		keys = []string{"priority ASC", "sort_rank IS NULL ASC", "sort_rank ASC", "created_at ASC"}
+	case SortUpdated:
+		keys = []string{"updated_at DESC", "priority ASC"}
+	case SortCreated:
+		keys = []string{"created_at ASC", "priority ASC"}
+	case SortStatus:
+		keys = []string{`CASE status
+			WHEN 'in_progress' THEN 0 WHEN 'open' THEN 1 WHEN 'blocked' THEN 2
+			WHEN 'pending_review' THEN 3 WHEN 'done' THEN 4 WHEN 'canceled' THEN 5 ELSE 6 END ASC`, "priority ASC", "created_at ASC"}
+	case SortTitle:
+		keys = []string{"title COLLATE NOCASE ASC", "priority ASC"}
+	default:
+		return "", fmt.Errorf("invalid sort field: %s (valid: %s)", o.Field, strings.Join(SortFields, ", "))
+	}
+	if o.Reverse {
+		for i, key := range keys {
+			if strings.HasSuffix(key, " ASC") {
+				keys[i] = strings.TrimSuffix(key, " ASC") + " DESC"
+			} else {
+				keys[i] = strings.TrimSuffix(key, " DESC") + " ASC"
+			}
+		}
+	}
+	return " ORDER BY " + strings.Join(keys, ", "), nil
 }
 
 // ListItems returns items filtered by project and/or status.
@@ -30,6 +93,21 @@ func (db *DB) ListItems(project string, status *model.Status) ([]model.Item, err
 
 // ListItemsFiltered returns items matching the given filters.
 func (db *DB) ListItemsFiltered(filter ListFilter) ([]model.Item, error) {
+	var items []model.Item
+	err := db.EachItemFiltered(filter, func(item model.Item) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// EachItemFiltered calls fn for each item matching the given filters, in
+// order, without holding them all in memory. It stops at the first error fn
+// returns.
+func (db *DB) EachItemFiltered(filter ListFilter, fn func(model.Item) error) error {
 	query := fmt.Sprintf("SELECT %s FROM items WHERE 1=1", itemSelectColumns)
 	args := []any{}
 
@@ -39,7 +117,7 @@ func (db *DB) ListItemsFiltered(filter ListFilter) ([]model.Item, error) {
 	}
 	if filter.Status != nil {
 		if !filter.Status.IsValid() {
-			return nil, fmt.Errorf("invalid status: %s", *filter.Status)
+			return fmt.Errorf("invalid status: %s", *filter.Status)
 		}
 		query += ` AND status = ?`
 		args = append(args, *filter.Status)
@@ -51,7 +129,7 @@ func (db *DB) ListItemsFiltered(filter ListFilter) ([]model.Item, error) {
 	if filter.Type != "" {
 		itemType := model.ItemType(filter.Type)
 		if !itemType.IsValid() {
-			return nil, fmt.Errorf("invalid type: %s (type cannot be empty)", filter.Type)
+			return fmt.Errorf("invalid type: %s (type cannot be empty)", filter.Type)
 		}
 		query += ` AND type = ?`
 		args = append(args, filter.Type)
@@ -96,9 +174,29 @@ func (db *DB) ListItemsFiltered(filter ListFilter) ([]model.Item, error) {
 		}
 		args = append(args, len(filter.Labels))
 	}
-	query += ` ORDER BY priority ASC, created_at ASC`
+	for key, value := range filter.Fields {
+		query += ` AND id IN (SELECT item_id FROM item_fields WHERE key = ? AND value = ?)`
+		args = append(args, key, value)
+	}
+	if !filter.CreatedSince.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, sqlTime(filter.CreatedSince))
+	}
+	if !filter.UpdatedSince.IsZero() {
+		query += ` AND updated_at >= ?`
+		args = append(args, sqlTime(filter.UpdatedSince))
+	}
+	if !filter.DoneSince.IsZero() {
+		query += ` AND status = 'done' AND closed_at >= ?`
+		args = append(args, sqlTime(filter.DoneSince))
+	}
+	orderBy, err := filter.Sort.orderBy()
+	if err != nil {
+		return err
+	}
+	query += orderBy
 
-	return db.queryItems(query, args...)
+	return db.eachItem(query, args, fn)
 }
 
 // ReadyItems returns items that are open and have no unmet dependencies.
@@ -125,18 +223,29 @@ type ReadyResult struct {
 // 1. It has status 'open'
 // 2. It has no unmet direct dependencies
 // 3. None of its ancestor epics have unmet dependencies (inherited deps)
+// 4. Every task in the label groups it (or an ancestor epic) waits on is done
 func (db *DB) ReadyItemsFiltered(project string, labels []string) ([]model.Item, error) {
+	return db.ReadyItemsSorted(project, labels, SortOrder{})
+}
+
+// ReadyItemsSorted is ReadyItemsFiltered with an explicit ordering. Label
+// weights only reorder results under the default ordering.
+func (db *DB) ReadyItemsSorted(project string, labels []string, order SortOrder) ([]model.Item, error) {
+	orderBy, err := order.orderBy()
+	if err != nil {
+		return nil, err
+	}
+	parentTypes, args := parentTypesSQL()
 	query := fmt.Sprintf(`
 		SELECT %s
 		FROM items
 		WHERE status = 'open'
-		  AND type != 'epic'
+		  AND type NOT IN (%s)
 		  AND id NOT IN (
 		    SELECT d.item_id FROM deps d
 		    JOIN items i ON d.depends_on = i.id
 		    WHERE i.status != 'done'
-		  )`, itemSelectColumns)
-	args := []any{}
+		  )`, itemSelectColumns, parentTypes)
 
 	if project != "" {
 		query += ` AND project = ?`
@@ -163,23 +272,45 @@ func (db *DB) ReadyItemsFiltered(project string, labels []string) ([]model.Item,
 		}
 		args = append(args, len(labels))
 	}
-	query += ` ORDER BY priority ASC, created_at ASC`
+	query += orderBy
 
 	candidates, err := db.queryItems(query, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter out items with unmet ancestor dependencies
+	// Filter out items with unmet ancestor or group dependencies
+	groups, err := db.hasGroupDeps()
+	if err != nil {
+		return nil, err
+	}
 	var ready []model.Item
 	for _, item := range candidates {
 		ancestorDeps, err := db.GetAncestorDependencies(item.ID)
 		if err != nil {
 			return nil, err
 		}
-		if len(ancestorDeps) == 0 {
-			ready = append(ready, item)
+		if len(ancestorDeps) > 0 {
+			continue
 		}
+		if groups {
+			unmet, err := db.hasUnmetGroupDeps(item.ID)
+			if err != nil {
+				return nil, err
+			}
+			if unmet {
+				continue
+			}
+		}
+		ready = append(ready, item)
+	}
+
+	// Weighted labels (service classes) override plain priority ordering
+	if order.IsZero() && len(db.labelWeights) > 0 && len(ready) > 0 {
+		if err := db.PopulateItemLabels(ready); err != nil {
+			return nil, err
+		}
+		db.labelWeights.Sort(ready)
 	}
 
 	return ready, nil
@@ -188,9 +319,9 @@ func (db *DB) ReadyItemsFiltered(project string, labels []string) ([]model.Item,
 // ReadyItemsWithCounts returns ready items along with epic-level task counts.
 // For each ready item that belongs to an epic, the epic's total and ready task counts
 // are computed. This enables UI display like "Epic Title (3/20 tasks ready)".
-func (db *DB) ReadyItemsWithCounts(project string, labels []string) (*ReadyResult, error) {
+func (db *DB) ReadyItemsWithCounts(project string, labels []string, order SortOrder) (*ReadyResult, error) {
 	// Get all ready items
-	readyItems, err := db.ReadyItemsFiltered(project, labels)
+	readyItems, err := db.ReadyItemsSorted(project, labels, order)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +341,7 @@ func (db *DB) ReadyItemsWithCounts(project string, labels []string) (*ReadyResul
 	epicIDs := make(map[string]bool)
 	for _, item := range readyItems {
 		// Only count parent epics for tasks, not for nested epics
-		if item.Type == model.ItemTypeEpic {
+		if item.Type.CanHaveChildren() {
 			continue
 		}
 		epicID := db.findImmediateParentEpic(item)
@@ -236,7 +367,7 @@ func (db *DB) ReadyItemsWithCounts(project string, labels []string) (*ReadyResul
 		// Count ready TASKS under this epic
 		readyCount := 0
 		for _, item := range readyItems {
-			if item.Type == model.ItemTypeEpic {
+			if item.Type.CanHaveChildren() {
 				continue // Skip epics themselves
 			}
 			if db.findImmediateParentEpic(item) == epicID {
@@ -254,6 +385,21 @@ func (db *DB) ReadyItemsWithCounts(project string, labels []string) (*ReadyResul
 	return result, nil
 }
 
+// parentTypesSQL returns a placeholder list and its arguments for the item
+// types that can have children, for "type NOT IN (...)" filters that leave
+// out containers such as epics.
+func parentTypesSQL() (string, []any) {
+	var placeholders []string
+	var args []any
+	for _, info := range model.ItemTypes() {
+		if info.CanHaveChildren {
+			placeholders = append(placeholders, "?")
+			args = append(args, string(info.Name))
+		}
+	}
+	return strings.Join(placeholders, ", "), args
+}
+
 // findImmediateParentEpic returns the immediate parent epic ID for an item.
 // It walks up the parent chain until it finds an epic, or returns "" if none.
 func (db *DB) findImmediateParentEpic(item model.Item) string {
@@ -267,8 +413,8 @@ func (db *DB) findImmediateParentEpic(item model.Item) string {
 		return ""
 	}
 
-	// If parent is an epic, return it
-	if parent.Type == model.ItemTypeEpic {
+	// If parent is an epic (or another type that has children), return it
+	if parent.Type.CanHaveChildren() {
 		return parent.ID
 	}
 
@@ -286,7 +432,7 @@ func (db *DB) countActiveDescendants(epicID string) (int, error) {
 	count := 0
 	for _, d := range descendants {
 		// Only count non-epic items (tasks) that are not done/canceled
-		if d.Type != model.ItemTypeEpic &&
+		if !d.Type.CanHaveChildren() &&
 			d.Status != model.StatusDone &&
 			d.Status != model.StatusCanceled {
 			count++
@@ -316,6 +462,22 @@ func (db *DB) StaleItems(project string, cutoff time.Time) ([]model.Item, error)
 	return db.queryItems(query, args...)
 }
 
+// StaleItemsNow returns in-progress items that are stale as of now under the
+// configured per-priority and per-type thresholds (see model.StaleThresholdFor).
+func (db *DB) StaleItemsNow(project string, now time.Time) ([]model.Item, error) {
+	candidates, err := db.StaleItems(project, now.Add(-model.MinStaleThreshold()))
+	if err != nil {
+		return nil, err
+	}
+	var stale []model.Item
+	for _, item := range candidates {
+		if now.Sub(item.UpdatedAt) > model.StaleThresholdFor(item) {
+			stale = append(stale, item)
+		}
+	}
+	return stale, nil
+}
+
 // InProgressItemsByAgent returns in-progress items assigned to a specific agent.
 func (db *DB) InProgressItemsByAgent(agentID string) ([]model.Item, error) {
 	query := fmt.Sprintf("SELECT %s FROM items WHERE status = 'in_progress' AND agent_id = ? ORDER BY updated_at DESC", itemSelectColumns)
@@ -330,15 +492,18 @@ type StatusReport struct {
 	Blocked           int
 	Done              int
 	Canceled          int
+	PendingReview     int
 	Ready             int
 	RecentDone        []model.Item // last 3 completed
 	InProgItems       []model.Item // current in-progress (all)
 	BlockedItems      []model.Item // blocked with reasons
 	ReadyItems        []model.Item // ready for work
-	StaleItems        []model.Item // in-progress with no updates > 5 min
+	StaleItems        []model.Item // in-progress past their stale threshold
+	NewlyUnblocked    []model.Item // ready items whose last blocker completed recently
 	AgentID           string
-	MyInProgItems     []model.Item // this agent's in-progress tasks
-	OtherInProgCount  int          // count of other agents' tasks
+	MyInProgItems     []model.Item      // this agent's in-progress tasks
+	OtherInProgCount  int               // count of other agents' tasks
+	AgentNames        map[string]string // registered agent ID -> display name
 	WorktreeEpicStats *WorktreeEpicStats
 }
 
@@ -349,7 +514,7 @@ func (db *DB) ProjectStatus(project string) (*StatusReport, error) {
 
 // ProjectStatusFiltered returns an aggregated status report with optional label filtering and agent awareness.
 func (db *DB) ProjectStatusFiltered(project string, labels []string, agentID string) (*StatusReport, error) {
-	report := &StatusReport{Project: project, AgentID: agentID}
+	report := &StatusReport{Project: project, AgentID: agentID, AgentNames: db.AgentNames()}
 
 	// Build label subquery for reuse
 	labelSubquery := ""
@@ -411,6 +576,8 @@ func (db *DB) ProjectStatusFiltered(project string, labels []string, agentID str
 			report.Done = count
 		case model.StatusCanceled:
 			report.Canceled = count
+		case model.StatusPendingReview:
+			report.PendingReview = count
 		}
 	}
 
@@ -472,13 +639,27 @@ func (db *DB) ProjectStatusFiltered(project string, labels []string, agentID str
 		return nil, err
 	}
 
-	// Get stale in-progress items (no updates in > 5 minutes)
-	staleCutoff := time.Now().Add(-5 * time.Minute)
-	report.StaleItems, err = db.StaleItems(project, staleCutoff)
+	// Get stale in-progress items (past their configured threshold)
+	report.StaleItems, err = db.StaleItemsNow(project, time.Now())
 	if err != nil {
 		return nil, err
 	}
 
+	// Get recently unblocked items that are ready (respects the label filter)
+	unblocked, err := db.NewlyUnblocked(project, time.Now().Add(-NewlyUnblockedWindow))
+	if err != nil {
+		return nil, err
+	}
+	readyIDs := make(map[string]bool, len(readyItems))
+	for _, item := range readyItems {
+		readyIDs[item.ID] = true
+	}
+	for _, item := range unblocked {
+		if readyIDs[item.ID] {
+			report.NewlyUnblocked = append(report.NewlyUnblocked, item)
+		}
+	}
+
 	// Get worktree epic statistics
 	report.WorktreeEpicStats, err = db.GetWorktreeEpicStats(project)
 	if err != nil {
@@ -562,9 +743,8 @@ func (db *DB) GetSummaryStats(project string) (*SummaryStats, error) {
 	}
 	stats.EpicsInProgress = epicCount
 
-	// Get stale count (in-progress with no updates > 5 minutes)
-	staleCutoff := time.Now().Add(-5 * time.Minute)
-	staleItems, err := db.StaleItems(project, staleCutoff)
+	// Get stale count (in-progress past their configured threshold)
+	staleItems, err := db.StaleItemsNow(project, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -615,7 +795,7 @@ func (db *DB) GetDistinctTypes() ([]model.ItemType, error) {
 }
 
 // ReadyItemsForEpic returns ready items (open with no unmet dependencies) for a specific epic.
-func (db *DB) ReadyItemsForEpic(epicID string) ([]model.Item, error) {
+func (db *DB) ReadyItemsForEpic(epicID string, order SortOrder) ([]model.Item, error) {
 	// Get all descendants of the epic
 	descendants, err := db.GetDescendants(epicID)
 	if err != nil {
@@ -629,7 +809,7 @@ func (db *DB) ReadyItemsForEpic(epicID string) ([]model.Item, error) {
 	}
 
 	// Get all ready items
-	readyItems, err := db.ReadyItems("")
+	readyItems, err := db.ReadyItemsSorted("", nil, order)
 	if err != nil {
 		return nil, err
 	}
@@ -740,6 +920,19 @@ func (db *DB) FindEpicsNeedingWorktreeSetup(existingBranches map[string]string)
 	return needingSetup, nil
 }
 
+// WorktreeEpics returns every epic with worktree metadata, whatever its
+// status, oldest first. An empty project returns epics from all projects.
+func (db *DB) WorktreeEpics(project string) ([]model.Item, error) {
+	query := fmt.Sprintf("SELECT %s FROM items WHERE type = 'epic' AND worktree_branch IS NOT NULL AND worktree_branch != ''", itemSelectColumns)
+	args := []any{}
+	if project != "" {
+		query += " AND project = ?"
+		args = append(args, project)
+	}
+	query += " ORDER BY created_at ASC, id ASC"
+	return db.queryItems(query, args...)
+}
+
 // WorktreeEpicStats returns statistics about worktree epics.
 type WorktreeEpicStats struct {
 	ReadyToMerge      int // Epics with all children done
@@ -794,13 +987,25 @@ func (db *DB) GetWorktreeEpicStats(project string) (*WorktreeEpicStats, error) {
 
 // queryItems is a helper to scan item rows.
 func (db *DB) queryItems(query string, args ...any) ([]model.Item, error) {
+	var items []model.Item
+	err := db.eachItem(query, args, func(item model.Item) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// eachItem scans item rows one at a time, passing each to fn.
+func (db *DB) eachItem(query string, args []any, fn func(model.Item) error) error {
 	rows, err := db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query items: %w", err)
+		return fmt.Errorf("failed to query items: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var items []model.Item
 	for rows.Next() {
 		var item model.Item
 		var parentID sql.NullString
@@ -827,7 +1032,7 @@ func (db *DB) queryItems(query string, args ...any) ([]model.Item, error) {
 			&sharedContext, &closingInstructions,
 			&closedAt, &item.CreatedAt, &item.UpdatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan item: %w", err)
+			return fmt.Errorf("failed to scan item: %w", err)
 		}
 		if parentID.Valid {
 			item.ParentID = &parentID.String
@@ -848,7 +1053,7 @@ func (db *DB) queryItems(query string, args ...any) ([]model.Item, error) {
 		if variables.Valid {
 			vars, err := unmarshalTemplateVars(variables.String)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			item.TemplateVars = vars
 		}
@@ -879,7 +1084,9 @@ func (db *DB) queryItems(query string, args ...any) ([]model.Item, error) {
 		if closedAt.Valid {
 			item.ClosedAt = &closedAt.Time
 		}
-		items = append(items, item)
+		if err := fn(item); err != nil {
+			return err
+		}
 	}
-	return items, rows.Err()
+	return rows.Err()
 }
@@ -227,6 +227,34 @@ func TestReadyItems_ProjectFilter(t *testing.T) {
 	}
 }
 
+func TestReadyItems_LabelWeights(t *testing.T) {
+	db := setupTestDB(t)
+	db.SetLabelWeights(map[string]int{"hotfix": 100, "backlog": -100})
+
+	backlog := createTestItemWithProject(t, db, "Someday", "test", model.StatusOpen, 1)
+	normal := createTestItemWithProject(t, db, "Normal", "test", model.StatusOpen, 2)
+	hotfix := createTestItemWithProject(t, db, "Hotfix", "test", model.StatusOpen, 5)
+	if err := db.AddLabelToItem(backlog.ID, "test", "backlog"); err != nil {
+		t.Fatalf("failed to label: %v", err)
+	}
+	if err := db.AddLabelToItem(hotfix.ID, "test", "hotfix"); err != nil {
+		t.Fatalf("failed to label: %v", err)
+	}
+
+	ready, err := db.ReadyItems("test")
+	if err != nil {
+		t.Fatalf("failed to get ready: %v", err)
+	}
+	var got []string
+	for _, item := range ready {
+		got = append(got, item.ID)
+	}
+	want := []string{hotfix.ID, normal.ID, backlog.ID}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ready order = %v, want %v", got, want)
+	}
+}
+
 func TestProjectStatus(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -726,6 +754,44 @@ func TestStaleItems_ReturnsEmptyWhenNoStaleItems(t *testing.T) {
 	}
 }
 
+func TestStaleItemsNow_UsesConfiguredThresholds(t *testing.T) {
+	db := setupTestDB(t)
+	model.SetStaleThresholds(model.StaleThresholds{
+		Priority: map[int]time.Duration{1: 30 * time.Minute},
+		Type:     map[model.ItemType]time.Duration{model.ItemTypeEpic: 24 * time.Hour},
+	})
+	t.Cleanup(func() { model.SetStaleThresholds(model.StaleThresholds{}) })
+
+	now := time.Now()
+	create := func(title string, itemType model.ItemType, priority int, age time.Duration) *model.Item {
+		item := &model.Item{
+			ID:        model.GenerateID(itemType),
+			Project:   "test",
+			Type:      itemType,
+			Title:     title,
+			Status:    model.StatusInProgress,
+			Priority:  priority,
+			CreatedAt: now.Add(-age),
+			UpdatedAt: now.Add(-age),
+		}
+		if err := db.CreateItem(item); err != nil {
+			t.Fatalf("failed to create item: %v", err)
+		}
+		return item
+	}
+	stale := create("P2 task, default 5m", model.ItemTypeTask, 2, 10*time.Minute)
+	create("P1 task, 30m", model.ItemTypeTask, 1, 10*time.Minute)
+	create("P1 epic, type wins with 24h", model.ItemTypeEpic, 1, 2*time.Hour)
+
+	items, err := db.StaleItemsNow("", now)
+	if err != nil {
+		t.Fatalf("StaleItemsNow failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != stale.ID {
+		t.Errorf("got %d stale items, want only %s", len(items), stale.ID)
+	}
+}
+
 func TestCompleteItem_SetsStatusToDone(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -917,7 +983,7 @@ func TestReadyItemsForEpic(t *testing.T) {
 	}
 
 	// Get ready items for epic
-	ready, err := db.ReadyItemsForEpic(epic.ID)
+	ready, err := db.ReadyItemsForEpic(epic.ID, SortOrder{})
 	if err != nil {
 		t.Fatalf("failed to get ready items: %v", err)
 	}
@@ -963,7 +1029,7 @@ func TestReadyItemsWithCounts_Basic(t *testing.T) {
 	}
 
 	// Get ready items with counts
-	result, err := db.ReadyItemsWithCounts("test", nil)
+	result, err := db.ReadyItemsWithCounts("test", nil, SortOrder{})
 	if err != nil {
 		t.Fatalf("failed to get ready items with counts: %v", err)
 	}
@@ -1035,7 +1101,7 @@ func TestReadyItemsWithCounts_MultipleEpics(t *testing.T) {
 	}
 
 	// Get ready items with counts
-	result, err := db.ReadyItemsWithCounts("test", nil)
+	result, err := db.ReadyItemsWithCounts("test", nil, SortOrder{})
 	if err != nil {
 		t.Fatalf("failed to get ready items with counts: %v", err)
 	}
@@ -1091,7 +1157,7 @@ func TestReadyItemsWithCounts_TopLevelTasks(t *testing.T) {
 	task2 := createTestItemWithProject(t, db, "Task 2", "test", model.StatusOpen, 2)
 
 	// Get ready items with counts
-	result, err := db.ReadyItemsWithCounts("test", nil)
+	result, err := db.ReadyItemsWithCounts("test", nil, SortOrder{})
 	if err != nil {
 		t.Fatalf("failed to get ready items with counts: %v", err)
 	}
@@ -1127,7 +1193,7 @@ func TestReadyItemsWithCounts_NestedEpics(t *testing.T) {
 	}
 
 	// Get ready items with counts
-	result, err := db.ReadyItemsWithCounts("test", nil)
+	result, err := db.ReadyItemsWithCounts("test", nil, SortOrder{})
 	if err != nil {
 		t.Fatalf("failed to get ready items with counts: %v", err)
 	}
@@ -1165,7 +1231,7 @@ func TestReadyItemsWithCounts_Empty(t *testing.T) {
 	db := setupTestDB(t)
 
 	// No items
-	result, err := db.ReadyItemsWithCounts("test", nil)
+	result, err := db.ReadyItemsWithCounts("test", nil, SortOrder{})
 	if err != nil {
 		t.Fatalf("failed to get ready items with counts: %v", err)
 	}
@@ -1555,3 +1621,99 @@ func TestCloseAndCascade_BlockedByOpenChildren(t *testing.T) {
 		t.Errorf("error = %q, want it to mention 'AutoCompleteEpic'", err.Error())
 	}
 }
+
+func TestListItemsFiltered_Sort(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	oldest := createTestItemWithTimestamp(t, db, "charlie", "test", model.StatusOpen, now.Add(-3*time.Hour))
+	middle := createTestItemWithTimestamp(t, db, "Alpha", "test", model.StatusInProgress, now.Add(-2*time.Hour))
+	newest := createTestItemWithTimestamp(t, db, "bravo", "test", model.StatusBlocked, now.Add(-1*time.Hour))
+	// Touch the oldest item so it is the most recently updated
+	if _, err := db.Exec(`UPDATE items SET updated_at = ? WHERE id = ?`, sqlTime(now), oldest.ID); err != nil {
+		t.Fatalf("failed to touch item: %v", err)
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortOrder{}, []string{oldest.ID, middle.ID, newest.ID}},
+		{SortOrder{Field: SortCreated}, []string{oldest.ID, middle.ID, newest.ID}},
+		{SortOrder{Field: SortCreated, Reverse: true}, []string{newest.ID, middle.ID, oldest.ID}},
+		{SortOrder{Field: SortUpdated}, []string{oldest.ID, newest.ID, middle.ID}},
+		{SortOrder{Field: SortStatus}, []string{middle.ID, oldest.ID, newest.ID}},
+		{SortOrder{Field: SortTitle}, []string{middle.ID, newest.ID, oldest.ID}},
+		{SortOrder{Field: SortTitle, Reverse: true}, []string{oldest.ID, newest.ID, middle.ID}},
+	}
+	for _, tt := range tests {
+		items, err := db.ListItemsFiltered(ListFilter{Project: "test", Sort: tt.order})
+		if err != nil {
+			t.Fatalf("ListItemsFiltered(%+v) failed: %v", tt.order, err)
+		}
+		var got []string
+		for _, item := range items {
+			got = append(got, item.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("sort %+v = %v, want %v", tt.order, got, tt.want)
+		}
+	}
+
+	if _, err := db.ListItemsFiltered(ListFilter{Sort: SortOrder{Field: "bogus"}}); err == nil {
+		t.Error("expected error for invalid sort field")
+	}
+}
+
+func TestReadyItemsSorted(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	older := createTestItemWithTimestamp(t, db, "Older", "test", model.StatusOpen, now.Add(-2*time.Hour))
+	newer := createTestItemWithTimestamp(t, db, "Newer", "test", model.StatusOpen, now.Add(-1*time.Hour))
+
+	ready, err := db.ReadyItemsSorted("test", nil, SortOrder{Field: SortCreated, Reverse: true})
+	if err != nil {
+		t.Fatalf("ReadyItemsSorted failed: %v", err)
+	}
+	if len(ready) != 2 || ready[0].ID != newer.ID || ready[1].ID != older.ID {
+		t.Errorf("ready = %v, want newest first", ready)
+	}
+}
+
+func TestListItemsFiltered_Since(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	old := createTestItemWithTimestamp(t, db, "Old", "test", model.StatusOpen, now.Add(-10*24*time.Hour))
+	recent := createTestItemWithTimestamp(t, db, "Recent", "test", model.StatusOpen, now.Add(-1*time.Hour))
+	finished := createTestItemWithTimestamp(t, db, "Finished", "test", model.StatusOpen, now.Add(-10*24*time.Hour))
+	if err := db.UpdateStatus(finished.ID, model.StatusDone, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	ids := func(filter ListFilter) []string {
+		t.Helper()
+		items, err := db.ListItemsFiltered(filter)
+		if err != nil {
+			t.Fatalf("ListItemsFiltered failed: %v", err)
+		}
+		var got []string
+		for _, item := range items {
+			got = append(got, item.ID)
+		}
+		return got
+	}
+	week := now.Add(-7 * 24 * time.Hour)
+
+	if got := ids(ListFilter{Project: "test", CreatedSince: week}); len(got) != 1 || got[0] != recent.ID {
+		t.Errorf("CreatedSince = %v, want [%s]", got, recent.ID)
+	}
+	got := ids(ListFilter{Project: "test", UpdatedSince: week})
+	if len(got) != 2 || strings.Contains(strings.Join(got, ","), old.ID) {
+		t.Errorf("UpdatedSince = %v, want recent and finished items", got)
+	}
+	if got := ids(ListFilter{Project: "test", DoneSince: week}); len(got) != 1 || got[0] != finished.ID {
+		t.Errorf("DoneSince = %v, want [%s]", got, finished.ID)
+	}
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// siblingOrder is the ORDER BY clause for the children of one parent:
+// manually ranked items first, in rank order, then the rest by priority.
+const siblingOrder = "sort_rank IS NULL, sort_rank ASC, priority ASC, created_at ASC"
+
+// RankBefore moves itemID directly before beforeID in the manual order of
+// their siblings. Both items must share a parent (or both be top-level in the
+// same project). Every sibling is renumbered, so the current presentation
+// order is kept for the rest.
+func (db *DB) RankBefore(itemID, beforeID string) error {
+	if itemID == beforeID {
+		return fmt.Errorf("cannot rank %s before itself", itemID)
+	}
+	item, err := db.GetItem(itemID)
+	if err != nil {
+		return err
+	}
+	before, err := db.GetItem(beforeID)
+	if err != nil {
+		return err
+	}
+	if parentOf(*item) != parentOf(*before) || (item.ParentID == nil && item.Project != before.Project) {
+		return fmt.Errorf("%s and %s are not siblings (parents: %s, %s)",
+			itemID, beforeID, parentOrNone(item), parentOrNone(before))
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, arg := `SELECT id FROM items WHERE parent_id = ? ORDER BY `+siblingOrder, parentOf(*item)
+	if item.ParentID == nil {
+		query, arg = `SELECT id FROM items WHERE parent_id IS NULL AND project = ? ORDER BY `+siblingOrder, item.Project
+	}
+	rows, err := tx.Query(query, arg)
+	if err != nil {
+		return fmt.Errorf("failed to list siblings: %w", err)
+	}
+	var order []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan sibling: %w", err)
+		}
+		if id == itemID {
+			continue
+		}
+		if id == beforeID {
+			order = append(order, itemID)
+		}
+		order = append(order, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list siblings: %w", err)
+	}
+
+	for i, id := range order {
+		if _, err := tx.Exec(`UPDATE items SET sort_rank = ? WHERE id = ?`, i+1, id); err != nil {
+			return fmt.Errorf("failed to set rank: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func parentOrNone(item *model.Item) string {
+	if item.ParentID == nil {
+		return "none"
+	}
+	return *item.ParentID
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestRankBefore(t *testing.T) {
+	db := setupTestDB(t)
+	epic := &model.Item{ID: "ep-rank", Project: "test", Type: model.ItemTypeEpic, Title: "Epic",
+		Status: model.StatusOpen, Priority: 2, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := db.CreateItem(epic); err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	base := time.Now().Add(-time.Hour)
+	var ids []string
+	for i, title := range []string{"a", "b", "c"} {
+		item := &model.Item{
+			ID: "ts-rank" + title, Project: "test", Type: model.ItemTypeTask, Title: title,
+			Status: model.StatusOpen, Priority: 2, ParentID: &epic.ID,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute), UpdatedAt: base,
+		}
+		if err := db.CreateItem(item); err != nil {
+			t.Fatalf("CreateItem: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	childOrder := func() []string {
+		t.Helper()
+		children, err := db.GetChildren(epic.ID)
+		if err != nil {
+			t.Fatalf("GetChildren: %v", err)
+		}
+		var got []string
+		for _, c := range children {
+			got = append(got, c.ID)
+		}
+		return got
+	}
+	assertOrder := func(got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("order = %v, want %v", got, want)
+			}
+		}
+	}
+
+	if err := db.RankBefore(ids[2], ids[0]); err != nil {
+		t.Fatalf("RankBefore: %v", err)
+	}
+	assertOrder(childOrder(), ids[2], ids[0], ids[1])
+
+	if err := db.RankBefore(ids[1], ids[0]); err != nil {
+		t.Fatalf("RankBefore: %v", err)
+	}
+	assertOrder(childOrder(), ids[2], ids[1], ids[0])
+
+	// Rank breaks ties in ready order
+	ready, err := db.ReadyItems("test")
+	if err != nil {
+		t.Fatalf("ReadyItems: %v", err)
+	}
+	var readyIDs []string
+	for _, item := range ready {
+		if item.ParentID != nil {
+			readyIDs = append(readyIDs, item.ID)
+		}
+	}
+	assertOrder(readyIDs, ids[2], ids[1], ids[0])
+
+	// Only siblings can be ranked against each other
+	if err := db.RankBefore(ids[0], epic.ID); err == nil {
+		t.Error("expected an error ranking against a non-sibling")
+	}
+	if err := db.RankBefore(ids[0], ids[0]); err == nil {
+		t.Error("expected an error ranking an item before itself")
+	}
+}
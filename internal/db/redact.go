@@ -0,0 +1,235 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// RedactedText replaces every match scrubbed by RedactItem.
+const RedactedText = "[REDACTED]"
+
+// RedactResult counts the matches RedactItem replaced (or would replace).
+type RedactResult struct {
+	Description int
+	Results     int
+	Logs        int // matches across all log entries
+	History     int // matches in earlier history events (e.g. old descriptions)
+	Versions    int // matches in previous description versions
+	Transcripts int // matches across all attached transcripts
+}
+
+// Total is the number of matches across all fields.
+func (r *RedactResult) Total() int {
+	return r.Description + r.Results + r.Logs + r.History + r.Versions + r.Transcripts
+}
+
+// redactString replaces every match of patterns in s with RedactedText.
+func redactString(s string, patterns []*regexp.Regexp) (string, int) {
+	n := 0
+	for _, re := range patterns {
+		s = re.ReplaceAllStringFunc(s, func(string) string {
+			n++
+			return RedactedText
+		})
+	}
+	return s, n
+}
+
+// redactValue redacts every string inside a decoded JSON value.
+func redactValue(v any, patterns []*regexp.Regexp) (any, int) {
+	switch v := v.(type) {
+	case string:
+		return redactString(v, patterns)
+	case map[string]any:
+		total := 0
+		for k, inner := range v {
+			var n int
+			v[k], n = redactValue(inner, patterns)
+			total += n
+		}
+		return v, total
+	case []any:
+		total := 0
+		for i, inner := range v {
+			var n int
+			v[i], n = redactValue(inner, patterns)
+			total += n
+		}
+		return v, total
+	}
+	return v, 0
+}
+
+// redactHistory returns the redacted changes JSON of an item's history
+// events, keyed by event ID. Only events with matches are included.
+func (db *DB) redactHistory(itemID string, patterns []*regexp.Regexp) (map[int64]string, int, error) {
+	rows, err := db.Query(`SELECT id, changes FROM history WHERE item_id = ? AND changes IS NOT NULL AND changes != ''`, itemID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	redacted := make(map[int64]string)
+	total := 0
+	for rows.Next() {
+		var id int64
+		var changes string
+		if err := rows.Scan(&id, &changes); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan history: %w", err)
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(changes), &decoded); err != nil {
+			continue // leave unparseable entries alone
+		}
+		decoded, n := redactValue(decoded, patterns)
+		if n == 0 {
+			continue
+		}
+		data, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode history: %w", err)
+		}
+		redacted[id] = string(data)
+		total += n
+	}
+	return redacted, total, rows.Err()
+}
+
+// redactTranscripts returns the redacted text of an item's transcripts,
+// keyed by transcript ID. Only transcripts with matches are included.
+func (db *DB) redactTranscripts(itemID string, patterns []*regexp.Regexp) (map[int64]string, int, error) {
+	rows, err := db.Query(`SELECT id, content FROM transcripts WHERE item_id = ?`, itemID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read transcripts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	redacted := make(map[int64]string)
+	total := 0
+	for rows.Next() {
+		var id int64
+		var content []byte
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan transcript: %w", err)
+		}
+		text, err := decompressTranscript(content)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decompress transcript #%d: %w", id, err)
+		}
+		text, n := redactString(text, patterns)
+		if n == 0 {
+			continue
+		}
+		redacted[id] = text
+		total += n
+	}
+	return redacted, total, rows.Err()
+}
+
+// RedactItem scrubs matches of patterns from an item's description, results,
+// logs, previous descriptions, transcripts, and the history events that
+// copied them. When anything is replaced a "redacted" history event records
+// the counts, never the matched text. With dryRun, matches are only counted.
+func (db *DB) RedactItem(itemID string, patterns []*regexp.Regexp, dryRun bool) (*RedactResult, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no redaction patterns given")
+	}
+	item, err := db.GetItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := db.GetLogs(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RedactResult{}
+	description, n := redactString(item.Description, patterns)
+	result.Description = n
+	results, n := redactString(item.Results, patterns)
+	result.Results = n
+	logMessages := make(map[int64]string)
+	for _, log := range logs {
+		message, n := redactString(log.Message, patterns)
+		if n > 0 {
+			logMessages[log.ID] = message
+			result.Logs += n
+		}
+	}
+	history, n, err := db.redactHistory(itemID, patterns)
+	if err != nil {
+		return nil, err
+	}
+	result.History = n
+	versions, err := db.DescriptionVersions(itemID)
+	if err != nil {
+		return nil, err
+	}
+	versionTexts := make(map[int64]string)
+	for _, v := range versions {
+		text, n := redactString(v.Description, patterns)
+		if n > 0 {
+			versionTexts[v.id] = text
+			result.Versions += n
+		}
+	}
+	transcripts, n, err := db.redactTranscripts(itemID, patterns)
+	if err != nil {
+		return nil, err
+	}
+	result.Transcripts = n
+	if dryRun || result.Total() == 0 {
+		return result, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if result.Description > 0 || result.Results > 0 {
+		if _, err := tx.Exec(`UPDATE items SET description = ?, results = ? WHERE id = ?`,
+			description, results, itemID); err != nil {
+			return nil, fmt.Errorf("failed to redact item: %w", err)
+		}
+	}
+	for id, message := range logMessages {
+		if _, err := tx.Exec(`UPDATE logs SET message = ? WHERE id = ?`, message, id); err != nil {
+			return nil, fmt.Errorf("failed to redact log: %w", err)
+		}
+	}
+	for id, changes := range history {
+		if _, err := tx.Exec(`UPDATE history SET changes = ? WHERE id = ?`, changes, id); err != nil {
+			return nil, fmt.Errorf("failed to redact history: %w", err)
+		}
+	}
+	for id, text := range versionTexts {
+		if _, err := tx.Exec(`UPDATE description_versions SET description = ? WHERE id = ?`, text, id); err != nil {
+			return nil, fmt.Errorf("failed to redact description version: %w", err)
+		}
+	}
+	for id, text := range transcripts {
+		content, err := compressTranscript(text)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`UPDATE transcripts SET size = ?, content = ? WHERE id = ?`, len(text), content, id); err != nil {
+			return nil, fmt.Errorf("failed to redact transcript: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	_ = db.RecordHistory(itemID, EventTypeRedacted, map[string]any{
+		"description": result.Description,
+		"results":     result.Results,
+		"logs":        result.Logs,
+		"history":     result.History,
+		"versions":    result.Versions,
+		"transcripts": result.Transcripts,
+	})
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedactItem(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Leaky")
+	if err := db.SetDescription(item.ID, "Use key sk-abc123 to call the API"); err != nil {
+		t.Fatalf("SetDescription failed: %v", err)
+	}
+	if err := db.AddLog(item.ID, "tried sk-abc123 and sk-def456"); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+	if err := db.AddLog(item.ID, "nothing secret"); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+	patterns := []*regexp.Regexp{regexp.MustCompile(`sk-[a-z0-9]+`)}
+
+	preview, err := db.RedactItem(item.ID, patterns, true)
+	if err != nil {
+		t.Fatalf("RedactItem dry run failed: %v", err)
+	}
+	// The description_changed event holds a copy of the description
+	if preview.Description != 1 || preview.Logs != 2 || preview.History != 1 || preview.Total() != 4 {
+		t.Errorf("dry run = %+v, want 1 description, 2 log, and 1 history match", preview)
+	}
+	if got, _ := db.GetItem(item.ID); !strings.Contains(got.Description, "sk-abc123") {
+		t.Error("dry run changed the description")
+	}
+
+	if _, err := db.RedactItem(item.ID, patterns, false); err != nil {
+		t.Fatalf("RedactItem failed: %v", err)
+	}
+	got, _ := db.GetItem(item.ID)
+	if got.Description != "Use key [REDACTED] to call the API" {
+		t.Errorf("description = %q", got.Description)
+	}
+	logs, _ := db.GetLogs(item.ID)
+	if logs[0].Message != "tried [REDACTED] and [REDACTED]" || logs[1].Message != "nothing secret" {
+		t.Errorf("logs = %q, %q", logs[0].Message, logs[1].Message)
+	}
+
+	history, _ := db.GetItemHistory(item.ID, 0)
+	found := false
+	for _, e := range history {
+		if e.EventType == EventTypeRedacted {
+			found = true
+		}
+		for _, v := range e.Changes {
+			if s, ok := v.(string); ok && strings.Contains(s, "sk-") {
+				t.Errorf("history event %s leaks the secret: %v", e.EventType, e.Changes)
+			}
+		}
+	}
+	if !found {
+		t.Error("no redacted event in history")
+	}
+
+	again, err := db.RedactItem(item.ID, patterns, false)
+	if err != nil || again.Total() != 0 {
+		t.Errorf("second redaction = %+v, %v; want no matches", again, err)
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// RestoreItem inserts an item exactly as it was saved, keeping its status,
+// timestamps, parent, and custom fields. Unlike CreateItem it doesn't refuse closed parents,
+// since it puts back items that were removed (see 'tpg clean --save'), and it
+// records no history. It fails if an item with the same ID exists.
+func (db *DB) RestoreItem(item *model.Item) error {
+	if !item.Type.IsValid() {
+		return fmt.Errorf("invalid item type: %s", item.Type)
+	}
+	if !item.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", item.Status)
+	}
+	if item.Project != "" {
+		if err := db.EnsureProject(item.Project); err != nil {
+			return err
+		}
+	}
+
+	varsJSON, err := marshalTemplateVars(item.TemplateVars)
+	if err != nil {
+		return err
+	}
+	var closedAt any
+	if item.ClosedAt != nil {
+		closedAt = sqlTime(*item.ClosedAt)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO items (
+			id, project, type, title, description, status, priority, parent_id,
+			template_id, step_index, variables, template_hash, results,
+			worktree_branch, worktree_base, merge_status, worktree_fork_point,
+			shared_context, closing_instructions,
+			closed_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.ID, item.Project, item.Type, item.Title, item.Description,
+		item.Status, item.Priority, item.ParentID,
+		item.TemplateID, item.StepIndex, varsJSON, item.TemplateHash, item.Results,
+		item.WorktreeBranch, item.WorktreeBase, item.MergeStatus, item.WorktreeForkPoint,
+		item.SharedContext, item.ClosingInstructions,
+		closedAt, sqlTime(item.CreatedAt), sqlTime(item.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore item %s: %w", item.ID, err)
+	}
+	for key, value := range item.Fields {
+		if _, err := db.Exec(`INSERT INTO item_fields (item_id, key, value) VALUES (?, ?, ?)`, item.ID, key, value); err != nil {
+			return fmt.Errorf("failed to restore field %s of %s: %w", key, item.ID, err)
+		}
+	}
+	return nil
+}
+
+// RestoreLog inserts a log entry with its original time and category,
+// without touching the item's timestamp.
+func (db *DB) RestoreLog(log model.Log) error {
+	category := log.Category
+	if !category.IsValid() {
+		category = model.LogCategoryOf(log.Message)
+	}
+	_, err := db.Exec(`
+		INSERT INTO logs (item_id, message, category, progress, created_at) VALUES (?, ?, ?, ?, ?)`,
+		log.ItemID, log.Message, string(category), log.Progress, sqlTime(log.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to restore log: %w", err)
+	}
+	return nil
+}
+
+// RestoreDep puts back a dependency of itemID on dependsOnID. It skips the
+// cycle checks and status changes of AddDep, as the dependency existed before.
+// It reports whether the dependency was missing.
+func (db *DB) RestoreDep(itemID, dependsOnID string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM items WHERE id IN (?, ?)`, itemID, dependsOnID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify items: %w", err)
+	}
+	if count != 2 {
+		return false, fmt.Errorf("one or both items not found: %s, %s", itemID, dependsOnID)
+	}
+	result, err := db.Exec(`INSERT OR IGNORE INTO deps (item_id, depends_on) VALUES (?, ?)`, itemID, dependsOnID)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore dependency: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// RelinkLearning links a learning back to the item it came from, if it
+// isn't linked to anything else. It reports whether it did.
+func (db *DB) RelinkLearning(learningID, itemID string) (bool, error) {
+	result, err := db.Exec(`UPDATE learnings SET task_id = ? WHERE id = ? AND task_id IS NULL`, itemID, learningID)
+	if err != nil {
+		return false, fmt.Errorf("failed to relink learning %s: %w", learningID, err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// SearchResults finds items whose completion results match an FTS5 query,
+// best matches first. An empty project searches every project.
+func (db *DB) SearchResults(project, query string) ([]model.Item, error) {
+	sqlQuery := fmt.Sprintf(`
+		SELECT %s FROM items
+		JOIN (
+			SELECT rowid AS fts_rowid, rank FROM items_results_fts
+			WHERE items_results_fts MATCH ?
+		) m ON items.rowid = m.fts_rowid`, itemSelectColumns)
+	args := []any{query}
+	if project != "" {
+		sqlQuery += ` WHERE project = ?`
+		args = append(args, project)
+	}
+	sqlQuery += ` ORDER BY m.rank`
+	return db.queryItems(sqlQuery, args...)
+}
+
+// DoneWithoutResults returns done items with empty results, such as items
+// closed before results were required or brought in by an import. Oldest
+// first.
+func (db *DB) DoneWithoutResults(project string) ([]model.Item, error) {
+	query := fmt.Sprintf(`SELECT %s FROM items
+		WHERE status = 'done' AND TRIM(COALESCE(results, '')) = ''`, itemSelectColumns)
+	var args []any
+	if project != "" {
+		query += ` AND project = ?`
+		args = append(args, project)
+	}
+	query += ` ORDER BY closed_at ASC, created_at ASC`
+	return db.queryItems(query, args...)
+}
+
+// autoLogPrefixes are the log messages tpg writes itself, which say nothing
+// about what the work produced.
+var autoLogPrefixes = []string{"Completed", "Started", "Reopened: ", "Blocked: ", "Canceled: ", "unblocked by ", "Closing instructions:"}
+
+// ResultsBackfill suggests results for a done item from its logs: the most
+// recent progress log, or else the most recent log tpg didn't write itself.
+// It returns "" when there is nothing to use.
+func (db *DB) ResultsBackfill(itemID string) (string, error) {
+	log, err := db.LatestProgressLog(itemID)
+	if err != nil || log == nil {
+		return "", err
+	}
+	return log.Message, nil
+}
+
+// LatestProgressLog returns an item's most recent progress log, or else its
+// most recent log tpg didn't write itself. It returns nil when there is
+// neither.
+func (db *DB) LatestProgressLog(itemID string) (*model.Log, error) {
+	var log model.Log
+	err := db.QueryRow(`SELECT id, item_id, message, category, progress, created_at FROM logs
+		WHERE item_id = ? AND progress IS NOT NULL
+		ORDER BY created_at DESC, id DESC LIMIT 1`, itemID).
+		Scan(&log.ID, &log.ItemID, &log.Message, &log.Category, &log.Progress, &log.CreatedAt)
+	if err == nil {
+		return &log, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get progress log: %w", err)
+	}
+
+	logs, err := db.GetLogs(itemID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(logs) - 1; i >= 0; i-- {
+		if !isAutoLog(logs[i].Message) {
+			return &logs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func isAutoLog(message string) bool {
+	for _, prefix := range autoLogPrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetResults replaces the results of a closed item, e.g. to fill in results
+// it was closed without.
+func (db *DB) SetResults(itemID, results string) error {
+	item, err := db.GetItem(itemID)
+	if err != nil {
+		return err
+	}
+	if item.Status != model.StatusDone && item.Status != model.StatusCanceled {
+		return fmt.Errorf("%s is %s; results can only be set on closed items", itemID, item.Status)
+	}
+	if _, err := db.Exec(`UPDATE items SET results = ?, updated_at = ? WHERE id = ?`,
+		results, sqlTime(time.Now()), itemID); err != nil {
+		return fmt.Errorf("failed to set results: %w", err)
+	}
+	_ = db.RecordHistory(itemID, EventTypeFieldChanged, map[string]any{"key": "results", "old_value": item.Results})
+	return nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestSearchResults(t *testing.T) {
+	db := setupTestDB(t)
+	limiter := createTestItem(t, db, "Rate limiter")
+	cache := createTestItem(t, db, "Cache")
+	other := createTestItemWithProject(t, db, "Elsewhere", "other", model.StatusOpen, 2)
+
+	if err := db.CompleteItem(limiter.ID, "Added a token bucket rate limiter in api/limit.go", AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+	if err := db.CompleteItem(cache.ID, "Cached lookups in memory", AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+	if err := db.CompleteItem(other.ID, "Another rate limiter", AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+
+	items, err := db.SearchResults("test", "limiter")
+	if err != nil {
+		t.Fatalf("SearchResults: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != limiter.ID {
+		t.Errorf("search in project = %v, want only %s", itemIDs(items), limiter.ID)
+	}
+	if items, _ := db.SearchResults("", "limiter"); len(items) != 2 {
+		t.Errorf("search across projects = %v, want 2 items", itemIDs(items))
+	}
+	if items, _ := db.SearchResults("test", `"token bucket"`); len(items) != 1 {
+		t.Errorf("phrase search = %v, want 1 item", itemIDs(items))
+	}
+
+	// Changed results are re-indexed
+	if _, err := db.Exec(`UPDATE items SET results = ? WHERE id = ?`, "Reverted", limiter.ID); err != nil {
+		t.Fatalf("update results: %v", err)
+	}
+	if items, _ := db.SearchResults("test", "limiter"); len(items) != 0 {
+		t.Errorf("stale match after update: %v", itemIDs(items))
+	}
+
+	if _, err := db.SearchResults("test", `"unterminated`); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+}
+
+func itemIDs(items []model.Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestResultsBackfill(t *testing.T) {
+	db := setupTestDB(t)
+	logged := createTestItem(t, db, "Logged")
+	progressed := createTestItem(t, db, "Progressed")
+	bare := createTestItem(t, db, "Bare")
+	finished := createTestItem(t, db, "Finished")
+
+	if err := db.AddLog(logged.ID, "Moved config parsing to config/load.go"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	if err := db.AddProgressLog(progressed.ID, 90, "Parser handles nested lists"); err != nil {
+		t.Fatalf("AddProgressLog: %v", err)
+	}
+	if err := db.AddLog(progressed.ID, "a later plain note"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	for _, item := range []*model.Item{logged, progressed, bare} {
+		if _, err := db.Exec(`UPDATE items SET status = 'done', results = '' WHERE id = ?`, item.ID); err != nil {
+			t.Fatalf("close %s: %v", item.ID, err)
+		}
+		if err := db.AddLog(item.ID, "Completed"); err != nil {
+			t.Fatalf("AddLog: %v", err)
+		}
+	}
+	if err := db.CompleteItem(finished.ID, "Has results", AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+
+	missing, err := db.DoneWithoutResults("test")
+	if err != nil {
+		t.Fatalf("DoneWithoutResults: %v", err)
+	}
+	if len(missing) != 3 {
+		t.Fatalf("DoneWithoutResults = %v, want 3 items", itemIDs(missing))
+	}
+
+	for id, want := range map[string]string{
+		logged.ID:     "Moved config parsing to config/load.go",
+		progressed.ID: "Parser handles nested lists",
+		bare.ID:       "",
+	} {
+		got, err := db.ResultsBackfill(id)
+		if err != nil {
+			t.Fatalf("ResultsBackfill(%s): %v", id, err)
+		}
+		if got != want {
+			t.Errorf("ResultsBackfill(%s) = %q, want %q", id, got, want)
+		}
+	}
+
+	if err := db.SetResults(logged.ID, "Backfilled"); err != nil {
+		t.Fatalf("SetResults: %v", err)
+	}
+	if missing, _ := db.DoneWithoutResults("test"); len(missing) != 2 {
+		t.Errorf("after backfill, %d items still missing results, want 2", len(missing))
+	}
+	open := createTestItem(t, db, "Open")
+	if err := db.SetResults(open.ID, "nope"); err == nil {
+		t.Error("SetResults on an open item should fail")
+	}
+}
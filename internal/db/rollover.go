@@ -0,0 +1,211 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EventTypeRolledOver is recorded on an epic whose unfinished work was moved
+// to another epic.
+const EventTypeRolledOver = "rolled_over"
+
+// RolloverResult describes an epic rollover.
+type RolloverResult struct {
+	ToID       string   // the epic that received the unfinished work
+	Created    bool     // whether ToID was created by the rollover
+	Moved      []string // unfinished children moved, each with its subtree
+	Dependents []string // items that waited on the original and now also wait on ToID
+	Completed  []string // epics closed: the original, then any parents it completed
+}
+
+// RolloverEpic moves the unfinished children of an epic, each with its whole
+// subtree, to the epic toID and closes the original with note as its results.
+// An empty toID creates a new epic next to the original, titled newTitle (the
+// original's title when empty) and copying its description, priority, labels,
+// and epic context.
+//
+// Items that depended on the original also come to depend on the target, so
+// they keep waiting for the carried-over work. force lets in-progress items
+// switch worktree branch, as for MoveSubtree. Every move is checked before
+// the new epic is created or anything is moved.
+func (db *DB) RolloverEpic(epicID, toID, newTitle, note string, force bool) (*RolloverResult, error) {
+	epic, err := db.GetItem(epicID)
+	if err != nil {
+		return nil, err
+	}
+	if epic.Type != model.ItemTypeEpic {
+		return nil, fmt.Errorf("%s is a %s; only epics can be rolled over", epicID, epic.Type)
+	}
+	if epic.Status == model.StatusDone || epic.Status == model.StatusCanceled {
+		return nil, fmt.Errorf("epic %s is already closed", epicID)
+	}
+
+	children, err := db.GetChildren(epicID)
+	if err != nil {
+		return nil, err
+	}
+	var unfinished []model.Item
+	for _, child := range children {
+		if child.Status != model.StatusDone && child.Status != model.StatusCanceled {
+			unfinished = append(unfinished, child)
+		}
+	}
+	if len(unfinished) == 0 {
+		return nil, fmt.Errorf("epic %s has no unfinished children to roll over", epicID)
+	}
+
+	// Check every move before changing anything, so that a refused move
+	// leaves both epics as they were.
+	target := &moveTarget{}
+	if toID == "" {
+		// The new epic will sit next to the original, under the same parent.
+		if epic.ParentID != nil {
+			parent, err := db.moveTargetFor(*epic.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			target.ancestors, target.worktree = parent.ancestors, parent.worktree
+		}
+	} else {
+		if err := db.checkRolloverTarget(epicID, toID); err != nil {
+			return nil, err
+		}
+		if target, err = db.moveTargetFor(toID); err != nil {
+			return nil, err
+		}
+	}
+	var checked []*model.Item
+	for _, child := range unfinished {
+		_, item, err := db.checkMove(child.ID, target, force)
+		if err != nil {
+			return nil, err
+		}
+		checked = append(checked, item)
+	}
+
+	result := &RolloverResult{ToID: toID}
+	if toID == "" {
+		if result.ToID, err = db.createRolloverEpic(epic, newTitle); err != nil {
+			return nil, err
+		}
+		result.Created = true
+	}
+
+	for _, item := range checked {
+		if err := db.applyMove(item, result.ToID); err != nil {
+			if len(result.Moved) > 0 {
+				return result, fmt.Errorf("%w (already moved to %s: %v)", err, result.ToID, result.Moved)
+			}
+			return result, err
+		}
+		result.Moved = append(result.Moved, item.ID)
+	}
+
+	rows, err := db.Query(`SELECT item_id FROM deps WHERE depends_on = ? ORDER BY item_id`, epicID)
+	if err != nil {
+		return result, fmt.Errorf("failed to find dependents of %s: %w", epicID, err)
+	}
+	var dependents []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return result, err
+		}
+		dependents = append(dependents, id)
+	}
+	rows.Close()
+	for _, id := range dependents {
+		if id == result.ToID {
+			continue
+		}
+		if err := db.AddDep(id, result.ToID); err != nil {
+			return result, fmt.Errorf("failed to carry dependency of %s over to %s: %w", id, result.ToID, err)
+		}
+		result.Dependents = append(result.Dependents, id)
+	}
+
+	if note == "" {
+		note = fmt.Sprintf("Rolled over %d unfinished item(s) to %s", len(result.Moved), result.ToID)
+	}
+	_ = db.RecordHistory(epicID, EventTypeRolledOver, map[string]any{
+		"to":    result.ToID,
+		"items": result.Moved,
+	})
+	if err := db.AddLog(result.ToID, fmt.Sprintf("Rolled over %d item(s) from %s", len(result.Moved), epicID)); err != nil {
+		return result, err
+	}
+	if result.Completed, err = db.completeEpic(epicID, note); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// checkRolloverTarget verifies that toID can receive work rolled over from
+// epicID: an open epic outside epicID's subtree.
+func (db *DB) checkRolloverTarget(epicID, toID string) error {
+	if toID == epicID {
+		return fmt.Errorf("cannot roll %s over to itself", epicID)
+	}
+	target, err := db.GetItem(toID)
+	if err != nil {
+		return err
+	}
+	if !target.Type.CanHaveChildren() {
+		return fmt.Errorf("cannot roll over to %s: it is a %s, not an epic", toID, target.Type)
+	}
+	if target.Status == model.StatusDone || target.Status == model.StatusCanceled {
+		return fmt.Errorf("cannot roll over to closed epic %s", toID)
+	}
+	chain, err := db.GetParentChain(toID)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range chain {
+		if ancestor.ID == epicID {
+			return fmt.Errorf("cannot roll over to %s: it is inside %s, which is being closed", toID, epicID)
+		}
+	}
+	return nil
+}
+
+// createRolloverEpic creates the epic that continues epic, next to it.
+func (db *DB) createRolloverEpic(epic *model.Item, title string) (string, error) {
+	if title == "" {
+		title = epic.Title
+	}
+	id, err := db.GenerateItemID(epic.Type)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	next := &model.Item{
+		ID:                  id,
+		Project:             epic.Project,
+		Type:                epic.Type,
+		Title:               title,
+		Description:         epic.Description,
+		Status:              model.StatusOpen,
+		Priority:            epic.Priority,
+		ParentID:            epic.ParentID,
+		SharedContext:       epic.SharedContext,
+		ClosingInstructions: epic.ClosingInstructions,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if err := db.CreateItem(next); err != nil {
+		return "", fmt.Errorf("failed to create epic for rollover: %w", err)
+	}
+	labels, err := db.GetItemLabels(epic.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, label := range labels {
+		if err := db.AddLabelToItem(id, epic.Project, label.Name); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestRolloverEpic(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Sprint 14", "test")
+	done := createTestItem(t, db, "Shipped")
+	open := createTestItem(t, db, "Carry me")
+	active := createTestItem(t, db, "Half done")
+	waiting := createTestItem(t, db, "Waits for the sprint")
+	for _, id := range []string{done.ID, open.ID, active.ID} {
+		if err := db.SetParent(id, epic.ID); err != nil {
+			t.Fatalf("SetParent: %v", err)
+		}
+	}
+	if err := db.AddDep(waiting.ID, epic.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddLabelToItem(epic.ID, "test", "sprint"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+	if err := db.UpdateStatus(done.ID, model.StatusDone, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := db.UpdateStatus(active.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	result, err := db.RolloverEpic(epic.ID, "", "Sprint 15", "", false)
+	if err != nil {
+		t.Fatalf("RolloverEpic: %v", err)
+	}
+	if !result.Created || result.ToID == "" {
+		t.Fatalf("expected a new epic, got %+v", result)
+	}
+	if len(result.Moved) != 2 {
+		t.Errorf("Moved = %v, want the two unfinished children", result.Moved)
+	}
+	if !reflect.DeepEqual(result.Dependents, []string{waiting.ID}) {
+		t.Errorf("Dependents = %v, want [%s]", result.Dependents, waiting.ID)
+	}
+
+	next, err := db.GetItem(result.ToID)
+	if err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if next.Title != "Sprint 15" || next.Type != model.ItemTypeEpic || next.Status != model.StatusOpen {
+		t.Errorf("new epic = %+v", next)
+	}
+	if labels, _ := db.GetItemLabels(next.ID); len(labels) != 1 || labels[0].Name != "sprint" {
+		t.Errorf("new epic labels = %+v, want [sprint]", labels)
+	}
+	for _, id := range []string{open.ID, active.ID} {
+		item, _ := db.GetItem(id)
+		if item.ParentID == nil || *item.ParentID != next.ID {
+			t.Errorf("%s parent = %v, want %s", id, item.ParentID, next.ID)
+		}
+	}
+	if item, _ := db.GetItem(active.ID); item.Status != model.StatusInProgress {
+		t.Errorf("moved item status = %s, want in_progress kept", item.Status)
+	}
+	if item, _ := db.GetItem(done.ID); item.ParentID == nil || *item.ParentID != epic.ID {
+		t.Errorf("finished child moved: parent = %v", item.ParentID)
+	}
+
+	closed, _ := db.GetItem(epic.ID)
+	if closed.Status != model.StatusDone || closed.Results != "Rolled over 2 unfinished item(s) to "+next.ID {
+		t.Errorf("original epic: status=%s results=%q", closed.Status, closed.Results)
+	}
+	if blocked, _ := db.HasUnmetDeps(waiting.ID); !blocked {
+		t.Error("dependent of the original should still wait on the carried-over work")
+	}
+
+	if _, err := db.RolloverEpic(epic.ID, next.ID, "", "", false); err == nil {
+		t.Error("expected error rolling over a closed epic")
+	}
+}
+
+func TestRolloverEpic_Target(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Sprint 14", "test")
+	inner := createTestEpic(t, db, "Inner", "test")
+	target := createTestEpic(t, db, "Backlog", "test")
+	task := createTestItem(t, db, "Carry me")
+	if err := db.SetParent(inner.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+	if err := db.SetParent(task.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+
+	if _, err := db.RolloverEpic(epic.ID, inner.ID, "", "", false); err == nil {
+		t.Error("expected error rolling over into the epic's own subtree")
+	}
+	if _, err := db.RolloverEpic(epic.ID, task.ID, "", "", false); err == nil {
+		t.Error("expected error rolling over to a task")
+	}
+
+	result, err := db.RolloverEpic(epic.ID, target.ID, "", "Descoped", false)
+	if err != nil {
+		t.Fatalf("RolloverEpic: %v", err)
+	}
+	if result.Created || result.ToID != target.ID || len(result.Moved) != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if closed, _ := db.GetItem(epic.ID); closed.Results != "Descoped" {
+		t.Errorf("results = %q, want the note", closed.Results)
+	}
+}
+
+func TestRolloverEpic_RefusedMoveChangesNothing(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Sprint 14", "test")
+	open := createTestItem(t, db, "Carry me")
+	active := createTestItem(t, db, "Half done")
+	for _, id := range []string{open.ID, active.ID} {
+		if err := db.SetParent(id, epic.ID); err != nil {
+			t.Fatalf("SetParent: %v", err)
+		}
+	}
+	if err := db.SetWorktreeMetadata(epic.ID, "feature/sprint-14", "main"); err != nil {
+		t.Fatalf("SetWorktreeMetadata: %v", err)
+	}
+	if err := db.UpdateStatus(active.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	before, err := db.ListItems("test", nil)
+	if err != nil {
+		t.Fatalf("ListItems: %v", err)
+	}
+
+	// The new epic has no worktree, so the in-progress child can't move
+	// without force; nothing may be created or moved before that is known.
+	if _, err := db.RolloverEpic(epic.ID, "", "", "", false); err == nil {
+		t.Fatal("expected error moving an in-progress item off its worktree")
+	}
+	after, err := db.ListItems("test", nil)
+	if err != nil {
+		t.Fatalf("ListItems: %v", err)
+	}
+	if len(after) != len(before) {
+		t.Errorf("items = %d, want %d (no new epic)", len(after), len(before))
+	}
+	if got, _ := db.GetItem(epic.ID); got.Status != model.StatusOpen {
+		t.Errorf("epic status = %s, want open", got.Status)
+	}
+	for _, id := range []string{open.ID, active.ID} {
+		if got, _ := db.GetItem(id); got.ParentID == nil || *got.ParentID != epic.ID {
+			t.Errorf("%s was moved off the epic", id)
+		}
+	}
+}
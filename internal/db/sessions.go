@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Session summarizes the history events recorded under one session key
+// (see CurrentSession), e.g. a single agent run.
+type Session struct {
+	ID      string
+	ActorID string // the last agent seen in the session, if any
+	First   time.Time
+	Last    time.Time
+	Events  int
+	Items   int
+}
+
+// ListSessions returns the sessions with history at or after since, most
+// recently active first. A limit of zero or less returns them all.
+func (db *DB) ListSessions(since time.Time, limit int) ([]Session, error) {
+	query := `
+		SELECT session, MAX(actor_id), MIN(created_at), MAX(created_at), COUNT(*), COUNT(DISTINCT item_id)
+		FROM history
+		WHERE session IS NOT NULL AND session != ''`
+	var args []any
+	if !since.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, sqlTime(since))
+	}
+	query += ` GROUP BY session ORDER BY MAX(created_at) DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []Session
+	for rows.Next() {
+		var s Session
+		var actor sql.NullString
+		var first, last string
+		if err := rows.Scan(&s.ID, &actor, &first, &last, &s.Events, &s.Items); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		s.ActorID = actor.String
+		s.First = parseHistoryTime(first)
+		s.Last = parseHistoryTime(last)
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// SessionHistory returns every history event recorded in a session, oldest
+// first.
+func (db *DB) SessionHistory(session string) ([]HistoryEntry, error) {
+	entries, err := db.GetHistory(HistoryQueryOptions{Session: session, Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// parseHistoryTime parses a timestamp written by sqlTime, as returned by
+// aggregates that lose the column's DATETIME type.
+func parseHistoryTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
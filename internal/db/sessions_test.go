@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestSessions(t *testing.T) {
+	db := setupTestDB(t)
+
+	t.Setenv("AGENT_ID", "")
+	t.Setenv("TPG_SESSION", "run-1")
+	first := createTestItem(t, db, "First")
+	if err := db.UpdateStatus(first.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	t.Setenv("TPG_SESSION", "run-2")
+	createTestItem(t, db, "Second")
+
+	sessions, err := db.ListSessions(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	counts := map[string]Session{}
+	for _, s := range sessions {
+		counts[s.ID] = s
+	}
+	if s := counts["session:run-1"]; s.Events != 2 || s.Items != 1 || s.Last.IsZero() {
+		t.Errorf("session:run-1 = %+v, want 2 events on 1 item", s)
+	}
+	if s := counts["session:run-2"]; s.Events != 1 {
+		t.Errorf("session:run-2 = %+v, want 1 event", s)
+	}
+
+	entries, err := db.SessionHistory("session:run-1")
+	if err != nil {
+		t.Fatalf("SessionHistory: %v", err)
+	}
+	if len(entries) != 2 || entries[0].EventType != EventTypeCreated || entries[1].EventType != EventTypeStatusChanged {
+		t.Errorf("SessionHistory = %+v, want created then status_changed", entries)
+	}
+	if entries[0].Session != "session:run-1" {
+		t.Errorf("entry session = %q, want session:run-1", entries[0].Session)
+	}
+}
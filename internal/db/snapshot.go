@@ -0,0 +1,309 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EpicSnapshot is a saved copy of an epic subtree.
+type EpicSnapshot struct {
+	ID        int64
+	EpicID    string
+	Name      string
+	ItemCount int
+	CreatedAt time.Time
+}
+
+// epicSnapshotData is the JSON stored for a snapshot.
+type epicSnapshotData struct {
+	Items  []model.Item        `json:"items"`  // the epic first, then its descendants
+	Deps   []model.Dep         `json:"deps"`   // dependencies of subtree items
+	Labels map[string][]string `json:"labels"` // item ID -> label IDs
+}
+
+// RollbackResult reports what RollbackEpic changed.
+type RollbackResult struct {
+	Restored []string // items updated or re-created from the snapshot
+	Removed  []string // items created after the snapshot and deleted
+	Detached []string // older items moved into the subtree since, moved back out to the top level
+}
+
+// SnapshotEpic saves the epic's subtree (items, statuses, dependencies, and
+// labels) so it can later be restored with RollbackEpic.
+func (db *DB) SnapshotEpic(epicID, name string) (*EpicSnapshot, error) {
+	epic, err := db.GetItem(epicID)
+	if err != nil {
+		return nil, err
+	}
+	if !epic.Type.CanHaveChildren() {
+		return nil, fmt.Errorf("%s is not an epic", epicID)
+	}
+	descendants, err := db.GetDescendants(epicID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := epicSnapshotData{
+		Items:  append([]model.Item{*epic}, descendants...),
+		Labels: make(map[string][]string),
+	}
+	for _, item := range data.Items {
+		deps, err := db.GetDepStatuses(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			data.Deps = append(data.Deps, model.Dep{ItemID: item.ID, DependsOn: dep.ID, Note: dep.Note})
+		}
+		labels, err := db.GetItemLabels(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			data.Labels[item.ID] = append(data.Labels[item.ID], l.ID)
+		}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	now := time.Now()
+	res, err := db.Exec(`INSERT INTO epic_snapshots (epic_id, name, data, created_at) VALUES (?, ?, ?, ?)`,
+		epicID, name, string(encoded), sqlTime(now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return &EpicSnapshot{ID: id, EpicID: epicID, Name: name, ItemCount: len(data.Items), CreatedAt: now}, nil
+}
+
+// ListEpicSnapshots returns the snapshots of an epic, newest first.
+func (db *DB) ListEpicSnapshots(epicID string) ([]EpicSnapshot, error) {
+	rows, err := db.Query(`
+		SELECT id, epic_id, COALESCE(name, ''), data, created_at
+		FROM epic_snapshots WHERE epic_id = ?
+		ORDER BY id DESC`, epicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []EpicSnapshot
+	for rows.Next() {
+		var s EpicSnapshot
+		var raw string
+		if err := rows.Scan(&s.ID, &s.EpicID, &s.Name, &raw, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		var data epicSnapshotData
+		if err := json.Unmarshal([]byte(raw), &data); err == nil {
+			s.ItemCount = len(data.Items)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// RollbackEpic restores an epic subtree to a snapshot. Items are reset to
+// their saved fields and statuses (re-created if deleted), and the subtree's
+// own dependencies and labels are restored. Items created since the snapshot
+// are deleted; older items moved into the subtree since are moved back out to
+// the top level. Logs of restored items are kept, replaced descriptions are
+// kept as previous versions, and status changes are recorded in history.
+func (db *DB) RollbackEpic(epicID string, snapshotID int64) (*RollbackResult, error) {
+	var raw string
+	var takenAt time.Time
+	err := db.QueryRow(`SELECT data, created_at FROM epic_snapshots WHERE id = ? AND epic_id = ?`, snapshotID, epicID).Scan(&raw, &takenAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("snapshot %d not found for %s (use 'tpg epic snapshots %s')", snapshotID, epicID, epicID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+	var data epicSnapshotData
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %d: %w", snapshotID, err)
+	}
+
+	inSnapshot := make(map[string]bool, len(data.Items))
+	for _, item := range data.Items {
+		inSnapshot[item.ID] = true
+	}
+	// The epic itself may have been deleted, in which case nothing else is left to compare.
+	var current []model.Item
+	epic, err := db.GetItem(epicID)
+	if err == nil {
+		current, err = db.GetDescendants(epicID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existing := make(map[string]model.Item, len(current)+1)
+	if epic != nil {
+		existing[epic.ID] = *epic
+	}
+	for _, item := range current {
+		existing[item.ID] = item
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result := &RollbackResult{}
+	now := sqlTime(time.Now())
+
+	// Descriptions edited since the snapshot stay available as previous versions.
+	for _, item := range data.Items {
+		if old, ok := existing[item.ID]; ok && old.Description != item.Description {
+			saveDescriptionVersionIn(tx, item.ID, old.Description)
+		}
+	}
+
+	// Re-create deleted items without a parent first, so parent links can be
+	// restored below regardless of order.
+	for _, item := range data.Items {
+		var exists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM items WHERE id = ?`, item.ID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists > 0 {
+			continue
+		}
+		varsJSON, err := marshalTemplateVars(item.TemplateVars)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO items (
+				id, project, type, title, status, priority,
+				template_id, step_index, variables, template_hash,
+				worktree_branch, worktree_base, created_at, updated_at
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			item.ID, item.Project, item.Type, item.Title, item.Status, item.Priority,
+			item.TemplateID, item.StepIndex, varsJSON, item.TemplateHash,
+			item.WorktreeBranch, item.WorktreeBase, sqlTime(item.CreatedAt), now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-create %s: %w", item.ID, err)
+		}
+	}
+
+	for _, item := range data.Items {
+		var closedAt any
+		if item.ClosedAt != nil {
+			closedAt = sqlTime(*item.ClosedAt)
+		}
+		_, err := tx.Exec(`
+			UPDATE items
+			SET type = ?, title = ?, description = ?, status = ?, priority = ?, parent_id = ?,
+			    results = ?, shared_context = ?, closing_instructions = ?,
+			    agent_id = NULL, agent_last_active = NULL, closed_at = ?, updated_at = ?
+			WHERE id = ?`,
+			item.Type, item.Title, item.Description, item.Status, item.Priority, item.ParentID,
+			item.Results, item.SharedContext, item.ClosingInstructions,
+			closedAt, now, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", item.ID, err)
+		}
+		result.Restored = append(result.Restored, item.ID)
+	}
+
+	// Items added to the subtree since the snapshot are removed: ones created
+	// since are deleted, older ones that were moved in are moved back out.
+	// Timestamps are to the second, so an item created in the same second as
+	// the snapshot counts as older and is kept.
+	created := make(map[string]bool)
+	for _, item := range current {
+		if !inSnapshot[item.ID] && item.CreatedAt.After(takenAt) {
+			created[item.ID] = true
+		}
+	}
+	for _, item := range current {
+		if inSnapshot[item.ID] || created[item.ID] || item.ParentID == nil {
+			continue
+		}
+		if parent := *item.ParentID; inSnapshot[parent] || created[parent] {
+			if _, err := tx.Exec(`UPDATE items SET parent_id = NULL, updated_at = ? WHERE id = ?`, now, item.ID); err != nil {
+				return nil, fmt.Errorf("failed to move %s out of %s: %w", item.ID, epicID, err)
+			}
+			result.Detached = append(result.Detached, item.ID)
+		}
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		id := current[i].ID
+		if !created[id] {
+			continue
+		}
+		if err := db.deleteItemInternal(tx, id); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", id, err)
+		}
+		result.Removed = append(result.Removed, id)
+	}
+
+	// Restore the subtree's own dependencies and labels.
+	for _, item := range data.Items {
+		if _, err := tx.Exec(`DELETE FROM deps WHERE item_id = ?`, item.ID); err != nil {
+			return nil, fmt.Errorf("failed to reset dependencies of %s: %w", item.ID, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM item_labels WHERE item_id = ?`, item.ID); err != nil {
+			return nil, fmt.Errorf("failed to reset labels of %s: %w", item.ID, err)
+		}
+		for _, labelID := range data.Labels[item.ID] {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO item_labels (item_id, label_id) SELECT ?, id FROM labels WHERE id = ?`, item.ID, labelID); err != nil {
+				return nil, fmt.Errorf("failed to restore labels of %s: %w", item.ID, err)
+			}
+		}
+	}
+	for _, dep := range data.Deps {
+		// Dependencies on items that no longer exist are dropped.
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO deps (item_id, depends_on, note) SELECT ?, id, ? FROM items WHERE id = ?`, dep.ItemID, nullString(dep.Note), dep.DependsOn); err != nil {
+			return nil, fmt.Errorf("failed to restore dependency %s -> %s: %w", dep.ItemID, dep.DependsOn, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Record status changes as UpdateStatus would, flagging the ones the
+	// status graph doesn't allow as forced.
+	for _, item := range data.Items {
+		old, ok := existing[item.ID]
+		if !ok || old.Status == item.Status {
+			continue
+		}
+		changes := map[string]any{"old": string(old.Status), "new": string(item.Status), "snapshot": snapshotID}
+		if model.CheckTransition(old.Status, item.Status) != nil {
+			_ = db.RecordHistory(item.ID, EventTypeStatusForced, changes)
+		}
+		eventType := EventTypeStatusChanged
+		wasClosed := old.Status == model.StatusDone || old.Status == model.StatusCanceled
+		if wasClosed && item.Status != model.StatusDone && item.Status != model.StatusCanceled {
+			eventType = EventTypeReopened
+		}
+		_ = db.RecordHistory(item.ID, eventType, changes)
+	}
+
+	msg := fmt.Sprintf("Rolled back to snapshot %d (%d restored", snapshotID, len(result.Restored))
+	if len(result.Removed) > 0 {
+		msg += fmt.Sprintf(", %d removed: %s", len(result.Removed), strings.Join(result.Removed, ", "))
+	}
+	if len(result.Detached) > 0 {
+		msg += fmt.Sprintf(", %d moved out: %s", len(result.Detached), strings.Join(result.Detached, ", "))
+	}
+	_ = db.AddLog(epicID, msg+")")
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestSnapshotAndRollbackEpic(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Experiment", "test")
+	a := createTestItem(t, db, "Step A")
+	b := createTestItem(t, db, "Step B")
+	outside := createTestItem(t, db, "Outside")
+	older := createTestItem(t, db, "Older")
+	for _, id := range []string{a.ID, b.ID} {
+		if err := db.SetParent(id, epic.ID); err != nil {
+			t.Fatalf("SetParent: %v", err)
+		}
+	}
+	if err := db.AddDep(b.ID, a.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.SetDepNote(b.ID, a.ID, "needs A's schema"); err != nil {
+		t.Fatalf("SetDepNote: %v", err)
+	}
+	if err := db.SetDescription(a.ID, "original plan"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+
+	snapshot, err := db.SnapshotEpic(epic.ID, "baseline")
+	if err != nil {
+		t.Fatalf("SnapshotEpic: %v", err)
+	}
+	if snapshot.ItemCount != 3 {
+		t.Errorf("expected 3 items in snapshot, got %d", snapshot.ItemCount)
+	}
+	// Timestamps are to the second: date the snapshot back so items created
+	// below count as newer, and Older back further so it stays older.
+	if _, err := db.Exec(`UPDATE epic_snapshots SET created_at = ? WHERE id = ?`, sqlTime(time.Now().Add(-time.Hour)), snapshot.ID); err != nil {
+		t.Fatalf("backdate snapshot: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE items SET created_at = ? WHERE id = ?`, sqlTime(time.Now().Add(-2*time.Hour)), older.ID); err != nil {
+		t.Fatalf("backdate item: %v", err)
+	}
+
+	// Experiment: finish A, drop the dep, delete B, add C, depend on outside
+	if err := db.UpdateStatus(a.ID, model.StatusDone, AgentContext{}, true); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := db.SetTitle(a.ID, "Renamed"); err != nil {
+		t.Fatalf("SetTitle: %v", err)
+	}
+	if err := db.SetDescription(a.ID, "new plan"); err != nil {
+		t.Fatalf("SetDescription: %v", err)
+	}
+	if err := db.SetParent(older.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+	if err := db.AddLog(older.ID, "worked on before the move"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	if err := db.AddDep(a.ID, outside.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.DeleteItem(b.ID, true, false); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	c := createTestItem(t, db, "Step C")
+	if err := db.SetParent(c.ID, epic.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+
+	result, err := db.RollbackEpic(epic.ID, snapshot.ID)
+	if err != nil {
+		t.Fatalf("RollbackEpic: %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != c.ID {
+		t.Errorf("expected %s removed, got %v", c.ID, result.Removed)
+	}
+
+	restoredA, err := db.GetItem(a.ID)
+	if err != nil {
+		t.Fatalf("GetItem(a): %v", err)
+	}
+	if restoredA.Status != model.StatusOpen || restoredA.Title != "Step A" {
+		t.Errorf("expected A restored to open/'Step A', got %s/%q", restoredA.Status, restoredA.Title)
+	}
+	restoredB, err := db.GetItem(b.ID)
+	if err != nil {
+		t.Fatalf("B should be re-created: %v", err)
+	}
+	if restoredB.ParentID == nil || *restoredB.ParentID != epic.ID {
+		t.Errorf("B should be back under %s, got %v", epic.ID, restoredB.ParentID)
+	}
+	if _, err := db.GetItem(c.ID); err == nil {
+		t.Error("C was added after the snapshot and should be removed")
+	}
+	if len(result.Detached) != 1 || result.Detached[0] != older.ID {
+		t.Errorf("expected %s moved out, got %v", older.ID, result.Detached)
+	}
+	movedOut, err := db.GetItem(older.ID)
+	if err != nil {
+		t.Fatalf("Older existed before the snapshot and should be kept: %v", err)
+	}
+	if movedOut.ParentID != nil {
+		t.Errorf("Older should be back at the top level, got parent %v", *movedOut.ParentID)
+	}
+	if logs, _ := db.GetLogs(older.ID); len(logs) == 0 {
+		t.Error("Older's logs should be kept")
+	}
+
+	versions, err := db.DescriptionVersions(a.ID)
+	if err != nil {
+		t.Fatalf("DescriptionVersions: %v", err)
+	}
+	if len(versions) == 0 || versions[len(versions)-1].Description != "new plan" {
+		t.Errorf("expected the replaced description kept as a version, got %+v", versions)
+	}
+	history, err := db.GetItemHistory(a.ID, 50)
+	if err != nil {
+		t.Fatalf("GetItemHistory: %v", err)
+	}
+	reopened := false
+	for _, e := range history {
+		if e.EventType == EventTypeReopened {
+			reopened = true
+		}
+	}
+	if !reopened {
+		t.Error("expected A's return to open to be recorded in history")
+	}
+
+	depsA, _ := db.GetDeps(a.ID)
+	if len(depsA) != 0 {
+		t.Errorf("A's new dependency should be removed, got %v", depsA)
+	}
+	depsB, _ := db.GetDepStatuses(b.ID)
+	if len(depsB) != 1 || depsB[0].ID != a.ID || depsB[0].Note != "needs A's schema" {
+		t.Errorf("B should depend on A again with its note, got %+v", depsB)
+	}
+
+	snapshots, err := db.ListEpicSnapshots(epic.ID)
+	if err != nil {
+		t.Fatalf("ListEpicSnapshots: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "baseline" {
+		t.Errorf("unexpected snapshots: %+v", snapshots)
+	}
+}
+
+func TestRollbackEpic_UnknownSnapshot(t *testing.T) {
+	db := setupTestDB(t)
+	epic := createTestEpic(t, db, "Epic", "test")
+
+	if _, err := db.RollbackEpic(epic.ID, 42); err == nil {
+		t.Error("expected error for unknown snapshot")
+	}
+}
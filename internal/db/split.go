@@ -0,0 +1,90 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// SplitItem converts a task into an epic with the given child tasks. The epic
+// takes the task's place: it inherits the parent, dependencies in both
+// directions, labels, logs, status, and claiming agent, and the task itself
+// is removed. Children are created under the epic, and the split is logged on
+// the epic, all in one transaction.
+func (db *DB) SplitItem(oldID string, epic *model.Item, children []*model.Item) error {
+	old, err := db.GetItem(oldID)
+	if err != nil {
+		return err
+	}
+	if old.Type.CanHaveChildren() {
+		return fmt.Errorf("%s is already a %s; add children with 'tpg add --parent %s'", oldID, old.Type, oldID)
+	}
+	switch old.Status {
+	case model.StatusDone, model.StatusCanceled:
+		return fmt.Errorf("cannot split %s: it is %s", oldID, old.Status)
+	case model.StatusPendingReview:
+		return fmt.Errorf("cannot split %s: it is pending review (approve it or request changes first)", oldID)
+	}
+	if len(children) == 0 {
+		return fmt.Errorf("at least one child task is required to split %s", oldID)
+	}
+	for i, c := range children {
+		if strings.TrimSpace(c.Title) == "" {
+			return fmt.Errorf("child task %d has no title", i+1)
+		}
+		if !c.Type.IsValid() {
+			return fmt.Errorf("child task %q: invalid item type: %s", c.Title, c.Type)
+		}
+	}
+	// Work already under way carries on in the epic
+	epic.Status = old.Status
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Unlike replace, a split keeps the labels
+	if err := replaceItemTx(tx, old, epic, true); err != nil {
+		return err
+	}
+	if old.AgentID != nil {
+		var lastActive any
+		if old.AgentLastActive != nil {
+			lastActive = sqlTime(*old.AgentLastActive)
+		}
+		_, err := tx.Exec(`UPDATE items SET agent_id = ?, agent_last_active = ? WHERE id = ?`,
+			*old.AgentID, lastActive, epic.ID)
+		if err != nil {
+			return fmt.Errorf("failed to transfer agent: %w", err)
+		}
+	}
+
+	childIDs := make([]string, len(children))
+	for i, c := range children {
+		c.ParentID = &epic.ID
+		if err := insertItem(tx, c); err != nil {
+			return fmt.Errorf("failed to create child %q: %w", c.Title, err)
+		}
+		childIDs[i] = c.ID
+	}
+
+	message := fmt.Sprintf("Split from %s into %d tasks: %s", oldID, len(childIDs), strings.Join(childIDs, ", "))
+	if err := insertLog(tx, epic.ID, message); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, c := range children {
+		db.recordCreated(c)
+	}
+	_ = db.RecordHistory(epic.ID, EventTypeSplit, map[string]any{
+		"from":     oldID,
+		"children": childIDs,
+	})
+	return nil
+}
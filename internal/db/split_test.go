@@ -0,0 +1,159 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func newSplitItem(itemType model.ItemType, title string) *model.Item {
+	return &model.Item{
+		ID:        model.GenerateID(itemType),
+		Project:   "test",
+		Type:      itemType,
+		Title:     title,
+		Status:    model.StatusOpen,
+		Priority:  2,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestSplitItem(t *testing.T) {
+	db := setupTestDB(t)
+
+	parent := createTestEpic(t, db, "Parent", "test")
+	task := createTestItem(t, db, "Big task")
+	blocker := createTestItem(t, db, "Blocker")
+	dependent := createTestItem(t, db, "Dependent")
+	if err := db.SetParent(task.ID, parent.ID); err != nil {
+		t.Fatalf("SetParent: %v", err)
+	}
+	if err := db.AddDep(task.ID, blocker.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddDep(dependent.ID, task.ID); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	if err := db.AddLabelToItem(task.ID, "test", "backend"); err != nil {
+		t.Fatalf("AddLabelToItem: %v", err)
+	}
+
+	epic := newSplitItem(model.ItemTypeEpic, "Big task")
+	children := []*model.Item{
+		newSplitItem(model.ItemTypeTask, "Part one"),
+		newSplitItem(model.ItemTypeTask, "Part two"),
+	}
+	if err := db.SplitItem(task.ID, epic, children); err != nil {
+		t.Fatalf("SplitItem: %v", err)
+	}
+
+	if _, err := db.GetItem(task.ID); err == nil {
+		t.Error("original task should be removed")
+	}
+	got, err := db.GetItem(epic.ID)
+	if err != nil {
+		t.Fatalf("GetItem(epic): %v", err)
+	}
+	if got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Errorf("epic should inherit parent %s, got %v", parent.ID, got.ParentID)
+	}
+
+	deps, _ := db.GetDeps(epic.ID)
+	if len(deps) != 1 || deps[0] != blocker.ID {
+		t.Errorf("epic should depend on %s, got %v", blocker.ID, deps)
+	}
+	deps, _ = db.GetDeps(dependent.ID)
+	if len(deps) != 1 || deps[0] != epic.ID {
+		t.Errorf("dependent should now depend on %s, got %v", epic.ID, deps)
+	}
+
+	labels, _ := db.GetItemLabels(epic.ID)
+	if len(labels) != 1 || labels[0].Name != "backend" {
+		t.Errorf("epic should keep label backend, got %v", labels)
+	}
+
+	kids, _ := db.GetChildren(epic.ID)
+	if len(kids) != 2 {
+		t.Errorf("expected 2 children, got %d", len(kids))
+	}
+
+	logs, _ := db.GetLogs(epic.ID)
+	found := false
+	for _, l := range logs {
+		if strings.Contains(l.Message, "Split from "+task.ID) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected split to be logged on the epic")
+	}
+}
+
+func TestSplitItem_Rejects(t *testing.T) {
+	db := setupTestDB(t)
+
+	epic := createTestEpic(t, db, "Already epic", "test")
+	child := []*model.Item{newSplitItem(model.ItemTypeTask, "Child")}
+	if err := db.SplitItem(epic.ID, newSplitItem(model.ItemTypeEpic, "x"), child); err == nil || !strings.Contains(err.Error(), "already") {
+		t.Errorf("expected error splitting an epic, got %v", err)
+	}
+
+	task := createTestItem(t, db, "Task")
+	if err := db.SplitItem(task.ID, newSplitItem(model.ItemTypeEpic, "x"), nil); err == nil {
+		t.Error("expected error splitting without children")
+	}
+	if _, err := db.GetItem(task.ID); err != nil {
+		t.Errorf("task should be untouched after a rejected split: %v", err)
+	}
+}
+
+func TestSplitItem_KeepsWorkInProgress(t *testing.T) {
+	db := setupTestDB(t)
+
+	task := createTestItem(t, db, "Started task")
+	if err := db.UpdateStatus(task.ID, model.StatusInProgress, AgentContext{ID: "agent-1"}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	epic := newSplitItem(model.ItemTypeEpic, "Started task")
+	if err := db.SplitItem(task.ID, epic, []*model.Item{newSplitItem(model.ItemTypeTask, "Part")}); err != nil {
+		t.Fatalf("SplitItem: %v", err)
+	}
+	got, err := db.GetItem(epic.ID)
+	if err != nil {
+		t.Fatalf("GetItem(epic): %v", err)
+	}
+	if got.Status != model.StatusInProgress {
+		t.Errorf("epic status = %s, want in_progress", got.Status)
+	}
+	if got.AgentID == nil || *got.AgentID != "agent-1" {
+		t.Errorf("epic agent = %v, want agent-1", got.AgentID)
+	}
+}
+
+func TestSplitItem_RollsBackOnFailedChild(t *testing.T) {
+	db := setupTestDB(t)
+
+	task := createTestItem(t, db, "Task")
+	existing := createTestItem(t, db, "Existing")
+
+	epic := newSplitItem(model.ItemTypeEpic, "Task")
+	first := newSplitItem(model.ItemTypeTask, "First")
+	clash := newSplitItem(model.ItemTypeTask, "Clash")
+	clash.ID = existing.ID
+	if err := db.SplitItem(task.ID, epic, []*model.Item{first, clash}); err == nil {
+		t.Fatal("expected an error creating a child with a taken ID")
+	}
+
+	if _, err := db.GetItem(task.ID); err != nil {
+		t.Errorf("task should survive a failed split: %v", err)
+	}
+	for _, id := range []string{epic.ID, first.ID} {
+		if _, err := db.GetItem(id); err == nil {
+			t.Errorf("%s should not exist after a failed split", id)
+		}
+	}
+}
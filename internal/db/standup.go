@@ -0,0 +1,163 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// historyScanLimit bounds how many history events a report scans.
+const historyScanLimit = 1000
+
+// standupNextLimit is how many ready tasks a standup suggests.
+const standupNextLimit = 3
+
+// StandupReport summarizes an agent's recent and current work.
+type StandupReport struct {
+	AgentID    string
+	Since      time.Time
+	Done       []model.Item     // completed by the agent since Since
+	Worked     []model.Item     // touched by the agent since Since but not done
+	InProgress []model.Item     // currently in progress for the agent
+	Next       []model.Item     // ready tasks to pick up next
+	Unblocked  []model.Item     // open tasks whose last blocker completed since Since
+	Blocked    []StandupBlocker // the agent's work that can't move
+	LogCounts  map[string]int   // item ID -> logs added since Since
+}
+
+// StandupBlocker is an item the agent is stuck on.
+type StandupBlocker struct {
+	Item    model.Item
+	Waiting []DepStatus // unmet dependencies; empty when marked blocked
+}
+
+// Standup builds a standup report for agentID from history events, logs,
+// and the ready queue. A non-empty project limits every section to it.
+func (db *DB) Standup(agentID, project string, since time.Time) (*StandupReport, error) {
+	report := &StandupReport{AgentID: agentID, Since: since, LogCounts: map[string]int{}}
+
+	events, err := db.GetHistory(HistoryQueryOptions{ActorID: agentID, Since: since, Limit: historyScanLimit})
+	if err != nil {
+		return nil, err
+	}
+	// Events are newest first; walk oldest first so order follows the day
+	seen := make(map[string]bool)
+	markedBlocked := make(map[string]bool)
+	var touched []string
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.EventType == EventTypeStatusChanged && e.Changes["new"] == string(model.StatusBlocked) {
+			markedBlocked[e.ItemID] = true
+		}
+		if e.EventType == EventTypeCreated {
+			continue // filing a task isn't working on it
+		}
+		if !seen[e.ItemID] {
+			seen[e.ItemID] = true
+			touched = append(touched, e.ItemID)
+		}
+	}
+
+	inProgress, err := db.InProgressItemsByAgent(agentID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]bool)
+	for _, item := range inProgress {
+		if project != "" && item.Project != project {
+			continue
+		}
+		current[item.ID] = true
+		report.InProgress = append(report.InProgress, item)
+	}
+
+	for _, id := range touched {
+		item, err := db.GetItem(id)
+		if err != nil {
+			continue // deleted since
+		}
+		if project != "" && item.Project != project {
+			continue
+		}
+		switch {
+		case item.Status == model.StatusDone:
+			report.Done = append(report.Done, *item)
+		case item.Status == model.StatusBlocked && markedBlocked[id]:
+			report.Blocked = append(report.Blocked, StandupBlocker{Item: *item})
+		case !current[id] && item.Status != model.StatusCanceled:
+			report.Worked = append(report.Worked, *item)
+		}
+	}
+
+	for _, item := range report.InProgress {
+		deps, err := db.GetDepStatuses(item.ID)
+		if err != nil {
+			return nil, err
+		}
+		var waiting []DepStatus
+		for _, dep := range deps {
+			if dep.Status != string(model.StatusDone) {
+				waiting = append(waiting, dep)
+			}
+		}
+		if len(waiting) > 0 {
+			report.Blocked = append(report.Blocked, StandupBlocker{Item: item, Waiting: waiting})
+		}
+	}
+
+	ready, err := db.ReadyItems(project)
+	if err != nil {
+		return nil, err
+	}
+	if len(ready) > standupNextLimit {
+		ready = ready[:standupNextLimit]
+	}
+	report.Next = ready
+
+	if report.Unblocked, err = db.NewlyUnblocked(project, since); err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, list := range [][]model.Item{report.Done, report.Worked, report.InProgress} {
+		for _, item := range list {
+			ids = append(ids, item.ID)
+		}
+	}
+	if err := db.countLogsSince(ids, since, report.LogCounts); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// countLogsSince adds the number of logs written since a time for each item.
+func (db *DB) countLogsSince(ids []string, since time.Time, counts map[string]int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []any{sqlTime(since)}
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT item_id, COUNT(*) FROM logs
+		WHERE created_at >= ? AND item_id IN (%s)
+		GROUP BY item_id`, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return fmt.Errorf("failed to count logs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return fmt.Errorf("failed to scan log count: %w", err)
+		}
+		counts[id] = n
+	}
+	return rows.Err()
+}
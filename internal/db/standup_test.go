@@ -0,0 +1,87 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestStandup(t *testing.T) {
+	db := setupTestDB(t)
+	t.Setenv("AGENT_ID", "agent-a")
+	agent := AgentContext{ID: "agent-a"}
+	since := time.Now().Add(-time.Hour)
+
+	finished := createTestItem(t, db, "Finished")
+	working := createTestItem(t, db, "Working")
+	waiting := createTestItem(t, db, "Waiting")
+	stuck := createTestItem(t, db, "Stuck")
+	dep := createTestItem(t, db, "Dependency")
+	nextUp := createTestItem(t, db, "Next")
+
+	if err := db.AddDep(waiting.ID, dep.ID); err != nil {
+		t.Fatalf("AddDep failed: %v", err)
+	}
+	for _, id := range []string{finished.ID, working.ID, waiting.ID, stuck.ID} {
+		if err := db.UpdateStatus(id, model.StatusInProgress, agent, true); err != nil {
+			t.Fatalf("UpdateStatus failed: %v", err)
+		}
+	}
+	if err := db.AddLog(working.ID, "halfway"); err != nil {
+		t.Fatalf("AddLog failed: %v", err)
+	}
+	if err := db.CompleteItem(finished.ID, "shipped", agent); err != nil {
+		t.Fatalf("CompleteItem failed: %v", err)
+	}
+	if err := db.UpdateStatus(stuck.ID, model.StatusBlocked, agent, true); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	// Another agent's activity must not show up
+	t.Setenv("AGENT_ID", "agent-b")
+	other := createTestItem(t, db, "Other")
+	if err := db.UpdateStatus(other.ID, model.StatusInProgress, AgentContext{ID: "agent-b"}, true); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	report, err := db.Standup("agent-a", "", since)
+	if err != nil {
+		t.Fatalf("Standup failed: %v", err)
+	}
+
+	ids := func(items []model.Item) map[string]bool {
+		m := make(map[string]bool)
+		for _, item := range items {
+			m[item.ID] = true
+		}
+		return m
+	}
+	if done := ids(report.Done); len(done) != 1 || !done[finished.ID] {
+		t.Errorf("Done = %v, want [%s]", report.Done, finished.ID)
+	}
+	if inProg := ids(report.InProgress); len(inProg) != 2 || !inProg[working.ID] || !inProg[waiting.ID] {
+		t.Errorf("InProgress = %v, want working and waiting", report.InProgress)
+	}
+	if report.LogCounts[working.ID] != 1 {
+		t.Errorf("LogCounts[working] = %d, want 1", report.LogCounts[working.ID])
+	}
+	blocked := map[string]int{}
+	for _, b := range report.Blocked {
+		blocked[b.Item.ID] = len(b.Waiting)
+	}
+	if n, ok := blocked[stuck.ID]; !ok || n != 0 {
+		t.Errorf("stuck should be listed as marked blocked: %+v", report.Blocked)
+	}
+	if n, ok := blocked[waiting.ID]; !ok || n != 1 {
+		t.Errorf("waiting should be listed with one unmet dependency: %+v", report.Blocked)
+	}
+	if next := ids(report.Next); !next[dep.ID] || !next[nextUp.ID] {
+		t.Errorf("Next = %v, want ready tasks", report.Next)
+	}
+	for _, item := range append(report.Worked, report.InProgress...) {
+		if item.ID == other.ID {
+			t.Error("another agent's task appeared in the standup")
+		}
+	}
+}
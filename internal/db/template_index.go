@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TemplateIndexEntry is a parsed template file remembered across commands,
+// valid while the file's modification time and size are unchanged.
+type TemplateIndexEntry struct {
+	Path    string
+	ModTime time.Time
+	Size    int64
+	Hash    string // sha256 of the file contents
+	Data    string // the parsed template, as JSON
+}
+
+// GetTemplateIndex returns the index entry for a template file, or nil when
+// the file has not been indexed.
+func (db *DB) GetTemplateIndex(path string) (*TemplateIndexEntry, error) {
+	entry := TemplateIndexEntry{Path: path}
+	var modTime int64
+	err := db.QueryRow(`SELECT mod_time, size, hash, data FROM template_index WHERE path = ?`, path).
+		Scan(&modTime, &entry.Size, &entry.Hash, &entry.Data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template index: %w", err)
+	}
+	entry.ModTime = time.Unix(0, modTime)
+	return &entry, nil
+}
+
+// PutTemplateIndex records or replaces the index entry for a template file.
+func (db *DB) PutTemplateIndex(entry TemplateIndexEntry) error {
+	_, err := db.ExecRetry(`INSERT INTO template_index (path, mod_time, size, hash, data, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET mod_time = excluded.mod_time, size = excluded.size,
+			hash = excluded.hash, data = excluded.data, updated_at = excluded.updated_at`,
+		entry.Path, entry.ModTime.UnixNano(), entry.Size, entry.Hash, entry.Data, sqlTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to update template index: %w", err)
+	}
+	return nil
+}
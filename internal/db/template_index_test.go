@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplateIndex(t *testing.T) {
+	db := setupTestDB(t)
+
+	if entry, err := db.GetTemplateIndex("/tpl/deploy.yaml"); err != nil || entry != nil {
+		t.Fatalf("GetTemplateIndex on empty index = %v, %v; want nil", entry, err)
+	}
+
+	modTime := time.Date(2026, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	entry := TemplateIndexEntry{Path: "/tpl/deploy.yaml", ModTime: modTime, Size: 42, Hash: "abc", Data: `{"Title":"Deploy"}`}
+	if err := db.PutTemplateIndex(entry); err != nil {
+		t.Fatalf("PutTemplateIndex: %v", err)
+	}
+	entry.Size, entry.Hash = 43, "def"
+	if err := db.PutTemplateIndex(entry); err != nil {
+		t.Fatalf("PutTemplateIndex (replace): %v", err)
+	}
+
+	got, err := db.GetTemplateIndex("/tpl/deploy.yaml")
+	if err != nil || got == nil {
+		t.Fatalf("GetTemplateIndex = %v, %v", got, err)
+	}
+	if !got.ModTime.Equal(modTime) || got.Size != 43 || got.Hash != "def" || got.Data != entry.Data {
+		t.Errorf("GetTemplateIndex = %+v, want %+v", got, entry)
+	}
+}
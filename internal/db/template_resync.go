@@ -0,0 +1,55 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// TemplatedItems returns open (not done or canceled) items that were
+// instantiated from a template, optionally limited to one project.
+func (db *DB) TemplatedItems(project string) ([]model.Item, error) {
+	query := fmt.Sprintf(`SELECT %s FROM items
+		WHERE template_id IS NOT NULL AND template_id != ''
+		  AND status NOT IN ('done', 'canceled')`, itemSelectColumns)
+	var args []any
+	if project != "" {
+		query += ` AND project = ?`
+		args = append(args, project)
+	}
+	query += ` ORDER BY created_at ASC`
+	return db.queryItems(query, args...)
+}
+
+// ResyncTemplate stores a freshly rendered title and description for a
+// templated item and records the template hash they were rendered from.
+func (db *DB) ResyncTemplate(id, title, description, hash string) error {
+	old, err := db.GetItem(id)
+	if err != nil {
+		return err
+	}
+	if old.TemplateID == "" {
+		return fmt.Errorf("%s was not created from a template", id)
+	}
+	if old.Description != description {
+		db.saveDescriptionVersion(id, old.Description)
+	}
+
+	_, err = db.Exec(`
+		UPDATE items
+		SET title = ?, description = ?, template_hash = ?, updated_at = ?
+		WHERE id = ?`,
+		title, description, hash, sqlTime(time.Now()), id)
+	if err != nil {
+		return fmt.Errorf("failed to resync %s: %w", id, err)
+	}
+
+	_ = db.RecordHistory(id, EventTypeTemplateResynced, map[string]any{
+		"template": old.TemplateID,
+		"old_hash": old.TemplateHash,
+		"new_hash": hash,
+	})
+	_ = db.AddLog(id, fmt.Sprintf("Re-synced with updated template %s", old.TemplateID))
+	return nil
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestResyncTemplate(t *testing.T) {
+	db := setupTestDB(t)
+
+	plain := createTestItem(t, db, "Plain")
+	templated := createTestItem(t, db, "Old title")
+	done := createTestItem(t, db, "Done templated")
+	for _, id := range []string{templated.ID, done.ID} {
+		if _, err := db.Exec(`UPDATE items SET template_id = 'tdd', template_hash = 'old' WHERE id = ?`, id); err != nil {
+			t.Fatalf("failed to mark templated: %v", err)
+		}
+	}
+	if err := db.UpdateStatus(done.ID, model.StatusDone, AgentContext{}, true); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+
+	items, err := db.TemplatedItems("test")
+	if err != nil {
+		t.Fatalf("TemplatedItems failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != templated.ID {
+		t.Fatalf("expected only %s, got %+v", templated.ID, items)
+	}
+
+	if err := db.ResyncTemplate(templated.ID, "New title", "New description", "new"); err != nil {
+		t.Fatalf("ResyncTemplate failed: %v", err)
+	}
+	got, err := db.GetItem(templated.ID)
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	if got.Title != "New title" || got.Description != "New description" || got.TemplateHash != "new" {
+		t.Errorf("unexpected item after resync: title=%q desc=%q hash=%q", got.Title, got.Description, got.TemplateHash)
+	}
+	logs, _ := db.GetLogs(templated.ID)
+	if len(logs) == 0 {
+		t.Error("expected a resync log entry")
+	}
+
+	if err := db.ResyncTemplate(plain.ID, "x", "y", "z"); err == nil {
+		t.Error("expected error resyncing a non-templated item")
+	}
+}
@@ -0,0 +1,16 @@
+package db
+
+import "time"
+
+// PhaseTimer, when set, is told how long each timed phase of a command took:
+// "db open", "migration", and "backup". 'tpg --timings' uses it.
+var PhaseTimer func(phase string, d time.Duration)
+
+// timePhase starts timing phase; call the returned function when it ends.
+func timePhase(phase string) func() {
+	if PhaseTimer == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { PhaseTimer(phase, time.Since(start)) }
+}
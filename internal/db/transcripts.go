@@ -0,0 +1,127 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EventTypeTranscriptAdded records a transcript attached to an item.
+const EventTypeTranscriptAdded = "transcript_added"
+
+// AddTranscript attaches text, such as the full output of a test run, to an
+// item. The text is stored gzip-compressed in its own table so it does not
+// bloat descriptions or logs. Returns the new transcript's ID.
+func (db *DB) AddTranscript(itemID, name, text string) (int64, error) {
+	if _, err := db.GetItem(itemID); err != nil {
+		return 0, err
+	}
+
+	content, err := compressTranscript(text)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := db.Exec(`
+		INSERT INTO transcripts (item_id, name, size, content) VALUES (?, ?, ?, ?)`,
+		itemID, name, len(text), content)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add transcript: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add transcript: %w", err)
+	}
+
+	_ = db.RecordHistory(itemID, EventTypeTranscriptAdded, map[string]any{
+		"transcript": id,
+		"name":       name,
+		"size":       len(text),
+	})
+	return id, nil
+}
+
+// ListTranscripts returns an item's transcripts, oldest first, without their
+// text.
+func (db *DB) ListTranscripts(itemID string) ([]model.Transcript, error) {
+	rows, err := db.Query(`
+		SELECT id, item_id, name, size, length(content), created_at
+		FROM transcripts WHERE item_id = ?
+		ORDER BY created_at ASC, id ASC`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transcripts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var transcripts []model.Transcript
+	for rows.Next() {
+		var t model.Transcript
+		if err := rows.Scan(&t.ID, &t.ItemID, &t.Name, &t.Size, &t.StoredSize, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript: %w", err)
+		}
+		transcripts = append(transcripts, t)
+	}
+	return transcripts, rows.Err()
+}
+
+// CountTranscripts returns how many transcripts are attached to an item.
+func (db *DB) CountTranscripts(itemID string) (int, error) {
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM transcripts WHERE item_id = ?`, itemID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count transcripts: %w", err)
+	}
+	return n, nil
+}
+
+// GetTranscript returns a transcript with its decompressed text.
+func (db *DB) GetTranscript(id int64) (*model.Transcript, error) {
+	var t model.Transcript
+	var content []byte
+	err := db.QueryRow(`
+		SELECT id, item_id, name, size, content, created_at
+		FROM transcripts WHERE id = ?`, id).
+		Scan(&t.ID, &t.ItemID, &t.Name, &t.Size, &content, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("transcript not found: #%d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript: %w", err)
+	}
+	t.StoredSize = int64(len(content))
+
+	if t.Text, err = decompressTranscript(content); err != nil {
+		return nil, fmt.Errorf("failed to decompress transcript #%d: %w", id, err)
+	}
+	return &t, nil
+}
+
+// compressTranscript gzips text for the transcripts.content column.
+func compressTranscript(text string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(text)); err != nil {
+		return nil, fmt.Errorf("failed to compress transcript: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress transcript: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressTranscript reverses compressTranscript.
+func decompressTranscript(content []byte) (string, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	text, err := io.ReadAll(zr)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestTranscripts(t *testing.T) {
+	db := setupTestDB(t)
+	createCancelTestItem(t, db, "ts-out", model.ItemTypeTask, "", model.StatusInProgress)
+
+	output := strings.Repeat("=== RUN TestSomething\n--- PASS: TestSomething\n", 500)
+	id, err := db.AddTranscript("ts-out", "go test", output)
+	if err != nil {
+		t.Fatalf("AddTranscript: %v", err)
+	}
+	if _, err := db.AddTranscript("ts-missing", "x", "y"); err == nil {
+		t.Error("AddTranscript on a missing item succeeded, want an error")
+	}
+
+	list, err := db.ListTranscripts("ts-out")
+	if err != nil {
+		t.Fatalf("ListTranscripts: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != id || list[0].Name != "go test" {
+		t.Fatalf("ListTranscripts = %+v, want the one transcript", list)
+	}
+	if list[0].Size != int64(len(output)) || list[0].StoredSize >= list[0].Size {
+		t.Errorf("size = %d, stored = %d; want %d stored compressed", list[0].Size, list[0].StoredSize, len(output))
+	}
+	if list[0].Text != "" {
+		t.Error("ListTranscripts returned text, want metadata only")
+	}
+
+	got, err := db.GetTranscript(id)
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	if got.Text != output {
+		t.Errorf("GetTranscript text differs from what was added (%d bytes vs %d)", len(got.Text), len(output))
+	}
+	if _, err := db.GetTranscript(id + 1); err == nil {
+		t.Error("GetTranscript of a missing ID succeeded, want an error")
+	}
+
+	if err := db.DeleteItem("ts-out", false, false); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if n, _ := db.CountTranscripts("ts-out"); n != 0 {
+		t.Errorf("%d transcripts left after deleting the item, want 0", n)
+	}
+}
+
+func TestRedactItemScrubsTranscripts(t *testing.T) {
+	db := setupTestDB(t)
+	item := createTestItem(t, db, "Leaky")
+	id, err := db.AddTranscript(item.ID, "curl", "Authorization: Bearer sk-abc123\nHTTP/1.1 200 OK")
+	if err != nil {
+		t.Fatalf("AddTranscript: %v", err)
+	}
+
+	result, err := db.RedactItem(item.ID, []*regexp.Regexp{regexp.MustCompile(`sk-[a-z0-9]+`)}, false)
+	if err != nil {
+		t.Fatalf("RedactItem: %v", err)
+	}
+	if result.Transcripts != 1 {
+		t.Errorf("redacted %d transcript matches, want 1", result.Transcripts)
+	}
+	got, err := db.GetTranscript(id)
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	want := "Authorization: Bearer [REDACTED]\nHTTP/1.1 200 OK"
+	if got.Text != want || got.Size != int64(len(want)) {
+		t.Errorf("transcript = %q (size %d), want %q", got.Text, got.Size, want)
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// Trend holds per-day task counts for the days ending today, oldest first.
+type Trend struct {
+	Days      []time.Time // local midnight of each day
+	Completed []int       // tasks marked done that day
+	Open      []int       // tasks not yet done or canceled at the end of that day
+}
+
+// DailyTrend returns completion throughput and the open task count for the
+// last days days (including today) in the local time zone. Epics are left
+// out since they close automatically with their children.
+func (db *DB) DailyTrend(project string, days int, now time.Time) (*Trend, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("days must be at least 1, got %d", days)
+	}
+	query := fmt.Sprintf(`SELECT %s FROM items WHERE type != 'epic'`, itemSelectColumns)
+	var args []any
+	if project != "" {
+		query += ` AND project = ?`
+		args = append(args, project)
+	}
+	items, err := db.queryItems(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	today := now.Local()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	trend := &Trend{
+		Days:      make([]time.Time, days),
+		Completed: make([]int, days),
+		Open:      make([]int, days),
+	}
+	for i := range trend.Days {
+		trend.Days[i] = today.AddDate(0, 0, i-days+1)
+	}
+
+	for _, item := range items {
+		// Items closed before closed_at was tracked fall back to updated_at
+		var closed *time.Time
+		if item.Status == model.StatusDone || item.Status == model.StatusCanceled {
+			closed = &item.UpdatedAt
+			if item.ClosedAt != nil {
+				closed = item.ClosedAt
+			}
+		}
+		for i, day := range trend.Days {
+			end := day.AddDate(0, 0, 1)
+			if item.CreatedAt.Before(end) && (closed == nil || !closed.Before(end)) {
+				trend.Open[i]++
+			}
+			if item.Status == model.StatusDone && !closed.Before(day) && closed.Before(end) {
+				trend.Completed[i]++
+			}
+		}
+	}
+	return trend, nil
+}
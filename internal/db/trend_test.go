@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyTrend(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	daysAgo := func(n int) string {
+		return sqlTime(now.AddDate(0, 0, -n))
+	}
+
+	// Created 4 days ago, done 2 days ago
+	done := createTestItem(t, db, "Done")
+	// Created 3 days ago, still open
+	open := createTestItem(t, db, "Open")
+	// Created 3 days ago, canceled yesterday (closes but is not throughput)
+	canceled := createTestItem(t, db, "Canceled")
+	epic := createTestEpic(t, db, "Epic", "test")
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE items SET created_at = ?, status = 'done', closed_at = ? WHERE id = ?`, []any{daysAgo(4), daysAgo(2), done.ID}},
+		{`UPDATE items SET created_at = ? WHERE id = ?`, []any{daysAgo(3), open.ID}},
+		{`UPDATE items SET created_at = ?, status = 'canceled', closed_at = ? WHERE id = ?`, []any{daysAgo(3), daysAgo(1), canceled.ID}},
+		{`UPDATE items SET created_at = ? WHERE id = ?`, []any{daysAgo(4), epic.ID}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+
+	trend, err := db.DailyTrend("test", 5, now)
+	if err != nil {
+		t.Fatalf("DailyTrend: %v", err)
+	}
+	wantCompleted := []int{0, 0, 1, 0, 0}
+	wantOpen := []int{1, 3, 2, 1, 1}
+	for i := range trend.Days {
+		if trend.Completed[i] != wantCompleted[i] || trend.Open[i] != wantOpen[i] {
+			t.Errorf("day %d: completed %d open %d, want %d and %d",
+				i, trend.Completed[i], trend.Open[i], wantCompleted[i], wantOpen[i])
+		}
+	}
+	if last := trend.Days[4]; last.Day() != now.Day() {
+		t.Errorf("expected the last day to be today, got %s", last)
+	}
+
+	if _, err := db.DailyTrend("test", 0, now); err == nil {
+		t.Error("expected an error for zero days")
+	}
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// EventTypeUnblocked is recorded on an item when its last unmet dependency
+// completes. The changes hold the completed item under "by".
+const EventTypeUnblocked = "unblocked"
+
+// NewlyUnblockedWindow is how far back 'tpg status' looks for items that
+// became unblocked.
+const NewlyUnblockedWindow = 24 * time.Hour
+
+// notifyUnblocked logs "unblocked by <id>" on every unfinished item that
+// depends on the just-completed item and has no other unmet dependencies.
+// Failures are non-fatal; the completion itself has already happened.
+func (db *DB) notifyUnblocked(doneID string) {
+	rows, err := db.Query(`
+		SELECT i.id FROM items i
+		JOIN deps d ON d.item_id = i.id
+		WHERE d.depends_on = ?
+		  AND i.status NOT IN ('done', 'canceled')
+		  AND NOT EXISTS (
+		      SELECT 1 FROM deps d2 JOIN items dep ON dep.id = d2.depends_on
+		      WHERE d2.item_id = i.id AND dep.status != 'done'
+		  )
+		ORDER BY i.id`, doneID)
+	if err != nil {
+		return
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if rows.Scan(&id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	_ = rows.Close()
+
+	for _, id := range ids {
+		_ = db.AddLog(id, "unblocked by "+doneID)
+		_ = db.RecordHistory(id, EventTypeUnblocked, map[string]any{"by": doneID})
+	}
+}
+
+// NewlyUnblocked returns open items that became unblocked at or after since
+// and are still waiting to be picked up, most recently unblocked first.
+func (db *DB) NewlyUnblocked(project string, since time.Time) ([]model.Item, error) {
+	query := fmt.Sprintf(`SELECT %s FROM items
+		JOIN (
+		    SELECT item_id, MAX(created_at) AS unblocked_at FROM history
+		    WHERE event_type = ? AND created_at >= ?
+		    GROUP BY item_id
+		) h ON h.item_id = items.id
+		WHERE items.status = 'open'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM deps d JOIN items dep ON dep.id = d.depends_on
+		      WHERE d.item_id = items.id AND dep.status != 'done'
+		  )`, itemSelectColumns)
+	args := []any{EventTypeUnblocked, sqlTime(since)}
+	if project != "" {
+		query += " AND items.project = ?"
+		args = append(args, project)
+	}
+	query += " ORDER BY h.unblocked_at DESC, items.id"
+	return db.queryItems(query, args...)
+}
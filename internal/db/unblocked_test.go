@@ -0,0 +1,84 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestNotifyUnblocked(t *testing.T) {
+	db := setupTestDB(t)
+
+	first := createTestItem(t, db, "First blocker")
+	second := createTestItem(t, db, "Second blocker")
+	waiting := createTestItem(t, db, "Waits on both")
+	single := createTestItem(t, db, "Waits on first")
+	for _, dep := range [][2]string{
+		{waiting.ID, first.ID},
+		{waiting.ID, second.ID},
+		{single.ID, first.ID},
+	} {
+		if err := db.AddDep(dep[0], dep[1]); err != nil {
+			t.Fatalf("AddDep: %v", err)
+		}
+	}
+
+	unblockLogs := func(id string) []string {
+		t.Helper()
+		logs, err := db.GetLogs(id)
+		if err != nil {
+			t.Fatalf("GetLogs: %v", err)
+		}
+		var msgs []string
+		for _, l := range logs {
+			msgs = append(msgs, l.Message)
+		}
+		return msgs
+	}
+
+	if err := db.CompleteItem(first.ID, "", AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+	if got := unblockLogs(single.ID); len(got) != 1 || got[0] != "unblocked by "+first.ID {
+		t.Errorf("expected single to be unblocked by %s, got %v", first.ID, got)
+	}
+	if got := unblockLogs(waiting.ID); len(got) != 0 {
+		t.Errorf("expected no log while a blocker remains, got %v", got)
+	}
+
+	if err := db.CompleteItem(second.ID, "", AgentContext{}); err != nil {
+		t.Fatalf("CompleteItem: %v", err)
+	}
+	if got := unblockLogs(waiting.ID); len(got) != 1 || got[0] != "unblocked by "+second.ID {
+		t.Errorf("expected waiting to be unblocked by %s, got %v", second.ID, got)
+	}
+
+	unblocked, err := db.NewlyUnblocked("test", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("NewlyUnblocked: %v", err)
+	}
+	if len(unblocked) != 2 {
+		t.Fatalf("expected 2 newly unblocked items, got %v", ids(unblocked))
+	}
+
+	// Starting work removes an item from the list
+	if err := db.UpdateStatus(single.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	unblocked, err = db.NewlyUnblocked("test", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("NewlyUnblocked: %v", err)
+	}
+	if len(unblocked) != 1 || unblocked[0].ID != waiting.ID {
+		t.Errorf("expected only %s, got %v", waiting.ID, ids(unblocked))
+	}
+
+	report, err := db.ProjectStatus("test")
+	if err != nil {
+		t.Fatalf("ProjectStatus: %v", err)
+	}
+	if len(report.NewlyUnblocked) != 1 || report.NewlyUnblocked[0].ID != waiting.ID {
+		t.Errorf("expected status to list %s as newly unblocked, got %v", waiting.ID, ids(report.NewlyUnblocked))
+	}
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// CurrentWatcher identifies who is watching items: "agent:<AGENT_ID>" for
+// agents, "session:<TPG_SESSION>" when that is set, and otherwise
+// "user:<login>", so a person's watches carry over between shells.
+func CurrentWatcher() string {
+	if id := os.Getenv("AGENT_ID"); id != "" {
+		return "agent:" + id
+	}
+	if s := os.Getenv("TPG_SESSION"); s != "" {
+		return "session:" + s
+	}
+	for _, key := range []string{"USER", "USERNAME", "LOGNAME"} {
+		if u := os.Getenv(key); u != "" {
+			return "user:" + u
+		}
+	}
+	return "user:unknown"
+}
+
+// WatchChange is one change to a watched item: a history event, or a log
+// entry when Log is set.
+type WatchChange struct {
+	At      time.Time
+	Event   string         // history event type, or "log"
+	Changes map[string]any // the history event's changes
+	Log     *model.Log
+}
+
+// InboxEntry is a watched item with the changes made since its watcher last
+// checked.
+type InboxEntry struct {
+	Item    model.Item
+	Changes []WatchChange // oldest first
+}
+
+// Watch adds itemID to watcher's watched items. Only changes made from now on
+// are reported. It returns false when the item was already watched.
+func (db *DB) Watch(watcher, itemID string) (bool, error) {
+	if _, err := db.GetItem(itemID); err != nil {
+		return false, err
+	}
+	historySeen, logSeen, err := db.latestChangeIDs()
+	if err != nil {
+		return false, err
+	}
+	res, err := db.Exec(`
+		INSERT INTO watches (watcher, item_id, history_seen, log_seen, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(watcher, item_id) DO NOTHING`,
+		watcher, itemID, historySeen, logSeen, sqlTime(time.Now()))
+	if err != nil {
+		return false, fmt.Errorf("failed to watch %s: %w", itemID, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Unwatch removes itemID from watcher's watched items. It returns false when
+// the item wasn't watched.
+func (db *DB) Unwatch(watcher, itemID string) (bool, error) {
+	res, err := db.Exec(`DELETE FROM watches WHERE watcher = ? AND item_id = ?`, watcher, itemID)
+	if err != nil {
+		return false, fmt.Errorf("failed to unwatch %s: %w", itemID, err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Inbox is what a watcher's watched items look like at one moment.
+type Inbox struct {
+	Entries []InboxEntry
+	// The newest history event and log entry the inbox covers.
+	historyID, logID int64
+}
+
+// Inbox returns watcher's watched items that changed since they were last
+// checked, each with its changes, most recently changed first. With all set,
+// unchanged watched items are included too, after the changed ones.
+func (db *DB) Inbox(watcher string, all bool) (*Inbox, error) {
+	inbox := &Inbox{}
+	var err error
+	if inbox.historyID, inbox.logID, err = db.latestChangeIDs(); err != nil {
+		return nil, err
+	}
+	type watch struct {
+		itemID               string
+		historySeen, logSeen int64
+	}
+	rows, err := db.Query(`SELECT item_id, history_seen, log_seen FROM watches WHERE watcher = ? ORDER BY created_at, item_id`, watcher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watches: %w", err)
+	}
+	var watches []watch
+	for rows.Next() {
+		var w watch
+		if err := rows.Scan(&w.itemID, &w.historySeen, &w.logSeen); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		watches = append(watches, w)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var changed, unchanged []InboxEntry
+	for _, w := range watches {
+		item, err := db.GetItem(w.itemID)
+		if err != nil {
+			return nil, err
+		}
+		entry := InboxEntry{Item: *item}
+		history, err := db.GetHistory(HistoryQueryOptions{ItemID: w.itemID, Limit: -1})
+		if err != nil {
+			return nil, err
+		}
+		for i := len(history) - 1; i >= 0; i-- {
+			if e := history[i]; e.ID > w.historySeen && e.ID <= inbox.historyID {
+				entry.Changes = append(entry.Changes, WatchChange{At: e.CreatedAt, Event: e.EventType, Changes: e.Changes})
+			}
+		}
+		logs, err := db.GetLogs(w.itemID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range logs {
+			if logs[i].ID > w.logSeen && logs[i].ID <= inbox.logID {
+				entry.Changes = append(entry.Changes, WatchChange{At: logs[i].CreatedAt, Event: "log", Log: &logs[i]})
+			}
+		}
+		if len(entry.Changes) == 0 {
+			if all {
+				unchanged = append(unchanged, entry)
+			}
+			continue
+		}
+		sortWatchChanges(entry.Changes)
+		changed = append(changed, entry)
+	}
+	sortInbox(changed)
+	inbox.Entries = append(changed, unchanged...)
+	return inbox, nil
+}
+
+// MarkInboxChecked records that watcher has seen every change in inbox, so
+// the next inbox shows only changes made after it was read.
+func (db *DB) MarkInboxChecked(watcher string, inbox *Inbox) error {
+	if _, err := db.Exec(`UPDATE watches SET history_seen = MAX(history_seen, ?), log_seen = MAX(log_seen, ?) WHERE watcher = ?`,
+		inbox.historyID, inbox.logID, watcher); err != nil {
+		return fmt.Errorf("failed to mark inbox checked: %w", err)
+	}
+	return nil
+}
+
+// latestChangeIDs returns the newest history event and log entry IDs.
+// Watches compare against these rather than timestamps, which are only
+// stored to the second.
+func (db *DB) latestChangeIDs() (historyID, logID int64, err error) {
+	err = db.QueryRow(`SELECT COALESCE((SELECT MAX(id) FROM history), 0), COALESCE((SELECT MAX(id) FROM logs), 0)`).
+		Scan(&historyID, &logID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read latest changes: %w", err)
+	}
+	return historyID, logID, nil
+}
+
+func sortWatchChanges(changes []WatchChange) {
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].At.Before(changes[j].At) })
+}
+
+// sortInbox orders entries by their latest change, newest first.
+func sortInbox(entries []InboxEntry) {
+	latest := func(e InboxEntry) time.Time { return e.Changes[len(e.Changes)-1].At }
+	sort.SliceStable(entries, func(i, j int) bool { return latest(entries[i]).After(latest(entries[j])) })
+}
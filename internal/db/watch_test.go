@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/taxilian/tpg/internal/model"
+)
+
+func TestInbox(t *testing.T) {
+	db := setupTestDB(t)
+
+	watched := createTestItem(t, db, "Watched")
+	other := createTestItem(t, db, "Not watched")
+	if err := db.AddLog(watched.ID, "before watching"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+
+	added, err := db.Watch("agent:a", watched.ID)
+	if err != nil || !added {
+		t.Fatalf("Watch = %v, %v", added, err)
+	}
+	if added, _ := db.Watch("agent:a", watched.ID); added {
+		t.Error("watching twice should report already watching")
+	}
+
+	inbox, err := db.Inbox("agent:a", false)
+	if err != nil {
+		t.Fatalf("Inbox: %v", err)
+	}
+	if len(inbox.Entries) != 0 {
+		t.Fatalf("changes made before watching were reported: %+v", inbox.Entries)
+	}
+
+	if err := db.UpdateStatus(watched.ID, model.StatusInProgress, AgentContext{}, false); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	if err := db.AddLog(watched.ID, "found the cause"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	if err := db.AddLog(other.ID, "elsewhere"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+
+	inbox, err = db.Inbox("agent:a", false)
+	if err != nil {
+		t.Fatalf("Inbox: %v", err)
+	}
+	if len(inbox.Entries) != 1 || inbox.Entries[0].Item.ID != watched.ID {
+		t.Fatalf("Inbox = %+v, want only the watched item", inbox.Entries)
+	}
+	changes := inbox.Entries[0].Changes
+	if len(changes) != 2 || changes[0].Event != EventTypeStatusChanged || changes[1].Log == nil || changes[1].Log.Message != "found the cause" {
+		t.Errorf("changes = %+v, want the status change then the log", changes)
+	}
+
+	// Another watcher's view is separate.
+	if inbox, _ := db.Inbox("agent:b", true); len(inbox.Entries) != 0 {
+		t.Errorf("agent:b inbox = %+v, want empty", inbox.Entries)
+	}
+
+	if err := db.MarkInboxChecked("agent:a", inbox); err != nil {
+		t.Fatalf("MarkInboxChecked: %v", err)
+	}
+	inbox, _ = db.Inbox("agent:a", false)
+	if len(inbox.Entries) != 0 {
+		t.Errorf("changes reported again after checking: %+v", inbox.Entries)
+	}
+	inbox, _ = db.Inbox("agent:a", true)
+	if len(inbox.Entries) != 1 || len(inbox.Entries[0].Changes) != 0 {
+		t.Errorf("Inbox(all) = %+v, want the watched item with no changes", inbox.Entries)
+	}
+
+	if removed, err := db.Unwatch("agent:a", watched.ID); err != nil || !removed {
+		t.Fatalf("Unwatch = %v, %v", removed, err)
+	}
+	if inbox, _ := db.Inbox("agent:a", true); len(inbox.Entries) != 0 {
+		t.Errorf("unwatched item still listed: %+v", inbox.Entries)
+	}
+}
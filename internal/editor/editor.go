@@ -0,0 +1,91 @@
+// Package editor picks the user's text editor and builds the command that
+// opens a file in it.
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Resolve returns the editor to use: $TPG_EDITOR, then $EDITOR, then nvim
+// or nano when installed, then notepad on Windows or vi elsewhere.
+func Resolve() string {
+	for _, env := range []string{"TPG_EDITOR", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	for _, candidate := range []string{"nvim", "nano"} {
+		if _, err := exec.LookPath(candidate); err == nil {
+			return candidate
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// Command returns the program and arguments that open path in editor.
+// editor may carry its own arguments ("code --wait"). On Windows the editor
+// runs through cmd /c, so .bat and .cmd wrappers and PATHEXT lookup work.
+func Command(editor, path string) (string, []string) {
+	return command(runtime.GOOS, editor, path)
+}
+
+func command(goos, editor, path string) (string, []string) {
+	return program(goos, editor, path)
+}
+
+// Program returns the program and arguments that run a command setting such
+// as $PAGER ("less -R"), split and run the same way as an editor.
+func Program(setting string) (string, []string) {
+	return program(runtime.GOOS, setting)
+}
+
+func program(goos, setting string, args ...string) (string, []string) {
+	argv := append(splitEditor(setting), args...)
+	if goos == "windows" {
+		return "cmd", append([]string{"/c"}, argv...)
+	}
+	return argv[0], argv[1:]
+}
+
+// splitEditor splits an editor setting into program and arguments. A
+// setting that names an existing program is kept whole, so paths with
+// spaces ("C:\Program Files\Notepad++\notepad++.exe") need no quoting.
+// Otherwise it is split on spaces, honoring double quotes.
+func splitEditor(editor string) []string {
+	editor = strings.TrimSpace(editor)
+	if _, err := exec.LookPath(editor); err == nil {
+		return []string{editor}
+	}
+	var fields []string
+	var current strings.Builder
+	inQuotes, inField := false, false
+	for _, r := range editor {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	if len(fields) == 0 {
+		return []string{editor}
+	}
+	return fields
+}
@@ -0,0 +1,47 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		goos, editor string
+		wantName     string
+		wantArgs     []string
+	}{
+		{"linux", "vi", "vi", []string{"notes.md"}},
+		{"linux", "code --wait", "code", []string{"--wait", "notes.md"}},
+		{"darwin", `"/Applications/My Editor/edit" -w`, "/Applications/My Editor/edit", []string{"-w", "notes.md"}},
+		{"windows", "notepad", "cmd", []string{"/c", "notepad", "notes.md"}},
+		{"windows", "code --wait", "cmd", []string{"/c", "code", "--wait", "notes.md"}},
+	}
+	for _, tt := range tests {
+		name, args := command(tt.goos, tt.editor, "notes.md")
+		if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("command(%q, %q) = %q %q, want %q %q", tt.goos, tt.editor, name, args, tt.wantName, tt.wantArgs)
+		}
+	}
+}
+
+func TestProgram(t *testing.T) {
+	if name, args := program("linux", "less -R"); name != "less" || !reflect.DeepEqual(args, []string{"-R"}) {
+		t.Errorf(`program("linux", "less -R") = %q %q, want "less" ["-R"]`, name, args)
+	}
+	if name, args := program("windows", "more"); name != "cmd" || !reflect.DeepEqual(args, []string{"/c", "more"}) {
+		t.Errorf(`program("windows", "more") = %q %q, want "cmd" ["/c" "more"]`, name, args)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("TPG_EDITOR", "")
+	t.Setenv("EDITOR", "emacs")
+	if got := Resolve(); got != "emacs" {
+		t.Errorf("Resolve() = %q with EDITOR set, want emacs", got)
+	}
+	t.Setenv("TPG_EDITOR", "hx")
+	if got := Resolve(); got != "hx" {
+		t.Errorf("Resolve() = %q, want $TPG_EDITOR to win", got)
+	}
+}
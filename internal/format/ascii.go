@@ -0,0 +1,127 @@
+package format
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// asciiEnabled is set when output should be plain ASCII. The CLI filters
+// stdout itself; renderers that own the terminal, like the TUI, check
+// ASCIIEnabled.
+var asciiEnabled atomic.Bool
+
+// SetASCIIEnabled turns ASCII-only output on or off.
+func SetASCIIEnabled(on bool) { asciiEnabled.Store(on) }
+
+// ASCIIEnabled reports whether output should be plain ASCII.
+func ASCIIEnabled() bool { return asciiEnabled.Load() }
+
+// asciiReplacer maps the box-drawing characters, symbols, and emoji used in
+// tpg's output to plain ASCII. Characters not listed, such as those in item
+// titles, pass through unchanged.
+var asciiReplacer = strings.NewReplacer(
+	// Tree and rule lines
+	"├──", "|--",
+	"└──", "`--",
+	"│", "|",
+	"├", "|",
+	"└", "`",
+	"─", "-",
+	"╭", "+",
+	"╮", "+",
+	"╰", "+",
+	"╯", "+",
+	"┌", "+",
+	"┐", "+",
+	"┘", "+",
+	// Progress bars and sparklines
+	"█", "#",
+	"▇", "#",
+	"▆", "=",
+	"▅", "=",
+	"▄", "-",
+	"▃", "-",
+	"▂", ".",
+	"▁", "_",
+	"░", ".",
+	// Status icons
+	"✅", "[+]",
+	"✓", "+",
+	"✗", "x",
+	"⛔", "[!]",
+	"⚠", "!",
+	"⊘", "x",
+	"○", "o",
+	"◐", "~",
+	"●", "*",
+	"◎", "?",
+	"◈", "@",
+	"▶", ">",
+	"►", ">",
+	"▼", "v",
+	"↻", "~",
+	"⚡", "!",
+	// Punctuation
+	"→", "->",
+	"—", "--",
+	"•", "*",
+	// Emoji used as section markers
+	"📊", "#",
+	"📋", "#",
+	"🔍", "#",
+	"🔐", "#",
+	"🔗", "#",
+	"📁", "#",
+	"🎉", "*",
+	"🐛", "*",
+	"\ufe0f", "", // emoji presentation selector, e.g. after ⚠
+)
+
+// ASCII replaces the unicode glyphs tpg prints with ASCII equivalents.
+func ASCII(s string) string {
+	return asciiReplacer.Replace(s)
+}
+
+// ASCIIWriter applies ASCII to everything written to it. Writes may split
+// a multi-byte character; the incomplete tail is held until the next write
+// or Close.
+type ASCIIWriter struct {
+	w       io.Writer
+	pending []byte
+}
+
+// NewASCIIWriter returns an ASCIIWriter writing to w.
+func NewASCIIWriter(w io.Writer) *ASCIIWriter {
+	return &ASCIIWriter{w: w}
+}
+
+// Write converts p and writes it to the underlying writer.
+func (a *ASCIIWriter) Write(p []byte) (int, error) {
+	buf := append(a.pending, p...)
+	cut := len(buf)
+	for i := len(buf) - 1; i >= 0 && i >= len(buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			if !utf8.FullRune(buf[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	a.pending = append([]byte(nil), buf[cut:]...)
+	if _, err := io.WriteString(a.w, ASCII(string(buf[:cut]))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes any held bytes. It does not close the underlying writer.
+func (a *ASCIIWriter) Close() error {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	_, err := a.w.Write(a.pending)
+	a.pending = nil
+	return err
+}
@@ -0,0 +1,39 @@
+package format
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestASCII(t *testing.T) {
+	tests := map[string]string{
+		"├── ts-1 ✅ ready":       "|-- ts-1 [+] ready",
+		"│   └── ts-2 ⛔ blocked": "|   `-- ts-2 [!] blocked",
+		"⚠️  Stale":              "!  Stale",
+		"[██████░░░░] 60%":       "[######....] 60%",
+		"a → b — c":              "a -> b -- c",
+		"国际化 stays":              "国际化 stays",
+	}
+	for in, want := range tests {
+		if got := ASCII(in); got != want {
+			t.Errorf("ASCII(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestASCIIWriterSplitRunes(t *testing.T) {
+	var out strings.Builder
+	w := NewASCIIWriter(&out)
+	in := []byte("└── done ✓ 日本")
+	for i := range in {
+		if _, err := w.Write(in[i : i+1]); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got, want := out.String(), "`-- done + 日本"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
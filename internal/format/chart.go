@@ -0,0 +1,35 @@
+package format
+
+import "strings"
+
+// sparkBlocks are the eight bar heights used by Sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a one-line bar chart, one cell per value,
+// e.g. "▁▃▅█▂". Bars are scaled between the smallest and largest value so
+// the shape of a trend stays visible even when the values are all large.
+// A flat series renders at the bottom when zero and mid-height otherwise.
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	top := len(sparkBlocks) - 1
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		switch {
+		case hi == lo && v != 0:
+			level = top / 2
+		case hi != lo:
+			level = ((v-lo)*top + (hi-lo)/2) / (hi - lo)
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
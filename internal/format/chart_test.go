@@ -0,0 +1,24 @@
+package format
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"all zero", []int{0, 0, 0}, "▁▁▁"},
+		{"flat", []int{4, 4}, "▄▄"},
+		{"ramp", []int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{"scaled to range", []int{100, 107, 114}, "▁▅█"},
+		{"spike", []int{0, 0, 9, 0}, "▁▁█▁"},
+	}
+
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("%s: Sparkline(%v) = %q, want %q", tt.name, tt.values, got, tt.want)
+		}
+	}
+}
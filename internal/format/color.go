@@ -0,0 +1,157 @@
+package format
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Color output is off until enabled with SetColorEnabled, so callers that
+// never opt in (tests, JSON encoders, library users) get plain text. The CLI
+// enables it for terminals unless NO_COLOR is set or --no-color is given.
+var (
+	colorMu      sync.RWMutex
+	colorEnabled bool
+	labelColors  = map[string]string{}
+)
+
+// ANSI SGR codes used by the helpers below.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "1"
+	ansiDim    = "2"
+	ansiRed    = "31"
+	ansiGreen  = "32"
+	ansiYellow = "33"
+	ansiBlue   = "34"
+	ansiCyan   = "36"
+	ansiGray   = "90"
+	ansiLabel  = "35"
+)
+
+var statusCodes = map[string]string{
+	"in_progress":    ansiYellow,
+	"blocked":        ansiRed,
+	"done":           ansiGreen,
+	"canceled":       ansiGray,
+	"pending_review": ansiBlue,
+	"stale":          ansiBold + ";" + ansiYellow,
+}
+
+var priorityCodes = map[int]string{
+	1: ansiBold + ";" + ansiRed,
+	4: ansiGray,
+	5: ansiGray,
+}
+
+// ShouldColor reports whether output to f should be colored: f must be a
+// terminal, NO_COLOR must be unset or empty (https://no-color.org), and TERM
+// must not be "dumb".
+func ShouldColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// SetColorEnabled turns colored output on or off.
+func SetColorEnabled(on bool) {
+	colorMu.Lock()
+	defer colorMu.Unlock()
+	colorEnabled = on
+}
+
+// ColorEnabled reports whether colored output is on.
+func ColorEnabled() bool {
+	colorMu.RLock()
+	defer colorMu.RUnlock()
+	return colorEnabled
+}
+
+// SetLabelColors sets the colors used for labels, keyed by label name.
+// Values are hex colors ("#ff0000") or 256-color numbers ("196").
+func SetLabelColors(colors map[string]string) {
+	colorMu.Lock()
+	defer colorMu.Unlock()
+	labelColors = make(map[string]string, len(colors))
+	for name, c := range colors {
+		if code := colorCode(c); code != "" {
+			labelColors[name] = code
+		}
+	}
+}
+
+// Colorize wraps s in the given SGR code when color is enabled.
+func Colorize(code, s string) string {
+	if code == "" || s == "" || !ColorEnabled() {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + ansiReset
+}
+
+// Bold renders s in bold.
+func Bold(s string) string { return Colorize(ansiBold, s) }
+
+// Dim renders s faintly.
+func Dim(s string) string { return Colorize(ansiDim, s) }
+
+// Warning renders s in the warning color.
+func Warning(s string) string { return Colorize(ansiBold+";"+ansiYellow, s) }
+
+// Heading renders a section heading.
+func Heading(s string) string { return Colorize(ansiBold+";"+ansiBlue, s) }
+
+// Status renders text in the color of a status. status may also be "stale"
+// (see StatusDisplay). text is usually the status itself, already padded for
+// table output so escape codes don't disturb the alignment.
+func Status(status, text string) string {
+	return Colorize(statusCodes[status], text)
+}
+
+// Priority renders text in the color of a priority.
+func Priority(p int, text string) string {
+	return Colorize(priorityCodes[p], text)
+}
+
+// Label renders "[name]" in the label's configured color.
+func Label(name string) string {
+	colorMu.RLock()
+	code, ok := labelColors[name]
+	colorMu.RUnlock()
+	if !ok {
+		code = ansiLabel
+	}
+	return Colorize(code, "["+name+"]")
+}
+
+// Type renders text in an item type's configured color: a hex ("#ff0000")
+// or 256-color ("196") value. Other values leave the text uncolored.
+func Type(color, text string) string {
+	return Colorize(colorCode(color), text)
+}
+
+// ID renders an item ID.
+func ID(id string) string { return Colorize(ansiCyan, id) }
+
+// colorCode converts a hex ("#rrggbb") or 256-color ("0"-"255") value to an
+// SGR foreground code. Unrecognized values return "".
+func colorCode(c string) string {
+	c = strings.TrimSpace(c)
+	if strings.HasPrefix(c, "#") && len(c) == 7 {
+		rgb, err := strconv.ParseUint(c[1:], 16, 32)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("38;2;%d;%d;%d", rgb>>16, (rgb>>8)&0xff, rgb&0xff)
+	}
+	if n, err := strconv.Atoi(c); err == nil && n >= 0 && n <= 255 {
+		return fmt.Sprintf("38;5;%d", n)
+	}
+	return ""
+}
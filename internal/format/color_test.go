@@ -0,0 +1,66 @@
+package format
+
+import (
+	"os"
+	"testing"
+)
+
+func TestColorize(t *testing.T) {
+	t.Cleanup(func() {
+		SetColorEnabled(false)
+		SetLabelColors(nil)
+	})
+
+	SetColorEnabled(false)
+	if got := Status("done", "done"); got != "done" {
+		t.Errorf("expected plain text with color off, got %q", got)
+	}
+
+	SetColorEnabled(true)
+	if got := Status("done", "done"); got != "\x1b[32mdone\x1b[0m" {
+		t.Errorf("unexpected done status %q", got)
+	}
+	if got := Status("open", "open"); got != "open" {
+		t.Errorf("expected open status to stay uncolored, got %q", got)
+	}
+	if got := Priority(1, "high"); got != "\x1b[1;31mhigh\x1b[0m" {
+		t.Errorf("unexpected priority %q", got)
+	}
+
+	if got := Type("#00ff00", "bug"); got != "\x1b[38;2;0;255;0mbug\x1b[0m" {
+		t.Errorf("unexpected type color %q", got)
+	}
+	if got := Type("", "task"); got != "task" {
+		t.Errorf("expected uncolored type without a color, got %q", got)
+	}
+
+	SetLabelColors(map[string]string{"bug": "#ff8000", "ui": "39", "odd": "teal"})
+	tests := map[string]string{
+		"bug":   "\x1b[38;2;255;128;0m[bug]\x1b[0m",
+		"ui":    "\x1b[38;5;39m[ui]\x1b[0m",
+		"odd":   "\x1b[35m[odd]\x1b[0m",
+		"plain": "\x1b[35m[plain]\x1b[0m",
+	}
+	for name, want := range tests {
+		if got := Label(name); got != want {
+			t.Errorf("Label(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestShouldColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("TERM", "xterm")
+	if ShouldColor(f) {
+		t.Error("expected no color for a regular file")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if ShouldColor(os.Stdout) {
+		t.Error("expected NO_COLOR to disable color")
+	}
+}
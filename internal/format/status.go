@@ -2,21 +2,24 @@
 package format
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/taxilian/tpg/internal/model"
 )
 
-// StaleThreshold is the duration after which an in_progress task is considered stale.
-const StaleThreshold = 5 * time.Minute
+// StaleThreshold is the default duration after which an in_progress task is
+// considered stale. Config can override it per priority and type.
+const StaleThreshold = model.DefaultStaleThreshold
 
 // IsStale returns true if the item is in_progress and hasn't been updated
-// within the StaleThreshold duration.
+// within the stale threshold for its priority and type.
 func IsStale(item model.Item, now time.Time) bool {
 	if item.Status != model.StatusInProgress {
 		return false
 	}
-	return now.Sub(item.UpdatedAt) > StaleThreshold
+	return now.Sub(item.UpdatedAt) > model.StaleThresholdFor(item)
 }
 
 // StatusDisplay returns the display status for an item.
@@ -27,3 +30,45 @@ func StatusDisplay(item model.Item, now time.Time) string {
 	}
 	return string(item.Status)
 }
+
+// AgentActiveWindow is how recently an agent must have been seen (through a
+// heartbeat or any update to its task) to count as active.
+const AgentActiveWindow = 10 * time.Minute
+
+// AgentLastSeen returns when the agent working on an item was last seen: the
+// later of its last heartbeat and the item's last update. ok is false when
+// the item is not claimed by an agent.
+func AgentLastSeen(item model.Item) (lastSeen time.Time, ok bool) {
+	if item.AgentID == nil || *item.AgentID == "" {
+		return time.Time{}, false
+	}
+	lastSeen = item.UpdatedAt
+	if item.AgentLastActive != nil && item.AgentLastActive.After(lastSeen) {
+		lastSeen = *item.AgentLastActive
+	}
+	return lastSeen, true
+}
+
+// IsAgentActive reports whether the agent claiming an item has been seen
+// within AgentActiveWindow.
+func IsAgentActive(item model.Item, now time.Time) bool {
+	lastSeen, ok := AgentLastSeen(item)
+	return ok && now.Sub(lastSeen) <= AgentActiveWindow
+}
+
+// ProgressBarWidth is the number of cells in the bar drawn by ProgressBar.
+const ProgressBarWidth = 10
+
+// ProgressBar renders a percentage as a fixed-width bar followed by the
+// number, e.g. "[██████░░░░] 60%". Out-of-range values are clamped.
+func ProgressBar(percent int) string {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	filled := percent * ProgressBarWidth / 100
+	return fmt.Sprintf("[%s%s] %d%%",
+		strings.Repeat("█", filled), strings.Repeat("░", ProgressBarWidth-filled), percent)
+}
@@ -173,3 +173,94 @@ func TestStaleThreshold(t *testing.T) {
 		t.Errorf("StaleThreshold = %v, want %v", StaleThreshold, 5*time.Minute)
 	}
 }
+
+func TestIsAgentActive(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	agent := "ses_1"
+	recent := now.Add(-2 * time.Minute)
+
+	tests := []struct {
+		name string
+		item model.Item
+		want bool
+	}{
+		{
+			name: "unclaimed item has no active agent",
+			item: model.Item{Status: model.StatusInProgress, UpdatedAt: now},
+			want: false,
+		},
+		{
+			name: "recent heartbeat is active even if the task is old",
+			item: model.Item{Status: model.StatusInProgress, AgentID: &agent, AgentLastActive: &recent, UpdatedAt: now.Add(-2 * time.Hour)},
+			want: true,
+		},
+		{
+			name: "recent update counts without heartbeats",
+			item: model.Item{Status: model.StatusInProgress, AgentID: &agent, UpdatedAt: recent},
+			want: true,
+		},
+		{
+			name: "agent silent for 2 hours is not active",
+			item: model.Item{Status: model.StatusInProgress, AgentID: &agent, UpdatedAt: now.Add(-2 * time.Hour)},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAgentActive(tt.item, now); got != tt.want {
+				t.Errorf("IsAgentActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		percent int
+		want    string
+	}{
+		{0, "[░░░░░░░░░░] 0%"},
+		{60, "[██████░░░░] 60%"},
+		{65, "[██████░░░░] 65%"},
+		{100, "[██████████] 100%"},
+		{-5, "[░░░░░░░░░░] 0%"},
+		{150, "[██████████] 100%"},
+	}
+
+	for _, tt := range tests {
+		if got := ProgressBar(tt.percent); got != tt.want {
+			t.Errorf("ProgressBar(%d) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestIsStale_ConfiguredThresholds(t *testing.T) {
+	model.SetStaleThresholds(model.StaleThresholds{
+		Default:  10 * time.Minute,
+		Priority: map[int]time.Duration{1: 30 * time.Minute},
+		Type:     map[model.ItemType]time.Duration{"research": 24 * time.Hour},
+	})
+	t.Cleanup(func() { model.SetStaleThresholds(model.StaleThresholds{}) })
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		item      model.Item
+		wantStale bool
+	}{
+		{"default threshold not reached", model.Item{Priority: 2, UpdatedAt: now.Add(-8 * time.Minute)}, false},
+		{"default threshold passed", model.Item{Priority: 2, UpdatedAt: now.Add(-11 * time.Minute)}, true},
+		{"priority threshold not reached", model.Item{Priority: 1, UpdatedAt: now.Add(-20 * time.Minute)}, false},
+		{"priority threshold passed", model.Item{Priority: 1, UpdatedAt: now.Add(-31 * time.Minute)}, true},
+		{"type wins over priority", model.Item{Type: "research", Priority: 1, UpdatedAt: now.Add(-2 * time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.item.Status = model.StatusInProgress
+			if got := IsStale(tt.item, now); got != tt.wantStale {
+				t.Errorf("IsStale() = %v, want %v", got, tt.wantStale)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package metrics collects the counts and gauges a command produces and
+// sends them to a statsd daemon or an OpenTelemetry collector.
+//
+// A command records into a Recorder as it runs and flushes once at the end,
+// so each invocation costs at most one UDP packet or HTTP request.
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind says how a metric's values combine.
+type Kind int
+
+const (
+	// Counter values add up: commands run, transitions made.
+	Counter Kind = iota
+	// Gauge values replace each other: the ready-queue depth right now.
+	Gauge
+)
+
+// Point is one metric value with its tags.
+type Point struct {
+	Name  string
+	Kind  Kind
+	Value float64
+	Tags  map[string]string
+}
+
+// Exporter sends points to a metrics backend.
+type Exporter interface {
+	Export(points []Point, at time.Time) error
+}
+
+// Recorder accumulates points until they are flushed. Counters with the
+// same name and tags are summed; a gauge keeps its last value.
+type Recorder struct {
+	mu     sync.Mutex
+	order  []string
+	points map[string]*Point
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{points: map[string]*Point{}}
+}
+
+// Count adds one to the counter name with tags.
+func (r *Recorder) Count(name string, tags map[string]string) {
+	r.record(Point{Name: name, Kind: Counter, Value: 1, Tags: tags})
+}
+
+// Gauge sets the gauge name with tags to value.
+func (r *Recorder) Gauge(name string, value float64, tags map[string]string) {
+	r.record(Point{Name: name, Kind: Gauge, Value: value, Tags: tags})
+}
+
+func (r *Recorder) record(p Point) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := seriesKey(p.Name, p.Tags)
+	if existing, ok := r.points[key]; ok {
+		if p.Kind == Counter {
+			existing.Value += p.Value
+		} else {
+			existing.Value = p.Value
+		}
+		return
+	}
+	r.order = append(r.order, key)
+	r.points[key] = &p
+}
+
+// Points returns the recorded points in the order they were first recorded.
+func (r *Recorder) Points() []Point {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	points := make([]Point, 0, len(r.order))
+	for _, key := range r.order {
+		points = append(points, *r.points[key])
+	}
+	return points
+}
+
+// Flush sends the recorded points through e, adding tags to each point
+// (a point's own tags win), and empties the recorder.
+func (r *Recorder) Flush(e Exporter, tags map[string]string) error {
+	points := r.Points()
+	r.mu.Lock()
+	r.order = nil
+	r.points = map[string]*Point{}
+	r.mu.Unlock()
+	if len(points) == 0 {
+		return nil
+	}
+	if len(tags) > 0 {
+		for i := range points {
+			merged := make(map[string]string, len(tags)+len(points[i].Tags))
+			for k, v := range tags {
+				merged[k] = v
+			}
+			for k, v := range points[i].Tags {
+				merged[k] = v
+			}
+			points[i].Tags = merged
+		}
+	}
+	return e.Export(points, time.Now())
+}
+
+// New returns the exporter for kind ("statsd" or "otlp"). An empty
+// endpoint uses the backend's usual local address.
+func New(kind, endpoint, prefix string) (Exporter, error) {
+	switch kind {
+	case "statsd":
+		return NewStatsd(endpoint, prefix), nil
+	case "otlp":
+		return NewOTLP(endpoint, prefix), nil
+	default:
+		return nil, fmt.Errorf("unknown metrics exporter %q (use statsd or otlp)", kind)
+	}
+}
+
+// seriesKey identifies a metric series by name and sorted tags.
+func seriesKey(name string, tags map[string]string) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range sortedKeys(tags) {
+		b.WriteString("\x00" + k + "=" + tags[k])
+	}
+	return b.String()
+}
+
+func sortedKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// metricName joins prefix and name with a dot.
+func metricName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
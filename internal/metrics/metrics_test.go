@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.Count("commands", map[string]string{"command": "ready"})
+	r.Count("commands", map[string]string{"command": "ready"})
+	r.Count("commands", map[string]string{"command": "done"})
+	r.Gauge("ready.depth", 4, nil)
+	r.Gauge("ready.depth", 3, nil)
+
+	points := r.Points()
+	if len(points) != 3 {
+		t.Fatalf("got %d points, want 3: %+v", len(points), points)
+	}
+	if points[0].Value != 2 || points[1].Value != 1 {
+		t.Errorf("counters = %v, %v; want 2, 1", points[0].Value, points[1].Value)
+	}
+	if points[2].Value != 3 {
+		t.Errorf("gauge = %v, want its last value 3", points[2].Value)
+	}
+}
+
+func TestStatsdExport(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no UDP: %v", err)
+	}
+	defer conn.Close()
+
+	r := NewRecorder()
+	r.Count("commands", map[string]string{"command": "dep add", "result": "ok"})
+	r.Gauge("ready.depth", 5, map[string]string{"project": "web"})
+	if err := r.Flush(NewStatsd(conn.LocalAddr().String(), "tpg"), map[string]string{"fleet": "ci"}); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := "tpg.commands:1|c|#command:dep_add,fleet:ci,result:ok\n" +
+		"tpg.ready.depth:5|g|#fleet:ci,project:web"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("packet =\n%s\nwant\n%s", got, want)
+	}
+	if len(r.Points()) != 0 {
+		t.Error("Flush did not empty the recorder")
+	}
+}
+
+func TestOTLPExport(t *testing.T) {
+	var got otlpRequest
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		body, _ := io.ReadAll(req.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewRecorder()
+	r.Count("status.transitions", map[string]string{"to": "done"})
+	r.Count("status.transitions", map[string]string{"to": "in_progress"})
+	r.Gauge("ready.depth", 2, nil)
+	if err := r.Flush(NewOTLP(srv.URL, "tpg"), nil); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if path != "/v1/metrics" {
+		t.Errorf("posted to %q, want /v1/metrics", path)
+	}
+	metrics := got.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics, want 2: %+v", len(metrics), metrics)
+	}
+	if metrics[0].Name != "tpg.status.transitions" || metrics[0].Sum == nil || len(metrics[0].Sum.DataPoints) != 2 {
+		t.Errorf("transitions metric = %+v", metrics[0])
+	}
+	if metrics[1].Gauge == nil || metrics[1].Gauge.DataPoints[0].AsDouble != 2 {
+		t.Errorf("ready metric = %+v", metrics[1])
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	r.Count("commands", nil)
+	if err := r.Flush(NewOTLP(srv.URL, "tpg"), nil); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("Flush to a failing collector: err = %v", err)
+	}
+}
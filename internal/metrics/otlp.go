@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOTLPEndpoint is where a local OpenTelemetry collector accepts
+// OTLP over HTTP.
+const DefaultOTLPEndpoint = "http://localhost:4318"
+
+// OTLP sends points to an OpenTelemetry collector using OTLP/HTTP with the
+// JSON encoding. Counters are sent as delta sums, since each command reports
+// only what it did.
+type OTLP struct {
+	url    string
+	prefix string
+	client *http.Client
+}
+
+// NewOTLP returns an exporter for the collector at endpoint, a base URL
+// such as "http://collector:4318". A URL ending in /v1/metrics is used as is.
+func NewOTLP(endpoint, prefix string) *OTLP {
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+	url := strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/metrics") {
+		url += "/v1/metrics"
+	}
+	return &OTLP{url: url, prefix: prefix, client: &http.Client{Timeout: 3 * time.Second}}
+}
+
+// Export posts points in a single request.
+func (o *OTLP) Export(points []Point, at time.Time) error {
+	body, err := json.Marshal(o.request(points, at))
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Post(o.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP collector at %s returned %s", o.url, resp.Status)
+	}
+	return nil
+}
+
+// The types below are the parts of the OTLP JSON schema tpg uses.
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name  string     `json:"name"`
+	Sum   *otlpSum   `json:"sum,omitempty"`
+	Gauge *otlpGauge `json:"gauge,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsDouble     float64         `json:"asDouble"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// aggregationDelta is AGGREGATION_TEMPORALITY_DELTA.
+const aggregationDelta = 1
+
+// request builds the OTLP request for points, one metric per name.
+func (o *OTLP) request(points []Point, at time.Time) otlpRequest {
+	now := strconv.FormatInt(at.UnixNano(), 10)
+	var metrics []otlpMetric
+	byName := map[string]int{}
+	for _, p := range points {
+		dp := otlpDataPoint{Attributes: otlpAttributes(p.Tags), TimeUnixNano: now, AsDouble: p.Value}
+		name := metricName(o.prefix, p.Name)
+		i, ok := byName[name]
+		if !ok {
+			m := otlpMetric{Name: name}
+			if p.Kind == Gauge {
+				m.Gauge = &otlpGauge{}
+			} else {
+				m.Sum = &otlpSum{AggregationTemporality: aggregationDelta, IsMonotonic: true}
+			}
+			metrics = append(metrics, m)
+			i = len(metrics) - 1
+			byName[name] = i
+		}
+		if metrics[i].Gauge != nil {
+			metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints, dp)
+		} else {
+			metrics[i].Sum.DataPoints = append(metrics[i].Sum.DataPoints, dp)
+		}
+	}
+	return otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     otlpResource{Attributes: []otlpAttribute{{Key: "service.name", Value: otlpValue{StringValue: "tpg"}}}},
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: "github.com/taxilian/tpg"}, Metrics: metrics}},
+	}}}
+}
+
+func otlpAttributes(tags map[string]string) []otlpAttribute {
+	var attrs []otlpAttribute
+	for _, k := range sortedKeys(tags) {
+		attrs = append(attrs, otlpAttribute{Key: k, Value: otlpValue{StringValue: tags[k]}})
+	}
+	return attrs
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStatsdAddr is where a local statsd daemon listens.
+const DefaultStatsdAddr = "127.0.0.1:8125"
+
+// maxStatsdPacket keeps packets under a typical MTU so they aren't
+// fragmented or dropped.
+const maxStatsdPacket = 1400
+
+// Statsd sends points as statsd lines over UDP, with tags in the DogStatsD
+// "|#key:value" form that Telegraf, Datadog, and the StatsD exporter for
+// Prometheus accept.
+type Statsd struct {
+	addr   string
+	prefix string
+}
+
+// NewStatsd returns an exporter for the statsd daemon at addr.
+func NewStatsd(addr, prefix string) *Statsd {
+	if addr == "" {
+		addr = DefaultStatsdAddr
+	}
+	return &Statsd{addr: addr, prefix: prefix}
+}
+
+// Export sends points in as few packets as fit.
+func (s *Statsd) Export(points []Point, _ time.Time) error {
+	conn, err := net.DialTimeout("udp", s.addr, 2*time.Second)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	var packet strings.Builder
+	send := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write([]byte(packet.String()))
+		packet.Reset()
+		return err
+	}
+	for _, p := range points {
+		line := s.line(p)
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxStatsdPacket {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	return send()
+}
+
+// line formats p as "name:value|type|#k:v,...".
+func (s *Statsd) line(p Point) string {
+	kind := "c"
+	if p.Kind == Gauge {
+		kind = "g"
+	}
+	line := statsdName(metricName(s.prefix, p.Name)) + ":" + strconv.FormatFloat(p.Value, 'f', -1, 64) + "|" + kind
+	if len(p.Tags) > 0 {
+		tags := make([]string, 0, len(p.Tags))
+		for _, k := range sortedKeys(p.Tags) {
+			tags = append(tags, statsdName(k)+":"+statsdName(p.Tags[k]))
+		}
+		line += "|#" + strings.Join(tags, ",")
+	}
+	return line
+}
+
+// statsdName replaces the characters statsd uses as separators.
+func statsdName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', ',', '#', '@', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
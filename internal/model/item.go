@@ -3,12 +3,15 @@ package model
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
 )
 
-const idAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+// DefaultIDAlphabet is the default set of characters the random portion of an
+// ID is drawn from.
+const DefaultIDAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
 
 // DefaultIDLength is the default number of characters in the random portion of an ID.
 const DefaultIDLength = 3
@@ -20,16 +23,22 @@ func GenerateID(itemType ItemType) string {
 
 // GenerateIDWithPrefixN returns a new ID with the provided prefix and n random chars from [0-9a-z].
 func GenerateIDWithPrefixN(prefix string, itemType ItemType, n int) string {
+	return GenerateIDFrom(prefix, itemType, n, DefaultIDAlphabet)
+}
+
+// GenerateIDFrom returns a new ID with the provided prefix (or the type's
+// prefix when empty) and n random chars from alphabet.
+func GenerateIDFrom(prefix string, itemType ItemType, n int, alphabet string) string {
 	p := strings.TrimSpace(prefix)
 	p = strings.TrimSuffix(p, "-")
 	if p == "" {
-		if itemType == ItemTypeEpic {
-			p = "ep"
+		if info, ok := LookupItemType(itemType); ok && info.Prefix != "" {
+			p = info.Prefix
 		} else {
 			p = "ts"
 		}
 	}
-	return p + "-" + randomAlpha(n)
+	return p + "-" + randomString(alphabet, n)
 }
 
 // GenerateIDWithPrefix returns a new ID with the provided prefix and default length.
@@ -38,15 +47,39 @@ func GenerateIDWithPrefix(prefix string) string {
 	return GenerateIDWithPrefixN(prefix, ItemTypeTask, DefaultIDLength)
 }
 
+// ValidateIDAlphabet checks that alphabet has at least two distinct
+// characters, all lowercase letters or digits, so IDs stay unambiguous in
+// case-insensitive matches, branch names, and file paths.
+func ValidateIDAlphabet(alphabet string) error {
+	seen := make(map[rune]bool)
+	for _, r := range alphabet {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return fmt.Errorf("ID alphabet may only contain lowercase letters and digits, got %q", r)
+		}
+		if seen[r] {
+			return fmt.Errorf("ID alphabet repeats %q", r)
+		}
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		return fmt.Errorf("ID alphabet needs at least 2 characters")
+	}
+	return nil
+}
+
 func randomAlpha(n int) string {
-	alphabetLen := big.NewInt(int64(len(idAlphabet)))
+	return randomString(DefaultIDAlphabet, n)
+}
+
+func randomString(alphabet string, n int) string {
+	alphabetLen := big.NewInt(int64(len(alphabet)))
 	b := make([]byte, n)
 	for i := range b {
 		idx, err := rand.Int(rand.Reader, alphabetLen)
 		if err != nil {
 			panic("crypto/rand failed: " + err.Error())
 		}
-		b[i] = idAlphabet[idx.Int64()]
+		b[i] = alphabet[idx.Int64()]
 	}
 	return string(b)
 }
@@ -58,8 +91,10 @@ const (
 	ItemTypeEpic ItemType = "epic"
 )
 
+// IsValid reports whether t is a built-in type or a registered custom type.
 func (t ItemType) IsValid() bool {
-	return t == ItemTypeTask || t == ItemTypeEpic
+	_, ok := LookupItemType(t)
+	return ok
 }
 
 type Status string
@@ -70,10 +105,13 @@ const (
 	StatusBlocked    Status = "blocked"
 	StatusDone       Status = "done"
 	StatusCanceled   Status = "canceled"
+	// StatusPendingReview is a finished task that needs approval
+	// ('tpg approve') before it counts as done.
+	StatusPendingReview Status = "pending_review"
 )
 
 func (s Status) IsValid() bool {
-	return s == StatusOpen || s == StatusInProgress || s == StatusBlocked || s == StatusDone || s == StatusCanceled
+	return s == StatusOpen || s == StatusInProgress || s == StatusBlocked || s == StatusDone || s == StatusCanceled || s == StatusPendingReview
 }
 
 // Item represents a task or epic in the system.
@@ -100,6 +138,10 @@ type Item struct {
 	SharedContext       string            // Context shared with all children (epics only)
 	ClosingInstructions string            // Instructions to display when completing epic
 	Labels              []string          // Attached label names (populated separately)
+	Fields              map[string]string // Custom key/value fields (populated separately)
+	Progress            *int              // Latest logged progress percentage (populated separately)
+	Alias               string            // Human-friendly alias, if any (populated separately)
+	NeedsApproval       bool              // Finishing needs 'tpg approve' (populated separately)
 	ClosedAt            *time.Time        // When item was closed (done/canceled); nil if open
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
@@ -110,6 +152,8 @@ type Log struct {
 	ID        int64
 	ItemID    string
 	Message   string
+	Category  LogCategory
+	Progress  *int // Percent complete recorded with the entry, if any
 	CreatedAt time.Time
 }
 
@@ -118,6 +162,7 @@ type Log struct {
 type Dep struct {
 	ItemID    string
 	DependsOn string
+	Note      string // why the dependency exists, if recorded
 }
 
 // Project represents a named project that groups related items.
@@ -153,16 +198,18 @@ type Concept struct {
 
 // Learning represents a piece of knowledge discovered during work.
 type Learning struct {
-	ID        string // lrn-XXXXXX
-	Project   string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	TaskID    *string // Optional link to the task that discovered this
-	Summary   string  // One-liner
-	Detail    string  // Full context
-	Files     []string
-	Status    LearningStatus
-	Concepts  []string // Associated concept names
+	ID          string // lrn-XXXXXX
+	Project     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	TaskID      *string // Optional link to the task that discovered this
+	Summary     string  // One-liner
+	Detail      string  // Full context
+	Files       []string
+	Status      LearningStatus
+	Concepts    []string   // Associated concept names
+	ReviewAfter string     // Review interval (e.g. "90d"); empty if never due
+	ReviewAt    *time.Time // When the learning is next due for review
 }
 
 // GenerateLearningID returns a new learning ID with lrn- prefix.
@@ -175,6 +222,35 @@ func GenerateConceptID() string {
 	return "con-" + randomAlpha(DefaultIDLength)
 }
 
+// Note is a free-form persistent page (design decision, runbook) attached to
+// a project, or to an epic when EpicID is set.
+type Note struct {
+	ID        string // note-XXXXXX
+	Project   string
+	EpicID    *string
+	Title     string
+	Body      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Transcript is a large block of text kept with an item, such as the full
+// output of a test run or build. It is stored compressed, apart from logs.
+type Transcript struct {
+	ID         int64
+	ItemID     string
+	Name       string // e.g. the file it came from
+	Size       int64  // Uncompressed size in bytes
+	StoredSize int64  // Compressed size in bytes
+	Text       string // Only set when fetched by ID
+	CreatedAt  time.Time
+}
+
+// GenerateNoteID returns a new note ID with note- prefix.
+func GenerateNoteID() string {
+	return "note-" + randomAlpha(DefaultIDLength)
+}
+
 // Label represents a tag that can be attached to items for categorization.
 // Labels are project-scoped and identified by name (IDs are internal).
 type Label struct {
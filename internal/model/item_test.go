@@ -44,6 +44,27 @@ func TestGenerateID_Uniqueness(t *testing.T) {
 	}
 }
 
+func TestGenerateIDFrom(t *testing.T) {
+	id := GenerateIDFrom("bug-", ItemTypeTask, 6, "01")
+	suffix, ok := strings.CutPrefix(id, "bug-")
+	if !ok || len(suffix) != 6 || strings.Trim(suffix, "01") != "" {
+		t.Errorf("expected bug- and 6 binary digits, got %q", id)
+	}
+}
+
+func TestValidateIDAlphabet(t *testing.T) {
+	for _, alphabet := range []string{DefaultIDAlphabet, "ab", "23456789abcdefghjkmnpqrstuvwxyz"} {
+		if err := ValidateIDAlphabet(alphabet); err != nil {
+			t.Errorf("ValidateIDAlphabet(%q) = %v, want nil", alphabet, err)
+		}
+	}
+	for _, alphabet := range []string{"", "a", "aa", "abC", "ab-"} {
+		if err := ValidateIDAlphabet(alphabet); err == nil {
+			t.Errorf("ValidateIDAlphabet(%q) = nil, want an error", alphabet)
+		}
+	}
+}
+
 func TestItemType_IsValid(t *testing.T) {
 	tests := []struct {
 		itemType ItemType
@@ -0,0 +1,53 @@
+package model
+
+import "sort"
+
+// LabelWeights act as service classes on top of numeric priority. An item's
+// weight is the sum of the weights of its labels; items with a higher weight
+// always sort first, and priority only orders items within the same weight.
+// A positive weight (e.g. "hotfix") jumps the queue, a negative one (e.g.
+// "backlog") sinks below everything unweighted. A nil LabelWeights weighs
+// every item zero.
+type LabelWeights map[string]int
+
+// NewLabelWeights returns the weights with zero entries dropped.
+func NewLabelWeights(weights map[string]int) LabelWeights {
+	w := LabelWeights{}
+	for name, weight := range weights {
+		if weight != 0 {
+			w[name] = weight
+		}
+	}
+	return w
+}
+
+// Weight returns the combined weight of the given labels.
+func (w LabelWeights) Weight(labels []string) int {
+	total := 0
+	for _, l := range labels {
+		total += w[l]
+	}
+	return total
+}
+
+// Sort stably moves heavier items ahead of lighter ones, keeping the existing
+// order (usually priority) within each weight. Labels must already be
+// populated on the items.
+func (w LabelWeights) Sort(items []Item) {
+	if len(w) == 0 {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return w.Weight(items[i].Labels) > w.Weight(items[j].Labels)
+	})
+}
+
+// ReadyLess orders items for work queues: label weight first, then priority.
+// Use it with a stable sort so ties keep the order the ready query returned
+// (manual rank, then creation time).
+func (w LabelWeights) ReadyLess(a, b Item) bool {
+	if wa, wb := w.Weight(a.Labels), w.Weight(b.Labels); wa != wb {
+		return wa > wb
+	}
+	return a.Priority < b.Priority
+}
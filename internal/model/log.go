@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LogCategory classifies a log entry so decisions and blockers can be found
+// among routine notes.
+type LogCategory string
+
+const (
+	LogCategoryProgress LogCategory = "progress"
+	LogCategoryDecision LogCategory = "decision"
+	LogCategoryBlocker  LogCategory = "blocker"
+	LogCategoryNote     LogCategory = "note"
+)
+
+// LogCategories lists every log category.
+var LogCategories = []LogCategory{LogCategoryProgress, LogCategoryDecision, LogCategoryBlocker, LogCategoryNote}
+
+func (c LogCategory) IsValid() bool {
+	for _, known := range LogCategories {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLogCategory parses a category name, case-insensitively.
+func ParseLogCategory(s string) (LogCategory, error) {
+	c := LogCategory(strings.ToLower(strings.TrimSpace(s)))
+	if !c.IsValid() {
+		names := make([]string, len(LogCategories))
+		for i, known := range LogCategories {
+			names[i] = string(known)
+		}
+		return "", fmt.Errorf("invalid log type %q (valid: %s)", s, strings.Join(names, ", "))
+	}
+	return c, nil
+}
+
+// LogCategoryOf returns the category a message declares with a prefix such
+// as "decision:" or "blocker:", or LogCategoryNote when it has none.
+func LogCategoryOf(message string) LogCategory {
+	head, _, found := strings.Cut(strings.TrimSpace(message), ":")
+	if !found {
+		return LogCategoryNote
+	}
+	if c := LogCategory(strings.ToLower(head)); c.IsValid() {
+		return c
+	}
+	return LogCategoryNote
+}
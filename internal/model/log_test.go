@@ -0,0 +1,32 @@
+package model
+
+import "testing"
+
+func TestLogCategoryOf(t *testing.T) {
+	tests := []struct {
+		message string
+		want    LogCategory
+	}{
+		{"progress: auth works", LogCategoryProgress},
+		{"Decision: use JWT", LogCategoryDecision},
+		{"  BLOCKER: waiting on keys", LogCategoryBlocker},
+		{"note: just a note", LogCategoryNote},
+		{"Fixed the parser", LogCategoryNote},
+		{"TODO: decide later", LogCategoryNote},
+		{"", LogCategoryNote},
+	}
+	for _, tt := range tests {
+		if got := LogCategoryOf(tt.message); got != tt.want {
+			t.Errorf("LogCategoryOf(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestParseLogCategory(t *testing.T) {
+	if c, err := ParseLogCategory("Decision"); err != nil || c != LogCategoryDecision {
+		t.Errorf("ParseLogCategory(Decision) = %q, %v", c, err)
+	}
+	if _, err := ParseLogCategory("idea"); err == nil {
+		t.Error("ParseLogCategory(idea) succeeded, want error")
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Priorities are stored as integers from MinPriority (most urgent) to
+// MaxPriority. The named levels keep their historical meaning of 1=high,
+// 2=medium, 3=low.
+const (
+	MinPriority = 1
+	MaxPriority = 5
+)
+
+// priorityNames maps each priority to its display name.
+var priorityNames = map[int]string{
+	1: "high",
+	2: "medium",
+	3: "low",
+	4: "lower",
+	5: "lowest",
+}
+
+// priorityAliases are extra names accepted by ParsePriority.
+var priorityAliases = map[string]int{
+	"critical": 1,
+	"urgent":   1,
+	"normal":   2,
+}
+
+// ValidatePriority returns an error when p is outside the allowed range.
+func ValidatePriority(p int) error {
+	if p < MinPriority || p > MaxPriority {
+		return fmt.Errorf("invalid priority: %d (must be %d-%d)", p, MinPriority, MaxPriority)
+	}
+	return nil
+}
+
+// ParsePriority accepts a priority number ("1"), a "p"-prefixed number
+// ("p1"), or a name such as "high" or "critical", case-insensitively.
+func ParsePriority(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(strings.TrimPrefix(s, "p")); err == nil {
+		return n, ValidatePriority(n)
+	}
+	for p, name := range priorityNames {
+		if name == s {
+			return p, nil
+		}
+	}
+	if p, ok := priorityAliases[s]; ok {
+		return p, nil
+	}
+	return 0, fmt.Errorf("invalid priority %q (use %d-%d or one of %s)", s, MinPriority, MaxPriority, strings.Join(PriorityNames()[:MaxPriority], ", "))
+}
+
+// PriorityName returns the display name of a priority, or the number itself
+// when it is out of range.
+func PriorityName(p int) string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return strconv.Itoa(p)
+}
+
+// PriorityNames returns the priority names, most urgent first, followed by
+// the accepted aliases.
+func PriorityNames() []string {
+	names := make([]string, 0, len(priorityNames)+len(priorityAliases))
+	for p := MinPriority; p <= MaxPriority; p++ {
+		names = append(names, priorityNames[p])
+	}
+	for _, alias := range []string{"critical", "urgent", "normal"} {
+		names = append(names, alias)
+	}
+	return names
+}
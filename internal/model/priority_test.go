@@ -0,0 +1,45 @@
+package model
+
+import "testing"
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"1", 1, true},
+		{"5", 5, true},
+		{"P2", 2, true},
+		{"high", 1, true},
+		{" Medium ", 2, true},
+		{"low", 3, true},
+		{"lowest", 5, true},
+		{"critical", 1, true},
+		{"0", 0, false},
+		{"99", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParsePriority(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParsePriority(%q) error = %v, want ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if tt.ok && got != tt.want {
+			t.Errorf("ParsePriority(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPriorityName(t *testing.T) {
+	for p := MinPriority; p <= MaxPriority; p++ {
+		name := PriorityName(p)
+		if got, err := ParsePriority(name); err != nil || got != p {
+			t.Errorf("PriorityName(%d) = %q does not parse back (%d, %v)", p, name, got, err)
+		}
+	}
+	if got := PriorityName(9); got != "9" {
+		t.Errorf("expected out-of-range priority to render as a number, got %q", got)
+	}
+}
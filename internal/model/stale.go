@@ -0,0 +1,86 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultStaleThreshold is how long an in_progress item may go without an
+// update before it is considered stale, unless configured otherwise.
+const DefaultStaleThreshold = 5 * time.Minute
+
+// StaleThresholds sets how long an in_progress item may go without updates
+// before it is stale. A type-specific threshold wins over a priority-specific
+// one, which wins over Default.
+type StaleThresholds struct {
+	Default  time.Duration
+	Priority map[int]time.Duration
+	Type     map[ItemType]time.Duration
+}
+
+var (
+	staleThresholdsMu sync.RWMutex
+	staleThresholds   StaleThresholds
+)
+
+// SetStaleThresholds replaces the configured stale thresholds. Non-positive
+// durations are ignored.
+func SetStaleThresholds(t StaleThresholds) {
+	staleThresholdsMu.Lock()
+	defer staleThresholdsMu.Unlock()
+	staleThresholds = StaleThresholds{Default: t.Default}
+	for p, d := range t.Priority {
+		if d > 0 {
+			if staleThresholds.Priority == nil {
+				staleThresholds.Priority = map[int]time.Duration{}
+			}
+			staleThresholds.Priority[p] = d
+		}
+	}
+	for typ, d := range t.Type {
+		if d > 0 {
+			if staleThresholds.Type == nil {
+				staleThresholds.Type = map[ItemType]time.Duration{}
+			}
+			staleThresholds.Type[typ] = d
+		}
+	}
+}
+
+// StaleThresholdFor returns the stale threshold that applies to an item.
+func StaleThresholdFor(item Item) time.Duration {
+	staleThresholdsMu.RLock()
+	defer staleThresholdsMu.RUnlock()
+	if d, ok := staleThresholds.Type[item.Type]; ok {
+		return d
+	}
+	if d, ok := staleThresholds.Priority[item.Priority]; ok {
+		return d
+	}
+	if staleThresholds.Default > 0 {
+		return staleThresholds.Default
+	}
+	return DefaultStaleThreshold
+}
+
+// MinStaleThreshold returns the shortest configured threshold, so callers can
+// narrow a query before checking each item with StaleThresholdFor.
+func MinStaleThreshold() time.Duration {
+	staleThresholdsMu.RLock()
+	defer staleThresholdsMu.RUnlock()
+	min := staleThresholds.Default
+	if min <= 0 {
+		min = DefaultStaleThreshold
+	}
+	for _, d := range staleThresholds.Priority {
+		if d < min {
+			min = d
+		}
+	}
+	for _, d := range staleThresholds.Type {
+		if d < min {
+			min = d
+		}
+	}
+	return min
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// statusTransitions is the built-in status graph: for each status, the
+// statuses an item in it may move to. Closed items must be reopened before
+// anything else happens to them. Items pending review reach done only
+// through approval, which doesn't go through the graph.
+var statusTransitions = map[Status][]Status{
+	StatusOpen:          {StatusInProgress, StatusBlocked, StatusDone, StatusCanceled, StatusPendingReview},
+	StatusInProgress:    {StatusOpen, StatusBlocked, StatusDone, StatusCanceled, StatusPendingReview},
+	StatusBlocked:       {StatusOpen, StatusInProgress, StatusDone, StatusCanceled, StatusPendingReview},
+	StatusPendingReview: {StatusOpen, StatusInProgress, StatusCanceled},
+	StatusDone:          {StatusOpen},
+	StatusCanceled:      {StatusOpen},
+}
+
+var (
+	customTransitionsMu sync.RWMutex
+	customTransitions   = map[Status][]Status{}
+)
+
+// AllowTransition adds a move from one status to another to the graph, for
+// projects whose workflow needs more than the built-in one.
+func AllowTransition(from, to Status) error {
+	if !from.IsValid() {
+		return fmt.Errorf("invalid status: %s", from)
+	}
+	if !to.IsValid() {
+		return fmt.Errorf("invalid status: %s", to)
+	}
+	if CanTransition(from, to) {
+		return nil
+	}
+	customTransitionsMu.Lock()
+	defer customTransitionsMu.Unlock()
+	customTransitions[from] = append(customTransitions[from], to)
+	return nil
+}
+
+// ResetTransitions removes all transitions added by AllowTransition.
+func ResetTransitions() {
+	customTransitionsMu.Lock()
+	defer customTransitionsMu.Unlock()
+	customTransitions = map[Status][]Status{}
+}
+
+// NextStatuses returns the statuses an item in from may move to.
+func NextStatuses(from Status) []Status {
+	next := append([]Status(nil), statusTransitions[from]...)
+	customTransitionsMu.RLock()
+	defer customTransitionsMu.RUnlock()
+	return append(next, customTransitions[from]...)
+}
+
+// CanTransition reports whether an item may move from one status to
+// another. Staying in the same status is always allowed.
+func CanTransition(from, to Status) bool {
+	if from == to {
+		return true
+	}
+	for _, s := range NextStatuses(from) {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError reports a status change the graph doesn't allow.
+type TransitionError struct {
+	From, To Status
+}
+
+func (e *TransitionError) Error() string {
+	next := NextStatuses(e.From)
+	names := make([]string, len(next))
+	for i, s := range next {
+		names[i] = string(s)
+	}
+	allowed := "nothing"
+	if len(names) > 0 {
+		allowed = strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("cannot change status from %s to %s (from %s: %s)", e.From, e.To, e.From, allowed)
+}
+
+// CheckTransition returns a *TransitionError when the graph doesn't allow
+// moving from one status to another.
+func CheckTransition(from, to Status) error {
+	if CanTransition(from, to) {
+		return nil
+	}
+	return &TransitionError{From: from, To: to}
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	t.Cleanup(ResetTransitions)
+
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{StatusOpen, StatusInProgress, true},
+		{StatusInProgress, StatusDone, true},
+		{StatusBlocked, StatusOpen, true},
+		{StatusDone, StatusOpen, true},
+		{StatusDone, StatusDone, true},
+		{StatusDone, StatusBlocked, false},
+		{StatusDone, StatusInProgress, false},
+		{StatusCanceled, StatusDone, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	if err := AllowTransition(StatusDone, StatusInProgress); err != nil {
+		t.Fatalf("AllowTransition: %v", err)
+	}
+	if !CanTransition(StatusDone, StatusInProgress) {
+		t.Error("added transition not allowed")
+	}
+	if err := AllowTransition(StatusDone, "shipped"); err == nil {
+		t.Error("expected error for an unknown status")
+	}
+	ResetTransitions()
+	if CanTransition(StatusDone, StatusInProgress) {
+		t.Error("ResetTransitions kept an added transition")
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	err := CheckTransition(StatusDone, StatusBlocked)
+	var transErr *TransitionError
+	if !errors.As(err, &transErr) {
+		t.Fatalf("CheckTransition(done, blocked) = %v, want a *TransitionError", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "done to blocked") || !strings.Contains(msg, "from done: open") {
+		t.Errorf("error = %q, want the move and the allowed statuses", msg)
+	}
+	if err := CheckTransition(StatusOpen, StatusDone); err != nil {
+		t.Errorf("CheckTransition(open, done) = %v", err)
+	}
+}
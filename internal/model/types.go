@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TypeInfo describes an item type and how items of that type behave.
+type TypeInfo struct {
+	Name            ItemType
+	Prefix          string // ID prefix, e.g. "ts"
+	DefaultPriority int    // priority used when none is given
+	CanHaveChildren bool   // whether items of this type may be parents
+	Icon            string // optional display icon for list/TUI
+	Color           string // optional display color (hex or ANSI name)
+	Builtin         bool
+}
+
+var builtinTypes = map[ItemType]TypeInfo{
+	ItemTypeTask: {Name: ItemTypeTask, Prefix: "ts", DefaultPriority: 2, Builtin: true},
+	ItemTypeEpic: {Name: ItemTypeEpic, Prefix: "ep", DefaultPriority: 2, CanHaveChildren: true, Builtin: true},
+}
+
+var (
+	customTypesMu sync.RWMutex
+	customTypes   = map[ItemType]TypeInfo{}
+)
+
+// RegisterItemType adds a custom item type. Built-in types cannot be redefined.
+func RegisterItemType(info TypeInfo) error {
+	if info.Name == "" {
+		return fmt.Errorf("type name cannot be empty")
+	}
+	if _, ok := builtinTypes[info.Name]; ok {
+		return fmt.Errorf("cannot redefine built-in type %q", info.Name)
+	}
+	info.Builtin = false
+	customTypesMu.Lock()
+	defer customTypesMu.Unlock()
+	customTypes[info.Name] = info
+	return nil
+}
+
+// ResetItemTypes removes all custom item types.
+func ResetItemTypes() {
+	customTypesMu.Lock()
+	defer customTypesMu.Unlock()
+	customTypes = map[ItemType]TypeInfo{}
+}
+
+// LookupItemType returns the definition for a built-in or registered type.
+func LookupItemType(t ItemType) (TypeInfo, bool) {
+	if info, ok := builtinTypes[t]; ok {
+		return info, true
+	}
+	customTypesMu.RLock()
+	defer customTypesMu.RUnlock()
+	info, ok := customTypes[t]
+	return info, ok
+}
+
+// ItemTypes returns all known types: built-ins first, then custom types by name.
+func ItemTypes() []TypeInfo {
+	types := []TypeInfo{builtinTypes[ItemTypeTask], builtinTypes[ItemTypeEpic]}
+	customTypesMu.RLock()
+	var custom []TypeInfo
+	for _, info := range customTypes {
+		custom = append(custom, info)
+	}
+	customTypesMu.RUnlock()
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	return append(types, custom...)
+}
+
+// CanHaveChildren reports whether items of this type may be parents.
+func (t ItemType) CanHaveChildren() bool {
+	info, ok := LookupItemType(t)
+	return ok && info.CanHaveChildren
+}
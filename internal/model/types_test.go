@@ -0,0 +1,37 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterItemType(t *testing.T) {
+	t.Cleanup(ResetItemTypes)
+
+	if err := RegisterItemType(TypeInfo{Name: "bug", Prefix: "bg", DefaultPriority: 1, Icon: "🐛"}); err != nil {
+		t.Fatalf("RegisterItemType failed: %v", err)
+	}
+	if !ItemType("bug").IsValid() {
+		t.Error("registered type should be valid")
+	}
+	if ItemType("bug").CanHaveChildren() {
+		t.Error("bug should not allow children")
+	}
+	if id := GenerateIDWithPrefixN("", "bug", 4); !strings.HasPrefix(id, "bg-") {
+		t.Errorf("expected bg- prefix, got %s", id)
+	}
+
+	if err := RegisterItemType(TypeInfo{Name: ItemTypeEpic}); err == nil {
+		t.Error("expected error redefining built-in type")
+	}
+
+	types := ItemTypes()
+	if len(types) != 3 || types[0].Name != ItemTypeTask || types[1].Name != ItemTypeEpic || types[2].Name != "bug" {
+		t.Errorf("unexpected type order: %+v", types)
+	}
+
+	ResetItemTypes()
+	if ItemType("bug").IsValid() {
+		t.Error("type should be invalid after reset")
+	}
+}
@@ -5,6 +5,7 @@
  * - Injects `tpg prime` context into system prompt (fresh each time)
  * - Injects `tpg prime` context during context compaction
  * - Adds AGENT_ID and AGENT_TYPE env vars to all `tpg` bash commands
+ * - Sends periodic `tpg heartbeat` calls so status can show which agents are alive
  * - Provides tools to inspect subagent sessions (check task status without full context)
  */
 
@@ -60,6 +61,29 @@ export const TpgPlugin: Plugin = async ({ $, directory, client, project, worktre
     }
   }
 
+  // Last heartbeat time per session, to send at most one per interval
+  const HEARTBEAT_INTERVAL_MS = 60_000
+  const lastHeartbeat = new Map<string, number>()
+
+  /**
+   * Record agent liveness with `tpg heartbeat`. Throttled per session and
+   * fire-and-forget: failures never affect the session.
+   */
+  async function heartbeat(sessionID: string, agentType: string): Promise<void> {
+    const now = Date.now()
+    const last = lastHeartbeat.get(sessionID) ?? 0
+    if (now - last < HEARTBEAT_INTERVAL_MS) return
+    lastHeartbeat.set(sessionID, now)
+    try {
+      await $`tpg heartbeat --quiet`
+        .cwd(directory)
+        .env({ AGENT_ID: sessionID, AGENT_TYPE: agentType })
+        .quiet()
+    } catch {
+      // Ignore: older tpg versions or no database
+    }
+  }
+
   /**
    * Verify a session is a legitimate subagent accessible from current context.
    * Security checks: must have parentID, must be in same directory tree.
@@ -103,6 +127,7 @@ export const TpgPlugin: Plugin = async ({ $, directory, client, project, worktre
       if (!input.sessionID) return
 
       const agentType = await getAgentType(input.sessionID)
+      void heartbeat(input.sessionID, agentType)
       const prime = await getPrime(input.sessionID, agentType)
       if (prime) {
         output.system.push(prime)
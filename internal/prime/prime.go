@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
 	"github.com/taxilian/tpg/internal/templates"
 )
 
@@ -59,8 +62,42 @@ type PrimeData struct {
 	// Worktree epics ready to merge
 	WorktreeMergeEpics []PrimeItem
 	WorktreeMergeCount int
+
+	// Needs attention (see 'tpg remind'); stale items are reported above
+	OverdueItems   []PrimeItem
+	UnblockedItems []PrimeItem
+	ClosableEpics  []PrimeItem
+	AttentionCount int
+
+	// Resume is the in-progress task this session is picking back up, if
+	// any. The default template leads with it and condenses the general
+	// guidance unless FullGuide is set.
+	Resume    *PrimeResume
+	FullGuide bool
+}
+
+// PrimeResume is the task a resuming session was working on.
+type PrimeResume struct {
+	PrimeItem
+	Description string     // the first resumeDescriptionLines lines
+	Epic        *PrimeItem // parent epic, if any
+	Progress    string     // latest progress log
+	ProgressAge string     // e.g. "2h ago"
+	Blockers    []PrimeBlocker
+	OtherTasks  []PrimeItem // other tasks the session has in progress
 }
 
+// PrimeBlocker is an unmet dependency of the resumed task.
+type PrimeBlocker struct {
+	ID     string
+	Title  string
+	Status string
+}
+
+// resumeDescriptionLines caps the description shown for a resumed task;
+// 'tpg show' has the rest.
+const resumeDescriptionLines = 12
+
 // PrimeItem is a simplified view of model.Item for templates
 type PrimeItem struct {
 	ID       string
@@ -150,7 +187,29 @@ func DefaultPrimeTemplate() string {
 
 This project uses **tpg** for cross-session task management.
 {{if .Project}}Project: {{.Project}}{{else if .DefaultProject}}Default: {{.DefaultProject}}{{end}}
-
+{{with .Resume}}
+## Resuming: [{{.ID}}] {{.Title}}
+{{with .Epic}}Epic: [{{.ID}}] {{.Title}}
+{{end -}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+{{if .Progress -}}
+**Latest progress ({{.ProgressAge}}):**
+{{.Progress}}
+{{else -}}
+**No progress logged yet.**
+{{end -}}
+{{if .Blockers}}
+**Waiting on:**
+{{range .Blockers}}  • [{{.ID}}] {{.Title}} ({{.Status}})
+{{end -}}
+{{end -}}
+{{if .OtherTasks}}
+Also in progress: {{range .OtherTasks}}[{{.ID}}] {{end}}
+{{end}}
+Full details: 'tpg show {{.ID}}'. Log milestones as you go; finish with 'tpg done {{.ID}}'.
+{{end}}
 ## Status
 {{if not .HasDB -}}
 No database - run 'tpg init'
@@ -169,6 +228,15 @@ No database - run 'tpg init'
 {{range .WorktreeMergeEpics}}  • [{{.ID}}] {{.Title}} - run 'tpg epic set-merged {{.ID}}'
 {{end}}
 {{end -}}
+{{if gt .AttentionCount 0 -}}
+**⚠️ NEEDS ATTENTION ({{.AttentionCount}}, details: 'tpg remind'):**
+{{range .OverdueItems}}  • [{{.ID}}] {{.Title}} - overdue
+{{end -}}
+{{range .UnblockedItems}}  • [{{.ID}}] {{.Title}} - blockers done, run 'tpg reopen {{.ID}}'
+{{end -}}
+{{range .ClosableEpics}}  • [{{.ID}}] {{.Title}} - all children closed, run 'tpg done {{.ID}}'
+{{end}}
+{{end -}}
 {{if gt (len .MyInProgItems) 0 -}}
 **Your work:**
 {{range .MyInProgItems}}  • [{{.ID}}] {{.Title}}{{if eq .Priority 1}} ⚡{{end}}
@@ -199,6 +267,16 @@ You have {{.SubagentTaskCount}} tasks assigned to this session:
 {{end -}}
 {{end -}}
 
+{{if and .Resume (not .FullGuide) -}}
+## Workflow (condensed while resuming; 'tpg prime --full' for everything)
+
+  tpg log <id> - <<EOF          # decisions, blockers, next steps
+  tpg done <id> - <<EOF         # results when finished
+  tpg dep <blocker> blocks <id> # if you get blocked
+  tpg ready / tpg show <id>     # next work, details
+  tpg add "title" --parent <epic> --desc - <<EOF   # follow-up work
+
+{{else -}}
 ## Workflow
 
 **Start:** 'tpg ready' → 'tpg show <id>' → 'tpg start <id>'
@@ -254,10 +332,20 @@ No templates. Create in .tpg/templates/ to standardize workflows.
   tpg status                 # Overview
   tpg context -c <concept>   # Load learnings
 
+{{end -}}
 **⚠️ CRITICAL:** Never modify '.tpg/tpg.db' directly. Use only 'tpg' CLI commands.
 `
 }
 
+// primeItems converts items to PrimeItems.
+func primeItems(items []model.Item) []PrimeItem {
+	var out []PrimeItem
+	for _, item := range items {
+		out = append(out, PrimeItem{ID: item.ID, Title: item.Title, Priority: item.Priority})
+	}
+	return out
+}
+
 // BuildPrimeData constructs PrimeData from database queries and config
 func BuildPrimeData(report *db.StatusReport, config *db.Config, agentCtx db.AgentContext, database *db.DB) PrimeData {
 	data := PrimeData{
@@ -340,6 +428,18 @@ func BuildPrimeData(report *db.StatusReport, config *db.Config, agentCtx db.Agen
 				})
 			}
 		}
+
+		// Get hygiene items needing attention
+		if attention, err := database.NeedsAttention(report.Project, time.Now()); err == nil {
+			data.OverdueItems = primeItems(attention.Overdue)
+			data.UnblockedItems = primeItems(attention.Unblocked)
+			data.ClosableEpics = primeItems(attention.Closable)
+			data.AttentionCount = len(attention.Overdue) + len(attention.Unblocked) + len(attention.Closable)
+		}
+	}
+
+	if database != nil && report != nil {
+		data.Resume = buildResume(database, data)
 	}
 
 	// Get available templates
@@ -350,3 +450,79 @@ func BuildPrimeData(report *db.StatusReport, config *db.Config, agentCtx db.Agen
 
 	return data
 }
+
+// buildResume picks the task a resuming session was working on: the
+// session's current task, else the first task assigned to this subagent or
+// agent. It returns nil when the session has nothing in progress.
+func buildResume(database *db.DB, data PrimeData) *PrimeResume {
+	candidates := data.MyInProgItems
+	if data.IsSubagent {
+		candidates = data.SubagentTasks
+	}
+	var ids []string
+	if current, err := database.GetCurrentTask(db.CurrentSession()); err == nil && current != "" {
+		ids = append(ids, current)
+	}
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+	var item *model.Item
+	for _, id := range ids {
+		if it, err := database.GetItem(id); err == nil && it.Status == model.StatusInProgress {
+			item = it
+			break
+		}
+	}
+	if item == nil {
+		return nil
+	}
+
+	resume := &PrimeResume{
+		PrimeItem:   PrimeItem{ID: item.ID, Title: item.Title, Priority: item.Priority},
+		Description: firstLines(strings.TrimSpace(item.Description), resumeDescriptionLines),
+	}
+	if item.ParentID != nil {
+		if parent, err := database.GetItem(*item.ParentID); err == nil {
+			resume.Epic = &PrimeItem{ID: parent.ID, Title: parent.Title, Priority: parent.Priority}
+		}
+	}
+	if log, err := database.LatestProgressLog(item.ID); err == nil && log != nil {
+		resume.Progress = strings.TrimSpace(log.Message)
+		resume.ProgressAge = timeAgo(log.CreatedAt)
+	}
+	if unmet, err := database.UnmetDeps(item.ID); err == nil {
+		for _, u := range unmet {
+			resume.Blockers = append(resume.Blockers, PrimeBlocker{ID: u.Dep.ID, Title: u.Dep.Title, Status: u.Dep.Status})
+		}
+	}
+	for _, c := range candidates {
+		if c.ID != item.ID {
+			resume.OtherTasks = append(resume.OtherTasks, c)
+		}
+	}
+	return resume
+}
+
+// firstLines returns the first n lines of text, noting how many were cut.
+func firstLines(text string, n int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) <= n {
+		return text
+	}
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n… (%d more lines)", len(lines)-n)
+}
+
+// timeAgo formats how long ago t was, e.g. "2h ago".
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
 )
 
 func TestRenderPrime_DefaultTemplate(t *testing.T) {
@@ -343,3 +345,74 @@ func TestDefaultPrimeTemplate_NotEmpty(t *testing.T) {
 		t.Error("Default template should mention 'tpg done'")
 	}
 }
+
+func TestBuildPrimeData_ResumesCurrentTask(t *testing.T) {
+	t.Setenv("AGENT_ID", "")
+	t.Setenv("TPG_SESSION", "prime-test")
+	database, err := db.Open(filepath.Join(t.TempDir(), "tpg.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+	if err := database.Init(); err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+
+	now := time.Now()
+	mk := func(id, title string, status model.Status, parent string) {
+		item := &model.Item{ID: id, Project: "p", Type: model.ItemTypeTask, Title: title, Status: status,
+			Priority: 2, Description: "Line one\nLine two", CreatedAt: now, UpdatedAt: now}
+		if parent != "" {
+			item.ParentID = &parent
+		}
+		if err := database.CreateItem(item); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	epic := &model.Item{ID: "ep-1", Project: "p", Type: model.ItemTypeEpic, Title: "Parser rewrite",
+		Status: model.StatusOpen, Priority: 2, CreatedAt: now, UpdatedAt: now}
+	if err := database.CreateItem(epic); err != nil {
+		t.Fatalf("create epic: %v", err)
+	}
+	mk("ts-1", "Fix lexer", model.StatusInProgress, "ep-1")
+	mk("ts-2", "Schema change", model.StatusOpen, "")
+	if err := database.AddDep("ts-1", "ts-2"); err != nil {
+		t.Fatalf("AddDep: %v", err)
+	}
+	// Adding a dependency reopens the task; put it back in progress
+	if _, err := database.Exec(`UPDATE items SET status = 'in_progress' WHERE id = 'ts-1'`); err != nil {
+		t.Fatalf("set status: %v", err)
+	}
+	if err := database.AddLog("ts-1", "Escapes handled; quotes next"); err != nil {
+		t.Fatalf("AddLog: %v", err)
+	}
+	if err := database.SetCurrentTask(db.CurrentSession(), "ts-1"); err != nil {
+		t.Fatalf("SetCurrentTask: %v", err)
+	}
+
+	report := &db.StatusReport{Project: "p"}
+	data := BuildPrimeData(report, nil, db.AgentContext{}, database)
+	if data.Resume == nil {
+		t.Fatal("Resume is nil with an in-progress current task")
+	}
+	r := data.Resume
+	if r.ID != "ts-1" || r.Epic == nil || r.Epic.ID != "ep-1" || r.Progress != "Escapes handled; quotes next" {
+		t.Errorf("Resume = %+v", r)
+	}
+	if len(r.Blockers) != 1 || r.Blockers[0].ID != "ts-2" {
+		t.Errorf("Blockers = %+v, want ts-2", r.Blockers)
+	}
+
+	output, err := RenderPrime(DefaultPrimeTemplate(), data)
+	if err != nil {
+		t.Fatalf("RenderPrime: %v", err)
+	}
+	if !strings.Contains(output, "## Resuming: [ts-1] Fix lexer") || strings.Contains(output, "## Creating Work") {
+		t.Errorf("resumed output should lead with the task and condense guidance:\n%s", output)
+	}
+	data.FullGuide = true
+	output, _ = RenderPrime(DefaultPrimeTemplate(), data)
+	if !strings.Contains(output, "## Creating Work") {
+		t.Error("FullGuide should keep the full workflow guide")
+	}
+}
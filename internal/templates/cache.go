@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Index persists parsed templates across commands so unchanged template
+// files are not read and parsed again by every invocation. Entries are
+// trusted only while the file's modification time and size still match.
+type Index interface {
+	GetTemplate(path string) (IndexEntry, bool)
+	PutTemplate(entry IndexEntry)
+}
+
+// IndexEntry is a parsed template file as stored in an Index.
+type IndexEntry struct {
+	Path    string
+	ModTime time.Time
+	Size    int64
+	Hash    string
+	Data    []byte // the parsed template, as JSON
+}
+
+var (
+	cacheMu sync.Mutex
+	index   Index
+	// parsed holds templates loaded by this process, keyed by path.
+	parsed = map[string]cachedTemplate{}
+	// walked remembers template paths found by searching subdirectories,
+	// keyed by directory and template ID.
+	walked = map[[2]string]string{}
+	// textTemplates holds parsed text templates, keyed by their source.
+	textTemplates sync.Map
+)
+
+type cachedTemplate struct {
+	modTime time.Time
+	size    int64
+	tmpl    *Template
+}
+
+// SetIndex installs the persistent index used by template loading. A nil
+// index disables it; the in-process cache is always used.
+func SetIndex(idx Index) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	index = idx
+}
+
+// ResetCache drops every template remembered by this process.
+func ResetCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	parsed = map[string]cachedTemplate{}
+	walked = map[[2]string]string{}
+	textTemplates.Range(func(k, _ any) bool {
+		textTemplates.Delete(k)
+		return true
+	})
+}
+
+// cachedLoad returns the template at path from the in-process cache or the
+// persistent index, when the file has not changed since it was parsed.
+func cachedLoad(path string, info os.FileInfo) (*Template, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if c, ok := parsed[path]; ok && c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+		return c.tmpl.clone(), true
+	}
+	if index == nil {
+		return nil, false
+	}
+	entry, ok := index.GetTemplate(path)
+	if !ok || !entry.ModTime.Equal(info.ModTime()) || entry.Size != info.Size() {
+		return nil, false
+	}
+	var tmpl Template
+	if err := json.Unmarshal(entry.Data, &tmpl); err != nil {
+		return nil, false
+	}
+	tmpl.Hash = entry.Hash
+	parsed[path] = cachedTemplate{modTime: info.ModTime(), size: info.Size(), tmpl: &tmpl}
+	return tmpl.clone(), true
+}
+
+// storeLoaded remembers a freshly parsed template in both caches.
+func storeLoaded(path string, info os.FileInfo, tmpl *Template) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	parsed[path] = cachedTemplate{modTime: info.ModTime(), size: info.Size(), tmpl: tmpl.clone()}
+	if index == nil {
+		return
+	}
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		return
+	}
+	index.PutTemplate(IndexEntry{Path: path, ModTime: info.ModTime(), Size: info.Size(), Hash: tmpl.Hash, Data: data})
+}
+
+// walkedPath returns the remembered subdirectory path for id in dir, if it
+// still exists.
+func walkedPath(dir, id string) (string, bool) {
+	cacheMu.Lock()
+	path, ok := walked[[2]string{dir, id}]
+	cacheMu.Unlock()
+	if !ok {
+		return "", false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+func rememberWalkedPath(dir, id, path string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	walked[[2]string{dir, id}] = path
+}
+
+// parseText parses input as a text template, reusing earlier parses.
+func parseText(input string) (*template.Template, error) {
+	if t, ok := textTemplates.Load(input); ok {
+		return t.(*template.Template), nil
+	}
+	t, err := template.New("").Funcs(templateFuncs).Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	textTemplates.Store(input, t)
+	return t, nil
+}
+
+// clone copies a template so callers can't modify a cached one.
+func (t *Template) clone() *Template {
+	c := *t
+	c.Steps = append([]Step(nil), t.Steps...)
+	c.Variables = make(map[string]Variable, len(t.Variables))
+	for k, v := range t.Variables {
+		c.Variables[k] = v
+	}
+	return &c
+}
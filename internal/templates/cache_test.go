@@ -0,0 +1,79 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type memIndex map[string]IndexEntry
+
+func (m memIndex) GetTemplate(path string) (IndexEntry, bool) {
+	e, ok := m[path]
+	return e, ok
+}
+
+func (m memIndex) PutTemplate(entry IndexEntry) { m[entry.Path] = entry }
+
+func TestLoadTemplateFromPath_Cache(t *testing.T) {
+	ResetCache()
+	idx := memIndex{}
+	SetIndex(idx)
+	t.Cleanup(func() { SetIndex(nil); ResetCache() })
+
+	path := filepath.Join(t.TempDir(), "deploy.yaml")
+	write := func(title string, mtime time.Time) {
+		t.Helper()
+		content := "title: " + title + "\nsteps:\n  - title: Ship it\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write template: %v", err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write("First", base)
+
+	tmpl, err := loadTemplateFromPath(path, "deploy", "project")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if tmpl.Title != "First" || tmpl.Hash == "" {
+		t.Fatalf("got %+v", tmpl)
+	}
+	if _, ok := idx[path]; !ok {
+		t.Fatal("parsed template was not stored in the index")
+	}
+
+	// Callers may modify what they get back without affecting the cache.
+	tmpl.Steps[0].Title = "changed"
+	again, _ := loadTemplateFromPath(path, "deploy", "project")
+	if again.Steps[0].Title != "Ship it" {
+		t.Errorf("cached template was modified through a returned copy")
+	}
+
+	// A changed file is parsed again.
+	write("Second", base.Add(time.Minute))
+	tmpl, err = loadTemplateFromPath(path, "deploy", "project")
+	if err != nil {
+		t.Fatalf("load after change: %v", err)
+	}
+	if tmpl.Title != "Second" {
+		t.Errorf("Title = %q after file changed, want Second", tmpl.Title)
+	}
+
+	// A fresh process trusts the index while the file is unchanged.
+	ResetCache()
+	entry := idx[path]
+	entry.Data = []byte(`{"Title":"From index","Steps":[{"Title":"Ship it"}]}`)
+	idx[path] = entry
+	tmpl, err = loadTemplateFromPath(path, "deploy", "project")
+	if err != nil {
+		t.Fatalf("load from index: %v", err)
+	}
+	if tmpl.Title != "From index" || tmpl.ID != "deploy" || tmpl.SourcePath != path || tmpl.Hash != entry.Hash {
+		t.Errorf("index entry not used: %+v", tmpl)
+	}
+}
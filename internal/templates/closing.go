@@ -0,0 +1,119 @@
+package templates
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ClosingItem is an epic or task as seen by closing instructions.
+type ClosingItem struct {
+	ID          string
+	Title       string
+	Type        string
+	Status      string
+	Description string
+	Results     string
+	Labels      []string
+}
+
+// ClosingData is what an epic's closing instructions are rendered against
+// when the epic completes.
+type ClosingData struct {
+	Epic ClosingItem
+	// Children are all descendants of the epic, in plan order.
+	Children []ClosingItem
+	// Results joins the results of every child, one per paragraph.
+	Results string
+	// Vars holds the variables the epic's template was instantiated with.
+	Vars map[string]string
+}
+
+// closeFieldRe matches references to fields that only exist at close time.
+var closeFieldRe = regexp.MustCompile(`\{\{[^}]*(\.Children|\.Results|\bfiles\b)`)
+
+// UsesCloseFields reports whether text refers to fields only known when an
+// epic completes, so it must not be rendered earlier (e.g. at template
+// instantiation).
+func UsesCloseFields(text string) bool {
+	return closeFieldRe.MatchString(text)
+}
+
+// filePathRe matches things that look like file paths: a name with an
+// extension, optionally preceded by directories.
+var filePathRe = regexp.MustCompile(`(?:[\w.-]+/)*[\w-]+\.[A-Za-z][\w]{0,9}\b`)
+
+// mentionedFiles returns the unique file paths mentioned in text, sorted.
+// URLs are skipped, and extensions must start with a letter so version
+// numbers ("v1.2") don't count.
+func mentionedFiles(text string) []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, field := range strings.Fields(text) {
+		if strings.Contains(field, "://") {
+			continue
+		}
+		for _, m := range filePathRe.FindAllString(field, -1) {
+			if m == "e.g" || m == "i.e" {
+				continue
+			}
+			if !seen[m] {
+				seen[m] = true
+				files = append(files, m)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// closingFuncs extends templateFuncs with helpers for closing instructions.
+var closingFuncs = func() template.FuncMap {
+	funcs := template.FuncMap{
+		// files lists the file paths mentioned in a string
+		"files": mentionedFiles,
+		// withStatus keeps the items with the given status
+		"withStatus": func(status string, items []ClosingItem) []ClosingItem {
+			var out []ClosingItem
+			for _, item := range items {
+				if item.Status == status {
+					out = append(out, item)
+				}
+			}
+			return out
+		},
+		// hasLabel reports whether an item carries a label
+		"hasLabel": func(label string, item ClosingItem) bool {
+			for _, l := range item.Labels {
+				if l == label {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	for name, fn := range templateFuncs {
+		funcs[name] = fn
+	}
+	return funcs
+}()
+
+// RenderClosing renders closing instructions against data. Text without
+// template actions is returned unchanged. Unlike RenderText, errors are
+// returned so the caller can fall back to the raw text and say why.
+func RenderClosing(input string, data ClosingData) (string, error) {
+	if !strings.Contains(input, "{{") {
+		return input, nil
+	}
+	tmpl, err := template.New("on_close").Funcs(closingFuncs).Option("missingkey=error").Parse(input)
+	if err != nil {
+		return input, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return input, err
+	}
+	return buf.String(), nil
+}
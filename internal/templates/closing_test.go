@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMentionedFiles(t *testing.T) {
+	text := "Changed internal/db/db.go and cmd/tpg/main.go (see README.md), bumped to v1.2, e.g. docs.\n" +
+		"Also touched internal/db/db.go again; spec at https://example.com/spec.html"
+	want := []string{"README.md", "cmd/tpg/main.go", "internal/db/db.go"}
+	if got := mentionedFiles(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("mentionedFiles = %v, want %v", got, want)
+	}
+}
+
+func TestRenderClosing(t *testing.T) {
+	data := ClosingData{
+		Epic: ClosingItem{ID: "ep-1", Title: "Auth rewrite"},
+		Children: []ClosingItem{
+			{ID: "ts-1", Title: "Schema", Status: "done", Results: "Added migrations/001_users.sql", Labels: []string{"db"}},
+			{ID: "ts-2", Title: "Old flow", Status: "canceled"},
+		},
+		Results: "Added migrations/001_users.sql",
+	}
+
+	got, err := RenderClosing("Close {{.Epic.Title}}:\n{{range files .Results}}- [ ] review {{.}}\n{{end}}"+
+		"{{range withStatus \"canceled\" .Children}}- [ ] remove leftovers of {{.ID}}\n{{end}}"+
+		"{{range .Children}}{{if hasLabel \"db\" .}}- [ ] back up before {{.ID}}\n{{end}}{{end}}", data)
+	if err != nil {
+		t.Fatalf("RenderClosing: %v", err)
+	}
+	want := "Close Auth rewrite:\n- [ ] review migrations/001_users.sql\n- [ ] remove leftovers of ts-2\n- [ ] back up before ts-1\n"
+	if got != want {
+		t.Errorf("RenderClosing =\n%s\nwant\n%s", got, want)
+	}
+
+	static := "Merge the PR {{ once approved"
+	if got, err := RenderClosing("Merge the PR", data); err != nil || got != "Merge the PR" {
+		t.Errorf("static text = %q, %v", got, err)
+	}
+	if got, err := RenderClosing(static, data); err == nil || got != static {
+		t.Errorf("broken template = %q, %v; want the input back and an error", got, err)
+	}
+	if _, err := RenderClosing("{{.Nope}}", data); err == nil || !strings.Contains(err.Error(), "Nope") {
+		t.Errorf("unknown field error = %v", err)
+	}
+}
+
+func TestUsesCloseFields(t *testing.T) {
+	for text, want := range map[string]bool{
+		"Merge {{.Epic.Title}}":             false,
+		"{{range .Children}}{{.ID}}{{end}}": true,
+		"{{range files .Results}}{{end}}":   true,
+		"no templates here":                 false,
+	} {
+		if got := UsesCloseFields(text); got != want {
+			t.Errorf("UsesCloseFields(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
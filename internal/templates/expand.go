@@ -0,0 +1,137 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StepInstance is one task to create when instantiating a template.
+type StepInstance struct {
+	StepIndex int               // index into Template.Steps
+	Vars      map[string]string // variables to render the step with
+	Depends   []int             // indices of the instances this one depends on
+}
+
+// ListValues splits a list variable into its elements. Elements are separated
+// by newlines, or by commas when the value is a single line. Blank elements
+// are dropped.
+func ListValues(value string) []string {
+	sep := "\n"
+	if !strings.Contains(value, "\n") {
+		sep = ","
+	}
+	var values []string
+	for _, v := range strings.Split(value, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// StepEnabled reports whether a step's when condition holds for vars.
+func StepEnabled(step Step, vars map[string]string) bool {
+	cond := strings.TrimSpace(step.When)
+	if cond == "" {
+		return true
+	}
+	if name, negated := strings.CutPrefix(cond, "!"); negated {
+		return strings.TrimSpace(vars[strings.TrimSpace(name)]) == ""
+	}
+	return strings.TrimSpace(vars[cond]) != ""
+}
+
+// Expand turns template steps into the tasks to create for vars:
+//   - depends entries are rendered, so a variable can name the step (or a
+//     comma-separated list of steps) to depend on
+//   - steps whose when condition fails are skipped; anything depending on a
+//     skipped step depends on that step's own dependencies instead
+//   - for_each steps produce one instance per list element, with the element
+//     in .item and its 1-based position in .item_index; depending on a
+//     for_each step means depending on all of its instances
+//
+// stepIDs holds the ID of every step, including generated ones.
+func Expand(steps []Step, stepIDs []string, vars map[string]string) ([]StepInstance, error) {
+	index := make(map[string]int, len(stepIDs))
+	for i, id := range stepIDs {
+		index[id] = i
+	}
+
+	stepDeps := make([][]int, len(steps))
+	for i, step := range steps {
+		for _, dep := range step.Depends {
+			for _, id := range ListValues(RenderText(dep, vars)) {
+				j, ok := index[id]
+				if !ok {
+					return nil, fmt.Errorf("step %d depends on unknown step id: %s", i, id)
+				}
+				stepDeps[i] = append(stepDeps[i], j)
+			}
+		}
+		if step.ForEach != "" {
+			if _, ok := vars[step.ForEach]; !ok {
+				return nil, fmt.Errorf("step %d: for_each references unknown variable: %s", i, step.ForEach)
+			}
+		}
+	}
+
+	var instances []StepInstance
+	byStep := make([][]int, len(steps))
+	for i, step := range steps {
+		if !StepEnabled(step, vars) {
+			continue
+		}
+		if step.ForEach == "" {
+			byStep[i] = append(byStep[i], len(instances))
+			instances = append(instances, StepInstance{StepIndex: i, Vars: vars})
+			continue
+		}
+		for n, value := range ListValues(vars[step.ForEach]) {
+			itemVars := make(map[string]string, len(vars)+2)
+			for k, v := range vars {
+				itemVars[k] = v
+			}
+			itemVars["item"] = value
+			itemVars["item_index"] = strconv.Itoa(n + 1)
+			byStep[i] = append(byStep[i], len(instances))
+			instances = append(instances, StepInstance{StepIndex: i, Vars: itemVars})
+		}
+	}
+
+	// resolve returns the instances standing in for step j, looking through
+	// skipped steps to what they depended on.
+	resolved := make(map[int][]int)
+	var resolve func(j int, visiting map[int]bool) []int
+	resolve = func(j int, visiting map[int]bool) []int {
+		if r, ok := resolved[j]; ok {
+			return r
+		}
+		if len(byStep[j]) > 0 {
+			return byStep[j]
+		}
+		if visiting[j] {
+			return nil
+		}
+		visiting[j] = true
+		var r []int
+		for _, k := range stepDeps[j] {
+			r = append(r, resolve(k, visiting)...)
+		}
+		resolved[j] = r
+		return r
+	}
+
+	for n := range instances {
+		seen := map[int]bool{n: true}
+		for _, j := range stepDeps[instances[n].StepIndex] {
+			for _, d := range resolve(j, map[int]bool{}) {
+				if !seen[d] {
+					seen[d] = true
+					instances[n].Depends = append(instances[n].Depends, d)
+				}
+			}
+		}
+	}
+	return instances, nil
+}
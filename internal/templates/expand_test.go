@@ -0,0 +1,122 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListValues(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a, b ,,c", []string{"a", "b", "c"}},
+		{"one, two\nthree\n\n", []string{"one, two", "three"}},
+	}
+	for _, tt := range tests {
+		if got := ListValues(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ListValues(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpand_WhenSkipsAndRewiresDeps(t *testing.T) {
+	steps := []Step{
+		{ID: "design"},
+		{ID: "review", When: "reviewer", Depends: []string{"design"}},
+		{ID: "implement", Depends: []string{"review"}},
+		{ID: "notes", When: "!reviewer"},
+	}
+	ids := []string{"design", "review", "implement", "notes"}
+
+	instances, err := Expand(steps, ids, map[string]string{"reviewer": ""})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	var stepIdx []int
+	for _, inst := range instances {
+		stepIdx = append(stepIdx, inst.StepIndex)
+	}
+	if !reflect.DeepEqual(stepIdx, []int{0, 2, 3}) {
+		t.Fatalf("expected steps [0 2 3], got %v", stepIdx)
+	}
+	// implement depended on the skipped review step, so it now waits on design
+	if !reflect.DeepEqual(instances[1].Depends, []int{0}) {
+		t.Errorf("implement deps = %v, want [0]", instances[1].Depends)
+	}
+
+	instances, err = Expand(steps, ids, map[string]string{"reviewer": "sam"})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if len(instances) != 3 || instances[1].StepIndex != 1 || !reflect.DeepEqual(instances[2].Depends, []int{1}) {
+		t.Errorf("unexpected instances with reviewer set: %+v", instances)
+	}
+}
+
+func TestExpand_ForEach(t *testing.T) {
+	steps := []Step{
+		{ID: "plan"},
+		{ID: "migrate", ForEach: "services", Depends: []string{"plan"}},
+		{ID: "verify", Depends: []string{"migrate"}},
+	}
+	vars := map[string]string{"services": "api, worker"}
+
+	instances, err := Expand(steps, []string{"plan", "migrate", "verify"}, vars)
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if len(instances) != 4 {
+		t.Fatalf("expected 4 instances, got %d", len(instances))
+	}
+	for n, want := range []string{"api", "worker"} {
+		inst := instances[n+1]
+		if inst.Vars["item"] != want || inst.Vars["item_index"] != string(rune('1'+n)) {
+			t.Errorf("instance %d vars = %v", n+1, inst.Vars)
+		}
+		if !reflect.DeepEqual(inst.Depends, []int{0}) {
+			t.Errorf("instance %d deps = %v, want [0]", n+1, inst.Depends)
+		}
+	}
+	if !reflect.DeepEqual(instances[3].Depends, []int{1, 2}) {
+		t.Errorf("verify deps = %v, want [1 2]", instances[3].Depends)
+	}
+	if _, ok := vars["item"]; ok {
+		t.Error("fan-out must not modify the shared vars")
+	}
+
+	if _, err := Expand([]Step{{ForEach: "missing"}}, []string{"x"}, map[string]string{}); err == nil {
+		t.Error("expected error for unknown for_each variable")
+	}
+}
+
+func TestExpand_VariableDepends(t *testing.T) {
+	steps := []Step{
+		{ID: "a"},
+		{ID: "b"},
+		{ID: "c", Depends: []string{"{{.after}}"}},
+	}
+	ids := []string{"a", "b", "c"}
+
+	instances, err := Expand(steps, ids, map[string]string{"after": "a,b"})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if !reflect.DeepEqual(instances[2].Depends, []int{0, 1}) {
+		t.Errorf("c deps = %v, want [0 1]", instances[2].Depends)
+	}
+
+	instances, err = Expand(steps, ids, map[string]string{"after": ""})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if len(instances[2].Depends) != 0 {
+		t.Errorf("empty variable should drop the dep, got %v", instances[2].Depends)
+	}
+
+	if _, err := Expand(steps, ids, map[string]string{"after": "nope"}); err == nil {
+		t.Error("expected error for unknown rendered step id")
+	}
+}
@@ -1,7 +1,6 @@
 package templates
 
 import (
-	"bufio"
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
@@ -13,6 +12,7 @@ import (
 	"text/template"
 
 	"github.com/pelletier/go-toml/v2"
+	"github.com/taxilian/tpg/internal/worktree"
 	"gopkg.in/yaml.v3"
 )
 
@@ -46,7 +46,9 @@ type Step struct {
 	ID          string   `yaml:"id" toml:"id"`
 	Title       string   `yaml:"title" toml:"title"`
 	Description string   `yaml:"description" toml:"description"`
-	Depends     []string `yaml:"depends" toml:"depends"`
+	Depends     []string `yaml:"depends" toml:"depends"`   // Step IDs; entries may use template syntax
+	When        string   `yaml:"when" toml:"when"`         // Variable name; step is skipped when it is empty ("!name" inverts)
+	ForEach     string   `yaml:"for_each" toml:"for_each"` // List variable; one task per element, exposed as .item
 }
 
 // TemplateLocation represents a directory that may contain templates.
@@ -169,55 +171,13 @@ func findWorktreeRoot() (string, error) {
 }
 
 // parseGitFile parses a .git file (used by worktrees) and extracts the main repo path.
-// The file format is: "gitdir: <path>" where path points to the main repo's .git directory.
+// The file format is: "gitdir: <path>" where path points to the main repo's .git directory
+// or to a worktree's directory inside it (.git/worktrees/<name>).
 func parseGitFile(gitFilePath string) (string, error) {
-	file, err := os.Open(gitFilePath)
+	_, repoRoot, _, err := worktree.ParseGitFile(gitFilePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open .git file: %w", err)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	if !scanner.Scan() {
-		if err := scanner.Err(); err != nil {
-			return "", fmt.Errorf("failed to read .git file: %w", err)
-		}
-		return "", fmt.Errorf(".git file is empty")
-	}
-
-	line := strings.TrimSpace(scanner.Text())
-
-	// Parse "gitdir: <path>" format
-	const prefix = "gitdir:"
-	if !strings.HasPrefix(line, prefix) {
-		return "", fmt.Errorf("malformed .git file: missing gitdir prefix")
-	}
-
-	gitDir := strings.TrimSpace(line[len(prefix):])
-	if gitDir == "" {
-		return "", fmt.Errorf("malformed .git file: empty gitdir path")
-	}
-
-	// Extract the main repo path from the gitdir path
-	// gitdir points to something like /path/to/repo/.git or /path/to/repo/.git/worktrees/myworktree
-	// We need to find the main repo root (parent of .git)
-
-	// If it's a worktrees path, go up to find the main .git directory
-	gitDir = filepath.Clean(gitDir)
-
-	// Walk up through "worktrees" directories to find the main .git
-	for strings.Contains(gitDir, "worktrees") {
-		parent := filepath.Dir(gitDir)
-		if parent == gitDir {
-			break
-		}
-		gitDir = parent
+		return "", err
 	}
-
-	// Now gitDir should be the main .git directory
-	// The repo root is the parent of .git
-	repoRoot := filepath.Dir(gitDir)
-
 	return repoRoot, nil
 }
 
@@ -313,6 +273,10 @@ func findTemplatePathInDir(dir, id string) (string, error) {
 		}
 	}
 
+	if path, ok := walkedPath(dir, id); ok {
+		return path, nil
+	}
+
 	// Search recursively through subdirectories
 	var foundPath string
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -334,12 +298,35 @@ func findTemplatePathInDir(dir, id string) (string, error) {
 	})
 
 	if foundPath != "" {
+		rememberWalkedPath(dir, id, foundPath)
 		return foundPath, nil
 	}
 	return "", fmt.Errorf("template not found: %s", id)
 }
 
+// loadTemplateFromPath loads the template at path, reusing an earlier parse
+// when the file is unchanged.
 func loadTemplateFromPath(path, id, source string) (*Template, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, ok := cachedLoad(path, info)
+	if !ok {
+		tmpl, err = parseTemplateFile(path)
+		if err != nil {
+			return nil, err
+		}
+		storeLoaded(path, info, tmpl)
+	}
+	tmpl.ID = id
+	tmpl.SourcePath = path
+	tmpl.Source = source
+	return tmpl, nil
+}
+
+// parseTemplateFile reads and validates a template file.
+func parseTemplateFile(path string) (*Template, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template: %w", err)
@@ -377,9 +364,6 @@ func loadTemplateFromPath(path, id, source string) (*Template, error) {
 		seen[step.ID] = true
 	}
 
-	tmpl.ID = id
-	tmpl.SourcePath = path
-	tmpl.Source = source
 	tmpl.Hash = hex.EncodeToString(hash[:])
 	if tmpl.Variables == nil {
 		tmpl.Variables = map[string]Variable{}
@@ -425,23 +409,81 @@ var templateFuncs = template.FuncMap{
 	},
 }
 
+// EpicFields are the epic fields available to templates as {{.Epic.<field>}}.
+var EpicFields = []string{"ID", "Title", "Description", "SharedContext", "ClosingInstructions"}
+
+// ContextVars returns the context variables for a template instantiated under
+// epic (which may be nil), plus project config values. They are stored with
+// the item's variables as "Epic.<field>" and "Config.<key>" so they render as
+// {{.Epic.Title}} and {{.Config.key}}; values are captured at instantiation.
+func ContextVars(epic map[string]string, config map[string]string) map[string]string {
+	vars := map[string]string{}
+	for _, field := range EpicFields {
+		vars["Epic."+field] = epic[field]
+	}
+	for k, v := range config {
+		vars["Config."+k] = v
+	}
+	return vars
+}
+
+// IsContextVar reports whether name is a context variable set by ContextVars
+// rather than a template variable.
+func IsContextVar(name string) bool {
+	return strings.HasPrefix(name, "Epic.") || strings.HasPrefix(name, "Config.")
+}
+
+// templateData converts vars to the value templates execute against. Dotted
+// context variables ("Epic.Title") become nested maps so they can be reached
+// as {{.Epic.Title}}. Without any, vars is used as is.
+func templateData(vars map[string]string) any {
+	nested := false
+	for k := range vars {
+		if IsContextVar(k) {
+			nested = true
+			break
+		}
+	}
+	if !nested {
+		return vars
+	}
+	data := make(map[string]any, len(vars))
+	groups := map[string]map[string]string{}
+	for k, v := range vars {
+		if IsContextVar(k) {
+			group, field, _ := strings.Cut(k, ".")
+			if groups[group] == nil {
+				groups[group] = map[string]string{}
+			}
+			groups[group][field] = v
+			continue
+		}
+		data[k] = v
+	}
+	for group, fields := range groups {
+		data[group] = fields
+	}
+	return data
+}
+
 // RenderText interpolates variables using Go's text/template.
 // Supports conditionals: {{if .var}}...{{end}}
 // Supports defaults: {{default "fallback" .var}}
 // Supports hasValue: {{if hasValue .var}}...{{end}}
+// Context variables from ContextVars are reachable as {{.Epic.Title}} etc.
 func RenderText(input string, vars map[string]string) string {
 	if vars == nil {
 		vars = map[string]string{}
 	}
 
-	tmpl, err := template.New("").Funcs(templateFuncs).Parse(input)
+	tmpl, err := parseText(input)
 	if err != nil {
 		// Return input unchanged if template parsing fails
 		return input
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, vars); err != nil {
+	if err := tmpl.Execute(&buf, templateData(vars)); err != nil {
 		// Return input unchanged on execution error
 		return input
 	}
@@ -456,5 +498,7 @@ func RenderStep(step Step, vars map[string]string) Step {
 		Title:       RenderText(step.Title, vars),
 		Description: RenderText(step.Description, vars),
 		Depends:     step.Depends,
+		When:        step.When,
+		ForEach:     step.ForEach,
 	}
 }
@@ -71,6 +71,7 @@ func (m Model) activeFiltersString() string {
 		model.StatusOpen,
 		model.StatusInProgress,
 		model.StatusBlocked,
+		model.StatusPendingReview,
 		model.StatusDone,
 		model.StatusCanceled,
 	}
@@ -79,7 +80,7 @@ func (m Model) activeFiltersString() string {
 			statuses = append(statuses, statusText(s))
 		}
 	}
-	if len(statuses) < 5 {
+	if len(statuses) < len(statusOrder) {
 		parts = append(parts, "status:"+strings.Join(statuses, ","))
 	}
 
@@ -111,6 +111,14 @@ func truncateWidth(s string, maxWidth int) string {
 	return "..."
 }
 
+// padRight pads s with spaces to width display columns.
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
 func trimLastRune(text string) string {
 	if text == "" {
 		return text
@@ -144,6 +152,8 @@ func statusIcon(s model.Status) string {
 		return iconBlocked
 	case model.StatusCanceled:
 		return iconCanceled
+	case model.StatusPendingReview:
+		return iconPendingReview
 	default:
 		return "?"
 	}
@@ -162,6 +172,8 @@ func statusText(s model.Status) string {
 		return "block"
 	case model.StatusCanceled:
 		return "cancel"
+	case model.StatusPendingReview:
+		return "review"
 	default:
 		return "?"
 	}
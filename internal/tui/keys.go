@@ -14,6 +14,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/editor"
 	"github.com/taxilian/tpg/internal/model"
 )
 
@@ -61,6 +62,9 @@ var listBindings = struct {
 	BatchStatus    key.Binding
 	BatchPriority  key.Binding
 	BatchDone      key.Binding
+	BatchLabel     key.Binding
+	BatchMove      key.Binding
+	BatchCancel    key.Binding
 	Start          key.Binding
 	Done           key.Binding
 	Project        key.Binding
@@ -83,6 +87,7 @@ var listBindings = struct {
 	New            key.Binding
 	Templates      key.Binding
 	Config         key.Binding
+	Projects       key.Binding
 }{
 	Up:             key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
 	Down:           key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
@@ -100,6 +105,9 @@ var listBindings = struct {
 	BatchStatus:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "batch status")),
 	BatchPriority:  key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "batch priority")),
 	BatchDone:      key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "batch done")),
+	BatchLabel:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "batch label")),
+	BatchMove:      key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "move to epic")),
+	BatchCancel:    key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "batch cancel")),
 	Start:          key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "start")),
 	Done:           key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "done")),
 	Project:        key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "project")),
@@ -122,6 +130,7 @@ var listBindings = struct {
 	New:            key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new")),
 	Templates:      key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "templates")),
 	Config:         key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "config")),
+	Projects:       key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "projects")),
 }
 
 var detailBindings = struct {
@@ -182,6 +191,22 @@ var graphBindings = struct {
 	Jump: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "jump")),
 }
 
+var projectBindings = struct {
+	Left    key.Binding
+	Right   key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Open    key.Binding
+	Refresh key.Binding
+}{
+	Left:    key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")),
+	Right:   key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "right")),
+	Up:      key.NewBinding(key.WithKeys("k", "up"), key.WithHelp("↑/k", "up")),
+	Down:    key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("↓/j", "down")),
+	Open:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+	Refresh: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+}
+
 var templateListBindings = struct {
 	Up           key.Binding
 	Down         key.Binding
@@ -260,6 +285,22 @@ var textareaBindings = struct {
 	ExternalEditor: key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "external editor")),
 }
 
+var pickerBindings = struct {
+	Up     key.Binding
+	Down   key.Binding
+	Scope  key.Binding
+	AddDep key.Binding
+	Move   key.Binding
+	Cancel key.Binding
+}{
+	Up:     key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp("↑", "up")),
+	Down:   key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp("↓", "down")),
+	Scope:  key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "epic/project")),
+	AddDep: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "add blocker")),
+	Move:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "move")),
+	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+}
+
 var statusMenuBindings = struct {
 	Up      key.Binding
 	Down    key.Binding
@@ -343,7 +384,10 @@ func (m Model) canToggleHelp() bool {
 		return false
 	}
 	if m.viewMode == ViewCreateWizard {
-		if m.createWizardStep == 2 || m.isWizardDescriptionStep() {
+		if m.createWizardState.FromTemplate && m.createWizardStep > 1 {
+			return m.createWizardStep == 2
+		}
+		if m.createWizardStep == 2 || m.isWizardTextareaStep() {
 			return false
 		}
 		if m.createWizardStep == 3 && m.createWizardState.SelectedType == model.ItemTypeEpic && m.createWizardState.UseWorktree {
@@ -372,6 +416,18 @@ func (m Model) currentHelpKeyMap() help.KeyMap {
 			full:  [][]key.Binding{{textareaBindings.Save, textareaBindings.ExternalEditor}, {textareaBindings.Cancel}},
 		}
 	}
+	if m.inputMode == InputAddDep {
+		return helpKeyMap{
+			short: []key.Binding{pickerBindings.Up, pickerBindings.Down, pickerBindings.Scope, pickerBindings.AddDep, pickerBindings.Cancel},
+			full:  [][]key.Binding{{pickerBindings.Up, pickerBindings.Down}, {pickerBindings.Scope, pickerBindings.AddDep, pickerBindings.Cancel}},
+		}
+	}
+	if m.inputMode == InputMoveEpic {
+		return helpKeyMap{
+			short: []key.Binding{pickerBindings.Up, pickerBindings.Down, pickerBindings.Move, pickerBindings.Cancel},
+			full:  [][]key.Binding{{pickerBindings.Up, pickerBindings.Down}, {pickerBindings.Move, pickerBindings.Cancel}},
+		}
+	}
 	if m.inputMode == InputStatusMenu {
 		return helpKeyMap{
 			short: []key.Binding{statusMenuBindings.Up, statusMenuBindings.Down, statusMenuBindings.Confirm, statusMenuBindings.Cancel, m.toggleHelpBinding()},
@@ -391,12 +447,17 @@ func (m Model) currentHelpKeyMap() help.KeyMap {
 				short: []key.Binding{wizardTypeBindings.Up, wizardTypeBindings.Down, wizardTypeBindings.Select, wizardTypeBindings.Cancel, m.toggleHelpBinding()},
 				full:  [][]key.Binding{{wizardTypeBindings.Up, wizardTypeBindings.Down, wizardTypeBindings.Select}, {wizardTypeBindings.Cancel, m.toggleHelpBinding()}},
 			}
-		case m.createWizardStep == 2:
+		case m.createWizardState.FromTemplate && m.createWizardStep == 2:
+			return helpKeyMap{
+				short: []key.Binding{wizardTypeBindings.Up, wizardTypeBindings.Down, wizardTypeBindings.Select, wizardTitleBindings.Cancel, m.toggleHelpBinding()},
+				full:  [][]key.Binding{{wizardTypeBindings.Up, wizardTypeBindings.Down, wizardTypeBindings.Select}, {wizardTitleBindings.Cancel, m.toggleHelpBinding()}},
+			}
+		case m.createWizardStep == 2, m.createWizardState.FromTemplate:
 			return helpKeyMap{
 				short: []key.Binding{wizardTitleBindings.Continue, wizardTitleBindings.Cancel},
 				full:  [][]key.Binding{{wizardTitleBindings.Continue, wizardTitleBindings.Cancel}},
 			}
-		case m.isWizardDescriptionStep():
+		case m.isWizardTextareaStep():
 			return helpKeyMap{
 				short: []key.Binding{wizardDescriptionBindings.Continue, wizardDescriptionBindings.Cancel},
 				full:  [][]key.Binding{{wizardDescriptionBindings.Continue, wizardDescriptionBindings.Cancel}},
@@ -414,22 +475,22 @@ func (m Model) currentHelpKeyMap() help.KeyMap {
 	case ViewList:
 		if m.selectMode {
 			return helpKeyMap{
-				short: []key.Binding{listBindings.Up, listBindings.Down, listBindings.ToggleSelected, listBindings.BatchStatus, listBindings.BatchPriority, listBindings.BatchDone, listBindings.SelectMode, listBindings.ClearFilters, appBindings.Quit, m.toggleHelpBinding()},
+				short: []key.Binding{listBindings.Up, listBindings.Down, listBindings.ToggleSelected, listBindings.BatchStatus, listBindings.BatchPriority, listBindings.BatchDone, listBindings.BatchLabel, listBindings.BatchMove, listBindings.BatchCancel, listBindings.SelectMode, listBindings.ClearFilters, appBindings.Quit, m.toggleHelpBinding()},
 				full: [][]key.Binding{
 					{listBindings.Up, listBindings.Down, listBindings.HalfPageUp, listBindings.HalfPageDown, listBindings.PageUp, listBindings.PageDown, listBindings.Top, listBindings.End},
-					{listBindings.ToggleSelected, listBindings.BatchStatus, listBindings.BatchPriority, listBindings.BatchDone, listBindings.SelectMode},
+					{listBindings.ToggleSelected, listBindings.BatchStatus, listBindings.BatchPriority, listBindings.BatchDone, listBindings.BatchLabel, listBindings.BatchMove, listBindings.BatchCancel, listBindings.SelectMode},
 					{listBindings.Search, listBindings.Label, listBindings.Ready, listBindings.StatusOpen, listBindings.StatusProgress, listBindings.StatusBlocked, listBindings.StatusDone, listBindings.StatusCanceled, listBindings.StatusAll, listBindings.ClearFilters},
 					{listBindings.Refresh, appBindings.Quit, m.toggleHelpBinding()},
 				},
 			}
 		}
 		return helpKeyMap{
-			short: []key.Binding{listBindings.Up, listBindings.Down, listBindings.HalfPageUp, listBindings.HalfPageDown, listBindings.PageUp, listBindings.PageDown, listBindings.Top, listBindings.End, listBindings.Detail, listBindings.Start, listBindings.Done, listBindings.New, listBindings.Search, listBindings.Project, listBindings.Label, listBindings.Ready, listBindings.Block, listBindings.Log, listBindings.Cancel, listBindings.Delete, listBindings.Templates, listBindings.Config, listBindings.Refresh, appBindings.Quit, m.toggleHelpBinding()},
+			short: []key.Binding{listBindings.Up, listBindings.Down, listBindings.HalfPageUp, listBindings.HalfPageDown, listBindings.PageUp, listBindings.PageDown, listBindings.Top, listBindings.End, listBindings.Detail, listBindings.Start, listBindings.Done, listBindings.New, listBindings.Search, listBindings.Project, listBindings.Label, listBindings.Ready, listBindings.Block, listBindings.Log, listBindings.Cancel, listBindings.Delete, listBindings.Templates, listBindings.Projects, listBindings.Config, listBindings.Refresh, appBindings.Quit, m.toggleHelpBinding()},
 			full: [][]key.Binding{
 				{listBindings.Up, listBindings.Down, listBindings.HalfPageUp, listBindings.HalfPageDown, listBindings.PageUp, listBindings.PageDown, listBindings.Top, listBindings.End, listBindings.Expand, listBindings.Collapse, listBindings.Detail},
 				{listBindings.Start, listBindings.Done, listBindings.Block, listBindings.Log, listBindings.Cancel, listBindings.Delete, listBindings.AddDep, listBindings.New, listBindings.SelectMode},
 				{listBindings.Search, listBindings.Project, listBindings.Label, listBindings.Ready, listBindings.StatusOpen, listBindings.StatusProgress, listBindings.StatusBlocked, listBindings.StatusDone, listBindings.StatusCanceled, listBindings.StatusAll, listBindings.ClearFilters},
-				{listBindings.Templates, listBindings.Config, listBindings.Refresh, appBindings.Quit, m.toggleHelpBinding()},
+				{listBindings.Templates, listBindings.Projects, listBindings.Config, listBindings.Refresh, appBindings.Quit, m.toggleHelpBinding()},
 			},
 		}
 	case ViewDetail:
@@ -471,6 +532,11 @@ func (m Model) currentHelpKeyMap() help.KeyMap {
 			short: []key.Binding{configBindings.Up, configBindings.Down, configBindings.Edit, configBindings.Refresh, appBindings.Back, appBindings.Quit, m.toggleHelpBinding()},
 			full:  [][]key.Binding{{configBindings.Up, configBindings.Down, configBindings.Top, configBindings.End}, {configBindings.Edit, configBindings.Refresh}, {appBindings.Back, appBindings.Quit, m.toggleHelpBinding()}},
 		}
+	case ViewProjects:
+		return helpKeyMap{
+			short: []key.Binding{projectBindings.Left, projectBindings.Right, projectBindings.Up, projectBindings.Down, projectBindings.Open, projectBindings.Refresh, appBindings.Back, appBindings.Quit, m.toggleHelpBinding()},
+			full:  [][]key.Binding{{projectBindings.Left, projectBindings.Right, projectBindings.Up, projectBindings.Down}, {projectBindings.Open, projectBindings.Refresh}, {appBindings.Back, appBindings.Quit, m.toggleHelpBinding()}},
+		}
 	case ViewVariablePicker:
 		return helpKeyMap{
 			short: []key.Binding{variablePickerBindings.Up, variablePickerBindings.Down, variablePickerBindings.Edit, appBindings.Back, m.toggleHelpBinding()},
@@ -511,6 +577,10 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleStatusMenuKey(msg)
 	}
 
+	if m.inputMode == InputAddDep || m.inputMode == InputMoveEpic {
+		return m.handlePickerKey(msg)
+	}
+
 	// Handle other input modes
 	if m.inputMode != InputNone {
 		return m.handleInputKey(msg)
@@ -533,6 +603,8 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleCreateWizardKey(msg)
 	case ViewVariablePicker:
 		return m.handleVariablePickerKey(msg)
+	case ViewProjects:
+		return m.handleProjectsKey(msg)
 	}
 	return m, nil
 }
@@ -747,18 +819,6 @@ func (m Model) submitInput() (tea.Model, tea.Cmd) {
 			return actionMsg{message: fmt.Sprintf("Canceled %s", item.ID)}
 		}
 
-	case InputAddDep:
-		if text == "" {
-			return m, nil
-		}
-		return m, func() tea.Msg {
-			// text blocks current item
-			if err := m.db.AddDep(item.ID, text); err != nil {
-				return actionMsg{err: err}
-			}
-			return actionMsg{message: fmt.Sprintf("%s now blocks %s", text, item.ID)}
-		}
-
 	case InputBatchStatus:
 		result, cmd := m.doBatchStatus(text)
 		// Exit select mode after batch operation
@@ -772,6 +832,21 @@ func (m Model) submitInput() (tea.Model, tea.Cmd) {
 		result.selectMode = false
 		result.selectedItems = make(map[string]bool)
 		return result, cmd
+
+	case InputBatchLabel:
+		if text == "" {
+			return m, nil
+		}
+		result, cmd := m.doBatchLabel(text)
+		result.selectMode = false
+		result.selectedItems = make(map[string]bool)
+		return result, cmd
+
+	case InputBatchCancel:
+		result, cmd := m.doBatchCancel(text)
+		result.selectMode = false
+		result.selectedItems = make(map[string]bool)
+		return result, cmd
 	}
 
 	return m, nil
@@ -793,7 +868,8 @@ func (m *Model) activePromptInput() *textinput.Model {
 		return &m.projectInput
 	case InputLabel:
 		return &m.labelInput
-	case InputBlock, InputLog, InputCancel, InputAddDep, InputCreate, InputCreateType, InputBatchStatus, InputBatchPriority:
+	case InputBlock, InputLog, InputCancel, InputAddDep, InputCreate, InputCreateType, InputBatchStatus, InputBatchPriority,
+		InputBatchLabel, InputBatchCancel, InputMoveEpic:
 		return &m.promptInput
 	default:
 		return nil
@@ -1094,8 +1170,8 @@ func (m Model) switchToExternalEditor() (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	editor := getEditor()
-	c := exec.Command(editor, tmpPath)
+	name, args := editor.Command(editor.Resolve(), tmpPath)
+	c := exec.Command(name, args...)
 
 	return m, tea.ExecProcess(c, func(err error) tea.Msg {
 		return editorFinishedMsg{
@@ -1222,20 +1298,9 @@ func (m Model) doBatchPriority(priorityStr string) (Model, tea.Cmd) {
 	if len(m.selectedItems) == 0 {
 		return m, nil
 	}
-	var priority int
-	switch priorityStr {
-	case "1":
-		priority = 1
-	case "2":
-		priority = 2
-	case "3":
-		priority = 3
-	case "4":
-		priority = 4
-	case "5":
-		priority = 5
-	default:
-		m.message = "Invalid priority: use 1-5"
+	priority, err := model.ParsePriority(priorityStr)
+	if err != nil {
+		m.message = "Invalid priority: use 1-5 or high, medium, low"
 		return m, nil
 	}
 	selectedIDs := make([]string, 0, len(m.selectedItems))
@@ -1250,26 +1315,75 @@ func (m Model) doBatchPriority(priorityStr string) (Model, tea.Cmd) {
 			}
 			count++
 		}
-		return actionMsg{message: fmt.Sprintf("Set priority %d on %d items", priority, count)}
+		return actionMsg{message: fmt.Sprintf("Set priority %s on %d items", model.PriorityName(priority), count)}
 	}
 }
 
-// getEditor returns the editor command to use.
-// Prefers $TPG_EDITOR, then $EDITOR, then nvim, nano, vi.
-func getEditor() string {
-	if editor := os.Getenv("TPG_EDITOR"); editor != "" {
-		return editor
+// doBatchLabel adds a label to every selected item.
+func (m Model) doBatchLabel(label string) (Model, tea.Cmd) {
+	ids := m.selectedIDs()
+	if len(ids) == 0 {
+		return m, nil
 	}
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
+	projects := make(map[string]string, len(m.items))
+	for _, item := range m.items {
+		projects[item.ID] = item.Project
 	}
-	if _, err := exec.LookPath("nvim"); err == nil {
-		return "nvim"
+	return m, func() tea.Msg {
+		for _, id := range ids {
+			if err := m.db.AddLabelToItem(id, projects[id], label); err != nil {
+				return actionMsg{err: fmt.Errorf("failed to label %s: %w", id, err)}
+			}
+		}
+		return actionMsg{message: fmt.Sprintf("Added label %s to %d items", label, len(ids))}
 	}
-	if _, err := exec.LookPath("nano"); err == nil {
-		return "nano"
+}
+
+// doBatchCancel cancels every selected item, logging the reason if given.
+func (m Model) doBatchCancel(reason string) (Model, tea.Cmd) {
+	ids := m.selectedIDs()
+	if len(ids) == 0 {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		for _, id := range ids {
+			if err := m.db.UpdateStatus(id, model.StatusCanceled, db.AgentContext{}, false); err != nil {
+				return actionMsg{err: fmt.Errorf("failed to cancel %s: %w", id, err)}
+			}
+			if reason != "" {
+				if err := m.db.AddLog(id, "Canceled: "+reason); err != nil {
+					return actionMsg{err: err}
+				}
+			}
+		}
+		return actionMsg{message: fmt.Sprintf("Canceled %d items", len(ids))}
+	}
+}
+
+// doBatchMove moves every selected item under the given epic, or to the
+// top level when epicID is empty.
+func (m Model) doBatchMove(epicID string) (Model, tea.Cmd) {
+	ids := m.selectedIDs()
+	if len(ids) == 0 {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		for _, id := range ids {
+			var err error
+			if epicID == "" {
+				err = m.db.ClearParent(id)
+			} else {
+				err = m.db.SetParent(id, epicID)
+			}
+			if err != nil {
+				return actionMsg{err: fmt.Errorf("failed to move %s: %w", id, err)}
+			}
+		}
+		if epicID == "" {
+			return actionMsg{message: fmt.Sprintf("Moved %d items to the top level", len(ids))}
+		}
+		return actionMsg{message: fmt.Sprintf("Moved %d items to %s", len(ids), epicID)}
 	}
-	return "vi"
 }
 
 // handleEditorFinished processes the result of an external editor session.
@@ -24,6 +24,7 @@ const (
 	ViewConfig
 	ViewCreateWizard
 	ViewVariablePicker
+	ViewProjects
 )
 
 // InputMode represents what kind of text input is active.
@@ -46,22 +47,26 @@ const (
 	InputSearch                  // Entering search text
 	InputProject                 // Entering project filter
 	InputLabel                   // Entering label filter
-	InputAddDep                  // Entering dependency ID to add
+	InputAddDep                  // Picking an item to add as a blocker
 	InputCreate                  // Entering new item title
 	InputCreateType              // Entering type for new item
 	InputBatchStatus             // Entering status for batch change
 	InputBatchPriority           // Entering priority for batch change
+	InputBatchLabel              // Entering label to add to selected items
+	InputBatchCancel             // Entering reason for canceling selected items
+	InputMoveEpic                // Picking an epic for the selected items
 	InputTextarea                // Multi-line textarea editing
 	InputStatusMenu              // Status change confirmation menu
 )
 
 // Status icons
 const (
-	iconOpen       = "○"
-	iconInProgress = "◐"
-	iconDone       = "●"
-	iconBlocked    = "⊘"
-	iconCanceled   = "✗"
+	iconOpen          = "○"
+	iconInProgress    = "◐"
+	iconDone          = "●"
+	iconBlocked       = "⊘"
+	iconCanceled      = "✗"
+	iconPendingReview = "◎"
 )
 
 // Model is the main Bubble Tea model for the TUI.
@@ -150,6 +155,14 @@ type Model struct {
 	// Status menu state
 	statusMenuCursor int // 0=start, 1=done, 2=block, 3=cancel
 
+	// Item picker state (dependency and epic pickers)
+	pickerCursor      int
+	depPickerEpicOnly bool // limit blocker candidates to the target's epic
+
+	// Project picker state
+	projects      []projectSummary
+	projectCursor int
+
 	// Config view state
 	configFields  []db.ConfigField
 	configCursor  int
@@ -162,6 +175,7 @@ type Model struct {
 	// Create wizard state
 	createWizardStep  int
 	createWizardState CreateWizardState
+	instantiate       TemplateInstantiator // nil when templates can't be instantiated
 
 	promptInput       textinput.Model
 	searchInput       textinput.Model
@@ -171,9 +185,15 @@ type Model struct {
 	wizardTitleInput  textinput.Model
 	wizardBranchInput textinput.Model
 	wizardBaseInput   textinput.Model
+	wizardVarInput    textinput.Model
 	inputOriginal     string
 }
 
+// TemplateInstantiator creates the items for a template and returns the ID
+// of the top-level item. The CLI supplies it so the TUI creates items from
+// templates exactly as "tpg add --template" does.
+type TemplateInstantiator func(project, title, templateID string, vars map[string]string) (string, error)
+
 // CreateWizardState holds all data during item creation
 type CreateWizardState struct {
 	// Step 1: Type (popup)
@@ -192,6 +212,19 @@ type CreateWizardState struct {
 
 	// Step 3/4: Description
 	Description string
+
+	// Steps 5-6 (epic only): Shared context and closing instructions
+	Context string
+	OnClose string
+
+	// Template flow: step 2 picks the template, step 3 is the title, and
+	// step 4 prompts for each variable in turn.
+	FromTemplate   bool
+	Template       *templates.Template
+	TemplateCursor int
+	VarNames       []string
+	VarIndex       int
+	Vars           map[string]string
 }
 
 // TypeOption represents an available item type with metadata.
@@ -199,6 +232,8 @@ type TypeOption struct {
 	Type   model.ItemType
 	Prefix string // e.g., "ts", "ep", "bg"
 	Desc   string // optional description
+
+	Template bool // the "from template" entry rather than a type
 }
 
 // graphNode represents a task in the dependency graph view.
@@ -222,11 +257,12 @@ type treeNode struct {
 func New(database *db.DB, project string) Model {
 	// Default: show open, in_progress, blocked
 	statuses := map[model.Status]bool{
-		model.StatusOpen:       true,
-		model.StatusInProgress: true,
-		model.StatusBlocked:    true,
-		model.StatusDone:       false,
-		model.StatusCanceled:   false,
+		model.StatusOpen:          true,
+		model.StatusInProgress:    true,
+		model.StatusBlocked:       true,
+		model.StatusPendingReview: true,
+		model.StatusDone:          false,
+		model.StatusCanceled:      false,
 	}
 
 	// Initialize textarea for multi-line editing
@@ -244,6 +280,7 @@ func New(database *db.DB, project string) Model {
 	wizardTitleInput := newTextInput("Enter title")
 	wizardBranchInput := newTextInput("feature/ep-xxx-title")
 	wizardBaseInput := newTextInput("main")
+	wizardVarInput := newTextInput("")
 
 	return Model{
 		db:                     database,
@@ -272,6 +309,7 @@ func New(database *db.DB, project string) Model {
 		wizardTitleInput:  wizardTitleInput,
 		wizardBranchInput: wizardBranchInput,
 		wizardBaseInput:   wizardBaseInput,
+		wizardVarInput:    wizardVarInput,
 		help:              newHelpModel(),
 	}
 }
@@ -19,11 +19,20 @@ var (
 				Background(lipgloss.Color("57"))
 
 	statusColors = map[model.Status]lipgloss.Color{
-		model.StatusOpen:       lipgloss.Color("252"),
-		model.StatusInProgress: lipgloss.Color("214"),
-		model.StatusBlocked:    lipgloss.Color("196"),
-		model.StatusDone:       lipgloss.Color("42"),
-		model.StatusCanceled:   lipgloss.Color("245"),
+		model.StatusOpen:          lipgloss.Color("252"),
+		model.StatusInProgress:    lipgloss.Color("214"),
+		model.StatusBlocked:       lipgloss.Color("196"),
+		model.StatusDone:          lipgloss.Color("42"),
+		model.StatusCanceled:      lipgloss.Color("245"),
+		model.StatusPendingReview: lipgloss.Color("39"),
+	}
+
+	priorityColors = map[int]lipgloss.Color{
+		1: lipgloss.Color("196"),
+		2: lipgloss.Color("214"),
+		3: lipgloss.Color("252"),
+		4: lipgloss.Color("245"),
+		5: lipgloss.Color("241"),
 	}
 
 	helpStyle = lipgloss.NewStyle().
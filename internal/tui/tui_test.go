@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/taxilian/tpg/internal/db"
 	"github.com/taxilian/tpg/internal/model"
 	"github.com/taxilian/tpg/internal/templates"
 )
@@ -490,3 +491,282 @@ func TestWizardTitleTextInputFlow(t *testing.T) {
 		t.Fatalf("wizard title = %q, want %q after continue", m.createWizardState.Title, "New")
 	}
 }
+
+func TestWizardTitlePolicy(t *testing.T) {
+	m := newTestModel()
+	m.viewMode = ViewCreateWizard
+	m.createWizardStep = 2
+	m.createWizardState.SelectedType = model.ItemTypeTask
+	_ = m.focusWizardTitleInput()
+	m.wizardTitleInput.SetValue(strings.Repeat("x", db.DefaultMaxTitleLength+1))
+
+	updated, _ := m.handleCreateWizardKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.createWizardStep != 2 {
+		t.Fatalf("createWizardStep = %d, want to stay on the title step", m.createWizardStep)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "characters") {
+		t.Errorf("err = %v, want the title policy error", m.err)
+	}
+}
+
+func TestWizardEpicContextSteps(t *testing.T) {
+	m := newTestModel()
+	m.viewMode = ViewCreateWizard
+	m.createWizardStep = 4
+	m.createWizardState.SelectedType = model.ItemTypeEpic
+	m.createWizardState.Title = "Epic"
+	m.createWizardState.Description = "Rework the storage layer"
+	m, _ = m.startWizardTextarea()
+
+	updated, _ := m.handleCreateWizardKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if m.createWizardStep != 5 || !strings.Contains(m.createWizardView(), "Shared context") {
+		t.Fatalf("expected the shared context step, got step %d", m.createWizardStep)
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			updated, _ = m.handleCreateWizardKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			m = updated.(Model)
+		}
+	}
+	typeText("Use sqlite")
+	updated, _ = m.handleCreateWizardKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if m.createWizardStep != 6 || m.createWizardState.Context != "Use sqlite" {
+		t.Fatalf("step = %d, context = %q", m.createWizardStep, m.createWizardState.Context)
+	}
+	typeText("Merge")
+	if m.createWizardState.OnClose != "Merge" {
+		t.Fatalf("on close = %q", m.createWizardState.OnClose)
+	}
+
+	// Going back restores the earlier text.
+	updated, _ = m.handleCreateWizardKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.createWizardStep != 5 || m.textarea.Value() != "Use sqlite" {
+		t.Fatalf("step = %d, textarea = %q", m.createWizardStep, m.textarea.Value())
+	}
+}
+
+func TestWizardTemplateFlow(t *testing.T) {
+	var gotTitle, gotTemplate string
+	var gotVars map[string]string
+	m := newTestModel()
+	m.instantiate = func(project, title, templateID string, vars map[string]string) (string, error) {
+		gotTitle, gotTemplate, gotVars = title, templateID, vars
+		return "ep-new", nil
+	}
+	m.viewMode = ViewCreateWizard
+	m.createWizardStep = 1
+	types := m.getAvailableTypes()
+	m.createWizardState.TypeCursor = len(types) - 1
+	if !types[len(types)-1].Template {
+		t.Fatalf("expected the last type option to be the template entry")
+	}
+
+	updated, _ := m.handleCreateWizardKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.createWizardState.FromTemplate || m.createWizardStep != 2 {
+		t.Fatalf("expected the template picker, got step %d", m.createWizardStep)
+	}
+	m.templates = []*templates.Template{
+		{ID: "bugfix", Title: "Bug fix"},
+		{ID: "feature", Title: "Feature", Variables: map[string]templates.Variable{
+			"goal":  {Description: "What to build"},
+			"notes": {Optional: true},
+			"scope": {Default: "api"},
+		}},
+	}
+	send := func(msgs ...tea.KeyMsg) {
+		for _, msg := range msgs {
+			updated, _ = m.handleCreateWizardKey(msg)
+			m = updated.(Model)
+		}
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.createWizardStep != 3 || m.createWizardState.Template.ID != "feature" {
+		t.Fatalf("expected the title step for feature, got step %d", m.createWizardStep)
+	}
+	typeText("Search")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := strings.Join(m.createWizardState.VarNames, ","); got != "goal,scope,notes" {
+		t.Fatalf("variable order = %s", got)
+	}
+	if view := m.createWizardView(); !strings.Contains(view, "Variable 1 of 3: goal") {
+		t.Errorf("expected the first variable prompt:\n%s", view)
+	}
+
+	// Required variables must be answered.
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err == nil || m.createWizardState.VarIndex != 0 {
+		t.Fatalf("expected an error for the empty required variable")
+	}
+	m.err = nil
+	typeText("full text")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.wizardVarInput.Value() != "api" {
+		t.Errorf("scope should be prefilled with its default, got %q", m.wizardVarInput.Value())
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.viewMode != ViewList || gotTitle != "Search" || gotTemplate != "feature" {
+		t.Fatalf("expected the template to be instantiated, view = %d title = %q", m.viewMode, gotTitle)
+	}
+	if len(gotVars) != 2 || gotVars["goal"] != "full text" || gotVars["scope"] != "api" {
+		t.Errorf("vars = %v", gotVars)
+	}
+}
+
+func TestProjectPicker(t *testing.T) {
+	m := newTestModel()
+	m.project = "beta"
+
+	updated, _ := m.Update(projectsMsg{projects: []projectSummary{
+		{Name: "", Status: &db.StatusReport{Open: 5}},
+		{Name: "alpha", Status: &db.StatusReport{Open: 2, Ready: 1}},
+		{Name: "beta", Status: &db.StatusReport{Open: 3, InProgress: 1}},
+	}})
+	m = updated.(Model)
+	m.viewMode = ViewProjects
+
+	if m.projectCursor != 2 {
+		t.Fatalf("projectCursor = %d, want current project (2)", m.projectCursor)
+	}
+
+	view := m.projectsView()
+	for _, want := range []string{"All projects", "alpha", "beta •", "ready 1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("projects view missing %q:\n%s", want, view)
+		}
+	}
+
+	updated, _ = m.handleProjectsKey(tea.KeyMsg{Type: tea.KeyLeft})
+	m = updated.(Model)
+	updated, cmd := m.handleProjectsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.project != "alpha" {
+		t.Errorf("project = %q, want alpha", m.project)
+	}
+	if m.viewMode != ViewList {
+		t.Errorf("viewMode = %v, want list", m.viewMode)
+	}
+	if cmd == nil {
+		t.Error("expected a reload command after switching project")
+	}
+}
+
+func TestDepPicker(t *testing.T) {
+	epic := "ep-1"
+	items := []model.Item{
+		{ID: "ts-target", Project: "p", Title: "Target", Status: model.StatusOpen, ParentID: &epic},
+		{ID: "ep-1", Project: "p", Type: model.ItemTypeEpic, Title: "Epic", Status: model.StatusOpen},
+		{ID: "ts-alpha", Project: "p", Title: "Alpha sibling", Status: model.StatusOpen, ParentID: &epic},
+		{ID: "ts-beta", Project: "p", Title: "Beta sibling", Status: model.StatusInProgress, ParentID: &epic},
+		{ID: "ts-done", Project: "p", Title: "Done sibling", Status: model.StatusDone, ParentID: &epic},
+		{ID: "ts-loose", Project: "p", Title: "Loose task", Status: model.StatusOpen},
+		{ID: "ts-other", Project: "q", Title: "Other project", Status: model.StatusOpen},
+	}
+	m := newTestModel(items...)
+	m.treeExpanded["ep-1"] = true
+	for i, node := range m.buildTree() {
+		if node.Item.ID == "ts-target" {
+			m.cursor = i
+		}
+	}
+	if target, _ := m.depPickerTarget(); target.ID != "ts-target" {
+		t.Fatalf("cursor not on target, got %s", target.ID)
+	}
+
+	m, _ = m.startDepPicker()
+	if m.inputMode != InputAddDep || !m.depPickerEpicOnly {
+		t.Fatalf("expected the picker open and scoped to the epic")
+	}
+	if got := ids(m.pickerCandidates()); got != "ts-alpha,ts-beta" {
+		t.Errorf("epic candidates = %s", got)
+	}
+
+	updated, _ := m.handlePickerKey(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if got := ids(m.pickerCandidates()); got != "ep-1,ts-alpha,ts-beta,ts-loose" {
+		t.Errorf("project candidates = %s", got)
+	}
+
+	for _, r := range "beta" {
+		updated, _ = m.handlePickerKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	if got := ids(m.pickerCandidates()); got != "ts-beta" {
+		t.Errorf("filtered candidates = %s", got)
+	}
+	if view := m.pickerView(); !strings.Contains(view, "▸ ◐ ts-beta Beta sibling") {
+		t.Errorf("picker view should highlight the match:\n%s", view)
+	}
+
+	updated, _ = m.handlePickerKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.inputMode != InputNone {
+		t.Errorf("expected esc to close the picker")
+	}
+}
+
+func TestBatchSelection(t *testing.T) {
+	items := []model.Item{
+		{ID: "ep-1", Project: "p", Type: model.ItemTypeEpic, Title: "Epic", Status: model.StatusOpen},
+		{ID: "ep-2", Project: "p", Type: model.ItemTypeEpic, Title: "Closed epic", Status: model.StatusDone},
+		{ID: "ts-a", Project: "p", Title: "Task A", Status: model.StatusOpen},
+		{ID: "ts-b", Project: "p", Title: "Task B", Status: model.StatusOpen},
+		{ID: "ep-3", Project: "q", Type: model.ItemTypeEpic, Title: "Other project", Status: model.StatusOpen},
+	}
+	m := newTestModel(items...)
+	for i, node := range m.buildTree() {
+		if node.Item.ID == "ts-a" {
+			m.cursor = i
+		}
+	}
+
+	updated, _ := m.handleListKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = updated.(Model)
+	if !m.selectMode || !m.selectedItems["ts-a"] {
+		t.Fatalf("space should enter select mode and mark the item")
+	}
+	m.selectedItems["ts-b"] = true
+	if got := strings.Join(m.selectedIDs(), ","); got != "ts-a,ts-b" {
+		t.Errorf("selectedIDs = %s", got)
+	}
+
+	updated, _ = m.handleListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = updated.(Model)
+	if m.inputMode != InputMoveEpic {
+		t.Fatalf("m should open the epic picker, got mode %d", m.inputMode)
+	}
+	// The empty-ID entry moves items to the top level.
+	if got := ids(m.pickerCandidates()); got != ",ep-1" {
+		t.Errorf("epic candidates = %q", got)
+	}
+	if view := m.pickerView(); !strings.Contains(view, "Moving 2 selected item(s)") {
+		t.Errorf("picker view should show the selection size:\n%s", view)
+	}
+
+	updated, _ = m.handlePickerKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.handleListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = updated.(Model)
+	if m.inputMode != InputBatchLabel {
+		t.Errorf("t should prompt for a batch label, got mode %d", m.inputMode)
+	}
+}
+
+func ids(items []model.Item) string {
+	var out []string
+	for _, item := range items {
+		out = append(out, item.ID)
+	}
+	return strings.Join(out, ",")
+}
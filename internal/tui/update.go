@@ -23,6 +23,9 @@ func (m Model) loadItemsPreserving(preserveID string) tea.Cmd {
 		if err := m.db.PopulateItemLabels(items); err != nil {
 			return itemsMsg{items: items, err: err, preserveID: preserveID}
 		}
+		if err := m.db.PopulateItemProgress(items); err != nil {
+			return itemsMsg{items: items, err: err, preserveID: preserveID}
+		}
 		return itemsMsg{items: items, err: nil, preserveID: preserveID}
 	}
 }
@@ -37,8 +40,7 @@ func tickCmd() tea.Cmd {
 // loadStaleItems loads stale items and returns a command.
 func (m Model) loadStaleItems() tea.Cmd {
 	return func() tea.Msg {
-		cutoff := time.Now().Add(-5 * time.Minute)
-		stale, err := m.db.StaleItems(m.project, cutoff)
+		stale, err := m.db.StaleItemsNow(m.project, time.Now())
 		if err != nil {
 			return staleMsg{err: err}
 		}
@@ -260,6 +262,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case projectsMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.projects = msg.projects
+		m.projectCursor = 0
+		for i, p := range m.projects {
+			if p.Name == m.project {
+				m.projectCursor = i
+				break
+			}
+		}
+		return m, nil
+
 	case configMsg:
 		if msg.err != nil {
 			m.err = msg.err
@@ -5,6 +5,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
 	"strings"
 )
 
@@ -17,6 +18,8 @@ func (m Model) View() string {
 		b.WriteString(m.textareaView())
 	case InputStatusMenu:
 		b.WriteString(m.statusMenuView())
+	case InputAddDep, InputMoveEpic:
+		b.WriteString(m.pickerView())
 	default:
 		switch m.viewMode {
 		case ViewList:
@@ -35,6 +38,8 @@ func (m Model) View() string {
 			b.WriteString(m.createWizardView())
 		case ViewVariablePicker:
 			b.WriteString(m.variablePickerView())
+		case ViewProjects:
+			b.WriteString(m.projectsView())
 		}
 
 		// Input line (for non-textarea input modes)
@@ -59,7 +64,11 @@ func (m Model) View() string {
 		PaddingRight(contentPadding).
 		PaddingTop(1)
 
-	return padStyle.Render(b.String())
+	view := padStyle.Render(b.String())
+	if format.ASCIIEnabled() {
+		view = format.ASCII(view)
+	}
+	return view
 }
 
 // renderBaseView renders the current view without input overlays.
@@ -79,6 +88,8 @@ func (m Model) renderBaseView() string {
 		return m.configView()
 	case ViewCreateWizard:
 		return m.wizardPopupBase()
+	case ViewProjects:
+		return m.projectsView()
 	default:
 		return ""
 	}
@@ -173,9 +184,11 @@ func (m Model) statusMenuView() string {
 	return m.renderPopup("Change Status", menuContent.String(), 50)
 }
 
-// Run starts the TUI with the given project filter.
-func Run(database *db.DB, project string) error {
+// Run starts the TUI with the given project filter. instantiate backs the
+// create wizard's template flow; when nil, that flow reports an error.
+func Run(database *db.DB, project string, instantiate TemplateInstantiator) error {
 	m := New(database, project)
+	m.instantiate = instantiate
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
@@ -5,6 +5,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/format"
 	"github.com/taxilian/tpg/internal/model"
 	"strings"
 )
@@ -120,7 +121,7 @@ func (m Model) handleDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "c":
 		return m.showStatusMenu(3) // Cancel selected
 	case "a":
-		return m.startInput(InputAddDep, "Add blocker ID: ")
+		return m.startDepPicker()
 	case "e":
 		// Open built-in textarea editor for description or selected variable
 		treeNodes := m.buildTree()
@@ -233,7 +234,13 @@ func (m Model) detailViewportContent() (string, model.Item, bool) {
 	}
 	b.WriteString("\n")
 
-	b.WriteString(detailLabelStyle.Render("Priority: ") + fmt.Sprintf("%d", item.Priority) + "\n")
+	if item.Progress != nil {
+		b.WriteString(detailLabelStyle.Render("Progress: ") + format.ProgressBar(*item.Progress) + "\n")
+	}
+
+	priorityStyled := lipgloss.NewStyle().Foreground(priorityColors[item.Priority]).
+		Render(fmt.Sprintf("%d (%s)", item.Priority, model.PriorityName(item.Priority)))
+	b.WriteString(detailLabelStyle.Render("Priority: ") + priorityStyled + "\n")
 
 	if item.ParentID != nil {
 		b.WriteString(detailLabelStyle.Render("Parent:   ") + *item.ParentID + "\n")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/taxilian/tpg/internal/format"
 	"github.com/taxilian/tpg/internal/model"
 	"strings"
 )
@@ -23,9 +24,10 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case " ":
-		// Space: toggle selection of current item (only in select mode)
+		// Space: toggle selection of current item, entering select mode
 		treeNodes := m.buildTree()
-		if m.selectMode && len(treeNodes) > 0 && m.cursor < len(treeNodes) {
+		if len(treeNodes) > 0 && m.cursor < len(treeNodes) {
+			m.selectMode = true
 			id := treeNodes[m.cursor].Item.ID
 			if m.selectedItems[id] {
 				delete(m.selectedItems, id)
@@ -144,7 +146,7 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.showStatusMenu(0) // Start selected
 	case "p":
 		if m.selectMode && len(m.selectedItems) > 0 {
-			return m.startInput(InputBatchPriority, "Batch priority (1-5): ")
+			return m.startInput(InputBatchPriority, "Batch priority (1-5 or high/medium/low): ")
 		}
 		return m.startInput(InputProject, "Project: ")
 	case "d":
@@ -157,7 +159,14 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "L":
 		return m.startInput(InputLog, "Log message: ")
 	case "c":
+		if m.selectMode && len(m.selectedItems) > 0 {
+			return m.startInput(InputBatchCancel, "Cancel reason (optional): ")
+		}
 		return m.showStatusMenu(3) // Cancel selected
+	case "m":
+		if m.selectMode && len(m.selectedItems) > 0 {
+			return m.startEpicPicker()
+		}
 	case "D":
 		return m.doDelete()
 
@@ -165,6 +174,9 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "/":
 		return m.startInput(InputSearch, "Search: ")
 	case "t":
+		if m.selectMode && len(m.selectedItems) > 0 {
+			return m.startInput(InputBatchLabel, "Add label to selected: ")
+		}
 		return m.startInput(InputLabel, "Label: ")
 	case "1":
 		m.filterStatuses[model.StatusOpen] = !m.filterStatuses[model.StatusOpen]
@@ -215,7 +227,7 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Dependencies
 	case "a":
-		return m.startInput(InputAddDep, "Add blocker ID: ")
+		return m.startDepPicker()
 
 	// Create
 	case "n":
@@ -237,6 +249,11 @@ func (m Model) handleListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "C":
 		m.viewMode = ViewConfig
 		return m, m.loadConfig()
+
+	// Projects
+	case "P":
+		m.viewMode = ViewProjects
+		return m, m.loadProjects()
 	}
 
 	m.syncListScroll()
@@ -315,6 +332,9 @@ func (m Model) listView() string {
 
 	// Header
 	title := "tpg"
+	if m.project != "" {
+		title += " · " + m.project
+	}
 	b.WriteString(titleStyle.Render(title))
 	b.WriteString(fmt.Sprintf("  %d/%d items", len(m.filtered), len(m.items)))
 
@@ -416,10 +436,7 @@ func (m Model) formatTreeNodeLinePlain(node treeNode, width int) string {
 		agentWidth = 2
 	}
 
-	itemType := string(item.Type)
-	if len(itemType) > 4 {
-		itemType = itemType[:4]
-	}
+	itemType := typeLabel(item.Type)
 	typeWidth := 5
 
 	statusWidth := 9
@@ -437,6 +454,10 @@ func (m Model) formatTreeNodeLinePlain(node treeNode, width int) string {
 		labels += " [" + lbl + "]"
 		labelsWidth += lipgloss.Width("["+lbl+"]") + 1
 	}
+	if bar := progressBar(item); bar != "" {
+		labels += " " + bar
+		labelsWidth += lipgloss.Width(bar) + 1
+	}
 
 	fixedWidth := 10 + labelsWidth + projectWidth + agentWidth + typeWidth + statusWidth + selectWidth + treePrefixWidth
 	titleWidth := width - fixedWidth
@@ -448,9 +469,40 @@ func (m Model) formatTreeNodeLinePlain(node treeNode, width int) string {
 	title = truncateWidth(title, titleWidth)
 
 	if agent != "" {
-		return fmt.Sprintf("%s%s%-8s %-4s %s %s %-*s%s %s", selectPrefix, treePrefix, status, itemType, item.ID, agent, titleWidth, title, labels, project)
+		return fmt.Sprintf("%s%s%-8s %s %s %s %-*s%s %s", selectPrefix, treePrefix, status, padRight(itemType, 4), item.ID, agent, titleWidth, title, labels, project)
 	}
-	return fmt.Sprintf("%s%s%-8s %-4s %s  %-*s%s %s", selectPrefix, treePrefix, status, itemType, item.ID, titleWidth, title, labels, project)
+	return fmt.Sprintf("%s%s%-8s %s %s  %-*s%s %s", selectPrefix, treePrefix, status, padRight(itemType, 4), item.ID, titleWidth, title, labels, project)
+}
+
+// progressBar returns the progress bar shown after an in-progress item's
+// title, or "" when no percentage has been logged.
+func progressBar(item model.Item) string {
+	if item.Status != model.StatusInProgress || item.Progress == nil {
+		return ""
+	}
+	return format.ProgressBar(*item.Progress)
+}
+
+// typeLabel returns the short type column text: the configured icon for the
+// type if any, otherwise the first four characters of the type name.
+func typeLabel(t model.ItemType) string {
+	if info, ok := model.LookupItemType(t); ok && info.Icon != "" {
+		return info.Icon
+	}
+	label := string(t)
+	if len(label) > 4 {
+		label = label[:4]
+	}
+	return label
+}
+
+// typeStyle returns the style for the type column, using the type's
+// configured color when set.
+func typeStyle(t model.ItemType) lipgloss.Style {
+	if info, ok := model.LookupItemType(t); ok && info.Color != "" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(info.Color))
+	}
+	return dimStyle
 }
 
 // formatTreeNodeLineStyled returns a styled line with colors for non-selected rows.
@@ -491,11 +543,7 @@ func (m Model) formatTreeNodeLineStyled(node treeNode, width int) string {
 
 	id := dimStyle.Render(item.ID)
 
-	itemType := string(item.Type)
-	if len(itemType) > 4 {
-		itemType = itemType[:4]
-	}
-	typeStyled := dimStyle.Render(fmt.Sprintf("%-4s", itemType))
+	typeStyled := typeStyle(item.Type).Render(padRight(typeLabel(item.Type), 4))
 	typeWidth := 5
 
 	statusWidth := 9
@@ -513,6 +561,10 @@ func (m Model) formatTreeNodeLineStyled(node treeNode, width int) string {
 		labels += " " + labelStyle.Render("["+lbl+"]")
 		labelsWidth += lipgloss.Width("["+lbl+"]") + 1
 	}
+	if bar := progressBar(item); bar != "" {
+		labels += " " + lipgloss.NewStyle().Foreground(statusColors[model.StatusInProgress]).Render(bar)
+		labelsWidth += lipgloss.Width(bar) + 1
+	}
 
 	fixedWidth := 10 + labelsWidth + projectWidth + agentWidth + typeWidth + statusWidth + selectWidth + treePrefixWidth
 	titleWidth := width - fixedWidth
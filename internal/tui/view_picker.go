@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/taxilian/tpg/internal/model"
+)
+
+// pickerLimit caps the candidates shown in an item picker.
+const pickerLimit = 10
+
+// The item picker is a filterable popup list of items. It backs two input
+// modes: InputAddDep picks a blocker for the item under the cursor, and
+// InputMoveEpic picks the epic to move the selected items into.
+
+// startDepPicker opens the dependency picker for the item under the cursor.
+// The picker starts scoped to the item's epic when it has one.
+func (m Model) startDepPicker() (Model, tea.Cmd) {
+	target, ok := m.depPickerTarget()
+	if !ok {
+		return m, nil
+	}
+	m.pickerCursor = 0
+	m.depPickerEpicOnly = target.ParentID != nil
+	return m.startInput(InputAddDep, "Blocker: ")
+}
+
+// startEpicPicker opens the picker for moving the selected items to an epic.
+func (m Model) startEpicPicker() (Model, tea.Cmd) {
+	if len(m.selectedItems) == 0 {
+		return m, nil
+	}
+	m.pickerCursor = 0
+	return m.startInput(InputMoveEpic, "Epic: ")
+}
+
+// depPickerTarget returns the item a dependency will be added to.
+func (m Model) depPickerTarget() (model.Item, bool) {
+	treeNodes := m.buildTree()
+	if len(treeNodes) == 0 || m.cursor >= len(treeNodes) {
+		return model.Item{}, false
+	}
+	return treeNodes[m.cursor].Item, true
+}
+
+// pickerCandidates returns the items offered by the active picker, matching
+// the filter text against ID and title.
+func (m Model) pickerCandidates() []model.Item {
+	query := strings.ToLower(strings.TrimSpace(m.promptInput.Value()))
+	matches := func(item model.Item) bool {
+		return query == "" || strings.Contains(strings.ToLower(item.ID), query) ||
+			strings.Contains(strings.ToLower(item.Title), query)
+	}
+
+	var candidates []model.Item
+	switch m.inputMode {
+	case InputAddDep:
+		target, ok := m.depPickerTarget()
+		if !ok {
+			return nil
+		}
+		for _, item := range m.items {
+			if item.ID == target.ID || item.Project != target.Project || !isOpenStatus(item.Status) {
+				continue
+			}
+			if m.depPickerEpicOnly && !sameParent(item, target) {
+				continue
+			}
+			if matches(item) {
+				candidates = append(candidates, item)
+			}
+		}
+
+	case InputMoveEpic:
+		if query == "" {
+			candidates = append(candidates, model.Item{Title: "(no epic, make top-level)"})
+		}
+		project := m.selectionProject()
+		for _, item := range m.items {
+			if m.selectedItems[item.ID] || !item.Type.CanHaveChildren() || !isOpenStatus(item.Status) {
+				continue
+			}
+			if project != "" && item.Project != project {
+				continue
+			}
+			if matches(item) {
+				candidates = append(candidates, item)
+			}
+		}
+	}
+	return candidates
+}
+
+// selectionProject returns the project shared by the selected items, or ""
+// when they span projects.
+func (m Model) selectionProject() string {
+	project := ""
+	for _, item := range m.items {
+		if !m.selectedItems[item.ID] {
+			continue
+		}
+		if project != "" && item.Project != project {
+			return ""
+		}
+		project = item.Project
+	}
+	return project
+}
+
+func isOpenStatus(s model.Status) bool {
+	return s != model.StatusDone && s != model.StatusCanceled
+}
+
+func sameParent(a, b model.Item) bool {
+	if a.ParentID == nil || b.ParentID == nil {
+		return a.ParentID == nil && b.ParentID == nil
+	}
+	return *a.ParentID == *b.ParentID
+}
+
+// selectedIDs returns the IDs of the selected items in a stable order.
+func (m Model) selectedIDs() []string {
+	ids := make([]string, 0, len(m.selectedItems))
+	for id := range m.selectedItems {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// handlePickerKey handles keys while an item picker is open. Typing filters
+// the candidates; arrows move, tab toggles the dependency picker's epic
+// scope, and enter applies the highlighted item.
+func (m Model) handlePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.restorePromptInput()
+		m.blurPromptInputs()
+		m.inputMode = InputNone
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.pickerCursor < min(len(m.pickerCandidates()), pickerLimit)-1 {
+			m.pickerCursor++
+		}
+		return m, nil
+
+	case "tab":
+		if target, ok := m.depPickerTarget(); ok && m.inputMode == InputAddDep && target.ParentID != nil {
+			m.depPickerEpicOnly = !m.depPickerEpicOnly
+			m.pickerCursor = 0
+		}
+		return m, nil
+
+	case "enter":
+		candidates := m.pickerCandidates()
+		if m.pickerCursor >= len(candidates) {
+			m.message = "No matching item"
+			return m, nil
+		}
+		choice := candidates[m.pickerCursor]
+		mode := m.inputMode
+		m.blurPromptInputs()
+		m.inputMode = InputNone
+		if mode == InputMoveEpic {
+			result, cmd := m.doBatchMove(choice.ID)
+			result.selectMode = false
+			result.selectedItems = make(map[string]bool)
+			return result, cmd
+		}
+		target, _ := m.depPickerTarget()
+		return m, func() tea.Msg {
+			if err := m.db.AddDep(target.ID, choice.ID); err != nil {
+				return actionMsg{err: err}
+			}
+			return actionMsg{message: fmt.Sprintf("%s now blocks %s", choice.ID, target.ID)}
+		}
+	}
+
+	prev := m.promptInput.Value()
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	if m.promptInput.Value() != prev {
+		m.syncPromptValue()
+		m.pickerCursor = 0
+	}
+	return m, cmd
+}
+
+// pickerView renders the active item picker popup.
+func (m Model) pickerView() string {
+	var b strings.Builder
+	title := "Add Blocker"
+	var scope string
+
+	switch m.inputMode {
+	case InputAddDep:
+		target, ok := m.depPickerTarget()
+		if !ok {
+			return ""
+		}
+		info := fmt.Sprintf("Blocks %s: %s", target.ID, target.Title)
+		b.WriteString(dimStyle.Render(truncateWidth(info, 60)) + "\n\n")
+		scope = "open items in project " + target.Project
+		if m.depPickerEpicOnly {
+			scope = "open items in epic " + *target.ParentID
+		}
+	case InputMoveEpic:
+		title = "Move to Epic"
+		b.WriteString(dimStyle.Render(fmt.Sprintf("Moving %d selected item(s)", len(m.selectedItems))) + "\n\n")
+		scope = "open epics"
+		if project := m.selectionProject(); project != "" {
+			scope += " in project " + project
+		}
+	}
+
+	input := m.promptInput
+	input.Width = 40
+	b.WriteString(inputStyle.Render(m.inputLabel) + input.View() + "\n\n")
+	b.WriteString(dimStyle.Render("Showing "+scope) + "\n")
+
+	candidates := m.pickerCandidates()
+	if len(candidates) == 0 {
+		b.WriteString("\n  " + dimStyle.Render("No matching items") + "\n")
+	}
+	for i, item := range candidates {
+		if i == pickerLimit {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  … %d more, keep typing to narrow", len(candidates)-pickerLimit)) + "\n")
+			break
+		}
+		line := item.Title
+		if item.ID != "" {
+			line = fmt.Sprintf("%s %s %s", statusIcon(item.Status), item.ID, truncateWidth(item.Title, 44))
+		}
+		if i == m.pickerCursor {
+			b.WriteString(selectedRowStyle.Render("▸ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	b.WriteString("\n" + m.helpViewWidth(62))
+	return m.renderPopup(title, b.String(), 66)
+}
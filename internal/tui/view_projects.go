@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/taxilian/tpg/internal/db"
+	"github.com/taxilian/tpg/internal/model"
+	"strings"
+)
+
+// projectTileWidth is the outer width of a project tile, borders included.
+const projectTileWidth = 28
+
+// projectSummary is one tile in the project picker. An empty Name stands
+// for all projects.
+type projectSummary struct {
+	Name   string
+	Status *db.StatusReport
+}
+
+// projectsMsg carries project summaries for the picker.
+type projectsMsg struct {
+	projects []projectSummary
+	err      error
+}
+
+// loadProjects loads a status summary for every project, preceded by an
+// "all projects" summary.
+func (m Model) loadProjects() tea.Cmd {
+	return func() tea.Msg {
+		names, err := m.db.ListProjects()
+		if err != nil {
+			return projectsMsg{err: err}
+		}
+		summaries := make([]projectSummary, 0, len(names)+1)
+		for _, name := range append([]string{""}, names...) {
+			status, err := m.db.ProjectStatus(name)
+			if err != nil {
+				return projectsMsg{err: err}
+			}
+			summaries = append(summaries, projectSummary{Name: name, Status: status})
+		}
+		return projectsMsg{projects: summaries}
+	}
+}
+
+// projectColumns is how many tiles fit on one row.
+func (m Model) projectColumns() int {
+	cols := (m.width - contentPadding*2) / (projectTileWidth + 1)
+	if cols < 1 {
+		cols = 1
+	}
+	return cols
+}
+
+func (m Model) handleProjectsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cols := m.projectColumns()
+	last := max(0, len(m.projects)-1)
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "left", "h":
+		if m.projectCursor > 0 {
+			m.projectCursor--
+		}
+	case "right", "l":
+		if m.projectCursor < last {
+			m.projectCursor++
+		}
+	case "up", "k":
+		if m.projectCursor-cols >= 0 {
+			m.projectCursor -= cols
+		}
+	case "down", "j":
+		if m.projectCursor+cols <= last {
+			m.projectCursor += cols
+		}
+	case "g", "home":
+		m.projectCursor = 0
+	case "G", "end":
+		m.projectCursor = last
+
+	case "enter":
+		if m.projectCursor < len(m.projects) {
+			m.project = m.projects[m.projectCursor].Name
+			m.filterProject = ""
+			m.cursor = 0
+			m.listScroll = 0
+			m.viewMode = ViewList
+			return m, tea.Batch(m.loadItems(), m.loadStaleItems(), m.loadReadyIDs())
+		}
+
+	case "esc", "backspace":
+		m.viewMode = ViewList
+
+	case "r":
+		return m, m.loadProjects()
+	}
+
+	return m, nil
+}
+
+func (m Model) projectsView() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Projects"))
+	b.WriteString(fmt.Sprintf("  %d projects\n\n", max(0, len(m.projects)-1)))
+
+	if len(m.projects) == 0 {
+		b.WriteString("Loading...\n")
+	} else {
+		cols := m.projectColumns()
+		var rows []string
+		for start := 0; start < len(m.projects); start += cols {
+			end := min(start+cols, len(m.projects))
+			var tiles []string
+			for i := start; i < end; i++ {
+				tiles = append(tiles, m.projectTile(m.projects[i], i == m.projectCursor), " ")
+			}
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, tiles...))
+		}
+		b.WriteString(lipgloss.JoinVertical(lipgloss.Left, rows...))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.helpView())
+	return b.String()
+}
+
+// projectTile renders a project's name and status counts as a bordered box.
+func (m Model) projectTile(p projectSummary, selected bool) string {
+	name := p.Name
+	if name == "" {
+		name = "All projects"
+	}
+	if p.Name == m.project {
+		name += " •"
+	}
+	inner := projectTileWidth - 4
+	title := titleStyle.Render(truncateWidth(name, inner))
+
+	s := p.Status
+	count := func(status model.Status, n int) string {
+		return lipgloss.NewStyle().Foreground(statusColors[status]).Render(fmt.Sprintf("%s %d", statusIcon(status), n))
+	}
+	counts := strings.Join([]string{
+		count(model.StatusOpen, s.Open),
+		count(model.StatusInProgress, s.InProgress),
+		count(model.StatusBlocked, s.Blocked),
+	}, "  ")
+	summary := dimStyle.Render(fmt.Sprintf("ready %d · done %d", s.Ready, s.Done))
+
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("241")).
+		Padding(0, 1).
+		Width(projectTileWidth - 2)
+	if selected {
+		border = border.BorderForeground(lipgloss.Color("205"))
+	}
+	return border.Render(title + "\n" + counts + "\n" + summary)
+}
@@ -14,22 +14,29 @@ import (
 )
 
 func (m Model) handleCreateWizardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if m.isWizardDescriptionStep() {
+	if m.createWizardState.FromTemplate && m.createWizardStep > 1 {
+		return m.handleTemplateWizardKey(msg)
+	}
+	if field := m.wizardTextareaField(); field != nil {
 		switch msg.String() {
 		case "esc":
+			*field = m.textarea.Value()
 			m.textarea.Blur()
 			if m.createWizardStep > 1 {
 				m.createWizardStep--
 			}
+			if m.isWizardTextareaStep() {
+				return m.startWizardTextarea()
+			}
 			return m, nil
 		case "ctrl+s", "ctrl+enter":
-			m.createWizardState.Description = m.textarea.Value()
+			*field = m.textarea.Value()
 			return m.advanceWizardStep()
 		}
 
 		var cmd tea.Cmd
 		m.textarea, cmd = m.textarea.Update(msg)
-		m.createWizardState.Description = m.textarea.Value()
+		*m.wizardTextareaField() = m.textarea.Value()
 		return m, cmd
 	}
 
@@ -108,6 +115,9 @@ func (m Model) getAvailableTypes() []TypeOption {
 		case model.ItemTypeEpic:
 			return db.DefaultEpicPrefix
 		default:
+			if info, ok := model.LookupItemType(itemType); ok && info.Prefix != "" {
+				return info.Prefix
+			}
 			return "it"
 		}
 	}
@@ -128,6 +138,13 @@ func (m Model) getAvailableTypes() []TypeOption {
 	addType(model.ItemTypeTask, "Standard task (default)")
 	addType(model.ItemTypeEpic, "Large body of work with child tasks")
 
+	// Custom types from the config registry
+	for _, info := range model.ItemTypes() {
+		if !info.Builtin {
+			addType(info.Name, "Custom type")
+		}
+	}
+
 	// Database types (for backward compatibility with existing items of old types)
 	if m.db != nil {
 		if dbTypes, err := m.db.GetDistinctTypes(); err == nil {
@@ -157,6 +174,7 @@ func (m Model) getAvailableTypes() []TypeOption {
 		return string(options[i].Type) < string(options[j].Type)
 	})
 
+	options = append(options, TypeOption{Template: true, Desc: "Instantiate a template, prompting for its variables"})
 	return options
 }
 
@@ -213,20 +231,27 @@ func (m Model) advanceWizardStep() (tea.Model, tea.Cmd) {
 		if state.TypeCursor < 0 || state.TypeCursor >= len(types) {
 			state.TypeCursor = 0
 		}
+		if types[state.TypeCursor].Template {
+			state.FromTemplate = true
+			state.TemplateCursor = 0
+			m.createWizardStep = 2
+			return m, m.loadTemplates()
+		}
+		state.FromTemplate = false
 		state.SelectedType = types[state.TypeCursor].Type
 		m.createWizardStep = 2
 		return m, m.focusWizardTitleInput()
 
 	case 2: // Title
 		state.Title = m.wizardTitleInput.Value()
-		if strings.TrimSpace(state.Title) == "" {
-			m.err = fmt.Errorf("title is required")
+		if err := checkWizardTitle(state.Title); err != nil {
+			m.err = err
 			return m, nil
 		}
 		m.wizardTitleInput.Blur()
 		m.createWizardStep = 3
 		if state.SelectedType != model.ItemTypeEpic {
-			return m.startWizardDescription()
+			return m.startWizardTextarea()
 		}
 
 	case 3: // Worktree (epics only) or Description (non-epic)
@@ -234,7 +259,7 @@ func (m Model) advanceWizardStep() (tea.Model, tea.Cmd) {
 			m.syncWizardWorktreeState()
 			m.blurWizardWorktreeInputs()
 			m.createWizardStep = 4
-			return m.startWizardDescription()
+			return m.startWizardTextarea()
 		}
 		if !validateDescription(state.Description) {
 			m.err = fmt.Errorf("description must be at least 3 words or 20 characters")
@@ -248,6 +273,14 @@ func (m Model) advanceWizardStep() (tea.Model, tea.Cmd) {
 			m.err = fmt.Errorf("description must be at least 3 words or 20 characters")
 			return m, nil
 		}
+		m.createWizardStep = 5
+		return m.startWizardTextarea()
+
+	case 5: // Shared context (epic, optional)
+		m.createWizardStep = 6
+		return m.startWizardTextarea()
+
+	case 6: // Closing instructions (epic, optional)
 		m.textarea.Blur()
 		return m.createItemFromWizard()
 	}
@@ -275,6 +308,22 @@ func validateDescription(desc string) bool {
 	return false
 }
 
+// checkWizardTitle returns an error when title is empty or breaks the title
+// policy.
+func checkWizardTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	maxLen := db.DefaultMaxTitleLength
+	if config, err := db.LoadConfig(); err == nil {
+		maxLen = config.PolicyMaxTitleLength()
+	}
+	if problem := db.TitleProblem(title, maxLen); problem != "" {
+		return fmt.Errorf("the title %s; shorten it and put the details in the description", problem)
+	}
+	return nil
+}
+
 // generateWorktreeBranch generates a branch name from epic ID and title.
 // Format: feature/<epic-id>-<slug> where slug is lowercase title with non-alnum->hyphens
 func generateWorktreeBranch(epicID, title string) string {
@@ -356,9 +405,13 @@ func (m Model) createItemFromWizard() (tea.Model, tea.Cmd) {
 		UpdatedAt:   now,
 	}
 
-	if selectedType == model.ItemTypeEpic && state.UseWorktree {
-		item.WorktreeBranch = state.WorktreeBranch
-		item.WorktreeBase = state.WorktreeBase
+	if selectedType == model.ItemTypeEpic {
+		item.SharedContext = strings.TrimSpace(state.Context)
+		item.ClosingInstructions = strings.TrimSpace(state.OnClose)
+		if state.UseWorktree {
+			item.WorktreeBranch = state.WorktreeBranch
+			item.WorktreeBase = state.WorktreeBase
+		}
 	}
 
 	if err := m.db.CreateItem(&item); err != nil {
@@ -376,6 +429,9 @@ func (m Model) createItemFromWizard() (tea.Model, tea.Cmd) {
 }
 
 func (m Model) createWizardView() string {
+	if m.createWizardState.FromTemplate && m.createWizardStep > 1 {
+		return m.templateWizardView()
+	}
 	switch m.createWizardStep {
 	case 1:
 		return m.wizardTypeView()
@@ -385,19 +441,35 @@ func (m Model) createWizardView() string {
 		if m.createWizardState.SelectedType == model.ItemTypeEpic {
 			return m.wizardWorktreeView()
 		}
-		return m.wizardDescriptionView()
-	case 4:
-		return m.wizardDescriptionView()
+		return m.wizardTextareaView()
+	case 4, 5, 6:
+		return m.wizardTextareaView()
 	default:
 		return ""
 	}
 }
 
-func (m Model) isWizardDescriptionStep() bool {
-	if m.createWizardStep == 3 && m.createWizardState.SelectedType != model.ItemTypeEpic {
-		return true
-	}
-	return m.createWizardStep == 4
+// wizardTextareaField returns the state field edited with the textarea at
+// the current step, or nil when the step doesn't use the textarea.
+func (m *Model) wizardTextareaField() *string {
+	state := &m.createWizardState
+	if state.FromTemplate {
+		return nil
+	}
+	epic := state.SelectedType == model.ItemTypeEpic
+	switch {
+	case m.createWizardStep == 3 && !epic, m.createWizardStep == 4 && epic:
+		return &state.Description
+	case m.createWizardStep == 5 && epic:
+		return &state.Context
+	case m.createWizardStep == 6 && epic:
+		return &state.OnClose
+	}
+	return nil
+}
+
+func (m Model) isWizardTextareaStep() bool {
+	return m.wizardTextareaField() != nil
 }
 
 func (m Model) wizardPopupWidth() int {
@@ -413,6 +485,12 @@ func (m Model) wizardPopupWidth() int {
 }
 
 func (m Model) wizardPopupTitle() string {
+	if m.createWizardState.FromTemplate {
+		if m.createWizardState.Template != nil && m.createWizardStep > 2 {
+			return "Create from " + m.createWizardState.Template.ID
+		}
+		return "Create from Template"
+	}
 	selectedType := m.createWizardState.SelectedType
 	if selectedType == "" {
 		selectedType = model.ItemTypeTask
@@ -424,8 +502,10 @@ func (m Model) wizardPopupBase() string {
 	return m.listView()
 }
 
-func (m Model) startWizardDescription() (Model, tea.Cmd) {
-	m.textarea.SetValue(m.createWizardState.Description)
+func (m Model) startWizardTextarea() (Model, tea.Cmd) {
+	if field := m.wizardTextareaField(); field != nil {
+		m.textarea.SetValue(*field)
+	}
 	m.textarea.Focus()
 	popupWidth := m.wizardPopupWidth()
 	width := max(20, popupWidth-6)
@@ -514,6 +594,9 @@ func (m Model) wizardTypeView() string {
 			icon = "●"
 		}
 		label := fmt.Sprintf("%s %s (%s-)", icon, t.Type, t.Prefix)
+		if t.Template {
+			label = fmt.Sprintf("%s from template", icon)
+		}
 		if t.Desc != "" {
 			label = fmt.Sprintf("%s - %s", label, t.Desc)
 		}
@@ -584,10 +667,17 @@ func (m Model) wizardTitleView() string {
 	return m.renderPopupOver(m.wizardPopupBase(), m.wizardPopupTitle(), b.String(), m.wizardPopupWidth())
 }
 
-func (m Model) wizardDescriptionView() string {
+func (m Model) wizardTextareaView() string {
 	var b strings.Builder
 
-	b.WriteString("Description (required):\n")
+	switch m.createWizardStep {
+	case 5:
+		b.WriteString("Shared context (optional, shown with every task in the epic):\n")
+	case 6:
+		b.WriteString("On close (optional, instructions for closing the epic):\n")
+	default:
+		b.WriteString("Description (required):\n")
+	}
 	b.WriteString(m.textarea.View())
 	b.WriteString("\n\n")
 	b.WriteString(m.helpViewWidth(m.wizardPopupWidth() - 6))
@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/taxilian/tpg/internal/model"
+	"github.com/taxilian/tpg/internal/templates"
+)
+
+// wizardTemplateLimit caps the templates listed at once in the picker.
+const wizardTemplateLimit = 10
+
+// handleTemplateWizardKey handles the create wizard's template flow: pick a
+// template (step 2), enter a title (step 3), then answer one prompt per
+// template variable (step 4).
+func (m Model) handleTemplateWizardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	state := &m.createWizardState
+
+	switch m.createWizardStep {
+	case 2:
+		switch msg.String() {
+		case "esc":
+			state.FromTemplate = false
+			m.createWizardStep = 1
+		case "up", "k":
+			if state.TemplateCursor > 0 {
+				state.TemplateCursor--
+			}
+		case "down", "j":
+			if state.TemplateCursor < len(m.templates)-1 {
+				state.TemplateCursor++
+			}
+		case "enter":
+			if state.TemplateCursor >= len(m.templates) {
+				return m, nil
+			}
+			if state.Template == nil || state.Template.ID != m.templates[state.TemplateCursor].ID {
+				state.Template = m.templates[state.TemplateCursor]
+				state.VarNames = sortedTemplateVars(state.Template)
+				state.Vars = make(map[string]string)
+			}
+			m.createWizardStep = 3
+			return m, m.focusWizardTitleInput()
+		}
+		return m, nil
+
+	case 3:
+		switch msg.String() {
+		case "esc":
+			m.wizardTitleInput.Blur()
+			m.createWizardStep = 2
+			return m, nil
+		case "enter":
+			state.Title = m.wizardTitleInput.Value()
+			if err := checkWizardTitle(state.Title); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.wizardTitleInput.Blur()
+			if len(state.VarNames) == 0 {
+				return m.createItemFromTemplate()
+			}
+			state.VarIndex = 0
+			m.createWizardStep = 4
+			return m, m.focusWizardVarInput()
+		}
+		return m.updateWizardTextInput(msg, &m.wizardTitleInput, func(value string) {
+			m.createWizardState.Title = value
+		})
+
+	case 4:
+		name := state.VarNames[state.VarIndex]
+		switch msg.String() {
+		case "esc":
+			state.Vars[name] = m.wizardVarInput.Value()
+			if state.VarIndex == 0 {
+				m.wizardVarInput.Blur()
+				m.createWizardStep = 3
+				return m, m.focusWizardTitleInput()
+			}
+			state.VarIndex--
+			return m, m.focusWizardVarInput()
+		case "enter":
+			value := m.wizardVarInput.Value()
+			if v := state.Template.Variables[name]; strings.TrimSpace(value) == "" && !v.Optional && v.Default == "" {
+				m.err = fmt.Errorf("%s is required", name)
+				return m, nil
+			}
+			state.Vars[name] = value
+			if state.VarIndex < len(state.VarNames)-1 {
+				state.VarIndex++
+				return m, m.focusWizardVarInput()
+			}
+			m.wizardVarInput.Blur()
+			return m.createItemFromTemplate()
+		}
+		return m.updateWizardTextInput(msg, &m.wizardVarInput, func(string) {})
+	}
+
+	return m, nil
+}
+
+// sortedTemplateVars returns a template's variable names, required ones
+// first, each group in alphabetical order.
+func sortedTemplateVars(tmpl *templates.Template) []string {
+	names := make([]string, 0, len(tmpl.Variables))
+	for name := range tmpl.Variables {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		oi, oj := tmpl.Variables[names[i]].Optional, tmpl.Variables[names[j]].Optional
+		if oi != oj {
+			return !oi
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// focusWizardVarInput loads the current variable's value, or its default,
+// into the variable input and focuses it.
+func (m *Model) focusWizardVarInput() tea.Cmd {
+	state := &m.createWizardState
+	name := state.VarNames[state.VarIndex]
+	value, ok := state.Vars[name]
+	if !ok {
+		value = state.Template.Variables[name].Default
+	}
+	m.wizardVarInput.Width = max(20, m.wizardPopupWidth()-8)
+	m.wizardVarInput.SetValue(value)
+	m.wizardVarInput.CursorEnd()
+	return m.wizardVarInput.Focus()
+}
+
+// createItemFromTemplate instantiates the chosen template. Empty answers are
+// left out so the template's defaults apply.
+func (m Model) createItemFromTemplate() (tea.Model, tea.Cmd) {
+	state := m.createWizardState
+	if m.instantiate == nil {
+		m.err = fmt.Errorf("creating items from templates is not available")
+		return m, nil
+	}
+
+	vars := make(map[string]string)
+	for name, value := range state.Vars {
+		if strings.TrimSpace(value) != "" {
+			vars[name] = value
+		}
+	}
+	id, err := m.instantiate(m.project, strings.TrimSpace(state.Title), state.Template.ID, vars)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.viewMode = ViewList
+	m.createWizardStep = 0
+	m.createWizardState = CreateWizardState{SelectedType: model.ItemTypeTask}
+	m.message = fmt.Sprintf("Created %s from template %s", id, state.Template.ID)
+	return m, m.loadItems()
+}
+
+func (m Model) templateWizardView() string {
+	switch m.createWizardStep {
+	case 2:
+		return m.wizardTemplatePickView()
+	case 3:
+		return m.wizardTitleView()
+	case 4:
+		return m.wizardVarView()
+	default:
+		return ""
+	}
+}
+
+func (m Model) wizardTemplatePickView() string {
+	var b strings.Builder
+	width := m.wizardPopupWidth()
+
+	if len(m.templates) == 0 {
+		b.WriteString(dimStyle.Render("No templates found. Add them to .tpg/templates/ in the project.") + "\n")
+	} else {
+		b.WriteString("Select a template:\n\n")
+	}
+
+	cursor := m.createWizardState.TemplateCursor
+	start := max(0, cursor-wizardTemplateLimit+1)
+	for i := start; i < len(m.templates) && i < start+wizardTemplateLimit; i++ {
+		tmpl := m.templates[i]
+		icon := "○"
+		if i == cursor {
+			icon = "●"
+		}
+		label := truncateWidth(fmt.Sprintf("%s %s  %s", icon, tmpl.ID, tmpl.Title), width-6)
+		if i == cursor {
+			b.WriteString(selectedRowStyle.Render(label))
+		} else {
+			b.WriteString(label)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.helpViewWidth(width - 6))
+
+	return m.renderPopupOver(m.wizardPopupBase(), m.wizardPopupTitle(), b.String(), width)
+}
+
+func (m Model) wizardVarView() string {
+	var b strings.Builder
+	state := m.createWizardState
+	name := state.VarNames[state.VarIndex]
+	def := state.Template.Variables[name]
+	width := m.wizardPopupWidth()
+
+	b.WriteString(fmt.Sprintf("Variable %d of %d: %s", state.VarIndex+1, len(state.VarNames), name))
+	if def.Optional {
+		b.WriteString(dimStyle.Render(" (optional)"))
+	}
+	b.WriteString("\n")
+	if def.Description != "" {
+		desc := strings.Join(strings.Fields(def.Description), " ")
+		b.WriteString(dimStyle.Render(truncateWidth(desc, width-6)) + "\n")
+	}
+
+	inputBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		Width(max(20, width-6)).
+		Render(m.wizardVarInput.View())
+	b.WriteString(inputBox)
+	b.WriteString("\n\n")
+	b.WriteString(m.helpViewWidth(width - 6))
+
+	return m.renderPopupOver(m.wizardPopupBase(), m.wizardPopupTitle(), b.String(), width)
+}
@@ -0,0 +1,33 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// setupMarker is created in a worktree's git directory once its setup
+// commands have succeeded. Keeping it out of the working tree leaves
+// 'git status' clean.
+const setupMarker = "tpg-setup-done"
+
+// Add creates a worktree at path checked out on branch, creating the branch
+// from base when it doesn't exist yet.
+func Add(repoRoot, path, branch, base string) error {
+	if refExists(repoRoot, "refs/heads/"+branch) {
+		return gitCombined(repoRoot, "worktree", "add", path, branch)
+	}
+	return gitCombined(repoRoot, "worktree", "add", "-b", branch, path, base)
+}
+
+// SetupDone reports whether setup has been recorded for the worktree whose
+// git directory is gitDir.
+func SetupDone(gitDir string) bool {
+	_, err := os.Stat(filepath.Join(gitDir, setupMarker))
+	return err == nil
+}
+
+// MarkSetupDone records that setup succeeded for the worktree whose git
+// directory is gitDir.
+func MarkSetupDone(gitDir string) error {
+	return os.WriteFile(filepath.Join(gitDir, setupMarker), nil, 0644)
+}
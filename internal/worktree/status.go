@@ -0,0 +1,117 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// BranchStatus describes the git state behind an epic's worktree metadata.
+type BranchStatus struct {
+	Branch       string
+	Base         string
+	BranchExists bool   // refs/heads/<Branch> exists
+	BaseExists   bool   // <Base> resolves to a commit
+	Path         string // worktree checked out on Branch; empty if none
+	PathExists   bool   // Path is present on disk
+	Dirty        bool   // the worktree has uncommitted changes
+	Ahead        int    // commits on Branch not on Base
+	Behind       int    // commits on Base not on Branch
+}
+
+// Inspect gathers the state of branch relative to base in the repository at
+// repoRoot. worktrees maps branch names to worktree paths, as returned by
+// ListWorktrees. Git failures leave the affected fields at their zero value.
+func Inspect(repoRoot, branch, base string, worktrees map[string]string) BranchStatus {
+	st := BranchStatus{Branch: branch, Base: base}
+	st.BranchExists = refExists(repoRoot, "refs/heads/"+branch)
+	st.BaseExists = base != "" && refExists(repoRoot, base)
+
+	if path, ok := worktrees[branch]; ok {
+		st.Path = path
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			st.PathExists = true
+			st.Dirty = verifyCleanWorktree(path) != nil
+		}
+	}
+
+	if st.BranchExists && st.BaseExists {
+		st.Ahead, st.Behind, _ = AheadBehind(repoRoot, branch, base)
+	}
+	return st
+}
+
+// AheadBehind counts the commits on branch that are not on base (ahead) and
+// on base that are not on branch (behind).
+func AheadBehind(repoRoot, branch, base string) (ahead, behind int, err error) {
+	out, err := git(repoRoot, "rev-list", "--left-right", "--count", branch+"..."+base)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// refExists reports whether ref resolves to a commit.
+func refExists(repoRoot, ref string) bool {
+	_, err := git(repoRoot, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// git runs a git command in dir and returns its trimmed stdout.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RemoveWorktree runs 'git worktree remove' for path. With force, a
+// worktree with uncommitted changes is removed too.
+func RemoveWorktree(repoRoot, path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = append(args, "--force")
+	}
+	return gitCombined(repoRoot, args...)
+}
+
+// DeleteBranch deletes a local branch. Without force, git refuses to delete
+// a branch that is not merged into its upstream or HEAD.
+func DeleteBranch(repoRoot, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	return gitCombined(repoRoot, "branch", flag, branch)
+}
+
+// gitCombined runs a git command in dir, including its output in the error.
+func gitCombined(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w\n%s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Prune runs 'git worktree prune', dropping records of worktrees whose
+// directories no longer exist.
+func Prune(repoRoot string) error {
+	return gitCombined(repoRoot, "worktree", "prune")
+}
@@ -42,7 +42,7 @@ func DetectContext(startDir string) (*Context, error) {
 				}, nil
 			}
 
-			gitDir, repoRoot, inWorktree, err := parseGitFile(gitPath)
+			gitDir, repoRoot, inWorktree, err := ParseGitFile(gitPath)
 			if err != nil {
 				return nil, err
 			}
@@ -116,10 +116,11 @@ func IsWithinDir(path, dir string) bool {
 		return false
 	}
 	rel, err := filepath.Rel(dir, path)
-	if err != nil {
+	if err != nil || filepath.IsAbs(rel) {
 		return false
 	}
-	return rel == "." || (!strings.HasPrefix(rel, "..") && rel != "")
+	// A ".." prefix alone is not enough: "..cache" is a child of dir.
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 func readHeadBranch(headPath string) (string, error) {
@@ -140,7 +141,11 @@ func readHeadBranch(headPath string) (string, error) {
 	return ref, nil
 }
 
-func parseGitFile(gitFilePath string) (gitDir string, repoRoot string, inWorktree bool, err error) {
+// ParseGitFile reads the "gitdir:" pointer in a worktree's or submodule's
+// .git file. It returns the git directory it points at (resolved against the
+// file's directory when relative), the main repository root, and whether the
+// file belongs to a linked worktree.
+func ParseGitFile(gitFilePath string) (gitDir string, repoRoot string, inWorktree bool, err error) {
 	file, err := os.Open(gitFilePath)
 	if err != nil {
 		return "", "", false, fmt.Errorf("failed to open .git file: %w", err)
@@ -169,15 +174,19 @@ func parseGitFile(gitFilePath string) (gitDir string, repoRoot string, inWorktre
 	}
 	gitDir = filepath.Clean(gitDir)
 
-	worktreesMarker := string(filepath.Separator) + "worktrees" + string(filepath.Separator)
-	modulesMarker := string(filepath.Separator) + "modules" + string(filepath.Separator)
+	// Linked worktrees point at <main git dir>/worktrees/<name>; submodules at
+	// <repo>/.git/modules/<path>. Only those exact layouts count, so a repo
+	// that merely lives under a directory named "worktrees" isn't misread.
+	sep := string(filepath.Separator)
+	modulesMarker := sep + ".git" + sep + "modules" + sep
 
 	mainGitDir := gitDir
-	if idx := strings.Index(gitDir, worktreesMarker); idx >= 0 {
+	for filepath.Base(filepath.Dir(mainGitDir)) == "worktrees" {
 		inWorktree = true
-		mainGitDir = gitDir[:idx]
-	} else if idx := strings.Index(gitDir, modulesMarker); idx >= 0 {
-		mainGitDir = gitDir[:idx]
+		mainGitDir = filepath.Dir(filepath.Dir(mainGitDir))
+	}
+	if idx := strings.Index(mainGitDir, modulesMarker); idx >= 0 {
+		mainGitDir = mainGitDir[:idx+len(sep+".git")]
 	}
 
 	repoRoot = filepath.Dir(mainGitDir)
@@ -0,0 +1,65 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsWithinDir(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "repo")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{root, true},
+		{filepath.Join(root, "src", "main.go"), true},
+		{filepath.Join(root, "..cache"), true},
+		{filepath.Join(root, ".."), false},
+		{filepath.Join(root, "..", "other"), false},
+		{filepath.Dir(root), false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsWithinDir(tt.path, root); got != tt.want {
+			t.Errorf("IsWithinDir(%q, %q) = %v, want %v", tt.path, root, got, tt.want)
+		}
+	}
+}
+
+func TestParseGitFile(t *testing.T) {
+	// The main repo lives under a directory named "worktrees", which must not
+	// be mistaken for git's own .git/worktrees directory.
+	repo := filepath.Join(t.TempDir(), "worktrees", "repo")
+	wt := filepath.Join(repo, ".worktrees", "ep-abc")
+	gitDir := filepath.Join(repo, ".git", "worktrees", "ep-abc")
+	for _, dir := range []string{wt, gitDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pointer string
+	}{
+		{"absolute", gitDir},
+		{"absolute with forward slashes", filepath.ToSlash(gitDir)},
+		{"relative", filepath.Join("..", "..", ".git", "worktrees", "ep-abc")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitFile := filepath.Join(wt, ".git")
+			if err := os.WriteFile(gitFile, []byte("gitdir: "+tt.pointer+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			gotDir, gotRoot, inWorktree, err := ParseGitFile(gitFile)
+			if err != nil {
+				t.Fatalf("ParseGitFile: %v", err)
+			}
+			if gotDir != gitDir || gotRoot != repo || !inWorktree {
+				t.Errorf("ParseGitFile = %q, %q, %v; want %q, %q, true", gotDir, gotRoot, inWorktree, gitDir, repo)
+			}
+		})
+	}
+}